@@ -17,23 +17,1403 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	// RestartedAtAnnotation triggers a rolling restart of the MCP server pods whenever its value changes.
+	// The value is copied onto the pod template, usually as an RFC 3339 timestamp.
+	RestartedAtAnnotation = "mcpserver.opendatahub.io/restartedAt"
+
+	// AdoptAnnotation set to "true" on an existing Deployment, Service or Route without a controller lets an
+	// MCPServer of the same name take it over instead of leaving it alone.
+	AdoptAnnotation = "mcpserver.opendatahub.io/adopt"
+
+	// RefreshToolsAnnotation makes the operator list the tools of the MCP server again whenever its value
+	// changes, usually set to an RFC 3339 timestamp.
+	RefreshToolsAnnotation = "mcpserver.opendatahub.io/refresh-tools"
+
+	// SuspendedReplicasAnnotation is set by "kubectl mcp suspend" when it scales an MCP server to zero pods, and
+	// holds the number of pods "kubectl mcp resume" scales it back to.
+	SuspendedReplicasAnnotation = "mcpserver.opendatahub.io/suspended-replicas"
+
+	// DisplayNameAnnotation holds the human readable name of an MCPServer without spec.displayName.
+	DisplayNameAnnotation = "openshift.io/display-name"
+
+	// DescriptionAnnotation holds the human readable description of an MCPServer without spec.description.
+	DescriptionAnnotation = "openshift.io/description"
+
+	// TemplateLabel set to "true" marks an MCPServer as a template that MCPServerClaims create per-user instances
+	// from. A template is not deployed itself.
+	TemplateLabel = "mcpserver.opendatahub.io/template"
+
+	// ShardLabel is set by a sharded operator to the shard of the replica that reconciles the MCPServer.
+	ShardLabel = "mcpserver.opendatahub.io/shard"
+)
+
+// MCPServerType is how an MCP server is run.
+// +kubebuilder:validation:Enum=Managed;External;Proxy
+type MCPServerType string
+
+const (
+	// MCPServerManaged servers are deployed by the operator.
+	MCPServerManaged MCPServerType = "Managed"
+	// MCPServerExternal servers are hosted outside of the cluster, or by someone else, and only checked and
+	// published by the operator.
+	MCPServerExternal MCPServerType = "External"
+	// MCPServerProxy servers are hosted outside of the cluster and reached through a proxy deployed by the
+	// operator, which injects the upstream credentials and only admits callers allowed to get the MCPServer.
+	MCPServerProxy MCPServerType = "Proxy"
+)
+
 // MCPServerSpec defines the desired state of MCPServer.
+// +kubebuilder:validation:XValidation:rule="has(self.type) && self.type != 'Managed' ? has(self.url) : has(self.image)",message="image is required for Managed MCPServers and url for External and Proxy MCPServers"
+// +kubebuilder:validation:XValidation:rule="!has(self.guardrails) || !has(self.type) || self.type != 'External'",message="guardrails cannot be set for External MCPServers"
+// +kubebuilder:validation:XValidation:rule="!has(self.capabilities) || !has(self.type) || self.type != 'External'",message="capabilities cannot be set for External MCPServers"
+// +kubebuilder:validation:XValidation:rule="!has(self.restBridge) || !has(self.type) || self.type != 'External'",message="restBridge cannot be set for External MCPServers"
+// +kubebuilder:validation:XValidation:rule="!has(self.gatewayRef) || !has(self.type) || self.type != 'External'",message="gatewayRef cannot be set for External MCPServers"
+// +kubebuilder:validation:XValidation:rule="!has(self.conformanceCheck) || !has(self.type) || self.type != 'External'",message="conformanceCheck cannot be set for External MCPServers"
+// +kubebuilder:validation:XValidation:rule="!has(self.metricsExporter) || !has(self.type) || self.type != 'External'",message="metricsExporter cannot be set for External MCPServers"
+// +kubebuilder:validation:XValidation:rule="!has(self.lifecycle) || !has(self.type) || self.type != 'External'",message="lifecycle cannot be set for External MCPServers"
+// +kubebuilder:validation:XValidation:rule="!has(self.probes) || !has(self.type) || self.type == 'Managed'",message="probes can only be set for Managed MCPServers"
+// +kubebuilder:validation:XValidation:rule="!(has(self.nodeSelector) || has(self.tolerations)) || !has(self.type) || self.type != 'External'",message="nodeSelector and tolerations cannot be set for External MCPServers"
+// +kubebuilder:validation:XValidation:rule="!has(self.rateLimit) || !has(self.type) || self.type != 'External'",message="rateLimit cannot be set for External MCPServers"
+// +kubebuilder:validation:XValidation:rule="!has(self.expose) || !has(self.type) || self.type != 'External'",message="expose cannot be set for External MCPServers"
+// +kubebuilder:validation:XValidation:rule="!has(self.exposures) || !has(self.type) || self.type != 'External'",message="exposures cannot be set for External MCPServers"
+// +kubebuilder:validation:XValidation:rule="!has(self.verifyImage) || !self.verifyImage || !has(self.type) || self.type == 'Managed'",message="verifyImage can only be set for Managed MCPServers"
+// +kubebuilder:validation:XValidation:rule="!has(self.imagePullPolicy) || !has(self.type) || self.type == 'Managed'",message="imagePullPolicy can only be set for Managed MCPServers"
+// +kubebuilder:validation:XValidation:rule="!has(self.pinImageDigest) || !self.pinImageDigest || !has(self.type) || self.type == 'Managed'",message="pinImageDigest can only be set for Managed MCPServers"
+// +kubebuilder:validation:XValidation:rule="!has(self.route) || !has(self.type) || self.type != 'External'",message="route cannot be set for External MCPServers"
+// +kubebuilder:validation:XValidation:rule="!has(self.route) || !(has(self.gatewayRef) || has(self.meshGateway))",message="route cannot be set with gatewayRef or meshGateway, which replace the Route"
+// +kubebuilder:validation:XValidation:rule="!has(self.expose) || !has(self.expose.allowedSourceRanges) || !has(self.gatewayRef)",message="expose.allowedSourceRanges cannot be set with gatewayRef, restrict the sources on the Gateway instead"
+// +kubebuilder:validation:XValidation:rule="!has(self.allowedClientNamespaces) || !has(self.type) || self.type != 'External'",message="allowedClientNamespaces cannot be set for External MCPServers"
+// +kubebuilder:validation:XValidation:rule="!has(self.observability) || !has(self.type) || self.type != 'External'",message="observability cannot be set for External MCPServers"
+// +kubebuilder:validation:XValidation:rule="!has(self.meshGateway) || !has(self.type) || self.type != 'External'",message="meshGateway cannot be set for External MCPServers"
+// +kubebuilder:validation:XValidation:rule="!has(self.meshGateway) || !has(self.gatewayRef)",message="meshGateway and gatewayRef are mutually exclusive"
+// +kubebuilder:validation:XValidation:rule="!has(self.meshGateway) || !has(self.expose) || !has(self.expose.allowedSourceRanges)",message="expose.allowedSourceRanges cannot be set with meshGateway, restrict the sources on the mesh gateway instead"
+// +kubebuilder:validation:XValidation:rule="!has(self.config) || !has(self.type) || self.type == 'Managed'",message="config can only be set for Managed MCPServers"
+// +kubebuilder:validation:XValidation:rule="!(has(self.env) || has(self.envFrom)) || !has(self.type) || self.type == 'Managed'",message="env and envFrom can only be set for Managed MCPServers"
+// +kubebuilder:validation:XValidation:rule="!(has(self.volumes) || has(self.volumeMounts)) || !has(self.type) || self.type == 'Managed'",message="volumes and volumeMounts can only be set for Managed MCPServers"
+// +kubebuilder:validation:XValidation:rule="!has(self.config) || !has(self.command)",message="config cannot be set with command, custom MCP servers are configured with args"
+// +kubebuilder:validation:XValidation:rule="!has(self.kubernetesAccess) || !has(self.type) || self.type == 'Managed'",message="kubernetesAccess can only be set for Managed MCPServers"
+// +kubebuilder:validation:XValidation:rule="!has(self.kubernetesAccess) || !has(self.kubernetesAccess.mode) || self.kubernetesAccess.mode != 'TokenPassthrough' || !has(self.command)",message="kubernetesAccess.mode TokenPassthrough is only supported for the Kubernetes MCP server run by the default command"
+// +kubebuilder:validation:XValidation:rule="!has(self.kubernetesAccess) || !has(self.kubernetesAccess.mode) || self.kubernetesAccess.mode != 'TokenPassthrough' || !has(self.auth)",message="kubernetesAccess.mode TokenPassthrough cannot be combined with auth, the callers authenticate with their Kubernetes tokens"
+// +kubebuilder:validation:XValidation:rule="!has(self.auth) || !has(self.type) || self.type == 'Managed'",message="auth can only be set for Managed MCPServers, Proxy MCPServers authenticate with Kubernetes tokens"
+// +kubebuilder:validation:XValidation:rule="!has(self.autoscaling) || !has(self.type) || self.type != 'External'",message="autoscaling cannot be set for External MCPServers"
+// +kubebuilder:validation:XValidation:rule="!has(self.serviceAccountName) || !has(self.type) || self.type != 'External'",message="serviceAccountName cannot be set for External MCPServers"
+// +kubebuilder:validation:XValidation:rule="!has(self.securityContext) || !has(self.type) || self.type != 'External'",message="securityContext cannot be set for External MCPServers"
+// +kubebuilder:validation:XValidation:rule="!(has(self.podSecurityContext) || has(self.containerSecurityContext)) || !has(self.type) || self.type != 'External'",message="podSecurityContext and containerSecurityContext cannot be set for External MCPServers"
+// +kubebuilder:validation:XValidation:rule="!(has(self.labels) || has(self.annotations) || has(self.podTemplateMetadata)) || !has(self.type) || self.type != 'External'",message="labels, annotations and podTemplateMetadata cannot be set for External MCPServers"
+// +kubebuilder:validation:XValidation:rule="!has(self.basePath) || !has(self.type) || self.type != 'External'",message="basePath cannot be set for External MCPServers, their url holds the path"
+// +kubebuilder:validation:XValidation:rule="!has(self.protocol) || self.protocol == 'HTTP' || !has(self.type) || self.type == 'Managed'",message="protocol can only be set to HTTP2 or GRPC for Managed MCPServers"
+// +kubebuilder:validation:XValidation:rule="!has(self.protocol) || self.protocol == 'HTTP' || !(has(self.guardrails) || has(self.capabilities) || has(self.rateLimit) || has(self.auth) || has(self.metricsExporter) || has(self.restBridge))",message="protocol HTTP2 and GRPC cannot be combined with guardrails, capabilities, rateLimit, auth, metricsExporter or restBridge, their sidecars only speak HTTP/1.1"
+// +kubebuilder:validation:XValidation:rule="!has(self.protocol) || self.protocol != 'GRPC' || !((has(self.testConnection) && self.testConnection) || has(self.conformanceCheck))",message="testConnection and conformanceCheck are not supported for GRPC MCPServers"
+// +kubebuilder:validation:XValidation:rule="!has(self.ttlSecondsAfterLastActivity) || has(self.metricsExporter)",message="ttlSecondsAfterLastActivity requires metricsExporter, which tracks the usage of the MCP server"
 type MCPServerSpec struct {
-	// Image specifies the image of the MCP server
-	// +kubebuilder:validation:Required
+	// DisplayName is the human readable name of the MCP server shown in catalogs, e.g. Kubernetes Tools.
+	// Defaults to the openshift.io/display-name annotation, or the name of the MCPServer.
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
+
+	// Description tells the users of a catalog what the MCP server offers. Defaults to the
+	// openshift.io/description annotation.
+	// +kubebuilder:validation:MaxLength=1024
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Owner is the team responsible for the MCP server. Its team is set as the mcpserver.opendatahub.io/owner-team
+	// label, and its contact as the mcpserver.opendatahub.io/owner-contact annotation, of all resources created
+	// for the server, and both are exported in the fleet metrics of the operator.
+	// +optional
+	Owner *Owner `json:"owner,omitempty"`
+
+	// Labels are added to the Deployment, Service and Route of the MCP server and to its pods, e.g. cost-center
+	// labels for cost attribution. Labels the operator sets itself take precedence. Changing them rolls out the
+	// Deployment, and labels removed from the field are left in place. It is not supported for External MCP
+	// servers.
+	// +kubebuilder:validation:MaxProperties=64
+	// +kubebuilder:validation:XValidation:rule="self.all(k, !k.startsWith('mcpserver.opendatahub.io/') && k != 'opendatahub.io/mcp-server')",message="labels cannot set the labels of the operator"
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are added to the Deployment, Service and Route of the MCP server. Annotations for the pods,
+	// such as those that enable sidecar injection, belong in podTemplateMetadata. Annotations the operator sets
+	// itself take precedence, and those removed from the field are left in place. It is not supported for
+	// External MCP servers.
+	// +kubebuilder:validation:MaxProperties=64
+	// +kubebuilder:validation:XValidation:rule="self.all(k, !k.startsWith('mcpserver.opendatahub.io/'))",message="annotations cannot set the annotations of the operator"
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// PodTemplateMetadata adds labels and annotations to the MCP server pods only, e.g. sidecar.istio.io/inject or
+	// vault.hashicorp.com/agent-inject. Its labels take precedence over spec.labels. It is not supported for
+	// External MCP servers.
+	// +optional
+	PodTemplateMetadata *PodTemplateMetadata `json:"podTemplateMetadata,omitempty"`
+
+	// Type is Managed for MCP servers the operator deploys from image, or External for MCP servers that are
+	// already running elsewhere at url. No workload is created for External MCP servers. Proxy MCP servers
+	// also run at url, but are reached through an in-cluster proxy the operator deploys in place of image.
+	// +kubebuilder:default=Managed
+	// +optional
+	Type MCPServerType `json:"type,omitempty"`
+
+	// Image specifies the image of the MCP server. It is required for Managed MCP servers.
 	// +kubebuilder:validation:MinLength=1
-	Image string `json:"image"`
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// URL is the SSE endpoint of an External or Proxy MCP server, e.g. https://mcp.example.com/sse
+	// +kubebuilder:validation:Pattern=`^https?://`
+	// +optional
+	URL string `json:"url,omitempty"`
 
-	// Args specifies the runtime args for the MCP server
+	// CredentialsSecretRef selects the key of a Secret holding a bearer token that is sent to an External MCP
+	// server when checking its health, or that the proxy of a Proxy MCP server adds to every request.
+	// +optional
+	CredentialsSecretRef *corev1.SecretKeySelector `json:"credentialsSecretRef,omitempty"`
+
+	// CredentialsExposure is how credentialsSecretRef is handed to the proxy of a Proxy MCP server and to the
+	// connection test of an External one. Both read it from a file by default.
+	// +optional
+	CredentialsExposure *SecretExposure `json:"credentialsExposure,omitempty"`
+
+	// Args specifies the runtime args for the MCP server. $(NAME) is replaced with the environment variable NAME
+	// of the container, $$(NAME) passes $(NAME) on literally.
 	// +optional
 	Args []string `json:"args,omitempty"`
 
 	// Command specifies the command for the MCP server
 	// +optional
 	Command []string `json:"command,omitempty"`
+
+	// Config sets common options of the MCP server by name, e.g. readOnly: true or toolsets: [core, helm],
+	// which the operator renders into the flags of the server. Only the Kubernetes MCP server run by the
+	// default command supports it, see the README for its options. Flags rendered from config are added
+	// after args.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Config map[string]apiextensionsv1.JSON `json:"config,omitempty"`
+
+	// Env sets environment variables of the MCP server container, e.g. API keys from a Secret with
+	// valueFrom.secretKeyRef or settings from a ConfigMap with valueFrom.configMapKeyRef. They take precedence over
+	// the variables the operator sets, and spec.args can reference them as $(NAME). It is only supported for
+	// Managed MCP servers.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// EnvFrom sets all keys of the given Secrets and ConfigMaps as environment variables of the MCP server
+	// container. Variables of env take precedence over them. It is only supported for Managed MCP servers.
+	// +listType=atomic
+	// +optional
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+
+	// TestConnection makes the operator run a short-lived Job that performs an MCP handshake
+	// against the server from inside the cluster, once per generation of the MCPServer.
+	// +optional
+	TestConnection bool `json:"testConnection,omitempty"`
+
+	// VerifyImage makes the operator check that the manifest of image exists in its registry before it rolls the
+	// image out, with the image pull secrets of the service account of the pods. A missing image is reported in
+	// the ImageNotFound condition and the Deployment keeps running the previous image, rather than the new pods
+	// failing with ErrImagePull. It is only supported for Managed MCP servers.
+	// +optional
+	VerifyImage bool `json:"verifyImage,omitempty"`
+
+	// ImagePullPolicy is the pull policy of the MCP server container. It defaults to Always for images with the
+	// latest tag or without a tag and to IfNotPresent for all others. It is only supported for Managed MCP servers.
+	// +kubebuilder:validation:Enum=Always;IfNotPresent;Never
+	// +optional
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// PinImageDigest makes the operator resolve the tag of image to the digest of its manifest, with the image pull
+	// secrets of the service account of the pods, and roll out the image by that digest, so that all pods run the
+	// same image even when the tag is pushed again. The digest is resolved again only when image changes and when
+	// the MCP server is restarted with kubectl mcp restart, and is recorded in status.imageDigest. The workload is
+	// held back while the digest of a new image cannot be resolved. It is only supported for Managed MCP servers.
+	// +optional
+	PinImageDigest bool `json:"pinImageDigest,omitempty"`
+
+	// DependsOn lists the MCPServers and other objects in the namespace of the MCPServer that must be ready
+	// before the operator rolls out the MCP server. While one is not, the workload is neither created nor updated,
+	// and the DependenciesNotReady condition and the Available condition report it.
+	// +kubebuilder:validation:MaxItems=16
+	// +listType=atomic
+	// +optional
+	DependsOn []Dependency `json:"dependsOn,omitempty"`
+
+	// RequeueInterval is how often the operator probes the endpoint of the MCP server while it is not reachable,
+	// e.g. "30s". Changes to the Deployment, Service and Route are picked up as they happen.
+	// The operator-wide default, set with --requeue-interval, is used when unset.
+	// +optional
+	RequeueInterval *metav1.Duration `json:"requeueInterval,omitempty"`
+
+	// ToolsRefreshInterval is how often the operator lists the tools of the MCP server again, e.g. "1h". The
+	// tools are otherwise only listed after each rollout, after a change of the MCPServer and when the
+	// mcpserver.opendatahub.io/refresh-tools annotation changes.
+	// +optional
+	ToolsRefreshInterval *metav1.Duration `json:"toolsRefreshInterval,omitempty"`
+
+	// ResourcesPreset selects a curated bundle of CPU and memory requests and limits for the MCP server
+	// container. The bundles are maintained in the operator configuration.
+	// +optional
+	ResourcesPreset ResourcesPreset `json:"resourcesPreset,omitempty"`
+
+	// SessionStore configures a store shared by all replicas of the MCP server for its streamable HTTP
+	// sessions. Its connection details are passed to the server in the MCP_SESSION_STORE_TYPE and
+	// MCP_SESSION_STORE_URL environment variables.
+	// +optional
+	SessionStore *SessionStore `json:"sessionStore,omitempty"`
+
+	// Cache mounts a size-limited scratch volume into the MCP server container. It is removed with the pod.
+	// +optional
+	Cache *Cache `json:"cache,omitempty"`
+
+	// Volumes are added to the MCP server pods, e.g. ConfigMaps with tool configuration, Secrets with a kubeconfig
+	// or emptyDirs. They are mounted into the MCP server container with volumeMounts. The names the operator uses
+	// for the volumes it adds itself are reserved. It is only supported for Managed MCP servers.
+	// +kubebuilder:validation:MaxItems=32
+	// +kubebuilder:validation:XValidation:rule="self.all(v, !(v.name in ['cache', 'credentials', 'guardrails-credentials', 'session-store-url', 'auth-token']))",message="cache, credentials, guardrails-credentials, session-store-url and auth-token are reserved volume names"
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Volumes []corev1.Volume `json:"volumes,omitempty"`
+
+	// VolumeMounts mounts volumes of spec.volumes into the MCP server container. It is only supported for Managed
+	// MCP servers.
+	// +kubebuilder:validation:MaxItems=32
+	// +listType=atomic
+	// +optional
+	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty"`
+
+	// Replicas is the number of MCP server pods. The Deployment keeps its own replica count when unset.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Autoscaling scales the MCP server pods with a HorizontalPodAutoscaler named after the MCPServer. The
+	// operator then leaves the replica count of the Deployment to the autoscaler, as it does for an autoscaler
+	// created by hand, and spec.replicas only scales the server to zero pods and back. It is not supported for
+	// External MCP servers.
+	// +optional
+	Autoscaling *Autoscaling `json:"autoscaling,omitempty"`
+
+	// IgnoreDifferences lists fields of the resources of the MCP server that another controller or tool, such as
+	// an HPA, KEDA or Argo CD, manages, so that the operator stops reverting them.
+	// +optional
+	IgnoreDifferences *IgnoreDifferences `json:"ignoreDifferences,omitempty"`
+
+	// MinReadySeconds is how long a new MCP server pod must be ready before it counts as available during a
+	// rollout. Defaults to 0.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinReadySeconds *int32 `json:"minReadySeconds,omitempty"`
+
+	// ProgressDeadlineSeconds is how long a rollout may make no progress before it is reported as stuck in the
+	// Degraded condition. Defaults to 600.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+
+	// RevisionHistoryLimit is the number of old ReplicaSets of the MCP server Deployment kept to allow a
+	// rollback. Defaults to 10.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+
+	// Affinity sets the scheduling constraints of the MCP server pods, and replaces the affinity of an existing
+	// Deployment. When unset and more than one replica is requested, the replicas are preferably spread across
+	// nodes and zones.
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// NodeSelector restricts the MCP server pods to the nodes with these labels, e.g. those of a dedicated node
+	// pool. It is not supported for External MCP servers.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations let the MCP server pods run on nodes with matching taints, e.g. those of a dedicated node pool.
+	// It is not supported for External MCP servers.
+	// +listType=atomic
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// HostAliases are added to the hosts file of the MCP server pods and of the Jobs the operator runs against
+	// the server, so that hostnames missing from the cluster DNS, such as those of on-premises systems, resolve.
+	// +listType=atomic
+	// +optional
+	HostAliases []corev1.HostAlias `json:"hostAliases,omitempty"`
+
+	// Lifecycle sets the postStart and preStop hooks of the MCP server container, e.g. to register the server
+	// with an external system once it starts and to deregister it or flush its state before it stops. It is
+	// not supported for External MCP servers.
+	// +optional
+	Lifecycle *corev1.Lifecycle `json:"lifecycle,omitempty"`
+
+	// Probes configures the startup, liveness and readiness probes of the MCP server container. New Deployments
+	// get default probes against the server port, so that MCP servers that crash or hang are restarted and stop
+	// receiving traffic. Deployments created before get them once probes is set. It is only supported for Managed
+	// MCP servers.
+	// +optional
+	Probes *Probes `json:"probes,omitempty"`
+
+	// ServiceAccountName is the existing service account the MCP server pods run with, e.g. one bound to the roles
+	// the tools need. When unset, the pods of Managed MCP servers run with a service account of their own named
+	// after the MCPServer, which the operator creates with the image pull secrets of the default service account,
+	// and those of Proxy MCP servers with the default service account. It is not supported for External MCP
+	// servers.
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// AutomountServiceAccountToken mounts the token of the service account into the MCP server pods. It
+	// defaults to true for servers that need the Kubernetes API, the Kubernetes MCP server run by the default
+	// command and the proxy of Proxy servers, and to false for all others.
+	// +optional
+	AutomountServiceAccountToken *bool `json:"automountServiceAccountToken,omitempty"`
+
+	// SecurityContext sets the user and groups the MCP server pods run as, for images that must run as a specific
+	// UID. On OpenShift the pods then request the SCC that admits them. It is not supported for External MCP
+	// servers.
+	// +optional
+	SecurityContext *SecurityContext `json:"securityContext,omitempty"`
+
+	// PodSecurityContext sets the security context of the MCP server pods, e.g. runAsNonRoot, fsGroup,
+	// supplementalGroups or seccompProfile. The user and groups of securityContext take precedence over it. A pod
+	// that runs with a UID or groups of its own requests the SCC that admits it on OpenShift, as with
+	// securityContext. It is not supported for External MCP servers.
+	// +optional
+	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+
+	// ContainerSecurityContext sets the security context of the MCP server container, e.g. capabilities,
+	// readOnlyRootFilesystem or allowPrivilegeEscalation. For Proxy MCP servers the fields that are set replace
+	// those of the hardened security context of the proxy. The sidecars keep their own. It is not supported for
+	// External MCP servers.
+	// +optional
+	ContainerSecurityContext *corev1.SecurityContext `json:"containerSecurityContext,omitempty"`
+
+	// Guardrails routes the traffic of the MCP server through a filter that checks tool inputs and outputs
+	// with a guardrails detection service. It is not supported for External MCP servers.
+	// +optional
+	Guardrails *Guardrails `json:"guardrails,omitempty"`
+
+	// Capabilities disables whole classes of MCP capabilities of the server, e.g. to offer its tools but not its
+	// resources. A filter in front of the server hides the disabled classes from the capabilities the server
+	// announces and answers their requests with a method not found error. It is not supported for External MCP
+	// servers.
+	// +optional
+	Capabilities *Capabilities `json:"capabilities,omitempty"`
+
+	// RESTBridge offers the tools of the MCP server as a REST API with an OpenAPI description, for clients that do
+	// not speak MCP. A sidecar calls the tools as an MCP client of the server and is published on a Service, and
+	// a Route where the server has one, of its own. It is not supported for External MCP servers.
+	// +optional
+	RESTBridge *RESTBridge `json:"restBridge,omitempty"`
+
+	// GatewayRef attaches the MCP server to an existing Gateway API Gateway shared with other servers. An
+	// HTTPRoute to the Service of the MCP server is created in place of a Route.
+	// +optional
+	GatewayRef *GatewayRef `json:"gatewayRef,omitempty"`
+
+	// MeshGateway publishes the MCP server under a path of the host of an existing Istio ingress gateway shared
+	// with other APIs. Only a VirtualService that routes the path to the Service of the MCP server is created,
+	// in place of a Route. It is not supported for External MCP servers.
+	// +optional
+	MeshGateway *MeshGateway `json:"meshGateway,omitempty"`
+
+	// Expose configures the Route that exposes the MCP server outside the cluster. It is not supported for
+	// External MCP servers.
+	// +optional
+	Expose *Expose `json:"expose,omitempty"`
+
+	// Route configures the Route that exposes the MCP server outside the cluster. It is not supported for
+	// External MCP servers, nor with gatewayRef or meshGateway, which replace the Route.
+	// +optional
+	Route *Route `json:"route,omitempty"`
+
+	// Exposures publish the MCP server through further Routes and Ingresses of its own, or under a path of the
+	// shared host of the operator, next to the Route, HTTPRoute or VirtualService that expose, gatewayRef or
+	// meshGateway configure, e.g. an Ingress for an external load balancer on a cluster where the server also
+	// keeps the Route on the default domain. Each is reported in an ExposureAvailable-<name> condition. They are
+	// not supported for External MCP servers.
+	// +kubebuilder:validation:MaxItems=8
+	// +kubebuilder:validation:XValidation:rule="self.filter(e, e.type == 'SharedHost').size() <= 1",message="only one exposure can be of type SharedHost"
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Exposures []Exposure `json:"exposures,omitempty"`
+
+	// SSE configures how the event streams of the MCP server are kept open, so that proxies between the agent
+	// and the server do not buffer or drop them. It is not supported for External MCP servers.
+	// +optional
+	SSE *SSE `json:"sse,omitempty"`
+
+	// BasePath is the path the MCP server serves MCP under, e.g. /mcp for the streamable HTTP transport. The status
+	// URLs, the endpoint probe, the connection test and the generated client configurations use it, and the Route
+	// only admits requests under it. Defaults to the SSE endpoint /sse, with a Route that admits all paths.
+	// +kubebuilder:validation:MaxLength=256
+	// +kubebuilder:validation:Pattern=`^/[-A-Za-z0-9._~/]*$`
+	// +optional
+	BasePath string `json:"basePath,omitempty"`
+
+	// Protocol is the protocol the MCP server speaks on its http port. HTTP2 and GRPC servers are reached over
+	// cleartext HTTP/2 (h2c) through their Service, and over TLS through their Route, so that clients can
+	// negotiate HTTP/2 with the router. Sidecars in front of the MCP server only proxy HTTP/1.1 and cannot be
+	// combined with them.
+	// +kubebuilder:default=HTTP
+	// +optional
+	Protocol Protocol `json:"protocol,omitempty"`
+
+	// AllowedClientNamespaces selects the namespaces whose pods may call the MCP server. When set, a
+	// NetworkPolicy admits traffic to the MCP server pods only from these namespaces, its own namespace, the
+	// operator, the router of its Route or the namespace of its Gateway, and the monitoring stack. An empty
+	// selector selects all namespaces. It is not supported for External MCP servers.
+	// +optional
+	AllowedClientNamespaces *metav1.LabelSelector `json:"allowedClientNamespaces,omitempty"`
+
+	// ConformanceCheck makes the operator run a short-lived Job with a basic MCP conformance suite against the
+	// server after each rollout of its Deployment. The results are reported in status.conformanceCheck. It is
+	// not supported for External MCP servers.
+	// +optional
+	ConformanceCheck *ConformanceCheck `json:"conformanceCheck,omitempty"`
+
+	// MetricsExporter routes the traffic of the MCP server through a sidecar that exports Prometheus metrics
+	// of its MCP requests, tool calls and sessions, for server images without metrics of their own. A
+	// ServiceMonitor is created for the metrics when the Prometheus Operator is installed. It is not supported
+	// for External MCP servers.
+	// +optional
+	MetricsExporter *MetricsExporter `json:"metricsExporter,omitempty"`
+
+	// RateLimit limits the rate of requests each client may send to the MCP server, e.g. to keep a misbehaving
+	// agent loop from overwhelming the API the server wraps. It is not supported for External MCP servers.
+	// +optional
+	RateLimit *RateLimit `json:"rateLimit,omitempty"`
+
+	// Observability configures how the logs of the MCP server reach a central log store. It is not supported
+	// for External MCP servers.
+	// +optional
+	Observability *Observability `json:"observability,omitempty"`
+
+	// Auth makes clients authenticate to the MCP server. It is only supported for Managed MCP servers.
+	// +optional
+	Auth *Auth `json:"auth,omitempty"`
+
+	// KubernetesAccess configures the identity the Kubernetes MCP server run by the default command uses for the
+	// calls it makes to the Kubernetes API. It is only supported for Managed MCP servers.
+	// +optional
+	KubernetesAccess *KubernetesAccess `json:"kubernetesAccess,omitempty"`
+
+	// TTLSecondsAfterCreation is how many seconds after its creation the operator deletes the MCPServer, e.g.
+	// for experiments and demos.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TTLSecondsAfterCreation *int32 `json:"ttlSecondsAfterCreation,omitempty"`
+
+	// TTLSecondsAfterLastActivity is how many seconds after status.usage.lastActivityTime, or after its creation
+	// while it has not been used, the operator deletes the MCPServer. Usage is only tracked with a metrics
+	// exporter.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TTLSecondsAfterLastActivity *int32 `json:"ttlSecondsAfterLastActivity,omitempty"`
+}
+
+// KubernetesAccessMode is the identity an MCP server calls the Kubernetes API with.
+// +kubebuilder:validation:Enum=ServiceAccount;TokenPassthrough
+type KubernetesAccessMode string
+
+const (
+	// KubernetesAccessServiceAccount servers call the Kubernetes API with the service account of their pods.
+	KubernetesAccessServiceAccount KubernetesAccessMode = "ServiceAccount"
+	// KubernetesAccessTokenPassthrough servers call the Kubernetes API with the bearer token of each caller, so
+	// their tools are subject to the RBAC of the invoking user.
+	KubernetesAccessTokenPassthrough KubernetesAccessMode = "TokenPassthrough"
+)
+
+// KubernetesAccess configures how an MCP server calls the Kubernetes API.
+type KubernetesAccess struct {
+	// Mode is ServiceAccount, the default, or TokenPassthrough, which rejects requests without a bearer token
+	// and uses it for the Kubernetes API calls made on behalf of the request.
+	// +kubebuilder:default=ServiceAccount
+	// +optional
+	Mode KubernetesAccessMode `json:"mode,omitempty"`
+}
+
+// AuthType is how clients authenticate to an MCP server.
+// +kubebuilder:validation:Enum=Token
+type AuthType string
+
+const (
+	// AuthToken admits the requests that present a static bearer token.
+	AuthToken AuthType = "Token"
+)
+
+// Auth configures the authentication of the clients of an MCP server.
+type Auth struct {
+	// Type is how clients authenticate.
+	Type AuthType `json:"type"`
+
+	// TokenSecretRef selects the key of an existing Secret that holds the bearer token of the Token type. When
+	// unset, the operator generates a token into the Secret <name>-auth-token, under the key token.
+	// +optional
+	TokenSecretRef *corev1.SecretKeySelector `json:"tokenSecretRef,omitempty"`
+}
+
+// Observability configures the telemetry of an MCP server.
+type Observability struct {
+	// LogForwarding forwards the logs of the MCP server, including the tool calls logged by the sidecars, to a
+	// central log store.
+	// +optional
+	LogForwarding *LogForwarding `json:"logForwarding,omitempty"`
+}
+
+// LogForwarding configures where the logs of an MCP server are forwarded to.
+// +kubebuilder:validation:XValidation:rule="!has(self.labels) || !('opendatahub.io/mcp-server' in self.labels)",message="the opendatahub.io/mcp-server label is set by the operator"
+type LogForwarding struct {
+	// OTLPEndpoint is the base URL of an OTLP/HTTP receiver, such as an OpenTelemetry Collector, e.g.
+	// http://otel-collector.observability.svc:4318. It is passed to the MCP server in the standard
+	// OTEL_EXPORTER_OTLP_ENDPOINT and OTEL_LOGS_EXPORTER environment variables, so servers instrumented with an
+	// OpenTelemetry SDK export their logs to it. It does not apply to Proxy MCP servers.
+	// +kubebuilder:validation:Pattern=`^https?://`
+	// +optional
+	OTLPEndpoint string `json:"otlpEndpoint,omitempty"`
+
+	// Labels are added to the MCP server pods. The cluster logging stack attaches the labels of a pod to its
+	// log records, so they can select the records in a ClusterLogForwarder and label the Loki streams. They
+	// are also passed to the OTLP endpoint as resource attributes.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// Owner identifies the team responsible for an MCP server, for cost attribution and notifications.
+type Owner struct {
+	// Team is the name of the team, a valid label value, e.g. payments. The operator may require a format, see
+	// its --owner-team-pattern flag.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?$`
+	Team string `json:"team"`
+
+	// Contact is how to reach the team, e.g. an email address or a chat channel. The operator may require a
+	// format, see its --owner-contact-pattern flag.
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	Contact string `json:"contact,omitempty"`
+}
+
+// PodTemplateMetadata is metadata added to the pods of an MCP server.
+type PodTemplateMetadata struct {
+	// Labels are added to the MCP server pods. Labels the operator sets itself take precedence.
+	// +kubebuilder:validation:MaxProperties=64
+	// +kubebuilder:validation:XValidation:rule="self.all(k, !k.startsWith('mcpserver.opendatahub.io/') && k != 'opendatahub.io/mcp-server')",message="labels cannot set the labels of the operator"
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are added to the MCP server pods. Annotations the operator sets itself, such as the
+	// openshift.io/required-scc annotation of a securityContext, take precedence.
+	// +kubebuilder:validation:MaxProperties=64
+	// +kubebuilder:validation:XValidation:rule="self.all(k, !k.startsWith('mcpserver.opendatahub.io/'))",message="annotations cannot set the annotations of the operator"
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Autoscaling configures the HorizontalPodAutoscaler of an MCP server.
+// +kubebuilder:validation:XValidation:rule="!has(self.minReplicas) || self.minReplicas <= self.maxReplicas",message="minReplicas cannot be greater than maxReplicas"
+type Autoscaling struct {
+	// MinReplicas is the lowest number of pods the autoscaler scales the MCP server to. Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas is the highest number of pods the autoscaler scales the MCP server to.
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// TargetCPUUtilizationPercentage is the average CPU utilization of the MCP server pods, relative to their
+	// CPU requests, that the autoscaler aims for. Defaults to 80.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TargetCPUUtilizationPercentage *int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+}
+
+// SecurityContext sets the user and groups of the MCP server pods.
+type SecurityContext struct {
+	// RunAsUser is the UID the containers of the MCP server pods run as.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RunAsUser *int64 `json:"runAsUser,omitempty"`
+
+	// RunAsGroup is the primary GID the containers of the MCP server pods run as.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RunAsGroup *int64 `json:"runAsGroup,omitempty"`
+
+	// FSGroup is the GID that owns the volumes of the MCP server pods and is added to the groups of their
+	// containers.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	FSGroup *int64 `json:"fsGroup,omitempty"`
+}
+
+// IgnoreDifferences lists fields the operator sets when it creates the resources of an MCP server, but leaves
+// alone afterwards.
+type IgnoreDifferences struct {
+	// Replicas leaves the replica count of the existing Deployment to whatever scales it. spec.replicas then only
+	// sets the replicas of a new Deployment.
+	// +optional
+	Replicas bool `json:"replicas,omitempty"`
+
+	// Annotations are the keys of annotations on the resources of the MCP server that the operator neither
+	// changes nor removes once they exist, such as haproxy.router.openshift.io/timeout on its Route. A key ending
+	// in * matches all keys with that prefix, e.g. argocd.argoproj.io/*.
+	// +kubebuilder:validation:MaxItems=64
+	// +kubebuilder:validation:items:MinLength=1
+	// +listType=set
+	// +optional
+	Annotations []string `json:"annotations,omitempty"`
+}
+
+// RateLimit configures the rate limiting of the traffic to an MCP server.
+type RateLimit struct {
+	// Local runs a rate-limiting sidecar in each MCP server pod, without an external rate limiting service.
+	// Each pod counts the requests it receives on its own, so a client may send up to replicas times the
+	// rate in total.
+	// +optional
+	Local *LocalRateLimit `json:"local,omitempty"`
+}
+
+// Protocol is the protocol an MCP server speaks.
+// +kubebuilder:validation:Enum=HTTP;HTTP2;GRPC
+type Protocol string
+
+const (
+	// ProtocolHTTP is HTTP/1.1, which every MCP server over SSE or streamable HTTP speaks.
+	ProtocolHTTP Protocol = "HTTP"
+	// ProtocolHTTP2 is cleartext HTTP/2 (h2c).
+	ProtocolHTTP2 Protocol = "HTTP2"
+	// ProtocolGRPC is gRPC over cleartext HTTP/2. The operator does not probe the tools of gRPC servers.
+	ProtocolGRPC Protocol = "GRPC"
+)
+
+// RateLimitKey identifies the clients whose requests are counted together.
+// +kubebuilder:validation:Enum=ClientIP;Identity
+type RateLimitKey string
+
+const (
+	// RateLimitClientIP counts the requests of each client address.
+	RateLimitClientIP RateLimitKey = "ClientIP"
+	// RateLimitIdentity counts the requests of each bearer token, and those without one by client address.
+	RateLimitIdentity RateLimitKey = "Identity"
+)
+
+// LocalRateLimit is a token bucket per client, refilled at RequestsPerSecond up to Burst requests.
+type LocalRateLimit struct {
+	// RequestsPerSecond is the sustained rate of requests each client may send.
+	// +kubebuilder:validation:Minimum=1
+	RequestsPerSecond int32 `json:"requestsPerSecond"`
+
+	// Burst is the number of requests a client may send at once after being idle. Defaults to
+	// RequestsPerSecond.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Burst *int32 `json:"burst,omitempty"`
+
+	// Key identifies the clients whose requests are counted together.
+	// +kubebuilder:default=ClientIP
+	// +optional
+	Key RateLimitKey `json:"key,omitempty"`
+}
+
+// MetricsExporter configures the metrics exporter sidecar.
+type MetricsExporter struct {
+	// Interval is how often Prometheus scrapes the metrics, e.g. "30s". The default of Prometheus is used when
+	// unset.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// UsageInterval is how often the operator scrapes the metrics of the MCP server pods into status.usage.
+	// Defaults to 5m.
+	// +optional
+	UsageInterval *metav1.Duration `json:"usageInterval,omitempty"`
+}
+
+// ConformanceCheck configures the MCP conformance suite.
+type ConformanceCheck struct {
+	// Tool is the name of a tool without side effects, e.g. one that echoes its input, that the suite calls.
+	// The tool call is skipped when unset.
+	// +optional
+	Tool string `json:"tool,omitempty"`
+
+	// Arguments are passed to Tool.
+	// +optional
+	Arguments map[string]string `json:"arguments,omitempty"`
+}
+
+// GatewayRef names a Gateway API Gateway, and optionally one of its listeners.
+type GatewayRef struct {
+	// Name of the Gateway.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Namespace of the Gateway. Defaults to the namespace of the MCPServer.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// SectionName selects a listener of the Gateway. All listeners that allow the HTTPRoute are used when
+	// unset.
+	// +optional
+	SectionName string `json:"sectionName,omitempty"`
+}
+
+// Expose configures the exposure of an MCP server outside the cluster.
+type Expose struct {
+	// AllowedSourceRanges restricts the clients that can reach the MCP server through its Route to these IP
+	// addresses and CIDR ranges, e.g. 10.0.0.0/8. All clients are allowed when unset.
+	// +kubebuilder:validation:MaxItems=64
+	// +kubebuilder:validation:items:Pattern=`^[0-9a-fA-F:.]+(/[0-9]{1,3})?$`
+	// +listType=set
+	// +optional
+	AllowedSourceRanges []string `json:"allowedSourceRanges,omitempty"`
+}
+
+// Dependency is an object an MCP server depends on, ready once its status has a readiness condition with
+// status True.
+// +kubebuilder:validation:XValidation:rule="has(self.apiVersion) == has(self.kind)",message="apiVersion and kind must be set together"
+type Dependency struct {
+	// APIVersion is the API group and version of the object, e.g. apps/v1. Defaults to the API of MCPServers.
+	// +optional
+	APIVersion string `json:"apiVersion,omitempty"`
+
+	// Kind is the kind of the object, e.g. Deployment. Defaults to MCPServer. The operator needs get permission
+	// on the kind to check it.
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// Name is the name of the object, in the namespace of the MCPServer.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// ConditionType is the type of the condition in status.conditions of the object that tells it is ready.
+	// Defaults to Available for MCPServers and to Ready for other kinds.
+	// +optional
+	ConditionType string `json:"conditionType,omitempty"`
+}
+
+// Route configures the Route of an MCP server.
+type Route struct {
+	// TLS configures how the router terminates TLS for the Route.
+	// +optional
+	TLS *RouteTLS `json:"tls,omitempty"`
+
+	// IngressControllerSelector are labels set on the Route so that it is only admitted by the router shard whose
+	// IngressController selects them with its routeSelector, e.g. an internal-only router. Labels removed from
+	// the selector are removed from the Route. The routers of the cluster must be sharded for it to take effect,
+	// a router without a routeSelector admits the Route regardless.
+	// +kubebuilder:validation:XValidation:rule="self.all(k, k != 'opendatahub.io/mcp-server' && !k.startsWith('mcpserver.opendatahub.io/'))",message="labels of the operator cannot be used to select an ingress controller"
+	// +optional
+	IngressControllerSelector map[string]string `json:"ingressControllerSelector,omitempty"`
+}
+
+// RouteTLS configures the TLS termination of the Route of an MCP server.
+type RouteTLS struct {
+	// CertificateSecretRef names a kubernetes.io/tls Secret in the namespace of the MCPServer, e.g. with a wildcard
+	// or per-host certificate of the team, whose tls.crt, tls.key and optional ca.crt the router serves for the
+	// host of the Route instead of the default certificate of the ingress controller. The Route is edge terminated
+	// and redirects plain HTTP. A renewed certificate is synced to the Route when the Secret changes.
+	CertificateSecretRef corev1.LocalObjectReference `json:"certificateSecretRef"`
+}
+
+// ExposureType is the kind of object an exposure of an MCP server is published through.
+// +kubebuilder:validation:Enum=Route;Ingress;SharedHost
+type ExposureType string
+
+const (
+	// ExposureRoute publishes the MCP server through an OpenShift Route.
+	ExposureRoute ExposureType = "Route"
+	// ExposureIngress publishes the MCP server through an Ingress.
+	ExposureIngress ExposureType = "Ingress"
+	// ExposureSharedHost publishes the MCP server under the path /mcp/<namespace>/<name> of the shared host of
+	// the operator, which routes the paths of all MCP servers through a single Route or HTTPRoute.
+	ExposureSharedHost ExposureType = "SharedHost"
+)
+
+// Exposure publishes an MCP server through a Route or an Ingress of its own.
+// +kubebuilder:validation:XValidation:rule="self.type == 'Ingress' || !has(self.ingressClassName)",message="ingressClassName can only be set for Ingress exposures"
+// +kubebuilder:validation:XValidation:rule="self.type == 'Ingress' || !has(self.tlsSecretName)",message="tlsSecretName can only be set for Ingress exposures"
+// +kubebuilder:validation:XValidation:rule="!has(self.tlsSecretName) || has(self.host)",message="tlsSecretName requires host"
+// +kubebuilder:validation:XValidation:rule="self.type != 'SharedHost' || (!has(self.host) && !has(self.annotations))",message="host and annotations cannot be set for SharedHost exposures"
+type Exposure struct {
+	// Name identifies the exposure. Its Route or Ingress is named <name of the MCPServer>-expose-<name>.
+	// +kubebuilder:validation:MaxLength=32
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// Type is the kind of object the MCP server is published through, Route or Ingress, or SharedHost to publish
+	// it on the shared host the operator is configured with, where it takes no object of its own. Route exposures
+	// require the Route API of OpenShift.
+	Type ExposureType `json:"type"`
+
+	// Host is the host name the MCP server is published on, e.g. mcp.example.com. The router generates one
+	// on the default domain for a Route without a host, and an Ingress without a host serves every host its
+	// ingress controller receives.
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// IngressClassName selects the ingress controller of an Ingress exposure, e.g. the one of an external
+	// load balancer. The default IngressClass of the cluster is used when unset.
+	// +optional
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+
+	// TLSSecretName is the name of a kubernetes.io/tls Secret in the namespace of the MCPServer that the
+	// ingress controller of an Ingress exposure terminates TLS for host with.
+	// +optional
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
+
+	// Annotations are set on the Route or Ingress, e.g. to configure the load balancer of an ingress controller.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Probes configures the probes of the MCP server container. A probe that is set replaces the default one.
+type Probes struct {
+	// Path is the HTTP path the default probes request on the server port, e.g. /healthz. It defaults to /healthz
+	// for the Kubernetes MCP server run by the default command. The default probes of other MCP servers, and of
+	// HTTP2 and GRPC servers, which the kubelet cannot request over HTTP/1.1, open a TCP connection to the port
+	// unless path is set for them.
+	// +kubebuilder:validation:MaxLength=256
+	// +kubebuilder:validation:Pattern=`^/[-A-Za-z0-9._~/?=&]*$`
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Startup replaces the default startup probe, which gives the MCP server 5 minutes to start listening, e.g.
+	// while npx or uvx download its packages. Liveness and readiness are only probed once it succeeds.
+	// +optional
+	Startup *corev1.Probe `json:"startup,omitempty"`
+
+	// Liveness replaces the default liveness probe, which restarts the MCP server container after it failed 3
+	// times in a row, 20 seconds apart.
+	// +optional
+	Liveness *corev1.Probe `json:"liveness,omitempty"`
+
+	// Readiness replaces the default readiness probe, which takes the pod out of the Service after it failed 3
+	// times in a row, 10 seconds apart.
+	// +optional
+	Readiness *corev1.Probe `json:"readiness,omitempty"`
+}
+
+// SSE configures the event streams of an MCP server.
+type SSE struct {
+	// KeepAliveInterval is how often a keep-alive comment is sent on an event stream that is otherwise idle, so
+	// that proxies and load balancers do not close it, e.g. 15s. The proxy of Proxy MCPServers sends the
+	// keep-alives itself. Other MCP servers are passed the interval in seconds with keepAliveFlag.
+	// +optional
+	KeepAliveInterval *metav1.Duration `json:"keepAliveInterval,omitempty"`
+
+	// KeepAliveFlag is the flag the MCP server reads the keep-alive interval from, in seconds, e.g.
+	// --sse-keep-alive. The interval is not passed to the server when empty, as most servers have no such flag.
+	// +kubebuilder:validation:Pattern=`^--?[A-Za-z0-9][-A-Za-z0-9_.]*$`
+	// +optional
+	KeepAliveFlag string `json:"keepAliveFlag,omitempty"`
+
+	// IdleTimeout is how long the OpenShift router keeps an idle connection to the MCP server open, set with the
+	// haproxy.router.openshift.io/timeout annotation of its Route, e.g. 1h. It should be longer than
+	// keepAliveInterval. The annotation of the Route is left as it is when unset, and the router default of 30s
+	// applies unless it was set by hand.
+	// +optional
+	IdleTimeout *metav1.Duration `json:"idleTimeout,omitempty"`
+}
+
+// MeshGateway names a host of an Istio ingress gateway and the path an MCP server is published under.
+type MeshGateway struct {
+	// Gateway is the Istio Gateway that serves Host, as <namespace>/<name>, e.g. istio-system/api-gateway.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?/[a-z0-9]([-.a-z0-9]*[a-z0-9])?$`
+	Gateway string `json:"gateway"`
+
+	// Host is the hostname of the gateway the MCP server is published on, e.g. api.example.com.
+	// +kubebuilder:validation:MinLength=1
+	Host string `json:"host"`
+
+	// Path is the path prefix the MCP server is published under, e.g. /mcp/payments. It is removed from the
+	// requests before they reach the server. Defaults to /mcp/<namespace>/<name>.
+	// +kubebuilder:validation:Pattern=`^/[-a-zA-Z0-9._~/]*$`
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// GuardrailsAction is what the guardrails filter does with flagged tool traffic.
+// +kubebuilder:validation:Enum=Block;Redact;Audit
+type GuardrailsAction string
+
+const (
+	// GuardrailsBlock rejects flagged tool calls and replaces flagged tool results with an error.
+	GuardrailsBlock GuardrailsAction = "Block"
+	// GuardrailsRedact replaces the flagged text with [REDACTED].
+	GuardrailsRedact GuardrailsAction = "Redact"
+	// GuardrailsAudit only logs the detections.
+	GuardrailsAudit GuardrailsAction = "Audit"
+)
+
+// Guardrails describes the filter in front of an MCP server.
+type Guardrails struct {
+	// URL of the guardrails detection service, such as the TrustyAI guardrails orchestrator, e.g.
+	// https://guardrails-orchestrator.trustyai.svc:8032.
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+
+	// Detectors are the names of the detectors the detection service runs on tool inputs and outputs.
+	// +kubebuilder:validation:MinItems=1
+	Detectors []string `json:"detectors"`
+
+	// CredentialsSecretRef selects the key of a Secret holding a token that is sent as bearer token to the
+	// detection service.
+	// +optional
+	CredentialsSecretRef *corev1.SecretKeySelector `json:"credentialsSecretRef,omitempty"`
+
+	// CredentialsExposure is how credentialsSecretRef is handed to the guardrails filter, which reads it from a
+	// file by default.
+	// +optional
+	CredentialsExposure *SecretExposure `json:"credentialsExposure,omitempty"`
+
+	// Input is the action taken when the arguments of a tool call are flagged.
+	// +kubebuilder:default=Block
+	// +optional
+	Input GuardrailsAction `json:"input,omitempty"`
+
+	// Output is the action taken when the result of a tool call is flagged.
+	// +kubebuilder:default=Redact
+	// +optional
+	Output GuardrailsAction `json:"output,omitempty"`
+}
+
+// Capabilities selects the classes of MCP capabilities an MCP server offers. Each class is offered unless it is
+// set to false.
+type Capabilities struct {
+	// Tools offers the tools of the MCP server.
+	// +kubebuilder:default=true
+	// +optional
+	Tools *bool `json:"tools,omitempty"`
+
+	// Resources offers the resources and resource templates of the MCP server.
+	// +kubebuilder:default=true
+	// +optional
+	Resources *bool `json:"resources,omitempty"`
+
+	// Prompts offers the prompts of the MCP server.
+	// +kubebuilder:default=true
+	// +optional
+	Prompts *bool `json:"prompts,omitempty"`
+}
+
+// RESTBridge configures the REST API of the tools of an MCP server.
+type RESTBridge struct {
+	// Enabled runs the REST bridge. Every tool is then a POST endpoint under /tools/ taking its arguments as JSON
+	// object, described at /openapi.json.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+// CacheMedium is the storage backing a cache volume.
+// +kubebuilder:validation:Enum=Disk;Memory
+type CacheMedium string
+
+const (
+	CacheMediumDisk   CacheMedium = "Disk"
+	CacheMediumMemory CacheMedium = "Memory"
+)
+
+// Cache describes the scratch volume of an MCP server.
+type Cache struct {
+	// MountPath is the path the cache is mounted at in the MCP server container
+	// +kubebuilder:default="/cache"
+	// +kubebuilder:validation:Pattern=`^/`
+	// +optional
+	MountPath string `json:"mountPath,omitempty"`
+
+	// SizeLimit is the maximum size of the cache. The pod is evicted when it is exceeded.
+	// +kubebuilder:validation:Required
+	SizeLimit resource.Quantity `json:"sizeLimit"`
+
+	// Medium backs the cache with the node's disk, or with memory, which counts against the memory limit
+	// of the container.
+	// +kubebuilder:default=Disk
+	// +optional
+	Medium CacheMedium `json:"medium,omitempty"`
+}
+
+// SessionStoreType is the kind of a session store.
+// +kubebuilder:validation:Enum=Redis
+type SessionStoreType string
+
+const (
+	SessionStoreRedis SessionStoreType = "Redis"
+)
+
+// SessionStore describes where an MCP server keeps its sessions.
+type SessionStore struct {
+	// Type of the session store
+	// +kubebuilder:default=Redis
+	// +optional
+	Type SessionStoreType `json:"type,omitempty"`
+
+	// URLSecretRef selects the key of a Secret holding the URL of an existing store, e.g.
+	// redis://:password@redis.example.svc:6379/0. When unset, the operator provisions a store for the MCP server.
+	// +optional
+	URLSecretRef *corev1.SecretKeySelector `json:"urlSecretRef,omitempty"`
+
+	// URLExposure is how urlSecretRef is handed to the MCP server. By default it is set in the
+	// MCP_SESSION_STORE_URL environment variable; in File mode MCP_SESSION_STORE_URL_FILE holds the path of the
+	// file instead, which the MCP server must support.
+	// +optional
+	URLExposure *SecretExposure `json:"urlExposure,omitempty"`
+}
+
+// SecretExposureMode is how the key of a Secret is handed to a container.
+// +kubebuilder:validation:Enum=Env;File
+type SecretExposureMode string
+
+const (
+	// SecretExposureEnv sets the key in an environment variable, which is visible in /proc and crash dumps.
+	SecretExposureEnv SecretExposureMode = "Env"
+	// SecretExposureFile mounts the key as a file from a Secret volume.
+	SecretExposureFile SecretExposureMode = "File"
+)
+
+// SecretExposure describes how the key of a Secret is handed to a container.
+type SecretExposure struct {
+	// Mode is Env or File. The default depends on the Secret.
+	// +optional
+	Mode SecretExposureMode `json:"mode,omitempty"`
+
+	// Path of the file in File mode. Defaults to a file in /var/run/secrets/mcpserver.opendatahub.io.
+	// +kubebuilder:validation:Pattern=`^/`
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// FileMode is the permission bits of the file in File mode, e.g. 0400. The default of Secret volumes, 0644,
+	// is used when unset. A file that is not readable by others needs a pod fsGroup to be read by a non-root user.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=511
+	// +optional
+	FileMode *int32 `json:"fileMode,omitempty"`
+}
+
+// ResourcesPreset names a bundle of resource requests and limits.
+// +kubebuilder:validation:Enum=small;medium;large
+type ResourcesPreset string
+
+const (
+	ResourcesPresetSmall  ResourcesPreset = "small"
+	ResourcesPresetMedium ResourcesPreset = "medium"
+	ResourcesPresetLarge  ResourcesPreset = "large"
+)
+
+// ConnectionTestResult describes the outcome of a connection test.
+// +kubebuilder:validation:Enum=Running;Succeeded;Failed
+type ConnectionTestResult string
+
+const (
+	ConnectionTestRunning   ConnectionTestResult = "Running"
+	ConnectionTestSucceeded ConnectionTestResult = "Succeeded"
+	ConnectionTestFailed    ConnectionTestResult = "Failed"
+)
+
+// ConnectionTestStatus reports the last connection test run against the MCP server.
+type ConnectionTestStatus struct {
+	// Result of the connection test
+	Result ConnectionTestResult `json:"result"`
+
+	// Message holds a snippet of the connection test output
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// ObservedGeneration is the generation of the MCPServer the test was run for
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Tools is the number of tools the server offered in a successful connection test
+	// +optional
+	Tools *int32 `json:"tools,omitempty"`
+
+	// CompletionTime is the time the connection test finished
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// ConformanceResult describes the outcome of the conformance suite or one of its checks.
+// +kubebuilder:validation:Enum=Running;Passed;Failed;Skipped
+type ConformanceResult string
+
+const (
+	ConformanceRunning ConformanceResult = "Running"
+	ConformancePassed  ConformanceResult = "Passed"
+	ConformanceFailed  ConformanceResult = "Failed"
+	ConformanceSkipped ConformanceResult = "Skipped"
+)
+
+// ConformanceCheckResult reports a single check of the conformance suite.
+type ConformanceCheckResult struct {
+	// Name of the check, e.g. initialize or tools/list
+	Name string `json:"name"`
+
+	// Result of the check
+	Result ConformanceResult `json:"result"`
+
+	// Message explains a failed or skipped check
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ConformanceCheckStatus reports the last conformance suite run against the MCP server.
+type ConformanceCheckStatus struct {
+	// Result of the suite, Failed if any check failed
+	Result ConformanceResult `json:"result"`
+
+	// Revision is the revision of the MCP server Deployment the suite was run against
+	// +optional
+	Revision string `json:"revision,omitempty"`
+
+	// ObservedGeneration is the generation of the MCPServer the suite was run for
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Checks holds the result of each check
+	// +optional
+	Checks []ConformanceCheckResult `json:"checks,omitempty"`
+
+	// Message explains why the suite could not be run
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// CompletionTime is the time the suite finished
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// Tool is a tool offered by the MCP server.
+type Tool struct {
+	// Name of the tool
+	Name string `json:"name"`
+
+	// Description of the tool, truncated to 256 characters
+	// +optional
+	Description string `json:"description,omitempty"`
+}
+
+// ToolsRefreshStatus reports the last time the operator listed the tools of the MCP server.
+type ToolsRefreshStatus struct {
+	// Revision is the revision of the MCP server Deployment the tools were listed for
+	// +optional
+	Revision string `json:"revision,omitempty"`
+
+	// ObservedGeneration is the generation of the MCPServer the tools were listed for
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Trigger is the value of the mcpserver.opendatahub.io/refresh-tools annotation the tools were listed for
+	// +optional
+	Trigger string `json:"trigger,omitempty"`
+
+	// LastRefreshTime is the time the tools were last listed
+	// +optional
+	LastRefreshTime *metav1.Time `json:"lastRefreshTime,omitempty"`
+
+	// Message explains why the tools could not be listed. The tools of the previous listing are kept.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ImageDigestStatus is the digest an image was resolved to.
+type ImageDigestStatus struct {
+	// Image is the spec.image the digest was resolved for
+	Image string `json:"image"`
+
+	// Digest is the digest of the manifest of the image, e.g. sha256:4a5b..., which is the digest of the index
+	// for multi-arch images
+	Digest string `json:"digest"`
+
+	// ResolvedTime is the time the digest was resolved
+	// +optional
+	ResolvedTime *metav1.Time `json:"resolvedTime,omitempty"`
+}
+
+// UsageStatus is a coarse summary of the traffic of an MCP server.
+type UsageStatus struct {
+	// TotalRequests is the number of MCP requests answered by the current MCP server pods. It drops when pods
+	// are replaced.
+	// +optional
+	TotalRequests int64 `json:"totalRequests,omitempty"`
+
+	// ActiveSessions is the number of clients holding an event stream of the MCP server open
+	// +optional
+	ActiveSessions int32 `json:"activeSessions,omitempty"`
+
+	// LastActivityTime is the first scrape at which the MCP server was found to have answered requests since
+	// the scrape before, or to have active sessions
+	// +optional
+	LastActivityTime *metav1.Time `json:"lastActivityTime,omitempty"`
+
+	// LastScrapeTime is the time the metrics were last scraped
+	// +optional
+	LastScrapeTime *metav1.Time `json:"lastScrapeTime,omitempty"`
+
+	// Message explains why the metrics could not be scraped. The usage of the previous scrape is kept.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// PodSummary aggregates the state of the MCP server pods.
+type PodSummary struct {
+	// Ready is the number of pods that are ready
+	Ready int32 `json:"ready"`
+
+	// Total is the number of pods of the MCP server Deployment
+	Total int32 `json:"total"`
+
+	// Restarts is the sum of the container restart counts of all pods
+	// +optional
+	Restarts int32 `json:"restarts,omitempty"`
+
+	// LastTerminationReason is the reason of the most recent container termination, e.g. OOMKilled or Error
+	// +optional
+	LastTerminationReason string `json:"lastTerminationReason,omitempty"`
+
+	// LastTerminationMessage is the message of the most recent container termination
+	// +optional
+	LastTerminationMessage string `json:"lastTerminationMessage,omitempty"`
+
+	// LastTerminationExitCode is the exit code of the most recent container termination
+	// +optional
+	LastTerminationExitCode *int32 `json:"lastTerminationExitCode,omitempty"`
+}
+
+// Component is a resource the operator manages for an MCP server.
+type Component struct {
+	// Kind is the kind of the resource, e.g. Deployment
+	Kind string `json:"kind"`
+
+	// Name is the name of the resource in the namespace of the MCPServer
+	Name string `json:"name"`
+}
+
+// AutoscalerStatus reports the HorizontalPodAutoscaler of an MCP server.
+type AutoscalerStatus struct {
+	// Name is the name of the HorizontalPodAutoscaler
+	Name string `json:"name"`
+
+	// Managed is true for the autoscaler of spec.autoscaling, and false for one created by hand
+	// +optional
+	Managed bool `json:"managed,omitempty"`
+
+	// MinReplicas and MaxReplicas are the bounds the autoscaler scales the MCP server within
+	// +optional
+	MinReplicas int32 `json:"minReplicas,omitempty"`
+	// +optional
+	MaxReplicas int32 `json:"maxReplicas,omitempty"`
+
+	// CurrentReplicas is the number of pods the autoscaler last saw
+	// +optional
+	CurrentReplicas int32 `json:"currentReplicas,omitempty"`
+
+	// DesiredReplicas is the number of pods the autoscaler last scaled the MCP server to
+	// +optional
+	DesiredReplicas int32 `json:"desiredReplicas,omitempty"`
+}
+
+// EndpointType is how an endpoint of an MCP server is reached.
+// +kubebuilder:validation:Enum=Service;Route;Gateway;MeshGateway;Ingress;SharedHost;External
+type EndpointType string
+
+const (
+	// EndpointService is the Service of the MCP server, only reachable from inside the cluster.
+	EndpointService EndpointType = "Service"
+	// EndpointRoute is the OpenShift Route of the MCP server.
+	EndpointRoute EndpointType = "Route"
+	// EndpointGateway is a listener of the Gateway the MCP server is attached to with gatewayRef.
+	EndpointGateway EndpointType = "Gateway"
+	// EndpointMeshGateway is the path of the MCP server on the mesh gateway host of meshGateway.
+	EndpointMeshGateway EndpointType = "MeshGateway"
+	// EndpointIngress is an Ingress of spec.exposures.
+	EndpointIngress EndpointType = "Ingress"
+	// EndpointSharedHost is the path of the MCP server on the shared host of the operator.
+	EndpointSharedHost EndpointType = "SharedHost"
+	// EndpointExternal is the URL of an External MCP server.
+	EndpointExternal EndpointType = "External"
+)
+
+// Transport is the MCP transport an endpoint speaks.
+// +kubebuilder:validation:Enum=SSE
+type Transport string
+
+const (
+	// TransportSSE is the HTTP with Server-Sent Events transport.
+	TransportSSE Transport = "SSE"
+)
+
+// Endpoint is a way to reach an MCP server.
+type Endpoint struct {
+	// Type is how the endpoint is reached. Service endpoints are cluster-internal, all others are reachable from
+	// outside the cluster.
+	Type EndpointType `json:"type"`
+
+	// URL of the endpoint
+	URL string `json:"url"`
+
+	// Scheme of the URL, http or https
+	Scheme string `json:"scheme"`
+
+	// Host of the URL, including the port when it is not the default one of the scheme
+	Host string `json:"host"`
+
+	// Path of the URL
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Transport is the MCP transport served at the URL
+	Transport Transport `json:"transport"`
+}
+
+// Revision records a spec of the MCP server that was rolled out successfully.
+type Revision struct {
+	// Revision numbers the rolled out specs of the MCP server, starting at 1
+	Revision int64 `json:"revision"`
+
+	// Image of the MCP server
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Command of the MCP server, unset for the default command
+	// +optional
+	Command []string `json:"command,omitempty"`
+
+	// Args of the MCP server, unset for the default args
+	// +optional
+	Args []string `json:"args,omitempty"`
+
+	// Config of the MCP server
+	// +optional
+	Config map[string]apiextensionsv1.JSON `json:"config,omitempty"`
+
+	// ConfigHash identifies the config of the revision, so that revisions with the same config are told apart
+	// at a glance
+	// +optional
+	ConfigHash string `json:"configHash,omitempty"`
+
+	// ObservedGeneration is the generation of the MCPServer that was rolled out
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// RolloutTime is the time the rollout of the revision was observed to be complete
+	RolloutTime metav1.Time `json:"rolloutTime"`
 }
 
 // MCPServerStatus defines the observed state of MCPServer.
@@ -41,12 +1421,135 @@ type MCPServerStatus struct {
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 
+	// ConnectionTest reports the result of the last connection test, if one was requested
+	// +optional
+	ConnectionTest *ConnectionTestStatus `json:"connectionTest,omitempty"`
+
+	// ConformanceCheck reports the result of the last conformance suite, if one was requested
+	// +optional
+	ConformanceCheck *ConformanceCheckStatus `json:"conformanceCheck,omitempty"`
+
+	// Tools lists the tools offered by the MCP server, as last listed by the operator
+	// +optional
+	Tools []Tool `json:"tools,omitempty"`
+
+	// ToolsRefresh reports when the tools of the MCP server were last listed
+	// +optional
+	ToolsRefresh *ToolsRefreshStatus `json:"toolsRefresh,omitempty"`
+
+	// Usage summarizes the traffic of the MCP server, as last scraped from its metrics exporter
+	// +optional
+	Usage *UsageStatus `json:"usage,omitempty"`
+
+	// PodSummary aggregates the readiness, restarts and terminations of the MCP server pods
+	// +optional
+	PodSummary *PodSummary `json:"podSummary,omitempty"`
+
+	// URL is the endpoint clients connect to: the Route, or the Service of a Managed MCP server, or the URL of
+	// an External one
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// ImageDigest reports the digest the image of the MCP server is pinned to with spec.pinImageDigest
+	// +optional
+	ImageDigest *ImageDigestStatus `json:"imageDigest,omitempty"`
+
+	// Endpoints lists every way to reach the MCP server: the URL of an External server, or the cluster-internal
+	// Service URL of a Managed or Proxy server followed by the URLs of its exposures and of its Route, Gateway or
+	// mesh gateway host once they are known. URL is the last of them.
+	// +listType=atomic
+	// +optional
+	Endpoints []Endpoint `json:"endpoints,omitempty"`
+
+	// RESTBridgeURL is the base URL of the REST API of the tools when spec.restBridge is enabled: the Route of the
+	// bridge once it is admitted, the cluster-internal URL of its Service otherwise.
+	// +optional
+	RESTBridgeURL string `json:"restBridgeURL,omitempty"`
+
+	// DisplayName is the name of the MCP server shown in catalogs: spec.displayName, the
+	// openshift.io/display-name annotation or the name of the MCPServer
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
+
+	// Description is spec.description, or the openshift.io/description annotation
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Platform is the platform the MCP server runs on, either OpenShift or Kubernetes. Routes are
+	// only created on OpenShift.
+	// +optional
+	Platform string `json:"platform,omitempty"`
+
+	// Replicas is the number of pods of the MCP server Deployment
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// ReadyReplicas is the number of ready pods of the MCP server Deployment
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
+	// Components lists the resources the operator manages for the MCP server and their names. They are named
+	// after the MCPServer, except that names longer than 63 characters are shortened with a hash
+	// +listType=atomic
+	// +optional
+	Components []Component `json:"components,omitempty"`
+
+	// Autoscaler reports the HorizontalPodAutoscaler that scales the MCP server Deployment, either the one of
+	// spec.autoscaling or one created by hand
+	// +optional
+	Autoscaler *AutoscalerStatus `json:"autoscaler,omitempty"`
+
+	// Revisions lists the last specs of a Managed MCP server that rolled out successfully, oldest first. A
+	// revision is recorded whenever the image, command, args or config of a completed rollout differ from
+	// the latest one.
+	// +listType=atomic
+	// +optional
+	Revisions []Revision `json:"revisions,omitempty"`
+
+	// ExpirationTime is when the operator deletes the MCPServer according to spec.ttlSecondsAfterCreation and
+	// spec.ttlSecondsAfterLastActivity
+	// +optional
+	ExpirationTime *metav1.Time `json:"expirationTime,omitempty"`
+
+	// Shard is the shard of the operator replica that reconciles the MCPServer, when the operator runs with
+	// several shards.
+	// +optional
+	Shard *int32 `json:"shard,omitempty"`
+
+	// LastReadyTime is when the Available condition of the MCP server last turned True
+	// +optional
+	LastReadyTime *metav1.Time `json:"lastReadyTime,omitempty"`
+
+	// LastTransitionSummary describes the last change of the Available condition of the MCP server, e.g.
+	// "Became unavailable at 2025-01-02T15:04:05Z after 3h0m0s available: EndpointUnreachable: ..."
+	// +optional
+	LastTransitionSummary string `json:"lastTransitionSummary,omitempty"`
+
+	// ReadinessFlaps is the number of times the MCP server stopped being available within the last hour, counted
+	// up to 20
+	// +optional
+	ReadinessFlaps int32 `json:"readinessFlaps,omitempty"`
+
+	// ReadinessFlapTimes lists when the MCP server stopped being available within the last hour, oldest first
+	// +listType=atomic
+	// +optional
+	ReadinessFlapTimes []metav1.Time `json:"readinessFlapTimes,omitempty"`
+
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Type",type=string,JSONPath=".spec.type"
+// +kubebuilder:printcolumn:name="Ready",type=integer,JSONPath=".status.readyReplicas"
+// +kubebuilder:printcolumn:name="Replicas",type=integer,JSONPath=".status.replicas"
+// +kubebuilder:printcolumn:name="Display Name",type=string,JSONPath=".status.displayName",priority=1
+// +kubebuilder:printcolumn:name="URL",type=string,JSONPath=".status.url",priority=1
+// +kubebuilder:printcolumn:name="Description",type=string,JSONPath=".status.description",priority=1
+// +kubebuilder:printcolumn:name="Shard",type=integer,JSONPath=".status.shard",priority=1
+// +kubebuilder:printcolumn:name="Flaps",type=integer,JSONPath=".status.readinessFlaps",priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
 
 // MCPServer is the Schema for the mcpservers API.
 type MCPServer struct {
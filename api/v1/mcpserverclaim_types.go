@@ -0,0 +1,108 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClaimLabel is set on the MCPServer of an MCPServerClaim to the name of the claim.
+const ClaimLabel = "mcpserver.opendatahub.io/claim"
+
+// MCPServerClaimSpec defines the desired state of MCPServerClaim.
+type MCPServerClaimSpec struct {
+	// TemplateName is the name of the MCPServer labeled as a template, in the namespace of the claim, that the
+	// instance is created from.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="templateName is immutable"
+	TemplateName string `json:"templateName"`
+
+	// User is the name of the Kubernetes user the instance is for. Only this user is granted access to the
+	// instance and to the token it authenticates its clients with.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="user is immutable"
+	User string `json:"user"`
+
+	// CredentialsSecretRef selects the credentials of the user, which replace spec.credentialsSecretRef of the
+	// template in the instance.
+	// +optional
+	CredentialsSecretRef *corev1.SecretKeySelector `json:"credentialsSecretRef,omitempty"`
+
+	// Lifetime is how long after its creation the claim and its instance are deleted. They are kept until the
+	// claim is deleted when unset.
+	// +optional
+	Lifetime *metav1.Duration `json:"lifetime,omitempty"`
+}
+
+// MCPServerClaimStatus defines the observed state of MCPServerClaim.
+type MCPServerClaimStatus struct {
+	// MCPServer is the name of the instance
+	// +optional
+	MCPServer string `json:"mcpServer,omitempty"`
+
+	// URL is the URL of the instance
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// TokenSecretName is the name of the Secret that holds the token, under the key token, that the user must
+	// present to the instance
+	// +optional
+	TokenSecretName string `json:"tokenSecretName,omitempty"`
+
+	// ExpirationTime is when the claim and its instance are deleted
+	// +optional
+	ExpirationTime *metav1.Time `json:"expirationTime,omitempty"`
+
+	// Conditions reports whether the instance is available
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Template",type=string,JSONPath=".spec.templateName"
+// +kubebuilder:printcolumn:name="User",type=string,JSONPath=".spec.user"
+// +kubebuilder:printcolumn:name="URL",type=string,JSONPath=".status.url"
+// +kubebuilder:printcolumn:name="Available",type=string,JSONPath=".status.conditions[?(@.type==\"Available\")].status"
+// +kubebuilder:printcolumn:name="Expires",type=date,JSONPath=".status.expirationTime"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+
+// MCPServerClaim is the Schema for the mcpserverclaims API. It requests a short-lived instance of an MCPServer
+// template for one user, with credentials of its own that no other user can read.
+type MCPServerClaim struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MCPServerClaimSpec   `json:"spec,omitempty"`
+	Status MCPServerClaimStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MCPServerClaimList contains a list of MCPServerClaim.
+type MCPServerClaimList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MCPServerClaim `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MCPServerClaim{}, &MCPServerClaimList{})
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MCPServerDefaultsName is the name of the MCPServerDefaults of a namespace. There is at most one per namespace.
+const MCPServerDefaultsName = "default"
+
+// MCPServerDefaultsSpec defines the defaults of the MCPServers in a namespace.
+type MCPServerDefaultsSpec struct {
+	// ResourcesPreset is the resources preset of MCPServers in the namespace that do not select one. The
+	// presets themselves are defined in the operator configuration.
+	// +optional
+	ResourcesPreset ResourcesPreset `json:"resourcesPreset,omitempty"`
+
+	// AllowedTypes restricts the types of MCPServers in the namespace, e.g. to forbid External servers.
+	// All types are allowed when empty.
+	// +listType=set
+	// +optional
+	AllowedTypes []MCPServerType `json:"allowedTypes,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:validation:XValidation:rule="self.metadata.name == 'default'",message="the MCPServerDefaults of a namespace must be named default"
+
+// MCPServerDefaults is the Schema for the mcpserverdefaults API. It sets team-level defaults for the
+// MCPServers in its namespace, such as a Data Science Project.
+type MCPServerDefaults struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec MCPServerDefaultsSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MCPServerDefaultsList contains a list of MCPServerDefaults.
+type MCPServerDefaultsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MCPServerDefaults `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MCPServerDefaults{}, &MCPServerDefaultsList{})
+}
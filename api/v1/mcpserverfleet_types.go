@@ -0,0 +1,119 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MCPServerFleetName is the name of the MCPServerFleet. There is only one per cluster.
+const MCPServerFleetName = "cluster"
+
+// ConditionCount counts the MCPServers by the status of one of their conditions.
+type ConditionCount struct {
+	// Type is the type of the condition, e.g. Available
+	Type string `json:"type"`
+
+	// True is the number of MCPServers whose condition is True
+	True int32 `json:"true"`
+
+	// False is the number of MCPServers whose condition is False
+	False int32 `json:"false"`
+
+	// Unknown is the number of MCPServers whose condition is Unknown or not set yet
+	Unknown int32 `json:"unknown"`
+}
+
+// ReasonCount counts the MCPServers that are unhealthy for a reason.
+type ReasonCount struct {
+	// Reason is the reason of the condition that makes the MCPServers unhealthy, e.g. ImagePullFailed
+	Reason string `json:"reason"`
+
+	// Count is the number of MCPServers that are unhealthy for the reason
+	Count int32 `json:"count"`
+}
+
+// MCPServerFleetStatus summarizes the health of all MCPServers of the cluster.
+type MCPServerFleetStatus struct {
+	// OperatorVersion is the version of the operator that wrote the status
+	// +optional
+	OperatorVersion string `json:"operatorVersion,omitempty"`
+
+	// Total is the number of MCPServers in the cluster
+	// +optional
+	Total int32 `json:"total"`
+
+	// Ready is the number of MCPServers whose Available condition is True
+	// +optional
+	Ready int32 `json:"ready"`
+
+	// Degraded is the number of MCPServers that are not available or whose Degraded condition is True
+	// +optional
+	Degraded int32 `json:"degraded"`
+
+	// ConditionCounts counts the MCPServers by the status of each condition type they report
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	ConditionCounts []ConditionCount `json:"conditionCounts,omitempty"`
+
+	// DegradedReasons counts the degraded MCPServers by the reason of their Degraded condition, or of their
+	// Available condition if they are not degraded but unavailable
+	// +listType=map
+	// +listMapKey=reason
+	// +optional
+	DegradedReasons []ReasonCount `json:"degradedReasons,omitempty"`
+
+	// Conditions are Available, True while the operator keeps the summary current, and Degraded, True when any
+	// MCPServer is degraded
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:validation:XValidation:rule="self.metadata.name == 'cluster'",message="the MCPServerFleet must be named cluster"
+// +kubebuilder:printcolumn:name="Total",type=integer,JSONPath=".status.total"
+// +kubebuilder:printcolumn:name="Ready",type=integer,JSONPath=".status.ready"
+// +kubebuilder:printcolumn:name="Degraded",type=integer,JSONPath=".status.degraded"
+// +kubebuilder:printcolumn:name="Version",type=string,JSONPath=".status.operatorVersion"
+
+// MCPServerFleet is the Schema for the mcpserverfleets API. The operator maintains a single MCPServerFleet named
+// cluster that summarizes the health of all MCPServers, for cluster admins and umbrella operators that would
+// otherwise list every MCPServer, much like a ClusterOperator.
+type MCPServerFleet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status MCPServerFleetStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MCPServerFleetList contains a list of MCPServerFleet.
+type MCPServerFleetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MCPServerFleet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MCPServerFleet{}, &MCPServerFleetList{})
+}
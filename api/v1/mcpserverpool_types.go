@@ -0,0 +1,107 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PoolLabel is set on the MCPServers of an MCPServerPool to the name of the pool.
+const PoolLabel = "mcpserver.opendatahub.io/pool"
+
+// MCPServerTemplate describes the MCPServers of an MCPServerPool.
+type MCPServerTemplate struct {
+	// Labels are added to the MCPServers of the pool.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Spec is the spec of each MCPServer of the pool.
+	Spec MCPServerSpec `json:"spec"`
+}
+
+// MCPServerPoolSpec defines the desired state of MCPServerPool.
+// +kubebuilder:validation:XValidation:rule="!has(self.template.spec.type) || self.template.spec.type != 'External'",message="the MCPServers of a pool cannot be External"
+// +kubebuilder:validation:XValidation:rule="!has(self.template.spec.auth) || has(self.template.spec.auth.tokenSecretRef)",message="template.spec.auth must set tokenSecretRef, generated tokens would differ between the shards"
+// +kubebuilder:validation:XValidation:rule="!has(self.template.spec.protocol) || self.template.spec.protocol == 'HTTP'",message="the router of a pool only speaks HTTP/1.1, template.spec.protocol must be HTTP"
+type MCPServerPoolSpec struct {
+	// Shards is the number of identical MCPServers the pool runs, named <pool>-0 to <pool>-<shards-1>.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=64
+	Shards int32 `json:"shards"`
+
+	// RouterReplicas is the number of pods of the router that spreads the sessions of the clients across the
+	// shards. The router keeps no state, so any of its pods routes every session. Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RouterReplicas *int32 `json:"routerReplicas,omitempty"`
+
+	// Template describes the MCPServers of the pool. Changing it updates all of them.
+	Template MCPServerTemplate `json:"template"`
+}
+
+// MCPServerPoolStatus defines the observed state of MCPServerPool.
+type MCPServerPoolStatus struct {
+	// Shards is the number of MCPServers of the pool
+	// +optional
+	Shards int32 `json:"shards,omitempty"`
+
+	// ReadyShards is the number of MCPServers of the pool whose Available condition is True
+	// +optional
+	ReadyShards int32 `json:"readyShards,omitempty"`
+
+	// URL is the in-cluster URL of the router of the pool
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// Conditions reports whether the router and the shards are available
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Shards",type=integer,JSONPath=".spec.shards"
+// +kubebuilder:printcolumn:name="Ready",type=integer,JSONPath=".status.readyShards"
+// +kubebuilder:printcolumn:name="URL",type=string,JSONPath=".status.url"
+// +kubebuilder:printcolumn:name="Available",type=string,JSONPath=".status.conditions[?(@.type==\"Available\")].status"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+
+// MCPServerPool is the Schema for the mcpserverpools API. It runs a number of identical MCPServers behind a
+// router that spreads the sessions of the clients across them, for tool backends a single Deployment cannot
+// serve.
+type MCPServerPool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MCPServerPoolSpec   `json:"spec,omitempty"`
+	Status MCPServerPoolStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MCPServerPoolList contains a list of MCPServerPool.
+type MCPServerPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MCPServerPool `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MCPServerPool{}, &MCPServerPoolList{})
+}
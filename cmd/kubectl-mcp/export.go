@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// childKinds are the kinds of the resources the operator creates for an MCPServer. Kinds whose API is not
+// installed on the cluster, such as Routes outside OpenShift, are skipped.
+var childKinds = []schema.GroupVersionKind{
+	{Group: "apps", Version: "v1", Kind: "Deployment"},
+	{Version: "v1", Kind: "Service"},
+	{Version: "v1", Kind: "ConfigMap"},
+	{Version: "v1", Kind: "Secret"},
+	{Group: "batch", Version: "v1", Kind: "Job"},
+	{Group: "route.openshift.io", Version: "v1", Kind: "Route"},
+	{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "HTTPRoute"},
+	{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"},
+}
+
+// redacted replaces the values of the Secrets in a bundle.
+const redacted = "REDACTED"
+
+func newExportCommand(opts *options) *cobra.Command {
+	var dir string
+	cmd := &cobra.Command{
+		Use:   "export NAME",
+		Short: "Export an MCPServer and its generated resources as a bundle for support or migration",
+		Long: `Export writes the MCPServer NAME and the resources the operator generated for it to bundle.yaml, and a
+client configuration for its endpoint to mcp.json, in the directory --dir. The values of Secrets are
+redacted and the fields the API server sets are removed, so that the MCPServer can be applied to another
+cluster; the generated resources are included for reference and recreated there by the operator.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cli, namespace, err := opts.client()
+			if err != nil {
+				return err
+			}
+			if dir == "" {
+				dir = args[0]
+			}
+			bundle, clientConfig, err := export(cmd.Context(), cli, client.ObjectKey{Name: args[0], Namespace: namespace})
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(filepath.Join(dir, "bundle.yaml"), bundle, 0o644); err != nil {
+				return err
+			}
+			if err := os.WriteFile(filepath.Join(dir, "mcp.json"), clientConfig, 0o644); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "mcpserver/%s exported to %s\n", args[0], dir)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&dir, "dir", "", "The directory to write the bundle to. Defaults to the name of the MCPServer.")
+	return cmd
+}
+
+// export returns the YAML bundle of the MCPServer at key and its children, and the client configuration of its
+// endpoint.
+func export(ctx context.Context, cli client.Client, key client.ObjectKey) ([]byte, []byte, error) {
+	mcpServer := &mcpserverv1.MCPServer{}
+	if err := cli.Get(ctx, key, mcpServer); err != nil {
+		return nil, nil, err
+	}
+	mcpServer.SetGroupVersionKind(mcpserverv1.GroupVersion.WithKind("MCPServer"))
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(mcpServer)
+	if err != nil {
+		return nil, nil, err
+	}
+	objects := []*unstructured.Unstructured{{Object: content}}
+
+	for _, gvk := range childKinds {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := cli.List(ctx, list, client.InNamespace(key.Namespace)); err != nil {
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+			return nil, nil, fmt.Errorf("failed to list %ss: %w", gvk.Kind, err)
+		}
+		for i := range list.Items {
+			if metav1.IsControlledBy(&list.Items[i], mcpServer) {
+				objects = append(objects, &list.Items[i])
+			}
+		}
+	}
+
+	var bundle bytes.Buffer
+	for _, object := range objects {
+		sanitize(object)
+		data, err := yaml.Marshal(object.Object)
+		if err != nil {
+			return nil, nil, err
+		}
+		bundle.WriteString("---\n")
+		bundle.Write(data)
+	}
+
+	clientConfig, err := json.MarshalIndent(mcpClientConfig(mcpServer), "", "  ")
+	if err != nil {
+		return nil, nil, err
+	}
+	return bundle.Bytes(), append(clientConfig, '\n'), nil
+}
+
+// sanitize redacts the values of a Secret and removes the fields of object that the API server sets, or that
+// only hold on the cluster it was exported from.
+func sanitize(object *unstructured.Unstructured) {
+	for _, field := range []string{"managedFields", "uid", "resourceVersion", "generation", "creationTimestamp",
+		"ownerReferences", "selfLink"} {
+		unstructured.RemoveNestedField(object.Object, "metadata", field)
+	}
+	unstructured.RemoveNestedField(object.Object, "metadata", "annotations", "kubectl.kubernetes.io/last-applied-configuration")
+
+	switch object.GetKind() {
+	case "Secret":
+		unstructured.RemoveNestedField(object.Object, "stringData")
+		if data, ok, _ := unstructured.NestedMap(object.Object, "data"); ok {
+			for k := range data {
+				data[k] = redacted
+			}
+			_ = unstructured.SetNestedMap(object.Object, data, "data")
+		}
+	case "Service":
+		unstructured.RemoveNestedField(object.Object, "spec", "clusterIP")
+		unstructured.RemoveNestedField(object.Object, "spec", "clusterIPs")
+	case "Route":
+		// The host of the Route is generated from the domain of the cluster.
+		unstructured.RemoveNestedField(object.Object, "spec", "host")
+	}
+}
+
+// mcpClientConfig returns the configuration MCP clients need to connect to the endpoint of mcpServer, in the
+// mcpServers format most of them read, with its display name as title and its description. Credentials are left
+// as a placeholder.
+func mcpClientConfig(mcpServer *mcpserverv1.MCPServer) map[string]any {
+	server := map[string]any{"url": mcpServer.Status.URL}
+	if mcpServer.Status.DisplayName != "" {
+		server["title"] = mcpServer.Status.DisplayName
+	}
+	if mcpServer.Status.Description != "" {
+		server["description"] = mcpServer.Status.Description
+	}
+	if auth := mcpServer.Spec.Auth; auth != nil && auth.Type == mcpserverv1.AuthToken {
+		server["headers"] = map[string]string{"Authorization": "Bearer ${MCP_TOKEN}"}
+	}
+	if mcpServer.Spec.Type == mcpserverv1.MCPServerProxy {
+		server["headers"] = map[string]string{"Authorization": "Bearer ${KUBERNETES_TOKEN}"}
+	}
+	return map[string]any{"mcpServers": map[string]any{mcpServer.Name: server}}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+func newHistoryCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "history NAME",
+		Short: "List the revisions of an MCPServer that rolled out successfully",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cli, namespace, err := opts.client()
+			if err != nil {
+				return err
+			}
+			return history(cmd.Context(), cli, client.ObjectKey{Name: args[0], Namespace: namespace}, cmd.OutOrStdout())
+		},
+	}
+}
+
+// history writes a table of the revisions recorded in the status of the MCPServer, oldest first.
+func history(ctx context.Context, cli client.Client, key client.ObjectKey, out io.Writer) error {
+	mcpServer := &mcpserverv1.MCPServer{}
+	if err := cli.Get(ctx, key, mcpServer); err != nil {
+		return err
+	}
+	if len(mcpServer.Status.Revisions) == 0 {
+		_, err := fmt.Fprintf(out, "No revisions of mcpserver/%s have rolled out yet\n", key.Name)
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "REVISION\tROLLED OUT\tIMAGE\tARGS\tCONFIG")
+	for _, revision := range mcpServer.Status.Revisions {
+		args := "<default>"
+		if revision.Args != nil {
+			args = strings.Join(revision.Args, " ")
+		}
+		config := revision.ConfigHash
+		if config == "" {
+			config = "<none>"
+		}
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", revision.Revision,
+			revision.RolloutTime.UTC().Format(time.RFC3339), revision.Image, args, config)
+	}
+	return w.Flush()
+}
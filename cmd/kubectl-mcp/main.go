@@ -0,0 +1,95 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// kubectl-mcp is a kubectl plugin for day-to-day operations on MCPServers. Install the binary
+// on the PATH and invoke it as "kubectl mcp".
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(mcpserverv1.AddToScheme(scheme))
+}
+
+// options holds the flags shared by all subcommands.
+type options struct {
+	kubeconfig string
+	namespace  string
+}
+
+// client returns a client for the cluster selected by the kubeconfig, and the namespace to operate in.
+func (o *options) client() (client.Client, string, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = o.kubeconfig
+	overrides := &clientcmd.ConfigOverrides{}
+	overrides.Context.Namespace = o.namespace
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides)
+
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return nil, "", err
+	}
+	cfg, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, "", err
+	}
+	cli, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, "", err
+	}
+	return cli, namespace, nil
+}
+
+func main() {
+	opts := &options{}
+
+	root := &cobra.Command{
+		Use:           "kubectl-mcp",
+		Short:         "Manage MCPServers deployed by the MCP Server Operator",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	root.PersistentFlags().StringVar(&opts.kubeconfig, "kubeconfig", "", "Path to the kubeconfig file to use.")
+	root.PersistentFlags().StringVarP(&opts.namespace, "namespace", "n", "", "The namespace of the MCPServers.")
+
+	root.AddCommand(newRestartCommand(opts))
+	root.AddCommand(newSuspendCommand(opts))
+	root.AddCommand(newResumeCommand(opts))
+	root.AddCommand(newSetImageCommand(opts))
+	root.AddCommand(newExportCommand(opts))
+	root.AddCommand(newHistoryCommand(opts))
+	root.AddCommand(newRollbackCommand(opts))
+
+	if err := root.Execute(); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+func newRestartCommand(opts *options) *cobra.Command {
+	targets := &targets{}
+	cmd := &cobra.Command{
+		Use:   "restart (NAME | --selector SELECTOR)",
+		Short: "Trigger a rolling restart of an MCPServer, or of all MCPServers matching a selector",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := targets.validate(args); err != nil {
+				return err
+			}
+			cli, namespace, err := opts.client()
+			if err != nil {
+				return err
+			}
+			keys, err := targets.keys(cmd.Context(), cli, namespace, args)
+			if err != nil {
+				return err
+			}
+			now := time.Now()
+			return targets.forEach(cmd.OutOrStdout(), keys, func(key client.ObjectKey) (string, error) {
+				return "restarted", restart(cmd.Context(), cli, key, now)
+			})
+		},
+	}
+	targets.addFlags(cmd)
+	return cmd
+}
+
+// restart sets the restartedAt annotation of the MCPServer, which the operator copies onto the pod template.
+func restart(ctx context.Context, cli client.Client, key client.ObjectKey, now time.Time) error {
+	mcpServer := &mcpserverv1.MCPServer{}
+	if err := cli.Get(ctx, key, mcpServer); err != nil {
+		return err
+	}
+
+	patch := client.MergeFrom(mcpServer.DeepCopy())
+	if mcpServer.Annotations == nil {
+		mcpServer.Annotations = map[string]string{}
+	}
+	mcpServer.Annotations[mcpserverv1.RestartedAtAnnotation] = now.UTC().Format(time.RFC3339)
+	return cli.Patch(ctx, mcpServer, patch)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+func newRollbackCommand(opts *options) *cobra.Command {
+	var toRevision int64
+	cmd := &cobra.Command{
+		Use:   "rollback NAME",
+		Short: "Roll an MCPServer back to the image, command, args and config of a previous revision",
+		Long: `Rollback sets the image, command, args and config of the MCPServer NAME to those of the revision
+--to-revision, as listed by "kubectl mcp history", or of the revision before the latest one when the
+flag is not set. The operator rolls out the change and records it as a new revision.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cli, namespace, err := opts.client()
+			if err != nil {
+				return err
+			}
+			revision, err := rollback(cmd.Context(), cli, client.ObjectKey{Name: args[0], Namespace: namespace}, toRevision)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "mcpserver/%s rolled back to revision %d\n", args[0], revision)
+			return nil
+		},
+	}
+	cmd.Flags().Int64Var(&toRevision, "to-revision", 0, "The revision to roll back to. Defaults to the revision before the latest one.")
+	return cmd
+}
+
+// rollback copies the spec recorded in a revision of the MCPServer back into its spec and returns the number of
+// the revision.
+func rollback(ctx context.Context, cli client.Client, key client.ObjectKey, toRevision int64) (int64, error) {
+	mcpServer := &mcpserverv1.MCPServer{}
+	if err := cli.Get(ctx, key, mcpServer); err != nil {
+		return 0, err
+	}
+
+	revisions := mcpServer.Status.Revisions
+	var target *mcpserverv1.Revision
+	switch {
+	case toRevision == 0 && len(revisions) < 2:
+		return 0, fmt.Errorf("mcpserver/%s has no previous revision to roll back to", key.Name)
+	case toRevision == 0:
+		target = &revisions[len(revisions)-2]
+	default:
+		for i := range revisions {
+			if revisions[i].Revision == toRevision {
+				target = &revisions[i]
+			}
+		}
+		if target == nil {
+			return 0, fmt.Errorf("revision %d of mcpserver/%s not found, see kubectl mcp history %s", toRevision, key.Name, key.Name)
+		}
+	}
+
+	patch := client.MergeFrom(mcpServer.DeepCopy())
+	mcpServer.Spec.Image = target.Image
+	mcpServer.Spec.Command = target.Command
+	mcpServer.Spec.Args = target.Args
+	mcpServer.Spec.Config = target.Config
+	return target.Revision, cli.Patch(ctx, mcpServer, patch)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// targets selects the MCPServers a bulk subcommand operates on: the one named by its argument, or all those
+// matching a label selector.
+type targets struct {
+	selector      string
+	allNamespaces bool
+}
+
+// addFlags adds the --selector and --all-namespaces flags to cmd.
+func (t *targets) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&t.selector, "selector", "l", "",
+		"Operate on the MCPServers matching this label selector, e.g. team=search, instead of NAME.")
+	cmd.Flags().BoolVarP(&t.allNamespaces, "all-namespaces", "A", false,
+		"Operate on the MCPServers matching --selector in all namespaces.")
+}
+
+// validate checks that names holds a single name or that a selector is set, but not both.
+func (t *targets) validate(names []string) error {
+	switch {
+	case t.selector != "" && len(names) > 0:
+		return errors.New("a NAME cannot be combined with --selector")
+	case t.selector == "" && len(names) != 1:
+		return errors.New("exactly one NAME or --selector is required")
+	case t.allNamespaces && t.selector == "":
+		return errors.New("--all-namespaces requires --selector")
+	}
+	return nil
+}
+
+// keys returns the keys of the selected MCPServers, in namespace unless all namespaces are selected. names must
+// have passed validate.
+func (t *targets) keys(ctx context.Context, cli client.Client, namespace string,
+	names []string) ([]client.ObjectKey, error) {
+	if t.selector == "" {
+		return []client.ObjectKey{{Name: names[0], Namespace: namespace}}, nil
+	}
+
+	selector, err := labels.Parse(t.selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --selector: %w", err)
+	}
+	opts := []client.ListOption{client.MatchingLabelsSelector{Selector: selector}}
+	if !t.allNamespaces {
+		opts = append(opts, client.InNamespace(namespace))
+	}
+	list := &mcpserverv1.MCPServerList{}
+	if err := cli.List(ctx, list, opts...); err != nil {
+		return nil, err
+	}
+	keys := make([]client.ObjectKey, 0, len(list.Items))
+	for i := range list.Items {
+		keys = append(keys, client.ObjectKeyFromObject(&list.Items[i]))
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no MCPServers match %s", t.selector)
+	}
+	return keys, nil
+}
+
+// forEach calls apply for each of keys and writes its outcome, such as "restarted", to out. With a selector, a
+// failure does not stop the others, so that one broken MCPServer does not hold up fleet-wide maintenance; an
+// error counting the failures is returned at the end.
+func (t *targets) forEach(out io.Writer, keys []client.ObjectKey, apply func(client.ObjectKey) (string, error)) error {
+	failed := 0
+	for _, key := range keys {
+		name := "mcpserver/" + key.Name
+		if t.allNamespaces {
+			name = fmt.Sprintf("%s in namespace %s", name, key.Namespace)
+		}
+		outcome, err := apply(key)
+		if err != nil && t.selector == "" {
+			return err
+		}
+		if err != nil {
+			failed++
+			_, _ = fmt.Fprintf(out, "%s failed: %v\n", name, err)
+			continue
+		}
+		_, _ = fmt.Fprintf(out, "%s %s\n", name, outcome)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d MCPServers failed", failed, len(keys))
+	}
+	return nil
+}
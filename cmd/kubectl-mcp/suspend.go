@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+func newSuspendCommand(opts *options) *cobra.Command {
+	targets := &targets{}
+	cmd := &cobra.Command{
+		Use:   "suspend (NAME | --selector SELECTOR)",
+		Short: "Scale an MCPServer, or all MCPServers matching a selector, to zero pods",
+		Long: `Suspend sets spec.replicas of the MCPServer to 0 and records the number of pods it ran in the
+mcpserver.opendatahub.io/suspended-replicas annotation, for "kubectl mcp resume" to scale it back to.
+The Service, Route and other resources of the MCPServer are kept. External MCPServers have no pods and
+are skipped with an error.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := targets.validate(args); err != nil {
+				return err
+			}
+			cli, namespace, err := opts.client()
+			if err != nil {
+				return err
+			}
+			keys, err := targets.keys(cmd.Context(), cli, namespace, args)
+			if err != nil {
+				return err
+			}
+			return targets.forEach(cmd.OutOrStdout(), keys, func(key client.ObjectKey) (string, error) {
+				return suspend(cmd.Context(), cli, key)
+			})
+		},
+	}
+	targets.addFlags(cmd)
+	return cmd
+}
+
+func newResumeCommand(opts *options) *cobra.Command {
+	targets := &targets{}
+	cmd := &cobra.Command{
+		Use:   "resume (NAME | --selector SELECTOR)",
+		Short: "Scale a suspended MCPServer, or all MCPServers matching a selector, back up",
+		Long: `Resume sets spec.replicas of an MCPServer suspended with "kubectl mcp suspend" to the number of pods
+it ran before, and removes the mcpserver.opendatahub.io/suspended-replicas annotation. MCPServers that are
+not suspended are left alone.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := targets.validate(args); err != nil {
+				return err
+			}
+			cli, namespace, err := opts.client()
+			if err != nil {
+				return err
+			}
+			keys, err := targets.keys(cmd.Context(), cli, namespace, args)
+			if err != nil {
+				return err
+			}
+			return targets.forEach(cmd.OutOrStdout(), keys, func(key client.ObjectKey) (string, error) {
+				return resume(cmd.Context(), cli, key)
+			})
+		},
+	}
+	targets.addFlags(cmd)
+	return cmd
+}
+
+// suspend scales the MCPServer to zero pods and records the number of pods it ran. The number is taken from
+// spec.replicas, or from its Deployment when spec.replicas is unset.
+func suspend(ctx context.Context, cli client.Client, key client.ObjectKey) (string, error) {
+	mcpServer := &mcpserverv1.MCPServer{}
+	if err := cli.Get(ctx, key, mcpServer); err != nil {
+		return "", err
+	}
+	if mcpServer.Spec.Type == mcpserverv1.MCPServerExternal {
+		return "", fmt.Errorf("mcpserver/%s is External and has no pods to suspend", key.Name)
+	}
+	if _, ok := mcpServer.Annotations[mcpserverv1.SuspendedReplicasAnnotation]; ok {
+		return "already suspended", nil
+	}
+
+	replicas := int32(1)
+	if mcpServer.Spec.Replicas != nil {
+		replicas = *mcpServer.Spec.Replicas
+	} else {
+		deployment := &appsv1.Deployment{}
+		err := cli.Get(ctx, deploymentKey(mcpServer), deployment)
+		if err != nil && !k8serr.IsNotFound(err) {
+			return "", err
+		}
+		if err == nil && deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+	}
+
+	patch := client.MergeFrom(mcpServer.DeepCopy())
+	if mcpServer.Annotations == nil {
+		mcpServer.Annotations = map[string]string{}
+	}
+	mcpServer.Annotations[mcpserverv1.SuspendedReplicasAnnotation] = strconv.Itoa(int(replicas))
+	mcpServer.Spec.Replicas = ptr.To(int32(0))
+	if err := cli.Patch(ctx, mcpServer, patch); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("suspended, %d replicas recorded", replicas), nil
+}
+
+// resume scales a suspended MCPServer back to the number of pods recorded by suspend.
+func resume(ctx context.Context, cli client.Client, key client.ObjectKey) (string, error) {
+	mcpServer := &mcpserverv1.MCPServer{}
+	if err := cli.Get(ctx, key, mcpServer); err != nil {
+		return "", err
+	}
+	value, ok := mcpServer.Annotations[mcpserverv1.SuspendedReplicasAnnotation]
+	if !ok {
+		return "not suspended", nil
+	}
+	replicas, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || replicas < 0 {
+		return "", fmt.Errorf("invalid %s annotation %q", mcpserverv1.SuspendedReplicasAnnotation, value)
+	}
+
+	patch := client.MergeFrom(mcpServer.DeepCopy())
+	delete(mcpServer.Annotations, mcpserverv1.SuspendedReplicasAnnotation)
+	mcpServer.Spec.Replicas = ptr.To(int32(replicas))
+	if err := cli.Patch(ctx, mcpServer, patch); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("resumed with %d replicas", replicas), nil
+}
+
+// deploymentKey returns the key of the Deployment of the MCPServer. It is listed in status.components, as it has
+// a shortened name when the name of the MCPServer is too long; it is named after the MCPServer otherwise.
+func deploymentKey(mcpServer *mcpserverv1.MCPServer) client.ObjectKey {
+	key := client.ObjectKeyFromObject(mcpServer)
+	for _, component := range mcpServer.Status.Components {
+		if component.Kind == "Deployment" {
+			key.Name = component.Name
+			break
+		}
+	}
+	return key
+}
@@ -17,36 +17,91 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	configv1 "github.com/openshift/api/config/v1"
+	consolev1 "github.com/openshift/api/console/v1"
 	routev1 "github.com/openshift/api/route/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/internal/accessreview"
+	"github.com/opendatahub-io/mcp-server-operator/internal/apiclient"
+	"github.com/opendatahub-io/mcp-server-operator/internal/capabilityfilter"
+	"github.com/opendatahub-io/mcp-server-operator/internal/conformance"
+	"github.com/opendatahub-io/mcp-server-operator/internal/connectiontest"
+	"github.com/opendatahub-io/mcp-server-operator/internal/consoleplugin"
 	"github.com/opendatahub-io/mcp-server-operator/internal/controller"
+	mcpdiscovery "github.com/opendatahub-io/mcp-server-operator/internal/discovery"
+	"github.com/opendatahub-io/mcp-server-operator/internal/guardrails"
+	"github.com/opendatahub-io/mcp-server-operator/internal/metricsexporter"
+	"github.com/opendatahub-io/mcp-server-operator/internal/poolrouter"
+	"github.com/opendatahub-io/mcp-server-operator/internal/preflight"
+	"github.com/opendatahub-io/mcp-server-operator/internal/proxy"
+	"github.com/opendatahub-io/mcp-server-operator/internal/ratelimiter"
+	"github.com/opendatahub-io/mcp-server-operator/internal/restapi"
+	"github.com/opendatahub-io/mcp-server-operator/internal/restbridge"
+	"github.com/opendatahub-io/mcp-server-operator/internal/sharedhost"
+	"github.com/opendatahub-io/mcp-server-operator/internal/storagemigration"
+	"github.com/opendatahub-io/mcp-server-operator/internal/tokenauth"
+	webhookv1 "github.com/opendatahub-io/mcp-server-operator/internal/webhook/v1"
+	"github.com/opendatahub-io/mcp-server-operator/internal/webhookcert"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster/gvk"
 	// +kubebuilder:scaffold:imports
 )
 
 var (
 	scheme   = runtime.NewScheme()
 	setupLog = ctrl.Log.WithName("setup")
+
+	// version is the version of the operator, set at build time with -ldflags "-X main.version=...".
+	version = "dev"
+)
+
+const (
+	// The names of the webhook resources as rendered by config/default.
+	webhookServiceName                 = "mcp-server-operator-webhook-service"
+	webhookCertSecretName              = "mcp-server-operator-webhook-server-cert"
+	validatingWebhookConfigurationName = "mcp-server-operator-validating-webhook-configuration"
+	mutatingWebhookConfigurationName   = "mcp-server-operator-mutating-webhook-configuration"
+
+	// webhookCertTimeout bounds how long the manager waits for the webhook certificate to be issued.
+	webhookCertTimeout = 2 * time.Minute
+
+	// serviceAccountNamespaceFile holds the namespace the manager runs in.
+	serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
 )
 
 func init() {
@@ -56,12 +111,61 @@ func init() {
 	utilruntime.Must(rbacv1.AddToScheme(scheme))
 	utilruntime.Must(corev1.AddToScheme(scheme))
 	utilruntime.Must(routev1.Install(scheme))
+	utilruntime.Must(consolev1.Install(scheme))
+	utilruntime.Must(configv1.Install(scheme))
 	utilruntime.Must(appsv1.AddToScheme(scheme))
+	utilruntime.Must(batchv1.AddToScheme(scheme))
+	utilruntime.Must(gatewayv1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
 // nolint:gocyclo
 func main() {
+	// The manager binary doubles as the connection test and conformance suite run by MCPServer Jobs, as the
+	// proxy run in front of Proxy MCPServers, as the guardrails filter of MCPServers with guardrails, as the
+	// capability filter of MCPServers that disable capabilities and as the metrics exporter of MCPServers with a
+	// metrics exporter, as the router of MCPServerPools and as the server of the console plugin. Its check
+	// subcommand verifies the prerequisites of the operator in a cluster.
+	if len(os.Args) > 1 && os.Args[1] == connectiontest.Command {
+		os.Exit(connectiontest.Run(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == conformance.Command {
+		os.Exit(conformance.Run(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == proxy.Command {
+		os.Exit(proxy.Run(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == guardrails.Command {
+		os.Exit(guardrails.Run(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == capabilityfilter.Command {
+		os.Exit(capabilityfilter.Run(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == metricsexporter.Command {
+		os.Exit(metricsexporter.Run(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == ratelimiter.Command {
+		os.Exit(ratelimiter.Run(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == restbridge.Command {
+		os.Exit(restbridge.Run(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == tokenauth.Command {
+		os.Exit(tokenauth.Run(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == poolrouter.Command {
+		os.Exit(poolrouter.Run(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == sharedhost.Command {
+		os.Exit(sharedhost.Run(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == consoleplugin.Command {
+		os.Exit(consoleplugin.Run(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == preflight.Command {
+		os.Exit(preflight.Run(os.Args[2:]))
+	}
+
 	var metricsAddr string
 	var metricsCertPath, metricsCertName, metricsCertKey string
 	var webhookCertPath, webhookCertName, webhookCertKey string
@@ -69,6 +173,23 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var watchNamespace string
+	var provisionWebhookCert bool
+	var requeueInterval time.Duration
+	var stallThreshold int
+	var dryRun bool
+	var resourcePresetsFile string
+	var ownerTeamPattern, ownerContactPattern string
+	var discoveryAddr, discoveryCertPath string
+	var enableRESTAPI bool
+	var kubeAPIQPS float64
+	var kubeAPIBurst int
+	var kubeAPITimeout time.Duration
+	var kubeAPIResourceTimeouts string
+	var shards, shardID int
+	var sharedHost, sharedHostGateway string
+	var enableConsolePlugin bool
+	var consolePluginDiscoveryService string
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -81,12 +202,67 @@ func main() {
 	flag.StringVar(&webhookCertPath, "webhook-cert-path", "", "The directory that contains the webhook certificate.")
 	flag.StringVar(&webhookCertName, "webhook-cert-name", "tls.crt", "The name of the webhook certificate file.")
 	flag.StringVar(&webhookCertKey, "webhook-cert-key", "tls.key", "The name of the webhook key file.")
+	flag.BoolVar(&provisionWebhookCert, "provision-webhook-cert", false,
+		"If set, the operator obtains the webhook certificate from the OpenShift service CA, or from cert-manager "+
+			"on other clusters, injects the CA bundle into its webhook configurations and writes the certificate "+
+			"to the webhook certificate directory.")
 	flag.StringVar(&metricsCertPath, "metrics-cert-path", "",
 		"The directory that contains the metrics server certificate.")
 	flag.StringVar(&metricsCertName, "metrics-cert-name", "tls.crt", "The name of the metrics server certificate file.")
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.StringVar(&watchNamespace, "watch-namespace", "",
+		"If set, MCPServers and their resources are only watched in this namespace, "+
+			"which allows the operator to run with namespaced Roles. All namespaces are watched by default.")
+	flag.DurationVar(&requeueInterval, "requeue-interval", controller.DefaultRequeueInterval,
+		"How often the endpoint of an MCPServer is probed while it is not reachable. Can be overridden per "+
+			"MCPServer with spec.requeueInterval.")
+	flag.IntVar(&stallThreshold, "stall-threshold", controller.DefaultStallThreshold,
+		"The number of consecutive failed reconciliations after which an MCPServer gets the ReconcileStalled "+
+			"condition and is only retried after an exponential cool-down.")
+	flag.BoolVar(&dryRun, "dry-run", false,
+		"If set, the operator only reports the changes it would make to the resources of MCPServers, in the "+
+			"DriftDetected condition and in events, without applying them.")
+	flag.StringVar(&discoveryAddr, "discovery-bind-address", "0", "The address the discovery API, which lists the "+
+		"ready MCPServers a caller may get, binds to over HTTPS. Leave as 0 to disable the discovery API.")
+	flag.StringVar(&discoveryCertPath, "discovery-cert-path", "",
+		"The directory that contains tls.crt and tls.key of the discovery API. A self-signed certificate is used if unset.")
+	flag.BoolVar(&enableRESTAPI, "enable-rest-api", false, "If set, a REST API to create, list, get and delete "+
+		"MCPServers on behalf of authorized callers is served next to the discovery API.")
+	flag.StringVar(&resourcePresetsFile, "resource-presets-file", "",
+		"A YAML file mapping the resource presets MCPServers select with spec.resourcesPreset to resource "+
+			"requests and limits. Built-in presets are used for presets that are not in the file.")
+	flag.StringVar(&ownerTeamPattern, "owner-team-pattern", "",
+		"A regular expression spec.owner.team of MCPServers must match, e.g. ^team-[a-z]+$. Any team is accepted if unset.")
+	flag.StringVar(&ownerContactPattern, "owner-contact-pattern", "",
+		"A regular expression spec.owner.contact of MCPServers must match when set. Any contact is accepted if unset.")
+	flag.Float64Var(&kubeAPIQPS, "kube-api-qps", apiclient.DefaultQPS,
+		"The number of requests per second the operator sends to the Kubernetes API server.")
+	flag.IntVar(&kubeAPIBurst, "kube-api-burst", apiclient.DefaultBurst,
+		"The number of requests the operator may send to the Kubernetes API server in a burst above --kube-api-qps.")
+	flag.DurationVar(&kubeAPITimeout, "kube-api-request-timeout", 0,
+		"The maximum time a request to the Kubernetes API server may take, watches excepted. Unbounded if 0.")
+	flag.StringVar(&kubeAPIResourceTimeouts, "kube-api-resource-timeouts", "",
+		"Timeouts of the requests on specific resources that override --kube-api-request-timeout, as a "+
+			"comma-separated list of resource=duration, e.g. deployments=10s,routes=5s.")
+	flag.IntVar(&shards, "shards", 1,
+		"The number of shards the MCPServers, MCPServerPools and MCPServerClaims are spread across by a hash of "+
+			"their namespace and name. Each shard is reconciled by its own active replica of the operator.")
+	flag.IntVar(&shardID, "shard-id", -1,
+		"The shard this replica reconciles, from 0 to --shards minus 1. Taken from the ordinal at the end of "+
+			"the hostname if unset, as given to the pods of a StatefulSet.")
+	flag.StringVar(&sharedHost, "shared-host", "",
+		"The host MCPServers with a SharedHost exposure are published under, at /mcp/<namespace>/<name>, through a "+
+			"router in the namespace of the operator. SharedHost exposures are not available if unset.")
+	flag.StringVar(&sharedHostGateway, "shared-host-gateway", "",
+		"The Gateway, as <namespace>/<name>, the HTTPRoute of the shared host attaches to. A Route is created if unset.")
+	flag.BoolVar(&enableConsolePlugin, "enable-console-plugin", false,
+		"If set, an OpenShift console plugin that lists the MCPServers from the discovery API is deployed in the "+
+			"namespace of the operator and registered with a ConsolePlugin.")
+	flag.StringVar(&consolePluginDiscoveryService, "console-plugin-discovery-service",
+		"mcp-server-operator-controller-manager-discovery-service:8444",
+		"The Service of the discovery API in the namespace of the operator, as <name>:<port>, the console plugin reads.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -116,6 +292,26 @@ func main() {
 	// Initial webhook TLS options
 	webhookTLSOpts := tlsOpts
 
+	var webhookCertProvisioner *webhookcert.Provisioner
+	if provisionWebhookCert {
+		if len(webhookCertPath) == 0 {
+			webhookCertPath = filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs")
+		}
+
+		var err error
+		webhookCertProvisioner, err = newWebhookCertProvisioner(webhookCertPath)
+		if err != nil {
+			setupLog.Error(err, "Failed to set up webhook certificate provisioning")
+			os.Exit(1)
+		}
+		setupLog.Info("Provisioning webhook certificate", "openshift", webhookCertProvisioner.OpenShift,
+			"secret", webhookCertProvisioner.SecretName)
+		if err := webhookCertProvisioner.Provision(context.Background(), webhookCertTimeout); err != nil {
+			setupLog.Error(err, "Failed to provision webhook certificate")
+			os.Exit(1)
+		}
+	}
+
 	if len(webhookCertPath) > 0 {
 		setupLog.Info("Initializing webhook certificate watcher using provided certificates",
 			"webhook-cert-path", webhookCertPath, "webhook-cert-name", webhookCertName, "webhook-cert-key", webhookCertKey)
@@ -155,6 +351,9 @@ func main() {
 		// can access the metrics endpoint. The RBAC are configured in 'config/rbac/kustomization.yaml'. More info:
 		// https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.20.4/pkg/metrics/filters#WithAuthenticationAndAuthorization
 		metricsServerOptions.FilterProvider = filters.WithAuthenticationAndAuthorization
+	} else if metricsAddr != "0" {
+		setupLog.Info("WARNING: the metrics endpoint is served over HTTP without authentication or authorization",
+			"metrics-bind-address", metricsAddr)
 	}
 
 	// If the certificate is not specified, controller-runtime will automatically
@@ -184,13 +383,61 @@ func main() {
 		})
 	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	// Pods are only cached for the MCP servers, not for the whole cluster.
+	byObject, err := controller.CacheByObject()
+	if err != nil {
+		setupLog.Error(err, "unable to set up the cache")
+		os.Exit(1)
+	}
+	cacheOptions := cache.Options{ByObject: byObject}
+	// In namespace-scoped mode the cache only lists and watches the operator's own namespace,
+	// so a namespaced Role is sufficient.
+	if watchNamespace != "" {
+		setupLog.Info("Restricting the operator to a single namespace", "namespace", watchNamespace)
+		cacheOptions.DefaultNamespaces = map[string]cache.Config{watchNamespace: {}}
+	}
+
+	resourceTimeouts, err := apiclient.ParseResourceTimeouts(kubeAPIResourceTimeouts)
+	if err != nil {
+		setupLog.Error(err, "invalid --kube-api-resource-timeouts")
+		os.Exit(1)
+	}
+	shard, err := newShard(shards, shardID)
+	if err != nil {
+		setupLog.Error(err, "invalid sharding")
+		os.Exit(1)
+	}
+	leaderElectionID := "9a15ae20.opendatahub.io"
+	if shard != nil {
+		// Each shard elects its own leader, so that the replicas of different shards all run.
+		leaderElectionID = fmt.Sprintf("shard-%d.%s", shard.ID, leaderElectionID)
+		setupLog.Info("Running sharded", "shard", shard.ID, "shards", shard.Count)
+	}
+
+	restConfig := ctrl.GetConfigOrDie()
+	apiclient.Options{
+		QPS:              float32(kubeAPIQPS),
+		Burst:            kubeAPIBurst,
+		Timeout:          kubeAPITimeout,
+		ResourceTimeouts: resourceTimeouts,
+	}.Apply(restConfig)
+
+	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
 		Scheme:                 scheme,
+		Cache:                  cacheOptions,
 		Metrics:                metricsServerOptions,
 		WebhookServer:          webhookServer,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "9a15ae20.opendatahub.io",
+		LeaderElectionID:       leaderElectionID,
+		// Secrets are only read for the few MCPServers that reference one, service accounts by name to copy the
+		// image pull secrets of the default one, quotas only before the Deployment of an MCPServer is created,
+		// and config maps only for the routes of the shared host, so they are not worth caching cluster-wide.
+		Client: client.Options{
+			Cache: &client.CacheOptions{DisableFor: []client.Object{
+				&corev1.Secret{}, &corev1.ServiceAccount{}, &corev1.ResourceQuota{}, &corev1.ConfigMap{},
+			}},
+		},
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -208,13 +455,208 @@ func main() {
 		os.Exit(1)
 	}
 
+	platform, err := cluster.DetectPlatform(context.Background(),
+		discovery.NewDiscoveryClientForConfigOrDie(mgr.GetConfig()), mgr.GetAPIReader())
+	if err != nil {
+		setupLog.Error(err, "unable to detect the platform")
+		os.Exit(1)
+	}
+	setupLog.Info("Detected platform", "platform", platform.Name, "routeAPI", platform.HasAPI(gvk.Route),
+		"ingressDomain", platform.IngressDomain, "proxy", platform.Proxy != nil)
+
+	// The prerequisites are checked once at startup. Failures are logged rather than fatal, as RBAC or CRDs may
+	// still be on their way, e.g. while OLM installs the operator.
+	checkClientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create a client for the prerequisite checks")
+		os.Exit(1)
+	}
+	checker := &preflight.Checker{
+		Platform:      platform,
+		AccessReviews: checkClientset.AuthorizationV1().SelfSubjectAccessReviews(),
+		Namespace:     watchNamespace,
+	}
+	for _, result := range checker.Run(context.Background()).Results {
+		switch result.Status {
+		case preflight.Fail:
+			setupLog.Error(nil, "Prerequisite check failed", "check", result.Name, "message", result.Message)
+		case preflight.Warn:
+			setupLog.Info("Prerequisite check warning", "check", result.Name, "message", result.Message)
+		}
+	}
+
+	// Optional APIs are looked up again when a CRD changes. CRDs are cluster-scoped and cannot be watched
+	// with the namespaced Roles of the namespace-scoped mode, which keeps the initial lookups instead.
+	if watchNamespace == "" {
+		if err := platform.APIs.InvalidateOnCRDEvents(context.Background(), mgr.GetCache()); err != nil {
+			setupLog.Error(err, "unable to watch CustomResourceDefinitions")
+			os.Exit(1)
+		}
+	}
+
+	resourcePresets := controller.DefaultResourcePresets()
+	if resourcePresetsFile != "" {
+		resourcePresets, err = controller.LoadResourcePresets(resourcePresetsFile)
+		if err != nil {
+			setupLog.Error(err, "unable to load resource presets")
+			os.Exit(1)
+		}
+	}
+
+	var ownerFormat controller.OwnerFormat
+	if ownerTeamPattern != "" {
+		if ownerFormat.Team, err = regexp.Compile(ownerTeamPattern); err != nil {
+			setupLog.Error(err, "invalid --owner-team-pattern")
+			os.Exit(1)
+		}
+	}
+	if ownerContactPattern != "" {
+		if ownerFormat.Contact, err = regexp.Compile(ownerContactPattern); err != nil {
+			setupLog.Error(err, "invalid --owner-contact-pattern")
+			os.Exit(1)
+		}
+	}
+
+	// The namespace is unknown when the manager runs outside the cluster, e.g. with make run.
+	var operatorNamespace string
+	if namespace, err := os.ReadFile(serviceAccountNamespaceFile); err == nil {
+		operatorNamespace = strings.TrimSpace(string(namespace))
+	}
+
 	if err = (&controller.MCPServerReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:            mgr.GetClient(),
+		APIReader:         mgr.GetAPIReader(),
+		Scheme:            mgr.GetScheme(),
+		OperatorImage:     os.Getenv("OPERATOR_IMAGE"),
+		OperatorNamespace: operatorNamespace,
+		SharedHost:        sharedHost,
+		SessionStoreImage: os.Getenv("SESSION_STORE_IMAGE"),
+		Recorder:          mgr.GetEventRecorderFor("mcpserver-controller"),
+		Platform:          platform,
+		RequeueInterval:   requeueInterval,
+		StallThreshold:    stallThreshold,
+		DryRun:            dryRun,
+		ResourcePresets:   resourcePresets,
+		OwnerFormat:       ownerFormat,
+		Shard:             shard,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "MCPServer")
 		os.Exit(1)
 	}
+	// Only MCPServers are reconciled in dry-run mode. The pools, claims, shared host and console plugin would write
+	// their resources directly, so their controllers are not started.
+	if dryRun {
+		setupLog.Info("Dry-run mode, not reconciling MCPServerPools, MCPServerClaims, the shared host and the console plugin")
+	} else {
+		if err = (&controller.MCPServerPoolReconciler{
+			Client:        mgr.GetClient(),
+			Scheme:        mgr.GetScheme(),
+			OperatorImage: os.Getenv("OPERATOR_IMAGE"),
+			Shard:         shard,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "MCPServerPool")
+			os.Exit(1)
+		}
+		if err = (&controller.MCPServerClaimReconciler{
+			Client: mgr.GetClient(),
+			Scheme: mgr.GetScheme(),
+			Shard:  shard,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "MCPServerClaim")
+			os.Exit(1)
+		}
+	}
+	// The fleet metrics and status cover the MCPServers of all shards, so they are left to the first shard.
+	if shard == nil || shard.ID == 0 {
+		metrics.Registry.MustRegister(&controller.FleetCollector{Reader: mgr.GetClient()})
+	}
+	// The MCPServerFleet is cluster-scoped, so it is left to an operator with cluster-wide permissions in
+	// namespace-scoped mode.
+	if watchNamespace == "" && (shard == nil || shard.ID == 0) {
+		if err = (&controller.FleetStatusReconciler{
+			Client:          mgr.GetClient(),
+			Scheme:          mgr.GetScheme(),
+			OperatorVersion: version,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "MCPServerFleet")
+			os.Exit(1)
+		}
+	}
+	// The shared host routes to MCPServers of all namespaces and shards from the namespace of the operator.
+	if sharedHost != "" {
+		if watchNamespace != "" || operatorNamespace == "" {
+			setupLog.Error(nil, "--shared-host requires the operator to watch all namespaces from inside the cluster")
+			os.Exit(1)
+		}
+		gateway, err := parseSharedHostGateway(sharedHostGateway)
+		if err != nil {
+			setupLog.Error(err, "invalid --shared-host-gateway")
+			os.Exit(1)
+		}
+		if !dryRun && (shard == nil || shard.ID == 0) {
+			if err = (&controller.SharedHostReconciler{
+				Client:        mgr.GetClient(),
+				Scheme:        mgr.GetScheme(),
+				Host:          sharedHost,
+				Namespace:     operatorNamespace,
+				Gateway:       gateway,
+				OperatorImage: os.Getenv("OPERATOR_IMAGE"),
+				Platform:      platform,
+			}).SetupWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "SharedHost")
+				os.Exit(1)
+			}
+		}
+	}
+	// The console plugin is cluster-scoped and reads the discovery API, which is not served in namespace-scoped mode.
+	if enableConsolePlugin {
+		if watchNamespace != "" || operatorNamespace == "" || discoveryAddr == "0" {
+			setupLog.Error(nil, "--enable-console-plugin requires the operator to watch all namespaces from inside "+
+				"the cluster and to serve the discovery API with --discovery-bind-address")
+			os.Exit(1)
+		}
+		if !platform.HasAPI(gvk.ConsolePlugin) {
+			setupLog.Error(nil, "--enable-console-plugin requires a cluster with the OpenShift console")
+			os.Exit(1)
+		}
+		discoveryService, discoveryPort, err := parseConsolePluginDiscoveryService(consolePluginDiscoveryService)
+		if err != nil {
+			setupLog.Error(err, "invalid --console-plugin-discovery-service")
+			os.Exit(1)
+		}
+		if !dryRun && (shard == nil || shard.ID == 0) {
+			if err = (&controller.ConsolePluginReconciler{
+				Client:           mgr.GetClient(),
+				Scheme:           mgr.GetScheme(),
+				Namespace:        operatorNamespace,
+				OperatorImage:    os.Getenv("OPERATOR_IMAGE"),
+				DiscoveryService: discoveryService,
+				DiscoveryPort:    discoveryPort,
+			}).SetupWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "ConsolePlugin")
+				os.Exit(1)
+			}
+		}
+	}
+	// The MCPServer webhook only returns warnings, the MCPServerClaim webhook rejects claims for other users. They
+	// are served when a webhook certificate is configured or provisioned, as the webhook server cannot start without
+	// one.
+	if len(webhookCertPath) > 0 {
+		if err = webhookv1.SetupMCPServerWebhookWithManager(mgr, platform); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "MCPServer")
+			os.Exit(1)
+		}
+		clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+		if err != nil {
+			setupLog.Error(err, "unable to create a client for the MCPServerClaim webhook")
+			os.Exit(1)
+		}
+		reviewer := &accessreview.Reviewer{Clientset: clientset}
+		if err = webhookv1.SetupMCPServerClaimWebhookWithManager(mgr, reviewer); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "MCPServerClaim")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if metricsCertWatcher != nil {
@@ -233,6 +675,54 @@ func main() {
 		}
 	}
 
+	if webhookCertProvisioner != nil {
+		setupLog.Info("Adding webhook certificate provisioner to manager")
+		if err := mgr.Add(webhookCertProvisioner); err != nil {
+			setupLog.Error(err, "unable to add webhook certificate provisioner to manager")
+			os.Exit(1)
+		}
+	}
+
+	// Stored MCPServers are migrated to the storage version on start. The CRD is cluster-scoped, so this is left
+	// to an operator with cluster-wide permissions in namespace-scoped mode, and to the first shard.
+	if watchNamespace == "" && (shard == nil || shard.ID == 0) {
+		if err := mgr.Add(&storagemigration.Migrator{
+			Reader:  mgr.GetAPIReader(),
+			Client:  mgr.GetClient(),
+			CRDName: "mcpservers." + mcpserverv1.GroupVersion.Group,
+		}); err != nil {
+			setupLog.Error(err, "unable to add storage version migration to manager")
+			os.Exit(1)
+		}
+	}
+
+	if enableRESTAPI && discoveryAddr == "0" {
+		setupLog.Error(nil, "--enable-rest-api requires the discovery API to be served with --discovery-bind-address")
+		os.Exit(1)
+	}
+	if discoveryAddr != "0" {
+		clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+		if err != nil {
+			setupLog.Error(err, "unable to create a client for the discovery API")
+			os.Exit(1)
+		}
+		reviewer := &accessreview.Reviewer{Clientset: clientset}
+		server := &mcpdiscovery.Server{
+			Reader:      mgr.GetClient(),
+			Reviewer:    reviewer,
+			BindAddress: discoveryAddr,
+			CertDir:     discoveryCertPath,
+			TLSOpts:     tlsOpts,
+		}
+		if enableRESTAPI {
+			server.Extensions = append(server.Extensions, &restapi.API{Client: mgr.GetClient(), Reviewer: reviewer})
+		}
+		if err := mgr.Add(server); err != nil {
+			setupLog.Error(err, "unable to add the discovery API to manager")
+			os.Exit(1)
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -242,9 +732,96 @@ func main() {
 		os.Exit(1)
 	}
 
-	setupLog.Info("starting manager")
+	setupLog.Info("starting manager", "version", version)
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
+
+// newWebhookCertProvisioner returns a provisioner for the webhook certificate of the operator, which writes the
+// certificate to certDir. It uses its own uncached client because it runs before the manager is started.
+func newWebhookCertProvisioner(certDir string) (*webhookcert.Provisioner, error) {
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	cli, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, err
+	}
+	dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	openShift, err := cluster.IsOpenShift(dc)
+	if err != nil {
+		return nil, err
+	}
+	namespace, err := os.ReadFile(serviceAccountNamespaceFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to determine the operator namespace: %w", err)
+	}
+
+	return &webhookcert.Provisioner{
+		Client:      cli,
+		Namespace:   strings.TrimSpace(string(namespace)),
+		ServiceName: webhookServiceName,
+		SecretName:  webhookCertSecretName,
+		WebhookConfigurations: []string{
+			validatingWebhookConfigurationName,
+			mutatingWebhookConfigurationName,
+		},
+		CertDir:   certDir,
+		OpenShift: openShift,
+	}, nil
+}
+
+// newShard returns the shard this replica reconciles among shards, nil when the operator runs unsharded. The
+// shard is taken from the hostname when id is negative.
+func newShard(shards, id int) (*controller.Shard, error) {
+	if shards < 1 {
+		return nil, fmt.Errorf("--shards must be at least 1, got %d", shards)
+	}
+	if shards == 1 {
+		return nil, nil
+	}
+	if id < 0 {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, err
+		}
+		if id, err = controller.ShardFromHostname(hostname); err != nil {
+			return nil, fmt.Errorf("--shard-id is unset: %w", err)
+		}
+	}
+	if id >= shards {
+		return nil, fmt.Errorf("shard %d is out of the %d shards", id, shards)
+	}
+	return &controller.Shard{ID: id, Count: shards}, nil
+}
+
+// parseSharedHostGateway returns the Gateway of --shared-host-gateway, nil when it is empty.
+func parseSharedHostGateway(value string) (*types.NamespacedName, error) {
+	if value == "" {
+		return nil, nil
+	}
+	namespace, name, ok := strings.Cut(value, "/")
+	if !ok || namespace == "" || name == "" || strings.Contains(name, "/") {
+		return nil, fmt.Errorf("%q is not of the form <namespace>/<name>", value)
+	}
+	return &types.NamespacedName{Namespace: namespace, Name: name}, nil
+}
+
+// parseConsolePluginDiscoveryService returns the name and port of --console-plugin-discovery-service.
+func parseConsolePluginDiscoveryService(value string) (string, int32, error) {
+	name, port, ok := strings.Cut(value, ":")
+	if !ok || name == "" {
+		return "", 0, fmt.Errorf("%q is not of the form <name>:<port>", value)
+	}
+	number, err := strconv.ParseInt(port, 10, 32)
+	if err != nil || number < 1 || number > 65535 {
+		return "", 0, fmt.Errorf("%q has an invalid port", value)
+	}
+	return name, int32(number), nil
+}
@@ -0,0 +1,79 @@
+// Package accessreview authenticates bearer tokens and authorizes their users with TokenReviews and
+// SubjectAccessReviews, for the HTTP endpoints served by the operator and the proxies it deploys.
+package accessreview
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Reviewer asks the API server about the callers of an endpoint.
+type Reviewer struct {
+	Clientset kubernetes.Interface
+}
+
+// Authenticate returns the user the token belongs to, or nil when the token is not valid.
+func (r *Reviewer) Authenticate(ctx context.Context, token string) (*authenticationv1.UserInfo, error) {
+	review, err := r.Clientset.AuthenticationV1().TokenReviews().Create(ctx, &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if !review.Status.Authenticated {
+		return nil, nil
+	}
+	return &review.Status.User, nil
+}
+
+// Allowed reports whether user may perform the request described by attributes.
+func (r *Reviewer) Allowed(ctx context.Context, user *authenticationv1.UserInfo,
+	attributes authorizationv1.ResourceAttributes) (bool, error) {
+	extra := map[string]authorizationv1.ExtraValue{}
+	for k, v := range user.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+
+	review, err := r.Clientset.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:               user.Username,
+			UID:                user.UID,
+			Groups:             user.Groups,
+			Extra:              extra,
+			ResourceAttributes: &attributes,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	return review.Status.Allowed, nil
+}
+
+// AuthenticateRequest authenticates the bearer token of req. When the caller cannot be authenticated an error
+// response is written to w and nil is returned.
+func (r *Reviewer) AuthenticateRequest(w http.ResponseWriter, req *http.Request) *authenticationv1.UserInfo {
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "a bearer token is required", http.StatusUnauthorized)
+		return nil
+	}
+	user, err := r.Authenticate(req.Context(), token)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("unable to authenticate the request: %v", err), http.StatusServiceUnavailable)
+		return nil
+	}
+	if user == nil {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "invalid bearer token", http.StatusUnauthorized)
+		return nil
+	}
+	return user
+}
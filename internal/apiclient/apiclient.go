@@ -0,0 +1,182 @@
+// Package apiclient tunes the client the operator talks to the Kubernetes API server with: its client-side rate
+// limit, so that large fleets of MCPServers can be reconciled without being throttled by the API server, and the
+// timeouts of its requests. Requests delayed by the client-side rate limit are counted in metrics.
+package apiclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// DefaultQPS is the default number of requests per second to the API server, the default of controller-runtime.
+	DefaultQPS = 20
+	// DefaultBurst is the default number of requests to the API server above the QPS, the default of
+	// controller-runtime.
+	DefaultBurst = 30
+)
+
+var (
+	throttledRequests = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "mcpserver_operator_client_throttled_requests_total",
+		Help: "Number of requests to the Kubernetes API server delayed by the client-side rate limit.",
+	})
+	throttleWait = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "mcpserver_operator_client_throttle_wait_seconds",
+		Help:    "Time requests to the Kubernetes API server delayed by the client-side rate limit waited.",
+		Buckets: []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(throttledRequests, throttleWait)
+}
+
+// Options configure the client of the API server.
+type Options struct {
+	// QPS is the number of requests per second to the API server. DefaultQPS is used when zero.
+	QPS float32
+	// Burst is the number of requests to the API server allowed above QPS. DefaultBurst is used when zero.
+	Burst int
+	// Timeout bounds the requests to the API server. Watches are never bounded. No timeout applies when zero.
+	Timeout time.Duration
+	// ResourceTimeouts override Timeout for the requests on a resource, keyed by its plural name, e.g. deployments.
+	ResourceTimeouts map[string]time.Duration
+}
+
+// Apply configures cfg with o.
+func (o Options) Apply(cfg *rest.Config) {
+	cfg.QPS = o.QPS
+	if cfg.QPS == 0 {
+		cfg.QPS = DefaultQPS
+	}
+	cfg.Burst = o.Burst
+	if cfg.Burst == 0 {
+		cfg.Burst = DefaultBurst
+	}
+	cfg.RateLimiter = &throttleRecorder{RateLimiter: flowcontrol.NewTokenBucketRateLimiter(cfg.QPS, cfg.Burst)}
+	if o.Timeout > 0 || len(o.ResourceTimeouts) > 0 {
+		cfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+			return &timeoutRoundTripper{next: rt, timeout: o.Timeout, resourceTimeouts: o.ResourceTimeouts}
+		})
+	}
+}
+
+// ParseResourceTimeouts parses a comma-separated list of resource=duration pairs, e.g. deployments=10s,routes=5s.
+func ParseResourceTimeouts(s string) (map[string]time.Duration, error) {
+	timeouts := map[string]time.Duration{}
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		resource, value, found := strings.Cut(pair, "=")
+		resource = strings.TrimSpace(resource)
+		if !found || resource == "" {
+			return nil, fmt.Errorf("invalid resource timeout %q, want resource=duration", pair)
+		}
+		timeout, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("invalid timeout of %s %q, want a positive duration", resource, value)
+		}
+		timeouts[resource] = timeout
+	}
+	return timeouts, nil
+}
+
+// throttleRecorder records the requests a rate limiter delays.
+type throttleRecorder struct {
+	flowcontrol.RateLimiter
+}
+
+// Wait implements flowcontrol.RateLimiter.
+func (l *throttleRecorder) Wait(ctx context.Context) error {
+	if l.TryAccept() {
+		return nil
+	}
+	start := time.Now()
+	err := l.RateLimiter.Wait(ctx)
+	throttledRequests.Inc()
+	throttleWait.Observe(time.Since(start).Seconds())
+	return err
+}
+
+// Accept implements flowcontrol.RateLimiter.
+func (l *throttleRecorder) Accept() {
+	if l.TryAccept() {
+		return
+	}
+	start := time.Now()
+	l.RateLimiter.Accept()
+	throttledRequests.Inc()
+	throttleWait.Observe(time.Since(start).Seconds())
+}
+
+// timeoutRoundTripper bounds the requests to the API server that are not watches.
+type timeoutRoundTripper struct {
+	next             http.RoundTripper
+	timeout          time.Duration
+	resourceTimeouts map[string]time.Duration
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *timeoutRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Query().Get("watch") == "true" {
+		return t.next.RoundTrip(req)
+	}
+	timeout, ok := t.resourceTimeouts[resource(req.URL.Path)]
+	if !ok {
+		timeout = t.timeout
+	}
+	if timeout <= 0 {
+		return t.next.RoundTrip(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// The response body is still read after RoundTrip returns, the context is canceled once it is closed.
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelBody cancels the context of its request when closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close implements io.Closer.
+func (b *cancelBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// resource returns the plural name of the resource a request path of the API server refers to, e.g. deployments
+// for /apis/apps/v1/namespaces/team-a/deployments/github, or an empty string for other paths.
+func resource(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case len(segments) >= 3 && segments[0] == "api":
+		segments = segments[2:]
+	case len(segments) >= 4 && segments[0] == "apis":
+		segments = segments[3:]
+	default:
+		return ""
+	}
+	// Namespaced resources are prefixed with namespaces/<namespace>, unless the namespace itself is requested.
+	if len(segments) >= 3 && segments[0] == "namespaces" {
+		segments = segments[2:]
+	}
+	return segments[0]
+}
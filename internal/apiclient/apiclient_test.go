@@ -0,0 +1,136 @@
+package apiclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+func TestParseResourceTimeouts(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    map[string]time.Duration
+		wantErr bool
+	}{
+		{name: "empty", want: map[string]time.Duration{}},
+		{
+			name:  "several resources",
+			value: "deployments=10s, routes=500ms,",
+			want:  map[string]time.Duration{"deployments": 10 * time.Second, "routes": 500 * time.Millisecond},
+		},
+		{name: "missing duration", value: "deployments", wantErr: true},
+		{name: "missing resource", value: "=10s", wantErr: true},
+		{name: "invalid duration", value: "deployments=fast", wantErr: true},
+		{name: "zero duration", value: "deployments=0s", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseResourceTimeouts(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseResourceTimeouts(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseResourceTimeouts(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+			for resource, timeout := range tt.want {
+				if got[resource] != timeout {
+					t.Errorf("ParseResourceTimeouts(%q)[%s] = %v, want %v", tt.value, resource, got[resource], timeout)
+				}
+			}
+		})
+	}
+}
+
+func Test_resource(t *testing.T) {
+	tests := map[string]string{
+		"/api/v1/namespaces/team-a/services/github":                               "services",
+		"/api/v1/namespaces/team-a/pods":                                          "pods",
+		"/api/v1/namespaces/team-a":                                               "namespaces",
+		"/api/v1/namespaces":                                                      "namespaces",
+		"/apis/apps/v1/namespaces/team-a/deployments/github":                      "deployments",
+		"/apis/mcpserver.opendatahub.io/v1/namespaces/team-a/mcpservers/a/status": "mcpservers",
+		"/apis/apiextensions.k8s.io/v1/customresourcedefinitions":                 "customresourcedefinitions",
+		"/apis/apps/v1":  "",
+		"/version":       "",
+		"/healthz/ready": "",
+	}
+	for path, want := range tests {
+		if got := resource(path); got != want {
+			t.Errorf("resource(%s) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestOptions_Apply(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(200 * time.Millisecond):
+			_, _ = w.Write([]byte("{}"))
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := &rest.Config{Host: server.URL}
+	Options{
+		Timeout:          time.Second,
+		ResourceTimeouts: map[string]time.Duration{"deployments": 50 * time.Millisecond},
+	}.Apply(cfg)
+	if cfg.QPS != DefaultQPS || cfg.Burst != DefaultBurst || cfg.RateLimiter == nil {
+		t.Errorf("Apply() = QPS %v, burst %d, rate limiter %v, want the defaults", cfg.QPS, cfg.Burst, cfg.RateLimiter)
+	}
+	httpClient, err := rest.HTTPClientFor(cfg)
+	if err != nil {
+		t.Fatalf("failed to create the HTTP client: %v", err)
+	}
+
+	get := func(path string) error {
+		t.Helper()
+		resp, err := httpClient.Get(server.URL + path)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		return nil
+	}
+	if err := get("/apis/apps/v1/namespaces/team-a/deployments/github"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GET deployment error = %v, want the timeout of deployments", err)
+	}
+	if err := get("/api/v1/namespaces/team-a/services/github"); err != nil {
+		t.Errorf("GET service error = %v, want the default timeout to leave it alone", err)
+	}
+	if err := get("/apis/apps/v1/namespaces/team-a/deployments?watch=true"); err != nil {
+		t.Errorf("watch of deployments error = %v, want watches unbounded", err)
+	}
+}
+
+func TestThrottleRecorder(t *testing.T) {
+	limiter := &throttleRecorder{RateLimiter: flowcontrol.NewTokenBucketRateLimiter(20, 1)}
+	before := testutil.ToFloat64(throttledRequests)
+
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if got := testutil.ToFloat64(throttledRequests) - before; got != 0 {
+		t.Errorf("%v requests throttled within the burst, want 0", got)
+	}
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if got := testutil.ToFloat64(throttledRequests) - before; got != 1 {
+		t.Errorf("%v requests throttled above the burst, want 1", got)
+	}
+}
@@ -0,0 +1,275 @@
+// Package capabilityfilter implements the capability-filter subcommand of the manager binary. It runs as a sidecar
+// in front of MCP servers that disable classes of capabilities in spec.capabilities: the disabled classes are
+// removed from the capabilities the server announces in its initialize result, their list_changed and updated
+// notifications are dropped, and requests to their methods never reach the server as such. Those requests are
+// forwarded with their method renamed, so that the server answers them with a method not found error over
+// whichever transport the client uses, the response of the POST or the event stream of the SSE transport.
+package capabilityfilter
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/opendatahub-io/mcp-server-operator/internal/jsonrpc"
+)
+
+const (
+	// Command is the name of the subcommand.
+	Command = "capability-filter"
+
+	// HealthPath is where the filter reports on its health port that it is running.
+	HealthPath = "/healthz"
+
+	// Tools, Resources and Prompts are the capability classes that can be disabled.
+	Tools     = "tools"
+	Resources = "resources"
+	Prompts   = "prompts"
+
+	// disabledMethodPrefix is prepended to the method of requests to a disabled capability, which no MCP server
+	// implements.
+	disabledMethodPrefix = "disabled/"
+
+	// maxMessageSize caps the size of the JSON-RPC messages that are inspected.
+	maxMessageSize = 10 << 20
+)
+
+// classes are the capability classes that can be disabled.
+var classes = []string{Tools, Resources, Prompts}
+
+// Run starts the filter with the given arguments and returns the process exit code.
+func Run(args []string) int {
+	fs := flag.NewFlagSet(Command, flag.ContinueOnError)
+	upstream := fs.String("upstream", "http://localhost:8000", "The URL of the MCP server the filter runs in front of.")
+	port := fs.Int("port", 8050, "The port the filter listens on.")
+	healthPort := fs.Int("health-port", 8051, "The port the health of the filter is served on.")
+	filter := &Filter{Disabled: map[string]bool{}}
+	fs.Func("disable", "A capability class to disable: tools, resources or prompts, can be repeated.", func(class string) error {
+		if !slices.Contains(classes, class) {
+			return fmt.Errorf("unknown capability class %q, must be one of %s", class, strings.Join(classes, ", "))
+		}
+		filter.Disabled[class] = true
+		return nil
+	})
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if len(filter.Disabled) == 0 {
+		_, _ = fmt.Fprintln(os.Stderr, "at least one --disable is required")
+		return 2
+	}
+	upstreamURL, err := url.Parse(*upstream)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "invalid --upstream: %v\n", err)
+		return 2
+	}
+
+	health := http.NewServeMux()
+	health.HandleFunc("GET "+HealthPath, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprintln(w, "ok")
+	})
+	healthServer := &http.Server{
+		Addr:              fmt.Sprintf(":%d", *healthPort),
+		Handler:           health,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		if err := healthServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			_, _ = fmt.Fprintf(os.Stderr, "health server failed: %v\n", err)
+			os.Exit(1)
+		}
+	}()
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", *port),
+		Handler:           New(upstreamURL, filter),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	_, _ = fmt.Fprintf(os.Stdout, "filtering port %d to %s without %s\n", *port, upstreamURL.Redacted(),
+		strings.Join(filter.disabled(), ", "))
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		_, _ = fmt.Fprintf(os.Stderr, "capability filter failed: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// Filter hides capability classes of an MCP server from its clients.
+type Filter struct {
+	// Disabled holds the disabled capability classes.
+	Disabled map[string]bool
+}
+
+// disabled returns the disabled capability classes in a stable order.
+func (f *Filter) disabled() []string {
+	var disabled []string
+	for _, class := range classes {
+		if f.Disabled[class] {
+			disabled = append(disabled, class)
+		}
+	}
+	return disabled
+}
+
+// New returns a handler that forwards requests to upstream with the methods of disabled capabilities renamed, and
+// removes the disabled capabilities from the responses, whether they are plain JSON or server-sent events.
+func New(upstream *url.URL, filter *Filter) http.Handler {
+	reverseProxy := &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.SetURL(upstream)
+			// Responses are inspected, so they must not be compressed.
+			r.Out.Header.Del("Accept-Encoding")
+		},
+		ModifyResponse: filter.filterResponse,
+		// SSE responses must reach the client as they are written.
+		FlushInterval: -1,
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && jsonrpc.IsJSON(r.Header.Get("Content-Type")) {
+			if !filter.filterRequest(w, r) {
+				return
+			}
+		}
+		reverseProxy.ServeHTTP(w, r)
+	})
+}
+
+// class returns the capability class a request or notification of method with params belongs to, or an empty
+// string when it belongs to none. Completions belong to the class of the prompt or resource they complete.
+func class(method string, params map[string]any) string {
+	if method == "completion/complete" {
+		ref, _ := params["ref"].(map[string]any)
+		switch ref["type"] {
+		case "ref/prompt":
+			return Prompts
+		case "ref/resource":
+			return Resources
+		}
+		return ""
+	}
+	method = strings.TrimPrefix(method, "notifications/")
+	prefix, _, _ := strings.Cut(method, "/")
+	if slices.Contains(classes, prefix) {
+		return prefix
+	}
+	return ""
+}
+
+// filterRequest renames the methods of disabled capabilities in the body of r. When the body cannot be read an
+// error response is written to w and false is returned.
+func (f *Filter) filterRequest(w http.ResponseWriter, r *http.Request) bool {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxMessageSize))
+	if err != nil {
+		code := http.StatusBadRequest
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			code = http.StatusRequestEntityTooLarge
+		}
+		http.Error(w, fmt.Sprintf("unable to read the request: %v", err), code)
+		return false
+	}
+	messages, batch, err := jsonrpc.DecodeMessages(body)
+	if err != nil {
+		// Not JSON-RPC, let the MCP server answer it.
+		jsonrpc.RestoreBody(r, body)
+		return true
+	}
+	changed := false
+	for _, msg := range messages {
+		method, _ := msg["method"].(string)
+		params, _ := msg["params"].(map[string]any)
+		if c := class(method, params); c != "" && f.Disabled[c] {
+			log.Printf("rejecting %s, the %s capability is disabled", method, c)
+			msg["method"] = disabledMethodPrefix + method
+			changed = true
+		}
+	}
+	if changed {
+		if body, err = jsonrpc.EncodeMessages(messages, batch); err != nil {
+			http.Error(w, fmt.Sprintf("unable to encode the request: %v", err), http.StatusInternalServerError)
+			return false
+		}
+	}
+	jsonrpc.RestoreBody(r, body)
+	return true
+}
+
+// filterResponse removes the disabled capabilities from the messages in resp.
+func (f *Filter) filterResponse(resp *http.Response) error {
+	contentType := resp.Header.Get("Content-Type")
+	switch {
+	case strings.HasPrefix(contentType, "text/event-stream"):
+		resp.Body = jsonrpc.FilterEventStream(resp.Body, f.filterMessages)
+	case jsonrpc.IsJSON(contentType):
+		data, err := io.ReadAll(io.LimitReader(resp.Body, maxMessageSize+1))
+		_ = resp.Body.Close()
+		if err != nil {
+			return err
+		}
+		if len(data) > maxMessageSize {
+			return fmt.Errorf("the response exceeds %d bytes", maxMessageSize)
+		}
+		data = f.filterMessages(data)
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+		resp.ContentLength = int64(len(data))
+		resp.Header.Set("Content-Length", strconv.Itoa(len(data)))
+	}
+	return nil
+}
+
+// filterMessages removes the disabled capabilities from the initialize result and drops the notifications of
+// disabled capabilities among the JSON-RPC messages in data, and returns the messages to send to the client, or
+// nil when none are left.
+func (f *Filter) filterMessages(data []byte) []byte {
+	messages, batch, err := jsonrpc.DecodeMessages(data)
+	if err != nil {
+		return data
+	}
+	changed := false
+	kept := make([]map[string]any, 0, len(messages))
+	for _, msg := range messages {
+		method, _ := msg["method"].(string)
+		if _, hasID := msg["id"]; !hasID && method != "" {
+			params, _ := msg["params"].(map[string]any)
+			if c := class(method, params); c != "" && f.Disabled[c] {
+				changed = true
+				continue
+			}
+		}
+		kept = append(kept, msg)
+
+		result, _ := msg["result"].(map[string]any)
+		capabilities, _ := result["capabilities"].(map[string]any)
+		if _, ok := result["protocolVersion"]; !ok || capabilities == nil {
+			// Only the initialize result announces the protocol version along with the capabilities.
+			continue
+		}
+		for disabled := range f.Disabled {
+			if _, ok := capabilities[disabled]; ok {
+				delete(capabilities, disabled)
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return data
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	encoded, err := jsonrpc.EncodeMessages(kept, batch)
+	if err != nil {
+		return data
+	}
+	return encoded
+}
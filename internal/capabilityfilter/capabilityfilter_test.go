@@ -0,0 +1,144 @@
+package capabilityfilter
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const initializeResult = `{"jsonrpc":"2.0","id":1,"result":{"protocolVersion":"2025-03-26",` +
+	`"capabilities":{"tools":{"listChanged":true},"resources":{},"prompts":{}},"serverInfo":{"name":"kubernetes"}}}`
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name          string
+		disabled      []string
+		request       string
+		response      string
+		contentType   string
+		wantUpstream  string
+		wantBody      string
+		forbiddenBody string
+	}{
+		{
+			name:         "request to a disabled capability is renamed",
+			disabled:     []string{Resources},
+			request:      `{"jsonrpc":"2.0","id":2,"method":"resources/read","params":{"uri":"file:///etc/passwd"}}`,
+			response:     `{"jsonrpc":"2.0","id":2,"error":{"code":-32601,"message":"Method not found"}}`,
+			contentType:  "application/json",
+			wantUpstream: `"method":"disabled/resources/read"`,
+			wantBody:     "Method not found",
+		},
+		{
+			name:         "completion of a disabled prompt is renamed",
+			disabled:     []string{Prompts},
+			request:      `{"jsonrpc":"2.0","id":3,"method":"completion/complete","params":{"ref":{"type":"ref/prompt","name":"a"}}}`,
+			response:     `{"jsonrpc":"2.0","id":3,"error":{"code":-32601,"message":"Method not found"}}`,
+			contentType:  "application/json",
+			wantUpstream: `"method":"disabled/completion/complete"`,
+		},
+		{
+			name:         "requests to enabled capabilities are forwarded unchanged",
+			disabled:     []string{Resources, Prompts},
+			request:      `[{"jsonrpc":"2.0","id":4,"method":"tools/list"},{"jsonrpc":"2.0","id":5,"method":"prompts/list"}]`,
+			response:     `[{"jsonrpc":"2.0","id":4,"result":{"tools":[]}}]`,
+			contentType:  "application/json",
+			wantUpstream: `{"id":4,"jsonrpc":"2.0","method":"tools/list"},{"id":5,"jsonrpc":"2.0","method":"disabled/prompts/list"}`,
+			wantBody:     `"tools":[]`,
+		},
+		{
+			name:          "disabled capabilities are removed from the initialize result",
+			disabled:      []string{Resources, Prompts},
+			request:       `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+			response:      initializeResult,
+			contentType:   "application/json",
+			wantUpstream:  `"method":"initialize"`,
+			wantBody:      `"capabilities":{"tools":{"listChanged":true}}`,
+			forbiddenBody: `"resources"`,
+		},
+		{
+			name:          "disabled capabilities are removed from an initialize result in an event stream",
+			disabled:      []string{Tools},
+			request:       `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+			response:      "event: message\ndata: " + initializeResult + "\n\n",
+			contentType:   "text/event-stream",
+			wantBody:      `"capabilities":{"prompts":{},"resources":{}}`,
+			forbiddenBody: `"tools"`,
+		},
+		{
+			name:     "notifications of disabled capabilities are dropped from an event stream",
+			disabled: []string{Tools},
+			request:  `{"jsonrpc":"2.0","id":6,"method":"prompts/list"}`,
+			response: "event: message\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/tools/list_changed\"}\n\n" +
+				"event: message\ndata: {\"jsonrpc\":\"2.0\",\"id\":6,\"result\":{\"prompts\":[]}}\n\n",
+			contentType:   "text/event-stream",
+			wantBody:      `"prompts":[]`,
+			forbiddenBody: "list_changed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUpstream string
+			upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				gotUpstream = string(body)
+				w.Header().Set("Content-Type", tt.contentType)
+				_, _ = io.WriteString(w, tt.response)
+			}))
+			defer upstream.Close()
+			upstreamURL, err := url.Parse(upstream.URL)
+			if err != nil {
+				t.Fatalf("failed to parse the upstream URL: %v", err)
+			}
+			filter := &Filter{Disabled: map[string]bool{}}
+			for _, class := range tt.disabled {
+				filter.Disabled[class] = true
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(tt.request))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			New(upstreamURL, filter).ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+			}
+			if !strings.Contains(gotUpstream, tt.wantUpstream) {
+				t.Errorf("upstream received %s, want it to contain %s", gotUpstream, tt.wantUpstream)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body = %s, want it to contain %s", rec.Body.String(), tt.wantBody)
+			}
+			if tt.forbiddenBody != "" && strings.Contains(rec.Body.String(), tt.forbiddenBody) {
+				t.Errorf("body = %s, want it not to contain %s", rec.Body.String(), tt.forbiddenBody)
+			}
+		})
+	}
+}
+
+func Test_class(t *testing.T) {
+	tests := []struct {
+		method string
+		params map[string]any
+		want   string
+	}{
+		{method: "tools/call", want: Tools},
+		{method: "resources/templates/list", want: Resources},
+		{method: "notifications/resources/updated", want: Resources},
+		{method: "prompts/get", want: Prompts},
+		{method: "completion/complete", params: map[string]any{"ref": map[string]any{"type": "ref/resource"}}, want: Resources},
+		{method: "completion/complete", want: ""},
+		{method: "initialize", want: ""},
+		{method: "toolsets/list", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			if got := class(tt.method, tt.params); got != tt.want {
+				t.Errorf("class() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,315 @@
+// Package conformance implements the conformance-check subcommand of the manager binary. It is run inside
+// the Jobs the operator creates for MCPServers with spec.conformanceCheck, and checks the basic behaviour the
+// MCP specification requires of every server.
+package conformance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/mcp"
+)
+
+// Command is the name of the subcommand.
+const Command = "conformance-check"
+
+// The checks of the suite, in the order they run.
+const (
+	CheckInitialize    = "initialize"
+	CheckListTools     = "tools/list"
+	CheckCallTool      = "tools/call"
+	CheckUnknownMethod = "unknown-method"
+	CheckUnknownTool   = "unknown-tool"
+)
+
+const (
+	// checkTimeout bounds each check, so that a request the server never answers fails its check rather than
+	// the whole suite.
+	checkTimeout = 10 * time.Second
+
+	// messageLimit caps the message of each check, so that the report fits into a termination message.
+	messageLimit = 256
+
+	unknownMethod = "mcp-server-operator/conformance-check"
+	unknownTool   = "mcp-server-operator-conformance-check"
+)
+
+// Options configures the suite.
+type Options struct {
+	// URL is the SSE URL of the MCP server.
+	URL string
+	// Token is sent as bearer token when not empty.
+	Token string
+	// Tool is a tool without side effects to call, the tool call is skipped when empty.
+	Tool string
+	// Arguments are passed to Tool.
+	Arguments map[string]any
+}
+
+// Run executes the suite with the given arguments and returns the process exit code. The report is written
+// to stdout and to the termination log so the operator can read it back from the Pod status.
+func Run(args []string) int {
+	opts := Options{Arguments: map[string]any{}}
+	fs := flag.NewFlagSet(Command, flag.ContinueOnError)
+	fs.StringVar(&opts.URL, "url", "", "The SSE URL of the MCP server to check.")
+	tokenFile := fs.String("token-file", "", "A file holding the bearer token to send.")
+	fs.StringVar(&opts.Tool, "tool", "", "A tool without side effects to call.")
+	fs.Func("argument", "An argument of the tool as key=value, can be repeated.", func(value string) error {
+		key, val, ok := strings.Cut(value, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("%q is not a key=value pair", value)
+		}
+		opts.Arguments[key] = val
+		return nil
+	})
+	timeout := fs.Duration("timeout", 90*time.Second, "The maximum time the suite may take.")
+	terminationLog := fs.String("termination-log", "/dev/termination-log",
+		"The file the report is written to for the kubelet to report in the Pod status.")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if opts.URL == "" {
+		_, _ = fmt.Fprintln(os.Stderr, "--url is required")
+		return 2
+	}
+	if *tokenFile != "" {
+		data, err := os.ReadFile(*tokenFile)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "unable to read the token: %v\n", err)
+			return 2
+		}
+		opts.Token = strings.TrimSpace(string(data))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	checks := Suite(ctx, opts)
+	report, err := json.Marshal(checks)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "unable to encode the report: %v\n", err)
+		return 1
+	}
+
+	_, _ = fmt.Fprintln(os.Stdout, string(report))
+	if writeErr := os.WriteFile(*terminationLog, report, 0o644); writeErr != nil &&
+		!errors.Is(writeErr, os.ErrNotExist) {
+		_, _ = fmt.Fprintf(os.Stderr, "unable to write termination log: %v\n", writeErr)
+	}
+
+	if Result(checks) != mcpserverv1.ConformancePassed {
+		return 1
+	}
+	return 0
+}
+
+// Suite runs all checks against the MCP server and returns their results. Checks that depend on a failed one
+// are skipped.
+func Suite(ctx context.Context, opts Options) []mcpserverv1.ConformanceCheckResult {
+	var httpClient *http.Client
+	if opts.Token != "" {
+		httpClient = mcp.WithBearerToken(nil, opts.Token)
+	}
+
+	// The session lives as long as the suite, only the requests are bounded per check.
+	session, err := mcp.Connect(ctx, httpClient, opts.URL)
+	if err != nil {
+		return failInitialize(fmt.Sprintf("connecting to %s: %v", opts.URL, err))
+	}
+	defer func() {
+		_ = session.Close()
+	}()
+
+	checkCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+	result, err := session.Initialize(checkCtx)
+	cancel()
+	switch {
+	case err != nil:
+		return failInitialize(err.Error())
+	case result.ProtocolVersion == "":
+		return failInitialize("the server announced no protocol version")
+	case result.ServerInfo.Name == "":
+		return failInitialize("the server announced no name")
+	}
+	checks := []mcpserverv1.ConformanceCheckResult{passed(CheckInitialize,
+		fmt.Sprintf("server %s %s, protocol %s", result.ServerInfo.Name, result.ServerInfo.Version, result.ProtocolVersion))}
+
+	_, hasTools := result.Capabilities["tools"]
+	var tools []mcp.Tool
+	if hasTools {
+		var check mcpserverv1.ConformanceCheckResult
+		tools, check = checkListTools(ctx, session)
+		checks = append(checks, check)
+	} else {
+		checks = append(checks, skipped(CheckListTools, "the server offers no tools capability"))
+	}
+
+	switch {
+	case opts.Tool == "":
+		checks = append(checks, skipped(CheckCallTool, "no tool is configured"))
+	case !hasTools:
+		checks = append(checks, failed(CheckCallTool, "the server offers no tools capability"))
+	case checks[len(checks)-1].Result != mcpserverv1.ConformancePassed:
+		checks = append(checks, skipped(CheckCallTool, "the tools could not be listed"))
+	default:
+		checks = append(checks, checkCallTool(ctx, session, tools, opts.Tool, opts.Arguments))
+	}
+
+	checks = append(checks, checkUnknownMethod(ctx, session))
+	if hasTools {
+		checks = append(checks, checkUnknownTool(ctx, session))
+	} else {
+		checks = append(checks, skipped(CheckUnknownTool, "the server offers no tools capability"))
+	}
+	return checks
+}
+
+// checkListTools lists the tools of the server, which must have unique names.
+func checkListTools(ctx context.Context, session *mcp.Session) ([]mcp.Tool, mcpserverv1.ConformanceCheckResult) {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	tools, err := session.ListTools(ctx)
+	if err != nil {
+		return nil, failed(CheckListTools, err.Error())
+	}
+	names := make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		if tool.Name == "" {
+			return nil, failed(CheckListTools, "a tool has no name")
+		}
+		if names[tool.Name] {
+			return nil, failed(CheckListTools, fmt.Sprintf("the tool %s is listed more than once", tool.Name))
+		}
+		names[tool.Name] = true
+	}
+	return tools, passed(CheckListTools, fmt.Sprintf("%d tools", len(tools)))
+}
+
+// checkCallTool calls the configured tool, which must be listed and succeed.
+func checkCallTool(ctx context.Context, session *mcp.Session, tools []mcp.Tool, name string,
+	arguments map[string]any) mcpserverv1.ConformanceCheckResult {
+	listed := false
+	for _, tool := range tools {
+		listed = listed || tool.Name == name
+	}
+	if !listed {
+		return failed(CheckCallTool, fmt.Sprintf("the tool %s is not listed", name))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	result, err := session.CallTool(ctx, name, arguments)
+	if err != nil {
+		return failed(CheckCallTool, fmt.Sprintf("calling %s: %v", name, err))
+	}
+	if result.IsError {
+		return failed(CheckCallTool, fmt.Sprintf("%s returned an error: %s", name, contentText(result.Content)))
+	}
+	return passed(CheckCallTool, fmt.Sprintf("%s returned %d content items", name, len(result.Content)))
+}
+
+// checkUnknownMethod sends a request with a method no server implements, which must be answered with a
+// method not found error.
+func checkUnknownMethod(ctx context.Context, session *mcp.Session) mcpserverv1.ConformanceCheckResult {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	err := session.Call(ctx, unknownMethod, nil, nil)
+	rpcErr := &mcp.RPCError{}
+	switch {
+	case err == nil:
+		return failed(CheckUnknownMethod, fmt.Sprintf("the unknown method %s succeeded", unknownMethod))
+	case !errors.As(err, &rpcErr):
+		return failed(CheckUnknownMethod, fmt.Sprintf("the unknown method %s was not answered: %v", unknownMethod, err))
+	case rpcErr.Code != mcp.MethodNotFound:
+		return failed(CheckUnknownMethod, fmt.Sprintf("the unknown method %s returned error code %d, want %d",
+			unknownMethod, rpcErr.Code, mcp.MethodNotFound))
+	}
+	return passed(CheckUnknownMethod, "")
+}
+
+// checkUnknownTool calls a tool the server does not offer, which must fail either with a JSON-RPC error or
+// with an error result.
+func checkUnknownTool(ctx context.Context, session *mcp.Session) mcpserverv1.ConformanceCheckResult {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	result, err := session.CallTool(ctx, unknownTool, nil)
+	rpcErr := &mcp.RPCError{}
+	switch {
+	case err != nil && !errors.As(err, &rpcErr):
+		return failed(CheckUnknownTool, fmt.Sprintf("the call of the unknown tool %s was not answered: %v", unknownTool, err))
+	case err == nil && !result.IsError:
+		return failed(CheckUnknownTool, fmt.Sprintf("the call of the unknown tool %s succeeded", unknownTool))
+	}
+	return passed(CheckUnknownTool, "")
+}
+
+// Result returns Failed if any of checks failed, and Passed otherwise.
+func Result(checks []mcpserverv1.ConformanceCheckResult) mcpserverv1.ConformanceResult {
+	for _, check := range checks {
+		if check.Result == mcpserverv1.ConformanceFailed {
+			return mcpserverv1.ConformanceFailed
+		}
+	}
+	return mcpserverv1.ConformancePassed
+}
+
+// ParseReport returns the checks of a report written by Run.
+func ParseReport(report string) ([]mcpserverv1.ConformanceCheckResult, error) {
+	var checks []mcpserverv1.ConformanceCheckResult
+	if err := json.Unmarshal([]byte(strings.TrimSpace(report)), &checks); err != nil {
+		return nil, err
+	}
+	return checks, nil
+}
+
+func failInitialize(message string) []mcpserverv1.ConformanceCheckResult {
+	return []mcpserverv1.ConformanceCheckResult{
+		failed(CheckInitialize, message),
+		skipped(CheckListTools, "initialize failed"),
+		skipped(CheckCallTool, "initialize failed"),
+		skipped(CheckUnknownMethod, "initialize failed"),
+		skipped(CheckUnknownTool, "initialize failed"),
+	}
+}
+
+func passed(name, message string) mcpserverv1.ConformanceCheckResult {
+	return mcpserverv1.ConformanceCheckResult{Name: name, Result: mcpserverv1.ConformancePassed, Message: truncate(message)}
+}
+
+func failed(name, message string) mcpserverv1.ConformanceCheckResult {
+	return mcpserverv1.ConformanceCheckResult{Name: name, Result: mcpserverv1.ConformanceFailed, Message: truncate(message)}
+}
+
+func skipped(name, message string) mcpserverv1.ConformanceCheckResult {
+	return mcpserverv1.ConformanceCheckResult{Name: name, Result: mcpserverv1.ConformanceSkipped, Message: message}
+}
+
+// contentText joins the text items of a tool result.
+func contentText(content []mcp.Content) string {
+	var texts []string
+	for _, item := range content {
+		if item.Type == "text" {
+			texts = append(texts, item.Text)
+		}
+	}
+	return strings.Join(texts, " ")
+}
+
+func truncate(message string) string {
+	if len(message) <= messageLimit {
+		return message
+	}
+	return strings.ToValidUTF8(message[:messageLimit], "")
+}
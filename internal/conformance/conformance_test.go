@@ -0,0 +1,162 @@
+package conformance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// newServer starts an SSE MCP server offering an echo tool. A non-conforming server answers unknown methods
+// and tools as if they succeeded.
+func newServer(t *testing.T, conforming bool) *httptest.Server {
+	t.Helper()
+	messages := make(chan string, 8)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sse", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher := w.(http.Flusher)
+		_, _ = fmt.Fprint(w, "event: endpoint\ndata: /message\n\n")
+		flusher.Flush()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case msg := <-messages:
+				_, _ = fmt.Fprintf(w, "event: message\ndata: %s\n\n", msg)
+				flusher.Flush()
+			}
+		}
+	})
+	mux.HandleFunc("/message", func(w http.ResponseWriter, r *http.Request) {
+		req := struct {
+			ID     *int           `json:"id"`
+			Method string         `json:"method"`
+			Params map[string]any `json:"params"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		if req.ID == nil {
+			return
+		}
+
+		result := `{}`
+		switch {
+		case req.Method == "initialize":
+			result = `{"protocolVersion":"2024-11-05","capabilities":{"tools":{}},"serverInfo":{"name":"fake","version":"1.0.0"}}`
+		case req.Method == "tools/list":
+			result = `{"tools":[{"name":"echo"}]}`
+		case req.Method == "tools/call" && req.Params["name"] == "echo":
+			result = `{"content":[{"type":"text","text":"hello"}]}`
+		case req.Method == "tools/call" && conforming:
+			result = `{"content":[{"type":"text","text":"unknown tool"}],"isError":true}`
+		case req.Method != "tools/call" && conforming:
+			messages <- fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"error":{"code":-32601,"message":"method not found"}}`, *req.ID)
+			return
+		}
+		messages <- fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"result":%s}`, *req.ID, result)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSuite(t *testing.T) {
+	tests := []struct {
+		name       string
+		conforming bool
+		tool       string
+		want       map[string]mcpserverv1.ConformanceResult
+		wantResult mcpserverv1.ConformanceResult
+	}{
+		{
+			name:       "conforming server",
+			conforming: true,
+			tool:       "echo",
+			want: map[string]mcpserverv1.ConformanceResult{
+				CheckInitialize:    mcpserverv1.ConformancePassed,
+				CheckListTools:     mcpserverv1.ConformancePassed,
+				CheckCallTool:      mcpserverv1.ConformancePassed,
+				CheckUnknownMethod: mcpserverv1.ConformancePassed,
+				CheckUnknownTool:   mcpserverv1.ConformancePassed,
+			},
+			wantResult: mcpserverv1.ConformancePassed,
+		},
+		{
+			name:       "no tool configured",
+			conforming: true,
+			want: map[string]mcpserverv1.ConformanceResult{
+				CheckCallTool: mcpserverv1.ConformanceSkipped,
+			},
+			wantResult: mcpserverv1.ConformancePassed,
+		},
+		{
+			name: "tool that is not listed",
+			tool: "search",
+			want: map[string]mcpserverv1.ConformanceResult{
+				CheckCallTool: mcpserverv1.ConformanceFailed,
+			},
+			wantResult: mcpserverv1.ConformanceFailed,
+		},
+		{
+			name: "server that accepts anything",
+			tool: "echo",
+			want: map[string]mcpserverv1.ConformanceResult{
+				CheckCallTool:      mcpserverv1.ConformancePassed,
+				CheckUnknownMethod: mcpserverv1.ConformanceFailed,
+				CheckUnknownTool:   mcpserverv1.ConformanceFailed,
+			},
+			wantResult: mcpserverv1.ConformanceFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newServer(t, tt.conforming)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			checks := Suite(ctx, Options{URL: server.URL + "/sse", Tool: tt.tool})
+			if len(checks) != 5 {
+				t.Fatalf("Suite() = %+v, want 5 checks", checks)
+			}
+			for _, check := range checks {
+				if want, ok := tt.want[check.Name]; ok && check.Result != want {
+					t.Errorf("check %s = %s (%s), want %s", check.Name, check.Result, check.Message, want)
+				}
+			}
+			if got := Result(checks); got != tt.wantResult {
+				t.Errorf("Result() = %s, want %s", got, tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestSuite_Unreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	checks := Suite(context.Background(), Options{URL: server.URL + "/sse"})
+	if checks[0].Result != mcpserverv1.ConformanceFailed || checks[1].Result != mcpserverv1.ConformanceSkipped {
+		t.Errorf("Suite() = %+v, want a failed initialize and skipped checks", checks)
+	}
+
+	report, err := json.Marshal(checks)
+	if err != nil {
+		t.Fatalf("failed to encode the report: %v", err)
+	}
+	parsed, err := ParseReport(string(report))
+	if err != nil || len(parsed) != len(checks) {
+		t.Errorf("ParseReport() = %+v, %v, want the %d checks of the report", parsed, err, len(checks))
+	}
+}
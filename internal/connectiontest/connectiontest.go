@@ -0,0 +1,124 @@
+// Package connectiontest implements the connection-test subcommand of the manager binary. It is
+// run inside the Jobs the operator creates for MCPServers with spec.testConnection enabled.
+package connectiontest
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/opendatahub-io/mcp-server-operator/pkg/mcp"
+)
+
+// Command is the name of the subcommand.
+const Command = "connection-test"
+
+// TokenEnv is the environment variable holding the bearer token sent to MCP servers that require one.
+const TokenEnv = "MCP_AUTH_TOKEN"
+
+// Run executes the connection test with the given arguments and returns the process exit code.
+// The outcome is written to stdout and to the termination log so the operator can read it back
+// from the Pod status.
+func Run(args []string) int {
+	fs := flag.NewFlagSet(Command, flag.ContinueOnError)
+	url := fs.String("url", "", "The SSE URL of the MCP server to test.")
+	tokenFile := fs.String("token-file", "",
+		"A file holding the bearer token to send, used instead of the "+TokenEnv+" environment variable.")
+	timeout := fs.Duration("timeout", 30*time.Second, "The maximum time the connection test may take.")
+	terminationLog := fs.String("termination-log", "/dev/termination-log",
+		"The file the outcome is written to for the kubelet to report in the Pod status.")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *url == "" {
+		_, _ = fmt.Fprintln(os.Stderr, "--url is required")
+		return 2
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	token := os.Getenv(TokenEnv)
+	if *tokenFile != "" {
+		data, err := os.ReadFile(*tokenFile)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "unable to read the token: %v\n", err)
+			return 2
+		}
+		token = strings.TrimSpace(string(data))
+	}
+
+	message, err := Test(ctx, *url, token)
+	if err != nil {
+		message = fmt.Sprintf("connection test against %s failed: %v", *url, err)
+	}
+
+	_, _ = fmt.Fprintln(os.Stdout, message)
+	if writeErr := os.WriteFile(*terminationLog, []byte(message), 0o644); writeErr != nil &&
+		!errors.Is(writeErr, os.ErrNotExist) {
+		_, _ = fmt.Fprintf(os.Stderr, "unable to write termination log: %v\n", writeErr)
+	}
+
+	if err != nil {
+		return 1
+	}
+	return 0
+}
+
+// Test performs a full MCP handshake against url and returns a short summary of the server. A non-empty
+// token is sent as bearer token.
+func Test(ctx context.Context, url, token string) (string, error) {
+	var httpClient *http.Client
+	if token != "" {
+		httpClient = mcp.WithBearerToken(nil, token)
+	}
+
+	session, err := mcp.Connect(ctx, httpClient, url)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = session.Close()
+	}()
+
+	result, err := session.Initialize(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	message := fmt.Sprintf("initialize succeeded: server %s %s, protocol %s",
+		result.ServerInfo.Name, result.ServerInfo.Version, result.ProtocolVersion)
+	if _, ok := result.Capabilities["tools"]; !ok {
+		return message, nil
+	}
+
+	tools, err := session.ListTools(ctx)
+	if err != nil {
+		return "", fmt.Errorf("listing tools: %w", err)
+	}
+	return fmt.Sprintf("%s, %d tools", message, len(tools)), nil
+}
+
+// toolCountPattern matches the number of tools at the end of a successful test's message.
+var toolCountPattern = regexp.MustCompile(`, (\d+) tools$`)
+
+// ToolCount returns the number of tools reported in the message of a successful connection test, and false
+// when the server offers no tools capability.
+func ToolCount(message string) (int32, bool) {
+	match := toolCountPattern.FindStringSubmatch(strings.TrimSpace(message))
+	if match == nil {
+		return 0, false
+	}
+	count, err := strconv.ParseInt(match[1], 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return int32(count), true
+}
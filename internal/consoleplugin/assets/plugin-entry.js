@@ -0,0 +1,132 @@
+// Entry of the mcp-server-operator console plugin. It is a hand-written webpack module federation container, so
+// that the plugin needs no build step: the console hands it the shared React of the console in init, and loads
+// the McpServersPage module of the page/route extension with get.
+(function () {
+  'use strict';
+
+  var pluginName = 'mcp-server-operator';
+  var pluginVersion = '1.0.0';
+  var discoveryURL = '/api/proxy/plugin/' + pluginName + '/discovery/api/v1/mcpservers?all=true';
+  var refreshInterval = 10000;
+
+  var sharedScope = null;
+
+  // shared returns a promise of the module the console shares under name.
+  function shared(name) {
+    var versions = (sharedScope && sharedScope[name]) || {};
+    var keys = Object.keys(versions);
+    if (keys.length === 0) {
+      return Promise.reject(new Error('the console does not share ' + name));
+    }
+    return Promise.resolve(versions[keys[keys.length - 1]].get()).then(function (factory) {
+      return factory();
+    });
+  }
+
+  function tools(server) {
+    if (server.tools === undefined || server.tools === null) {
+      return '-';
+    }
+    if (!server.toolNames || server.toolNames.length === 0) {
+      return String(server.tools);
+    }
+    return server.tools + ': ' + server.toolNames.join(', ');
+  }
+
+  function pageModule(React) {
+    var h = React.createElement;
+
+    function McpServersPage() {
+      var state = React.useState({ loading: true, items: [] });
+      var setState = state[1];
+      state = state[0];
+
+      React.useEffect(function () {
+        var cancelled = false;
+        function load() {
+          fetch(discoveryURL, { credentials: 'same-origin', headers: { Accept: 'application/json' } })
+            .then(function (response) {
+              if (!response.ok) {
+                return response.text().then(function (text) {
+                  throw new Error(text || response.statusText);
+                });
+              }
+              return response.json();
+            })
+            .then(function (list) {
+              if (!cancelled) {
+                setState({ items: list.items || [] });
+              }
+            }, function (err) {
+              if (!cancelled) {
+                setState({ items: [], error: err.message });
+              }
+            });
+        }
+        load();
+        var timer = setInterval(load, refreshInterval);
+        return function () {
+          cancelled = true;
+          clearInterval(timer);
+        };
+      }, []);
+
+      var body;
+      if (state.loading) {
+        body = h('p', null, 'Loading...');
+      } else if (state.error) {
+        body = h('p', { className: 'co-error' }, 'Unable to list the MCP servers: ' + state.error);
+      } else if (state.items.length === 0) {
+        body = h('p', null, 'No MCP servers found.');
+      } else {
+        body = h('table', { className: 'pf-v5-c-table pf-m-compact pf-c-table pf-m-compact' },
+          h('thead', null, h('tr', null,
+            ['Name', 'Namespace', 'Status', 'URL', 'Tools'].map(function (title) {
+              return h('th', { key: title, scope: 'col' }, title);
+            }))),
+          h('tbody', null, state.items.map(function (server) {
+            return h('tr', { key: server.namespace + '/' + server.name },
+              h('td', null,
+                h('a', { href: '/k8s/ns/' + server.namespace + '/mcpserver.opendatahub.io~v1~MCPServer/' + server.name },
+                  server.displayName || server.name),
+                server.description ? h('div', { className: 'text-muted' }, server.description) : null),
+              h('td', null, h('a', { href: '/k8s/cluster/projects/' + server.namespace }, server.namespace)),
+              h('td', { title: server.message || '' }, server.ready ? 'Ready' : 'Not ready'),
+              h('td', null, server.url ? h('a', { href: server.url, target: '_blank', rel: 'noopener noreferrer' },
+                server.url) : '-'),
+              h('td', null, tools(server)));
+          })));
+      }
+
+      return h('div', { className: 'co-m-pane__body' },
+        h('h1', { className: 'co-m-pane__heading' }, 'MCP Servers'),
+        body);
+    }
+
+    return { __esModule: true, default: McpServersPage, McpServersPage: McpServersPage };
+  }
+
+  var container = {
+    init: function (scope) {
+      sharedScope = scope;
+    },
+    get: function (module) {
+      var name = String(module).replace(/^\.\//, '');
+      if (name !== 'McpServersPage') {
+        return Promise.reject(new Error('the plugin has no module ' + module));
+      }
+      return shared('react').then(function (React) {
+        var page = pageModule(React.default || React);
+        return function () {
+          return page;
+        };
+      });
+    },
+  };
+
+  if (typeof window.__load_plugin_entry__ === 'function') {
+    window.__load_plugin_entry__(pluginName + '@' + pluginVersion, container);
+  } else {
+    window.loadPluginEntry(pluginName + '@' + pluginVersion, container);
+  }
+})();
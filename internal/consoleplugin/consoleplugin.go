@@ -0,0 +1,86 @@
+// Package consoleplugin implements the console-plugin subcommand of the manager binary. It serves the assets of
+// an OpenShift console dynamic plugin that adds an MCP Servers page to the console, listing the MCPServers the
+// user may get with their readiness, URL and tools. The page reads them from the discovery API of the operator,
+// which the console proxies with the token of the user.
+package consoleplugin
+
+import (
+	"context"
+	"crypto/tls"
+	"embed"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+)
+
+const (
+	// Command is the name of the subcommand.
+	Command = "console-plugin"
+
+	// Name is the name of the plugin, which the ConsolePlugin must have and the console operator configuration
+	// must list to enable it.
+	Name = "mcp-server-operator"
+
+	// DiscoveryProxyAlias is the alias of the proxy of the ConsolePlugin to the discovery API, which the page
+	// reaches under /api/proxy/plugin/<name>/<alias>.
+	DiscoveryProxyAlias = "discovery"
+
+	// ManifestPath is the path of the plugin manifest, which the readiness probe gets.
+	ManifestPath = "/plugin-manifest.json"
+)
+
+//go:embed assets
+var assets embed.FS
+
+// Run serves the assets of the plugin over HTTPS with the given arguments and returns the process exit code.
+func Run(args []string) int {
+	flags := flag.NewFlagSet(Command, flag.ContinueOnError)
+	port := flags.Int("port", 9443, "The port the plugin is served on.")
+	certDir := flags.String("cert-dir", "/var/serving-cert", "The directory holding the tls.crt and tls.key served.")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	watcher, err := certwatcher.New(filepath.Join(*certDir, "tls.crt"), filepath.Join(*certDir, "tls.key"))
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "unable to load the serving certificate: %v\n", err)
+		return 1
+	}
+	go func() {
+		_ = watcher.Start(context.Background())
+	}()
+
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", *port),
+		Handler:           Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		TLSConfig:         &tls.Config{MinVersion: tls.VersionTLS12, GetCertificate: watcher.GetCertificate},
+	}
+	_, _ = fmt.Fprintf(os.Stdout, "serving console plugin %s on port %d\n", Name, *port)
+	if err := server.ListenAndServeTLS("", ""); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		_, _ = fmt.Fprintf(os.Stderr, "console plugin server failed: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// Handler returns the handler serving the assets of the plugin. They are not cached by the console, so that a
+// new version of the operator is picked up on the next page load.
+func Handler() http.Handler {
+	root, err := fs.Sub(assets, "assets")
+	if err != nil {
+		panic(err)
+	}
+	files := http.FileServer(http.FS(root))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-cache")
+		files.ServeHTTP(w, r)
+	})
+}
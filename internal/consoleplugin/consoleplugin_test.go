@@ -0,0 +1,55 @@
+package consoleplugin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	get := func(path string) *httptest.ResponseRecorder {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s status = %d, want %d", path, rec.Code, http.StatusOK)
+		}
+		return rec
+	}
+
+	manifest := struct {
+		Name        string   `json:"name"`
+		Version     string   `json:"version"`
+		LoadScripts []string `json:"loadScripts"`
+		Extensions  []struct {
+			Type string `json:"type"`
+		} `json:"extensions"`
+	}{}
+	rec := get(ManifestPath)
+	if err := json.Unmarshal(rec.Body.Bytes(), &manifest); err != nil {
+		t.Fatalf("failed to decode the manifest: %v", err)
+	}
+	if manifest.Name != Name || len(manifest.Extensions) == 0 {
+		t.Errorf("manifest = %+v, want the extensions of plugin %s", manifest, Name)
+	}
+	if got := rec.Header().Get("Cache-Control"); got != "no-cache" {
+		t.Errorf("Cache-Control = %q, want no-cache", got)
+	}
+
+	// The entry must register under the name and version of the manifest, and read the discovery API through the
+	// proxy of the ConsolePlugin.
+	for _, script := range manifest.LoadScripts {
+		entry := get("/" + script).Body.String()
+		for _, want := range []string{
+			"var pluginName = '" + Name + "'",
+			"var pluginVersion = '" + manifest.Version + "'",
+			"'/" + DiscoveryProxyAlias + "/api/v1/mcpservers?all=true'",
+		} {
+			if !strings.Contains(entry, want) {
+				t.Errorf("%s does not contain %s", script, want)
+			}
+		}
+	}
+}
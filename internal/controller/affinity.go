@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"maps"
+	"slices"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// podAffinity returns the affinity of the MCP server pods: the one of the MCPServer if set, otherwise a preferred
+// anti-affinity that spreads multiple replicas, or an autoscaler that may add them, across nodes and, with a lower
+// weight, across zones.
+func podAffinity(cr *mcpserverv1.MCPServer) *corev1.Affinity {
+	if cr.Spec.Affinity != nil {
+		return cr.Spec.Affinity.DeepCopy()
+	}
+	replicas := ptr.Deref(cr.Spec.Replicas, 1)
+	if cr.Spec.Autoscaling != nil {
+		replicas = max(replicas, cr.Spec.Autoscaling.MaxReplicas)
+	}
+	if replicas <= 1 {
+		return nil
+	}
+
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{mcpServerAppLabelKey: resourceName(cr)}}
+	return &corev1.Affinity{
+		PodAntiAffinity: &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+				{
+					Weight: 100,
+					PodAffinityTerm: corev1.PodAffinityTerm{
+						LabelSelector: selector,
+						TopologyKey:   corev1.LabelHostname,
+					},
+				},
+				{
+					Weight: 50,
+					PodAffinityTerm: corev1.PodAffinityTerm{
+						LabelSelector: selector.DeepCopy(),
+						TopologyKey:   corev1.LabelTopologyZone,
+					},
+				},
+			},
+		},
+	}
+}
+
+// applyScheduling sets spec.affinity, spec.nodeSelector and spec.tolerations of the MCPServer on the pods of an
+// existing Deployment, so that changing them reschedules the pods. Settings removed from the MCPServer are left in
+// place. Pods without an affinity of their own get the default one of podAffinity once replicas or autoscaling are
+// set, so that replicas added later are spread as well.
+func applyScheduling(deployment *appsv1.Deployment, cr *mcpserverv1.MCPServer) {
+	podSpec := &deployment.Spec.Template.Spec
+	switch {
+	case cr.Spec.Affinity != nil:
+		podSpec.Affinity = cr.Spec.Affinity.DeepCopy()
+	case podSpec.Affinity == nil && (cr.Spec.Replicas != nil || cr.Spec.Autoscaling != nil):
+		podSpec.Affinity = podAffinity(cr)
+	}
+	if cr.Spec.NodeSelector != nil {
+		podSpec.NodeSelector = maps.Clone(cr.Spec.NodeSelector)
+	}
+	if cr.Spec.Tolerations != nil {
+		podSpec.Tolerations = slices.Clone(cr.Spec.Tolerations)
+	}
+}
@@ -0,0 +1,155 @@
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func Test_podAffinity(t *testing.T) {
+	userAffinity := &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		replicas     *int32
+		autoscaling  *mcpserverv1.Autoscaling
+		affinity     *corev1.Affinity
+		wantNil      bool
+		wantTopology []string
+	}{
+		{
+			name:    "replicas unset",
+			wantNil: true,
+		},
+		{
+			name:     "single replica",
+			replicas: ptr.To[int32](1),
+			wantNil:  true,
+		},
+		{
+			name:         "multiple replicas",
+			replicas:     ptr.To[int32](2),
+			wantTopology: []string{corev1.LabelHostname, corev1.LabelTopologyZone},
+		},
+		{
+			name:         "autoscaled to multiple replicas",
+			autoscaling:  &mcpserverv1.Autoscaling{MaxReplicas: 4},
+			wantTopology: []string{corev1.LabelHostname, corev1.LabelTopologyZone},
+		},
+		{
+			name:     "user affinity wins",
+			replicas: ptr.To[int32](2),
+			affinity: userAffinity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &mcpserverv1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+				Spec:       mcpserverv1.MCPServerSpec{Replicas: tt.replicas, Autoscaling: tt.autoscaling, Affinity: tt.affinity},
+			}
+
+			got := podAffinity(cr)
+			if tt.wantNil {
+				if got != nil {
+					t.Errorf("podAffinity() = %v, want nil", got)
+				}
+				return
+			}
+			if tt.affinity != nil {
+				if got.PodAntiAffinity != nil || got.NodeAffinity == nil {
+					t.Errorf("podAffinity() = %v, want the affinity of the MCPServer", got)
+				}
+				return
+			}
+
+			terms := got.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+			if len(terms) != len(tt.wantTopology) {
+				t.Fatalf("podAffinity() has %d terms, want %d", len(terms), len(tt.wantTopology))
+			}
+			for i, term := range terms {
+				if term.PodAffinityTerm.TopologyKey != tt.wantTopology[i] {
+					t.Errorf("term %d topology = %s, want %s", i, term.PodAffinityTerm.TopologyKey, tt.wantTopology[i])
+				}
+				if term.PodAffinityTerm.LabelSelector.MatchLabels[mcpServerAppLabelKey] != mcpServerName {
+					t.Errorf("term %d selector = %v, want the MCP server pods", i, term.PodAffinityTerm.LabelSelector)
+				}
+			}
+		})
+	}
+}
+
+func Test_applyScheduling(t *testing.T) {
+	nodeAffinity := &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+			MatchExpressions: []corev1.NodeSelectorRequirement{
+				{Key: "node-pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"mcp"}},
+			},
+		}}},
+	}}
+	existingAffinity := &corev1.Affinity{PodAffinity: &corev1.PodAffinity{}}
+	toleration := corev1.Toleration{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "mcp",
+		Effect: corev1.TaintEffectNoSchedule}
+
+	tests := []struct {
+		name             string
+		spec             mcpserverv1.MCPServerSpec
+		existing         corev1.PodSpec
+		wantAffinity     *corev1.Affinity
+		wantNodeSelector map[string]string
+		wantTolerations  []corev1.Toleration
+	}{
+		{
+			name:             "Verify that nothing is set without scheduling settings",
+			existing:         corev1.PodSpec{NodeSelector: map[string]string{"zone": "a"}},
+			wantNodeSelector: map[string]string{"zone": "a"},
+		},
+		{
+			name: "Verify that the scheduling settings of the MCPServer are applied",
+			spec: mcpserverv1.MCPServerSpec{Affinity: nodeAffinity, NodeSelector: map[string]string{"node-pool": "mcp"},
+				Tolerations: []corev1.Toleration{toleration}},
+			existing:         corev1.PodSpec{Affinity: existingAffinity, NodeSelector: map[string]string{"zone": "a"}},
+			wantAffinity:     nodeAffinity,
+			wantNodeSelector: map[string]string{"node-pool": "mcp"},
+			wantTolerations:  []corev1.Toleration{toleration},
+		},
+		{
+			name:         "Verify that the default affinity is kept out of an affinity on the Deployment",
+			spec:         mcpserverv1.MCPServerSpec{Replicas: ptr.To[int32](2)},
+			existing:     corev1.PodSpec{Affinity: existingAffinity},
+			wantAffinity: existingAffinity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deployment := &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{
+				Spec: tt.existing,
+			}}}
+			cr := &mcpserverv1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+				Spec:       tt.spec,
+			}
+
+			applyScheduling(deployment, cr)
+			podSpec := deployment.Spec.Template.Spec
+			if !reflect.DeepEqual(podSpec.Affinity, tt.wantAffinity) {
+				t.Errorf("affinity = %v, want %v", podSpec.Affinity, tt.wantAffinity)
+			}
+			if !reflect.DeepEqual(podSpec.NodeSelector, tt.wantNodeSelector) {
+				t.Errorf("nodeSelector = %v, want %v", podSpec.NodeSelector, tt.wantNodeSelector)
+			}
+			if !reflect.DeepEqual(podSpec.Tolerations, tt.wantTolerations) {
+				t.Errorf("tolerations = %v, want %v", podSpec.Tolerations, tt.wantTolerations)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package controller
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// +kubebuilder:rbac:groups="autoscaling",resources=horizontalpodautoscalers,verbs=create;get;list;watch;update;patch;delete
+
+// defaultTargetCPUUtilization is the average CPU utilization the autoscaler of an MCP server aims for when
+// spec.autoscaling does not set one.
+const defaultTargetCPUUtilization int32 = 80
+
+// horizontalPodAutoscaler returns the HorizontalPodAutoscaler of spec.autoscaling, which scales the Deployment of
+// cr on the CPU utilization of its pods.
+func horizontalPodAutoscaler(cr *mcpserverv1.MCPServer) *autoscalingv2.HorizontalPodAutoscaler {
+	autoscaling := cr.Spec.Autoscaling
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: autoscalingv2.SchemeGroupVersion.String(),
+			Kind:       "HorizontalPodAutoscaler",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resourceName(cr),
+			Namespace: cr.Namespace,
+			Labels:    map[string]string{mcpServerAppLabelKey: resourceName(cr)},
+		},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: appsv1.SchemeGroupVersion.String(),
+				Kind:       "Deployment",
+				Name:       resourceName(cr),
+			},
+			MinReplicas: ptr.To(ptr.Deref(autoscaling.MinReplicas, 1)),
+			MaxReplicas: autoscaling.MaxReplicas,
+			Metrics: []autoscalingv2.MetricSpec{{
+				Type: autoscalingv2.ResourceMetricSourceType,
+				Resource: &autoscalingv2.ResourceMetricSource{
+					Name: corev1.ResourceCPU,
+					Target: autoscalingv2.MetricTarget{
+						Type:               autoscalingv2.UtilizationMetricType,
+						AverageUtilization: ptr.To(ptr.Deref(autoscaling.TargetCPUUtilizationPercentage, defaultTargetCPUUtilization)),
+					},
+				},
+			}},
+		},
+	}
+}
+
+// reconcileAutoscaler creates or updates the HorizontalPodAutoscaler of cr when spec.autoscaling is set, and
+// removes it otherwise. An autoscaler with the same name that was created by hand is left alone.
+func (r *MCPServerReconciler) reconcileAutoscaler(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) error {
+	if cr.Spec.Autoscaling == nil {
+		return r.deleteAutoscaler(ctx, cli, cr)
+	}
+
+	desired := horizontalPodAutoscaler(cr)
+	if err := r.createChild(ctx, cli, cr, desired); err != nil {
+		return err
+	}
+
+	existing := &autoscalingv2.HorizontalPodAutoscaler{}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(desired), existing); err != nil {
+		if k8serr.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !metav1.IsControlledBy(existing, cr) || equality.Semantic.DeepEqual(existing.Spec, desired.Spec) {
+		return nil
+	}
+	original := existing.DeepCopy()
+	existing.Spec = desired.Spec
+	logChildDiff(ctx, original, existing)
+	return cli.Patch(ctx, existing, client.MergeFrom(original))
+}
+
+// deleteAutoscaler removes the HorizontalPodAutoscaler of an MCP server whose spec.autoscaling was removed.
+func (r *MCPServerReconciler) deleteAutoscaler(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) error {
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{ObjectMeta: metav1.ObjectMeta{Name: resourceName(cr), Namespace: cr.Namespace}}
+	return r.deleteChild(ctx, cli, cr, hpa)
+}
+
+// getAutoscaler returns the HorizontalPodAutoscaler that scales the Deployment of cr, whether the operator created
+// it or not, or nil if there is none. The one of spec.autoscaling wins if several target the Deployment. Autoscalers
+// created by hand are not cached, so they are listed from the API server.
+func (r *MCPServerReconciler) getAutoscaler(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) (*autoscalingv2.HorizontalPodAutoscaler, error) {
+	list := &autoscalingv2.HorizontalPodAutoscalerList{}
+	if err := r.uncachedReader(cli).List(ctx, list, client.InNamespace(cr.Namespace)); err != nil {
+		return nil, err
+	}
+	var found *autoscalingv2.HorizontalPodAutoscaler
+	for i := range list.Items {
+		hpa := &list.Items[i]
+		if !scalesDeployment(hpa, resourceName(cr)) {
+			continue
+		}
+		if metav1.IsControlledBy(hpa, cr) {
+			return hpa, nil
+		}
+		if found == nil {
+			found = hpa
+		}
+	}
+	return found, nil
+}
+
+// scalesDeployment reports whether hpa targets the Deployment named name.
+func scalesDeployment(hpa *autoscalingv2.HorizontalPodAutoscaler, name string) bool {
+	ref := hpa.Spec.ScaleTargetRef
+	return ref.Kind == "Deployment" && ref.Name == name && (ref.APIVersion == "" || ref.APIVersion == appsv1.SchemeGroupVersion.String())
+}
+
+// isAutoscaled reports whether the replica count of the Deployment of cr is driven by an autoscaler, the one of
+// spec.autoscaling or one created by hand, so that the operator must not reset it.
+func (r *MCPServerReconciler) isAutoscaled(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) (bool, error) {
+	if cr.Spec.Autoscaling != nil {
+		return true, nil
+	}
+	hpa, err := r.getAutoscaler(ctx, cli, cr)
+	return hpa != nil, err
+}
+
+// scalesToOrFromZero reports whether spec.replicas suspends the autoscaled deployment or resumes it. An
+// autoscaler neither scales a Deployment to zero pods nor scales up one that has none, so spec.replicas still
+// applies then.
+func scalesToOrFromZero(cr *mcpserverv1.MCPServer, deployment *appsv1.Deployment) bool {
+	return *cr.Spec.Replicas == 0 || ptr.Deref(deployment.Spec.Replicas, 1) == 0
+}
+
+// getAutoscalerStatus returns the bounds and replica counts of the HorizontalPodAutoscaler of cr, or nil if its
+// Deployment is not autoscaled.
+func (r *MCPServerReconciler) getAutoscalerStatus(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) (*mcpserverv1.AutoscalerStatus, error) {
+	hpa, err := r.getAutoscaler(ctx, cli, cr)
+	if hpa == nil || err != nil {
+		return nil, err
+	}
+	return &mcpserverv1.AutoscalerStatus{
+		Name:            hpa.Name,
+		Managed:         metav1.IsControlledBy(hpa, cr),
+		MinReplicas:     ptr.Deref(hpa.Spec.MinReplicas, 1),
+		MaxReplicas:     hpa.Spec.MaxReplicas,
+		CurrentReplicas: hpa.Status.CurrentReplicas,
+		DesiredReplicas: hpa.Status.DesiredReplicas,
+	}, nil
+}
+
+// mapAutoscalerToMCPServer maps a HorizontalPodAutoscaler to the MCPServer whose Deployment it scales, taken from the
+// name of the target Deployment. Only the autoscalers the operator created are cached and so watched; one created by
+// hand is found by the next reconciliation, at the latest once it scales the Deployment.
+func (r *MCPServerReconciler) mapAutoscalerToMCPServer(ctx context.Context, obj client.Object) []reconcile.Request {
+	hpa, ok := obj.(*autoscalingv2.HorizontalPodAutoscaler)
+	if !ok || !scalesDeployment(hpa, hpa.Spec.ScaleTargetRef.Name) {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: r.mcpServerKey(ctx, hpa.Namespace, hpa.Spec.ScaleTargetRef.Name)}}
+}
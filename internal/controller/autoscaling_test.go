@@ -0,0 +1,212 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+func newAutoscalingScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	fakeScheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(fakeScheme); err != nil {
+		t.Fatalf("failed to add client-go scheme: %v", err)
+	}
+	if err := mcpserverv1.AddToScheme(fakeScheme); err != nil {
+		t.Fatalf("failed to add mcpserverv1 scheme: %v", err)
+	}
+	return fakeScheme
+}
+
+// newHandMadeAutoscaler returns an autoscaler of the MCP server Deployment that the operator did not create.
+func newHandMadeAutoscaler(name string) *autoscalingv2.HorizontalPodAutoscaler {
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       mcpServerName,
+			},
+			MinReplicas: ptr.To[int32](2),
+			MaxReplicas: 8,
+		},
+		Status: autoscalingv2.HorizontalPodAutoscalerStatus{CurrentReplicas: 3, DesiredReplicas: 4},
+	}
+}
+
+func TestMCPServerReconciler_reconcileAutoscaler(t *testing.T) {
+	fakeScheme := newAutoscalingScheme(t)
+	newMCPServer := func(autoscaling *mcpserverv1.Autoscaling) *mcpserverv1.MCPServer {
+		return &mcpserverv1.MCPServer{
+			ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace, UID: types.UID("uid")},
+			Spec:       mcpserverv1.MCPServerSpec{Image: mcpServerImage, Autoscaling: autoscaling},
+		}
+	}
+
+	// owned returns the autoscaler the operator created for an earlier spec.autoscaling of cr.
+	owned := func(cr *mcpserverv1.MCPServer) client.Object {
+		hpa := horizontalPodAutoscaler(newMCPServer(&mcpserverv1.Autoscaling{MaxReplicas: 5}))
+		if err := ctrl.SetControllerReference(cr, hpa, fakeScheme); err != nil {
+			t.Fatalf("failed to set the controller reference: %v", err)
+		}
+		return hpa
+	}
+
+	tests := []struct {
+		name        string
+		autoscaling *mcpserverv1.Autoscaling
+		existing    func(cr *mcpserverv1.MCPServer) client.Object
+		wantExists  bool
+		wantMin     int32
+		wantMax     int32
+		wantCPU     int32
+	}{
+		{
+			name:        "Verify that the autoscaler is created with the defaults",
+			autoscaling: &mcpserverv1.Autoscaling{MaxReplicas: 5},
+			wantExists:  true,
+			wantMin:     1,
+			wantMax:     5,
+			wantCPU:     defaultTargetCPUUtilization,
+		},
+		{
+			name: "Verify that a changed autoscaling is applied to the autoscaler",
+			autoscaling: &mcpserverv1.Autoscaling{MinReplicas: ptr.To[int32](2), MaxReplicas: 10,
+				TargetCPUUtilizationPercentage: ptr.To[int32](60)},
+			existing:   owned,
+			wantExists: true,
+			wantMin:    2,
+			wantMax:    10,
+			wantCPU:    60,
+		},
+		{
+			name:     "Verify that the autoscaler is removed when autoscaling is unset",
+			existing: owned,
+		},
+		{
+			name:        "Verify that an autoscaler created by hand is not changed",
+			autoscaling: &mcpserverv1.Autoscaling{MaxReplicas: 5},
+			existing: func(*mcpserverv1.MCPServer) client.Object {
+				return newHandMadeAutoscaler(mcpServerName)
+			},
+			wantExists: true,
+			wantMin:    2,
+			wantMax:    8,
+		},
+		{
+			name: "Verify that an autoscaler created by hand is not removed",
+			existing: func(*mcpserverv1.MCPServer) client.Object {
+				return newHandMadeAutoscaler(mcpServerName)
+			},
+			wantExists: true,
+			wantMin:    2,
+			wantMax:    8,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := newMCPServer(tt.autoscaling)
+			builder := fake.NewClientBuilder().WithScheme(fakeScheme)
+			if tt.existing != nil {
+				builder = builder.WithObjects(tt.existing(cr))
+			}
+			cli := builder.Build()
+			r := &MCPServerReconciler{Client: cli, Scheme: fakeScheme}
+
+			if err := r.reconcileAutoscaler(context.Background(), cli, cr); err != nil {
+				t.Fatalf("reconcileAutoscaler() error = %v", err)
+			}
+
+			hpa := &autoscalingv2.HorizontalPodAutoscaler{}
+			err := cli.Get(context.Background(), client.ObjectKeyFromObject(cr), hpa)
+			if !tt.wantExists {
+				if !k8serr.IsNotFound(err) {
+					t.Fatalf("autoscaler still exists, error = %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to get the autoscaler: %v", err)
+			}
+			if got := ptr.Deref(hpa.Spec.MinReplicas, 0); got != tt.wantMin {
+				t.Errorf("minReplicas = %d, want %d", got, tt.wantMin)
+			}
+			if got := hpa.Spec.MaxReplicas; got != tt.wantMax {
+				t.Errorf("maxReplicas = %d, want %d", got, tt.wantMax)
+			}
+			var cpu int32
+			if len(hpa.Spec.Metrics) > 0 && hpa.Spec.Metrics[0].Resource != nil {
+				cpu = ptr.Deref(hpa.Spec.Metrics[0].Resource.Target.AverageUtilization, 0)
+			}
+			if cpu != tt.wantCPU {
+				t.Errorf("target CPU utilization = %d, want %d", cpu, tt.wantCPU)
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_getAutoscalerStatus(t *testing.T) {
+	fakeScheme := newAutoscalingScheme(t)
+	cr := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace, UID: types.UID("uid")},
+		Spec:       mcpserverv1.MCPServerSpec{Image: mcpServerImage},
+	}
+	other := newHandMadeAutoscaler("other")
+	other.Spec.ScaleTargetRef.Name = "other"
+	managed := horizontalPodAutoscaler(&mcpserverv1.MCPServer{
+		ObjectMeta: cr.ObjectMeta,
+		Spec:       mcpserverv1.MCPServerSpec{Autoscaling: &mcpserverv1.Autoscaling{MaxReplicas: 5}},
+	})
+	if err := ctrl.SetControllerReference(cr, managed, fakeScheme); err != nil {
+		t.Fatalf("failed to set the controller reference: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		existing []client.Object
+		want     *mcpserverv1.AutoscalerStatus
+	}{
+		{
+			name:     "Verify that there is no status without an autoscaler of the Deployment",
+			existing: []client.Object{other},
+		},
+		{
+			name:     "Verify that an autoscaler created by hand is reported",
+			existing: []client.Object{other, newHandMadeAutoscaler("custom")},
+			want: &mcpserverv1.AutoscalerStatus{Name: "custom", MinReplicas: 2, MaxReplicas: 8,
+				CurrentReplicas: 3, DesiredReplicas: 4},
+		},
+		{
+			name:     "Verify that the autoscaler of spec.autoscaling is preferred",
+			existing: []client.Object{newHandMadeAutoscaler("custom"), managed},
+			want:     &mcpserverv1.AutoscalerStatus{Name: mcpServerName, Managed: true, MinReplicas: 1, MaxReplicas: 5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(tt.existing...).Build()
+			r := &MCPServerReconciler{Client: cli, Scheme: fakeScheme}
+
+			got, err := r.getAutoscalerStatus(context.Background(), cli, cr)
+			if err != nil {
+				t.Fatalf("getAutoscalerStatus() error = %v", err)
+			}
+			if (got == nil) != (tt.want == nil) || got != nil && *got != *tt.want {
+				t.Errorf("getAutoscalerStatus() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+const (
+	// readinessFlapWindow is the period over which the readiness flaps of an MCPServer are counted.
+	readinessFlapWindow = time.Hour
+	// maxReadinessFlaps is the number of readiness flaps kept in the status of an MCPServer.
+	maxReadinessFlaps = 20
+)
+
+// recordAvailability records the change of the Available condition from original to status in the availability
+// history of status: the time it last turned True, a summary of the change, and the times it turned from True to
+// anything else within the last readinessFlapWindow, except for rollouts. The flaps are the LastTransitionTime of the condition, so
+// recording the same change twice has no effect.
+func recordAvailability(original, status *mcpserverv1.MCPServerStatus, now time.Time) {
+	current := meta.FindStatusCondition(status.Conditions, OverallAvailable)
+	previous := meta.FindStatusCondition(original.Conditions, OverallAvailable)
+	if current != nil {
+		if current.Status == metav1.ConditionTrue {
+			status.LastReadyTime = current.LastTransitionTime.DeepCopy()
+		}
+		if previous == nil || previous.Status != current.Status {
+			status.LastTransitionSummary = transitionSummary(previous, current)
+		}
+		// Rolling out a change of the spec is not a flap, even though Available is Unknown meanwhile.
+		flapped := previous != nil && previous.Status == metav1.ConditionTrue &&
+			current.Status != metav1.ConditionTrue && current.Reason != ReasonRolloutInProgress
+		if flapped && !slices.ContainsFunc(status.ReadinessFlapTimes, func(t metav1.Time) bool {
+			return t.Equal(&current.LastTransitionTime)
+		}) {
+			status.ReadinessFlapTimes = append(status.ReadinessFlapTimes, current.LastTransitionTime)
+		}
+	}
+
+	cutoff := now.Add(-readinessFlapWindow)
+	flaps := slices.DeleteFunc(slices.Clone(status.ReadinessFlapTimes), func(t metav1.Time) bool {
+		return t.Time.Before(cutoff)
+	})
+	if n := len(flaps); n > maxReadinessFlaps {
+		flaps = flaps[n-maxReadinessFlaps:]
+	}
+	if len(flaps) == 0 {
+		flaps = nil
+	}
+	status.ReadinessFlapTimes = flaps
+	status.ReadinessFlaps = int32(len(flaps))
+}
+
+// transitionSummary describes the change of the Available condition from previous, nil when it was not set, to
+// current.
+func transitionSummary(previous, current *metav1.Condition) string {
+	at := current.LastTransitionTime.UTC().Format(time.RFC3339)
+	became := "Became available"
+	if current.Status != metav1.ConditionTrue {
+		became = "Became unavailable"
+	}
+	summary := fmt.Sprintf("%s at %s", became, at)
+	if previous != nil && !previous.LastTransitionTime.IsZero() {
+		state := "available"
+		if previous.Status != metav1.ConditionTrue {
+			state = "unavailable"
+		}
+		lasted := current.LastTransitionTime.Sub(previous.LastTransitionTime.Time).Round(time.Second)
+		summary = fmt.Sprintf("%s after %s %s", summary, lasted, state)
+	}
+	return fmt.Sprintf("%s: %s: %s", summary, current.Reason, current.Message)
+}
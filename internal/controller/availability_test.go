@@ -0,0 +1,136 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+func Test_recordAvailability(t *testing.T) {
+	now := time.Date(2025, 1, 2, 15, 0, 0, 0, time.UTC)
+	at := func(ago time.Duration) metav1.Time { return metav1.NewTime(now.Add(-ago)) }
+	available := func(status metav1.ConditionStatus, since metav1.Time) []metav1.Condition {
+		reason := ReasonAsExpected
+		if status != metav1.ConditionTrue {
+			reason = ReasonEndpointUnreachable
+		}
+		return []metav1.Condition{{
+			Type:               OverallAvailable,
+			Status:             status,
+			Reason:             reason,
+			Message:            "message",
+			LastTransitionTime: since,
+		}}
+	}
+
+	tests := []struct {
+		name        string
+		original    mcpserverv1.MCPServerStatus
+		status      mcpserverv1.MCPServerStatus
+		wantReady   *metav1.Time
+		wantSummary string
+		wantFlaps   []metav1.Time
+	}{
+		{
+			name:        "Verify that the first Available condition is summarized",
+			status:      mcpserverv1.MCPServerStatus{Conditions: available(metav1.ConditionTrue, at(0))},
+			wantReady:   &metav1.Time{Time: now},
+			wantSummary: "Became available at 2025-01-02T15:00:00Z: AsExpected: message",
+		},
+		{
+			name:     "Verify that becoming unavailable counts as a flap",
+			original: mcpserverv1.MCPServerStatus{Conditions: available(metav1.ConditionTrue, at(3*time.Hour))},
+			status: mcpserverv1.MCPServerStatus{
+				Conditions:    available(metav1.ConditionFalse, at(0)),
+				LastReadyTime: &metav1.Time{Time: now.Add(-3 * time.Hour)},
+			},
+			wantReady: &metav1.Time{Time: now.Add(-3 * time.Hour)},
+			wantSummary: "Became unavailable at 2025-01-02T15:00:00Z after 3h0m0s available: EndpointUnreachable: " +
+				"message",
+			wantFlaps: []metav1.Time{at(0)},
+		},
+		{
+			name:     "Verify that a rollout does not count as a flap",
+			original: mcpserverv1.MCPServerStatus{Conditions: available(metav1.ConditionTrue, at(time.Hour))},
+			status: mcpserverv1.MCPServerStatus{Conditions: []metav1.Condition{{
+				Type:               OverallAvailable,
+				Status:             metav1.ConditionUnknown,
+				Reason:             ReasonRolloutInProgress,
+				Message:            "message",
+				LastTransitionTime: at(0),
+			}}},
+			wantSummary: "Became unavailable at 2025-01-02T15:00:00Z after 1h0m0s available: RolloutInProgress: message",
+		},
+		{
+			name:     "Verify that a flap recorded already is not counted twice",
+			original: mcpserverv1.MCPServerStatus{Conditions: available(metav1.ConditionTrue, at(time.Hour))},
+			status: mcpserverv1.MCPServerStatus{
+				Conditions:            available(metav1.ConditionFalse, at(0)),
+				LastTransitionSummary: "summary",
+				ReadinessFlapTimes:    []metav1.Time{at(0)},
+			},
+			wantSummary: "Became unavailable at 2025-01-02T15:00:00Z after 1h0m0s available: EndpointUnreachable: " +
+				"message",
+			wantFlaps: []metav1.Time{at(0)},
+		},
+		{
+			name:     "Verify that flaps older than the window are dropped",
+			original: mcpserverv1.MCPServerStatus{Conditions: available(metav1.ConditionTrue, at(time.Minute))},
+			status: mcpserverv1.MCPServerStatus{
+				Conditions:            available(metav1.ConditionTrue, at(time.Minute)),
+				LastTransitionSummary: "summary",
+				ReadinessFlapTimes:    []metav1.Time{at(2 * time.Hour), at(30 * time.Minute)},
+			},
+			wantReady:   &metav1.Time{Time: now.Add(-time.Minute)},
+			wantSummary: "summary",
+			wantFlaps:   []metav1.Time{at(30 * time.Minute)},
+		},
+		{
+			name:     "Verify that the last flaps are kept",
+			original: mcpserverv1.MCPServerStatus{Conditions: available(metav1.ConditionTrue, at(time.Minute))},
+			status: mcpserverv1.MCPServerStatus{
+				Conditions: available(metav1.ConditionFalse, at(0)),
+				ReadinessFlapTimes: func() []metav1.Time {
+					var flaps []metav1.Time
+					for i := maxReadinessFlaps; i > 0; i-- {
+						flaps = append(flaps, at(time.Duration(i)*time.Minute))
+					}
+					return flaps
+				}(),
+			},
+			wantSummary: "Became unavailable at 2025-01-02T15:00:00Z after 1m0s available: EndpointUnreachable: message",
+			wantFlaps: func() []metav1.Time {
+				var flaps []metav1.Time
+				for i := maxReadinessFlaps - 1; i >= 0; i-- {
+					flaps = append(flaps, at(time.Duration(i)*time.Minute))
+				}
+				return flaps
+			}(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := tt.status.DeepCopy()
+			recordAvailability(&tt.original, status, now)
+
+			if !status.LastReadyTime.Equal(tt.wantReady) {
+				t.Errorf("LastReadyTime = %v, want %v", status.LastReadyTime, tt.wantReady)
+			}
+			if status.LastTransitionSummary != tt.wantSummary {
+				t.Errorf("LastTransitionSummary = %q, want %q", status.LastTransitionSummary, tt.wantSummary)
+			}
+			if int(status.ReadinessFlaps) != len(tt.wantFlaps) || len(status.ReadinessFlapTimes) != len(tt.wantFlaps) {
+				t.Fatalf("ReadinessFlaps = %d, ReadinessFlapTimes = %v, want %v", status.ReadinessFlaps,
+					status.ReadinessFlapTimes, tt.wantFlaps)
+			}
+			for i := range tt.wantFlaps {
+				if !status.ReadinessFlapTimes[i].Equal(&tt.wantFlaps[i]) {
+					t.Errorf("ReadinessFlapTimes = %v, want %v", status.ReadinessFlapTimes, tt.wantFlaps)
+				}
+			}
+		})
+	}
+}
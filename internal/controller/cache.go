@@ -0,0 +1,48 @@
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+const (
+	cacheVolumeName = "cache"
+
+	// defaultCacheMountPath is used when the MCPServer was created without the API server defaulting mountPath.
+	defaultCacheMountPath = "/cache"
+)
+
+// cacheVolume returns the emptyDir volume backing spec.cache and its mount in the MCP server container, or nils
+// when no cache is requested.
+func cacheVolume(cr *mcpserverv1.MCPServer) (*corev1.Volume, *corev1.VolumeMount) {
+	cache := cr.Spec.Cache
+	if cache == nil {
+		return nil, nil
+	}
+
+	medium := corev1.StorageMediumDefault
+	if cache.Medium == mcpserverv1.CacheMediumMemory {
+		medium = corev1.StorageMediumMemory
+	}
+	sizeLimit := cache.SizeLimit.DeepCopy()
+	volume := &corev1.Volume{
+		Name: cacheVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{
+				Medium:    medium,
+				SizeLimit: &sizeLimit,
+			},
+		},
+	}
+
+	mountPath := cache.MountPath
+	if mountPath == "" {
+		mountPath = defaultCacheMountPath
+	}
+	mount := &corev1.VolumeMount{
+		Name:      cacheVolumeName,
+		MountPath: mountPath,
+	}
+	return volume, mount
+}
@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func Test_cacheVolume(t *testing.T) {
+	sizeLimit := resource.MustParse("512Mi")
+
+	tests := []struct {
+		name       string
+		cache      *mcpserverv1.Cache
+		wantVolume *corev1.Volume
+		wantMount  *corev1.VolumeMount
+	}{
+		{
+			name: "no cache",
+		},
+		{
+			name:  "disk cache at the default path",
+			cache: &mcpserverv1.Cache{SizeLimit: sizeLimit},
+			wantVolume: &corev1.Volume{
+				Name: cacheVolumeName,
+				VolumeSource: corev1.VolumeSource{
+					EmptyDir: &corev1.EmptyDirVolumeSource{SizeLimit: &sizeLimit},
+				},
+			},
+			wantMount: &corev1.VolumeMount{Name: cacheVolumeName, MountPath: "/cache"},
+		},
+		{
+			name: "memory cache",
+			cache: &mcpserverv1.Cache{
+				MountPath: "/tmp/models",
+				SizeLimit: sizeLimit,
+				Medium:    mcpserverv1.CacheMediumMemory,
+			},
+			wantVolume: &corev1.Volume{
+				Name: cacheVolumeName,
+				VolumeSource: corev1.VolumeSource{
+					EmptyDir: &corev1.EmptyDirVolumeSource{
+						Medium:    corev1.StorageMediumMemory,
+						SizeLimit: &sizeLimit,
+					},
+				},
+			},
+			wantMount: &corev1.VolumeMount{Name: cacheVolumeName, MountPath: "/tmp/models"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &mcpserverv1.MCPServer{Spec: mcpserverv1.MCPServerSpec{Cache: tt.cache}}
+			volume, mount := cacheVolume(cr)
+			if !reflect.DeepEqual(volume, tt.wantVolume) {
+				t.Errorf("cacheVolume() volume = %v, want %v", volume, tt.wantVolume)
+			}
+			if !reflect.DeepEqual(mount, tt.wantMount) {
+				t.Errorf("cacheVolume() mount = %v, want %v", mount, tt.wantMount)
+			}
+		})
+	}
+}
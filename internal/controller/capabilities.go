@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/internal/capabilityfilter"
+)
+
+const (
+	// CapabilityFilterAvailable reports whether the capability filter of every MCP server pod is ready. It is
+	// only set for MCP servers that disable a capability class in spec.capabilities.
+	CapabilityFilterAvailable = "CapabilityFilterAvailable"
+
+	capabilityFilterContainerName = "capability-filter"
+	capabilityFilterPort          = 8050
+	capabilityFilterHealthPort    = 8051
+)
+
+// disabledCapabilities returns the capability classes that spec.capabilities of cr disables.
+func disabledCapabilities(cr *mcpserverv1.MCPServer) []string {
+	spec := cr.Spec.Capabilities
+	if spec == nil {
+		return nil
+	}
+	var disabled []string
+	for _, capability := range []struct {
+		class   string
+		enabled *bool
+	}{
+		{capabilityfilter.Tools, spec.Tools},
+		{capabilityfilter.Resources, spec.Resources},
+		{capabilityfilter.Prompts, spec.Prompts},
+	} {
+		if !ptr.Deref(capability.enabled, true) {
+			disabled = append(disabled, capability.class)
+		}
+	}
+	return disabled
+}
+
+// capabilityFilterContainer returns the capability filter of cr, which runs the capability-filter subcommand of
+// the operator image in front of the guardrails filter and the MCP server, or nil when cr disables no capability
+// class.
+func (r *MCPServerReconciler) capabilityFilterContainer(cr *mcpserverv1.MCPServer) *corev1.Container {
+	disabled := disabledCapabilities(cr)
+	if len(disabled) == 0 {
+		return nil
+	}
+
+	args := []string{
+		"--upstream", fmt.Sprintf("http://localhost:%d", upstreamPort(cr, capabilityFilterContainerName)),
+		"--port", strconv.Itoa(capabilityFilterPort),
+		"--health-port", strconv.Itoa(capabilityFilterHealthPort),
+	}
+	for _, class := range disabled {
+		args = append(args, "--disable", class)
+	}
+
+	return &corev1.Container{
+		Name:    capabilityFilterContainerName,
+		Image:   r.OperatorImage,
+		Command: []string{"/manager", capabilityfilter.Command},
+		Args:    args,
+		Ports: []corev1.ContainerPort{{
+			ContainerPort: capabilityFilterPort,
+			Name:          "http",
+		}},
+		ReadinessProbe: &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Path: capabilityfilter.HealthPath,
+					Port: intstr.FromInt32(capabilityFilterHealthPort),
+				},
+			},
+			PeriodSeconds: 10,
+		},
+		SecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: ptr.To(false),
+			Capabilities: &corev1.Capabilities{
+				Drop: []corev1.Capability{"ALL"},
+			},
+		},
+	}
+}
+
+// withCapabilityFilter returns containers with the capability filter set to sidecar, or removed when sidecar is
+// nil.
+func withCapabilityFilter(containers []corev1.Container, sidecar *corev1.Container) []corev1.Container {
+	return withSidecar(containers, capabilityFilterContainerName, sidecar, guardrailsContainerName, "mcp-server")
+}
+
+// getCapabilityFilterCondition returns the CapabilityFilterAvailable condition of cr from the readiness of the
+// capability filter in each MCP server pod.
+func (r *MCPServerReconciler) getCapabilityFilterCondition(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) metav1.Condition {
+	return r.getSidecarCondition(ctx, cli, cr, CapabilityFilterAvailable, capabilityFilterContainerName, "CapabilityFilter",
+		"capability filter", "check the logs of its capability-filter container")
+}
@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+func Test_disabledCapabilities(t *testing.T) {
+	tests := []struct {
+		name         string
+		capabilities *mcpserverv1.Capabilities
+		want         []string
+	}{
+		{
+			name: "no capabilities",
+		},
+		{
+			name:         "all capabilities offered",
+			capabilities: &mcpserverv1.Capabilities{Tools: ptr.To(true)},
+		},
+		{
+			name:         "resources and prompts disabled",
+			capabilities: &mcpserverv1.Capabilities{Resources: ptr.To(false), Prompts: ptr.To(false)},
+			want:         []string{"resources", "prompts"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := newGuardrailsMCPServer()
+			cr.Spec.Capabilities = tt.capabilities
+			if got := disabledCapabilities(cr); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("disabledCapabilities() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_withCapabilityFilter(t *testing.T) {
+	r := &MCPServerReconciler{OperatorImage: "quay.io/example/operator:latest"}
+	server := corev1.Container{
+		Name:  "mcp-server",
+		Ports: []corev1.ContainerPort{{ContainerPort: 8000, Name: "http"}},
+	}
+	newCR := func(guardrails, capabilities, metricsExporter bool) *mcpserverv1.MCPServer {
+		cr := newGuardrailsMCPServer()
+		if !guardrails {
+			cr.Spec.Guardrails = nil
+		}
+		if capabilities {
+			cr.Spec.Capabilities = &mcpserverv1.Capabilities{Resources: ptr.To(false)}
+		}
+		if metricsExporter {
+			cr.Spec.MetricsExporter = &mcpserverv1.MetricsExporter{}
+		}
+		return cr
+	}
+	all := r.withSidecars(newCR(true, true, true), []corev1.Container{server})
+
+	tests := []struct {
+		name         string
+		cr           *mcpserverv1.MCPServer
+		containers   []corev1.Container
+		wantPorts    map[string]string
+		wantUpstream map[string]string
+	}{
+		{
+			name:         "capability filter in front of the MCP server",
+			cr:           newCR(false, true, false),
+			containers:   []corev1.Container{server},
+			wantPorts:    map[string]string{"mcp-server": mcpServerPortName, capabilityFilterContainerName: "http"},
+			wantUpstream: map[string]string{capabilityFilterContainerName: "http://localhost:8000"},
+		},
+		{
+			name:       "capability filter between the metrics exporter and the guardrails filter",
+			cr:         newCR(true, true, true),
+			containers: []corev1.Container{server},
+			wantPorts: map[string]string{"mcp-server": mcpServerPortName, guardrailsContainerName: guardrailsPortName,
+				capabilityFilterContainerName: capabilityFilterPortName, metricsExporterContainerName: "http"},
+			wantUpstream: map[string]string{
+				capabilityFilterContainerName: "http://localhost:8080",
+				metricsExporterContainerName:  "http://localhost:8050",
+			},
+		},
+		{
+			name:       "capability filter removed behind the metrics exporter",
+			cr:         newCR(true, false, true),
+			containers: all,
+			wantPorts: map[string]string{"mcp-server": mcpServerPortName, guardrailsContainerName: guardrailsPortName,
+				metricsExporterContainerName: "http"},
+			wantUpstream: map[string]string{metricsExporterContainerName: "http://localhost:8080"},
+		},
+		{
+			name:       "guardrails filter removed behind the capability filter",
+			cr:         newCR(false, true, false),
+			containers: all,
+			wantPorts: map[string]string{"mcp-server": mcpServerPortName,
+				capabilityFilterContainerName: "http"},
+			wantUpstream: map[string]string{capabilityFilterContainerName: "http://localhost:8000"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := r.withSidecars(tt.cr, tt.containers)
+			if len(got) != len(tt.wantPorts) {
+				t.Fatalf("withSidecars() returned %d containers, want %d", len(got), len(tt.wantPorts))
+			}
+			for _, container := range got {
+				if container.Ports[0].Name != tt.wantPorts[container.Name] {
+					t.Errorf("port of %s = %s, want %s", container.Name, container.Ports[0].Name, tt.wantPorts[container.Name])
+				}
+				if want, ok := tt.wantUpstream[container.Name]; ok && container.Args[1] != want {
+					t.Errorf("upstream of %s = %s, want %s", container.Name, container.Args[1], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package controller
+
+import (
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// DisplayName returns the name of cr shown in catalogs: spec.displayName, the openshift.io/display-name
+// annotation or the name of the MCPServer.
+func DisplayName(cr *mcpserverv1.MCPServer) string {
+	if cr.Spec.DisplayName != "" {
+		return cr.Spec.DisplayName
+	}
+	if name := cr.Annotations[mcpserverv1.DisplayNameAnnotation]; name != "" {
+		return name
+	}
+	return cr.Name
+}
+
+// Description returns the description of cr shown in catalogs: spec.description, or the
+// openshift.io/description annotation.
+func Description(cr *mcpserverv1.MCPServer) string {
+	if cr.Spec.Description != "" {
+		return cr.Spec.Description
+	}
+	return cr.Annotations[mcpserverv1.DescriptionAnnotation]
+}
@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+func Test_DisplayNameAndDescription(t *testing.T) {
+	tests := []struct {
+		name            string
+		spec            mcpserverv1.MCPServerSpec
+		annotations     map[string]string
+		wantDisplayName string
+		wantDescription string
+	}{
+		{
+			name:            "defaults to the name of the MCPServer",
+			wantDisplayName: mcpServerName,
+		},
+		{
+			name: "annotations",
+			annotations: map[string]string{
+				mcpserverv1.DisplayNameAnnotation: "Cluster Tools",
+				mcpserverv1.DescriptionAnnotation: "Tools for the cluster",
+			},
+			wantDisplayName: "Cluster Tools",
+			wantDescription: "Tools for the cluster",
+		},
+		{
+			name: "spec takes precedence over the annotations",
+			spec: mcpserverv1.MCPServerSpec{DisplayName: "Kubernetes Tools", Description: "Read pods and logs"},
+			annotations: map[string]string{
+				mcpserverv1.DisplayNameAnnotation: "Cluster Tools",
+				mcpserverv1.DescriptionAnnotation: "Tools for the cluster",
+			},
+			wantDisplayName: "Kubernetes Tools",
+			wantDescription: "Read pods and logs",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &mcpserverv1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace, Annotations: tt.annotations},
+				Spec:       tt.spec,
+			}
+			if got := DisplayName(cr); got != tt.wantDisplayName {
+				t.Errorf("DisplayName() = %q, want %q", got, tt.wantDisplayName)
+			}
+			if got := Description(cr); got != tt.wantDescription {
+				t.Errorf("Description() = %q, want %q", got, tt.wantDescription)
+			}
+		})
+	}
+}
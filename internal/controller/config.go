@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+const (
+	// ReasonInvalidConfig is set on the Available condition of an MCPServer whose spec.config cannot be rendered
+	// into the flags of its server.
+	ReasonInvalidConfig = "InvalidConfig"
+)
+
+// configParameterType is the type of the value of a spec.config option.
+type configParameterType string
+
+const (
+	configBoolean configParameterType = "boolean"
+	configInteger configParameterType = "integer"
+	configString  configParameterType = "string"
+	configList    configParameterType = "list"
+)
+
+// configParameter describes how a spec.config option is rendered into a flag of the MCP server.
+type configParameter struct {
+	Type configParameterType
+	// Flag is set to the value of the option. A boolean option adds the flag without a value when true.
+	Flag string
+	// Values are the values a string option accepts, any value is accepted when empty.
+	Values []string
+	// Min and Max bound the value of an integer option.
+	Min, Max int64
+}
+
+// kubernetesMCPServerConfig is the template of the options of the Kubernetes MCP server run by the default command.
+var kubernetesMCPServerConfig = map[string]configParameter{
+	"logLevel":            {Type: configInteger, Flag: "--log-level", Min: 0, Max: 9},
+	"readOnly":            {Type: configBoolean, Flag: "--read-only"},
+	"disableDestructive":  {Type: configBoolean, Flag: "--disable-destructive"},
+	"disableMultiCluster": {Type: configBoolean, Flag: "--disable-multi-cluster"},
+	"listOutput":          {Type: configString, Flag: "--list-output", Values: []string{"yaml", "table"}},
+	"toolsets":            {Type: configList, Flag: "--toolsets"},
+}
+
+// mcpServerArgs returns the args of the MCP server container: spec.args, or the default args, with the flags rendered
+// from spec.config, the flag of token passthrough and the SSE keep-alive flag. An error is returned when an option of
+// spec.config is unknown or has a value of the wrong type.
+func mcpServerArgs(cr *mcpserverv1.MCPServer) ([]string, error) {
+	args := slices.Clone(DefaultMCPDeploymentArgs)
+	if cr.Spec.Args != nil {
+		args = slices.Clone(cr.Spec.Args)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(cr.Spec.Config)) {
+		parameter, ok := kubernetesMCPServerConfig[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown config option %q, the server supports %s", key,
+				strings.Join(slices.Sorted(maps.Keys(kubernetesMCPServerConfig)), ", "))
+		}
+		value, err := parameter.render(cr.Spec.Config[key].Raw)
+		if err != nil {
+			return nil, fmt.Errorf("config option %q: %w", key, err)
+		}
+		args = withFlag(args, parameter, value)
+	}
+	if usesTokenPassthrough(cr) {
+		args = withFlag(args, configParameter{Type: configBoolean, Flag: requireOAuthFlag}, "true")
+	}
+	return withSSEKeepAlive(args, cr), nil
+}
+
+// render returns the value of the flag of p for the JSON value raw. A false boolean renders to an empty value.
+func (p configParameter) render(raw []byte) (string, error) {
+	switch p.Type {
+	case configBoolean:
+		var value bool
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return "", fmt.Errorf("%s is not a boolean", raw)
+		}
+		if !value {
+			return "", nil
+		}
+		return "true", nil
+	case configInteger:
+		var value int64
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return "", fmt.Errorf("%s is not an integer", raw)
+		}
+		if value < p.Min || value > p.Max {
+			return "", fmt.Errorf("%d is not between %d and %d", value, p.Min, p.Max)
+		}
+		return strconv.FormatInt(value, 10), nil
+	case configString:
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return "", fmt.Errorf("%s is not a string", raw)
+		}
+		if len(p.Values) > 0 && !slices.Contains(p.Values, value) {
+			return "", fmt.Errorf("%q is not one of %s", value, strings.Join(p.Values, ", "))
+		}
+		return value, nil
+	case configList:
+		var value []string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return "", fmt.Errorf("%s is not a list of strings", raw)
+		}
+		return strings.Join(value, ","), nil
+	}
+	return "", fmt.Errorf("unsupported type %s", p.Type)
+}
+
+// withFlag returns args with the flag of p set to value, replacing the flag when args already set it, e.g. the
+// --log-level of the default args. A boolean flag is removed when value is empty.
+func withFlag(args []string, p configParameter, value string) []string {
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == p.Flag && p.Type != configBoolean && i+1 < len(args):
+			args = slices.Delete(args, i, i+2)
+			i--
+		case args[i] == p.Flag || strings.HasPrefix(args[i], p.Flag+"="):
+			args = slices.Delete(args, i, i+1)
+			i--
+		}
+	}
+	if p.Type == configBoolean {
+		if value == "" {
+			return args
+		}
+		return append(args, p.Flag)
+	}
+	return append(args, p.Flag, value)
+}
+
+// getConfigCondition returns the Available condition of an MCPServer whose spec.config cannot be rendered, or nil
+// when it can.
+func getConfigCondition(cr *mcpserverv1.MCPServer) *metav1.Condition {
+	if len(cr.Spec.Config) == 0 {
+		return nil
+	}
+	if _, err := mcpServerArgs(cr); err != nil {
+		return &metav1.Condition{
+			Type:    OverallAvailable,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonInvalidConfig,
+			Message: fmt.Sprintf("Invalid spec.config: %v", err),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+func Test_mcpServerArgs(t *testing.T) {
+	value := func(raw string) apiextensionsv1.JSON {
+		return apiextensionsv1.JSON{Raw: []byte(raw)}
+	}
+
+	tests := []struct {
+		name        string
+		args        []string
+		config      map[string]apiextensionsv1.JSON
+		passthrough bool
+		sse         *mcpserverv1.SSE
+		want        []string
+		wantErr     bool
+	}{
+		{
+			name: "default args",
+			want: DefaultMCPDeploymentArgs,
+		},
+		{
+			name: "args without config",
+			args: []string{"--port", "8000"},
+			want: []string{"--port", "8000"},
+		},
+		{
+			name: "options of the default server",
+			config: map[string]apiextensionsv1.JSON{
+				"readOnly":   value("true"),
+				"toolsets":   value(`["core","helm"]`),
+				"listOutput": value(`"table"`),
+				"logLevel":   value("2"),
+			},
+			want: []string{"--port", "8000", "--list-output", "table", "--log-level", "2", "--read-only", "--toolsets", "core,helm"},
+		},
+		{
+			name:   "flags of args replaced",
+			args:   []string{"--port", "8000", "--read-only", "--log-level=5"},
+			config: map[string]apiextensionsv1.JSON{"readOnly": value("false"), "logLevel": value("1")},
+			want:   []string{"--port", "8000", "--log-level", "1"},
+		},
+		{
+			name:        "token passthrough",
+			config:      map[string]apiextensionsv1.JSON{"readOnly": value("true")},
+			passthrough: true,
+			want:        []string{"--port", "8000", "--log-level", "9", "--read-only", "--require-oauth"},
+		},
+		{
+			name: "SSE keep-alive flag",
+			args: []string{"--port", "8000", "--keep-alive=60"},
+			sse: &mcpserverv1.SSE{
+				KeepAliveInterval: &metav1.Duration{Duration: 15 * time.Second},
+				KeepAliveFlag:     "--keep-alive",
+			},
+			want: []string{"--port", "8000", "--keep-alive", "15"},
+		},
+		{
+			name: "SSE keep-alive without a flag",
+			sse:  &mcpserverv1.SSE{KeepAliveInterval: &metav1.Duration{Duration: 15 * time.Second}},
+			want: DefaultMCPDeploymentArgs,
+		},
+		{
+			name:    "unknown option",
+			config:  map[string]apiextensionsv1.JSON{"verbose": value("true")},
+			wantErr: true,
+		},
+		{
+			name:    "wrong type",
+			config:  map[string]apiextensionsv1.JSON{"readOnly": value(`"yes"`)},
+			wantErr: true,
+		},
+		{
+			name:    "value out of range",
+			config:  map[string]apiextensionsv1.JSON{"logLevel": value("12")},
+			wantErr: true,
+		},
+		{
+			name:    "value not allowed",
+			config:  map[string]apiextensionsv1.JSON{"listOutput": value(`"json"`)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &mcpserverv1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+				Spec:       mcpserverv1.MCPServerSpec{Image: mcpServerImage, Args: tt.args, Config: tt.config, SSE: tt.sse},
+			}
+			if tt.passthrough {
+				cr.Spec.KubernetesAccess = &mcpserverv1.KubernetesAccess{Mode: mcpserverv1.KubernetesAccessTokenPassthrough}
+			}
+			got, err := mcpServerArgs(cr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("mcpServerArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) && !tt.wantErr {
+				t.Errorf("mcpServerArgs() = %v, want %v", got, tt.want)
+			}
+			condition := getConfigCondition(cr)
+			if (condition != nil) != tt.wantErr {
+				t.Errorf("getConfigCondition() = %v, want a condition %v", condition, tt.wantErr)
+			}
+			if condition != nil && condition.Reason != ReasonInvalidConfig {
+				t.Errorf("getConfigCondition() reason = %s, want %s", condition.Reason, ReasonInvalidConfig)
+			}
+		})
+	}
+	if !reflect.DeepEqual(DefaultMCPDeploymentArgs, []string{"--port", "8000", "--log-level", "9"}) {
+		t.Errorf("the default args were changed to %v", DefaultMCPDeploymentArgs)
+	}
+}
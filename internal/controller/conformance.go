@@ -0,0 +1,233 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/internal/conformance"
+)
+
+const (
+	// ReasonConformanceCheckFailed is the reason of the event emitted when the conformance suite fails for a
+	// rollout.
+	ReasonConformanceCheckFailed = "ConformanceCheckFailed"
+
+	// conformanceCheckRevisionAnnotation records the Deployment revision a conformance check Job was created for.
+	conformanceCheckRevisionAnnotation = "mcpserver.opendatahub.io/conformance-check-revision"
+
+	// conformanceCheckGenerationAnnotation records the MCPServer generation a conformance check Job was created
+	// for, so that changes to spec.conformanceCheck run the suite again.
+	conformanceCheckGenerationAnnotation = "mcpserver.opendatahub.io/conformance-check-generation"
+
+	// deploymentRevisionAnnotation is set by the Deployment controller and counts the rollouts of a Deployment.
+	deploymentRevisionAnnotation = "deployment.kubernetes.io/revision"
+)
+
+// conformanceCheckSuffix is the suffix of the name of the conformance check Job of an MCP server.
+const conformanceCheckSuffix = "conformance-check"
+
+func conformanceCheckJobName(cr *mcpserverv1.MCPServer) string {
+	return childName(cr, conformanceCheckSuffix)
+}
+
+// conformanceCheckArgs returns the arguments of the conformance suite, which runs against the in-cluster
+// Service URL like the connection test.
+func conformanceCheckArgs(cr *mcpserverv1.MCPServer) []string {
+	args := connectionTestArgs(cr)
+	spec := cr.Spec.ConformanceCheck
+	if spec.Tool == "" {
+		return args
+	}
+	args = append(args, "--tool", spec.Tool)
+	keys := make([]string, 0, len(spec.Arguments))
+	for key := range spec.Arguments {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		args = append(args, "--argument", fmt.Sprintf("%s=%s", key, spec.Arguments[key]))
+	}
+	return args
+}
+
+// rolloutComplete reports whether every replica of deployment runs its current pod template and is available.
+func rolloutComplete(deployment *appsv1.Deployment) bool {
+	replicas := ptr.Deref(deployment.Spec.Replicas, 1)
+	return deployment.Status.ObservedGeneration >= deployment.Generation &&
+		deployment.Status.UpdatedReplicas == replicas &&
+		deployment.Status.Replicas == replicas &&
+		deployment.Status.AvailableReplicas == replicas
+}
+
+// reconcileConformanceCheck runs the conformance suite once the rollout of each Deployment revision is complete,
+// and records its results in the status of cr.
+func (r *MCPServerReconciler) reconcileConformanceCheck(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) error {
+	if cr.Spec.ConformanceCheck == nil {
+		if cr.Status.ConformanceCheck == nil {
+			return nil
+		}
+		cr.Status.ConformanceCheck = nil
+		return r.deleteChild(ctx, cli, cr, &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: conformanceCheckJobName(cr), Namespace: cr.Namespace},
+		}, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	}
+
+	deployment := &appsv1.Deployment{}
+	err := cli.Get(ctx, client.ObjectKey{Name: resourceName(cr), Namespace: cr.Namespace}, deployment)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	revision := deployment.Annotations[deploymentRevisionAnnotation]
+
+	if usesTokenPassthrough(cr) {
+		cr.Status.ConformanceCheck = &mcpserverv1.ConformanceCheckStatus{
+			Result:             mcpserverv1.ConformanceSkipped,
+			Revision:           revision,
+			ObservedGeneration: cr.Generation,
+			Message:            tokenPassthroughSkipped,
+		}
+		return r.deleteChild(ctx, cli, cr, &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: conformanceCheckJobName(cr), Namespace: cr.Namespace},
+		}, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	}
+
+	job := &batchv1.Job{}
+	err = cli.Get(ctx, client.ObjectKey{Name: conformanceCheckJobName(cr), Namespace: cr.Namespace}, job)
+	if err != nil && !k8serr.IsNotFound(err) {
+		return err
+	}
+
+	if k8serr.IsNotFound(err) {
+		// The suite only runs against pods of a single revision, and not at all while the server is scaled down.
+		if !rolloutComplete(deployment) || ptr.Deref(deployment.Spec.Replicas, 1) == 0 {
+			return nil
+		}
+		// A finished suite for the current revision is kept in status; don't run it again.
+		if status := cr.Status.ConformanceCheck; status != nil && status.Revision == revision &&
+			status.ObservedGeneration == cr.Generation && status.Result != mcpserverv1.ConformanceRunning {
+			return nil
+		}
+		return r.createConformanceCheckJob(ctx, cli, cr, revision)
+	}
+
+	if job.Annotations[conformanceCheckRevisionAnnotation] != revision ||
+		job.Annotations[conformanceCheckGenerationAnnotation] != strconv.FormatInt(cr.Generation, 10) {
+		// The Job checked a previous rollout, remove it so a new one is created once the rollout completes.
+		err = cli.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+		if err != nil && !k8serr.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	status := &mcpserverv1.ConformanceCheckStatus{
+		Result:             mcpserverv1.ConformanceRunning,
+		Revision:           revision,
+		ObservedGeneration: cr.Generation,
+	}
+	if job.Status.Succeeded == 0 && job.Status.Failed == 0 {
+		cr.Status.ConformanceCheck = status
+		return nil
+	}
+
+	report, err := r.getJobTerminationMessage(ctx, cli, job)
+	if err != nil {
+		return err
+	}
+	applyConformanceReport(status, report)
+
+	status.CompletionTime = job.Status.CompletionTime
+	if status.CompletionTime == nil {
+		now := metav1.Now()
+		status.CompletionTime = &now
+	}
+	previous := cr.Status.ConformanceCheck
+	if previous != nil && previous.Result == status.Result && previous.Revision == revision &&
+		previous.ObservedGeneration == cr.Generation {
+		// Keep the recorded completion time stable across reconciles.
+		status.CompletionTime = previous.CompletionTime
+	} else if status.Result == mcpserverv1.ConformanceFailed && r.Recorder != nil {
+		r.Recorder.Event(cr, corev1.EventTypeWarning, ReasonConformanceCheckFailed, conformanceFailureMessage(status))
+	}
+	cr.Status.ConformanceCheck = status
+
+	return nil
+}
+
+// applyConformanceReport records the checks of the report of a finished conformance suite, and their result, in
+// status. A report that cannot be parsed fails the suite.
+func applyConformanceReport(status *mcpserverv1.ConformanceCheckStatus, report string) {
+	checks, err := conformance.ParseReport(report)
+	if err != nil {
+		if len(report) > connectionTestMessageLimit {
+			report = report[:connectionTestMessageLimit]
+		}
+		status.Result = mcpserverv1.ConformanceFailed
+		status.Message = fmt.Sprintf("The conformance suite did not report its checks: %s", report)
+		return
+	}
+	status.Checks = checks
+	status.Result = conformance.Result(checks)
+}
+
+func (r *MCPServerReconciler) createConformanceCheckJob(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer, revision string) error {
+	if r.OperatorImage == "" {
+		cr.Status.ConformanceCheck = &mcpserverv1.ConformanceCheckStatus{
+			Result:             mcpserverv1.ConformanceFailed,
+			Revision:           revision,
+			ObservedGeneration: cr.Generation,
+			Message:            "The operator image is not configured, unable to run the conformance suite",
+		}
+		return nil
+	}
+
+	job := r.newOperatorJob(cr, conformanceCheckJobName(cr), conformance.Command, conformanceCheckArgs(cr), nil)
+	mountConnectionTestCredentials(job, cr)
+	job.Annotations = map[string]string{
+		conformanceCheckRevisionAnnotation:   revision,
+		conformanceCheckGenerationAnnotation: strconv.FormatInt(cr.Generation, 10),
+	}
+
+	// Set MCPServer to own the job.
+	err := r.createChild(ctx, cli, cr, job)
+	if err != nil {
+		return err
+	}
+
+	cr.Status.ConformanceCheck = &mcpserverv1.ConformanceCheckStatus{
+		Result:             mcpserverv1.ConformanceRunning,
+		Revision:           revision,
+		ObservedGeneration: cr.Generation,
+	}
+	return nil
+}
+
+// conformanceFailureMessage names the failed checks of status for the event of a failed suite.
+func conformanceFailureMessage(status *mcpserverv1.ConformanceCheckStatus) string {
+	if status.Message != "" {
+		return status.Message
+	}
+	var failed []string
+	for _, check := range status.Checks {
+		if check.Result == mcpserverv1.ConformanceFailed {
+			failed = append(failed, fmt.Sprintf("%s (%s)", check.Name, check.Message))
+		}
+	}
+	return fmt.Sprintf("The conformance suite failed for revision %s of the Deployment: %s", status.Revision,
+		strings.Join(failed, ", "))
+}
@@ -0,0 +1,186 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+func TestMCPServerReconciler_reconcileConformanceCheck(t *testing.T) {
+	fakeScheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(fakeScheme); err != nil {
+		t.Fatalf("failed to add client-go scheme: %v", err)
+	}
+	if err := mcpserverv1.AddToScheme(fakeScheme); err != nil {
+		t.Fatalf("failed to add mcpserverv1 scheme: %v", err)
+	}
+
+	newMCPServer := func() *mcpserverv1.MCPServer {
+		return &mcpserverv1.MCPServer{
+			ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace, Generation: 2},
+			Spec: mcpserverv1.MCPServerSpec{
+				Image:            mcpServerImage,
+				ConformanceCheck: &mcpserverv1.ConformanceCheck{Tool: "echo", Arguments: map[string]string{"text": "hi"}},
+			},
+		}
+	}
+	newDeployment := func(revision string, updated int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        mcpServerName,
+				Namespace:   testNamespace,
+				Annotations: map[string]string{deploymentRevisionAnnotation: revision},
+			},
+			Spec: appsv1.DeploymentSpec{Replicas: ptr.To[int32](2)},
+			Status: appsv1.DeploymentStatus{
+				Replicas:          2,
+				UpdatedReplicas:   updated,
+				AvailableReplicas: 2,
+			},
+		}
+	}
+	newJob := func(revision string, succeeded, failed int32) *batchv1.Job {
+		return &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            mcpServerName + "-conformance-check",
+				Namespace:       testNamespace,
+				OwnerReferences: []metav1.OwnerReference{{Kind: "MCPServer", Name: mcpServerName, Controller: ptr.To(true)}},
+				Annotations: map[string]string{
+					conformanceCheckRevisionAnnotation:   revision,
+					conformanceCheckGenerationAnnotation: "2",
+				},
+			},
+			Status: batchv1.JobStatus{Succeeded: succeeded, Failed: failed},
+		}
+	}
+	newPod := func(report string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      mcpServerName + "-conformance-check-abcde",
+				Namespace: testNamespace,
+				Labels:    map[string]string{batchv1.JobNameLabel: mcpServerName + "-conformance-check"},
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{
+					State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Message: report}},
+				}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		objects    []client.Object
+		wantJob    bool
+		wantResult mcpserverv1.ConformanceResult
+		wantChecks int
+		wantEvent  bool
+		// passthrough sets kubernetesAccess.mode TokenPassthrough.
+		passthrough bool
+	}{
+		{
+			name:    "Verify that no Job is created while the rollout is in progress",
+			objects: []client.Object{newDeployment("3", 1)},
+		},
+		{
+			name:       "Verify that a Job is created once the rollout is complete",
+			objects:    []client.Object{newDeployment("3", 2)},
+			wantJob:    true,
+			wantResult: mcpserverv1.ConformanceRunning,
+		},
+		{
+			name: "Verify that a passed suite records its checks in status",
+			objects: []client.Object{newDeployment("3", 2), newJob("3", 1, 0),
+				newPod(`[{"name":"initialize","result":"Passed"},{"name":"tools/call","result":"Passed"}]`)},
+			wantJob:    true,
+			wantResult: mcpserverv1.ConformancePassed,
+			wantChecks: 2,
+		},
+		{
+			name: "Verify that a failed suite is reported with an event",
+			objects: []client.Object{newDeployment("3", 2), newJob("3", 0, 1),
+				newPod(`[{"name":"initialize","result":"Passed"},{"name":"unknown-method","result":"Failed","message":"succeeded"}]`)},
+			wantJob:    true,
+			wantResult: mcpserverv1.ConformanceFailed,
+			wantChecks: 2,
+			wantEvent:  true,
+		},
+		{
+			name:       "Verify that a suite without a report is failed",
+			objects:    []client.Object{newDeployment("3", 2), newJob("3", 0, 1), newPod("exec format error")},
+			wantJob:    true,
+			wantResult: mcpserverv1.ConformanceFailed,
+			wantEvent:  true,
+		},
+		{
+			name:        "Verify that the suite is skipped for a server with token passthrough",
+			objects:     []client.Object{newDeployment("3", 2), newJob("3", 1, 0)},
+			wantResult:  mcpserverv1.ConformanceSkipped,
+			passthrough: true,
+		},
+		{
+			name:    "Verify that the Job of a previous rollout is deleted",
+			objects: []client.Object{newDeployment("4", 1), newJob("3", 1, 0)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(tt.objects...).Build()
+			recorder := record.NewFakeRecorder(10)
+			r := &MCPServerReconciler{
+				Client:        cli,
+				Scheme:        fakeScheme,
+				OperatorImage: "operator-image",
+				Recorder:      recorder,
+			}
+			cr := newMCPServer()
+			if tt.passthrough {
+				cr.Spec.KubernetesAccess = &mcpserverv1.KubernetesAccess{Mode: mcpserverv1.KubernetesAccessTokenPassthrough}
+			}
+			if err := r.reconcileConformanceCheck(context.Background(), cli, cr); err != nil {
+				t.Fatalf("reconcileConformanceCheck() error = %v", err)
+			}
+
+			job := &batchv1.Job{}
+			err := cli.Get(context.Background(), client.ObjectKey{Name: conformanceCheckJobName(cr), Namespace: testNamespace}, job)
+			if (err == nil) != tt.wantJob {
+				t.Errorf("conformance check Job exists = %v, want %v", err == nil, tt.wantJob)
+			}
+			if err == nil && tt.wantResult == mcpserverv1.ConformanceRunning {
+				if args := job.Spec.Template.Spec.Containers[0].Args; args[len(args)-1] != "text=hi" {
+					t.Errorf("conformance check args = %v, want the arguments of the tool", args)
+				}
+			}
+			if (len(recorder.Events) > 0) != tt.wantEvent {
+				t.Errorf("event emitted = %v, want %v", len(recorder.Events) > 0, tt.wantEvent)
+			}
+
+			if tt.wantResult == "" {
+				if cr.Status.ConformanceCheck != nil {
+					t.Errorf("status.conformanceCheck = %+v, want none", cr.Status.ConformanceCheck)
+				}
+				return
+			}
+			if cr.Status.ConformanceCheck == nil {
+				t.Fatalf("status.conformanceCheck is nil, want result %v", tt.wantResult)
+			}
+			if got := cr.Status.ConformanceCheck; got.Result != tt.wantResult || got.Revision != "3" ||
+				len(got.Checks) != tt.wantChecks {
+				t.Errorf("status.conformanceCheck = %+v, want result %v for revision 3 with %d checks", got,
+					tt.wantResult, tt.wantChecks)
+			}
+		})
+	}
+}
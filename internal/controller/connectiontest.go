@@ -0,0 +1,276 @@
+package controller
+
+import (
+	"context"
+	"strconv"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/internal/connectiontest"
+)
+
+const (
+	// connectionTestGenerationAnnotation records the MCPServer generation a connection test Job was created for.
+	connectionTestGenerationAnnotation = "mcpserver.opendatahub.io/connection-test-generation"
+
+	// connectionTestMessageLimit caps the output snippet copied into the MCPServer status.
+	connectionTestMessageLimit = 1024
+
+	// serviceAccountTokenPath is where the token of the pod's service account is mounted.
+	serviceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
+// connectionTestSuffix is the suffix of the name of the connection test Job of an MCP server.
+const connectionTestSuffix = "connection-test"
+
+func connectionTestJobName(cr *mcpserverv1.MCPServer) string {
+	return childName(cr, connectionTestSuffix)
+}
+
+// connectionTestURL returns the URL the connection test connects to, the in-cluster Service URL of a Managed
+// MCP server or the URL of an External one.
+func connectionTestURL(cr *mcpserverv1.MCPServer) string {
+	if isExternal(cr) {
+		return cr.Spec.URL
+	}
+	return serviceURL(cr)
+}
+
+// connectionTestCredentials returns the token of an External MCP server as spec.credentialsExposure asks, read
+// from a file by default, and the token clients of an MCP server with token authentication present.
+func connectionTestCredentials(cr *mcpserverv1.MCPServer) exposedSecret {
+	if usesTokenAuth(cr) {
+		return authToken(cr)
+	}
+	if !isExternal(cr) {
+		return exposedSecret{}
+	}
+	return exposeSecret(cr.Spec.CredentialsSecretRef, cr.Spec.CredentialsExposure, mcpserverv1.SecretExposureFile,
+		connectiontest.TokenEnv, credentialsVolumeName)
+}
+
+// connectionTestArgs returns the arguments of the connection test. The proxy of a Proxy MCP server only admits
+// Kubernetes identities, and does not pass them on, so the test authenticates with the token of its service account.
+func connectionTestArgs(cr *mcpserverv1.MCPServer) []string {
+	args := []string{"--url", connectionTestURL(cr)}
+	if isProxy(cr) {
+		args = append(args, "--token-file", serviceAccountTokenPath)
+	}
+	if credentials := connectionTestCredentials(cr); credentials.Mount != nil {
+		args = append(args, "--token-file", credentials.Path)
+	}
+	return args
+}
+
+// connectionTestEnv passes the credentials of an External MCP server exposed in Env mode to the connection test.
+func connectionTestEnv(cr *mcpserverv1.MCPServer) []corev1.EnvVar {
+	if credentials := connectionTestCredentials(cr); credentials.Env != nil {
+		return []corev1.EnvVar{*credentials.Env}
+	}
+	return nil
+}
+
+func (r *MCPServerReconciler) reconcileMCPServerConnectionTest(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) error {
+	if !cr.Spec.TestConnection {
+		return nil
+	}
+	if usesTokenPassthrough(cr) {
+		cr.Status.ConnectionTest = nil
+		return r.deleteChild(ctx, cli, cr, &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: connectionTestJobName(cr), Namespace: cr.Namespace},
+		}, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	}
+
+	// The test only makes sense once the server pods are up.
+	if !isExternal(cr) && !meta.IsStatusConditionTrue(cr.Status.Conditions, DeploymentAvailable) {
+		return nil
+	}
+
+	job := &batchv1.Job{}
+	err := cli.Get(ctx, client.ObjectKey{Name: connectionTestJobName(cr), Namespace: cr.Namespace}, job)
+	if err != nil && !k8serr.IsNotFound(err) {
+		return err
+	}
+
+	if k8serr.IsNotFound(err) {
+		// A finished test for the current generation is kept in status; don't run it again.
+		if cr.Status.ConnectionTest != nil && cr.Status.ConnectionTest.ObservedGeneration == cr.Generation &&
+			cr.Status.ConnectionTest.Result != mcpserverv1.ConnectionTestRunning {
+			return nil
+		}
+		return r.createConnectionTestJob(ctx, cli, cr)
+	}
+
+	if job.Annotations[connectionTestGenerationAnnotation] != strconv.FormatInt(cr.Generation, 10) {
+		// The Job tested a previous generation, remove it so a new one is created.
+		err = cli.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+		if err != nil && !k8serr.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	result := mcpserverv1.ConnectionTestRunning
+	switch {
+	case job.Status.Succeeded > 0:
+		result = mcpserverv1.ConnectionTestSucceeded
+	case job.Status.Failed > 0:
+		result = mcpserverv1.ConnectionTestFailed
+	}
+
+	status := &mcpserverv1.ConnectionTestStatus{
+		Result:             result,
+		ObservedGeneration: cr.Generation,
+	}
+	if result != mcpserverv1.ConnectionTestRunning {
+		message, err := r.getConnectionTestOutput(ctx, cli, job)
+		if err != nil {
+			return err
+		}
+		status.Message = message
+		if tools, ok := connectiontest.ToolCount(message); ok && result == mcpserverv1.ConnectionTestSucceeded {
+			status.Tools = ptr.To(tools)
+		}
+		status.CompletionTime = job.Status.CompletionTime
+		if status.CompletionTime == nil {
+			now := metav1.Now()
+			status.CompletionTime = &now
+		}
+		if cr.Status.ConnectionTest != nil && cr.Status.ConnectionTest.Result == result &&
+			cr.Status.ConnectionTest.ObservedGeneration == cr.Generation {
+			// Keep the recorded completion time stable across reconciles.
+			status.CompletionTime = cr.Status.ConnectionTest.CompletionTime
+		}
+	}
+	cr.Status.ConnectionTest = status
+
+	return nil
+}
+
+func (r *MCPServerReconciler) createConnectionTestJob(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) error {
+	if r.OperatorImage == "" {
+		cr.Status.ConnectionTest = &mcpserverv1.ConnectionTestStatus{
+			Result:             mcpserverv1.ConnectionTestFailed,
+			Message:            "The operator image is not configured, unable to run the connection test",
+			ObservedGeneration: cr.Generation,
+		}
+		return nil
+	}
+
+	job := r.newOperatorJob(cr, connectionTestJobName(cr), connectiontest.Command, connectionTestArgs(cr), connectionTestEnv(cr))
+	job.Annotations = map[string]string{
+		connectionTestGenerationAnnotation: strconv.FormatInt(cr.Generation, 10),
+	}
+	mountConnectionTestCredentials(job, cr)
+
+	// Set MCPServer to own the job.
+	err := r.createChild(ctx, cli, cr, job)
+	if err != nil {
+		return err
+	}
+
+	cr.Status.ConnectionTest = &mcpserverv1.ConnectionTestStatus{
+		Result:             mcpserverv1.ConnectionTestRunning,
+		ObservedGeneration: cr.Generation,
+	}
+	return nil
+}
+
+// mountConnectionTestCredentials mounts the credentials of cr exposed as a file into job.
+func mountConnectionTestCredentials(job *batchv1.Job, cr *mcpserverv1.MCPServer) {
+	if credentials := connectionTestCredentials(cr); credentials.Volume != nil {
+		podSpec := &job.Spec.Template.Spec
+		podSpec.Volumes = append(podSpec.Volumes, *credentials.Volume)
+		podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, *credentials.Mount)
+	}
+}
+
+// newOperatorJob returns a Job of cr that runs a subcommand of the operator image once, as the connection test
+// and the conformance suite do.
+func (r *MCPServerReconciler) newOperatorJob(cr *mcpserverv1.MCPServer, name, command string, args []string, env []corev1.EnvVar) *batchv1.Job {
+	// The Job is transient and recreated on demand, so it has no place in a backup.
+	jobLabels := map[string]string{
+		mcpServerAppLabelKey:         resourceName(cr),
+		veleroExcludeFromBackupLabel: "true",
+	}
+
+	return &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "batch/v1",
+			Kind:       "Job",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: cr.Namespace,
+			Labels:    jobLabels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:          ptr.To[int32](0),
+			ActiveDeadlineSeconds: ptr.To[int64](120),
+			// The pod deliberately does not carry the MCP server label, which would put it behind the
+			// MCP server Service and make it look like a server pod.
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					HostAliases:   cr.Spec.HostAliases,
+					SecurityContext: &corev1.PodSecurityContext{
+						RunAsNonRoot: ptr.To(true),
+						SeccompProfile: &corev1.SeccompProfile{
+							Type: corev1.SeccompProfileTypeRuntimeDefault,
+						},
+					},
+					Containers: []corev1.Container{{
+						Name:    command,
+						Image:   r.OperatorImage,
+						Command: []string{"/manager", command},
+						Args:    args,
+						Env:     env,
+						SecurityContext: &corev1.SecurityContext{
+							AllowPrivilegeEscalation: ptr.To(false),
+							Capabilities: &corev1.Capabilities{
+								Drop: []corev1.Capability{"ALL"},
+							},
+						},
+						TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+					}},
+				},
+			},
+		},
+	}
+}
+
+// getConnectionTestOutput returns the termination message of the connection test Pod.
+func (r *MCPServerReconciler) getConnectionTestOutput(ctx context.Context, cli client.Client, job *batchv1.Job) (string, error) {
+	message, err := r.getJobTerminationMessage(ctx, cli, job)
+	if len(message) > connectionTestMessageLimit {
+		message = message[:connectionTestMessageLimit]
+	}
+	return message, err
+}
+
+// getJobTerminationMessage returns the termination message of the Pod of job. The pods of Jobs are not cached, as
+// they do not carry the MCP server label.
+func (r *MCPServerReconciler) getJobTerminationMessage(ctx context.Context, cli client.Client, job *batchv1.Job) (string, error) {
+	pods := &corev1.PodList{}
+	err := r.uncachedReader(cli).List(ctx, pods, client.InNamespace(job.Namespace), client.MatchingLabels{batchv1.JobNameLabel: job.Name})
+	if err != nil {
+		return "", err
+	}
+
+	for _, pod := range pods.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.State.Terminated != nil && status.State.Terminated.Message != "" {
+				return status.State.Terminated.Message, nil
+			}
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,168 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMCPServerReconciler_reconcileMCPServerConnectionTest(t *testing.T) {
+	fakeScheme := runtime.NewScheme()
+	err := clientgoscheme.AddToScheme(fakeScheme)
+	if err != nil {
+		t.Errorf("failed to add client-go scheme: %v", err)
+	}
+	err = mcpserverv1.AddToScheme(fakeScheme)
+	if err != nil {
+		t.Errorf("failed to add mcpserverv1 scheme: %v", err)
+	}
+
+	newMCPServer := func(testConnection bool) *mcpserverv1.MCPServer {
+		return &mcpserverv1.MCPServer{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       mcpServerName,
+				Namespace:  testNamespace,
+				Generation: 2,
+			},
+			Spec: mcpserverv1.MCPServerSpec{
+				Image:          mcpServerImage,
+				TestConnection: testConnection,
+			},
+			Status: mcpserverv1.MCPServerStatus{
+				Conditions: []metav1.Condition{
+					{Type: DeploymentAvailable, Status: metav1.ConditionTrue},
+				},
+			},
+		}
+	}
+
+	jobForGeneration := func(generation string, succeeded, failed int32) *batchv1.Job {
+		return &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            mcpServerName + "-connection-test",
+				Namespace:       testNamespace,
+				OwnerReferences: []metav1.OwnerReference{{Kind: "MCPServer", Name: mcpServerName, Controller: ptr.To(true)}},
+				Annotations:     map[string]string{connectionTestGenerationAnnotation: generation},
+			},
+			Status: batchv1.JobStatus{
+				Succeeded: succeeded,
+				Failed:    failed,
+			},
+		}
+	}
+
+	testPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mcpServerName + "-connection-test-abcde",
+			Namespace: testNamespace,
+			Labels:    map[string]string{batchv1.JobNameLabel: mcpServerName + "-connection-test"},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{
+				State: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{
+						Message: "initialize succeeded: server fake 1.0.0, protocol 2024-11-05",
+					},
+				},
+			}},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		cli         client.Client
+		cr          *mcpserverv1.MCPServer
+		wantJob     bool
+		wantResult  mcpserverv1.ConnectionTestResult
+		wantMessage string
+	}{
+		{
+			name:    "Verify that no Job is created when testConnection is disabled",
+			cli:     fake.NewClientBuilder().WithScheme(fakeScheme).Build(),
+			cr:      newMCPServer(false),
+			wantJob: false,
+		},
+		{
+			name:       "Verify that a Job is created and reported as running when testConnection is enabled",
+			cli:        fake.NewClientBuilder().WithScheme(fakeScheme).Build(),
+			cr:         newMCPServer(true),
+			wantJob:    true,
+			wantResult: mcpserverv1.ConnectionTestRunning,
+		},
+		{
+			name:        "Verify that a succeeded Job records its output in status",
+			cli:         fake.NewClientBuilder().WithScheme(fakeScheme).WithRuntimeObjects(jobForGeneration("2", 1, 0), testPod).Build(),
+			cr:          newMCPServer(true),
+			wantJob:     true,
+			wantResult:  mcpserverv1.ConnectionTestSucceeded,
+			wantMessage: "initialize succeeded: server fake 1.0.0, protocol 2024-11-05",
+		},
+		{
+			name:       "Verify that a failed Job is reported as failed",
+			cli:        fake.NewClientBuilder().WithScheme(fakeScheme).WithRuntimeObjects(jobForGeneration("2", 0, 1)).Build(),
+			cr:         newMCPServer(true),
+			wantJob:    true,
+			wantResult: mcpserverv1.ConnectionTestFailed,
+		},
+		{
+			name: "Verify that the Job of a server with token passthrough is deleted",
+			cli:  fake.NewClientBuilder().WithScheme(fakeScheme).WithRuntimeObjects(jobForGeneration("2", 1, 0)).Build(),
+			cr: func() *mcpserverv1.MCPServer {
+				cr := newMCPServer(true)
+				cr.Spec.KubernetesAccess = &mcpserverv1.KubernetesAccess{Mode: mcpserverv1.KubernetesAccessTokenPassthrough}
+				return cr
+			}(),
+			wantJob: false,
+		},
+		{
+			name:    "Verify that a Job for a previous generation is deleted",
+			cli:     fake.NewClientBuilder().WithScheme(fakeScheme).WithRuntimeObjects(jobForGeneration("1", 1, 0)).Build(),
+			cr:      newMCPServer(true),
+			wantJob: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &MCPServerReconciler{
+				Client:        tt.cli,
+				Scheme:        fakeScheme,
+				OperatorImage: "operator-image",
+			}
+			if err := r.reconcileMCPServerConnectionTest(context.Background(), tt.cli, tt.cr); err != nil {
+				t.Fatalf("reconcileMCPServerConnectionTest() error = %v", err)
+			}
+
+			job := &batchv1.Job{}
+			err := tt.cli.Get(context.Background(), types.NamespacedName{Name: connectionTestJobName(tt.cr), Namespace: testNamespace}, job)
+			if (err == nil) != tt.wantJob {
+				t.Errorf("connection test Job exists = %v, want %v", err == nil, tt.wantJob)
+			}
+			if _, ok := job.Spec.Template.Labels[mcpServerAppLabelKey]; err == nil && ok {
+				t.Errorf("connection test pod labels = %v, want no MCP server label", job.Spec.Template.Labels)
+			}
+
+			if tt.wantResult == "" {
+				return
+			}
+			if tt.cr.Status.ConnectionTest == nil {
+				t.Fatalf("status.connectionTest is nil, want result %v", tt.wantResult)
+			}
+			if tt.cr.Status.ConnectionTest.Result != tt.wantResult {
+				t.Errorf("status.connectionTest.result = %v, want %v", tt.cr.Status.ConnectionTest.Result, tt.wantResult)
+			}
+			if tt.cr.Status.ConnectionTest.Message != tt.wantMessage {
+				t.Errorf("status.connectionTest.message = %v, want %v", tt.cr.Status.ConnectionTest.Message, tt.wantMessage)
+			}
+		})
+	}
+}
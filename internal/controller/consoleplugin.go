@@ -0,0 +1,264 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	consolev1 "github.com/openshift/api/console/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/opendatahub-io/mcp-server-operator/internal/consoleplugin"
+)
+
+// +kubebuilder:rbac:groups=console.openshift.io,resources=consoleplugins,verbs=get;list;watch;create;update;patch
+
+const (
+	// ConsolePluginServerName is the name of the Deployment and Service serving the console plugin in the
+	// namespace of the operator.
+	ConsolePluginServerName = "mcp-console-plugin"
+	// consolePluginCertName is the name of the Secret the OpenShift service CA issues the serving certificate of
+	// the console plugin into.
+	consolePluginCertName = "mcp-console-plugin-cert"
+	// consolePluginCertPath is where the serving certificate is mounted.
+	consolePluginCertPath = "/var/serving-cert"
+	// consolePluginPort is the port the console plugin is served on.
+	consolePluginPort = 9443
+	// consolePluginLabelKey selects the pods of the console plugin.
+	consolePluginLabelKey = "mcpserver.opendatahub.io/console-plugin"
+	// servingCertAnnotation asks the OpenShift service CA for a serving certificate of a Service.
+	servingCertAnnotation = "service.beta.openshift.io/serving-cert-secret-name"
+)
+
+// consolePluginRequest is the request of the console plugin, which every change of its objects enqueues.
+var consolePluginRequest = reconcile.Request{NamespacedName: types.NamespacedName{Name: consoleplugin.Name}}
+
+// ConsolePluginReconciler serves the OpenShift console dynamic plugin of the operator from a Deployment in the
+// namespace of the operator, and registers it with the console in a ConsolePlugin that proxies the discovery API
+// with the token of the user. Enabling the plugin in the console operator configuration is left to the cluster
+// administrator.
+type ConsolePluginReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// Namespace is the namespace of the operator, which the plugin runs in.
+	Namespace string
+	// OperatorImage serves the plugin.
+	OperatorImage string
+	// DiscoveryService is the Service of the discovery API in the namespace of the operator, and DiscoveryPort
+	// its port.
+	DiscoveryService string
+	DiscoveryPort    int32
+}
+
+// Reconcile creates or updates the Deployment, Service and ConsolePlugin of the console plugin.
+func (r *ConsolePluginReconciler) Reconcile(ctx context.Context, _ ctrl.Request) (ctrl.Result, error) {
+	if r.OperatorImage == "" {
+		return ctrl.Result{}, fmt.Errorf("the operator image is not configured, unable to deploy the console plugin")
+	}
+	if err := r.reconcileServer(ctx); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, r.reconcileConsolePlugin(ctx)
+}
+
+func (r *ConsolePluginReconciler) labels() map[string]string {
+	return map[string]string{consolePluginLabelKey: ConsolePluginServerName}
+}
+
+// server returns the Deployment serving the console plugin with the serving certificate of its Service.
+func (r *ConsolePluginReconciler) server() *appsv1.Deployment {
+	labels := r.labels()
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ConsolePluginServerName,
+			Namespace: r.Namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To(int32(1)),
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					AutomountServiceAccountToken: ptr.To(false),
+					Containers: []corev1.Container{{
+						Name:    "console-plugin",
+						Image:   r.OperatorImage,
+						Command: []string{"/manager", consoleplugin.Command},
+						Args: []string{
+							"--port", strconv.Itoa(consolePluginPort),
+							"--cert-dir", consolePluginCertPath,
+						},
+						Ports: []corev1.ContainerPort{{
+							ContainerPort: consolePluginPort,
+							Name:          "https",
+						}},
+						ReadinessProbe: &corev1.Probe{
+							ProbeHandler: corev1.ProbeHandler{
+								HTTPGet: &corev1.HTTPGetAction{
+									Path:   consoleplugin.ManifestPath,
+									Port:   intstr.FromString("https"),
+									Scheme: corev1.URISchemeHTTPS,
+								},
+							},
+						},
+						SecurityContext: &corev1.SecurityContext{
+							AllowPrivilegeEscalation: ptr.To(false),
+							Capabilities: &corev1.Capabilities{
+								Drop: []corev1.Capability{"ALL"},
+							},
+						},
+						VolumeMounts: []corev1.VolumeMount{{
+							Name:      "serving-cert",
+							MountPath: consolePluginCertPath,
+							ReadOnly:  true,
+						}},
+					}},
+					Volumes: []corev1.Volume{{
+						Name: "serving-cert",
+						VolumeSource: corev1.VolumeSource{
+							Secret: &corev1.SecretVolumeSource{SecretName: consolePluginCertName},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// reconcileServer creates the Deployment and Service of the console plugin, and keeps the image of the plugin in
+// line with the operator, so that the page is updated along with the operator.
+func (r *ConsolePluginReconciler) reconcileServer(ctx context.Context) error {
+	desired := r.server()
+	if err := r.Create(ctx, desired); err != nil && !k8serr.IsAlreadyExists(err) {
+		return err
+	}
+	existing := &appsv1.Deployment{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(desired), existing); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if len(existing.Spec.Template.Spec.Containers) > 0 {
+		original := existing.DeepCopy()
+		container := &existing.Spec.Template.Spec.Containers[0]
+		container.Image = desired.Spec.Template.Spec.Containers[0].Image
+		container.Args = desired.Spec.Template.Spec.Containers[0].Args
+		if !equality.Semantic.DeepEqual(original.Spec, existing.Spec) {
+			logChildDiff(ctx, original, existing)
+			if err := r.Patch(ctx, existing, client.MergeFrom(original)); err != nil {
+				return err
+			}
+		}
+	}
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        ConsolePluginServerName,
+			Namespace:   r.Namespace,
+			Labels:      desired.Labels,
+			Annotations: map[string]string{servingCertAnnotation: consolePluginCertName},
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: desired.Spec.Selector.MatchLabels,
+			Ports: []corev1.ServicePort{{
+				Name:       "https",
+				Port:       consolePluginPort,
+				TargetPort: intstr.FromString("https"),
+			}},
+		},
+	}
+	if err := r.Create(ctx, service); err != nil && !k8serr.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// reconcileConsolePlugin creates the ConsolePlugin of the plugin, and keeps its backend and its proxy to the
+// discovery API in line.
+func (r *ConsolePluginReconciler) reconcileConsolePlugin(ctx context.Context) error {
+	spec := consolev1.ConsolePluginSpec{
+		DisplayName: "MCP Servers",
+		Backend: consolev1.ConsolePluginBackend{
+			Type: consolev1.Service,
+			Service: &consolev1.ConsolePluginService{
+				Name:      ConsolePluginServerName,
+				Namespace: r.Namespace,
+				Port:      consolePluginPort,
+				BasePath:  "/",
+			},
+		},
+		Proxy: []consolev1.ConsolePluginProxy{{
+			Alias:         consoleplugin.DiscoveryProxyAlias,
+			Authorization: consolev1.UserToken,
+			Endpoint: consolev1.ConsolePluginProxyEndpoint{
+				Type: consolev1.ProxyTypeService,
+				Service: &consolev1.ConsolePluginProxyServiceConfig{
+					Name:      r.DiscoveryService,
+					Namespace: r.Namespace,
+					Port:      r.DiscoveryPort,
+				},
+			},
+		}},
+	}
+
+	plugin := &consolev1.ConsolePlugin{}
+	err := r.Get(ctx, client.ObjectKey{Name: consoleplugin.Name}, plugin)
+	if k8serr.IsNotFound(err) {
+		plugin = &consolev1.ConsolePlugin{
+			ObjectMeta: metav1.ObjectMeta{Name: consoleplugin.Name, Labels: r.labels()},
+			Spec:       spec,
+		}
+		return r.Create(ctx, plugin)
+	}
+	if err != nil {
+		return err
+	}
+	original := plugin.DeepCopy()
+	plugin.Spec.DisplayName = spec.DisplayName
+	plugin.Spec.Backend = spec.Backend
+	plugin.Spec.Proxy = spec.Proxy
+	if equality.Semantic.DeepEqual(original.Spec, plugin.Spec) {
+		return nil
+	}
+	logChildDiff(ctx, original, plugin)
+	return r.Patch(ctx, plugin, client.MergeFrom(original))
+}
+
+// SetupWithManager sets up the controller with the Manager. The plugin is reconciled once on start, and again
+// whenever its ConsolePlugin or Deployment changes.
+func (r *ConsolePluginReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	enqueue := handler.EnqueueRequestsFromMapFunc(func(context.Context, client.Object) []reconcile.Request {
+		return []reconcile.Request{consolePluginRequest}
+	})
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("consoleplugin").
+		Watches(&consolev1.ConsolePlugin{}, enqueue, builder.WithPredicates(predicate.NewPredicateFuncs(
+			func(obj client.Object) bool {
+				return obj.GetName() == consoleplugin.Name
+			}))).
+		Watches(&appsv1.Deployment{}, enqueue, builder.WithPredicates(predicate.NewPredicateFuncs(
+			func(obj client.Object) bool {
+				return obj.GetNamespace() == r.Namespace && obj.GetLabels()[consolePluginLabelKey] != ""
+			}))).
+		WatchesRawSource(source.Func(func(_ context.Context, queue workqueue.TypedRateLimitingInterface[reconcile.Request]) error {
+			queue.Add(consolePluginRequest)
+			return nil
+		})).
+		Complete(r)
+}
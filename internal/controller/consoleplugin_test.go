@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	consolev1 "github.com/openshift/api/console/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/opendatahub-io/mcp-server-operator/internal/consoleplugin"
+)
+
+func TestConsolePluginReconciler_Reconcile(t *testing.T) {
+	fakeScheme := newAutoscalingScheme(t)
+	if err := consolev1.Install(fakeScheme); err != nil {
+		t.Fatalf("failed to add consolev1 scheme: %v", err)
+	}
+	// A ConsolePlugin left by a previous version proxies to a Service that has moved.
+	stale := &consolev1.ConsolePlugin{
+		ObjectMeta: metav1.ObjectMeta{Name: consoleplugin.Name},
+		Spec: consolev1.ConsolePluginSpec{
+			DisplayName: "MCP Servers",
+			Proxy: []consolev1.ConsolePluginProxy{{
+				Alias: consoleplugin.DiscoveryProxyAlias,
+				Endpoint: consolev1.ConsolePluginProxyEndpoint{
+					Type:    consolev1.ProxyTypeService,
+					Service: &consolev1.ConsolePluginProxyServiceConfig{Name: "old", Namespace: operatorNamespace, Port: 8443},
+				},
+			}},
+		},
+	}
+	cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(stale).Build()
+	r := &ConsolePluginReconciler{
+		Client:           cli,
+		Scheme:           fakeScheme,
+		Namespace:        operatorNamespace,
+		OperatorImage:    "quay.io/example/operator:v2",
+		DiscoveryService: "mcp-server-operator-controller-manager-discovery-service",
+		DiscoveryPort:    8444,
+	}
+	ctx := context.Background()
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: consolePluginRequest.NamespacedName}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	deployment := &appsv1.Deployment{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: ConsolePluginServerName, Namespace: operatorNamespace}, deployment); err != nil {
+		t.Fatalf("failed to get the Deployment: %v", err)
+	}
+	container := deployment.Spec.Template.Spec.Containers[0]
+	if container.Image != r.OperatorImage || container.Command[1] != consoleplugin.Command {
+		t.Errorf("container = %s %v, want the %s subcommand of the operator image", container.Image, container.Command,
+			consoleplugin.Command)
+	}
+	service := &corev1.Service{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: ConsolePluginServerName, Namespace: operatorNamespace}, service); err != nil {
+		t.Fatalf("failed to get the Service: %v", err)
+	}
+	if service.Annotations[servingCertAnnotation] != consolePluginCertName {
+		t.Errorf("Service annotations = %v, want a serving certificate in %s", service.Annotations, consolePluginCertName)
+	}
+
+	plugin := &consolev1.ConsolePlugin{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: consoleplugin.Name}, plugin); err != nil {
+		t.Fatalf("failed to get the ConsolePlugin: %v", err)
+	}
+	if backend := plugin.Spec.Backend.Service; backend == nil || backend.Name != ConsolePluginServerName ||
+		backend.Port != consolePluginPort {
+		t.Errorf("backend = %+v, want the Service of the plugin", plugin.Spec.Backend)
+	}
+	if len(plugin.Spec.Proxy) != 1 || plugin.Spec.Proxy[0].Authorization != consolev1.UserToken ||
+		plugin.Spec.Proxy[0].Endpoint.Service.Name != r.DiscoveryService || plugin.Spec.Proxy[0].Endpoint.Service.Port != 8444 {
+		t.Errorf("proxy = %+v, want the discovery API with the token of the user", plugin.Spec.Proxy)
+	}
+
+	// A new version of the operator updates the image of the plugin.
+	r.OperatorImage = "quay.io/example/operator:v3"
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: consolePluginRequest.NamespacedName}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(deployment), deployment); err != nil {
+		t.Fatalf("failed to get the Deployment: %v", err)
+	}
+	if got := deployment.Spec.Template.Spec.Containers[0].Image; got != r.OperatorImage {
+		t.Errorf("image = %s, want %s", got, r.OperatorImage)
+	}
+}
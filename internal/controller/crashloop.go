@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+const (
+	// ReasonCrashLoopBackOff is set on the Degraded and DeploymentAvailable conditions when a container of the MCP
+	// server pods keeps exiting and the kubelet backs off restarting it. It is also used as the reason of the
+	// emitted Warning event.
+	ReasonCrashLoopBackOff = "CrashLoopBackOff"
+
+	// crashLoopBackOffReason is the waiting reason the kubelet reports for a container it backs off restarting.
+	crashLoopBackOffReason = "CrashLoopBackOff"
+)
+
+// getCrashLoopFailure returns a message describing the first container of the MCP server pods that is in
+// CrashLoopBackOff, with the exit code and message of its last termination, or an empty string when there is none.
+func (r *MCPServerReconciler) getCrashLoopFailure(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) (string, error) {
+	pods, err := r.listMCPServerPods(ctx, cli, cr)
+	if err != nil {
+		return "", err
+	}
+
+	for _, pod := range pods {
+		statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+		for _, status := range statuses {
+			if status.State.Waiting == nil || status.State.Waiting.Reason != crashLoopBackOffReason {
+				continue
+			}
+			message := fmt.Sprintf("Container %s in pod %s is in CrashLoopBackOff after %d restarts",
+				status.Name, pod.Name, status.RestartCount)
+			if terminated := status.LastTerminationState.Terminated; terminated != nil {
+				message = fmt.Sprintf("%s, it last exited with code %d", message, terminated.ExitCode)
+				if terminated.Reason != "" {
+					message = fmt.Sprintf("%s (%s)", message, terminated.Reason)
+				}
+				if terminated.Message != "" {
+					message = fmt.Sprintf("%s: %s", message, terminated.Message)
+				}
+			}
+			return message, nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// +kubebuilder:rbac:groups=mcpserver.opendatahub.io,resources=mcpserverdefaults,verbs=get;list;watch
+
+const (
+	// ReasonTypeNotAllowed is set on the Available condition of an MCPServer whose type the MCPServerDefaults of
+	// its namespace do not allow.
+	ReasonTypeNotAllowed = "TypeNotAllowed"
+)
+
+// getDefaults returns the MCPServerDefaults of namespace, or nil when the namespace has none.
+func (r *MCPServerReconciler) getDefaults(ctx context.Context, namespace string) (*mcpserverv1.MCPServerDefaults, error) {
+	defaults := &mcpserverv1.MCPServerDefaults{}
+	err := r.Client.Get(ctx, client.ObjectKey{Name: mcpserverv1.MCPServerDefaultsName, Namespace: namespace}, defaults)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return defaults, nil
+}
+
+// applyDefaults fills the fields cr leaves unset from the defaults of its namespace. Only the in-memory copy of
+// cr is changed, the spec stored in the cluster stays as the user wrote it. The operator configuration applies
+// below both, e.g. it defines the resources of the preset.
+func applyDefaults(cr *mcpserverv1.MCPServer, defaults *mcpserverv1.MCPServerDefaults) {
+	if defaults == nil {
+		return
+	}
+	if cr.Spec.ResourcesPreset == "" && !isExternal(cr) {
+		cr.Spec.ResourcesPreset = defaults.Spec.ResourcesPreset
+	}
+}
+
+// getTypeAllowedCondition returns the Available condition of an MCPServer whose type the defaults of its
+// namespace do not allow, or nil when the type is allowed.
+func getTypeAllowedCondition(cr *mcpserverv1.MCPServer, defaults *mcpserverv1.MCPServerDefaults) *metav1.Condition {
+	if defaults == nil || len(defaults.Spec.AllowedTypes) == 0 {
+		return nil
+	}
+	serverType := cr.Spec.Type
+	if serverType == "" {
+		serverType = mcpserverv1.MCPServerManaged
+	}
+	if slices.Contains(defaults.Spec.AllowedTypes, serverType) {
+		return nil
+	}
+	return &metav1.Condition{
+		Type:   OverallAvailable,
+		Status: metav1.ConditionFalse,
+		Reason: ReasonTypeNotAllowed,
+		Message: fmt.Sprintf("%s MCP servers are not allowed in namespace %s, the MCPServerDefaults allow %v",
+			serverType, cr.Namespace, defaults.Spec.AllowedTypes),
+	}
+}
+
+// mapDefaultsToMCPServers maps MCPServerDefaults to the MCPServers of their namespace.
+func (r *MCPServerReconciler) mapDefaultsToMCPServers(ctx context.Context, obj client.Object) []reconcile.Request {
+	if obj.GetName() != mcpserverv1.MCPServerDefaultsName {
+		return nil
+	}
+
+	list := &mcpserverv1.MCPServerList{}
+	if err := r.Client.List(ctx, list, client.InNamespace(obj.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list the MCPServers of MCPServerDefaults", "namespace", obj.GetNamespace())
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(list.Items))
+	for _, cr := range list.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&cr)})
+	}
+	return requests
+}
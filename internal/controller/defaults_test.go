@@ -0,0 +1,148 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+func newDefaults(preset mcpserverv1.ResourcesPreset, allowedTypes ...mcpserverv1.MCPServerType) *mcpserverv1.MCPServerDefaults {
+	return &mcpserverv1.MCPServerDefaults{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpserverv1.MCPServerDefaultsName, Namespace: testNamespace},
+		Spec:       mcpserverv1.MCPServerDefaultsSpec{ResourcesPreset: preset, AllowedTypes: allowedTypes},
+	}
+}
+
+func Test_applyDefaults(t *testing.T) {
+	tests := []struct {
+		name       string
+		spec       mcpserverv1.MCPServerSpec
+		defaults   *mcpserverv1.MCPServerDefaults
+		wantPreset mcpserverv1.ResourcesPreset
+	}{
+		{
+			name: "no defaults",
+			spec: mcpserverv1.MCPServerSpec{Image: mcpServerImage},
+		},
+		{
+			name:       "preset of the namespace",
+			spec:       mcpserverv1.MCPServerSpec{Image: mcpServerImage},
+			defaults:   newDefaults(mcpserverv1.ResourcesPresetMedium),
+			wantPreset: mcpserverv1.ResourcesPresetMedium,
+		},
+		{
+			name:       "preset of the MCPServer wins",
+			spec:       mcpserverv1.MCPServerSpec{Image: mcpServerImage, ResourcesPreset: mcpserverv1.ResourcesPresetLarge},
+			defaults:   newDefaults(mcpserverv1.ResourcesPresetMedium),
+			wantPreset: mcpserverv1.ResourcesPresetLarge,
+		},
+		{
+			name:     "External servers have no pods",
+			spec:     mcpserverv1.MCPServerSpec{Type: mcpserverv1.MCPServerExternal, URL: "https://mcp.example.com/sse"},
+			defaults: newDefaults(mcpserverv1.ResourcesPresetMedium),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &mcpserverv1.MCPServer{Spec: tt.spec}
+			applyDefaults(cr, tt.defaults)
+			if cr.Spec.ResourcesPreset != tt.wantPreset {
+				t.Errorf("spec.resourcesPreset = %q, want %q", cr.Spec.ResourcesPreset, tt.wantPreset)
+			}
+		})
+	}
+}
+
+func Test_getTypeAllowedCondition(t *testing.T) {
+	tests := []struct {
+		name        string
+		serverType  mcpserverv1.MCPServerType
+		defaults    *mcpserverv1.MCPServerDefaults
+		wantAllowed bool
+	}{
+		{
+			name:        "no defaults",
+			serverType:  mcpserverv1.MCPServerExternal,
+			wantAllowed: true,
+		},
+		{
+			name:        "no restriction",
+			serverType:  mcpserverv1.MCPServerExternal,
+			defaults:    newDefaults(""),
+			wantAllowed: true,
+		},
+		{
+			name:        "Managed is the default type",
+			defaults:    newDefaults("", mcpserverv1.MCPServerManaged),
+			wantAllowed: true,
+		},
+		{
+			name:       "type not allowed",
+			serverType: mcpserverv1.MCPServerExternal,
+			defaults:   newDefaults("", mcpserverv1.MCPServerManaged, mcpserverv1.MCPServerProxy),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &mcpserverv1.MCPServer{Spec: mcpserverv1.MCPServerSpec{Type: tt.serverType}}
+			got := getTypeAllowedCondition(cr, tt.defaults)
+			if (got == nil) != tt.wantAllowed {
+				t.Errorf("getTypeAllowedCondition() = %v, want allowed %v", got, tt.wantAllowed)
+			}
+			if got != nil && (got.Status != metav1.ConditionFalse || got.Reason != ReasonTypeNotAllowed) {
+				t.Errorf("getTypeAllowedCondition() = %s/%s, want False/%s", got.Status, got.Reason, ReasonTypeNotAllowed)
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_Reconcile_typeNotAllowed(t *testing.T) {
+	fakeScheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(fakeScheme); err != nil {
+		t.Fatalf("failed to add client-go scheme: %v", err)
+	}
+	if err := mcpserverv1.AddToScheme(fakeScheme); err != nil {
+		t.Fatalf("failed to add mcpserverv1 scheme: %v", err)
+	}
+
+	cr := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+		Spec:       mcpserverv1.MCPServerSpec{Type: mcpserverv1.MCPServerExternal, URL: "https://mcp.example.com/sse"},
+	}
+	cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithStatusSubresource(&mcpserverv1.MCPServer{}).
+		WithObjects(cr, newDefaults("", mcpserverv1.MCPServerManaged)).Build()
+	recorder := record.NewFakeRecorder(10)
+	r := &MCPServerReconciler{Client: cli, Scheme: fakeScheme, Recorder: recorder}
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{
+		Name: mcpServerName, Namespace: testNamespace}}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := cli.Get(context.Background(), client.ObjectKeyFromObject(cr), cr); err != nil {
+		t.Fatalf("failed to get the MCPServer: %v", err)
+	}
+	if len(cr.Status.Conditions) != 2 || cr.Status.Conditions[0].Reason != ReasonTypeNotAllowed ||
+		!meta.IsStatusConditionFalse(cr.Status.Conditions, Ready) {
+		t.Errorf("status.conditions = %+v, want only Available with reason %s and Ready", cr.Status.Conditions, ReasonTypeNotAllowed)
+	}
+	if len(recorder.Events) != 1 {
+		t.Errorf("%d events emitted, want 1", len(recorder.Events))
+	}
+
+	// The MCPServers of a namespace are reconciled when its defaults change.
+	requests := r.mapDefaultsToMCPServers(context.Background(), newDefaults(""))
+	if len(requests) != 1 || requests[0].Name != mcpServerName {
+		t.Errorf("mapDefaultsToMCPServers() = %v, want the MCPServer of the namespace", requests)
+	}
+}
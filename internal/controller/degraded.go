@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+const (
+	// Degraded reports a problem with the workload of an MCP server that needs attention, even when a previous
+	// revision may still be serving.
+	Degraded = "Degraded"
+
+	// ReasonProgressDeadlineExceeded is set on the Degraded condition when a rollout of the MCP server made no
+	// progress within spec.progressDeadlineSeconds. It is also used as the reason of the emitted Warning event.
+	ReasonProgressDeadlineExceeded = "ProgressDeadlineExceeded"
+	// ReasonAsExpected is set on the Degraded condition when nothing is wrong.
+	ReasonAsExpected = "AsExpected"
+)
+
+// getDegradedCondition returns the Degraded condition of cr from the containers of its pods in CrashLoopBackOff
+// and the Progressing condition of its Deployment.
+func (r *MCPServerReconciler) getDegradedCondition(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) metav1.Condition {
+	dep := &appsv1.Deployment{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: resourceName(cr), Namespace: cr.Namespace}, dep); err != nil {
+		if k8serr.IsNotFound(err) {
+			return metav1.Condition{
+				Type:    Degraded,
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonAsExpected,
+				Message: fmt.Sprintf("Deployment %s is not created yet", resourceName(cr)),
+			}
+		}
+		return metav1.Condition{
+			Type:    Degraded,
+			Status:  metav1.ConditionUnknown,
+			Reason:  fmt.Sprintf("%s%s", "Deployment", ReasonGetFailedSuffix),
+			Message: fmt.Sprintf("Failed to retrieve Deployment %s, %v", cr.Name, err),
+		}
+	}
+
+	// A crash loop explains a rollout that makes no progress, so it is reported first.
+	if message, err := r.getCrashLoopFailure(ctx, cli, cr); err == nil && message != "" {
+		return metav1.Condition{
+			Type:    Degraded,
+			Status:  metav1.ConditionTrue,
+			Reason:  ReasonCrashLoopBackOff,
+			Message: message,
+		}
+	}
+	for _, cond := range dep.Status.Conditions {
+		if cond.Type == appsv1.DeploymentProgressing && cond.Status == corev1.ConditionFalse &&
+			cond.Reason == ReasonProgressDeadlineExceeded {
+			return metav1.Condition{
+				Type:    Degraded,
+				Status:  metav1.ConditionTrue,
+				Reason:  ReasonProgressDeadlineExceeded,
+				Message: fmt.Sprintf("The rollout of Deployment %s is stuck: %s", cr.Name, cond.Message),
+			}
+		}
+	}
+	return metav1.Condition{
+		Type:    Degraded,
+		Status:  metav1.ConditionFalse,
+		Reason:  ReasonAsExpected,
+		Message: fmt.Sprintf("The rollout of Deployment %s is progressing", cr.Name),
+	}
+}
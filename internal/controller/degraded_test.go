@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+func TestMCPServerReconciler_getDegradedCondition(t *testing.T) {
+	cr := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+		Spec:       mcpserverv1.MCPServerSpec{Image: mcpServerImage},
+	}
+
+	tests := []struct {
+		name        string
+		conditions  []appsv1.DeploymentCondition
+		pods        []corev1.Pod
+		missing     bool
+		wantStatus  metav1.ConditionStatus
+		wantReason  string
+		wantMessage string
+	}{
+		{
+			name:       "missing Deployment",
+			missing:    true,
+			wantStatus: metav1.ConditionFalse,
+			wantReason: ReasonAsExpected,
+		},
+		{
+			name: "progressing rollout",
+			conditions: []appsv1.DeploymentCondition{{
+				Type:   appsv1.DeploymentProgressing,
+				Status: corev1.ConditionTrue,
+				Reason: "NewReplicaSetAvailable",
+			}},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: ReasonAsExpected,
+		},
+		{
+			name: "stuck rollout",
+			conditions: []appsv1.DeploymentCondition{{
+				Type:    appsv1.DeploymentProgressing,
+				Status:  corev1.ConditionFalse,
+				Reason:  ReasonProgressDeadlineExceeded,
+				Message: `ReplicaSet "test-mcp-server-5d4f" has timed out progressing.`,
+			}},
+			wantStatus: metav1.ConditionTrue,
+			wantReason: ReasonProgressDeadlineExceeded,
+		},
+		{
+			name: "crash loop",
+			conditions: []appsv1.DeploymentCondition{{
+				Type:   appsv1.DeploymentProgressing,
+				Status: corev1.ConditionFalse,
+				Reason: ReasonProgressDeadlineExceeded,
+			}},
+			pods: []corev1.Pod{{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      mcpServerName + "-7c9d",
+					Namespace: testNamespace,
+					Labels:    map[string]string{mcpServerAppLabelKey: mcpServerName},
+				},
+				Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+					Name:         "mcp-server",
+					RestartCount: 6,
+					State: corev1.ContainerState{
+						Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+					},
+					LastTerminationState: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							ExitCode: 2,
+							Reason:   "Error",
+							Message:  "API_TOKEN is not set",
+						},
+					},
+				}}},
+			}},
+			wantStatus: metav1.ConditionTrue,
+			wantReason: ReasonCrashLoopBackOff,
+			wantMessage: "Container mcp-server in pod test-mcpserver-7c9d is in CrashLoopBackOff after 6 restarts, " +
+				"it last exited with code 2 (Error): API_TOKEN is not set",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder()
+			if !tt.missing {
+				builder = builder.WithObjects(&appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+					Status:     appsv1.DeploymentStatus{Conditions: tt.conditions},
+				})
+			}
+			for i := range tt.pods {
+				builder = builder.WithObjects(&tt.pods[i])
+			}
+			cli := builder.Build()
+			r := &MCPServerReconciler{Client: cli}
+
+			got := r.getDegradedCondition(context.Background(), cli, cr)
+			if got.Type != Degraded || got.Status != tt.wantStatus || got.Reason != tt.wantReason {
+				t.Errorf("getDegradedCondition() = %s %s %s, want %s %s", got.Type, got.Status, got.Reason, tt.wantStatus, tt.wantReason)
+			}
+			if tt.wantMessage != "" && got.Message != tt.wantMessage {
+				t.Errorf("getDegradedCondition() message = %q, want %q", got.Message, tt.wantMessage)
+			}
+		})
+	}
+}
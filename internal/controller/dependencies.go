@@ -0,0 +1,237 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+const (
+	// DependenciesNotReady reports whether an object of spec.dependsOn is not ready. The workload is neither
+	// created nor updated while it is True.
+	DependenciesNotReady = "DependenciesNotReady"
+
+	// ReasonDependencyNotFound is set on the DependenciesNotReady condition when an object of spec.dependsOn does
+	// not exist.
+	ReasonDependencyNotFound = "DependencyNotFound"
+	// ReasonDependencyNotReady is set on the DependenciesNotReady condition when the readiness condition of an
+	// object of spec.dependsOn is not True.
+	ReasonDependencyNotReady = "DependencyNotReady"
+	// ReasonDependencyUnreadable is set on the DependenciesNotReady condition when the operator cannot read an
+	// object of spec.dependsOn, because its kind is not served or the operator may not get it.
+	ReasonDependencyUnreadable = "DependencyUnreadable"
+	// ReasonDependencyCycle is set on the DependenciesNotReady condition when an MCPServer of spec.dependsOn
+	// depends on the MCPServer itself, directly or through other MCPServers, so neither would ever roll out.
+	ReasonDependencyCycle = "DependencyCycle"
+
+	// defaultDependencyCondition is the readiness condition of dependencies that are not MCPServers.
+	defaultDependencyCondition = "Ready"
+)
+
+// isMCPServerDependency reports whether dependency refers to an MCPServer.
+func isMCPServerDependency(dependency mcpserverv1.Dependency) bool {
+	return dependency.Kind == "" ||
+		(dependency.Kind == "MCPServer" && dependency.APIVersion == mcpserverv1.GroupVersion.String())
+}
+
+// dependencyConditionType returns the type of the readiness condition of dependency.
+func dependencyConditionType(dependency mcpserverv1.Dependency) string {
+	switch {
+	case dependency.ConditionType != "":
+		return dependency.ConditionType
+	case isMCPServerDependency(dependency):
+		return OverallAvailable
+	}
+	return defaultDependencyCondition
+}
+
+// dependencyKind returns the kind of dependency as shown in messages.
+func dependencyKind(dependency mcpserverv1.Dependency) string {
+	if isMCPServerDependency(dependency) {
+		return "MCPServer"
+	}
+	return dependency.Kind
+}
+
+// getDependenciesCondition returns the DependenciesNotReady condition of cr, which is True while an object of
+// spec.dependsOn is missing, unreadable or not ready. MCPServers are read from the cache and watched, other
+// objects are read from the API server and checked again at the probe interval.
+func (r *MCPServerReconciler) getDependenciesCondition(ctx context.Context, cr *mcpserverv1.MCPServer) (metav1.Condition, error) {
+	var failures []prereqFailure
+	for _, dependency := range cr.Spec.DependsOn {
+		failure, err := r.checkDependency(ctx, cr, dependency)
+		if err != nil {
+			return metav1.Condition{}, err
+		}
+		if failure != nil {
+			failures = append(failures, *failure)
+		}
+	}
+	if len(failures) == 0 {
+		return metav1.Condition{
+			Type:    DependenciesNotReady,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonAsExpected,
+			Message: fmt.Sprintf("All %d dependencies are ready", len(cr.Spec.DependsOn)),
+		}, nil
+	}
+	messages := make([]string, len(failures))
+	for i, failure := range failures {
+		messages[i] = failure.message
+	}
+	return metav1.Condition{
+		Type:    DependenciesNotReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  failures[0].reason,
+		Message: strings.Join(messages, "; "),
+	}, nil
+}
+
+// checkDependency returns why dependency of cr is not ready, or nil when it is.
+func (r *MCPServerReconciler) checkDependency(ctx context.Context, cr *mcpserverv1.MCPServer,
+	dependency mcpserverv1.Dependency) (*prereqFailure, error) {
+	kind := dependencyKind(dependency)
+	conditionType := dependencyConditionType(dependency)
+	key := client.ObjectKey{Name: dependency.Name, Namespace: cr.Namespace}
+
+	var conditions []metav1.Condition
+	if isMCPServerDependency(dependency) {
+		server := &mcpserverv1.MCPServer{}
+		if err := r.Get(ctx, key, server); err != nil {
+			if k8serr.IsNotFound(err) {
+				return &prereqFailure{ReasonDependencyNotFound, fmt.Sprintf("MCPServer %s does not exist", dependency.Name)}, nil
+			}
+			return nil, err
+		}
+		cycle, err := r.dependsOn(ctx, server, cr.Name, map[string]bool{})
+		if err != nil {
+			return nil, err
+		}
+		if cycle {
+			return &prereqFailure{ReasonDependencyCycle,
+				fmt.Sprintf("MCPServer %s depends on %s in turn, remove one of the dependencies", dependency.Name, cr.Name)}, nil
+		}
+		conditions = server.Status.Conditions
+	} else {
+		gv, err := schema.ParseGroupVersion(dependency.APIVersion)
+		if err != nil {
+			return &prereqFailure{ReasonDependencyUnreadable,
+				fmt.Sprintf("%s %s has an invalid apiVersion: %v", kind, dependency.Name, err)}, nil
+		}
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gv.WithKind(dependency.Kind))
+		err = r.Get(ctx, key, obj)
+		switch {
+		case k8serr.IsNotFound(err):
+			return &prereqFailure{ReasonDependencyNotFound, fmt.Sprintf("%s %s does not exist", kind, dependency.Name)}, nil
+		case meta.IsNoMatchError(err):
+			return &prereqFailure{ReasonDependencyUnreadable,
+				fmt.Sprintf("%s %s cannot be checked, the cluster does not serve %s", kind, dependency.Name,
+					gv.WithKind(dependency.Kind))}, nil
+		case k8serr.IsForbidden(err):
+			return &prereqFailure{ReasonDependencyUnreadable,
+				fmt.Sprintf("%s %s cannot be checked, grant the operator get permission on it", kind, dependency.Name)}, nil
+		case err != nil:
+			return nil, err
+		}
+		conditions = unstructuredConditions(obj)
+	}
+
+	condition := meta.FindStatusCondition(conditions, conditionType)
+	if condition == nil || condition.Status != metav1.ConditionTrue {
+		message := fmt.Sprintf("%s %s has no %s condition yet", kind, dependency.Name, conditionType)
+		if condition != nil {
+			message = fmt.Sprintf("%s %s is not ready, its %s condition is %s", kind, dependency.Name, conditionType,
+				condition.Status)
+			if condition.Reason != "" {
+				message += fmt.Sprintf(" with reason %s", condition.Reason)
+			}
+		}
+		return &prereqFailure{ReasonDependencyNotReady, message}, nil
+	}
+	return nil, nil
+}
+
+// dependsOn reports whether server depends on the MCPServer named target of its namespace, directly or through
+// other MCPServers. visited holds the MCPServers already followed.
+func (r *MCPServerReconciler) dependsOn(ctx context.Context, server *mcpserverv1.MCPServer, target string,
+	visited map[string]bool) (bool, error) {
+	visited[server.Name] = true
+	for _, dependency := range server.Spec.DependsOn {
+		if !isMCPServerDependency(dependency) {
+			continue
+		}
+		if dependency.Name == target {
+			return true, nil
+		}
+		if visited[dependency.Name] {
+			continue
+		}
+		next := &mcpserverv1.MCPServer{}
+		if err := r.Get(ctx, client.ObjectKey{Name: dependency.Name, Namespace: server.Namespace}, next); err != nil {
+			if k8serr.IsNotFound(err) {
+				continue
+			}
+			return false, err
+		}
+		if found, err := r.dependsOn(ctx, next, target, visited); err != nil || found {
+			return found, err
+		}
+	}
+	return false, nil
+}
+
+// unstructuredConditions returns the well-formed conditions in status.conditions of obj.
+func unstructuredConditions(obj *unstructured.Unstructured) []metav1.Condition {
+	items, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	var conditions []metav1.Condition
+	for _, item := range items {
+		fields, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		conditionType, _ := fields["type"].(string)
+		status, _ := fields["status"].(string)
+		reason, _ := fields["reason"].(string)
+		if conditionType == "" {
+			continue
+		}
+		conditions = append(conditions, metav1.Condition{
+			Type:   conditionType,
+			Status: metav1.ConditionStatus(status),
+			Reason: reason,
+		})
+	}
+	return conditions
+}
+
+// mapMCPServerToDependents maps an MCPServer to the MCPServers of its namespace that depend on it, so that they
+// roll out as soon as it becomes ready.
+func (r *MCPServerReconciler) mapMCPServerToDependents(ctx context.Context, obj client.Object) []reconcile.Request {
+	list := &mcpserverv1.MCPServerList{}
+	if err := r.Client.List(ctx, list, client.InNamespace(obj.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list the dependents of an MCPServer", "namespace", obj.GetNamespace())
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, cr := range list.Items {
+		for _, dependency := range cr.Spec.DependsOn {
+			if isMCPServerDependency(dependency) && dependency.Name == obj.GetName() {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&cr)})
+				break
+			}
+		}
+	}
+	return requests
+}
@@ -0,0 +1,189 @@
+package controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster"
+)
+
+// newDependency returns an MCPServer named name that depends on dependsOn, available when available is set.
+func newDependency(name string, available bool, dependsOn ...mcpserverv1.Dependency) *mcpserverv1.MCPServer {
+	status := metav1.ConditionFalse
+	if available {
+		status = metav1.ConditionTrue
+	}
+	return &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Spec:       mcpserverv1.MCPServerSpec{Image: mcpServerImage, DependsOn: dependsOn},
+		Status: mcpserverv1.MCPServerStatus{Conditions: []metav1.Condition{
+			{Type: OverallAvailable, Status: status, Reason: ReasonEndpointUnreachable},
+		}},
+	}
+}
+
+// newBackend returns a Deployment named backend whose Available condition has status.
+func newBackend(status corev1.ConditionStatus) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "backend", Namespace: testNamespace},
+		Status: appsv1.DeploymentStatus{Conditions: []appsv1.DeploymentCondition{
+			{Type: appsv1.DeploymentAvailable, Status: status, Reason: "MinimumReplicasAvailable"},
+		}},
+	}
+}
+
+func TestMCPServerReconciler_getDependenciesCondition(t *testing.T) {
+	fakeScheme := newAutoscalingScheme(t)
+	backend := mcpserverv1.Dependency{APIVersion: "apps/v1", Kind: "Deployment", Name: "backend", ConditionType: "Available"}
+
+	tests := []struct {
+		name        string
+		dependsOn   []mcpserverv1.Dependency
+		objects     []client.Object
+		wantStatus  metav1.ConditionStatus
+		wantReason  string
+		wantMessage string
+	}{
+		{
+			name:       "Verify that ready MCPServers and objects are reported as ready",
+			dependsOn:  []mcpserverv1.Dependency{{Name: "search"}, backend},
+			objects:    []client.Object{newDependency("search", true), newBackend(corev1.ConditionTrue)},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: ReasonAsExpected,
+		},
+		{
+			name:        "Verify that an MCPServer that is not available holds the MCP server",
+			dependsOn:   []mcpserverv1.Dependency{{Name: "search"}},
+			objects:     []client.Object{newDependency("search", false)},
+			wantStatus:  metav1.ConditionTrue,
+			wantReason:  ReasonDependencyNotReady,
+			wantMessage: "MCPServer search is not ready, its Available condition is False with reason EndpointUnreachable",
+		},
+		{
+			name:        "Verify that an object whose readiness condition is not True holds the MCP server",
+			dependsOn:   []mcpserverv1.Dependency{backend},
+			objects:     []client.Object{newBackend(corev1.ConditionFalse)},
+			wantStatus:  metav1.ConditionTrue,
+			wantReason:  ReasonDependencyNotReady,
+			wantMessage: "Deployment backend is not ready, its Available condition is False with reason MinimumReplicasAvailable",
+		},
+		{
+			name: "Verify that an object without the readiness condition holds the MCP server",
+			dependsOn: []mcpserverv1.Dependency{
+				{APIVersion: "apps/v1", Kind: "Deployment", Name: "backend"},
+			},
+			objects:     []client.Object{newBackend(corev1.ConditionTrue)},
+			wantStatus:  metav1.ConditionTrue,
+			wantReason:  ReasonDependencyNotReady,
+			wantMessage: "Deployment backend has no Ready condition yet",
+		},
+		{
+			name:        "Verify that missing dependencies are all reported",
+			dependsOn:   []mcpserverv1.Dependency{{Name: "search"}, backend},
+			wantStatus:  metav1.ConditionTrue,
+			wantReason:  ReasonDependencyNotFound,
+			wantMessage: "MCPServer search does not exist; Deployment backend does not exist",
+		},
+		{
+			name:      "Verify that a cycle through other MCPServers is reported",
+			dependsOn: []mcpserverv1.Dependency{{Name: "search"}},
+			objects: []client.Object{
+				newDependency("search", false, mcpserverv1.Dependency{Name: "index"}),
+				newDependency("index", false, mcpserverv1.Dependency{Name: mcpServerName}),
+			},
+			wantStatus:  metav1.ConditionTrue,
+			wantReason:  ReasonDependencyCycle,
+			wantMessage: "MCPServer search depends on " + mcpServerName + " in turn, remove one of the dependencies",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(tt.objects...).Build()
+			r := &MCPServerReconciler{Client: cli, Scheme: fakeScheme}
+			cr := newDependency(mcpServerName, false, tt.dependsOn...)
+			got, err := r.getDependenciesCondition(context.Background(), cr)
+			if err != nil {
+				t.Fatalf("getDependenciesCondition() error = %v", err)
+			}
+			if got.Type != DependenciesNotReady || got.Status != tt.wantStatus || got.Reason != tt.wantReason {
+				t.Errorf("getDependenciesCondition() = %+v, want status %s and reason %s", got, tt.wantStatus, tt.wantReason)
+			}
+			if tt.wantMessage != "" && got.Message != tt.wantMessage {
+				t.Errorf("getDependenciesCondition() message = %q, want %q", got.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_Reconcile_dependsOn(t *testing.T) {
+	fakeScheme := newAutoscalingScheme(t)
+	search := newDependency("search", false)
+	cr := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+		Spec: mcpserverv1.MCPServerSpec{
+			Image:     mcpServerImage,
+			DependsOn: []mcpserverv1.Dependency{{Name: "search"}},
+		},
+	}
+	cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithStatusSubresource(&mcpserverv1.MCPServer{}).
+		WithObjects(cr, search).Build()
+	r := &MCPServerReconciler{
+		Client:   cli,
+		Scheme:   fakeScheme,
+		Platform: &cluster.Platform{Name: cluster.Kubernetes},
+	}
+	ctx := context.Background()
+	reconcileCR := func() {
+		t.Helper()
+		if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cr)}); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		if err := cli.Get(ctx, client.ObjectKeyFromObject(cr), cr); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+	}
+
+	reconcileCR()
+	if !meta.IsStatusConditionTrue(cr.Status.Conditions, DependenciesNotReady) {
+		t.Errorf("DependenciesNotReady condition is not True: %+v", cr.Status.Conditions)
+	}
+	if got := meta.FindStatusCondition(cr.Status.Conditions, OverallAvailable); got == nil ||
+		got.Reason != DependenciesNotReady {
+		t.Errorf("Available condition = %+v, want reason %s", got, DependenciesNotReady)
+	}
+	deployment := &appsv1.Deployment{}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(cr), deployment); !k8serr.IsNotFound(err) {
+		t.Errorf("Get() Deployment error = %v, want it not created", err)
+	}
+
+	// The change of the dependency is mapped to its dependents, which roll out once it is available.
+	meta.SetStatusCondition(&search.Status.Conditions,
+		metav1.Condition{Type: OverallAvailable, Status: metav1.ConditionTrue, Reason: ReasonAsExpected})
+	if err := cli.Status().Update(ctx, search); err != nil {
+		t.Fatalf("Update() dependency status error = %v", err)
+	}
+	want := []reconcile.Request{{NamespacedName: client.ObjectKeyFromObject(cr)}}
+	if got := r.mapMCPServerToDependents(ctx, search); !reflect.DeepEqual(got, want) {
+		t.Errorf("mapMCPServerToDependents() = %v, want %v", got, want)
+	}
+	reconcileCR()
+	if got := meta.FindStatusCondition(cr.Status.Conditions, DependenciesNotReady); got == nil ||
+		got.Status != metav1.ConditionFalse {
+		t.Errorf("DependenciesNotReady condition = %+v, want False", got)
+	}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(cr), deployment); err != nil {
+		t.Errorf("Get() Deployment error = %v, want it created", err)
+	}
+}
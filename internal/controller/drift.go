@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"context"
+	"reflect"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// childDiff returns the JSON merge patch that turns original into modified, which lists exactly the fields
+// the reconciler changes on a child.
+func childDiff(original, modified client.Object) (string, error) {
+	data, err := client.MergeFrom(original).Data(modified)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// logChildDiff logs at debug level the fields of an out-of-sync child that the reconciler is about to change.
+// This makes fights with other controllers that mutate the same object, such as an HPA, an admission webhook
+// or a GitOps tool, visible in the operator log.
+func logChildDiff(ctx context.Context, original, modified client.Object) {
+	logger := logf.FromContext(ctx).V(1)
+	if !logger.Enabled() {
+		return
+	}
+
+	kind := reflect.TypeOf(modified).Elem().Name()
+	diff, err := childDiff(original, modified)
+	if err != nil {
+		logger.Error(err, "unable to compute the diff of an out-of-sync child", "kind", kind, "name", modified.GetName())
+		return
+	}
+	logger.Info("Updating out-of-sync child", "kind", kind, "name", modified.GetName(), "diff", diff)
+}
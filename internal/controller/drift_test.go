@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+func Test_childDiff(t *testing.T) {
+	original := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mcpServerName,
+			Namespace: testNamespace,
+			Labels:    map[string]string{mcpServerAppLabelKey: mcpServerName},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*appsv1.Deployment)
+		want   string
+	}{
+		{
+			name:   "unchanged",
+			modify: func(*appsv1.Deployment) {},
+			want:   `{}`,
+		},
+		{
+			name: "pod template annotation",
+			modify: func(d *appsv1.Deployment) {
+				d.Spec.Template.Annotations = map[string]string{mcpserverv1.RestartedAtAnnotation: "now"}
+			},
+			want: `{"spec":{"template":{"metadata":{"annotations":{"mcpserver.opendatahub.io/restartedAt":"now"}}}}}`,
+		},
+		{
+			name: "removed label",
+			modify: func(d *appsv1.Deployment) {
+				d.Labels = nil
+			},
+			want: `{"metadata":{"labels":null}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modified := original.DeepCopy()
+			tt.modify(modified)
+			got, err := childDiff(original, modified)
+			if err != nil {
+				t.Fatalf("childDiff() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("childDiff() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
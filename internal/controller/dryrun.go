@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+const (
+	// DriftDetected is set in dry-run mode and reports whether the managed resources differ from the state
+	// the operator would apply.
+	DriftDetected = "DriftDetected"
+
+	ReasonDriftDetected = "DriftDetected"
+	ReasonInSync        = "InSync"
+)
+
+// driftClient performs every write as a server-side dry run, so that requests are validated but never
+// persisted, and records the changes that would have been made. It is safe for concurrent use, as the children of
+// an MCPServer are reconciled concurrently.
+type driftClient struct {
+	client.Client
+	mu      sync.Mutex
+	changes []string
+}
+
+func newDriftClient(cli client.Client) *driftClient {
+	return &driftClient{Client: client.NewDryRunClient(cli)}
+}
+
+func (c *driftClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if err := c.Client.Create(ctx, obj, opts...); err != nil {
+		return err
+	}
+	c.record("create", obj)
+	return nil
+}
+
+func (c *driftClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if err := c.Client.Update(ctx, obj, opts...); err != nil {
+		return err
+	}
+	c.record("update", obj)
+	return nil
+}
+
+func (c *driftClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if err := c.Client.Patch(ctx, obj, patch, opts...); err != nil {
+		return err
+	}
+	c.record("patch", obj)
+	return nil
+}
+
+func (c *driftClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	if err := c.Client.Delete(ctx, obj, opts...); err != nil {
+		return err
+	}
+	c.record("delete", obj)
+	return nil
+}
+
+func (c *driftClient) record(verb string, obj client.Object) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.changes = append(c.changes, fmt.Sprintf("%s %s %s", verb, reflect.TypeOf(obj).Elem().Name(), obj.GetName()))
+}
+
+// getDriftCondition returns the DriftDetected condition for the changes recorded while reconciling cr.
+// The changes are sorted, so that the message does not depend on the order in which the children were reconciled.
+func (c *driftClient) getDriftCondition(cr *mcpserverv1.MCPServer) metav1.Condition {
+	c.mu.Lock()
+	changes := slices.Sorted(slices.Values(c.changes))
+	c.mu.Unlock()
+	if len(changes) == 0 {
+		return metav1.Condition{
+			Type:    DriftDetected,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonInSync,
+			Message: fmt.Sprintf("Resources of %s are in sync", cr.Name),
+		}
+	}
+	return metav1.Condition{
+		Type:    DriftDetected,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonDriftDetected,
+		Message: fmt.Sprintf("Dry-run mode, the operator would %s", strings.Join(changes, ", ")),
+	}
+}
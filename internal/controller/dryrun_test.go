@@ -0,0 +1,148 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestMCPServerReconciler_driftClient(t *testing.T) {
+	fakeScheme := runtime.NewScheme()
+	err := clientgoscheme.AddToScheme(fakeScheme)
+	if err != nil {
+		t.Errorf("failed to add client-go scheme: %v", err)
+	}
+	err = mcpserverv1.AddToScheme(fakeScheme)
+	if err != nil {
+		t.Errorf("failed to add mcpserverv1 scheme: %v", err)
+	}
+
+	mcpServer := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mcpServerName,
+			Namespace: testNamespace,
+			UID:       types.UID("uid"),
+			Annotations: map[string]string{
+				mcpserverv1.RestartedAtAnnotation: "now",
+			},
+		},
+		Spec: mcpserverv1.MCPServerSpec{
+			Image: mcpServerImage,
+		},
+	}
+
+	r := &MCPServerReconciler{Scheme: fakeScheme, DryRun: true}
+	ownedDeployment := func(restartedAt string) *appsv1.Deployment {
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      mcpServerName,
+				Namespace: testNamespace,
+			},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: map[string]string{mcpserverv1.RestartedAtAnnotation: restartedAt},
+					},
+				},
+			},
+		}
+		if err := r.createChild(context.Background(), fake.NewClientBuilder().WithScheme(fakeScheme).Build(), mcpServer, deployment); err != nil {
+			t.Fatalf("failed to set the owner reference: %v", err)
+		}
+		deployment.ResourceVersion = ""
+		return deployment
+	}
+
+	tests := []struct {
+		name           string
+		objects        []client.Object
+		wantCondition  metav1.Condition
+		wantDeployment bool
+		wantRestarted  string
+	}{
+		{
+			name: "missing deployment is not created",
+			wantCondition: metav1.Condition{
+				Type:    DriftDetected,
+				Status:  metav1.ConditionTrue,
+				Reason:  ReasonDriftDetected,
+				Message: "Dry-run mode, the operator would create Deployment " + mcpServerName,
+			},
+		},
+		{
+			name:           "outdated deployment is not patched",
+			objects:        []client.Object{ownedDeployment("before")},
+			wantDeployment: true,
+			wantRestarted:  "before",
+			wantCondition: metav1.Condition{
+				Type:    DriftDetected,
+				Status:  metav1.ConditionTrue,
+				Reason:  ReasonDriftDetected,
+				Message: "Dry-run mode, the operator would patch Deployment " + mcpServerName,
+			},
+		},
+		{
+			name:           "deployment in sync",
+			objects:        []client.Object{ownedDeployment("now")},
+			wantDeployment: true,
+			wantRestarted:  "now",
+			wantCondition: metav1.Condition{
+				Type:    DriftDetected,
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonInSync,
+				Message: "Resources of " + mcpServerName + " are in sync",
+			},
+		},
+	}
+	// The fake client accepts dry-run creates of existing objects, which the API server rejects.
+	rejectExisting := interceptor.Funcs{
+		Create: func(ctx context.Context, cli client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			existing := &appsv1.Deployment{}
+			if err := cli.Get(ctx, client.ObjectKeyFromObject(obj), existing); err == nil {
+				return k8serr.NewAlreadyExists(appsv1.Resource("deployments"), obj.GetName())
+			}
+			return cli.Create(ctx, obj, opts...)
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(tt.objects...).
+				WithInterceptorFuncs(rejectExisting).Build()
+			drift := newDriftClient(cli)
+
+			if err := r.reconcileMCPServerDeployment(context.Background(), drift, mcpServer); err != nil {
+				t.Fatalf("reconcileMCPServerDeployment() error = %v", err)
+			}
+			if got := drift.getDriftCondition(mcpServer); got != tt.wantCondition {
+				t.Errorf("getDriftCondition() = %v, want %v", got, tt.wantCondition)
+			}
+
+			deployment := &appsv1.Deployment{}
+			err := cli.Get(context.Background(), client.ObjectKeyFromObject(mcpServer), deployment)
+			if !tt.wantDeployment {
+				if !k8serr.IsNotFound(err) {
+					t.Errorf("expected the Deployment not to be created, got error %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to get the Deployment: %v", err)
+			}
+			if got := deployment.Spec.Template.Annotations[mcpserverv1.RestartedAtAnnotation]; got != tt.wantRestarted {
+				t.Errorf("restartedAt = %q, want %q", got, tt.wantRestarted)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	routev1 "github.com/openshift/api/route/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+const (
+	// EndpointReachable is set once the controller has been able to reach the MCP server over HTTP.
+	EndpointReachable = "EndpointReachable"
+
+	ReasonEndpointUnreachable = "EndpointUnreachable"
+	ReasonEndpointNotProbed   = "EndpointNotProbed"
+
+	// mcpServerSSEPath is the path the MCP server serves its SSE stream on, unless spec.basePath is set.
+	mcpServerSSEPath = "/sse"
+
+	// endpointProbeTimeout bounds a single probe so an unresponsive endpoint cannot stall the reconcile.
+	endpointProbeTimeout = 5 * time.Second
+)
+
+// getEndpoints returns the endpoints of the given MCPServer: the URL of an External server, or the in-cluster
+// Service URL followed by the URLs of its exposures and the URL of the Route, or of the Gateway or mesh gateway
+// host of a server published on one, once those are known.
+func (r *MCPServerReconciler) getEndpoints(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) ([]mcpserverv1.Endpoint, error) {
+	if isExternal(cr) {
+		return []mcpserverv1.Endpoint{newEndpoint(mcpserverv1.EndpointExternal, cr.Spec.URL)}, nil
+	}
+
+	endpoints := []mcpserverv1.Endpoint{newEndpoint(mcpserverv1.EndpointService, serviceURL(cr))}
+	// The exposures come before the primary exposure, which stays last and so in status.url.
+	exposures, err := r.getExposureEndpoints(ctx, cli, cr)
+	if err != nil {
+		return nil, err
+	}
+	endpoints = append(endpoints, exposures...)
+	if usesMeshGateway(cr) {
+		return append(endpoints, newEndpoint(mcpserverv1.EndpointMeshGateway, meshGatewayURL(cr))), nil
+	}
+	if usesGateway(cr) {
+		url, err := r.getGatewayURL(ctx, cli, cr)
+		if err != nil {
+			return nil, err
+		}
+		if url != "" {
+			endpoints = append(endpoints, newEndpoint(mcpserverv1.EndpointGateway, url))
+		}
+		return endpoints, nil
+	}
+	if !r.routeAPIAvailable() {
+		return endpoints, nil
+	}
+
+	route := &routev1.Route{}
+	err = cli.Get(ctx, client.ObjectKey{Name: resourceName(cr), Namespace: cr.Namespace}, route)
+	if err != nil && !k8serr.IsNotFound(err) {
+		return nil, err
+	}
+
+	if url := routeURL(cr, route); url != "" {
+		endpoints = append(endpoints, newEndpoint(mcpserverv1.EndpointRoute, url))
+	}
+	return endpoints, nil
+}
+
+// routeURL returns the URL of the MCP server of cr on route, or "" while the route has no host yet.
+func routeURL(cr *mcpserverv1.MCPServer, route *routev1.Route) string {
+	host := route.Spec.Host
+	if host == "" {
+		for _, ingress := range route.Status.Ingress {
+			if ingress.Host != "" {
+				host = ingress.Host
+				break
+			}
+		}
+	}
+	if host == "" {
+		return ""
+	}
+	scheme := "http"
+	if route.Spec.TLS != nil {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s%s", scheme, host, mcpServerPath(cr))
+}
+
+// newEndpoint returns the endpoint of the given type at rawURL. All endpoints serve the SSE transport.
+func newEndpoint(endpointType mcpserverv1.EndpointType, rawURL string) mcpserverv1.Endpoint {
+	endpoint := mcpserverv1.Endpoint{Type: endpointType, URL: rawURL, Transport: mcpserverv1.TransportSSE}
+	if u, err := url.Parse(rawURL); err == nil {
+		endpoint.Scheme, endpoint.Host, endpoint.Path = u.Scheme, u.Host, u.Path
+	}
+	return endpoint
+}
+
+// endpointURL returns the URL the controller probes and reports in status.url: the last of endpoints, so the
+// Route host, or the Gateway or mesh gateway host of a server published on one, is preferred and a broken ingress
+// path is caught. The in-cluster Service URL is used otherwise. External MCP servers are probed at their URL.
+func endpointURL(endpoints []mcpserverv1.Endpoint) string {
+	if len(endpoints) == 0 {
+		return ""
+	}
+	return endpoints[len(endpoints)-1].URL
+}
+
+// getEndpointURL returns the URL the controller probes for the given MCPServer, see endpointURL.
+func (r *MCPServerReconciler) getEndpointURL(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) (string, error) {
+	endpoints, err := r.getEndpoints(ctx, cli, cr)
+	if err != nil {
+		return "", err
+	}
+	return endpointURL(endpoints), nil
+}
+
+// mcpServerPath returns the path the MCP server of cr serves MCP under.
+func mcpServerPath(cr *mcpserverv1.MCPServer) string {
+	if cr.Spec.BasePath != "" {
+		return cr.Spec.BasePath
+	}
+	return mcpServerSSEPath
+}
+
+// serviceURL returns the in-cluster URL of the MCP server's endpoint.
+func serviceURL(cr *mcpserverv1.MCPServer) string {
+	return fmt.Sprintf("http://%s.%s.svc:%d%s", resourceName(cr), cr.Namespace, mcpServerPort, mcpServerPath(cr))
+}
+
+// probeEndpoint issues a GET against url and returns an error if the endpoint could not be reached or
+// answered with a server error. The body is never read, as SSE endpoints keep the stream open. A non-empty
+// token is sent as bearer token.
+func (r *MCPServerReconciler) probeEndpoint(ctx context.Context, url, token string) error {
+	httpClient := r.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, endpointProbeTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(probeCtx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (r *MCPServerReconciler) getEndpointCondition(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) metav1.Condition {
+	// There is nothing to probe until the server pods are available.
+	if !isExternal(cr) && !meta.IsStatusConditionTrue(cr.Status.Conditions, DeploymentAvailable) {
+		return metav1.Condition{
+			Type:    EndpointReachable,
+			Status:  metav1.ConditionUnknown,
+			Reason:  ReasonEndpointNotProbed,
+			Message: fmt.Sprintf("Endpoint of %s is not probed until the Deployment is available", cr.Name),
+		}
+	}
+
+	url, err := r.getEndpointURL(ctx, cli, cr)
+	if err != nil {
+		return metav1.Condition{
+			Type:    EndpointReachable,
+			Status:  metav1.ConditionUnknown,
+			Reason:  fmt.Sprintf("%s%s", "Endpoint", ReasonGetFailedSuffix),
+			Message: fmt.Sprintf("Failed to determine endpoint of %s: %v", cr.Name, err),
+		}
+	}
+
+	token, err := r.getCredentialsToken(ctx, cli, cr)
+	if err != nil {
+		return metav1.Condition{
+			Type:    EndpointReachable,
+			Status:  metav1.ConditionUnknown,
+			Reason:  fmt.Sprintf("%s%s", "Credentials", ReasonGetFailedSuffix),
+			Message: fmt.Sprintf("Failed to read the credentials of %s: %v", cr.Name, err),
+		}
+	}
+
+	probe := func() error { return r.probeEndpoint(ctx, url, token) }
+	if usesGRPC(cr) {
+		probe = func() error { return probeTCP(ctx, url) }
+	}
+	if err := probe(); err != nil {
+		return metav1.Condition{
+			Type:    EndpointReachable,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonEndpointUnreachable,
+			Message: fmt.Sprintf("Endpoint %s is not reachable: %v", url, err),
+		}
+	}
+
+	return metav1.Condition{
+		Type:    EndpointReachable,
+		Status:  metav1.ConditionTrue,
+		Reason:  fmt.Sprintf("%s%s", "Endpoint", ReasonReadySuffix),
+		Message: fmt.Sprintf("Endpoint %s is reachable", url),
+	}
+}
@@ -0,0 +1,318 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMCPServerReconciler_getEndpointURL(t *testing.T) {
+	fakeScheme := runtime.NewScheme()
+	err := routev1.AddToScheme(fakeScheme)
+	if err != nil {
+		t.Errorf("failed to add routev1 scheme: %v", err)
+	}
+
+	routeWithHost := &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mcpServerName,
+			Namespace: testNamespace,
+		},
+		Spec: routev1.RouteSpec{
+			Host: "mcp.apps.example.com",
+		},
+	}
+
+	mcpServer := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mcpServerName,
+			Namespace: testNamespace,
+		},
+	}
+
+	tests := []struct {
+		name string
+		cli  client.Client
+		want string
+	}{
+		{
+			name: "Verify that the route host is used when the route has one",
+			cli:  fake.NewClientBuilder().WithScheme(fakeScheme).WithRuntimeObjects(routeWithHost).Build(),
+			want: "http://mcp.apps.example.com/sse",
+		},
+		{
+			name: "Verify that the service URL is used when there is no route",
+			cli:  fake.NewClientBuilder().WithScheme(fakeScheme).Build(),
+			want: fmt.Sprintf("http://%s.%s.svc:8000/sse", mcpServerName, testNamespace),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &MCPServerReconciler{
+				Client: tt.cli,
+				Scheme: fakeScheme,
+			}
+			got, err := r.getEndpointURL(context.Background(), tt.cli, mcpServer)
+			if err != nil {
+				t.Errorf("getEndpointURL() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("getEndpointURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_getEndpoints(t *testing.T) {
+	fakeScheme := runtime.NewScheme()
+	if err := routev1.AddToScheme(fakeScheme); err != nil {
+		t.Errorf("failed to add routev1 scheme: %v", err)
+	}
+
+	routeWithIngress := &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+		Status: routev1.RouteStatus{
+			Ingress: []routev1.RouteIngress{{Host: "mcp.apps.example.com"}},
+		},
+	}
+	service := mcpserverv1.Endpoint{
+		Type:      mcpserverv1.EndpointService,
+		URL:       fmt.Sprintf("http://%s.%s.svc:8000/sse", mcpServerName, testNamespace),
+		Scheme:    "http",
+		Host:      fmt.Sprintf("%s.%s.svc:8000", mcpServerName, testNamespace),
+		Path:      "/sse",
+		Transport: mcpserverv1.TransportSSE,
+	}
+
+	tests := []struct {
+		name string
+		spec mcpserverv1.MCPServerSpec
+		cli  client.Client
+		want []mcpserverv1.Endpoint
+	}{
+		{
+			name: "Verify that the route host from the route status follows the service",
+			cli:  fake.NewClientBuilder().WithScheme(fakeScheme).WithRuntimeObjects(routeWithIngress).Build(),
+			want: []mcpserverv1.Endpoint{service, {
+				Type:      mcpserverv1.EndpointRoute,
+				URL:       "http://mcp.apps.example.com/sse",
+				Scheme:    "http",
+				Host:      "mcp.apps.example.com",
+				Path:      "/sse",
+				Transport: mcpserverv1.TransportSSE,
+			}},
+		},
+		{
+			name: "Verify that the base path replaces the SSE path",
+			spec: mcpserverv1.MCPServerSpec{BasePath: "/mcp"},
+			cli:  fake.NewClientBuilder().WithScheme(fakeScheme).WithRuntimeObjects(routeWithIngress).Build(),
+			want: []mcpserverv1.Endpoint{{
+				Type:      mcpserverv1.EndpointService,
+				URL:       fmt.Sprintf("http://%s.%s.svc:8000/mcp", mcpServerName, testNamespace),
+				Scheme:    "http",
+				Host:      fmt.Sprintf("%s.%s.svc:8000", mcpServerName, testNamespace),
+				Path:      "/mcp",
+				Transport: mcpserverv1.TransportSSE,
+			}, {
+				Type:      mcpserverv1.EndpointRoute,
+				URL:       "http://mcp.apps.example.com/mcp",
+				Scheme:    "http",
+				Host:      "mcp.apps.example.com",
+				Path:      "/mcp",
+				Transport: mcpserverv1.TransportSSE,
+			}},
+		},
+		{
+			name: "Verify that a route with TLS is listed with https",
+			cli: fake.NewClientBuilder().WithScheme(fakeScheme).WithRuntimeObjects(&routev1.Route{
+				ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+				Spec: routev1.RouteSpec{
+					Host: "mcp.apps.example.com",
+					TLS:  &routev1.TLSConfig{Termination: routev1.TLSTerminationEdge},
+				},
+			}).Build(),
+			want: []mcpserverv1.Endpoint{service, {
+				Type:      mcpserverv1.EndpointRoute,
+				URL:       "https://mcp.apps.example.com/sse",
+				Scheme:    "https",
+				Host:      "mcp.apps.example.com",
+				Path:      "/sse",
+				Transport: mcpserverv1.TransportSSE,
+			}},
+		},
+		{
+			name: "Verify that only the service is listed until the route has a host",
+			cli:  fake.NewClientBuilder().WithScheme(fakeScheme).Build(),
+			want: []mcpserverv1.Endpoint{service},
+		},
+		{
+			name: "Verify that the mesh gateway host and path follow the service",
+			spec: mcpserverv1.MCPServerSpec{
+				MeshGateway: &mcpserverv1.MeshGateway{Gateway: "istio-system/api-gateway", Host: "api.example.com", Path: "/mcp/k8s"},
+			},
+			cli: fake.NewClientBuilder().WithScheme(fakeScheme).Build(),
+			want: []mcpserverv1.Endpoint{service, {
+				Type:      mcpserverv1.EndpointMeshGateway,
+				URL:       "https://api.example.com/mcp/k8s/sse",
+				Scheme:    "https",
+				Host:      "api.example.com",
+				Path:      "/mcp/k8s/sse",
+				Transport: mcpserverv1.TransportSSE,
+			}},
+		},
+		{
+			name: "Verify that an external server only lists its URL",
+			spec: mcpserverv1.MCPServerSpec{Type: mcpserverv1.MCPServerExternal, URL: "https://mcp.example.com:8443/sse"},
+			cli:  fake.NewClientBuilder().WithScheme(fakeScheme).Build(),
+			want: []mcpserverv1.Endpoint{{
+				Type:      mcpserverv1.EndpointExternal,
+				URL:       "https://mcp.example.com:8443/sse",
+				Scheme:    "https",
+				Host:      "mcp.example.com:8443",
+				Path:      "/sse",
+				Transport: mcpserverv1.TransportSSE,
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &MCPServerReconciler{
+				Client: tt.cli,
+				Scheme: fakeScheme,
+			}
+			mcpServer := &mcpserverv1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+				Spec:       tt.spec,
+			}
+			got, err := r.getEndpoints(context.Background(), tt.cli, mcpServer)
+			if err != nil {
+				t.Errorf("getEndpoints() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getEndpoints() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_getEndpointCondition(t *testing.T) {
+	healthyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer healthyServer.Close()
+
+	brokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer brokenServer.Close()
+
+	fakeScheme := runtime.NewScheme()
+	err := routev1.AddToScheme(fakeScheme)
+	if err != nil {
+		t.Errorf("failed to add routev1 scheme: %v", err)
+	}
+
+	routeFor := func(server *httptest.Server) *routev1.Route {
+		return &routev1.Route{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      mcpServerName,
+				Namespace: testNamespace,
+			},
+			Spec: routev1.RouteSpec{
+				Host: strings.TrimPrefix(server.URL, "http://"),
+			},
+		}
+	}
+
+	availableServer := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mcpServerName,
+			Namespace: testNamespace,
+		},
+		Status: mcpserverv1.MCPServerStatus{
+			Conditions: []metav1.Condition{
+				{Type: DeploymentAvailable, Status: metav1.ConditionTrue},
+			},
+		},
+	}
+	unavailableServer := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mcpServerName,
+			Namespace: testNamespace,
+		},
+	}
+
+	externalServer := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mcpServerName,
+			Namespace: testNamespace,
+		},
+		Spec: mcpserverv1.MCPServerSpec{
+			Type: mcpserverv1.MCPServerExternal,
+			URL:  healthyServer.URL,
+		},
+	}
+
+	tests := []struct {
+		name       string
+		cli        client.Client
+		cr         *mcpserverv1.MCPServer
+		wantStatus metav1.ConditionStatus
+		wantReason string
+	}{
+		{
+			name:       "Verify that the endpoint is not probed while the deployment is unavailable",
+			cli:        fake.NewClientBuilder().WithScheme(fakeScheme).Build(),
+			cr:         unavailableServer,
+			wantStatus: metav1.ConditionUnknown,
+			wantReason: ReasonEndpointNotProbed,
+		},
+		{
+			name:       "Verify that a healthy endpoint returns the EndpointReady condition",
+			cli:        fake.NewClientBuilder().WithScheme(fakeScheme).WithRuntimeObjects(routeFor(healthyServer)).Build(),
+			cr:         availableServer,
+			wantStatus: metav1.ConditionTrue,
+			wantReason: fmt.Sprintf("%s%s", "Endpoint", ReasonReadySuffix),
+		},
+		{
+			name:       "Verify that an endpoint answering with a server error returns the EndpointUnreachable condition",
+			cli:        fake.NewClientBuilder().WithScheme(fakeScheme).WithRuntimeObjects(routeFor(brokenServer)).Build(),
+			cr:         availableServer,
+			wantStatus: metav1.ConditionFalse,
+			wantReason: ReasonEndpointUnreachable,
+		},
+		{
+			name:       "Verify that an external endpoint is probed without a deployment",
+			cli:        fake.NewClientBuilder().WithScheme(fakeScheme).Build(),
+			cr:         externalServer,
+			wantStatus: metav1.ConditionTrue,
+			wantReason: fmt.Sprintf("%s%s", "Endpoint", ReasonReadySuffix),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &MCPServerReconciler{
+				Client: tt.cli,
+				Scheme: fakeScheme,
+			}
+			got := r.getEndpointCondition(context.Background(), tt.cli, tt.cr)
+			if got.Type != EndpointReachable {
+				t.Errorf("getEndpointCondition() type = %v, want %v", got.Type, EndpointReachable)
+			}
+			if got.Status != tt.wantStatus || got.Reason != tt.wantReason {
+				t.Errorf("getEndpointCondition() = %v/%v, want %v/%v", got.Status, got.Reason, tt.wantStatus, tt.wantReason)
+			}
+		})
+	}
+}
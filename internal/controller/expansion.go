@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+const (
+	// ReasonUndeclaredVariable is set on the Available condition of an MCPServer whose spec.args reference an
+	// environment variable the MCP server container does not declare, which the kubelet would pass on literally.
+	ReasonUndeclaredVariable = "UndeclaredVariable"
+)
+
+// mcpServerEnv returns the environment variables of the MCP server container: those the operator sets, overridden
+// by spec.env. The kubelet expands references to them in spec.args, written $(NAME), so that secret-backed values
+// can be passed on the command line.
+func (r *MCPServerReconciler) mcpServerEnv(cr *mcpserverv1.MCPServer) []corev1.EnvVar {
+	env := append(append(r.proxyEnv(), sessionStoreEnv(cr)...), logForwardingEnv(cr)...)
+	return withEnv(env, cr.Spec.Env)
+}
+
+// argVariables returns the names of the variables the args reference with $(NAME), in order of appearance and
+// without duplicates. $$ escapes a $, as in the expansion of the kubelet.
+func argVariables(args []string) []string {
+	var names []string
+	for _, arg := range args {
+		for i := 0; i < len(arg); i++ {
+			if arg[i] != '$' || i+1 == len(arg) {
+				continue
+			}
+			switch arg[i+1] {
+			case '$':
+				i++
+			case '(':
+				end := strings.IndexByte(arg[i+2:], ')')
+				if end < 0 {
+					continue
+				}
+				if name := arg[i+2 : i+2+end]; name != "" && !slices.Contains(names, name) {
+					names = append(names, name)
+				}
+				i += 2 + end
+			}
+		}
+	}
+	return names
+}
+
+// getArgsCondition returns the Available condition of an MCPServer whose spec.args reference variables the MCP
+// server container does not declare, or nil when they declare all of them. The variables of spec.envFrom are only
+// known to the kubelet, so args are not checked when it is set.
+func (r *MCPServerReconciler) getArgsCondition(cr *mcpserverv1.MCPServer) *metav1.Condition {
+	if len(cr.Spec.Args) == 0 || len(cr.Spec.EnvFrom) > 0 || isExternal(cr) || isProxy(cr) {
+		return nil
+	}
+	declared := map[string]bool{}
+	for _, env := range r.mcpServerEnv(cr) {
+		declared[env.Name] = true
+	}
+	var undeclared []string
+	for _, name := range argVariables(cr.Spec.Args) {
+		if !declared[name] {
+			undeclared = append(undeclared, fmt.Sprintf("$(%s)", name))
+		}
+	}
+	if len(undeclared) == 0 {
+		return nil
+	}
+	return &metav1.Condition{
+		Type:   OverallAvailable,
+		Status: metav1.ConditionFalse,
+		Reason: ReasonUndeclaredVariable,
+		Message: fmt.Sprintf("spec.args reference %s, which the MCP server container does not declare as "+
+			"environment variables; declare them, or escape them as $$(NAME) to pass them on literally",
+			strings.Join(undeclared, ", ")),
+	}
+}
@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster"
+)
+
+func Test_argVariables(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{
+			name: "Verify that args without references reference nothing",
+			args: []string{"--port", "8000", "--price=$5"},
+		},
+		{
+			name: "Verify that references are returned in order without duplicates",
+			args: []string{"--token=$(API_TOKEN)", "--url", "$(MCP_SESSION_STORE_URL)/0", "--again=$(API_TOKEN)"},
+			want: []string{"API_TOKEN", "MCP_SESSION_STORE_URL"},
+		},
+		{
+			name: "Verify that escaped references are skipped",
+			args: []string{"--literal=$$(API_TOKEN)", "--both=$$$(REAL)"},
+			want: []string{"REAL"},
+		},
+		{
+			name: "Verify that unterminated and empty references are skipped",
+			args: []string{"--open=$(API_TOKEN", "--empty=$()", "--end=$"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := argVariables(tt.args); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("argVariables() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_getArgsCondition(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        mcpserverv1.MCPServerSpec
+		platform    *cluster.Platform
+		wantMessage string
+	}{
+		{
+			name: "Verify that args referencing a secret-backed variable are accepted",
+			spec: mcpserverv1.MCPServerSpec{
+				Args: []string{"--session-store", "$(MCP_SESSION_STORE_URL)"},
+				SessionStore: &mcpserverv1.SessionStore{
+					URLSecretRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "redis"},
+						Key:                  "url",
+					},
+				},
+			},
+		},
+		{
+			name: "Verify that args referencing the variables of the egress proxy are accepted",
+			spec: mcpserverv1.MCPServerSpec{Args: []string{"--proxy=$(HTTPS_PROXY)"}},
+			platform: &cluster.Platform{
+				Name:  cluster.OpenShift,
+				Proxy: &cluster.Proxy{HTTPSProxy: "http://proxy.example.com:3128"},
+			},
+		},
+		{
+			name: "Verify that args referencing undeclared variables are reported",
+			spec: mcpserverv1.MCPServerSpec{Args: []string{"--token=$(API_TOKEN)", "--key=$(API_KEY)"}},
+			wantMessage: "spec.args reference $(API_TOKEN), $(API_KEY), which the MCP server container does not " +
+				"declare as environment variables; declare them, or escape them as $$(NAME) to pass them on literally",
+		},
+		{
+			name: "Verify that args referencing the variables of spec.env are accepted",
+			spec: mcpserverv1.MCPServerSpec{
+				Args: []string{"--token=$(API_TOKEN)"},
+				Env:  []corev1.EnvVar{{Name: "API_TOKEN", Value: "secret"}},
+			},
+		},
+		{
+			name: "Verify that args are not checked when the variables come from spec.envFrom",
+			spec: mcpserverv1.MCPServerSpec{
+				Args: []string{"--token=$(API_TOKEN)"},
+				EnvFrom: []corev1.EnvFromSource{{
+					SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "api"}},
+				}},
+			},
+		},
+		{
+			name: "Verify that escaped references are accepted",
+			spec: mcpserverv1.MCPServerSpec{Args: []string{"--template=$$(API_TOKEN)"}},
+		},
+		{
+			name: "Verify that the args of a Proxy server are not checked",
+			spec: mcpserverv1.MCPServerSpec{
+				Type: mcpserverv1.MCPServerProxy,
+				Args: []string{"--token=$(API_TOKEN)"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &MCPServerReconciler{Platform: tt.platform}
+			cr := &mcpserverv1.MCPServer{Spec: tt.spec}
+			cr.Name = mcpServerName
+			cr.Namespace = testNamespace
+
+			got := r.getArgsCondition(cr)
+			if tt.wantMessage == "" {
+				if got != nil {
+					t.Errorf("getArgsCondition() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil || got.Reason != ReasonUndeclaredVariable || got.Message != tt.wantMessage {
+				t.Errorf("getArgsCondition() = %+v, want reason %s and message %q", got, ReasonUndeclaredVariable,
+					tt.wantMessage)
+			}
+		})
+	}
+}
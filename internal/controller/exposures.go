@@ -0,0 +1,422 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	routev1 "github.com/openshift/api/route/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// +kubebuilder:rbac:groups="networking.k8s.io",resources=ingresses,verbs=create;get;list;watch;update;patch;delete
+
+const (
+	// ExposureAvailablePrefix prefixes the type of the condition that reports whether an exposure of
+	// spec.exposures is available, which is followed by its name.
+	ExposureAvailablePrefix = "ExposureAvailable-"
+
+	// ReasonRouteAPIUnavailable is set on the condition of a Route exposure on a cluster without the Route API.
+	ReasonRouteAPIUnavailable = "RouteAPIUnavailable"
+	// ReasonIngressNotReady is set on the condition of an Ingress exposure until its ingress controller reports
+	// a load balancer address.
+	ReasonIngressNotReady = "IngressNotReady"
+
+	// exposureLabelKey marks the Routes and Ingresses of spec.exposures with the name of their exposure.
+	exposureLabelKey = "mcpserver.opendatahub.io/exposure"
+)
+
+// exposureName returns the name of the Route or Ingress of the exposure of cr.
+func exposureName(cr *mcpserverv1.MCPServer, exposure mcpserverv1.Exposure) string {
+	return childName(cr, "expose-"+exposure.Name)
+}
+
+// exposureConditionType returns the type of the condition of the exposure.
+func exposureConditionType(exposure mcpserverv1.Exposure) string {
+	return ExposureAvailablePrefix + exposure.Name
+}
+
+// exposureLabels returns the labels of the Route or Ingress of the exposure of cr.
+func exposureLabels(cr *mcpserverv1.MCPServer, exposure mcpserverv1.Exposure) map[string]string {
+	return map[string]string{mcpServerAppLabelKey: resourceName(cr), exposureLabelKey: exposure.Name}
+}
+
+// exposureRoute returns the Route of a Route exposure of cr. It routes to the Service like the Route of cr, and
+// takes the same annotations, so that spec.expose.allowedSourceRanges restricts its clients too.
+func exposureRoute(cr *mcpserverv1.MCPServer, exposure mcpserverv1.Exposure) *routev1.Route {
+	route := &routev1.Route{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "route.openshift.io/v1",
+			Kind:       "Route",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      exposureName(cr, exposure),
+			Namespace: cr.Namespace,
+			Labels:    exposureLabels(cr, exposure),
+		},
+		Spec: routev1.RouteSpec{
+			Host: exposure.Host,
+			To: routev1.RouteTargetReference{
+				Kind: "Service",
+				Name: resourceName(cr),
+			},
+			Port: &routev1.RoutePort{
+				TargetPort: intstr.FromString("http"),
+			},
+			Path: cr.Spec.BasePath,
+			TLS:  routeTLS(cr),
+		},
+	}
+	for key, value := range routeAnnotations(cr) {
+		if value != "" {
+			metav1.SetMetaDataAnnotation(&route.ObjectMeta, key, value)
+		}
+	}
+	for key, value := range exposure.Annotations {
+		metav1.SetMetaDataAnnotation(&route.ObjectMeta, key, value)
+	}
+	return route
+}
+
+// exposureIngress returns the Ingress of an Ingress exposure of cr, which routes the base path of the MCP
+// server, or every path when it has none, to its Service.
+func exposureIngress(cr *mcpserverv1.MCPServer, exposure mcpserverv1.Exposure) *networkingv1.Ingress {
+	path := cr.Spec.BasePath
+	if path == "" {
+		path = "/"
+	}
+	ingress := &networkingv1.Ingress{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: networkingv1.SchemeGroupVersion.String(),
+			Kind:       "Ingress",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        exposureName(cr, exposure),
+			Namespace:   cr.Namespace,
+			Labels:      exposureLabels(cr, exposure),
+			Annotations: exposure.Annotations,
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: exposure.IngressClassName,
+			Rules: []networkingv1.IngressRule{{
+				Host: exposure.Host,
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{{
+							Path:     path,
+							PathType: ptr.To(networkingv1.PathTypePrefix),
+							Backend: networkingv1.IngressBackend{
+								Service: &networkingv1.IngressServiceBackend{
+									Name: resourceName(cr),
+									Port: networkingv1.ServiceBackendPort{Name: "http"},
+								},
+							},
+						}},
+					},
+				},
+			}},
+		},
+	}
+	if exposure.TLSSecretName != "" {
+		ingress.Spec.TLS = []networkingv1.IngressTLS{{Hosts: []string{exposure.Host}, SecretName: exposure.TLSSecretName}}
+	}
+	return ingress
+}
+
+// reconcileExposures creates or updates the Routes and Ingresses of spec.exposures, and removes those of
+// exposures that were removed or changed their type. Route exposures are skipped on clusters without the Route
+// API, their condition reports it. SharedHost exposures have no object of their own, the SharedHostReconciler
+// routes them.
+func (r *MCPServerReconciler) reconcileExposures(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) error {
+	wanted := map[string]bool{}
+	for _, exposure := range cr.Spec.Exposures {
+		switch exposure.Type {
+		case mcpserverv1.ExposureRoute:
+			if !r.routeAPIAvailable() {
+				continue
+			}
+			wanted["Route/"+exposureName(cr, exposure)] = true
+			if err := r.reconcileExposureRoute(ctx, cli, cr, exposure); err != nil {
+				return err
+			}
+		case mcpserverv1.ExposureIngress:
+			wanted["Ingress/"+exposureName(cr, exposure)] = true
+			if err := r.reconcileExposureIngress(ctx, cli, cr, exposure); err != nil {
+				return err
+			}
+		}
+	}
+	return r.deleteStaleExposures(ctx, cli, cr, wanted)
+}
+
+// reconcileExposureRoute creates the Route of a Route exposure of cr, and keeps its spec and annotations in line
+// with the exposure. A host the router generated and a TLS configuration set by hand are kept.
+func (r *MCPServerReconciler) reconcileExposureRoute(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer,
+	exposure mcpserverv1.Exposure) error {
+	desired := exposureRoute(cr, exposure)
+	if err := r.createChild(ctx, cli, cr, desired); err != nil {
+		return err
+	}
+
+	route := &routev1.Route{}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(desired), route); err != nil {
+		// A Route that was only just created is not in the cache yet.
+		if k8serr.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	original := route.DeepCopy()
+	if desired.Spec.Host != "" {
+		route.Spec.Host = desired.Spec.Host
+	}
+	route.Spec.To = desired.Spec.To
+	route.Spec.Port = desired.Spec.Port
+	route.Spec.Path = desired.Spec.Path
+	if route.Spec.TLS == nil {
+		route.Spec.TLS = desired.Spec.TLS
+	}
+	for key, value := range routeAnnotations(cr) {
+		if value == "" {
+			delete(route.Annotations, key)
+		}
+	}
+	for key, value := range desired.Annotations {
+		metav1.SetMetaDataAnnotation(&route.ObjectMeta, key, value)
+	}
+	keepIgnoredAnnotations(cr, original, route)
+	if equality.Semantic.DeepEqual(original.Spec, route.Spec) &&
+		equality.Semantic.DeepEqual(original.Annotations, route.Annotations) {
+		return nil
+	}
+	logChildDiff(ctx, original, route)
+	return cli.Patch(ctx, route, client.MergeFrom(original))
+}
+
+// reconcileExposureIngress creates the Ingress of an Ingress exposure of cr, and keeps its spec and annotations
+// in line with the exposure. The IngressClass the API server defaults an Ingress without one to is kept.
+func (r *MCPServerReconciler) reconcileExposureIngress(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer,
+	exposure mcpserverv1.Exposure) error {
+	desired := exposureIngress(cr, exposure)
+	if err := r.createChild(ctx, cli, cr, desired); err != nil {
+		return err
+	}
+
+	ingress := &networkingv1.Ingress{}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(desired), ingress); err != nil {
+		// An Ingress that was only just created is not in the cache yet.
+		if k8serr.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	original := ingress.DeepCopy()
+	className := ingress.Spec.IngressClassName
+	ingress.Spec = desired.Spec
+	if ingress.Spec.IngressClassName == nil {
+		ingress.Spec.IngressClassName = className
+	}
+	for key, value := range desired.Annotations {
+		metav1.SetMetaDataAnnotation(&ingress.ObjectMeta, key, value)
+	}
+	keepIgnoredAnnotations(cr, original, ingress)
+	if equality.Semantic.DeepEqual(original.Spec, ingress.Spec) &&
+		equality.Semantic.DeepEqual(original.Annotations, ingress.Annotations) {
+		return nil
+	}
+	logChildDiff(ctx, original, ingress)
+	return cli.Patch(ctx, ingress, client.MergeFrom(original))
+}
+
+// deleteStaleExposures removes the Routes and Ingresses of exposures of cr that are not wanted anymore, keyed by
+// kind and name. Only objects that carry the exposure label and are controlled by cr are removed.
+func (r *MCPServerReconciler) deleteStaleExposures(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer,
+	wanted map[string]bool) error {
+	selector := client.MatchingLabels{mcpServerAppLabelKey: resourceName(cr)}
+	hasExposure := client.HasLabels{exposureLabelKey}
+
+	var stale []client.Object
+	ingresses := &networkingv1.IngressList{}
+	if err := cli.List(ctx, ingresses, client.InNamespace(cr.Namespace), selector, hasExposure); err != nil {
+		return err
+	}
+	for i := range ingresses.Items {
+		if !wanted["Ingress/"+ingresses.Items[i].Name] {
+			stale = append(stale, &ingresses.Items[i])
+		}
+	}
+	if r.routeAPIAvailable() {
+		routes := &routev1.RouteList{}
+		if err := cli.List(ctx, routes, client.InNamespace(cr.Namespace), selector, hasExposure); err != nil {
+			return err
+		}
+		for i := range routes.Items {
+			if !wanted["Route/"+routes.Items[i].Name] {
+				stale = append(stale, &routes.Items[i])
+			}
+		}
+	}
+
+	for _, obj := range stale {
+		if !metav1.IsControlledBy(obj, cr) {
+			continue
+		}
+		if err := cli.Delete(ctx, obj); err != nil && !k8serr.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// setExposureConditions sets a condition for every exposure of cr and removes those of exposures that were
+// removed. They do not count towards the Available condition, which reports the primary exposure.
+func (r *MCPServerReconciler) setExposureConditions(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) {
+	current := map[string]bool{}
+	for _, exposure := range cr.Spec.Exposures {
+		condition := r.getExposureCondition(ctx, cli, cr, exposure)
+		current[condition.Type] = true
+		meta.SetStatusCondition(&cr.Status.Conditions, condition)
+	}
+	for _, condition := range append([]metav1.Condition{}, cr.Status.Conditions...) {
+		if strings.HasPrefix(condition.Type, ExposureAvailablePrefix) && !current[condition.Type] {
+			meta.RemoveStatusCondition(&cr.Status.Conditions, condition.Type)
+		}
+	}
+}
+
+// getExposureCondition returns the condition of the exposure of cr: a Route is available once a router admitted
+// it, an Ingress once its ingress controller reports a load balancer address, a SharedHost exposure once the
+// shared host router is.
+func (r *MCPServerReconciler) getExposureCondition(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer,
+	exposure mcpserverv1.Exposure) metav1.Condition {
+	name := exposureName(cr, exposure)
+	condition := metav1.Condition{Type: exposureConditionType(exposure)}
+
+	var obj client.Object
+	switch exposure.Type {
+	case mcpserverv1.ExposureSharedHost:
+		return r.getSharedHostCondition(ctx, cli, cr, condition)
+	case mcpserverv1.ExposureRoute:
+		if !r.routeAPIAvailable() {
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = ReasonRouteAPIUnavailable
+			condition.Message = fmt.Sprintf("Route %s cannot be created, the cluster does not serve the Route API", name)
+			return condition
+		}
+		obj = &routev1.Route{}
+	default:
+		obj = &networkingv1.Ingress{}
+	}
+	kind := string(exposure.Type)
+
+	if err := cli.Get(ctx, client.ObjectKey{Name: name, Namespace: cr.Namespace}, obj); err != nil {
+		if k8serr.IsNotFound(err) {
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = kind + ReasonNotFoundSuffix
+			condition.Message = fmt.Sprintf("%s %s not found", kind, name)
+			return condition
+		}
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = kind + ReasonGetFailedSuffix
+		condition.Message = fmt.Sprintf("Failed to get %s %s: %v", kind, name, err)
+		return condition
+	}
+
+	switch obj := obj.(type) {
+	case *routev1.Route:
+		if !routeAdmitted(obj) {
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = ReasonRouteNotAdmitted
+			condition.Message = fmt.Sprintf("Route %s has not been admitted by a router yet", name)
+			return condition
+		}
+		condition.Message = fmt.Sprintf("Route %s is admitted and active", name)
+	case *networkingv1.Ingress:
+		if len(obj.Status.LoadBalancer.Ingress) == 0 {
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = ReasonIngressNotReady
+			condition.Message = fmt.Sprintf("Ingress %s has no load balancer address yet", name)
+			return condition
+		}
+		condition.Message = fmt.Sprintf("Ingress %s is served by its ingress controller", name)
+	}
+	condition.Status = metav1.ConditionTrue
+	condition.Reason = kind + ReasonReadySuffix
+	return condition
+}
+
+// getExposureEndpoints returns the endpoints of the exposures of cr whose host is known, in their order.
+func (r *MCPServerReconciler) getExposureEndpoints(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) ([]mcpserverv1.Endpoint, error) {
+	var endpoints []mcpserverv1.Endpoint
+	for _, exposure := range cr.Spec.Exposures {
+		key := client.ObjectKey{Name: exposureName(cr, exposure), Namespace: cr.Namespace}
+		switch exposure.Type {
+		case mcpserverv1.ExposureRoute:
+			if !r.routeAPIAvailable() {
+				continue
+			}
+			route := &routev1.Route{}
+			if err := cli.Get(ctx, key, route); err != nil {
+				if k8serr.IsNotFound(err) {
+					continue
+				}
+				return nil, err
+			}
+			if url := routeURL(cr, route); url != "" {
+				endpoints = append(endpoints, newEndpoint(mcpserverv1.EndpointRoute, url))
+			}
+		case mcpserverv1.ExposureIngress:
+			ingress := &networkingv1.Ingress{}
+			if err := cli.Get(ctx, key, ingress); err != nil {
+				if k8serr.IsNotFound(err) {
+					continue
+				}
+				return nil, err
+			}
+			if url := ingressURL(cr, exposure, ingress); url != "" {
+				endpoints = append(endpoints, newEndpoint(mcpserverv1.EndpointIngress, url))
+			}
+		case mcpserverv1.ExposureSharedHost:
+			if r.SharedHost != "" {
+				endpoints = append(endpoints, newEndpoint(mcpserverv1.EndpointSharedHost, sharedHostURL(cr, r.SharedHost)))
+			}
+		}
+	}
+	return endpoints, nil
+}
+
+// ingressURL returns the URL of the MCP server of cr on the Ingress of the exposure: on its host, or on the load
+// balancer address of an Ingress without one, or "" while that is not known yet.
+func ingressURL(cr *mcpserverv1.MCPServer, exposure mcpserverv1.Exposure, ingress *networkingv1.Ingress) string {
+	host := exposure.Host
+	if host == "" {
+		for _, address := range ingress.Status.LoadBalancer.Ingress {
+			host = address.Hostname
+			if host == "" && strings.Contains(address.IP, ":") {
+				host = "[" + address.IP + "]"
+			} else if host == "" {
+				host = address.IP
+			}
+			if host != "" {
+				break
+			}
+		}
+	}
+	if host == "" {
+		return ""
+	}
+	scheme := "http"
+	if exposure.TLSSecretName != "" {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s%s", scheme, host, mcpServerPath(cr))
+}
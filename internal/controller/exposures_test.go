@@ -0,0 +1,233 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	routev1 "github.com/openshift/api/route/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster"
+)
+
+func Test_ingressURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		exposure  mcpserverv1.Exposure
+		addresses []networkingv1.IngressLoadBalancerIngress
+		want      string
+	}{
+		{
+			name:     "Verify that the host of the exposure is used",
+			exposure: mcpserverv1.Exposure{Host: "mcp.example.com"},
+			want:     "http://mcp.example.com/sse",
+		},
+		{
+			name:     "Verify that an exposure with a TLS secret is served over HTTPS",
+			exposure: mcpserverv1.Exposure{Host: "mcp.example.com", TLSSecretName: "mcp-tls"},
+			want:     "https://mcp.example.com/sse",
+		},
+		{
+			name:      "Verify that the load balancer address is used without a host",
+			addresses: []networkingv1.IngressLoadBalancerIngress{{IP: "203.0.113.10"}},
+			want:      "http://203.0.113.10/sse",
+		},
+		{
+			name:      "Verify that IPv6 load balancer addresses are bracketed",
+			addresses: []networkingv1.IngressLoadBalancerIngress{{IP: "2001:db8::10"}},
+			want:      "http://[2001:db8::10]/sse",
+		},
+		{
+			name: "Verify that there is no URL until the load balancer address is known",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &mcpserverv1.MCPServer{ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace}}
+			ingress := &networkingv1.Ingress{}
+			ingress.Status.LoadBalancer.Ingress = tt.addresses
+			if got := ingressURL(cr, tt.exposure, ingress); got != tt.want {
+				t.Errorf("ingressURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_reconcileExposures(t *testing.T) {
+	fakeScheme := newAutoscalingScheme(t)
+	if err := routev1.AddToScheme(fakeScheme); err != nil {
+		t.Fatalf("failed to add routev1 scheme: %v", err)
+	}
+	cr := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace, UID: "uid"},
+		Spec: mcpserverv1.MCPServerSpec{
+			Image:  mcpServerImage,
+			Expose: &mcpserverv1.Expose{AllowedSourceRanges: []string{"10.0.0.0/8"}},
+			Exposures: []mcpserverv1.Exposure{
+				{Name: "internal", Type: mcpserverv1.ExposureRoute, Host: "mcp.apps.internal.example.com"},
+				{
+					Name:             "public",
+					Type:             mcpserverv1.ExposureIngress,
+					Host:             "mcp.example.com",
+					IngressClassName: ptr.To("external-lb"),
+					TLSSecretName:    "mcp-tls",
+					Annotations:      map[string]string{"lb.example.com/idle-timeout": "3600"},
+				},
+			},
+		},
+	}
+	cli := fake.NewClientBuilder().WithScheme(fakeScheme).Build()
+	// Without a detected platform, the Route API is assumed to be served.
+	r := &MCPServerReconciler{Client: cli, Scheme: fakeScheme}
+	ctx := context.Background()
+
+	if err := r.reconcileExposures(ctx, cli, cr); err != nil {
+		t.Fatalf("reconcileExposures() error = %v", err)
+	}
+
+	route := &routev1.Route{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: mcpServerName + "-expose-internal", Namespace: testNamespace},
+		route); err != nil {
+		t.Fatalf("Get() Route error = %v", err)
+	}
+	if route.Spec.Host != "mcp.apps.internal.example.com" || route.Spec.To.Name != mcpServerName {
+		t.Errorf("Route spec = %+v, want host mcp.apps.internal.example.com to Service %s", route.Spec, mcpServerName)
+	}
+	if route.Annotations[routeIPAllowlistAnnotation] != "10.0.0.0/8" {
+		t.Errorf("Route annotations = %v, want the allowlist of spec.expose", route.Annotations)
+	}
+	if !metav1.IsControlledBy(route, cr) || route.Labels[exposureLabelKey] != "internal" {
+		t.Errorf("Route metadata = %+v, want it controlled by the MCPServer and labeled", route.ObjectMeta)
+	}
+
+	ingress := &networkingv1.Ingress{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: mcpServerName + "-expose-public", Namespace: testNamespace},
+		ingress); err != nil {
+		t.Fatalf("Get() Ingress error = %v", err)
+	}
+	if ptr.Deref(ingress.Spec.IngressClassName, "") != "external-lb" {
+		t.Errorf("Ingress class = %v, want external-lb", ingress.Spec.IngressClassName)
+	}
+	if len(ingress.Spec.TLS) != 1 || ingress.Spec.TLS[0].SecretName != "mcp-tls" {
+		t.Errorf("Ingress TLS = %+v, want secret mcp-tls", ingress.Spec.TLS)
+	}
+	backend := ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service
+	if ingress.Spec.Rules[0].Host != "mcp.example.com" || backend.Name != mcpServerName || backend.Port.Name != "http" {
+		t.Errorf("Ingress rules = %+v, want host mcp.example.com to port http of Service %s", ingress.Spec.Rules,
+			mcpServerName)
+	}
+	if ingress.Annotations["lb.example.com/idle-timeout"] != "3600" {
+		t.Errorf("Ingress annotations = %v, want the annotations of the exposure", ingress.Annotations)
+	}
+
+	// A changed exposure is applied to its Ingress, and a removed one is deleted.
+	cr.Spec.Exposures = cr.Spec.Exposures[1:]
+	cr.Spec.Exposures[0].Host = "mcp.example.org"
+	if err := r.reconcileExposures(ctx, cli, cr); err != nil {
+		t.Fatalf("reconcileExposures() error = %v", err)
+	}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(route), &routev1.Route{}); !k8serr.IsNotFound(err) {
+		t.Errorf("Get() Route error = %v, want the Route of the removed exposure deleted", err)
+	}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(ingress), ingress); err != nil {
+		t.Fatalf("Get() Ingress error = %v", err)
+	}
+	if ingress.Spec.Rules[0].Host != "mcp.example.org" {
+		t.Errorf("Ingress host = %s, want mcp.example.org", ingress.Spec.Rules[0].Host)
+	}
+}
+
+func TestMCPServerReconciler_Reconcile_exposures(t *testing.T) {
+	fakeScheme := newAutoscalingScheme(t)
+	cr := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+		Spec: mcpserverv1.MCPServerSpec{
+			Image: mcpServerImage,
+			Exposures: []mcpserverv1.Exposure{
+				{Name: "public", Type: mcpserverv1.ExposureIngress, Host: "mcp.example.com"},
+				{Name: "apps", Type: mcpserverv1.ExposureRoute},
+			},
+		},
+	}
+	cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithStatusSubresource(&mcpserverv1.MCPServer{}).
+		WithObjects(cr).Build()
+	r := &MCPServerReconciler{
+		Client:   cli,
+		Scheme:   fakeScheme,
+		Platform: &cluster.Platform{Name: cluster.Kubernetes},
+	}
+	ctx := context.Background()
+	reconcile := func() {
+		t.Helper()
+		if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cr)}); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		if err := cli.Get(ctx, client.ObjectKeyFromObject(cr), cr); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+	}
+
+	reconcile()
+	if got := meta.FindStatusCondition(cr.Status.Conditions, "ExposureAvailable-public"); got == nil ||
+		got.Reason != ReasonIngressNotReady {
+		t.Errorf("ExposureAvailable-public condition = %+v, want reason %s", got, ReasonIngressNotReady)
+	}
+	if got := meta.FindStatusCondition(cr.Status.Conditions, "ExposureAvailable-apps"); got == nil ||
+		got.Reason != ReasonRouteAPIUnavailable {
+		t.Errorf("ExposureAvailable-apps condition = %+v, want reason %s", got, ReasonRouteAPIUnavailable)
+	}
+
+	// The Ingress becomes available once its ingress controller reports an address, and is listed as an
+	// endpoint before the primary one.
+	ingress := &networkingv1.Ingress{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: mcpServerName + "-expose-public", Namespace: testNamespace},
+		ingress); err != nil {
+		t.Fatalf("Get() Ingress error = %v", err)
+	}
+	ingress.Status.LoadBalancer.Ingress = []networkingv1.IngressLoadBalancerIngress{{IP: "203.0.113.10"}}
+	if err := cli.Status().Update(ctx, ingress); err != nil {
+		t.Fatalf("Update() Ingress status error = %v", err)
+	}
+	reconcile()
+	if !meta.IsStatusConditionTrue(cr.Status.Conditions, "ExposureAvailable-public") {
+		t.Errorf("ExposureAvailable-public condition is not True: %+v", cr.Status.Conditions)
+	}
+	wantEndpoints := []mcpserverv1.EndpointType{mcpserverv1.EndpointService, mcpserverv1.EndpointIngress}
+	if len(cr.Status.Endpoints) != len(wantEndpoints) {
+		t.Fatalf("status.endpoints = %+v, want types %v", cr.Status.Endpoints, wantEndpoints)
+	}
+	for i, endpoint := range cr.Status.Endpoints {
+		if endpoint.Type != wantEndpoints[i] {
+			t.Errorf("status.endpoints[%d] type = %s, want %s", i, endpoint.Type, wantEndpoints[i])
+		}
+	}
+	if cr.Status.Endpoints[1].URL != "http://mcp.example.com/sse" {
+		t.Errorf("Ingress endpoint = %s, want http://mcp.example.com/sse", cr.Status.Endpoints[1].URL)
+	}
+
+	// Removing the exposures removes their Ingress and conditions.
+	cr.Spec.Exposures = nil
+	if err := cli.Update(ctx, cr); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	reconcile()
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(ingress), &networkingv1.Ingress{}); !k8serr.IsNotFound(err) {
+		t.Errorf("Get() Ingress error = %v, want it deleted", err)
+	}
+	for _, condition := range cr.Status.Conditions {
+		if condition.Type == "ExposureAvailable-public" || condition.Type == "ExposureAvailable-apps" {
+			t.Errorf("condition %s kept after its exposure was removed", condition.Type)
+		}
+	}
+	if cr.Status.Endpoints[len(cr.Status.Endpoints)-1].Type != mcpserverv1.EndpointService {
+		t.Errorf("status.endpoints = %+v, want only the Service", cr.Status.Endpoints)
+	}
+}
@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	routev1 "github.com/openshift/api/route/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// isExternal reports whether the MCP server is hosted elsewhere and has no workload managed by the operator.
+func isExternal(cr *mcpserverv1.MCPServer) bool {
+	return cr.Spec.Type == mcpserverv1.MCPServerExternal
+}
+
+// reconcileExternal removes the workload of an MCPServer that was switched to External, along with the
+// status that describes it.
+func (r *MCPServerReconciler) reconcileExternal(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) error {
+	children := []client.Object{&appsv1.Deployment{}, &corev1.Service{}}
+	if r.routeAPIAvailable() {
+		children = append(children, &routev1.Route{})
+	}
+	for _, obj := range children {
+		obj.SetName(resourceName(cr))
+		obj.SetNamespace(cr.Namespace)
+		if err := r.deleteChild(ctx, cli, cr, obj); err != nil {
+			return err
+		}
+	}
+	if err := r.deleteHTTPRoute(ctx, cli, cr); err != nil {
+		return err
+	}
+	if err := r.deleteVirtualService(ctx, cli, cr); err != nil {
+		return err
+	}
+	if err := r.deleteAutoscaler(ctx, cli, cr); err != nil {
+		return err
+	}
+	if err := r.deleteNetworkPolicy(ctx, cli, cr); err != nil {
+		return err
+	}
+	if err := r.deleteSessionStore(ctx, cli, cr); err != nil {
+		return err
+	}
+	if err := r.deleteMetricsExporter(ctx, cli, cr); err != nil {
+		return err
+	}
+	if err := r.deleteRESTBridge(ctx, cli, cr); err != nil {
+		return err
+	}
+	if err := r.reconcileAuthToken(ctx, cli, cr); err != nil {
+		return err
+	}
+
+	for _, conditionType := range []string{DeploymentAvailable, ServiceAvailable, RouteAvailable, HTTPRouteAccepted, VirtualServiceAvailable, Degraded,
+		Progressing, GuardrailsAvailable, CapabilityFilterAvailable, MetricsExporterAvailable, RateLimiterAvailable, TokenAuthAvailable,
+		RESTBridgeAvailable} {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, conditionType)
+	}
+	cr.Status.PodSummary = nil
+	cr.Status.Replicas = 0
+	cr.Status.ReadyReplicas = 0
+	cr.Status.Autoscaler = nil
+	cr.Status.Revisions = nil
+	return nil
+}
+
+// getCredentialsToken returns the bearer token of an External MCP server, or an empty string when it has none.
+func (r *MCPServerReconciler) getCredentialsToken(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) (string, error) {
+	ref := cr.Spec.CredentialsSecretRef
+	if usesTokenAuth(cr) {
+		ref = authTokenSecretRef(cr)
+	} else if !isExternal(cr) {
+		ref = nil
+	}
+	if ref == nil {
+		return "", nil
+	}
+
+	secret := &corev1.Secret{}
+	err := cli.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: cr.Namespace}, secret)
+	if err != nil {
+		return "", err
+	}
+	token, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("secret %s has no key %s", ref.Name, ref.Key)
+	}
+	return string(token), nil
+}
+
+// getExternalOverallCondition returns the Available condition of an External MCP server, which only depends on
+// its endpoint.
+func getExternalOverallCondition(cr *mcpserverv1.MCPServer) metav1.Condition {
+	endpointCondition := meta.FindStatusCondition(cr.Status.Conditions, EndpointReachable)
+	if endpointCondition == nil || endpointCondition.Status != metav1.ConditionTrue {
+		return metav1.Condition{
+			Type:    OverallAvailable,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonEndpointUnreachable,
+			Message: "Endpoint is not yet reachable",
+		}
+	}
+	return metav1.Condition{
+		Type:    OverallAvailable,
+		Status:  metav1.ConditionTrue,
+		Reason:  "EndpointReady",
+		Message: "The external endpoint is reachable",
+	}
+}
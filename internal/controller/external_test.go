@@ -0,0 +1,187 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMCPServerReconciler_reconcileExternal(t *testing.T) {
+	fakeScheme := runtime.NewScheme()
+	err := clientgoscheme.AddToScheme(fakeScheme)
+	if err != nil {
+		t.Errorf("failed to add client-go scheme: %v", err)
+	}
+	err = mcpserverv1.AddToScheme(fakeScheme)
+	if err != nil {
+		t.Errorf("failed to add mcpserverv1 scheme: %v", err)
+	}
+	err = routev1.AddToScheme(fakeScheme)
+	if err != nil {
+		t.Errorf("failed to add routev1 scheme: %v", err)
+	}
+
+	cr := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace, UID: types.UID("uid")},
+		Spec: mcpserverv1.MCPServerSpec{
+			Type: mcpserverv1.MCPServerExternal,
+			URL:  "https://mcp.example.com/mcp",
+		},
+		Status: mcpserverv1.MCPServerStatus{
+			Conditions: []metav1.Condition{
+				{Type: DeploymentAvailable, Status: metav1.ConditionTrue, Reason: "DeploymentReady"},
+				{Type: ServiceAvailable, Status: metav1.ConditionTrue, Reason: "ServiceReady"},
+				{Type: EndpointReachable, Status: metav1.ConditionTrue, Reason: "EndpointReady"},
+			},
+			Replicas:      1,
+			ReadyReplicas: 1,
+		},
+	}
+	owner := metav1.OwnerReference{
+		APIVersion: mcpserverv1.GroupVersion.String(),
+		Kind:       "MCPServer",
+		Name:       cr.Name,
+		UID:        cr.UID,
+		Controller: ptr.To(true),
+	}
+
+	tests := []struct {
+		name       string
+		objects    []client.Object
+		wantExists bool
+	}{
+		{
+			name: "no workload",
+		},
+		{
+			name: "owned workload is deleted",
+			objects: []client.Object{
+				&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+					Name: mcpServerName, Namespace: testNamespace, OwnerReferences: []metav1.OwnerReference{owner},
+				}},
+				&corev1.Service{ObjectMeta: metav1.ObjectMeta{
+					Name: mcpServerName, Namespace: testNamespace, OwnerReferences: []metav1.OwnerReference{owner},
+				}},
+			},
+		},
+		{
+			name: "foreign workload is kept",
+			objects: []client.Object{
+				&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace}},
+				&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace}},
+			},
+			wantExists: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(tt.objects...).Build()
+			r := &MCPServerReconciler{Client: cli, Scheme: fakeScheme}
+			cr := cr.DeepCopy()
+
+			if err := r.reconcileExternal(context.Background(), cli, cr); err != nil {
+				t.Fatalf("reconcileExternal() error = %v", err)
+			}
+
+			key := client.ObjectKey{Name: mcpServerName, Namespace: testNamespace}
+			for _, obj := range []client.Object{&appsv1.Deployment{}, &corev1.Service{}} {
+				err := cli.Get(context.Background(), key, obj)
+				if tt.wantExists && err != nil {
+					t.Errorf("expected %T to be kept, got error %v", obj, err)
+				}
+				if !tt.wantExists && !k8serr.IsNotFound(err) {
+					t.Errorf("expected %T to be deleted, got error %v", obj, err)
+				}
+			}
+
+			for _, conditionType := range []string{DeploymentAvailable, ServiceAvailable} {
+				if meta.FindStatusCondition(cr.Status.Conditions, conditionType) != nil {
+					t.Errorf("expected condition %s to be removed", conditionType)
+				}
+			}
+			if meta.FindStatusCondition(cr.Status.Conditions, EndpointReachable) == nil {
+				t.Errorf("expected condition %s to be kept", EndpointReachable)
+			}
+			if cr.Status.Replicas != 0 || cr.Status.ReadyReplicas != 0 {
+				t.Errorf("replicas = %d/%d, want 0/0", cr.Status.ReadyReplicas, cr.Status.Replicas)
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_getCredentialsToken(t *testing.T) {
+	fakeScheme := runtime.NewScheme()
+	err := clientgoscheme.AddToScheme(fakeScheme)
+	if err != nil {
+		t.Errorf("failed to add client-go scheme: %v", err)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: testNamespace},
+		Data:       map[string][]byte{"token": []byte("secret-token")},
+	}
+	ref := func(key string) *corev1.SecretKeySelector {
+		return &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "credentials"}, Key: key}
+	}
+
+	tests := []struct {
+		name    string
+		spec    mcpserverv1.MCPServerSpec
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "managed server",
+			spec: mcpserverv1.MCPServerSpec{Image: mcpServerImage, CredentialsSecretRef: ref("token")},
+		},
+		{
+			name: "external server without credentials",
+			spec: mcpserverv1.MCPServerSpec{Type: mcpserverv1.MCPServerExternal, URL: "https://mcp.example.com"},
+		},
+		{
+			name: "external server with credentials",
+			spec: mcpserverv1.MCPServerSpec{
+				Type: mcpserverv1.MCPServerExternal, URL: "https://mcp.example.com", CredentialsSecretRef: ref("token"),
+			},
+			want: "secret-token",
+		},
+		{
+			name: "missing key",
+			spec: mcpserverv1.MCPServerSpec{
+				Type: mcpserverv1.MCPServerExternal, URL: "https://mcp.example.com", CredentialsSecretRef: ref("other"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(secret).Build()
+			r := &MCPServerReconciler{Client: cli, Scheme: fakeScheme}
+			cr := &mcpserverv1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+				Spec:       tt.spec,
+			}
+
+			got, err := r.getCredentialsToken(context.Background(), cli, cr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getCredentialsToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("getCredentialsToken() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
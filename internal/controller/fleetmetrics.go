@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// fleetMetricsTimeout bounds the listing of the MCPServers on a scrape of the fleet metrics.
+const fleetMetricsTimeout = 10 * time.Second
+
+var (
+	mcpServerInfoDesc = prometheus.NewDesc("mcpserver_operator_mcpserver_info",
+		"Information about an MCPServer: its type and the team and contact of its owner. Always 1.",
+		[]string{"namespace", "name", "type", "team", "contact"}, nil)
+	mcpServerReadyDesc = prometheus.NewDesc("mcpserver_operator_mcpserver_ready",
+		"Whether an MCPServer is available, 1 or 0, with the team of its owner.",
+		[]string{"namespace", "name", "team"}, nil)
+
+	teamMCPServersDesc = prometheus.NewDesc("mcpserver_operator_team_mcpservers",
+		"The number of MCPServers of a team in a namespace.",
+		[]string{"namespace", "team"}, nil)
+	teamReplicasDesc = prometheus.NewDesc("mcpserver_operator_team_replicas",
+		"The number of MCP server pods of a team in a namespace.",
+		[]string{"namespace", "team"}, nil)
+	teamReadyReplicasDesc = prometheus.NewDesc("mcpserver_operator_team_ready_replicas",
+		"The number of ready MCP server pods of a team in a namespace.",
+		[]string{"namespace", "team"}, nil)
+	teamRequestedCPUDesc = prometheus.NewDesc("mcpserver_operator_team_requested_cpu_cores",
+		"The CPU requested by the desired MCP server pods of a team in a namespace, sidecars included.",
+		[]string{"namespace", "team"}, nil)
+	teamRequestedMemoryDesc = prometheus.NewDesc("mcpserver_operator_team_requested_memory_bytes",
+		"The memory requested by the desired MCP server pods of a team in a namespace, sidecars included.",
+		[]string{"namespace", "team"}, nil)
+)
+
+// teamKey identifies the MCPServers of a team in a namespace. MCPServers without an owner have an empty team.
+type teamKey struct {
+	namespace, team string
+}
+
+// teamUsage is the aggregate of the MCPServers of a team in a namespace.
+type teamUsage struct {
+	servers, replicas, readyReplicas int32
+	// cpu is in cores, memory in bytes.
+	cpu, memory float64
+}
+
+// FleetCollector exports metrics about all MCPServers, listed from Reader on each scrape, so that platform teams
+// can attribute them to the teams owning them and alert those teams of failing servers. The MCPServers of each
+// team in a namespace are also aggregated into their number, pods and requested resources, for capacity planning.
+// It implements prometheus.Collector.
+type FleetCollector struct {
+	// Reader lists the MCPServers, usually from the manager's cache.
+	Reader client.Reader
+}
+
+// Describe implements prometheus.Collector.
+func (c *FleetCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- mcpServerInfoDesc
+	ch <- mcpServerReadyDesc
+	ch <- teamMCPServersDesc
+	ch <- teamReplicasDesc
+	ch <- teamReadyReplicasDesc
+	ch <- teamRequestedCPUDesc
+	ch <- teamRequestedMemoryDesc
+}
+
+// Collect implements prometheus.Collector. Nothing is collected when the MCPServers cannot be listed, e.g. before
+// the cache is started.
+func (c *FleetCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), fleetMetricsTimeout)
+	defer cancel()
+
+	servers := &mcpserverv1.MCPServerList{}
+	if err := c.Reader.List(ctx, servers); err != nil {
+		logf.FromContext(ctx).WithName("fleet-metrics").Error(err, "unable to list MCPServers")
+		return
+	}
+	teams := map[teamKey]*teamUsage{}
+	for i := range servers.Items {
+		cr := &servers.Items[i]
+		var team, contact string
+		if cr.Spec.Owner != nil {
+			team, contact = cr.Spec.Owner.Team, cr.Spec.Owner.Contact
+		}
+		serverType := cr.Spec.Type
+		if serverType == "" {
+			serverType = mcpserverv1.MCPServerManaged
+		}
+		ready := 0.0
+		if meta.IsStatusConditionTrue(cr.Status.Conditions, OverallAvailable) {
+			ready = 1
+		}
+		ch <- prometheus.MustNewConstMetric(mcpServerInfoDesc, prometheus.GaugeValue, 1,
+			cr.Namespace, cr.Name, string(serverType), team, contact)
+		ch <- prometheus.MustNewConstMetric(mcpServerReadyDesc, prometheus.GaugeValue, ready,
+			cr.Namespace, cr.Name, team)
+
+		key := teamKey{namespace: cr.Namespace, team: team}
+		usage := teams[key]
+		if usage == nil {
+			usage = &teamUsage{}
+			teams[key] = usage
+		}
+		usage.servers++
+		usage.replicas += cr.Status.Replicas
+		usage.readyReplicas += cr.Status.ReadyReplicas
+		if err := c.addRequests(ctx, cr, usage); err != nil {
+			logf.FromContext(ctx).WithName("fleet-metrics").Error(err, "unable to get the Deployment of MCPServer",
+				"namespace", cr.Namespace, "name", cr.Name)
+		}
+	}
+
+	for key, usage := range teams {
+		for desc, value := range map[*prometheus.Desc]float64{
+			teamMCPServersDesc:      float64(usage.servers),
+			teamReplicasDesc:        float64(usage.replicas),
+			teamReadyReplicasDesc:   float64(usage.readyReplicas),
+			teamRequestedCPUDesc:    usage.cpu,
+			teamRequestedMemoryDesc: usage.memory,
+		} {
+			ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, key.namespace, key.team)
+		}
+	}
+}
+
+// addRequests adds the CPU and memory requested by the desired pods of the Deployment of cr to usage. External
+// MCP servers have no Deployment and request nothing.
+func (c *FleetCollector) addRequests(ctx context.Context, cr *mcpserverv1.MCPServer, usage *teamUsage) error {
+	if isExternal(cr) {
+		return nil
+	}
+	deployment := &appsv1.Deployment{}
+	if err := c.Reader.Get(ctx, client.ObjectKey{Name: resourceName(cr), Namespace: cr.Namespace}, deployment); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	replicas := float64(ptr.Deref(deployment.Spec.Replicas, 1))
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if cpu, ok := container.Resources.Requests[corev1.ResourceCPU]; ok {
+			usage.cpu += cpu.AsApproximateFloat64() * replicas
+		}
+		if memory, ok := container.Resources.Requests[corev1.ResourceMemory]; ok {
+			usage.memory += memory.AsApproximateFloat64() * replicas
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+func TestFleetCollector_Collect(t *testing.T) {
+	fakeScheme := runtime.NewScheme()
+	if err := mcpserverv1.AddToScheme(fakeScheme); err != nil {
+		t.Errorf("failed to add mcpserverv1 scheme: %v", err)
+	}
+	if err := appsv1.AddToScheme(fakeScheme); err != nil {
+		t.Errorf("failed to add appsv1 scheme: %v", err)
+	}
+	requests := func(cpu, memory string) corev1.ResourceRequirements {
+		return corev1.ResourceRequirements{Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(memory),
+		}}
+	}
+	cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(
+		&mcpserverv1.MCPServer{
+			ObjectMeta: metav1.ObjectMeta{Name: "kubernetes", Namespace: "team-a"},
+			Spec: mcpserverv1.MCPServerSpec{
+				Owner: &mcpserverv1.Owner{Team: "payments", Contact: "payments@example.com"},
+			},
+			Status: mcpserverv1.MCPServerStatus{
+				Conditions:    []metav1.Condition{{Type: OverallAvailable, Status: metav1.ConditionTrue}},
+				Replicas:      2,
+				ReadyReplicas: 2,
+			},
+		},
+		&mcpserverv1.MCPServer{
+			ObjectMeta: metav1.ObjectMeta{Name: "helm", Namespace: "team-a"},
+			Spec: mcpserverv1.MCPServerSpec{
+				Owner: &mcpserverv1.Owner{Team: "payments"},
+			},
+			Status: mcpserverv1.MCPServerStatus{
+				Conditions: []metav1.Condition{{Type: OverallAvailable, Status: metav1.ConditionFalse}},
+				Replicas:   1,
+			},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "kubernetes", Namespace: "team-a"},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.To[int32](2),
+				Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{
+					{Name: "mcp-server", Resources: requests("250m", "256Mi")},
+					{Name: "metrics-exporter", Resources: requests("50m", "32Mi")},
+				}}},
+			},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "helm", Namespace: "team-a"},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.To[int32](1),
+				Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{
+					{Name: "mcp-server", Resources: requests("100m", "64Mi")},
+				}}},
+			},
+		},
+		&mcpserverv1.MCPServer{
+			ObjectMeta: metav1.ObjectMeta{Name: "remote", Namespace: "team-b"},
+			Spec:       mcpserverv1.MCPServerSpec{Type: mcpserverv1.MCPServerExternal},
+			Status: mcpserverv1.MCPServerStatus{
+				Conditions: []metav1.Condition{{Type: OverallAvailable, Status: metav1.ConditionFalse}},
+			},
+		},
+	).Build()
+
+	want := `
+# HELP mcpserver_operator_mcpserver_info Information about an MCPServer: its type and the team and contact of its owner. Always 1.
+# TYPE mcpserver_operator_mcpserver_info gauge
+mcpserver_operator_mcpserver_info{contact="",name="helm",namespace="team-a",team="payments",type="Managed"} 1
+mcpserver_operator_mcpserver_info{contact="payments@example.com",name="kubernetes",namespace="team-a",team="payments",type="Managed"} 1
+mcpserver_operator_mcpserver_info{contact="",name="remote",namespace="team-b",team="",type="External"} 1
+# HELP mcpserver_operator_mcpserver_ready Whether an MCPServer is available, 1 or 0, with the team of its owner.
+# TYPE mcpserver_operator_mcpserver_ready gauge
+mcpserver_operator_mcpserver_ready{name="helm",namespace="team-a",team="payments"} 0
+mcpserver_operator_mcpserver_ready{name="kubernetes",namespace="team-a",team="payments"} 1
+mcpserver_operator_mcpserver_ready{name="remote",namespace="team-b",team=""} 0
+# HELP mcpserver_operator_team_mcpservers The number of MCPServers of a team in a namespace.
+# TYPE mcpserver_operator_team_mcpservers gauge
+mcpserver_operator_team_mcpservers{namespace="team-a",team="payments"} 2
+mcpserver_operator_team_mcpservers{namespace="team-b",team=""} 1
+# HELP mcpserver_operator_team_ready_replicas The number of ready MCP server pods of a team in a namespace.
+# TYPE mcpserver_operator_team_ready_replicas gauge
+mcpserver_operator_team_ready_replicas{namespace="team-a",team="payments"} 2
+mcpserver_operator_team_ready_replicas{namespace="team-b",team=""} 0
+# HELP mcpserver_operator_team_replicas The number of MCP server pods of a team in a namespace.
+# TYPE mcpserver_operator_team_replicas gauge
+mcpserver_operator_team_replicas{namespace="team-a",team="payments"} 3
+mcpserver_operator_team_replicas{namespace="team-b",team=""} 0
+# HELP mcpserver_operator_team_requested_cpu_cores The CPU requested by the desired MCP server pods of a team in a namespace, sidecars included.
+# TYPE mcpserver_operator_team_requested_cpu_cores gauge
+mcpserver_operator_team_requested_cpu_cores{namespace="team-a",team="payments"} 0.7
+mcpserver_operator_team_requested_cpu_cores{namespace="team-b",team=""} 0
+# HELP mcpserver_operator_team_requested_memory_bytes The memory requested by the desired MCP server pods of a team in a namespace, sidecars included.
+# TYPE mcpserver_operator_team_requested_memory_bytes gauge
+mcpserver_operator_team_requested_memory_bytes{namespace="team-a",team="payments"} 6.7108864e+08
+mcpserver_operator_team_requested_memory_bytes{namespace="team-b",team=""} 0
+`
+	if err := testutil.CollectAndCompare(&FleetCollector{Reader: cli}, strings.NewReader(want)); err != nil {
+		t.Errorf("unexpected metrics: %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// +kubebuilder:rbac:groups=mcpserver.opendatahub.io,resources=mcpserverfleets,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=mcpserver.opendatahub.io,resources=mcpserverfleets/status,verbs=get;update;patch
+
+const (
+	// ReasonMCPServersDegraded is set on the Degraded condition of the MCPServerFleet when any MCPServer is
+	// degraded.
+	ReasonMCPServersDegraded = "MCPServersDegraded"
+)
+
+// fleetRequest is the request of the MCPServerFleet, which every change of an MCPServer enqueues.
+var fleetRequest = reconcile.Request{NamespacedName: types.NamespacedName{Name: mcpserverv1.MCPServerFleetName}}
+
+// FleetStatusReconciler maintains the MCPServerFleet named cluster, which summarizes the health of all MCPServers
+// in one object, so that cluster admins and umbrella operators need not list them.
+type FleetStatusReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// OperatorVersion is reported in status.operatorVersion.
+	OperatorVersion string
+}
+
+// Reconcile creates the MCPServerFleet if it is missing and updates its status from the MCPServers of the cluster.
+func (r *FleetStatusReconciler) Reconcile(ctx context.Context, _ ctrl.Request) (ctrl.Result, error) {
+	servers := &mcpserverv1.MCPServerList{}
+	if err := r.List(ctx, servers); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	fleet := &mcpserverv1.MCPServerFleet{}
+	err := r.Get(ctx, fleetRequest.NamespacedName, fleet)
+	if k8serr.IsNotFound(err) {
+		fleet = &mcpserverv1.MCPServerFleet{ObjectMeta: metav1.ObjectMeta{Name: mcpserverv1.MCPServerFleetName}}
+		err = r.Create(ctx, fleet)
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	original := fleet.DeepCopy()
+	status := fleetStatus(servers.Items, r.OperatorVersion)
+	// The conditions are set one by one to keep their last transition times.
+	status.Conditions = fleet.Status.Conditions
+	for _, condition := range fleetConditions(status) {
+		meta.SetStatusCondition(&status.Conditions, condition)
+	}
+	fleet.Status = status
+	if equality.Semantic.DeepEqual(original.Status, fleet.Status) {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{}, r.Status().Patch(ctx, fleet, client.MergeFrom(original))
+}
+
+// fleetStatus counts the MCPServers by their conditions. An MCPServer is degraded when its Degraded condition is
+// True, or when it is unavailable without a rollout in progress, so that servers that are only starting up are not
+// counted. Templates, which are never available, are not degraded either.
+func fleetStatus(servers []mcpserverv1.MCPServer, operatorVersion string) mcpserverv1.MCPServerFleetStatus {
+	status := mcpserverv1.MCPServerFleetStatus{OperatorVersion: operatorVersion, Total: int32(len(servers))}
+
+	conditionCounts := map[string]*mcpserverv1.ConditionCount{}
+	reasons := map[string]int32{}
+	for i := range servers {
+		conditions := servers[i].Status.Conditions
+		for _, condition := range conditions {
+			if conditionCounts[condition.Type] == nil {
+				conditionCounts[condition.Type] = &mcpserverv1.ConditionCount{Type: condition.Type}
+			}
+		}
+
+		if meta.IsStatusConditionTrue(conditions, OverallAvailable) {
+			status.Ready++
+		}
+		if reason, degraded := degradedReason(conditions); degraded {
+			status.Degraded++
+			reasons[reason]++
+		}
+	}
+
+	for conditionType, count := range conditionCounts {
+		for i := range servers {
+			condition := meta.FindStatusCondition(servers[i].Status.Conditions, conditionType)
+			switch {
+			case condition == nil || condition.Status == metav1.ConditionUnknown:
+				count.Unknown++
+			case condition.Status == metav1.ConditionTrue:
+				count.True++
+			default:
+				count.False++
+			}
+		}
+		status.ConditionCounts = append(status.ConditionCounts, *count)
+	}
+	sort.Slice(status.ConditionCounts, func(i, j int) bool {
+		return status.ConditionCounts[i].Type < status.ConditionCounts[j].Type
+	})
+
+	for reason, count := range reasons {
+		status.DegradedReasons = append(status.DegradedReasons, mcpserverv1.ReasonCount{Reason: reason, Count: count})
+	}
+	sort.Slice(status.DegradedReasons, func(i, j int) bool {
+		return status.DegradedReasons[i].Reason < status.DegradedReasons[j].Reason
+	})
+	return status
+}
+
+// degradedReason returns the reason an MCPServer with conditions is degraded for, and whether it is.
+func degradedReason(conditions []metav1.Condition) (string, bool) {
+	if degraded := meta.FindStatusCondition(conditions, Degraded); degraded != nil &&
+		degraded.Status == metav1.ConditionTrue {
+		return degraded.Reason, true
+	}
+	available := meta.FindStatusCondition(conditions, OverallAvailable)
+	if available == nil || available.Status != metav1.ConditionFalse || available.Reason == ReasonTemplate ||
+		meta.IsStatusConditionTrue(conditions, Progressing) {
+		return "", false
+	}
+	return available.Reason, true
+}
+
+// fleetConditions returns the Available and Degraded conditions of the MCPServerFleet with status.
+func fleetConditions(status mcpserverv1.MCPServerFleetStatus) []metav1.Condition {
+	available := metav1.Condition{
+		Type:    OverallAvailable,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonAsExpected,
+		Message: fmt.Sprintf("%d of %d MCPServers are ready", status.Ready, status.Total),
+	}
+	degraded := metav1.Condition{
+		Type:    Degraded,
+		Status:  metav1.ConditionFalse,
+		Reason:  ReasonAsExpected,
+		Message: "No MCPServer is degraded",
+	}
+	if status.Degraded > 0 {
+		reasons := make([]string, 0, len(status.DegradedReasons))
+		for _, reason := range status.DegradedReasons {
+			reasons = append(reasons, fmt.Sprintf("%d %s", reason.Count, reason.Reason))
+		}
+		degraded.Status = metav1.ConditionTrue
+		degraded.Reason = ReasonMCPServersDegraded
+		degraded.Message = fmt.Sprintf("%d of %d MCPServers are degraded: %s", status.Degraded, status.Total,
+			strings.Join(reasons, ", "))
+	}
+	return []metav1.Condition{available, degraded}
+}
+
+// SetupWithManager sets up the controller with the Manager. Every change of an MCPServer updates the summary.
+func (r *FleetStatusReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&mcpserverv1.MCPServerFleet{}).
+		Watches(&mcpserverv1.MCPServer{}, handler.EnqueueRequestsFromMapFunc(
+			func(context.Context, client.Object) []reconcile.Request {
+				return []reconcile.Request{fleetRequest}
+			})).
+		Named("mcpserverfleet").
+		Complete(r)
+}
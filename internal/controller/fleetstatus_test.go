@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// newFleetMCPServer returns an MCPServer named name with conditions.
+func newFleetMCPServer(name string, conditions ...metav1.Condition) mcpserverv1.MCPServer {
+	return mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Status:     mcpserverv1.MCPServerStatus{Conditions: conditions},
+	}
+}
+
+func Test_fleetStatus(t *testing.T) {
+	available := metav1.Condition{Type: OverallAvailable, Status: metav1.ConditionTrue, Reason: ReasonAsExpected}
+	imagePullFailed := metav1.Condition{Type: OverallAvailable, Status: metav1.ConditionFalse, Reason: ReasonImagePullFailed}
+	progressing := metav1.Condition{Type: Progressing, Status: metav1.ConditionTrue, Reason: "NewReplicaSetCreated"}
+	stuck := metav1.Condition{Type: Degraded, Status: metav1.ConditionTrue, Reason: ReasonProgressDeadlineExceeded}
+
+	tests := []struct {
+		name    string
+		servers []mcpserverv1.MCPServer
+		want    mcpserverv1.MCPServerFleetStatus
+	}{
+		{
+			name: "Verify that an empty fleet is reported with the operator version",
+			want: mcpserverv1.MCPServerFleetStatus{OperatorVersion: "1.2.0"},
+		},
+		{
+			name: "Verify that MCPServers are counted by condition and degraded reason",
+			servers: []mcpserverv1.MCPServer{
+				newFleetMCPServer("ready", available),
+				newFleetMCPServer("image", imagePullFailed),
+				newFleetMCPServer("stuck", available, stuck),
+				newFleetMCPServer("new"),
+			},
+			want: mcpserverv1.MCPServerFleetStatus{
+				OperatorVersion: "1.2.0",
+				Total:           4,
+				Ready:           2,
+				Degraded:        2,
+				ConditionCounts: []mcpserverv1.ConditionCount{
+					{Type: OverallAvailable, True: 2, False: 1, Unknown: 1},
+					{Type: Degraded, True: 1, Unknown: 3},
+				},
+				DegradedReasons: []mcpserverv1.ReasonCount{
+					{Reason: ReasonImagePullFailed, Count: 1},
+					{Reason: ReasonProgressDeadlineExceeded, Count: 1},
+				},
+			},
+		},
+		{
+			name: "Verify that an MCPServer that is rolling out is not degraded",
+			servers: []mcpserverv1.MCPServer{
+				newFleetMCPServer("rolling", imagePullFailed, progressing),
+			},
+			want: mcpserverv1.MCPServerFleetStatus{
+				OperatorVersion: "1.2.0",
+				Total:           1,
+				ConditionCounts: []mcpserverv1.ConditionCount{
+					{Type: OverallAvailable, False: 1},
+					{Type: Progressing, True: 1},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fleetStatus(tt.servers, "1.2.0"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fleetStatus() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFleetStatusReconciler_Reconcile(t *testing.T) {
+	fakeScheme := newAutoscalingScheme(t)
+	ready := newFleetMCPServer("ready",
+		metav1.Condition{Type: OverallAvailable, Status: metav1.ConditionTrue, Reason: ReasonAsExpected})
+	stuck := newFleetMCPServer("stuck",
+		metav1.Condition{Type: Degraded, Status: metav1.ConditionTrue, Reason: ReasonProgressDeadlineExceeded})
+	cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(&ready, &stuck).
+		WithStatusSubresource(&mcpserverv1.MCPServerFleet{}).Build()
+	r := &FleetStatusReconciler{Client: cli, Scheme: fakeScheme, OperatorVersion: "1.2.0"}
+
+	if _, err := r.Reconcile(context.Background(), fleetRequest); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	fleet := &mcpserverv1.MCPServerFleet{}
+	if err := cli.Get(context.Background(), client.ObjectKey{Name: mcpserverv1.MCPServerFleetName}, fleet); err != nil {
+		t.Fatalf("failed to get the MCPServerFleet: %v", err)
+	}
+	if fleet.Status.OperatorVersion != "1.2.0" || fleet.Status.Total != 2 || fleet.Status.Ready != 1 ||
+		fleet.Status.Degraded != 1 {
+		t.Errorf("status = %+v, want 2 MCPServers, 1 ready and 1 degraded", fleet.Status)
+	}
+	degraded := meta.FindStatusCondition(fleet.Status.Conditions, Degraded)
+	if degraded == nil || degraded.Status != metav1.ConditionTrue || degraded.Reason != ReasonMCPServersDegraded {
+		t.Fatalf("Degraded condition = %+v, want True with reason %s", degraded, ReasonMCPServersDegraded)
+	}
+	if want := "1 of 2 MCPServers are degraded: 1 ProgressDeadlineExceeded"; degraded.Message != want {
+		t.Errorf("Degraded message = %q, want %q", degraded.Message, want)
+	}
+
+	if err := cli.Delete(context.Background(), &stuck); err != nil {
+		t.Fatalf("failed to delete the MCPServer: %v", err)
+	}
+	if _, err := r.Reconcile(context.Background(), fleetRequest); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := cli.Get(context.Background(), client.ObjectKey{Name: mcpserverv1.MCPServerFleetName}, fleet); err != nil {
+		t.Fatalf("failed to get the MCPServerFleet: %v", err)
+	}
+	if meta.IsStatusConditionTrue(fleet.Status.Conditions, Degraded) || fleet.Status.DegradedReasons != nil {
+		t.Errorf("status = %+v, want no degraded MCPServer", fleet.Status)
+	}
+}
@@ -0,0 +1,377 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster/gvk"
+)
+
+const (
+	// HTTPRouteAccepted reports whether the HTTPRoute of an MCP server with a gatewayRef has been accepted by
+	// its Gateway. It replaces RouteAvailable for these servers.
+	HTTPRouteAccepted = "HTTPRouteAccepted"
+
+	// ReasonGatewayNotFound is set on the HTTPRouteAccepted condition when the referenced Gateway does not exist.
+	ReasonGatewayNotFound = "GatewayNotFound"
+	// ReasonNoMatchingListener is set on the HTTPRouteAccepted condition when the Gateway has no listener with
+	// the referenced sectionName.
+	ReasonNoMatchingListener = "NoMatchingListener"
+	// ReasonNotAllowedByListeners is set on the HTTPRouteAccepted condition when no listener of the Gateway
+	// admits HTTPRoutes from the namespace of the MCPServer.
+	ReasonNotAllowedByListeners = "NotAllowedByListeners"
+)
+
+// +kubebuilder:rbac:groups="gateway.networking.k8s.io",resources=httproutes,verbs=create;get;list;watch;update;patch;delete
+// +kubebuilder:rbac:groups="gateway.networking.k8s.io",resources=gateways,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+// usesGateway reports whether the MCP server is exposed through a shared Gateway rather than its own Route.
+func usesGateway(cr *mcpserverv1.MCPServer) bool {
+	return cr.Spec.GatewayRef != nil
+}
+
+// gatewayAPIAvailable reports whether HTTPRoutes can be created on the cluster.
+func (r *MCPServerReconciler) gatewayAPIAvailable() bool {
+	return r.Platform == nil || r.Platform.HasAPI(gvk.HTTPRoute)
+}
+
+// reconcileExposure creates the Route of cr, its HTTPRoute when it is attached to a shared Gateway or its
+// VirtualService when it is published on a mesh gateway, and removes the others. An HTTPRoute or VirtualService
+// is only looked for when the status shows that cr used one, so that clusters without their API are not queried
+// for it.
+func (r *MCPServerReconciler) reconcileExposure(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) error {
+	if usesMeshGateway(cr) {
+		if r.routeAPIAvailable() {
+			route := &routev1.Route{ObjectMeta: metav1.ObjectMeta{Name: resourceName(cr), Namespace: cr.Namespace}}
+			if err := r.deleteChild(ctx, cli, cr, route); err != nil {
+				return err
+			}
+		}
+		if err := r.deleteHTTPRoute(ctx, cli, cr); err != nil {
+			return err
+		}
+		return r.reconcileVirtualService(ctx, cli, cr)
+	}
+	if err := r.deleteVirtualService(ctx, cli, cr); err != nil {
+		return err
+	}
+
+	if usesGateway(cr) {
+		if r.routeAPIAvailable() {
+			route := &routev1.Route{ObjectMeta: metav1.ObjectMeta{Name: resourceName(cr), Namespace: cr.Namespace}}
+			if err := r.deleteChild(ctx, cli, cr, route); err != nil {
+				return err
+			}
+		}
+		return r.reconcileHTTPRoute(ctx, cli, cr)
+	}
+
+	if err := r.deleteHTTPRoute(ctx, cli, cr); err != nil {
+		return err
+	}
+	if r.routeAPIAvailable() {
+		return r.reconcileMCPServerRoute(ctx, cli, cr)
+	}
+	return nil
+}
+
+// deleteHTTPRoute removes the HTTPRoute of an MCP server that no longer uses a Gateway.
+func (r *MCPServerReconciler) deleteHTTPRoute(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) error {
+	if meta.FindStatusCondition(cr.Status.Conditions, HTTPRouteAccepted) == nil || !r.gatewayAPIAvailable() {
+		return nil
+	}
+	httpRoute := &gatewayv1.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Name: resourceName(cr), Namespace: cr.Namespace}}
+	return r.deleteChild(ctx, cli, cr, httpRoute)
+}
+
+// gatewayKey returns the name and namespace of the Gateway referenced by cr.
+func gatewayKey(cr *mcpserverv1.MCPServer) client.ObjectKey {
+	key := client.ObjectKey{Name: cr.Spec.GatewayRef.Name, Namespace: cr.Spec.GatewayRef.Namespace}
+	if key.Namespace == "" {
+		key.Namespace = cr.Namespace
+	}
+	return key
+}
+
+// gatewayParentRef returns the parent reference of the HTTPRoute of cr. Every field is set, so that it
+// compares equal to the reference once the API server has defaulted it.
+func gatewayParentRef(cr *mcpserverv1.MCPServer) gatewayv1.ParentReference {
+	key := gatewayKey(cr)
+	ref := gatewayv1.ParentReference{
+		Group:     ptr.To(gatewayv1.Group(gatewayv1.GroupName)),
+		Kind:      ptr.To(gatewayv1.Kind("Gateway")),
+		Namespace: ptr.To(gatewayv1.Namespace(key.Namespace)),
+		Name:      gatewayv1.ObjectName(key.Name),
+	}
+	if sectionName := cr.Spec.GatewayRef.SectionName; sectionName != "" {
+		ref.SectionName = ptr.To(gatewayv1.SectionName(sectionName))
+	}
+	return ref
+}
+
+// reconcileHTTPRoute creates the HTTPRoute that attaches the Service of cr to the referenced Gateway, and moves
+// an existing HTTPRoute when the reference changed.
+func (r *MCPServerReconciler) reconcileHTTPRoute(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) error {
+	parentRef := gatewayParentRef(cr)
+	httpRoute := &gatewayv1.HTTPRoute{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: gatewayv1.GroupVersion.String(),
+			Kind:       "HTTPRoute",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resourceName(cr),
+			Namespace: cr.Namespace,
+			Labels:    map[string]string{mcpServerAppLabelKey: resourceName(cr)},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: []gatewayv1.ParentReference{parentRef},
+			},
+			Rules: []gatewayv1.HTTPRouteRule{{
+				BackendRefs: []gatewayv1.HTTPBackendRef{{
+					BackendRef: gatewayv1.BackendRef{
+						BackendObjectReference: gatewayv1.BackendObjectReference{
+							Name: gatewayv1.ObjectName(resourceName(cr)),
+							Port: ptr.To(gatewayv1.PortNumber(8000)),
+						},
+					},
+				}},
+			}},
+		},
+	}
+	if err := r.createChild(ctx, cli, cr, httpRoute); err != nil {
+		return err
+	}
+
+	existing := &gatewayv1.HTTPRoute{}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(httpRoute), existing); err != nil {
+		if k8serr.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if equality.Semantic.DeepEqual(existing.Spec.ParentRefs, httpRoute.Spec.ParentRefs) {
+		return nil
+	}
+	original := existing.DeepCopy()
+	existing.Spec.ParentRefs = httpRoute.Spec.ParentRefs
+	logChildDiff(ctx, original, existing)
+	return cli.Patch(ctx, existing, client.MergeFrom(original))
+}
+
+// getHTTPRouteCondition returns the HTTPRouteAccepted condition of cr. The listeners of the Gateway are checked
+// first, so that an HTTPRoute the Gateway will never accept is reported with the cause rather than as pending.
+func (r *MCPServerReconciler) getHTTPRouteCondition(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) metav1.Condition {
+	key := gatewayKey(cr)
+	gateway := &gatewayv1.Gateway{}
+	if err := cli.Get(ctx, key, gateway); err != nil {
+		if k8serr.IsNotFound(err) {
+			return metav1.Condition{
+				Type:    HTTPRouteAccepted,
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonGatewayNotFound,
+				Message: fmt.Sprintf("Gateway %s cannot be found", key),
+			}
+		}
+		return metav1.Condition{
+			Type:    HTTPRouteAccepted,
+			Status:  metav1.ConditionUnknown,
+			Reason:  fmt.Sprintf("%s%s", "Gateway", ReasonGetFailedSuffix),
+			Message: fmt.Sprintf("Failed to retrieve Gateway %s, %v", key, err),
+		}
+	}
+
+	listeners := gatewayListeners(cr, gateway)
+	if len(listeners) == 0 {
+		return metav1.Condition{
+			Type:    HTTPRouteAccepted,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonNoMatchingListener,
+			Message: fmt.Sprintf("Gateway %s has no listener named %s", key, cr.Spec.GatewayRef.SectionName),
+		}
+	}
+	allowed := false
+	for _, listener := range listeners {
+		ok, err := r.listenerAllowsRoute(ctx, cli, gateway, listener, cr.Namespace)
+		if err != nil {
+			return metav1.Condition{
+				Type:    HTTPRouteAccepted,
+				Status:  metav1.ConditionUnknown,
+				Reason:  fmt.Sprintf("%s%s", "Namespace", ReasonGetFailedSuffix),
+				Message: fmt.Sprintf("Failed to check the listeners of Gateway %s, %v", key, err),
+			}
+		}
+		allowed = allowed || ok
+	}
+	if !allowed {
+		return metav1.Condition{
+			Type:   HTTPRouteAccepted,
+			Status: metav1.ConditionFalse,
+			Reason: ReasonNotAllowedByListeners,
+			Message: fmt.Sprintf("No listener of Gateway %s allows HTTPRoutes from namespace %s, "+
+				"its allowedRoutes must select the namespace", key, cr.Namespace),
+		}
+	}
+
+	httpRoute := &gatewayv1.HTTPRoute{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: resourceName(cr), Namespace: cr.Namespace}, httpRoute); err != nil {
+		if k8serr.IsNotFound(err) {
+			return metav1.Condition{
+				Type:    HTTPRouteAccepted,
+				Status:  metav1.ConditionFalse,
+				Reason:  fmt.Sprintf("%s%s", "HTTPRoute", ReasonNotFoundSuffix),
+				Message: fmt.Sprintf("HTTPRoute %s cannot be found", resourceName(cr)),
+			}
+		}
+		return metav1.Condition{
+			Type:    HTTPRouteAccepted,
+			Status:  metav1.ConditionUnknown,
+			Reason:  fmt.Sprintf("%s%s", "HTTPRoute", ReasonGetFailedSuffix),
+			Message: fmt.Sprintf("Failed to retrieve HTTPRoute %s, %v", cr.Name, err),
+		}
+	}
+
+	parentRef := gatewayParentRef(cr)
+	for _, parent := range httpRoute.Status.Parents {
+		if parent.ParentRef.Name != parentRef.Name ||
+			ptr.Deref(parent.ParentRef.Namespace, gatewayv1.Namespace(cr.Namespace)) != *parentRef.Namespace ||
+			ptr.Deref(parent.ParentRef.SectionName, "") != ptr.Deref(parentRef.SectionName, "") {
+			continue
+		}
+		for _, conditionType := range []gatewayv1.RouteConditionType{gatewayv1.RouteConditionAccepted, gatewayv1.RouteConditionResolvedRefs} {
+			cond := meta.FindStatusCondition(parent.Conditions, string(conditionType))
+			if cond != nil && cond.Status == metav1.ConditionFalse {
+				return metav1.Condition{
+					Type:    HTTPRouteAccepted,
+					Status:  metav1.ConditionFalse,
+					Reason:  cond.Reason,
+					Message: fmt.Sprintf("HTTPRoute %s is not %s by Gateway %s: %s", resourceName(cr), strings.ToLower(string(conditionType)), key, cond.Message),
+				}
+			}
+		}
+		if meta.IsStatusConditionTrue(parent.Conditions, string(gatewayv1.RouteConditionAccepted)) {
+			return metav1.Condition{
+				Type:    HTTPRouteAccepted,
+				Status:  metav1.ConditionTrue,
+				Reason:  string(gatewayv1.RouteReasonAccepted),
+				Message: fmt.Sprintf("HTTPRoute %s is accepted by Gateway %s", resourceName(cr), key),
+			}
+		}
+	}
+	return metav1.Condition{
+		Type:    HTTPRouteAccepted,
+		Status:  metav1.ConditionFalse,
+		Reason:  fmt.Sprintf("%s%s", "HTTPRoute", ReasonNotReadySuffix),
+		Message: fmt.Sprintf("HTTPRoute %s has not been accepted by Gateway %s yet", resourceName(cr), key),
+	}
+}
+
+// gatewayListeners returns the listeners of gateway the HTTPRoute of cr attaches to.
+func gatewayListeners(cr *mcpserverv1.MCPServer, gateway *gatewayv1.Gateway) []gatewayv1.Listener {
+	sectionName := cr.Spec.GatewayRef.SectionName
+	if sectionName == "" {
+		return gateway.Spec.Listeners
+	}
+	for _, listener := range gateway.Spec.Listeners {
+		if string(listener.Name) == sectionName {
+			return []gatewayv1.Listener{listener}
+		}
+	}
+	return nil
+}
+
+// listenerAllowsRoute reports whether listener admits HTTPRoutes from namespace, following the allowedRoutes
+// rules of the Gateway API: routes from the namespace of the Gateway by default, from all namespaces, or from
+// the namespaces matching a selector. Cross-namespace attachment needs no ReferenceGrant, as it is governed by
+// the listener; the backend of the HTTPRoute is always in its own namespace.
+func (r *MCPServerReconciler) listenerAllowsRoute(ctx context.Context, cli client.Client, gateway *gatewayv1.Gateway,
+	listener gatewayv1.Listener, namespace string) (bool, error) {
+	allowedRoutes := listener.AllowedRoutes
+	if allowedRoutes != nil && len(allowedRoutes.Kinds) > 0 {
+		allowsKind := false
+		for _, kind := range allowedRoutes.Kinds {
+			if kind.Kind == "HTTPRoute" && string(ptr.Deref(kind.Group, gatewayv1.GroupName)) == gatewayv1.GroupName {
+				allowsKind = true
+			}
+		}
+		if !allowsKind {
+			return false, nil
+		}
+	} else if listener.Protocol != gatewayv1.HTTPProtocolType && listener.Protocol != gatewayv1.HTTPSProtocolType {
+		return false, nil
+	}
+
+	from := gatewayv1.NamespacesFromSame
+	if allowedRoutes != nil && allowedRoutes.Namespaces != nil && allowedRoutes.Namespaces.From != nil {
+		from = *allowedRoutes.Namespaces.From
+	}
+	switch from {
+	case gatewayv1.NamespacesFromAll:
+		return true, nil
+	case gatewayv1.NamespacesFromSelector:
+		if allowedRoutes.Namespaces.Selector == nil {
+			return false, nil
+		}
+		selector, err := metav1.LabelSelectorAsSelector(allowedRoutes.Namespaces.Selector)
+		if err != nil {
+			return false, nil
+		}
+		ns := &corev1.Namespace{}
+		if err := cli.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+			return false, err
+		}
+		return selector.Matches(labels.Set(ns.Labels)), nil
+	default:
+		return gateway.Namespace == namespace, nil
+	}
+}
+
+// getGatewayURL returns the URL of the MCP server on its Gateway, or an empty string when the Gateway has no
+// listener with a concrete hostname or an address the server can be reached at.
+func (r *MCPServerReconciler) getGatewayURL(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) (string, error) {
+	gateway := &gatewayv1.Gateway{}
+	if err := cli.Get(ctx, gatewayKey(cr), gateway); err != nil {
+		if k8serr.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	for _, listener := range gatewayListeners(cr, gateway) {
+		scheme, defaultPort := "http", gatewayv1.PortNumber(80)
+		switch listener.Protocol {
+		case gatewayv1.HTTPSProtocolType:
+			scheme, defaultPort = "https", 443
+		case gatewayv1.HTTPProtocolType:
+		default:
+			continue
+		}
+
+		host := string(ptr.Deref(listener.Hostname, ""))
+		if host == "" && len(gateway.Status.Addresses) > 0 {
+			host = gateway.Status.Addresses[0].Value
+		}
+		if host == "" || strings.Contains(host, "*") {
+			continue
+		}
+		if listener.Port != defaultPort {
+			host = fmt.Sprintf("%s:%d", host, listener.Port)
+		}
+		return fmt.Sprintf("%s://%s%s", scheme, host, mcpServerPath(cr)), nil
+	}
+	return "", nil
+}
@@ -0,0 +1,196 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+const gatewayNamespace = "gateways"
+
+func newGatewayScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{corev1.AddToScheme, gatewayv1.Install, mcpserverv1.AddToScheme} {
+		if err := add(scheme); err != nil {
+			t.Fatalf("failed to build the scheme: %v", err)
+		}
+	}
+	return scheme
+}
+
+func newGateway(allowedRoutes *gatewayv1.AllowedRoutes) *gatewayv1.Gateway {
+	return &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: gatewayNamespace},
+		Spec: gatewayv1.GatewaySpec{
+			Listeners: []gatewayv1.Listener{{
+				Name:          "https",
+				Hostname:      ptr.To(gatewayv1.Hostname("mcp.example.com")),
+				Port:          443,
+				Protocol:      gatewayv1.HTTPSProtocolType,
+				AllowedRoutes: allowedRoutes,
+			}},
+		},
+	}
+}
+
+func newGatewayMCPServer(sectionName string) *mcpserverv1.MCPServer {
+	return &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+		Spec: mcpserverv1.MCPServerSpec{
+			Image: mcpServerImage,
+			GatewayRef: &mcpserverv1.GatewayRef{
+				Name:        "shared",
+				Namespace:   gatewayNamespace,
+				SectionName: sectionName,
+			},
+		},
+	}
+}
+
+func TestMCPServerReconciler_getHTTPRouteCondition(t *testing.T) {
+	fromAll := &gatewayv1.AllowedRoutes{
+		Namespaces: &gatewayv1.RouteNamespaces{From: ptr.To(gatewayv1.NamespacesFromAll)},
+	}
+	fromSelector := &gatewayv1.AllowedRoutes{
+		Namespaces: &gatewayv1.RouteNamespaces{
+			From:     ptr.To(gatewayv1.NamespacesFromSelector),
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"mcp-gateway": "allowed"}},
+		},
+	}
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: testNamespace, Labels: map[string]string{"mcp-gateway": "allowed"}},
+	}
+	// Gateway controllers report the parentRef as it is in the spec.
+	acceptedRoute := func(sectionName string, status metav1.ConditionStatus, reason string) *gatewayv1.HTTPRoute {
+		parentRef := gatewayv1.ParentReference{
+			Name:      "shared",
+			Namespace: ptr.To(gatewayv1.Namespace(gatewayNamespace)),
+		}
+		if sectionName != "" {
+			parentRef.SectionName = ptr.To(gatewayv1.SectionName(sectionName))
+		}
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+			Status: gatewayv1.HTTPRouteStatus{RouteStatus: gatewayv1.RouteStatus{
+				Parents: []gatewayv1.RouteParentStatus{{
+					ParentRef: parentRef,
+					Conditions: []metav1.Condition{{
+						Type:   string(gatewayv1.RouteConditionAccepted),
+						Status: status,
+						Reason: reason,
+					}},
+				}},
+			}},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		sectionName string
+		objects     []client.Object
+		wantStatus  metav1.ConditionStatus
+		wantReason  string
+	}{
+		{
+			name:       "missing Gateway",
+			wantStatus: metav1.ConditionFalse,
+			wantReason: ReasonGatewayNotFound,
+		},
+		{
+			name:        "unknown listener",
+			sectionName: "http",
+			objects:     []client.Object{newGateway(fromAll)},
+			wantStatus:  metav1.ConditionFalse,
+			wantReason:  ReasonNoMatchingListener,
+		},
+		{
+			name:       "listener only admits routes from its own namespace",
+			objects:    []client.Object{newGateway(nil), acceptedRoute("", metav1.ConditionTrue, "Accepted")},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: ReasonNotAllowedByListeners,
+		},
+		{
+			name:        "listener admits the namespace by selector",
+			sectionName: "https",
+			objects:     []client.Object{newGateway(fromSelector), namespace, acceptedRoute("https", metav1.ConditionTrue, "Accepted")},
+			wantStatus:  metav1.ConditionTrue,
+			wantReason:  "Accepted",
+		},
+		{
+			name:       "HTTPRoute not accepted yet",
+			objects:    []client.Object{newGateway(fromAll), &gatewayv1.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace}}},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: "HTTPRouteNotReady",
+		},
+		{
+			name:       "HTTPRoute rejected by the Gateway",
+			objects:    []client.Object{newGateway(fromAll), acceptedRoute("", metav1.ConditionFalse, "NoMatchingListenerHostname")},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: "NoMatchingListenerHostname",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().WithScheme(newGatewayScheme(t)).WithObjects(tt.objects...).Build()
+			r := &MCPServerReconciler{Client: cli}
+
+			got := r.getHTTPRouteCondition(context.Background(), cli, newGatewayMCPServer(tt.sectionName))
+			if got.Type != HTTPRouteAccepted || got.Status != tt.wantStatus || got.Reason != tt.wantReason {
+				t.Errorf("getHTTPRouteCondition() = %s %s (%s), want %s %s", got.Status, got.Reason, got.Message,
+					tt.wantStatus, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_reconcileHTTPRoute(t *testing.T) {
+	scheme := newGatewayScheme(t)
+	cli := fake.NewClientBuilder().WithScheme(scheme).Build()
+	r := &MCPServerReconciler{Client: cli, Scheme: scheme}
+
+	cr := newGatewayMCPServer("")
+	if err := r.reconcileHTTPRoute(context.Background(), cli, cr); err != nil {
+		t.Fatalf("reconcileHTTPRoute() error = %v", err)
+	}
+
+	// Moving the MCP server to another listener updates the existing HTTPRoute.
+	cr.Spec.GatewayRef.SectionName = "https"
+	if err := r.reconcileHTTPRoute(context.Background(), cli, cr); err != nil {
+		t.Fatalf("reconcileHTTPRoute() error = %v", err)
+	}
+
+	httpRoute := &gatewayv1.HTTPRoute{}
+	if err := cli.Get(context.Background(), client.ObjectKey{Name: mcpServerName, Namespace: testNamespace}, httpRoute); err != nil {
+		t.Fatalf("failed to get the HTTPRoute: %v", err)
+	}
+	if len(httpRoute.Spec.ParentRefs) != 1 || ptr.Deref(httpRoute.Spec.ParentRefs[0].SectionName, "") != "https" ||
+		ptr.Deref(httpRoute.Spec.ParentRefs[0].Namespace, "") != gatewayNamespace {
+		t.Errorf("parentRefs = %+v, want the https listener of %s/shared", httpRoute.Spec.ParentRefs, gatewayNamespace)
+	}
+	if backend := httpRoute.Spec.Rules[0].BackendRefs[0]; string(backend.Name) != mcpServerName || ptr.Deref(backend.Port, 0) != 8000 {
+		t.Errorf("backendRef = %+v, want the Service of the MCP server", backend)
+	}
+}
+
+func TestMCPServerReconciler_getGatewayURL(t *testing.T) {
+	cli := fake.NewClientBuilder().WithScheme(newGatewayScheme(t)).WithObjects(newGateway(nil)).Build()
+	r := &MCPServerReconciler{Client: cli}
+
+	got, err := r.getGatewayURL(context.Background(), cli, newGatewayMCPServer("https"))
+	if err != nil {
+		t.Fatalf("getGatewayURL() error = %v", err)
+	}
+	if want := "https://mcp.example.com/sse"; got != want {
+		t.Errorf("getGatewayURL() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/internal/guardrails"
+)
+
+const (
+	// GuardrailsAvailable reports whether the guardrails filter of every MCP server pod is ready, which it is
+	// while the detection service is healthy. It is only set for MCP servers with guardrails.
+	GuardrailsAvailable = "GuardrailsAvailable"
+
+	guardrailsContainerName = "guardrails"
+	guardrailsPort          = 8080
+	guardrailsHealthPort    = 8081
+)
+
+// guardrailsCredentials returns the token of the detection service as spec.guardrails.credentialsExposure asks,
+// read from a file by default.
+func guardrailsCredentials(cr *mcpserverv1.MCPServer) exposedSecret {
+	spec := cr.Spec.Guardrails
+	if spec == nil {
+		return exposedSecret{}
+	}
+	return exposeSecret(spec.CredentialsSecretRef, spec.CredentialsExposure, mcpserverv1.SecretExposureFile,
+		guardrails.TokenEnv, guardrailsCredentialsVolumeName)
+}
+
+// guardrailsContainer returns the guardrails filter of cr, which runs the guardrails subcommand of the operator
+// image in front of the MCP server, or nil when cr has no guardrails.
+func (r *MCPServerReconciler) guardrailsContainer(cr *mcpserverv1.MCPServer) *corev1.Container {
+	spec := cr.Spec.Guardrails
+	if spec == nil {
+		return nil
+	}
+
+	// The defaults of the CRD are repeated for MCPServers created before it had them.
+	input, output := spec.Input, spec.Output
+	if input == "" {
+		input = mcpserverv1.GuardrailsBlock
+	}
+	if output == "" {
+		output = mcpserverv1.GuardrailsRedact
+	}
+	args := []string{
+		"--upstream", fmt.Sprintf("http://localhost:%d", upstreamPort(cr, guardrailsContainerName)),
+		"--port", strconv.Itoa(guardrailsPort),
+		"--health-port", strconv.Itoa(guardrailsHealthPort),
+		"--url", spec.URL,
+		"--input", string(input),
+		"--output", string(output),
+	}
+	for _, detector := range spec.Detectors {
+		args = append(args, "--detector", detector)
+	}
+
+	env := r.proxyEnv()
+	var volumeMounts []corev1.VolumeMount
+	credentials := guardrailsCredentials(cr)
+	if credentials.Env != nil {
+		env = append(env, *credentials.Env)
+	}
+	if credentials.Mount != nil {
+		args = append(args, "--token-file", credentials.Path)
+		volumeMounts = append(volumeMounts, *credentials.Mount)
+	}
+
+	return &corev1.Container{
+		Name:    guardrailsContainerName,
+		Image:   r.OperatorImage,
+		Command: []string{"/manager", guardrails.Command},
+		Args:    args,
+		Ports: []corev1.ContainerPort{{
+			ContainerPort: guardrailsPort,
+			Name:          "http",
+		}},
+		Env:          env,
+		VolumeMounts: volumeMounts,
+		ReadinessProbe: &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Path: guardrails.HealthPath,
+					Port: intstr.FromInt32(guardrailsHealthPort),
+				},
+			},
+			PeriodSeconds: 10,
+		},
+		SecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: ptr.To(false),
+			Capabilities: &corev1.Capabilities{
+				Drop: []corev1.Capability{"ALL"},
+			},
+		},
+	}
+}
+
+// withGuardrails returns containers with the guardrails filter set to sidecar, or removed when sidecar is nil.
+// While the filter runs, it owns the http port and the port of the MCP server container is renamed, so that the
+// Service and Route send all traffic through the filter.
+func withGuardrails(containers []corev1.Container, sidecar *corev1.Container) []corev1.Container {
+	return withSidecar(containers, guardrailsContainerName, sidecar, "mcp-server")
+}
+
+// getGuardrailsCondition returns the GuardrailsAvailable condition of cr from the readiness of the guardrails
+// filter in each MCP server pod.
+func (r *MCPServerReconciler) getGuardrailsCondition(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) metav1.Condition {
+	return r.getSidecarCondition(ctx, cli, cr, GuardrailsAvailable, guardrailsContainerName, "Guardrails", "guardrails filter",
+		fmt.Sprintf("check that the detection service %s is healthy", cr.Spec.Guardrails.URL))
+}
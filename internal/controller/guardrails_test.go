@@ -0,0 +1,141 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+func newGuardrailsMCPServer() *mcpserverv1.MCPServer {
+	return &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+		Spec: mcpserverv1.MCPServerSpec{
+			Image: mcpServerImage,
+			Guardrails: &mcpserverv1.Guardrails{
+				URL:       "https://guardrails-orchestrator.trustyai.svc:8032",
+				Detectors: []string{"pii"},
+			},
+		},
+	}
+}
+
+func Test_withGuardrails(t *testing.T) {
+	r := &MCPServerReconciler{OperatorImage: "quay.io/example/operator:latest"}
+	sidecar := r.guardrailsContainer(newGuardrailsMCPServer())
+	server := corev1.Container{
+		Name:  "mcp-server",
+		Ports: []corev1.ContainerPort{{ContainerPort: 8000, Name: "http"}},
+	}
+
+	// The API server defaults fields of the filter, which must not be reverted.
+	deployed := *sidecar.DeepCopy()
+	deployed.TerminationMessagePath = corev1.TerminationMessagePathDefault
+	filtered := *server.DeepCopy()
+	filtered.Ports[0].Name = mcpServerPortName
+
+	tests := []struct {
+		name           string
+		containers     []corev1.Container
+		sidecar        *corev1.Container
+		wantContainers []string
+		wantPort       string
+		wantDefaulted  bool
+	}{
+		{
+			name:           "filter added",
+			containers:     []corev1.Container{server},
+			sidecar:        sidecar,
+			wantContainers: []string{"mcp-server", guardrailsContainerName},
+			wantPort:       mcpServerPortName,
+		},
+		{
+			name:           "unchanged filter kept",
+			containers:     []corev1.Container{filtered, deployed},
+			sidecar:        sidecar,
+			wantContainers: []string{"mcp-server", guardrailsContainerName},
+			wantPort:       mcpServerPortName,
+			wantDefaulted:  true,
+		},
+		{
+			name:           "filter removed",
+			containers:     []corev1.Container{filtered, deployed},
+			wantContainers: []string{"mcp-server"},
+			wantPort:       "http",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := withGuardrails(tt.containers, tt.sidecar)
+
+			var names []string
+			for _, container := range got {
+				names = append(names, container.Name)
+			}
+			if len(names) != len(tt.wantContainers) || names[len(names)-1] != tt.wantContainers[len(tt.wantContainers)-1] {
+				t.Fatalf("containers = %v, want %v", names, tt.wantContainers)
+			}
+			if got[0].Ports[0].Name != tt.wantPort {
+				t.Errorf("MCP server port = %s, want %s", got[0].Ports[0].Name, tt.wantPort)
+			}
+			if tt.sidecar != nil && (got[1].TerminationMessagePath != "") != tt.wantDefaulted {
+				t.Errorf("defaulted fields kept = %v, want %v", got[1].TerminationMessagePath != "", tt.wantDefaulted)
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_getGuardrailsCondition(t *testing.T) {
+	newPod := func(name string, ready bool) client.Object {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: testNamespace,
+				Labels:    map[string]string{mcpServerAppLabelKey: mcpServerName},
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: "mcp-server", Ready: true},
+					{Name: guardrailsContainerName, Ready: ready},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		pods       []client.Object
+		wantStatus metav1.ConditionStatus
+	}{
+		{
+			name:       "no pods",
+			wantStatus: metav1.ConditionFalse,
+		},
+		{
+			name:       "filter not ready in one pod",
+			pods:       []client.Object{newPod("a", true), newPod("b", false)},
+			wantStatus: metav1.ConditionFalse,
+		},
+		{
+			name:       "filter ready in all pods",
+			pods:       []client.Object{newPod("a", true), newPod("b", true)},
+			wantStatus: metav1.ConditionTrue,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().WithObjects(tt.pods...).Build()
+			r := &MCPServerReconciler{Client: cli}
+
+			got := r.getGuardrailsCondition(context.Background(), cli, newGuardrailsMCPServer())
+			if got.Type != GuardrailsAvailable || got.Status != tt.wantStatus {
+				t.Errorf("getGuardrailsCondition() = %s %s (%s), want %s", got.Type, got.Status, got.Message, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// ignoresReplicas reports whether the replica count of the existing Deployment of cr is managed by someone else.
+func ignoresReplicas(cr *mcpserverv1.MCPServer) bool {
+	return cr.Spec.IgnoreDifferences != nil && cr.Spec.IgnoreDifferences.Replicas
+}
+
+// ignoresAnnotation reports whether cr lists key in spec.ignoreDifferences.annotations, exactly or by a prefix
+// ending in *.
+func ignoresAnnotation(cr *mcpserverv1.MCPServer, key string) bool {
+	if cr.Spec.IgnoreDifferences == nil {
+		return false
+	}
+	for _, ignored := range cr.Spec.IgnoreDifferences.Annotations {
+		if prefix, ok := strings.CutSuffix(ignored, "*"); ok && strings.HasPrefix(key, prefix) || ignored == key {
+			return true
+		}
+	}
+	return false
+}
+
+// keepIgnoredAnnotations sets the annotations of obj that cr ignores back to their values in original, where obj
+// is a modified copy of the existing child original, so that the operator neither changes nor removes them.
+func keepIgnoredAnnotations(cr *mcpserverv1.MCPServer, original, obj metav1.Object) {
+	if cr.Spec.IgnoreDifferences == nil || len(cr.Spec.IgnoreDifferences.Annotations) == 0 {
+		return
+	}
+	annotations := obj.GetAnnotations()
+	for key := range annotations {
+		if _, ok := original.GetAnnotations()[key]; !ok && ignoresAnnotation(cr, key) {
+			delete(annotations, key)
+		}
+	}
+	for key, value := range original.GetAnnotations() {
+		if ignoresAnnotation(cr, key) {
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			annotations[key] = value
+		}
+	}
+	obj.SetAnnotations(annotations)
+}
@@ -0,0 +1,224 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/registry"
+)
+
+const (
+	// ImageNotFound reports whether the image of an MCP server with spec.verifyImage is missing from its
+	// registry. The Deployment is neither created nor updated while it is True.
+	ImageNotFound = "ImageNotFound"
+
+	// ReasonManifestNotFound is set on the ImageNotFound condition when the registry has no manifest for the image.
+	ReasonManifestNotFound = "ManifestNotFound"
+	// ReasonImageVerified is set on the ImageNotFound condition when the registry has a manifest for the image.
+	ReasonImageVerified = "ImageVerified"
+	// ReasonImageNotVerified is set on the ImageNotFound condition when the registry could not tell whether the
+	// image exists, e.g. because it is unreachable or refuses the credentials. The image is rolled out anyway.
+	ReasonImageNotVerified = "ImageNotVerified"
+
+	// ImageDigestFailed reports whether the digest of the image of an MCP server with spec.pinImageDigest could not
+	// be resolved. The Deployment is neither created nor updated while it is True.
+	ImageDigestFailed = "ImageDigestFailed"
+
+	// ReasonImagePinned is set on the ImageDigestFailed condition when the image is pinned to its digest.
+	ReasonImagePinned = "ImagePinned"
+	// ReasonDigestNotResolved is set on the ImageDigestFailed condition when the registry could not be asked for
+	// the digest, e.g. because it is unreachable or refuses the credentials.
+	ReasonDigestNotResolved = "DigestNotResolved"
+
+	// imageCheckTimeout bounds the requests to a registry so an unresponsive registry cannot stall the reconcile.
+	imageCheckTimeout = 10 * time.Second
+)
+
+// getImageCondition returns the ImageNotFound condition of cr. The registry is only asked when the image is not
+// rolled out yet, i.e. before the Deployment is created and when spec.image changed.
+func (r *MCPServerReconciler) getImageCondition(ctx context.Context, cr *mcpserverv1.MCPServer) (metav1.Condition, error) {
+	condition := metav1.Condition{Type: ImageNotFound, Status: metav1.ConditionFalse}
+
+	deployment := &appsv1.Deployment{}
+	err := r.Get(ctx, client.ObjectKey{Name: resourceName(cr), Namespace: cr.Namespace}, deployment)
+	if err != nil && !k8serr.IsNotFound(err) {
+		return metav1.Condition{}, err
+	}
+	current := ""
+	if err == nil {
+		for _, container := range deployment.Spec.Template.Spec.Containers {
+			if container.Name == "mcp-server" {
+				current = container.Image
+			}
+		}
+	}
+	if current == pinnedImage(cr) {
+		condition.Reason = ReasonAsExpected
+		condition.Message = fmt.Sprintf("Image %s is rolled out", cr.Spec.Image)
+		return condition, nil
+	}
+
+	ref, err := registry.ParseReference(cr.Spec.Image)
+	if err != nil {
+		condition.Reason = ReasonImageNotVerified
+		condition.Message = fmt.Sprintf("Image %s could not be verified: %v", cr.Spec.Image, err)
+		return condition, nil
+	}
+	credentials, err := r.registryCredentials(ctx, cr, ref)
+	if err != nil {
+		return metav1.Condition{}, err
+	}
+	checker := r.Registry
+	if checker == nil {
+		checker = &registry.Client{}
+	}
+	checkCtx, cancel := context.WithTimeout(ctx, imageCheckTimeout)
+	defer cancel()
+	exists, err := checker.ManifestExists(checkCtx, ref, credentials)
+	switch {
+	case err != nil:
+		condition.Reason = ReasonImageNotVerified
+		condition.Message = fmt.Sprintf("Image %s could not be verified, it is rolled out anyway: %v", cr.Spec.Image, err)
+	case !exists:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = ReasonManifestNotFound
+		condition.Message = fmt.Sprintf("Image %s does not exist in registry %s", cr.Spec.Image, ref.Registry)
+		if current != "" {
+			condition.Message = fmt.Sprintf("%s, the Deployment keeps running %s", condition.Message, current)
+		}
+	default:
+		condition.Reason = ReasonImageVerified
+		condition.Message = fmt.Sprintf("Image %s exists in registry %s", cr.Spec.Image, ref.Registry)
+	}
+	return condition, nil
+}
+
+// pinnedImage returns the image of the MCP server container: spec.image, pinned to the digest recorded in
+// status.imageDigest with spec.pinImageDigest. The tag is kept for readability, the container runtime pulls by
+// the digest.
+func pinnedImage(cr *mcpserverv1.MCPServer) string {
+	digest := cr.Status.ImageDigest
+	if !cr.Spec.PinImageDigest || digest == nil || digest.Image != cr.Spec.Image || strings.Contains(cr.Spec.Image, "@") {
+		return cr.Spec.Image
+	}
+	return cr.Spec.Image + "@" + digest.Digest
+}
+
+// getImageDigestCondition resolves the digest of spec.image for spec.pinImageDigest into status.imageDigest and
+// returns the ImageDigestFailed condition of cr. The registry is only asked when spec.image changed and when the
+// MCP server was restarted after the digest was resolved, so that a tag pushed again is not rolled out until
+// then. When the digest cannot be resolved again on a restart, the recorded one is kept.
+func (r *MCPServerReconciler) getImageDigestCondition(ctx context.Context, cr *mcpserverv1.MCPServer) (metav1.Condition, error) {
+	condition := metav1.Condition{Type: ImageDigestFailed, Status: metav1.ConditionFalse, Reason: ReasonImagePinned}
+
+	ref, err := registry.ParseReference(cr.Spec.Image)
+	if err != nil {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = ReasonDigestNotResolved
+		condition.Message = fmt.Sprintf("Image %s cannot be pinned: %v", cr.Spec.Image, err)
+		return condition, nil
+	}
+	if strings.Contains(cr.Spec.Image, "@") {
+		// An image given by digest is pinned already.
+		cr.Status.ImageDigest = &mcpserverv1.ImageDigestStatus{Image: cr.Spec.Image, Digest: ref.Reference}
+		condition.Message = fmt.Sprintf("Image %s is pinned by spec.image", cr.Spec.Image)
+		return condition, nil
+	}
+
+	recorded := cr.Status.ImageDigest
+	if recorded != nil && recorded.Image != cr.Spec.Image {
+		recorded = nil
+	}
+	if recorded != nil && !restartedSince(cr, recorded.ResolvedTime) {
+		condition.Message = fmt.Sprintf("Image %s is pinned to %s", cr.Spec.Image, recorded.Digest)
+		return condition, nil
+	}
+
+	credentials, err := r.registryCredentials(ctx, cr, ref)
+	if err != nil {
+		return metav1.Condition{}, err
+	}
+	resolver := r.Registry
+	if resolver == nil {
+		resolver = &registry.Client{}
+	}
+	resolveCtx, cancel := context.WithTimeout(ctx, imageCheckTimeout)
+	defer cancel()
+	digest, err := resolver.ManifestDigest(resolveCtx, ref, credentials)
+	switch {
+	case err != nil && recorded != nil:
+		condition.Message = fmt.Sprintf("Digest of image %s could not be resolved again, it stays pinned to %s: %v",
+			cr.Spec.Image, recorded.Digest, err)
+	case errors.Is(err, registry.ErrManifestNotFound):
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = ReasonManifestNotFound
+		condition.Message = fmt.Sprintf("Image %s does not exist in registry %s", cr.Spec.Image, ref.Registry)
+	case err != nil:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = ReasonDigestNotResolved
+		condition.Message = fmt.Sprintf("Digest of image %s could not be resolved: %v", cr.Spec.Image, err)
+	default:
+		cr.Status.ImageDigest = &mcpserverv1.ImageDigestStatus{
+			Image:        cr.Spec.Image,
+			Digest:       digest,
+			ResolvedTime: ptr.To(metav1.Now()),
+		}
+		condition.Message = fmt.Sprintf("Image %s is pinned to %s", cr.Spec.Image, digest)
+	}
+	return condition, nil
+}
+
+// restartedSince reports whether the MCP server was restarted with the restartedAt annotation after t.
+func restartedSince(cr *mcpserverv1.MCPServer, t *metav1.Time) bool {
+	restartedAt, err := time.Parse(time.RFC3339, cr.Annotations[mcpserverv1.RestartedAtAnnotation])
+	if err != nil {
+		return false
+	}
+	return t == nil || restartedAt.After(t.Time)
+}
+
+// registryCredentials returns the credentials for ref from the image pull secrets of the service account of the
+// pods, as the kubelet would use them, or nil when none of them has credentials for its registry.
+func (r *MCPServerReconciler) registryCredentials(ctx context.Context, cr *mcpserverv1.MCPServer,
+	ref registry.Reference) (*registry.Credentials, error) {
+	serviceAccount, err := r.getPodServiceAccount(ctx, cr)
+	if err != nil || serviceAccount == nil {
+		return nil, err
+	}
+
+	for _, pullSecret := range serviceAccount.ImagePullSecrets {
+		secret := &corev1.Secret{}
+		err := r.Get(ctx, client.ObjectKey{Name: pullSecret.Name, Namespace: cr.Namespace}, secret)
+		if k8serr.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		data, ok := secret.Data[corev1.DockerConfigJsonKey]
+		if !ok {
+			data, ok = secret.Data[corev1.DockerConfigKey]
+		}
+		if !ok {
+			continue
+		}
+		credentials, found, err := registry.CredentialsFor(data, ref)
+		if err != nil || !found {
+			// A malformed pull secret is skipped, as by the kubelet.
+			continue
+		}
+		return &credentials, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,386 @@
+package controller
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/registry"
+)
+
+// testImageDigest is the digest of the manifest of team/server:1 served by newImageRegistry.
+const testImageDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+// newImageRegistry returns a registry that serves the manifest of team/server:1 to clients that authenticate as
+// robot:secret with Basic authentication, and the host of the registry.
+func newImageRegistry(t *testing.T) (*httptest.Server, string) {
+	t.Helper()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != "robot" || password != "secret" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/v2/team/server/manifests/1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Docker-Content-Digest", testImageDigest)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server, strings.TrimPrefix(server.URL, "https://")
+}
+
+// newPullSecretObjects returns the default service account of the test namespace with a pull secret for host.
+func newPullSecretObjects(host string) []client.Object {
+	auth := base64.StdEncoding.EncodeToString([]byte("robot:secret"))
+	return []client.Object{
+		&corev1.ServiceAccount{
+			ObjectMeta:       metav1.ObjectMeta{Name: defaultServiceAccountName, Namespace: testNamespace},
+			ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry-pull"}},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "registry-pull", Namespace: testNamespace},
+			Type:       corev1.SecretTypeDockerConfigJson,
+			Data: map[string][]byte{
+				corev1.DockerConfigJsonKey: fmt.Appendf(nil, `{"auths": {%q: {"auth": %q}}}`, host, auth),
+			},
+		},
+	}
+}
+
+func TestMCPServerReconciler_getImageCondition(t *testing.T) {
+	server, host := newImageRegistry(t)
+	deployment := func(image string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+			Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "mcp-server", Image: image}},
+			}}},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		image       string
+		objects     []client.Object
+		wantStatus  metav1.ConditionStatus
+		wantReason  string
+		wantMessage string
+	}{
+		{
+			name:       "Verify that an existing image is verified with the pull secret",
+			image:      host + "/team/server:1",
+			objects:    newPullSecretObjects(host),
+			wantStatus: metav1.ConditionFalse,
+			wantReason: ReasonImageVerified,
+		},
+		{
+			name:       "Verify that a missing image is reported",
+			image:      host + "/team/server:2",
+			objects:    newPullSecretObjects(host),
+			wantStatus: metav1.ConditionTrue,
+			wantReason: ReasonManifestNotFound,
+		},
+		{
+			name:    "Verify that the image the Deployment keeps running is reported",
+			image:   host + "/team/server:2",
+			objects: append(newPullSecretObjects(host), deployment(host+"/team/server:1")),
+			wantMessage: fmt.Sprintf("Image %s/team/server:2 does not exist in registry %s, the Deployment keeps "+
+				"running %s/team/server:1", host, host, host),
+			wantStatus: metav1.ConditionTrue,
+			wantReason: ReasonManifestNotFound,
+		},
+		{
+			name:       "Verify that an image the registry refuses access to is not held back",
+			image:      host + "/team/server:2",
+			wantStatus: metav1.ConditionFalse,
+			wantReason: ReasonImageNotVerified,
+		},
+		{
+			name:       "Verify that the registry is not asked for a rolled out image",
+			image:      host + "/team/server:2",
+			objects:    []client.Object{deployment(host + "/team/server:2")},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: ReasonAsExpected,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeScheme := newAutoscalingScheme(t)
+			cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(tt.objects...).Build()
+			r := &MCPServerReconciler{
+				Client:   cli,
+				Scheme:   fakeScheme,
+				Registry: &registry.Client{HTTPClient: server.Client()},
+			}
+			cr := &mcpserverv1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+				Spec:       mcpserverv1.MCPServerSpec{Image: tt.image, VerifyImage: true},
+			}
+
+			got, err := r.getImageCondition(context.Background(), cr)
+			if err != nil {
+				t.Fatalf("getImageCondition() error = %v", err)
+			}
+			if got.Status != tt.wantStatus || got.Reason != tt.wantReason {
+				t.Errorf("getImageCondition() = %s/%s, want %s/%s: %s", got.Status, got.Reason, tt.wantStatus,
+					tt.wantReason, got.Message)
+			}
+			if tt.wantMessage != "" && got.Message != tt.wantMessage {
+				t.Errorf("getImageCondition() message = %q, want %q", got.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_Reconcile_imageNotFound(t *testing.T) {
+	server, host := newImageRegistry(t)
+	fakeScheme := newAutoscalingScheme(t)
+	cr := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+		Spec:       mcpserverv1.MCPServerSpec{Image: host + "/team/server:1", VerifyImage: true},
+	}
+	cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithStatusSubresource(&mcpserverv1.MCPServer{}).
+		WithObjects(append(newPullSecretObjects(host), cr)...).Build()
+	r := &MCPServerReconciler{
+		Client:   cli,
+		Scheme:   fakeScheme,
+		Platform: &cluster.Platform{Name: cluster.Kubernetes},
+		Registry: &registry.Client{HTTPClient: server.Client()},
+	}
+	ctx := context.Background()
+	reconcile := func() ctrl.Result {
+		t.Helper()
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cr)})
+		if err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		if err := cli.Get(ctx, client.ObjectKeyFromObject(cr), cr); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		return result
+	}
+
+	reconcile()
+	deployment := &appsv1.Deployment{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: mcpServerName, Namespace: testNamespace}, deployment); err != nil {
+		t.Fatalf("Get() Deployment error = %v", err)
+	}
+
+	// An image that does not exist is not rolled out.
+	cr.Spec.Image = host + "/team/server:2"
+	if err := cli.Update(ctx, cr); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if result := reconcile(); result.RequeueAfter != DefaultRequeueInterval {
+		t.Errorf("Reconcile() requeues after %v, want %v", result.RequeueAfter, DefaultRequeueInterval)
+	}
+	if !meta.IsStatusConditionTrue(cr.Status.Conditions, ImageNotFound) {
+		t.Errorf("ImageNotFound condition is not True: %+v", cr.Status.Conditions)
+	}
+	available := meta.FindStatusCondition(cr.Status.Conditions, OverallAvailable)
+	if available == nil || available.Reason != ImageNotFound {
+		t.Errorf("Available condition = %+v, want reason %s", available, ImageNotFound)
+	}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(deployment), deployment); err != nil {
+		t.Fatalf("Get() Deployment error = %v", err)
+	}
+	if image := deployment.Spec.Template.Spec.Containers[0].Image; image != host+"/team/server:1" {
+		t.Errorf("Deployment image = %s, want the previous image kept", image)
+	}
+}
+
+func TestMCPServerReconciler_getImageDigestCondition(t *testing.T) {
+	server, host := newImageRegistry(t)
+	resolvedTime := metav1.NewTime(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	const previousDigest = "sha256:fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210"
+
+	tests := []struct {
+		name        string
+		image       string
+		restartedAt string
+		recorded    *mcpserverv1.ImageDigestStatus
+		objects     []client.Object
+		wantStatus  metav1.ConditionStatus
+		wantReason  string
+		wantDigest  string
+	}{
+		{
+			name:       "Verify that the digest of a tag is resolved with the pull secret",
+			image:      host + "/team/server:1",
+			objects:    newPullSecretObjects(host),
+			wantStatus: metav1.ConditionFalse,
+			wantReason: ReasonImagePinned,
+			wantDigest: testImageDigest,
+		},
+		{
+			name:       "Verify that a missing image is reported",
+			image:      host + "/team/server:2",
+			objects:    newPullSecretObjects(host),
+			wantStatus: metav1.ConditionTrue,
+			wantReason: ReasonManifestNotFound,
+		},
+		{
+			name:       "Verify that an image the registry refuses access to is held back",
+			image:      host + "/team/server:1",
+			wantStatus: metav1.ConditionTrue,
+			wantReason: ReasonDigestNotResolved,
+		},
+		{
+			name:       "Verify that the registry is not asked again for a pinned image",
+			image:      host + "/team/server:1",
+			recorded:   &mcpserverv1.ImageDigestStatus{Image: host + "/team/server:1", Digest: previousDigest, ResolvedTime: &resolvedTime},
+			objects:    newPullSecretObjects(host),
+			wantStatus: metav1.ConditionFalse,
+			wantReason: ReasonImagePinned,
+			wantDigest: previousDigest,
+		},
+		{
+			name:        "Verify that the digest is resolved again on a restart",
+			image:       host + "/team/server:1",
+			restartedAt: "2026-01-02T04:00:00Z",
+			recorded:    &mcpserverv1.ImageDigestStatus{Image: host + "/team/server:1", Digest: previousDigest, ResolvedTime: &resolvedTime},
+			objects:     newPullSecretObjects(host),
+			wantStatus:  metav1.ConditionFalse,
+			wantReason:  ReasonImagePinned,
+			wantDigest:  testImageDigest,
+		},
+		{
+			name:        "Verify that the recorded digest is kept when it cannot be resolved again",
+			image:       host + "/team/server:1",
+			restartedAt: "2026-01-02T04:00:00Z",
+			recorded:    &mcpserverv1.ImageDigestStatus{Image: host + "/team/server:1", Digest: previousDigest, ResolvedTime: &resolvedTime},
+			wantStatus:  metav1.ConditionFalse,
+			wantReason:  ReasonImagePinned,
+			wantDigest:  previousDigest,
+		},
+		{
+			name:       "Verify that the digest of a changed image is resolved",
+			image:      host + "/team/server:1",
+			recorded:   &mcpserverv1.ImageDigestStatus{Image: host + "/team/server:0", Digest: previousDigest, ResolvedTime: &resolvedTime},
+			objects:    newPullSecretObjects(host),
+			wantStatus: metav1.ConditionFalse,
+			wantReason: ReasonImagePinned,
+			wantDigest: testImageDigest,
+		},
+		{
+			name:       "Verify that an image given by digest is pinned without the registry",
+			image:      "registry.invalid/team/server@" + previousDigest,
+			wantStatus: metav1.ConditionFalse,
+			wantReason: ReasonImagePinned,
+			wantDigest: previousDigest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeScheme := newAutoscalingScheme(t)
+			cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(tt.objects...).Build()
+			r := &MCPServerReconciler{
+				Client:   cli,
+				Scheme:   fakeScheme,
+				Registry: &registry.Client{HTTPClient: server.Client()},
+			}
+			cr := &mcpserverv1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+				Spec:       mcpserverv1.MCPServerSpec{Image: tt.image, PinImageDigest: true},
+				Status:     mcpserverv1.MCPServerStatus{ImageDigest: tt.recorded},
+			}
+			if tt.restartedAt != "" {
+				cr.Annotations = map[string]string{mcpserverv1.RestartedAtAnnotation: tt.restartedAt}
+			}
+
+			got, err := r.getImageDigestCondition(context.Background(), cr)
+			if err != nil {
+				t.Fatalf("getImageDigestCondition() error = %v", err)
+			}
+			if got.Status != tt.wantStatus || got.Reason != tt.wantReason {
+				t.Errorf("getImageDigestCondition() = %s/%s, want %s/%s: %s", got.Status, got.Reason, tt.wantStatus,
+					tt.wantReason, got.Message)
+			}
+			digest := ""
+			if cr.Status.ImageDigest != nil && cr.Status.ImageDigest.Image == tt.image {
+				digest = cr.Status.ImageDigest.Digest
+			}
+			if digest != tt.wantDigest {
+				t.Errorf("status.imageDigest = %+v, want digest %q", cr.Status.ImageDigest, tt.wantDigest)
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_Reconcile_pinImageDigest(t *testing.T) {
+	server, host := newImageRegistry(t)
+	fakeScheme := newAutoscalingScheme(t)
+	cr := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+		Spec: mcpserverv1.MCPServerSpec{Image: host + "/team/server:1", PinImageDigest: true,
+			ImagePullPolicy: corev1.PullIfNotPresent},
+	}
+	cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithStatusSubresource(&mcpserverv1.MCPServer{}).
+		WithObjects(append(newPullSecretObjects(host), cr)...).Build()
+	r := &MCPServerReconciler{
+		Client:   cli,
+		Scheme:   fakeScheme,
+		Platform: &cluster.Platform{Name: cluster.Kubernetes},
+		Registry: &registry.Client{HTTPClient: server.Client()},
+	}
+	ctx := context.Background()
+	reconcile := func() {
+		t.Helper()
+		if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cr)}); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		if err := cli.Get(ctx, client.ObjectKeyFromObject(cr), cr); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+	}
+
+	reconcile()
+	if cr.Status.ImageDigest == nil || cr.Status.ImageDigest.Digest != testImageDigest {
+		t.Errorf("status.imageDigest = %+v, want %s", cr.Status.ImageDigest, testImageDigest)
+	}
+	deployment := &appsv1.Deployment{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: mcpServerName, Namespace: testNamespace}, deployment); err != nil {
+		t.Fatalf("Get() Deployment error = %v", err)
+	}
+	container := deployment.Spec.Template.Spec.Containers[0]
+	if want := host + "/team/server:1@" + testImageDigest; container.Image != want {
+		t.Errorf("Deployment image = %s, want %s", container.Image, want)
+	}
+	if container.ImagePullPolicy != corev1.PullIfNotPresent {
+		t.Errorf("Deployment imagePullPolicy = %s, want %s", container.ImagePullPolicy, corev1.PullIfNotPresent)
+	}
+
+	// An image whose digest cannot be resolved is not rolled out.
+	cr.Spec.Image = host + "/team/server:2"
+	if err := cli.Update(ctx, cr); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	reconcile()
+	if !meta.IsStatusConditionTrue(cr.Status.Conditions, ImageDigestFailed) {
+		t.Errorf("ImageDigestFailed condition is not True: %+v", cr.Status.Conditions)
+	}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(deployment), deployment); err != nil {
+		t.Fatalf("Get() Deployment error = %v", err)
+	}
+	if image := deployment.Spec.Template.Spec.Containers[0].Image; image != host+"/team/server:1@"+testImageDigest {
+		t.Errorf("Deployment image = %s, want the pinned image kept", image)
+	}
+}
@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+const (
+	// ReasonImagePullFailed is set on the DeploymentAvailable condition when the kubelet cannot pull the
+	// image of an MCP server container. It is also used as the reason of the emitted Warning event.
+	ReasonImagePullFailed = "ImagePullFailed"
+)
+
+// imagePullFailureReasons are the waiting reasons the kubelet reports for a container whose image cannot be pulled.
+var imagePullFailureReasons = map[string]bool{
+	"ErrImagePull":      true,
+	"ImagePullBackOff":  true,
+	"InvalidImageName":  true,
+	"ErrImageNeverPull": true,
+}
+
+// getImagePullFailure returns a message describing the first container of the MCP server pods that is stuck
+// pulling its image, or an empty string when there is none.
+func (r *MCPServerReconciler) getImagePullFailure(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) (string, error) {
+	pods, err := r.listMCPServerPods(ctx, cli, cr)
+	if err != nil {
+		return "", err
+	}
+
+	for _, pod := range pods {
+		statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+		for _, status := range statuses {
+			if status.State.Waiting == nil || !imagePullFailureReasons[status.State.Waiting.Reason] {
+				continue
+			}
+			message := fmt.Sprintf("Failed to pull image %s for container %s in pod %s: %s",
+				status.Image, status.Name, pod.Name, status.State.Waiting.Reason)
+			if status.State.Waiting.Message != "" {
+				message = fmt.Sprintf("%s, %s", message, status.State.Waiting.Message)
+			}
+			return message, nil
+		}
+	}
+	return "", nil
+}
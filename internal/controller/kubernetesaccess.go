@@ -0,0 +1,20 @@
+package controller
+
+import (
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// requireOAuthFlag makes the Kubernetes MCP server reject requests without a bearer token and call the
+// Kubernetes API with the token of each request instead of the one of its service account.
+const requireOAuthFlag = "--require-oauth"
+
+// usesTokenPassthrough reports whether the MCP server calls the Kubernetes API with the tokens of its callers.
+func usesTokenPassthrough(cr *mcpserverv1.MCPServer) bool {
+	return cr.Spec.KubernetesAccess != nil && cr.Spec.KubernetesAccess.Mode == mcpserverv1.KubernetesAccessTokenPassthrough
+}
+
+// tokenPassthroughSkipped is the message of the checks the operator does not run against a server with token
+// passthrough. Such a server calls the Kubernetes API with the token it receives, so the operator never sends it the
+// token of its own service account or of the Jobs it runs.
+const tokenPassthroughSkipped = "Not run for servers with kubernetesAccess.mode TokenPassthrough, which would " +
+	"receive the service account token of the operator"
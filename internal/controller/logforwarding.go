@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+func logForwarding(cr *mcpserverv1.MCPServer) *mcpserverv1.LogForwarding {
+	if cr.Spec.Observability == nil {
+		return nil
+	}
+	return cr.Spec.Observability.LogForwarding
+}
+
+// podLabels returns the labels of the MCP server pods of cr: spec.labels and the labels of
+// spec.podTemplateMetadata, the labels of spec.observability.logForwarding that the cluster logging stack attaches
+// to their logs, the owner team label and the label that selects them, each taking precedence over the former.
+func podLabels(cr *mcpserverv1.MCPServer) map[string]string {
+	labels := maps.Clone(cr.Spec.Labels)
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	templateLabels, _ := podTemplateMetadata(cr)
+	maps.Copy(labels, templateLabels)
+	if forwarding := logForwarding(cr); forwarding != nil {
+		maps.Copy(labels, forwarding.Labels)
+	}
+	if cr.Spec.Owner != nil {
+		labels[OwnerTeamLabel] = cr.Spec.Owner.Team
+	}
+	labels[mcpServerAppLabelKey] = resourceName(cr)
+	return labels
+}
+
+// logForwardingEnv returns the environment variables that make an MCP server instrumented with an
+// OpenTelemetry SDK export its logs to the OTLP endpoint of cr, or nil when it has none.
+func logForwardingEnv(cr *mcpserverv1.MCPServer) []corev1.EnvVar {
+	forwarding := logForwarding(cr)
+	if forwarding == nil || forwarding.OTLPEndpoint == "" || isProxy(cr) {
+		return nil
+	}
+
+	attributes := []string{"k8s.namespace.name=" + cr.Namespace}
+	for _, key := range slices.Sorted(maps.Keys(forwarding.Labels)) {
+		attributes = append(attributes, fmt.Sprintf("%s=%s", key, forwarding.Labels[key]))
+	}
+	return []corev1.EnvVar{
+		{Name: "OTEL_EXPORTER_OTLP_ENDPOINT", Value: forwarding.OTLPEndpoint},
+		{Name: "OTEL_EXPORTER_OTLP_PROTOCOL", Value: "http/protobuf"},
+		{Name: "OTEL_LOGS_EXPORTER", Value: "otlp"},
+		{Name: "OTEL_SERVICE_NAME", Value: cr.Name},
+		{Name: "OTEL_RESOURCE_ATTRIBUTES", Value: strings.Join(attributes, ",")},
+	}
+}
+
+// withEnv returns env with the variables of vars set, replacing those with the same name.
+func withEnv(env []corev1.EnvVar, vars []corev1.EnvVar) []corev1.EnvVar {
+	for _, v := range vars {
+		i := slices.IndexFunc(env, func(e corev1.EnvVar) bool { return e.Name == v.Name })
+		if i < 0 {
+			env = append(env, v)
+			continue
+		}
+		env[i] = v
+	}
+	return env
+}
+
+// applyLogForwarding sets the labels and environment variables of spec.observability.logForwarding on an
+// existing Deployment. Like the other settings, they are left in place when the field is removed.
+func applyLogForwarding(deployment *appsv1.Deployment, cr *mcpserverv1.MCPServer) {
+	forwarding := logForwarding(cr)
+	if forwarding == nil {
+		return
+	}
+	if len(forwarding.Labels) > 0 {
+		if deployment.Spec.Template.Labels == nil {
+			deployment.Spec.Template.Labels = map[string]string{}
+		}
+		maps.Copy(deployment.Spec.Template.Labels, podLabels(cr))
+	}
+	env := logForwardingEnv(cr)
+	if env == nil {
+		return
+	}
+	for i := range deployment.Spec.Template.Spec.Containers {
+		if container := &deployment.Spec.Template.Spec.Containers[i]; container.Name == "mcp-server" {
+			container.Env = withEnv(container.Env, env)
+		}
+	}
+}
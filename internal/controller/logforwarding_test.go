@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+func Test_applyLogForwarding(t *testing.T) {
+	newDeployment := func() *appsv1.Deployment {
+		return &appsv1.Deployment{
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{mcpServerAppLabelKey: mcpServerName}},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{
+								Name: "mcp-server",
+								Env: []corev1.EnvVar{
+									{Name: "LOG_LEVEL", Value: "debug"},
+									{Name: "OTEL_EXPORTER_OTLP_ENDPOINT", Value: "http://old-collector:4318"},
+								},
+							},
+							{Name: metricsExporterContainerName},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		spec       mcpserverv1.MCPServerSpec
+		wantLabels map[string]string
+		wantEnv    map[string]string
+	}{
+		{
+			name:       "no log forwarding",
+			wantLabels: map[string]string{mcpServerAppLabelKey: mcpServerName},
+			wantEnv: map[string]string{
+				"LOG_LEVEL":                   "debug",
+				"OTEL_EXPORTER_OTLP_ENDPOINT": "http://old-collector:4318",
+			},
+		},
+		{
+			name: "labels for the cluster logging stack",
+			spec: mcpserverv1.MCPServerSpec{Observability: &mcpserverv1.Observability{
+				LogForwarding: &mcpserverv1.LogForwarding{Labels: map[string]string{"team": "payments"}},
+			}},
+			wantLabels: map[string]string{mcpServerAppLabelKey: mcpServerName, "team": "payments"},
+			wantEnv: map[string]string{
+				"LOG_LEVEL":                   "debug",
+				"OTEL_EXPORTER_OTLP_ENDPOINT": "http://old-collector:4318",
+			},
+		},
+		{
+			name: "OTLP endpoint",
+			spec: mcpserverv1.MCPServerSpec{Observability: &mcpserverv1.Observability{
+				LogForwarding: &mcpserverv1.LogForwarding{
+					OTLPEndpoint: "http://otel-collector.observability.svc:4318",
+					Labels:       map[string]string{"team": "payments", "env": "prod"},
+				},
+			}},
+			wantLabels: map[string]string{mcpServerAppLabelKey: mcpServerName, "team": "payments", "env": "prod"},
+			wantEnv: map[string]string{
+				"LOG_LEVEL":                   "debug",
+				"OTEL_EXPORTER_OTLP_ENDPOINT": "http://otel-collector.observability.svc:4318",
+				"OTEL_EXPORTER_OTLP_PROTOCOL": "http/protobuf",
+				"OTEL_LOGS_EXPORTER":          "otlp",
+				"OTEL_SERVICE_NAME":           mcpServerName,
+				"OTEL_RESOURCE_ATTRIBUTES":    "k8s.namespace.name=" + testNamespace + ",env=prod,team=payments",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &mcpserverv1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+				Spec:       tt.spec,
+			}
+			deployment := newDeployment()
+			applyLogForwarding(deployment, cr)
+
+			if got := deployment.Spec.Template.Labels; !reflect.DeepEqual(got, tt.wantLabels) {
+				t.Errorf("pod labels = %v, want %v", got, tt.wantLabels)
+			}
+			env := map[string]string{}
+			for _, v := range deployment.Spec.Template.Spec.Containers[0].Env {
+				env[v.Name] = v.Value
+			}
+			if !reflect.DeepEqual(env, tt.wantEnv) {
+				t.Errorf("env = %v, want %v", env, tt.wantEnv)
+			}
+			if sidecar := deployment.Spec.Template.Spec.Containers[1]; len(sidecar.Env) != 0 {
+				t.Errorf("env of the sidecar = %v, want none", sidecar.Env)
+			}
+		})
+	}
+}
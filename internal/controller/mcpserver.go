@@ -3,16 +3,20 @@ package controller
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 
 	routev1 "github.com/openshift/api/route/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
-	ctrl "sigs.k8s.io/controller-runtime"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
@@ -21,6 +25,13 @@ import (
 const (
 	mcpServerAppLabelKey = "opendatahub.io/mcp-server"
 
+	// mcpServerPort is the port the MCP server container listens on, and the port of its Service.
+	mcpServerPort = 8000
+
+	// routeIPAllowlistAnnotation restricts the clients the OpenShift router admits to a Route to the
+	// space-separated IP addresses and CIDR ranges it holds.
+	routeIPAllowlistAnnotation = "haproxy.router.openshift.io/ip_whitelist"
+
 	// Condition types
 	DeploymentAvailable = "DeploymentAvailable"
 	RouteAvailable      = "RouteAvailable"
@@ -43,17 +54,88 @@ var (
 func (r *MCPServerReconciler) reconcileMCPServerDeployment(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) error {
 
 	labels := map[string]string{
-		mcpServerAppLabelKey: cr.Name,
+		mcpServerAppLabelKey: resourceName(cr),
 	}
 
-	command := DefaultMCPDeploymentCommand
-	if cr.Spec.Command != nil {
-		command = cr.Spec.Command
+	command := mcpServerCommand(cr)
+
+	args, err := mcpServerArgs(cr)
+	if err != nil {
+		return err
 	}
 
-	args := DefaultMCPDeploymentArgs
-	if cr.Spec.Args != nil {
-		args = cr.Spec.Args
+	_, templateAnnotations := podTemplateMetadata(cr)
+	podAnnotations := maps.Clone(templateAnnotations)
+	if podAnnotations == nil {
+		podAnnotations = map[string]string{}
+	}
+	if restartedAt := cr.Annotations[mcpserverv1.RestartedAtAnnotation]; restartedAt != "" {
+		podAnnotations[mcpserverv1.RestartedAtAnnotation] = restartedAt
+	}
+	if scc := r.requiredSCC(cr); scc != "" {
+		podAnnotations[requiredSCCAnnotation] = scc
+	}
+
+	var volumes []corev1.Volume
+	var volumeMounts []corev1.VolumeMount
+	if volume, mount := cacheVolume(cr); volume != nil {
+		volumes = append(volumes, *volume)
+		volumeMounts = append(volumeMounts, *mount)
+	}
+	if url := sessionStoreURL(cr); url.Volume != nil {
+		volumes = append(volumes, *url.Volume)
+		volumeMounts = append(volumeMounts, *url.Mount)
+	}
+	volumes = append(volumes, cr.Spec.Volumes...)
+	volumeMounts = append(volumeMounts, cr.Spec.VolumeMounts...)
+
+	container := corev1.Container{
+		Image:           pinnedImage(cr),
+		ImagePullPolicy: cr.Spec.ImagePullPolicy,
+		Name:            "mcp-server",
+		Ports: []corev1.ContainerPort{{
+			ContainerPort: mcpServerPort,
+			Name:          "http",
+		}},
+		Command:      command,
+		Args:         args,
+		Env:          r.mcpServerEnv(cr),
+		EnvFrom:      cr.Spec.EnvFrom,
+		Resources:    r.resources(cr),
+		VolumeMounts: volumeMounts,
+	}
+	if isProxy(cr) {
+		if r.OperatorImage == "" {
+			return fmt.Errorf("the operator image is not configured, unable to deploy the proxy of %s", cr.Name)
+		}
+		container = r.upstreamProxyContainer(cr)
+		volumes = nil
+		if credentials := upstreamProxyCredentials(cr); credentials.Volume != nil {
+			volumes = append(volumes, *credentials.Volume)
+		}
+	}
+	container.Lifecycle = cr.Spec.Lifecycle
+	container.SecurityContext = r.serverContainerSecurityContext(cr)
+	withServerProbes(&container, cr)
+	volumes = withSecretVolume(volumes, guardrailsCredentialsVolumeName, guardrailsCredentials(cr).Volume)
+	volumes = withSecretVolume(volumes, authTokenVolumeName, authToken(cr).Volume)
+	if cr.Spec.Guardrails != nil && r.OperatorImage == "" {
+		return fmt.Errorf("the operator image is not configured, unable to deploy the guardrails filter of %s", cr.Name)
+	}
+	if len(disabledCapabilities(cr)) > 0 && r.OperatorImage == "" {
+		return fmt.Errorf("the operator image is not configured, unable to deploy the capability filter of %s", cr.Name)
+	}
+	if cr.Spec.MetricsExporter != nil && r.OperatorImage == "" {
+		return fmt.Errorf("the operator image is not configured, unable to deploy the metrics exporter of %s", cr.Name)
+	}
+	if usesTokenAuth(cr) && r.OperatorImage == "" {
+		return fmt.Errorf("the operator image is not configured, unable to deploy the token authentication of %s", cr.Name)
+	}
+	if cr.Spec.RateLimit != nil && cr.Spec.RateLimit.Local != nil && r.OperatorImage == "" {
+		return fmt.Errorf("the operator image is not configured, unable to deploy the rate limiter of %s", cr.Name)
+	}
+	if usesRESTBridge(cr) && r.OperatorImage == "" {
+		return fmt.Errorf("the operator image is not configured, unable to deploy the REST bridge of %s", cr.Name)
 	}
 
 	deployment := &appsv1.Deployment{
@@ -62,51 +144,230 @@ func (r *MCPServerReconciler) reconcileMCPServerDeployment(ctx context.Context,
 			Kind:       "Deployment",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      cr.Name,
+			Name:      resourceName(cr),
 			Namespace: cr.Namespace,
 			Labels:    labels,
 		},
 		Spec: appsv1.DeploymentSpec{
+			Replicas:                cr.Spec.Replicas,
+			MinReadySeconds:         ptr.Deref(cr.Spec.MinReadySeconds, 0),
+			ProgressDeadlineSeconds: cr.Spec.ProgressDeadlineSeconds,
+			RevisionHistoryLimit:    cr.Spec.RevisionHistoryLimit,
 			Selector: &metav1.LabelSelector{
 				MatchLabels: labels,
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: labels,
+					Labels:      podLabels(cr),
+					Annotations: podAnnotations,
 				},
 				Spec: corev1.PodSpec{
-					Containers: []corev1.Container{{
-						Image: cr.Spec.Image,
-						Name:  "mcp-server",
-						Ports: []corev1.ContainerPort{{
-							ContainerPort: 8000,
-							Name:          "http",
-						}},
-						Command: command,
-						Args:    args,
-					}},
+					Containers:                   r.withSidecars(cr, []corev1.Container{container}),
+					Volumes:                      volumes,
+					Affinity:                     podAffinity(cr),
+					NodeSelector:                 cr.Spec.NodeSelector,
+					Tolerations:                  cr.Spec.Tolerations,
+					HostAliases:                  cr.Spec.HostAliases,
+					ServiceAccountName:           podServiceAccountName(cr),
+					AutomountServiceAccountToken: automountServiceAccountToken(cr),
+					SecurityContext:              podSecurityContext(cr),
 				},
 			},
 		},
 	}
 
+	withCustomMetadata(cr, deployment)
+
 	// Set the MCPServer to own the deployment.
-	err := ctrl.SetControllerReference(cr, deployment, r.Scheme)
+	err = r.createChild(ctx, cli, cr, deployment)
 	if err != nil {
 		return err
 	}
+	existing := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: resourceName(cr), Namespace: cr.Namespace}}
+	if err := reconcileCustomMetadata(ctx, cli, cr, existing); err != nil {
+		return err
+	}
 
-	err = cli.Create(ctx, deployment)
-	if err != nil && !k8serr.IsAlreadyExists(err) {
+	if err := r.reconcileDeploymentRestart(ctx, cli, cr); err != nil {
 		return err
 	}
+	if err := r.reconcileDeploymentSidecars(ctx, cli, cr); err != nil {
+		return err
+	}
+	return r.reconcileDeploymentSpec(ctx, cli, cr)
+}
+
+// proxyEnv returns the environment variables that make the MCP server use the cluster-wide egress proxy.
+func (r *MCPServerReconciler) proxyEnv() []corev1.EnvVar {
+	if r.Platform == nil || r.Platform.Proxy == nil {
+		return nil
+	}
+
+	proxy := r.Platform.Proxy
+	var env []corev1.EnvVar
+	for _, v := range []struct {
+		names []string
+		value string
+	}{
+		{[]string{"HTTP_PROXY", "http_proxy"}, proxy.HTTPProxy},
+		{[]string{"HTTPS_PROXY", "https_proxy"}, proxy.HTTPSProxy},
+		{[]string{"NO_PROXY", "no_proxy"}, proxy.NoProxy},
+	} {
+		if v.value == "" {
+			continue
+		}
+		for _, name := range v.names {
+			env = append(env, corev1.EnvVar{Name: name, Value: v.value})
+		}
+	}
+	return env
+}
+
+// reconcileDeploymentRestart copies the restartedAt annotation of the MCPServer onto the pod template of an
+// existing Deployment, which makes the Deployment controller roll out new pods.
+func (r *MCPServerReconciler) reconcileDeploymentRestart(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) error {
+	restartedAt := cr.Annotations[mcpserverv1.RestartedAtAnnotation]
+	if restartedAt == "" {
+		return nil
+	}
+
+	deployment := &appsv1.Deployment{}
+	err := cli.Get(ctx, client.ObjectKey{Name: resourceName(cr), Namespace: cr.Namespace}, deployment)
+	if err != nil {
+		// A Deployment that was only just created, or only created as a dry run, is not in the cache yet.
+		// It already carries the annotation.
+		if k8serr.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if deployment.Spec.Template.Annotations[mcpserverv1.RestartedAtAnnotation] == restartedAt {
+		return nil
+	}
+
+	original := deployment.DeepCopy()
+	if deployment.Spec.Template.Annotations == nil {
+		deployment.Spec.Template.Annotations = map[string]string{}
+	}
+	deployment.Spec.Template.Annotations[mcpserverv1.RestartedAtAnnotation] = restartedAt
+	logChildDiff(ctx, original, deployment)
+	return cli.Patch(ctx, deployment, client.MergeFrom(original))
+}
+
+// reconcileDeploymentSpec applies the fields of cr that are set to an existing Deployment: the replicas, unless
+// they are ignored or autoscaled, the rollout settings and the pod settings it sets itself, then those of the
+// apply* helpers, such as applyScheduling, applyProbes and applyServerContainer.
+func (r *MCPServerReconciler) reconcileDeploymentSpec(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) error {
+	deployment := &appsv1.Deployment{}
+	err := cli.Get(ctx, client.ObjectKey{Name: resourceName(cr), Namespace: cr.Namespace}, deployment)
+	if err != nil {
+		// A Deployment that was only just created is not in the cache yet, and already has the replicas.
+		if k8serr.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	autoscaled, err := r.isAutoscaled(ctx, cli, cr)
+	if err != nil {
+		return err
+	}
+
+	original := deployment.DeepCopy()
+	if cr.Spec.Replicas != nil && !ignoresReplicas(cr) && (!autoscaled || scalesToOrFromZero(cr, deployment)) {
+		deployment.Spec.Replicas = ptr.To(*cr.Spec.Replicas)
+	}
+	applyScheduling(deployment, cr)
+	if cr.Spec.MinReadySeconds != nil {
+		deployment.Spec.MinReadySeconds = *cr.Spec.MinReadySeconds
+	}
+	if cr.Spec.ProgressDeadlineSeconds != nil {
+		deployment.Spec.ProgressDeadlineSeconds = ptr.To(*cr.Spec.ProgressDeadlineSeconds)
+	}
+	if cr.Spec.RevisionHistoryLimit != nil {
+		deployment.Spec.RevisionHistoryLimit = ptr.To(*cr.Spec.RevisionHistoryLimit)
+	}
+	if cr.Spec.HostAliases != nil {
+		deployment.Spec.Template.Spec.HostAliases = cr.Spec.HostAliases
+	}
+	if cr.Spec.ServiceAccountName != "" {
+		deployment.Spec.Template.Spec.ServiceAccountName = cr.Spec.ServiceAccountName
+	}
+	if cr.Spec.AutomountServiceAccountToken != nil {
+		deployment.Spec.Template.Spec.AutomountServiceAccountToken = ptr.To(*cr.Spec.AutomountServiceAccountToken)
+	}
+	if cr.Spec.Lifecycle != nil {
+		for i := range deployment.Spec.Template.Spec.Containers {
+			if container := &deployment.Spec.Template.Spec.Containers[i]; container.Name == "mcp-server" {
+				container.Lifecycle = cr.Spec.Lifecycle
+			}
+		}
+	}
+	applyPodTemplateMetadata(deployment, cr)
+	applyLogForwarding(deployment, cr)
+	applyVolumes(deployment, cr)
+	applyProbes(deployment, cr)
+	r.applySecurityContext(deployment, cr)
+	applyOwnerLabel(deployment, cr)
+	if err := applyServerContainer(deployment, cr); err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(original.Spec, deployment.Spec) {
+		return nil
+	}
+	logChildDiff(ctx, original, deployment)
+	return cli.Patch(ctx, deployment, client.MergeFrom(original))
+}
+
+// mcpServerCommand returns the command of the MCP server container, spec.command or the default command.
+func mcpServerCommand(cr *mcpserverv1.MCPServer) []string {
+	if cr.Spec.Command != nil {
+		return cr.Spec.Command
+	}
+	return DefaultMCPDeploymentCommand
+}
+
+// applyServerContainer sets the image, spec.imagePullPolicy, command, args, the session store variables, spec.env
+// and spec.envFrom of the MCP server container of an existing Deployment, so that changing them, or rolling them
+// back, rolls out the Deployment. Variables removed from spec.env are left in place, like those of the other
+// settings. The proxy container of a Proxy MCP server is left alone.
+func applyServerContainer(deployment *appsv1.Deployment, cr *mcpserverv1.MCPServer) error {
+	if isProxy(cr) {
+		return nil
+	}
+	args, err := mcpServerArgs(cr)
+	if err != nil {
+		return err
+	}
+	for i := range deployment.Spec.Template.Spec.Containers {
+		if container := &deployment.Spec.Template.Spec.Containers[i]; container.Name == "mcp-server" {
+			container.Image = pinnedImage(cr)
+			if cr.Spec.ImagePullPolicy != "" {
+				container.ImagePullPolicy = cr.Spec.ImagePullPolicy
+			}
+			container.Command = slices.Clone(mcpServerCommand(cr))
+			container.Args = args
+			container.Env = withEnv(withEnv(container.Env, sessionStoreEnv(cr)), cr.Spec.Env)
+			container.EnvFrom = slices.Clone(cr.Spec.EnvFrom)
+		}
+	}
 	return nil
 }
 
+// automountServiceAccountToken returns whether the MCP server pods get the token of their service account. Only
+// the Kubernetes MCP server run by the default command and the proxy, which reviews the tokens of its callers,
+// use the Kubernetes API.
+func automountServiceAccountToken(cr *mcpserverv1.MCPServer) *bool {
+	if cr.Spec.AutomountServiceAccountToken != nil {
+		return ptr.To(*cr.Spec.AutomountServiceAccountToken)
+	}
+	return ptr.To(isProxy(cr) || cr.Spec.Command == nil)
+}
+
 func (r *MCPServerReconciler) reconcileMCPServerService(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) error {
 
 	labels := map[string]string{
-		mcpServerAppLabelKey: cr.Name,
+		mcpServerAppLabelKey: resourceName(cr),
 	}
 
 	service := &corev1.Service{
@@ -115,7 +376,7 @@ func (r *MCPServerReconciler) reconcileMCPServerService(ctx context.Context, cli
 			Kind:       "Service",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      cr.Name,
+			Name:      resourceName(cr),
 			Namespace: cr.Namespace,
 			Labels:    labels,
 		},
@@ -123,32 +384,33 @@ func (r *MCPServerReconciler) reconcileMCPServerService(ctx context.Context, cli
 			Selector: labels,
 			Ports: []corev1.ServicePort{
 				{
-					Name:       "http",
-					Port:       8000,
-					TargetPort: intstr.FromString("http"),
-					Protocol:   corev1.ProtocolTCP,
+					Name:        "http",
+					Port:        mcpServerPort,
+					TargetPort:  intstr.FromString("http"),
+					Protocol:    corev1.ProtocolTCP,
+					AppProtocol: serviceAppProtocol(cr),
 				},
 			},
 		},
 	}
 
+	withCustomMetadata(cr, service)
+
 	// Set MCPServer to own the service.
-	err := ctrl.SetControllerReference(cr, service, r.Scheme)
-	if err != nil {
+	if err := r.createChild(ctx, cli, cr, service); err != nil {
 		return err
 	}
-
-	err = cli.Create(ctx, service)
-	if err != nil && !k8serr.IsAlreadyExists(err) {
+	existing := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: resourceName(cr), Namespace: cr.Namespace}}
+	if err := reconcileCustomMetadata(ctx, cli, cr, existing); err != nil {
 		return err
 	}
-	return nil
+	return r.reconcileServiceAppProtocol(ctx, cli, cr)
 }
 
 func (r *MCPServerReconciler) reconcileMCPServerRoute(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) error {
 
 	labels := map[string]string{
-		mcpServerAppLabelKey: cr.Name,
+		mcpServerAppLabelKey: resourceName(cr),
 	}
 
 	route := &routev1.Route{
@@ -157,45 +419,141 @@ func (r *MCPServerReconciler) reconcileMCPServerRoute(ctx context.Context, cli c
 			Kind:       "Route",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      cr.Name,
+			Name:      resourceName(cr),
 			Namespace: cr.Namespace,
 			Labels:    labels,
 		},
 		Spec: routev1.RouteSpec{
 			To: routev1.RouteTargetReference{
 				Kind: "Service",
-				Name: cr.Name,
+				Name: resourceName(cr),
 			},
 			Port: &routev1.RoutePort{
 				TargetPort: intstr.FromString("http"),
 			},
+			Path: cr.Spec.BasePath,
+			TLS:  routeTLS(cr),
 		},
 	}
+	managedAnnotations := slices.Collect(maps.Keys(routeAnnotations(cr)))
+	withCustomMetadata(cr, route, managedAnnotations...)
+	for key, value := range routeAnnotations(cr) {
+		if value != "" {
+			metav1.SetMetaDataAnnotation(&route.ObjectMeta, key, value)
+		}
+	}
+	certificate, _, err := r.getRouteCertificate(ctx, cli, cr)
+	if err != nil {
+		return err
+	}
+	applyRouteCertificate(cr, route, certificate)
+	applyIngressControllerSelector(cr, route)
 
 	// Set MCPServer to own the route.
-	err := ctrl.SetControllerReference(cr, route, r.Scheme)
-	if err != nil {
+	if err := r.createChild(ctx, cli, cr, route); err != nil {
+		return err
+	}
+	existing := &routev1.Route{ObjectMeta: metav1.ObjectMeta{Name: resourceName(cr), Namespace: cr.Namespace}}
+	if err := reconcileCustomMetadata(ctx, cli, cr, existing, managedAnnotations...); err != nil {
 		return err
 	}
+	if err := r.reconcileRouteSpec(ctx, cli, cr, certificate); err != nil {
+		return err
+	}
+	return r.reconcileRouteAnnotations(ctx, cli, cr)
+}
+
+// routeIPAllowlist returns the value of the IP allowlist annotation of the Route of cr, or "" when all clients
+// are allowed.
+func routeIPAllowlist(cr *mcpserverv1.MCPServer) string {
+	if cr.Spec.Expose == nil {
+		return ""
+	}
+	return strings.Join(cr.Spec.Expose.AllowedSourceRanges, " ")
+}
+
+// routeAnnotations returns the annotations the operator manages on the Route of cr. An annotation with an empty
+// value is removed. The timeout is only managed while spec.sse.idleTimeout is set, so that a timeout set by hand
+// is kept otherwise.
+func routeAnnotations(cr *mcpserverv1.MCPServer) map[string]string {
+	annotations := map[string]string{routeIPAllowlistAnnotation: routeIPAllowlist(cr)}
+	if timeout := routeTimeout(cr); timeout != "" {
+		annotations[routeTimeoutAnnotation] = timeout
+	}
+	return annotations
+}
 
-	err = cli.Create(ctx, route)
-	if err != nil && !k8serr.IsAlreadyExists(err) {
+// reconcileRouteSpec keeps the path of the existing Route of cr at spec.basePath, and terminates TLS at the router
+// on the Route of an HTTP2 or GRPC MCP server that has no TLS configuration. A TLS configuration that is already
+// set, by hand or for an earlier protocol, is kept, unless certificate, the TLS configuration with the certificate
+// of spec.route.tls.certificateSecretRef, replaces it. The labels of spec.route.ingressControllerSelector are kept
+// in line too.
+func (r *MCPServerReconciler) reconcileRouteSpec(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer,
+	certificate *routev1.TLSConfig) error {
+	route := &routev1.Route{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: resourceName(cr), Namespace: cr.Namespace}, route); err != nil {
+		if k8serr.IsNotFound(err) {
+			return nil
+		}
 		return err
 	}
-	return nil
+
+	original := route.DeepCopy()
+	route.Spec.Path = cr.Spec.BasePath
+	if route.Spec.TLS == nil {
+		route.Spec.TLS = routeTLS(cr)
+	}
+	applyRouteCertificate(cr, route, certificate)
+	applyIngressControllerSelector(cr, route)
+	if equality.Semantic.DeepEqual(original.Spec, route.Spec) &&
+		equality.Semantic.DeepEqual(original.Labels, route.Labels) &&
+		equality.Semantic.DeepEqual(original.Annotations, route.Annotations) {
+		return nil
+	}
+	logChildDiff(ctx, original, route)
+	return cli.Patch(ctx, route, client.MergeFrom(original))
+}
+
+// reconcileRouteAnnotations applies spec.expose.allowedSourceRanges and spec.sse.idleTimeout to an existing
+// Route, and removes the allowlist once the ranges are unset, so that the Route never admits clients the
+// MCPServer does not. Annotations listed in spec.ignoreDifferences are left alone.
+func (r *MCPServerReconciler) reconcileRouteAnnotations(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) error {
+	route := &routev1.Route{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: resourceName(cr), Namespace: cr.Namespace}, route); err != nil {
+		// A Route that was only just created is not in the cache yet, and already has the annotations.
+		if k8serr.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	original := route.DeepCopy()
+	for key, value := range routeAnnotations(cr) {
+		if value == "" {
+			delete(route.Annotations, key)
+		} else {
+			metav1.SetMetaDataAnnotation(&route.ObjectMeta, key, value)
+		}
+	}
+	keepIgnoredAnnotations(cr, original, route)
+	if equality.Semantic.DeepEqual(original.Annotations, route.Annotations) {
+		return nil
+	}
+	logChildDiff(ctx, original, route)
+	return cli.Patch(ctx, route, client.MergeFrom(original))
 }
 
 func (r *MCPServerReconciler) getDeploymentCondition(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) metav1.Condition {
 	dep := &appsv1.Deployment{}
 
-	err := cli.Get(ctx, client.ObjectKey{Name: cr.Name, Namespace: cr.Namespace}, dep)
+	err := cli.Get(ctx, client.ObjectKey{Name: resourceName(cr), Namespace: cr.Namespace}, dep)
 	if err != nil {
 		if k8serr.IsNotFound(err) {
 			return metav1.Condition{
 				Type:    DeploymentAvailable,
 				Status:  metav1.ConditionFalse,
 				Reason:  fmt.Sprintf("%s%s", "Deployment", ReasonNotFoundSuffix),
-				Message: fmt.Sprintf("Deployment %s cannot be found", cr.Name),
+				Message: fmt.Sprintf("Deployment %s cannot be found", resourceName(cr)),
 			}
 		}
 		return metav1.Condition{
@@ -219,11 +577,37 @@ func (r *MCPServerReconciler) getDeploymentCondition(ctx context.Context, cli cl
 	}
 
 	if !meta.IsStatusConditionTrue(deploymentConditions, string(appsv1.DeploymentAvailable)) {
+		// A pod that cannot pull its image never becomes available, so surface the cause instead of
+		// leaving the user with a generic not ready message.
+		if message, err := r.getImagePullFailure(ctx, cli, cr); err == nil && message != "" {
+			return metav1.Condition{
+				Type:    DeploymentAvailable,
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonImagePullFailed,
+				Message: message,
+			}
+		}
+		if message, err := r.getCrashLoopFailure(ctx, cli, cr); err == nil && message != "" {
+			return metav1.Condition{
+				Type:    DeploymentAvailable,
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonCrashLoopBackOff,
+				Message: message,
+			}
+		}
+		if message := getSCCFailure(dep); message != "" {
+			return metav1.Condition{
+				Type:    DeploymentAvailable,
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonSCCDenied,
+				Message: message,
+			}
+		}
 		return metav1.Condition{
 			Type:    DeploymentAvailable,
 			Status:  metav1.ConditionFalse,
 			Reason:  fmt.Sprintf("%s%s", "Deployment", ReasonNotReadySuffix),
-			Message: fmt.Sprintf("Deployment %s is not yet available", cr.Name),
+			Message: fmt.Sprintf("Deployment %s is not yet available", resourceName(cr)),
 		}
 	}
 
@@ -231,15 +615,29 @@ func (r *MCPServerReconciler) getDeploymentCondition(ctx context.Context, cli cl
 		Type:    DeploymentAvailable,
 		Status:  metav1.ConditionTrue,
 		Reason:  fmt.Sprintf("%s%s", "Deployment", ReasonReadySuffix),
-		Message: fmt.Sprintf("Deployment %s is available", cr.Name),
+		Message: fmt.Sprintf("Deployment %s is available", resourceName(cr)),
 	}
 
 }
 
+// getDeploymentReplicas returns the number of pods and ready pods of the MCP server Deployment. Both are zero
+// when the Deployment does not exist yet.
+func (r *MCPServerReconciler) getDeploymentReplicas(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) (int32, int32, error) {
+	dep := &appsv1.Deployment{}
+	err := cli.Get(ctx, client.ObjectKey{Name: resourceName(cr), Namespace: cr.Namespace}, dep)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return 0, 0, nil
+		}
+		return 0, 0, err
+	}
+	return dep.Status.Replicas, dep.Status.ReadyReplicas, nil
+}
+
 func (r *MCPServerReconciler) getServiceCondition(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) metav1.Condition {
 
 	svc := &corev1.Service{}
-	err := cli.Get(ctx, client.ObjectKey{Name: cr.Name, Namespace: cr.Namespace}, svc)
+	err := cli.Get(ctx, client.ObjectKey{Name: resourceName(cr), Namespace: cr.Namespace}, svc)
 
 	if err != nil {
 		if k8serr.IsNotFound(err) {
@@ -247,7 +645,7 @@ func (r *MCPServerReconciler) getServiceCondition(ctx context.Context, cli clien
 				Type:    ServiceAvailable,
 				Status:  metav1.ConditionFalse,
 				Reason:  fmt.Sprintf("%s%s", "Service", ReasonNotFoundSuffix),
-				Message: fmt.Sprintf("Service %s not found", cr.Name),
+				Message: fmt.Sprintf("Service %s not found", resourceName(cr)),
 			}
 		}
 		return metav1.Condition{
@@ -262,13 +660,13 @@ func (r *MCPServerReconciler) getServiceCondition(ctx context.Context, cli clien
 		Type:    ServiceAvailable,
 		Status:  metav1.ConditionTrue,
 		Reason:  fmt.Sprintf("%s%s", "Service", ReasonReadySuffix),
-		Message: fmt.Sprintf("Service %s exists and is available", cr.Name),
+		Message: fmt.Sprintf("Service %s exists and is available", resourceName(cr)),
 	}
 }
 
 func (r *MCPServerReconciler) getRouteCondition(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) metav1.Condition {
 	route := &routev1.Route{}
-	err := cli.Get(ctx, client.ObjectKey{Name: cr.Name, Namespace: cr.Namespace}, route)
+	err := cli.Get(ctx, client.ObjectKey{Name: resourceName(cr), Namespace: cr.Namespace}, route)
 
 	if err != nil {
 		if k8serr.IsNotFound(err) {
@@ -276,7 +674,7 @@ func (r *MCPServerReconciler) getRouteCondition(ctx context.Context, cli client.
 				Type:    RouteAvailable,
 				Status:  metav1.ConditionFalse,
 				Reason:  fmt.Sprintf("%s%s", "Route", ReasonNotFoundSuffix),
-				Message: fmt.Sprintf("Route %s not found", cr.Name),
+				Message: fmt.Sprintf("Route %s not found", resourceName(cr)),
 			}
 		}
 		return metav1.Condition{
@@ -287,25 +685,34 @@ func (r *MCPServerReconciler) getRouteCondition(ctx context.Context, cli client.
 		}
 	}
 
-	admitted := false
-	for _, ingress := range route.Status.Ingress {
-		for _, cond := range ingress.Conditions {
-			if cond.Type == routev1.RouteAdmitted && cond.Status == corev1.ConditionTrue {
-				admitted = true
-				break
-			}
+	_, problem, err := r.getRouteCertificate(ctx, cli, cr)
+	if err != nil {
+		return metav1.Condition{
+			Type:    RouteAvailable,
+			Status:  metav1.ConditionUnknown,
+			Reason:  fmt.Sprintf("%s%s", "Secret", ReasonGetFailedSuffix),
+			Message: fmt.Sprintf("Failed to get certificate Secret %s: %v", certificateSecretName(cr), err),
 		}
-		if admitted {
-			break
+	}
+	if problem != "" {
+		return metav1.Condition{
+			Type:    RouteAvailable,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonCertificateSecretInvalid,
+			Message: problem,
 		}
 	}
 
-	if !admitted {
+	if !routeAdmitted(route) {
+		message := fmt.Sprintf("Route %s has not been admitted by a router yet", resourceName(cr))
+		if len(ingressControllerSelector(cr)) > 0 {
+			message += ", check that the routeSelector of an IngressController matches spec.route.ingressControllerSelector"
+		}
 		return metav1.Condition{
 			Type:    RouteAvailable,
 			Status:  metav1.ConditionFalse,
 			Reason:  ReasonRouteNotAdmitted,
-			Message: fmt.Sprintf("Route %s has not been admitted by a router yet", cr.Name),
+			Message: message,
 		}
 	}
 
@@ -313,16 +720,33 @@ func (r *MCPServerReconciler) getRouteCondition(ctx context.Context, cli client.
 		Type:    RouteAvailable,
 		Status:  metav1.ConditionTrue,
 		Reason:  fmt.Sprintf("%s%s", "Route", ReasonReadySuffix),
-		Message: fmt.Sprintf("Route %s is admitted and active", cr.Name),
+		Message: fmt.Sprintf("Route %s is admitted and active", resourceName(cr)),
 	}
 
 }
 
+// routeAdmitted reports whether a router has admitted route.
+func routeAdmitted(route *routev1.Route) bool {
+	for _, ingress := range route.Status.Ingress {
+		for _, cond := range ingress.Conditions {
+			if cond.Type == routev1.RouteAdmitted && cond.Status == corev1.ConditionTrue {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (r *MCPServerReconciler) getOverallCondition(cr *mcpserverv1.MCPServer) metav1.Condition {
+	if isExternal(cr) {
+		return getExternalOverallCondition(cr)
+	}
 
 	depCondition := meta.FindStatusCondition(cr.Status.Conditions, DeploymentAvailable)
 	svcCondition := meta.FindStatusCondition(cr.Status.Conditions, ServiceAvailable)
 	routeCondition := meta.FindStatusCondition(cr.Status.Conditions, RouteAvailable)
+	httpRouteCondition := meta.FindStatusCondition(cr.Status.Conditions, HTTPRouteAccepted)
+	endpointCondition := meta.FindStatusCondition(cr.Status.Conditions, EndpointReachable)
 
 	if depCondition == nil || depCondition.Status != metav1.ConditionTrue {
 		return metav1.Condition{
@@ -332,6 +756,15 @@ func (r *MCPServerReconciler) getOverallCondition(cr *mcpserverv1.MCPServer) met
 			Message: "Deployment is not yet ready",
 		}
 	}
+	// Components that were ready for a previous generation say nothing about the new one until its rollout is done.
+	if meta.IsStatusConditionTrue(cr.Status.Conditions, Progressing) && !verifiedGeneration(cr) {
+		return metav1.Condition{
+			Type:    OverallAvailable,
+			Status:  metav1.ConditionUnknown,
+			Reason:  ReasonRolloutInProgress,
+			Message: fmt.Sprintf("Generation %d is rolling out", cr.Generation),
+		}
+	}
 	if svcCondition == nil || svcCondition.Status != metav1.ConditionTrue {
 		return metav1.Condition{
 			Type:    OverallAvailable,
@@ -340,7 +773,24 @@ func (r *MCPServerReconciler) getOverallCondition(cr *mcpserverv1.MCPServer) met
 			Message: "Service is not yet ready",
 		}
 	}
-	if routeCondition == nil || routeCondition.Status != metav1.ConditionTrue {
+	if usesGateway(cr) && (httpRouteCondition == nil || httpRouteCondition.Status != metav1.ConditionTrue) {
+		return metav1.Condition{
+			Type:    OverallAvailable,
+			Status:  metav1.ConditionFalse,
+			Reason:  fmt.Sprintf("%s%s", "HTTPRoute", ReasonNotReadySuffix),
+			Message: "HTTPRoute is not yet accepted",
+		}
+	}
+	virtualServiceCondition := meta.FindStatusCondition(cr.Status.Conditions, VirtualServiceAvailable)
+	if usesMeshGateway(cr) && (virtualServiceCondition == nil || virtualServiceCondition.Status != metav1.ConditionTrue) {
+		return metav1.Condition{
+			Type:    OverallAvailable,
+			Status:  metav1.ConditionFalse,
+			Reason:  fmt.Sprintf("%s%s", "VirtualService", ReasonNotReadySuffix),
+			Message: "VirtualService is not yet available",
+		}
+	}
+	if r.usesRoute(cr) && (routeCondition == nil || routeCondition.Status != metav1.ConditionTrue) {
 		return metav1.Condition{
 			Type:    OverallAvailable,
 			Status:  metav1.ConditionFalse,
@@ -348,12 +798,28 @@ func (r *MCPServerReconciler) getOverallCondition(cr *mcpserverv1.MCPServer) met
 			Message: "Route is not yet ready",
 		}
 	}
+	if endpointCondition == nil || endpointCondition.Status != metav1.ConditionTrue {
+		return metav1.Condition{
+			Type:    OverallAvailable,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonEndpointUnreachable,
+			Message: "Endpoint is not yet reachable",
+		}
+	}
 
+	components := "Deployment, Service, Route"
+	if usesGateway(cr) {
+		components = "Deployment, Service, HTTPRoute"
+	} else if usesMeshGateway(cr) {
+		components = "Deployment, Service, VirtualService"
+	} else if !r.routeAPIAvailable() {
+		components = "Deployment, Service"
+	}
 	return metav1.Condition{
 		Type:    OverallAvailable,
 		Status:  metav1.ConditionTrue,
 		Reason:  "AllComponentsReady",
-		Message: "All managed components (Deployment, Service, Route) are ready",
+		Message: fmt.Sprintf("All managed components (%s) are ready and the endpoint is reachable", components),
 	}
 
 }
@@ -18,12 +18,19 @@ package controller
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
 	"reflect"
 	"time"
 
 	routev1 "github.com/openshift/api/route/v1"
+	"golang.org/x/sync/errgroup"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -33,18 +40,93 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster/gvk"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/registry"
+)
+
+const (
+	// DefaultRequeueInterval is how often the endpoint of an MCPServer is probed while it is not reachable,
+	// when neither the operator nor the MCPServer configure an interval.
+	DefaultRequeueInterval = 15 * time.Second
+
+	// resyncInterval is how often every MCPServer is reconciled regardless of watch events. Changes to the
+	// managed resources are picked up by the watches, so this is only a safety net, e.g. for a ready endpoint
+	// that stops responding.
+	resyncInterval = 10 * time.Minute
 )
 
 // MCPServerReconciler reconciles a MCPServer object
 type MCPServerReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+
+	// HTTPClient is used to probe the MCP server endpoint. http.DefaultClient is used when nil.
+	HTTPClient *http.Client
+
+	// Registry checks that the images of MCP servers with spec.verifyImage exist. A client with
+	// http.DefaultClient is used when nil.
+	Registry *registry.Client
+
+	// OperatorImage is the image of the operator itself, used to run connection test Jobs.
+	OperatorImage string
+
+	// OperatorNamespace is the namespace the operator runs in, which the NetworkPolicies of MCP servers admit so
+	// that the operator can probe them. It is empty when the operator runs outside the cluster.
+	OperatorNamespace string
+
+	// SharedHost is the host MCP servers with a SharedHost exposure are published under, by the router the
+	// SharedHostReconciler deploys in OperatorNamespace. SharedHost exposures are not available when empty.
+	SharedHost string
+
+	// Recorder emits events on the MCPServer. No events are emitted when nil.
+	Recorder record.EventRecorder
+
+	// Platform describes the cluster the operator runs on. OpenShift with the Route API is assumed when nil.
+	Platform *cluster.Platform
+
+	// APIReader reads the objects the cache of the manager does not hold, such as the pods of connection test
+	// Jobs, which do not carry the MCP server label. The client is used when nil.
+	APIReader client.Reader
+
+	// DryRun makes the controller report the changes it would make to the managed resources in the
+	// DriftDetected condition and in events, without applying them. Only the MCPServer status is written.
+	DryRun bool
+
+	// SessionStoreImage is the Redis image of session stores provisioned by the operator.
+	// DefaultSessionStoreImage is used when empty.
+	SessionStoreImage string
+
+	// ResourcePresets are the bundles MCPServers select with spec.resourcesPreset. DefaultResourcePresets is
+	// used when nil.
+	ResourcePresets ResourcePresets
+
+	// RequeueInterval is the default interval at which unreachable MCPServer endpoints are probed.
+	// DefaultRequeueInterval is used when zero. It can be overridden per MCPServer with spec.requeueInterval.
+	RequeueInterval time.Duration
+
+	// OwnerFormat is the format spec.owner must have. Any team and contact are accepted when its patterns are nil.
+	OwnerFormat OwnerFormat
+
+	// StallThreshold is the number of consecutive failed reconciliations after which an MCPServer gets the
+	// ReconcileStalled condition and is only retried after a cool-down. DefaultStallThreshold is used when zero.
+	StallThreshold int
+
+	// Shard selects the MCPServers this replica reconciles. All MCPServers are reconciled when nil.
+	Shard *Shard
+
+	failures failureTracker
 }
 
 // +kubebuilder:rbac:groups=mcpserver.opendatahub.io,resources=mcpservers,verbs=get;list;watch;create;update;patch;delete
@@ -54,10 +136,27 @@ type MCPServerReconciler struct {
 // +kubebuilder:rbac:groups="",resources=services,verbs=create;get;list;watch;update;patch;delete
 // +kubebuilder:rbac:groups="apps",resources=deployments,verbs=create;get;list;watch;update;patch;delete
 // +kubebuilder:rbac:groups="route.openshift.io",resources=routes,verbs=create;get;list;watch;update;patch;delete
+// +kubebuilder:rbac:groups="batch",resources=jobs,verbs=create;get;list;watch;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 func (r *MCPServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	// The MCPServers of other shards are reconciled by other replicas of the operator.
+	if !r.Shard.Owns(req.NamespacedName) {
+		return ctrl.Result{}, nil
+	}
+	result, err := r.reconcile(ctx, req)
+	if err != nil {
+		return r.handleFailure(ctx, req, err)
+	}
+	r.failures.succeed(req.NamespacedName)
+	return result, nil
+}
+
+// reconcile reconciles the MCPServer of req once.
+func (r *MCPServerReconciler) reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	// Create logger with passed in context value
 	logger := logf.FromContext(ctx)
 
@@ -80,38 +179,128 @@ func (r *MCPServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 
 	}
 
+	// An MCPServer whose TTL elapsed is deleted rather than reconciled.
+	if deleted, err := r.deleteExpired(ctx, mcpServer); err != nil || deleted {
+		return ctrl.Result{}, err
+	}
+
+	if err = r.reconcileShardLabel(ctx, mcpServer); err != nil {
+		logger.Error(err, "Failed to label MCPServer with its shard")
+		return ctrl.Result{}, err
+	}
+
 	originalStatus := mcpServer.Status.DeepCopy()
+	mcpServer.Status.ExpirationTime = expirationTime(mcpServer)
+	mcpServer.Status.Shard = r.Shard.status()
+	// A stalled MCPServer recovers once it is reconciled successfully, which writes its status without the condition.
+	meta.RemoveStatusCondition(&mcpServer.Status.Conditions, ReconcileStalled)
+
+	if isTemplate(mcpServer) {
+		// Templates are only copied into the instances of MCPServerClaims. Resources created before the MCPServer
+		// was labeled as a template are left alone.
+		meta.SetStatusCondition(&mcpServer.Status.Conditions, templateCondition())
+		meta.SetStatusCondition(&mcpServer.Status.Conditions, getReadyCondition(mcpServer))
+		observeGeneration(mcpServer)
+		if err = r.patchStatus(ctx, mcpServer, originalStatus); err != nil {
+			logger.Error(err, "unable to update MCPServer status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: max(expiresAfter(mcpServer, time.Now()), 0)}, nil
+	}
 
-	// Calls the reconcileMCPServerDeployment function, passing through the context, client and the mcpServer object
-	err = r.reconcileMCPServerDeployment(ctx, r.Client, mcpServer)
+	defaults, err := r.getDefaults(ctx, mcpServer.Namespace)
 	if err != nil {
-		logger.Error(err, "Failed to reconcile MCPServer Deployment")
+		logger.Error(err, "Failed to get MCPServerDefaults")
 		return ctrl.Result{}, err
 	}
+	if condition := r.getValidationCondition(mcpServer, defaults); condition != nil {
+		// The resources of the MCPServer are left alone until its type is allowed again and its config, volume
+		// mounts, args and owner are valid.
+		previous := meta.FindStatusCondition(originalStatus.Conditions, OverallAvailable)
+		if r.Recorder != nil && (previous == nil || previous.Reason != condition.Reason) {
+			r.Recorder.Event(mcpServer, corev1.EventTypeWarning, condition.Reason, condition.Message)
+		}
+		meta.SetStatusCondition(&mcpServer.Status.Conditions, *condition)
+		meta.SetStatusCondition(&mcpServer.Status.Conditions, getReadyCondition(mcpServer))
+		observeGeneration(mcpServer)
+		if err = r.patchStatus(ctx, mcpServer, originalStatus); err != nil {
+			logger.Error(err, "unable to update MCPServer status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: max(expiresAfter(mcpServer, time.Now()), 0)}, nil
+	}
+	applyDefaults(mcpServer, defaults)
 
-	// Calls the reconcileMCPServerService function, passes through context, client and mcpserver object
-	err = r.reconcileMCPServerService(ctx, r.Client, mcpServer)
+	hold, err := r.getHoldCondition(ctx, mcpServer)
 	if err != nil {
-		logger.Error(err, "Failed to reconcile MCPServer Service")
 		return ctrl.Result{}, err
 	}
+	if hold != nil {
+		return r.holdWorkload(ctx, mcpServer, originalStatus, *hold)
+	}
+
+	// In dry-run mode all writes to the managed resources go through a client that only records them.
+	cli := r.Client
+	var drift *driftClient
+	if r.DryRun {
+		drift = newDriftClient(r.Client)
+		cli = drift
+	}
 
-	err = r.reconcileMCPServerRoute(ctx, r.Client, mcpServer)
+	if isExternal(mcpServer) {
+		err = r.reconcileExternal(ctx, cli, mcpServer)
+		if err != nil {
+			logger.Error(err, "Failed to reconcile external MCPServer")
+			return ctrl.Result{}, err
+		}
+	} else {
+		err = r.reconcileWorkload(ctx, cli, mcpServer, originalStatus)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	meta.SetStatusCondition(&mcpServer.Status.Conditions, r.getEndpointCondition(ctx, cli, mcpServer))
+
+	mcpServer.Status.Platform = r.platformName()
+	mcpServer.Status.Components = r.components(mcpServer)
+	mcpServer.Status.DisplayName = DisplayName(mcpServer)
+	mcpServer.Status.Description = Description(mcpServer)
+	mcpServer.Status.Endpoints, err = r.getEndpoints(ctx, cli, mcpServer)
+	mcpServer.Status.URL = endpointURL(mcpServer.Status.Endpoints)
 	if err != nil {
-		logger.Error(err, "Failed to reconcile MCPServer Route")
+		logger.Error(err, "Failed to determine MCPServer URL")
+		return ctrl.Result{}, err
+	}
+	mcpServer.Status.RESTBridgeURL, err = r.getRESTBridgeURL(ctx, cli, mcpServer)
+	if err != nil {
+		logger.Error(err, "Failed to determine the URL of the MCPServer REST bridge")
 		return ctrl.Result{}, err
 	}
-
-	meta.SetStatusCondition(&mcpServer.Status.Conditions, r.getDeploymentCondition(ctx, r.Client, mcpServer))
-	meta.SetStatusCondition(&mcpServer.Status.Conditions, r.getServiceCondition(ctx, r.Client, mcpServer))
-	meta.SetStatusCondition(&mcpServer.Status.Conditions, r.getRouteCondition(ctx, r.Client, mcpServer))
 
 	overallReady := r.getOverallCondition(mcpServer)
 	meta.SetStatusCondition(&mcpServer.Status.Conditions, overallReady)
 
+	err = r.reconcileTools(ctx, cli, mcpServer)
+	if err != nil {
+		logger.Error(err, "Failed to reconcile MCPServer tools")
+		return ctrl.Result{}, err
+	}
+	err = r.reconcileUsage(ctx, cli, mcpServer)
+	if err != nil {
+		logger.Error(err, "Failed to reconcile MCPServer usage")
+		return ctrl.Result{}, err
+	}
+
+	if err = r.reconcileChecks(ctx, cli, mcpServer, drift, originalStatus); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	meta.SetStatusCondition(&mcpServer.Status.Conditions, getReadyCondition(mcpServer))
+	observeGeneration(mcpServer)
+
 	if !reflect.DeepEqual(originalStatus, &mcpServer.Status) {
 		logger.Info("Status has changed, attempting to update")
-		if err = r.Status().Update(ctx, mcpServer); err != nil {
+		if err = r.patchStatus(ctx, mcpServer, originalStatus); err != nil {
 			logger.Error(err, "unable to update MCPServer status")
 			return ctrl.Result{}, err
 		}
@@ -119,14 +308,305 @@ func (r *MCPServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	}
 
 	if overallReady.Status != metav1.ConditionTrue {
-		logger.Info("MCPServer not yet fully ready, re-queuing...", "reason", overallReady.Reason, "message", overallReady.Message)
-		return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+		logger.Info("MCPServer not yet fully ready", "reason", overallReady.Reason, "message", overallReady.Message)
+	} else {
+		logger.Info("MCPServer is fully ready", "name", mcpServer.Name, "namespace", mcpServer.Namespace)
+	}
+	return ctrl.Result{RequeueAfter: r.nextReconcile(mcpServer, overallReady)}, nil
+}
+
+// reconcileChecks runs the connection test and conformance check of cr, or reports the changes drift recorded
+// when it is not nil in dry-run mode.
+func (r *MCPServerReconciler) reconcileChecks(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer,
+	drift *driftClient, originalStatus *mcpserverv1.MCPServerStatus) error {
+	logger := logf.FromContext(ctx)
+
+	if drift == nil {
+		err := r.reconcileMCPServerConnectionTest(ctx, cli, cr)
+		if err != nil {
+			logger.Error(err, "Failed to reconcile MCPServer connection test")
+			return err
+		}
+		err = r.reconcileConformanceCheck(ctx, cli, cr)
+		if err != nil {
+			logger.Error(err, "Failed to reconcile MCPServer conformance check")
+			return err
+		}
+		meta.RemoveStatusCondition(&cr.Status.Conditions, DriftDetected)
+	} else {
+		// Connection tests and conformance checks are skipped, a test Job would change the cluster just like any other resource.
+		driftCondition := drift.getDriftCondition(cr)
+		previous := meta.FindStatusCondition(originalStatus.Conditions, DriftDetected)
+		if driftCondition.Status == metav1.ConditionTrue && r.Recorder != nil &&
+			(previous == nil || previous.Message != driftCondition.Message) {
+			r.Recorder.Event(cr, corev1.EventTypeNormal, ReasonDriftDetected, driftCondition.Message)
+		}
+		meta.SetStatusCondition(&cr.Status.Conditions, driftCondition)
+	}
+	return nil
+}
+
+// getValidationCondition returns the condition that keeps the resources of cr from being created or updated
+// because its type is not allowed or its config, volume mounts, args or owner are invalid, or nil when there is
+// none.
+func (r *MCPServerReconciler) getValidationCondition(cr *mcpserverv1.MCPServer, defaults *mcpserverv1.MCPServerDefaults) *metav1.Condition {
+	condition := getTypeAllowedCondition(cr, defaults)
+	if condition == nil {
+		condition = getConfigCondition(cr)
+	}
+	if condition == nil {
+		condition = getVolumeMountsCondition(cr)
+	}
+	if condition == nil {
+		condition = r.getArgsCondition(cr)
+	}
+	if condition == nil {
+		condition = r.getOwnerCondition(cr)
+	}
+	return condition
+}
+
+// getHoldCondition sets the conditions of the checks that hold the workload of cr: its dependencies, the
+// prerequisites of its namespace, and the existence and digest of its image. It returns the first of them that is
+// True, for holdWorkload, or nil when the workload can be rolled out.
+func (r *MCPServerReconciler) getHoldCondition(ctx context.Context, cr *mcpserverv1.MCPServer) (*metav1.Condition, error) {
+	logger := logf.FromContext(ctx)
+
+	if len(cr.Spec.DependsOn) == 0 {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, DependenciesNotReady)
+	} else {
+		dependencies, err := r.getDependenciesCondition(ctx, cr)
+		if err != nil {
+			logger.Error(err, "Failed to check the dependencies of the MCPServer")
+			return nil, err
+		}
+		meta.SetStatusCondition(&cr.Status.Conditions, dependencies)
+		if dependencies.Status == metav1.ConditionTrue {
+			// The workload is not rolled out until its dependencies are ready. MCPServers are watched, other
+			// objects are checked again at the probe interval.
+			return &dependencies, nil
+		}
+	}
+
+	if isExternal(cr) {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, PrereqFailed)
+	} else {
+		prereq, err := r.getPrereqCondition(ctx, cr)
+		if err != nil {
+			logger.Error(err, "Failed to check the prerequisites of the MCPServer")
+			return nil, err
+		}
+		meta.SetStatusCondition(&cr.Status.Conditions, prereq)
+		if prereq.Status == metav1.ConditionTrue {
+			// The workload is not created until the namespace meets the prerequisites of its pods. Secrets and
+			// quotas are not watched, so they are checked again at the probe interval.
+			return &prereq, nil
+		}
 	}
 
-	logger.Info("MCPServer is fully ready", "name", mcpServer.Name, "namespace", mcpServer.Namespace)
-	return ctrl.Result{}, nil
+	if !cr.Spec.VerifyImage || isExternal(cr) || isProxy(cr) {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, ImageNotFound)
+	} else {
+		image, err := r.getImageCondition(ctx, cr)
+		if err != nil {
+			logger.Error(err, "Failed to verify the image of the MCPServer")
+			return nil, err
+		}
+		meta.SetStatusCondition(&cr.Status.Conditions, image)
+		if image.Status == metav1.ConditionTrue {
+			// The Deployment is not updated to an image that does not exist, which is looked for again at the
+			// probe interval, as registries are not watched.
+			return &image, nil
+		}
+	}
+
+	if !cr.Spec.PinImageDigest || isExternal(cr) || isProxy(cr) {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, ImageDigestFailed)
+		cr.Status.ImageDigest = nil
+	} else {
+		digest, err := r.getImageDigestCondition(ctx, cr)
+		if err != nil {
+			logger.Error(err, "Failed to resolve the image digest of the MCPServer")
+			return nil, err
+		}
+		meta.SetStatusCondition(&cr.Status.Conditions, digest)
+		if digest.Status == metav1.ConditionTrue {
+			// The Deployment is not updated to an image that is not pinned, whose digest is resolved again at
+			// the probe interval.
+			return &digest, nil
+		}
+	}
+	return nil, nil
+}
+
+// holdWorkload reports condition, a True condition that keeps the workload of cr from being created or updated,
+// in the Available condition of cr and in a Warning event when its message changed, and requeues cr to check the
+// condition again at the probe interval.
+func (r *MCPServerReconciler) holdWorkload(ctx context.Context, cr *mcpserverv1.MCPServer,
+	originalStatus *mcpserverv1.MCPServerStatus, condition metav1.Condition) (ctrl.Result, error) {
+	previous := meta.FindStatusCondition(originalStatus.Conditions, condition.Type)
+	if r.Recorder != nil && (previous == nil || previous.Message != condition.Message) {
+		r.Recorder.Event(cr, corev1.EventTypeWarning, condition.Type, condition.Message)
+	}
+	meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+		Type:    OverallAvailable,
+		Status:  metav1.ConditionFalse,
+		Reason:  condition.Type,
+		Message: condition.Message,
+	})
+	meta.SetStatusCondition(&cr.Status.Conditions, getReadyCondition(cr))
+	observeGeneration(cr)
+	if err := r.patchStatus(ctx, cr, originalStatus); err != nil {
+		logf.FromContext(ctx).Error(err, "unable to update MCPServer status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: r.requeueInterval(cr)}, nil
 }
 
+// reconcileWorkload creates the Deployment, Service and Route of a Managed MCP server and reports their state
+// in the status of cr.
+func (r *MCPServerReconciler) reconcileWorkload(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer, originalStatus *mcpserverv1.MCPServerStatus) error {
+	logger := logf.FromContext(ctx)
+
+	// The token is generated before the Deployment, whose pods mount it.
+	err := r.reconcileAuthToken(ctx, cli, cr)
+	if err != nil {
+		logger.Error(err, "Failed to reconcile MCPServer auth token")
+		return err
+	}
+	// The service account is created before the Deployment, whose pods run with it.
+	if err := r.reconcileServiceAccount(ctx, cli, cr); err != nil {
+		logger.Error(err, "Failed to reconcile MCPServer service account")
+		return err
+	}
+
+	// The children are independent of each other and reconciled concurrently, so that a slow API call for one
+	// does not hold up the others. All errors are returned, rather than the first.
+	children := []struct {
+		name      string
+		reconcile func(context.Context, client.Client, *mcpserverv1.MCPServer) error
+	}{
+		{"Deployment", r.reconcileMCPServerDeployment},
+		{"HorizontalPodAutoscaler", r.reconcileAutoscaler},
+		{"Service", r.reconcileMCPServerService},
+		{"Route", r.reconcileExposure},
+		{"exposures", r.reconcileExposures},
+		{"NetworkPolicy", r.reconcileNetworkPolicy},
+		{"session store", r.reconcileSessionStore},
+		{"metrics exporter", r.reconcileMetricsExporter},
+		{"REST bridge", r.reconcileRESTBridge},
+	}
+	errs := make([]error, len(children))
+	var g errgroup.Group
+	for i, child := range children {
+		g.Go(func() error {
+			if err := child.reconcile(ctx, cli, cr); err != nil {
+				logger.Error(err, "Failed to reconcile MCPServer "+child.name)
+				errs[i] = fmt.Errorf("unable to reconcile the %s of %s: %w", child.name, cr.Name, err)
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+	if err = errors.Join(errs...); err != nil {
+		return err
+	}
+
+	deploymentCondition := r.getDeploymentCondition(ctx, cli, cr)
+	if (deploymentCondition.Reason == ReasonImagePullFailed || deploymentCondition.Reason == ReasonSCCDenied) &&
+		r.Recorder != nil {
+		previous := meta.FindStatusCondition(originalStatus.Conditions, DeploymentAvailable)
+		if previous == nil || previous.Message != deploymentCondition.Message {
+			r.Recorder.Event(cr, corev1.EventTypeWarning, deploymentCondition.Reason, deploymentCondition.Message)
+		}
+	}
+	meta.SetStatusCondition(&cr.Status.Conditions, deploymentCondition)
+
+	degradedCondition := r.getDegradedCondition(ctx, cli, cr)
+	if degradedCondition.Status == metav1.ConditionTrue && r.Recorder != nil {
+		previous := meta.FindStatusCondition(originalStatus.Conditions, Degraded)
+		if previous == nil || previous.Status != metav1.ConditionTrue || previous.Reason != degradedCondition.Reason {
+			r.Recorder.Event(cr, corev1.EventTypeWarning, degradedCondition.Reason, degradedCondition.Message)
+		}
+	}
+	meta.SetStatusCondition(&cr.Status.Conditions, degradedCondition)
+	meta.SetStatusCondition(&cr.Status.Conditions, r.getProgressingCondition(ctx, cli, cr))
+	if cr.Spec.Guardrails != nil {
+		meta.SetStatusCondition(&cr.Status.Conditions, r.getGuardrailsCondition(ctx, cli, cr))
+	} else {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, GuardrailsAvailable)
+	}
+	if len(disabledCapabilities(cr)) > 0 {
+		meta.SetStatusCondition(&cr.Status.Conditions, r.getCapabilityFilterCondition(ctx, cli, cr))
+	} else {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, CapabilityFilterAvailable)
+	}
+	if cr.Spec.MetricsExporter != nil {
+		meta.SetStatusCondition(&cr.Status.Conditions, r.getMetricsExporterCondition(ctx, cli, cr))
+	} else {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, MetricsExporterAvailable)
+	}
+	if usesRESTBridge(cr) {
+		meta.SetStatusCondition(&cr.Status.Conditions, r.getRESTBridgeCondition(ctx, cli, cr))
+	} else {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, RESTBridgeAvailable)
+	}
+	if usesTokenAuth(cr) {
+		meta.SetStatusCondition(&cr.Status.Conditions, r.getTokenAuthCondition(ctx, cli, cr))
+	} else {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, TokenAuthAvailable)
+	}
+	if cr.Spec.RateLimit != nil && cr.Spec.RateLimit.Local != nil {
+		meta.SetStatusCondition(&cr.Status.Conditions, r.getRateLimiterCondition(ctx, cli, cr))
+	} else {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, RateLimiterAvailable)
+	}
+	meta.SetStatusCondition(&cr.Status.Conditions, r.getServiceCondition(ctx, cli, cr))
+	if r.usesRoute(cr) {
+		meta.SetStatusCondition(&cr.Status.Conditions, r.getRouteCondition(ctx, cli, cr))
+	} else {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, RouteAvailable)
+	}
+	if usesGateway(cr) {
+		meta.SetStatusCondition(&cr.Status.Conditions, r.getHTTPRouteCondition(ctx, cli, cr))
+	} else {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, HTTPRouteAccepted)
+	}
+	if usesMeshGateway(cr) {
+		meta.SetStatusCondition(&cr.Status.Conditions, r.getVirtualServiceCondition(ctx, cli, cr))
+	} else {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, VirtualServiceAvailable)
+	}
+	r.setExposureConditions(ctx, cli, cr)
+
+	cr.Status.PodSummary, err = r.getPodSummary(ctx, cli, cr)
+	if err != nil {
+		logger.Error(err, "Failed to summarize MCPServer pods")
+		return err
+	}
+	cr.Status.Replicas, cr.Status.ReadyReplicas, err = r.getDeploymentReplicas(ctx, cli, cr)
+	if err != nil {
+		logger.Error(err, "Failed to get MCPServer Deployment replicas")
+		return err
+	}
+	cr.Status.Autoscaler, err = r.getAutoscalerStatus(ctx, cli, cr)
+	if err != nil {
+		logger.Error(err, "Failed to get MCPServer autoscaler")
+		return err
+	}
+	if err = r.recordRevision(ctx, cli, cr, metav1.Now()); err != nil {
+		logger.Error(err, "Failed to record MCPServer revision")
+		return err
+	}
+	return nil
+}
+
+// mcpServerChangedPredicate admits the events of an MCPServer except updates of its status only, which the reconciler
+// writes itself: reconciling them again would retry a stalled MCPServer without its cool-down. Changes of the labels
+// and annotations, such as the refresh-tools annotation, are reconciled like changes of the spec.
+var mcpServerChangedPredicate = predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{},
+	predicate.AnnotationChangedPredicate{})
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *MCPServerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	// Create a predicate to filter resources with the "opendatahub.io/mcp-server" label
@@ -145,19 +625,142 @@ func (r *MCPServerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		},
 	}
 
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&mcpserverv1.MCPServer{}).
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&mcpserverv1.MCPServer{}, builder.WithPredicates(mcpServerChangedPredicate)).
 		Watches(&appsv1.Deployment{},
 			handler.EnqueueRequestsFromMapFunc(r.mapResourceToMCPServer),
 			builder.WithPredicates(labelPredicate)).
 		Watches(&corev1.Service{},
 			handler.EnqueueRequestsFromMapFunc(r.mapResourceToMCPServer),
 			builder.WithPredicates(labelPredicate)).
-		Watches(&routev1.Route{},
+		Watches(&batchv1.Job{},
 			handler.EnqueueRequestsFromMapFunc(r.mapResourceToMCPServer),
 			builder.WithPredicates(labelPredicate)).
-		Named("mcpserver").
-		Complete(r)
+		Watches(&mcpserverv1.MCPServer{},
+			handler.EnqueueRequestsFromMapFunc(r.mapMCPServerToDependents)).
+		Watches(&mcpserverv1.MCPServerDefaults{},
+			handler.EnqueueRequestsFromMapFunc(r.mapDefaultsToMCPServers)).
+		Watches(&networkingv1.NetworkPolicy{},
+			handler.EnqueueRequestsFromMapFunc(r.mapResourceToMCPServer),
+			builder.WithPredicates(labelPredicate)).
+		Watches(&networkingv1.Ingress{},
+			handler.EnqueueRequestsFromMapFunc(r.mapResourceToMCPServer),
+			builder.WithPredicates(labelPredicate)).
+		Watches(&autoscalingv2.HorizontalPodAutoscaler{},
+			handler.EnqueueRequestsFromMapFunc(r.mapAutoscalerToMCPServer)).
+		Watches(&corev1.Pod{},
+			handler.EnqueueRequestsFromMapFunc(r.mapPodToMCPServer),
+			builder.WithPredicates(labelPredicate)).
+		Named("mcpserver")
+
+	// Watching Routes on a cluster without the Route API would keep the controller from starting.
+	if r.routeAPIAvailable() {
+		b = b.Watches(&routev1.Route{},
+			handler.EnqueueRequestsFromMapFunc(r.mapResourceToMCPServer),
+			builder.WithPredicates(labelPredicate))
+		// Only the metadata of Secrets is cached, a change of their resourceVersion is enough to sync a renewed
+		// Route certificate, which is then read from the API server.
+		b = b.WatchesMetadata(&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.mapSecretToMCPServers))
+	}
+	if r.gatewayAPIAvailable() {
+		b = b.Watches(&gatewayv1.HTTPRoute{},
+			handler.EnqueueRequestsFromMapFunc(r.mapResourceToMCPServer),
+			builder.WithPredicates(labelPredicate))
+	}
+
+	return b.Complete(r)
+}
+
+// CacheByObject returns the cache options of the objects the MCPServerReconciler only watches for the MCP servers
+// it manages: only the pods and HorizontalPodAutoscalers that carry the MCP server label are cached, rather than all
+// of those in the cluster.
+func CacheByObject() (map[client.Object]cache.ByObject, error) {
+	managed, err := labels.NewRequirement(mcpServerAppLabelKey, selection.Exists, nil)
+	if err != nil {
+		return nil, err
+	}
+	selector := labels.NewSelector().Add(*managed)
+	return map[client.Object]cache.ByObject{
+		&corev1.Pod{}:                            {Label: selector},
+		&autoscalingv2.HorizontalPodAutoscaler{}: {Label: selector},
+	}, nil
+}
+
+// uncachedReader returns the reader of the objects the cache of the manager does not hold.
+func (r *MCPServerReconciler) uncachedReader(cli client.Client) client.Reader {
+	if r.APIReader == nil {
+		return cli
+	}
+	return r.APIReader
+}
+
+// routeAPIAvailable reports whether Routes can be created on the cluster.
+func (r *MCPServerReconciler) routeAPIAvailable() bool {
+	return r.Platform == nil || r.Platform.HasAPI(gvk.Route)
+}
+
+// usesRoute reports whether the MCP server is exposed through a Route of its own.
+func (r *MCPServerReconciler) usesRoute(cr *mcpserverv1.MCPServer) bool {
+	return r.routeAPIAvailable() && !usesGateway(cr) && !usesMeshGateway(cr)
+}
+
+// nextReconcile returns how long to wait before reconciling the MCPServer again when no watch event arrives
+// first. Deployments, Services, Routes and pods are watched, so their readiness transitions trigger a reconcile
+// on their own. Only the endpoint probe, the tool refreshes, the usage scrapes and the expiry of the TTLs are not
+// backed by a watch and have to be run on a timer.
+func (r *MCPServerReconciler) nextReconcile(cr *mcpserverv1.MCPServer, overall metav1.Condition) time.Duration {
+	if overall.Status != metav1.ConditionTrue && overall.Reason == ReasonEndpointUnreachable {
+		return r.requeueInterval(cr)
+	}
+	now := time.Now()
+	next := resyncInterval
+	for _, after := range []time.Duration{toolsRefreshAfter(cr, now), usageScrapeAfter(cr, now), expiresAfter(cr, now)} {
+		if after >= 0 && after < next {
+			// An overdue refresh or scrape, which failed, is retried at the probe interval rather than immediately.
+			next = max(after, r.requeueInterval(cr))
+		}
+	}
+	return next
+}
+
+// requeueInterval returns how long to wait before probing an unreachable MCPServer endpoint again.
+func (r *MCPServerReconciler) requeueInterval(cr *mcpserverv1.MCPServer) time.Duration {
+	if cr.Spec.RequeueInterval != nil && cr.Spec.RequeueInterval.Duration > 0 {
+		return cr.Spec.RequeueInterval.Duration
+	}
+	if r.RequeueInterval > 0 {
+		return r.RequeueInterval
+	}
+	return DefaultRequeueInterval
+}
+
+// patchStatus writes the status of cr when it differs from originalStatus, the status cr was read with. The status
+// is sent as a merge patch without a resourceVersion, so that it does not fail with a conflict when cr was changed
+// since it was read, e.g. by a reconcile triggered in quick succession, which computes the same status anyway. An
+// MCPServer deleted in the meantime is ignored. A change of the Available condition is recorded in the availability
+// history of the status first, and the time it took cr to become available is observed once the status is written.
+func (r *MCPServerReconciler) patchStatus(ctx context.Context, cr *mcpserverv1.MCPServer, originalStatus *mcpserverv1.MCPServerStatus) error {
+	recordAvailability(originalStatus, &cr.Status, time.Now())
+	if reflect.DeepEqual(originalStatus, &cr.Status) {
+		return nil
+	}
+	// The patch is computed from the statuses only, as defaults applied to the spec in memory must not be sent.
+	original := &mcpserverv1.MCPServer{ObjectMeta: *cr.ObjectMeta.DeepCopy(), Status: *originalStatus.DeepCopy()}
+	patched := &mcpserverv1.MCPServer{ObjectMeta: *cr.ObjectMeta.DeepCopy(), Status: *cr.Status.DeepCopy()}
+	if err := r.Status().Patch(ctx, patched, client.MergeFrom(original)); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	observeTimeToReady(cr, originalStatus)
+	return nil
+}
+
+// platformName returns the name of the platform the operator runs on.
+func (r *MCPServerReconciler) platformName() string {
+	if r.Platform == nil {
+		return cluster.OpenShift
+	}
+	return r.Platform.Name
 }
 
 // mapResourceToMCPServer maps a watched resource to the MCPServer that owns it
@@ -187,3 +790,13 @@ func (r *MCPServerReconciler) mapResourceToMCPServer(ctx context.Context, obj cl
 		},
 	}
 }
+
+// mapPodToMCPServer maps an MCP server pod to its MCPServer. Pods are owned by a ReplicaSet rather than
+// the MCPServer, so the MCPServer is found by the label the Deployment sets on its pods.
+func (r *MCPServerReconciler) mapPodToMCPServer(ctx context.Context, obj client.Object) []reconcile.Request {
+	name := obj.GetLabels()[mcpServerAppLabelKey]
+	if name == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: r.mcpServerKey(ctx, obj.GetNamespace(), name)}}
+}
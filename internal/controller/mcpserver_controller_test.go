@@ -18,16 +18,31 @@ package controller
 
 import (
 	"context"
+	"strings"
+	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster"
 )
 
 var _ = Describe("MCPServer Controller", func() {
@@ -84,3 +99,211 @@ var _ = Describe("MCPServer Controller", func() {
 		})
 	})
 })
+
+func TestMCPServerReconciler_requeueInterval(t *testing.T) {
+	tests := []struct {
+		name            string
+		requeueInterval time.Duration
+		spec            *metav1.Duration
+		want            time.Duration
+	}{
+		{
+			name: "defaults to DefaultRequeueInterval",
+			want: DefaultRequeueInterval,
+		},
+		{
+			name:            "operator-wide interval",
+			requeueInterval: time.Minute,
+			want:            time.Minute,
+		},
+		{
+			name:            "spec overrides the operator-wide interval",
+			requeueInterval: time.Minute,
+			spec:            &metav1.Duration{Duration: 5 * time.Second},
+			want:            5 * time.Second,
+		},
+		{
+			name:            "zero spec interval is ignored",
+			requeueInterval: time.Minute,
+			spec:            &metav1.Duration{},
+			want:            time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &MCPServerReconciler{RequeueInterval: tt.requeueInterval}
+			cr := &mcpserverv1.MCPServer{Spec: mcpserverv1.MCPServerSpec{RequeueInterval: tt.spec}}
+			if got := r.requeueInterval(cr); got != tt.want {
+				t.Errorf("requeueInterval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_nextReconcile(t *testing.T) {
+	tests := []struct {
+		name    string
+		overall metav1.Condition
+		want    time.Duration
+	}{
+		{
+			name:    "ready",
+			overall: metav1.Condition{Status: metav1.ConditionTrue, Reason: "AllComponentsReady"},
+			want:    resyncInterval,
+		},
+		{
+			name:    "waiting for the deployment",
+			overall: metav1.Condition{Status: metav1.ConditionFalse, Reason: "DeploymentNotReady"},
+			want:    resyncInterval,
+		},
+		{
+			name:    "endpoint unreachable",
+			overall: metav1.Condition{Status: metav1.ConditionFalse, Reason: ReasonEndpointUnreachable},
+			want:    DefaultRequeueInterval,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &MCPServerReconciler{}
+			if got := r.nextReconcile(&mcpserverv1.MCPServer{}, tt.overall); got != tt.want {
+				t.Errorf("nextReconcile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_reconcileWorkload(t *testing.T) {
+	fakeScheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(fakeScheme); err != nil {
+		t.Errorf("failed to add client-go scheme: %v", err)
+	}
+	if err := mcpserverv1.AddToScheme(fakeScheme); err != nil {
+		t.Errorf("failed to add mcpserverv1 scheme: %v", err)
+	}
+	// The fake client fails to create the Deployment and the Service, the other children are still reconciled.
+	failCreate := interceptor.Funcs{
+		Create: func(ctx context.Context, cli client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			switch obj.(type) {
+			case *appsv1.Deployment, *corev1.Service:
+				return errors.NewServiceUnavailable("creates are failing")
+			}
+			return cli.Create(ctx, obj, opts...)
+		},
+	}
+	cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithInterceptorFuncs(failCreate).Build()
+	r := &MCPServerReconciler{
+		Client:   cli,
+		Scheme:   fakeScheme,
+		Platform: &cluster.Platform{Name: cluster.Kubernetes},
+	}
+	cr := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+		Spec: mcpserverv1.MCPServerSpec{
+			Image:                   mcpServerImage,
+			AllowedClientNamespaces: &metav1.LabelSelector{},
+		},
+	}
+
+	err := r.reconcileWorkload(context.Background(), cli, cr, cr.Status.DeepCopy())
+	if err == nil {
+		t.Fatal("reconcileWorkload() error = nil, want the errors of the Deployment and the Service")
+	}
+	for _, want := range []string{"Deployment of " + mcpServerName, "Service of " + mcpServerName} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("reconcileWorkload() error = %v, want it to contain the error of the %s", err, want)
+		}
+	}
+	if !errors.IsServiceUnavailable(err) {
+		t.Errorf("reconcileWorkload() error = %v, want it to wrap the API errors", err)
+	}
+
+	policy := &networkingv1.NetworkPolicy{}
+	if err := cli.Get(context.Background(), client.ObjectKeyFromObject(cr), policy); err != nil {
+		t.Errorf("failed to get the NetworkPolicy reconciled alongside the failing children: %v", err)
+	}
+}
+
+func TestMCPServerReconciler_patchStatus(t *testing.T) {
+	fakeScheme := runtime.NewScheme()
+	if err := mcpserverv1.AddToScheme(fakeScheme); err != nil {
+		t.Errorf("failed to add mcpserverv1 scheme: %v", err)
+	}
+	stored := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+		Spec:       mcpserverv1.MCPServerSpec{Image: mcpServerImage},
+		Status:     mcpserverv1.MCPServerStatus{URL: "http://old"},
+	}
+	cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(stored).
+		WithStatusSubresource(&mcpserverv1.MCPServer{}).Build()
+	r := &MCPServerReconciler{Client: cli, Scheme: fakeScheme}
+
+	cr := &mcpserverv1.MCPServer{}
+	if err := cli.Get(context.Background(), client.ObjectKeyFromObject(stored), cr); err != nil {
+		t.Fatalf("failed to get the MCPServer: %v", err)
+	}
+	originalStatus := cr.Status.DeepCopy()
+
+	// The MCPServer changes after it was read, which makes an update of the stale copy conflict.
+	changed := cr.DeepCopy()
+	changed.Labels = map[string]string{"changed": "true"}
+	if err := cli.Update(context.Background(), changed); err != nil {
+		t.Fatalf("failed to change the MCPServer: %v", err)
+	}
+
+	cr.Spec.Replicas = ptr.To[int32](3)
+	cr.Status.URL = "http://new"
+	cr.Status.Platform = cluster.Kubernetes
+	if err := r.Status().Update(context.Background(), cr.DeepCopy()); !errors.IsConflict(err) {
+		t.Fatalf("Status().Update() error = %v, want a conflict", err)
+	}
+	if err := r.patchStatus(context.Background(), cr, originalStatus); err != nil {
+		t.Fatalf("patchStatus() error = %v", err)
+	}
+
+	got := &mcpserverv1.MCPServer{}
+	if err := cli.Get(context.Background(), client.ObjectKeyFromObject(stored), got); err != nil {
+		t.Fatalf("failed to get the MCPServer: %v", err)
+	}
+	if got.Status.URL != "http://new" || got.Status.Platform != cluster.Kubernetes {
+		t.Errorf("status = %+v, want the patched status", got.Status)
+	}
+	if got.Spec.Replicas != nil || got.Labels["changed"] != "true" {
+		t.Errorf("MCPServer = %+v, want only its status patched", got)
+	}
+
+	if err := cli.Delete(context.Background(), got); err != nil {
+		t.Fatalf("failed to delete the MCPServer: %v", err)
+	}
+	cr.Status.URL = "http://newer"
+	if err := r.patchStatus(context.Background(), cr, originalStatus); err != nil {
+		t.Errorf("patchStatus() of a deleted MCPServer error = %v, want nil", err)
+	}
+}
+
+func TestCacheByObject(t *testing.T) {
+	byObject, err := CacheByObject()
+	if err != nil {
+		t.Fatalf("CacheByObject() error = %v", err)
+	}
+	selectors := map[string]labels.Selector{}
+	for obj, options := range byObject {
+		switch obj.(type) {
+		case *corev1.Pod:
+			selectors["pods"] = options.Label
+		case *autoscalingv2.HorizontalPodAutoscaler:
+			selectors["autoscalers"] = options.Label
+		}
+	}
+	for _, kind := range []string{"pods", "autoscalers"} {
+		selector := selectors[kind]
+		if selector == nil {
+			t.Fatalf("CacheByObject() = %v, want a label selector for %s", byObject, kind)
+		}
+		if !selector.Matches(labels.Set{mcpServerAppLabelKey: mcpServerName}) {
+			t.Errorf("selector %s does not match the %s of an MCP server", selector, kind)
+		}
+		if selector.Matches(labels.Set{"app": "other"}) {
+			t.Errorf("selector %s matches the %s of other workloads", selector, kind)
+		}
+	}
+}
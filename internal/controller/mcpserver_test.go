@@ -5,14 +5,19 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster"
 	routev1 "github.com/openshift/api/route/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -76,10 +81,32 @@ func TestMCPServerReconciler_reconcileMCPServerDeployment(t *testing.T) {
 			Args:    CustomMCPDeploymentArgs,
 		},
 	}
+	apiKey := corev1.EnvVar{
+		Name: "API_KEY",
+		ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "github"},
+			Key:                  "token",
+		}},
+	}
+	envFrom := []corev1.EnvFromSource{{
+		ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "settings"}},
+	}}
+	mcpServerWithEnv := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mcpServerName,
+			Namespace: testNamespace,
+		},
+		Spec: mcpserverv1.MCPServerSpec{
+			Image:   mcpServerImage,
+			Env:     []corev1.EnvVar{{Name: "NO_PROXY", Value: ".svc,.corp"}, apiKey},
+			EnvFrom: envFrom,
+		},
+	}
 
 	type fields struct {
-		Client client.Client
-		Scheme *runtime.Scheme
+		Client   client.Client
+		Scheme   *runtime.Scheme
+		Platform *cluster.Platform
 	}
 	type args struct {
 		ctx context.Context
@@ -93,6 +120,8 @@ func TestMCPServerReconciler_reconcileMCPServerDeployment(t *testing.T) {
 		wantErr     bool
 		wantCommand []string
 		wantArgs    []string
+		wantEnv     []corev1.EnvVar
+		wantEnvFrom []corev1.EnvFromSource
 	}{
 		{
 			name: "Verify MCPServer Deployment can be created with default values",
@@ -121,6 +150,9 @@ func TestMCPServerReconciler_reconcileMCPServerDeployment(t *testing.T) {
 				cr:  mcpServer,
 			},
 			wantErr: false,
+			// The command and args of the existing Deployment are brought in line with the MCPServer.
+			wantCommand: DefaultMCPDeploymentCommand,
+			wantArgs:    DefaultMCPDeploymentArgs,
 		},
 		{
 			name: "Verify Deployment is created with custom command and args",
@@ -137,12 +169,63 @@ func TestMCPServerReconciler_reconcileMCPServerDeployment(t *testing.T) {
 			wantCommand: CustomMCPDeploymentCommand, // Expect the custom value
 			wantArgs:    CustomMCPDeploymentArgs,    // Expect the custom value
 		},
+		{
+			name: "Verify Deployment is created with the cluster-wide proxy settings",
+			fields: fields{
+				Client: fake.NewClientBuilder().Build(),
+				Scheme: fakeScheme,
+				Platform: &cluster.Platform{
+					Name:  cluster.OpenShift,
+					Proxy: &cluster.Proxy{HTTPSProxy: "http://proxy:3128", NoProxy: ".svc"},
+				},
+			},
+			args: args{
+				ctx: testContext,
+				cli: fake.NewClientBuilder().Build(),
+				cr:  mcpServer,
+			},
+			wantErr:     false,
+			wantCommand: DefaultMCPDeploymentCommand,
+			wantArgs:    DefaultMCPDeploymentArgs,
+			wantEnv: []corev1.EnvVar{
+				{Name: "HTTPS_PROXY", Value: "http://proxy:3128"},
+				{Name: "https_proxy", Value: "http://proxy:3128"},
+				{Name: "NO_PROXY", Value: ".svc"},
+				{Name: "no_proxy", Value: ".svc"},
+			},
+		},
+		{
+			name: "Verify Deployment is created with the env and envFrom of the MCPServer, which override the proxy settings",
+			fields: fields{
+				Client: fake.NewClientBuilder().Build(),
+				Scheme: fakeScheme,
+				Platform: &cluster.Platform{
+					Name:  cluster.OpenShift,
+					Proxy: &cluster.Proxy{NoProxy: ".svc"},
+				},
+			},
+			args: args{
+				ctx: testContext,
+				cli: fake.NewClientBuilder().Build(),
+				cr:  mcpServerWithEnv,
+			},
+			wantErr:     false,
+			wantCommand: DefaultMCPDeploymentCommand,
+			wantArgs:    DefaultMCPDeploymentArgs,
+			wantEnv: []corev1.EnvVar{
+				{Name: "NO_PROXY", Value: ".svc,.corp"},
+				{Name: "no_proxy", Value: ".svc"},
+				apiKey,
+			},
+			wantEnvFrom: envFrom,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := &MCPServerReconciler{
-				Client: tt.fields.Client,
-				Scheme: tt.fields.Scheme,
+				Client:   tt.fields.Client,
+				Scheme:   tt.fields.Scheme,
+				Platform: tt.fields.Platform,
 			}
 
 			err := r.reconcileMCPServerDeployment(context.Background(), tt.fields.Client, tt.args.cr)
@@ -170,6 +253,12 @@ func TestMCPServerReconciler_reconcileMCPServerDeployment(t *testing.T) {
 			if !reflect.DeepEqual(container.Args, tt.wantArgs) {
 				t.Errorf("Args mismatch: got %v, want %v", container.Args, tt.wantArgs)
 			}
+			if !reflect.DeepEqual(container.Env, tt.wantEnv) {
+				t.Errorf("Env mismatch: got %v, want %v", container.Env, tt.wantEnv)
+			}
+			if !reflect.DeepEqual(container.EnvFrom, tt.wantEnvFrom) {
+				t.Errorf("EnvFrom mismatch: got %v, want %v", container.EnvFrom, tt.wantEnvFrom)
+			}
 		})
 	}
 }
@@ -349,6 +438,139 @@ func TestMCPServerReconciler_reconcileMCPServerRoute(t *testing.T) {
 	}
 }
 
+func TestMCPServerReconciler_reconcileRouteAnnotations(t *testing.T) {
+	fakeScheme := runtime.NewScheme()
+	if err := routev1.AddToScheme(fakeScheme); err != nil {
+		t.Errorf("failed to add routev1 scheme: %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		annotations     map[string]string
+		expose          *mcpserverv1.Expose
+		sse             *mcpserverv1.SSE
+		ignore          *mcpserverv1.IgnoreDifferences
+		wantAnnotations map[string]string
+	}{
+		{
+			name:        "Verify that a Route without allowed source ranges is left untouched",
+			annotations: map[string]string{"haproxy.router.openshift.io/timeout": "5m"},
+			wantAnnotations: map[string]string{
+				"haproxy.router.openshift.io/timeout": "5m",
+			},
+		},
+		{
+			name:        "Verify that the allowed source ranges are applied",
+			annotations: map[string]string{"haproxy.router.openshift.io/timeout": "5m"},
+			expose:      &mcpserverv1.Expose{AllowedSourceRanges: []string{"10.0.0.0/8", "192.168.1.10"}},
+			wantAnnotations: map[string]string{
+				"haproxy.router.openshift.io/timeout": "5m",
+				routeIPAllowlistAnnotation:            "10.0.0.0/8 192.168.1.10",
+			},
+		},
+		{
+			name:            "Verify that changed allowed source ranges are applied",
+			annotations:     map[string]string{routeIPAllowlistAnnotation: "10.0.0.0/8"},
+			expose:          &mcpserverv1.Expose{AllowedSourceRanges: []string{"172.16.0.0/12"}},
+			wantAnnotations: map[string]string{routeIPAllowlistAnnotation: "172.16.0.0/12"},
+		},
+		{
+			name:        "Verify that the allowlist is removed with the allowed source ranges",
+			annotations: map[string]string{routeIPAllowlistAnnotation: "10.0.0.0/8"},
+			expose:      &mcpserverv1.Expose{},
+		},
+		{
+			name:            "Verify that the SSE idle timeout replaces the timeout",
+			annotations:     map[string]string{routeTimeoutAnnotation: "5m"},
+			sse:             &mcpserverv1.SSE{IdleTimeout: &metav1.Duration{Duration: time.Hour}},
+			wantAnnotations: map[string]string{routeTimeoutAnnotation: "3600s"},
+		},
+		{
+			name:            "Verify that ignored annotations are neither changed nor removed",
+			annotations:     map[string]string{routeTimeoutAnnotation: "5m", routeIPAllowlistAnnotation: "10.0.0.0/8"},
+			expose:          &mcpserverv1.Expose{},
+			sse:             &mcpserverv1.SSE{IdleTimeout: &metav1.Duration{Duration: time.Hour}},
+			ignore:          &mcpserverv1.IgnoreDifferences{Annotations: []string{"haproxy.router.openshift.io/*"}},
+			wantAnnotations: map[string]string{routeTimeoutAnnotation: "5m", routeIPAllowlistAnnotation: "10.0.0.0/8"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route := &routev1.Route{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        mcpServerName,
+					Namespace:   testNamespace,
+					Annotations: tt.annotations,
+				},
+			}
+			cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(route).Build()
+			r := &MCPServerReconciler{Client: cli, Scheme: fakeScheme}
+			cr := &mcpserverv1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+				Spec: mcpserverv1.MCPServerSpec{Image: mcpServerImage, Expose: tt.expose, SSE: tt.sse,
+					IgnoreDifferences: tt.ignore},
+			}
+
+			if err := r.reconcileRouteAnnotations(context.Background(), cli, cr); err != nil {
+				t.Fatalf("reconcileRouteAnnotations() error = %v", err)
+			}
+
+			found := &routev1.Route{}
+			if err := cli.Get(context.Background(), client.ObjectKeyFromObject(route), found); err != nil {
+				t.Fatalf("failed to get route for verification: %v", err)
+			}
+			if len(found.Annotations) != len(tt.wantAnnotations) || (len(tt.wantAnnotations) > 0 && !reflect.DeepEqual(found.Annotations, tt.wantAnnotations)) {
+				t.Errorf("annotations = %v, want %v", found.Annotations, tt.wantAnnotations)
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_reconcileRouteSpec(t *testing.T) {
+	fakeScheme := runtime.NewScheme()
+	if err := routev1.AddToScheme(fakeScheme); err != nil {
+		t.Errorf("failed to add routev1 scheme: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		basePath string
+		wantPath string
+	}{
+		{name: "Verify that a Route without a base path admits all paths"},
+		{name: "Verify that the base path is applied", basePath: "/mcp", wantPath: "/mcp"},
+		{name: "Verify that a changed base path is applied", path: "/mcp", basePath: "/v2/mcp", wantPath: "/v2/mcp"},
+		{name: "Verify that the path is removed with the base path", path: "/mcp"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route := &routev1.Route{
+				ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+				Spec:       routev1.RouteSpec{Path: tt.path},
+			}
+			cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(route).Build()
+			r := &MCPServerReconciler{Client: cli, Scheme: fakeScheme}
+			cr := &mcpserverv1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+				Spec:       mcpserverv1.MCPServerSpec{Image: mcpServerImage, BasePath: tt.basePath},
+			}
+
+			if err := r.reconcileRouteSpec(context.Background(), cli, cr, nil); err != nil {
+				t.Fatalf("reconcileRouteSpec() error = %v", err)
+			}
+
+			found := &routev1.Route{}
+			if err := cli.Get(context.Background(), client.ObjectKeyFromObject(route), found); err != nil {
+				t.Fatalf("failed to get route for verification: %v", err)
+			}
+			if found.Spec.Path != tt.wantPath {
+				t.Errorf("path = %q, want %q", found.Spec.Path, tt.wantPath)
+			}
+		})
+	}
+}
+
 type mockErrorClient struct {
 	client.Client
 	errOnGet bool
@@ -407,6 +629,37 @@ func TestMCPServerReconciler_getDeploymentCondition(t *testing.T) {
 		},
 	}
 
+	// Create a deployment whose pods are not admitted by any SCC.
+	sccDeniedDeployment := unreadyDeployment.DeepCopy()
+	sccDeniedDeployment.Status.Conditions = append(sccDeniedDeployment.Status.Conditions, appsv1.DeploymentCondition{
+		Type:   appsv1.DeploymentReplicaFailure,
+		Status: corev1.ConditionTrue,
+		Reason: "FailedCreate",
+		Message: "pods \"" + mcpServerName + "-abcde\" is forbidden: unable to validate against any security context " +
+			"constraint: [provider restricted-v2: .spec.securityContext.runAsUser: Invalid value: 1001]",
+	})
+
+	// Create a pod of the deployment that cannot pull its image.
+	imagePullBackOffPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mcpServerName + "-abcde",
+			Namespace: testNamespace,
+			Labels:    map[string]string{mcpServerAppLabelKey: mcpServerName},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "mcp-server",
+				Image: mcpServerImage,
+				State: corev1.ContainerState{
+					Waiting: &corev1.ContainerStateWaiting{
+						Reason:  "ImagePullBackOff",
+						Message: "Back-off pulling image",
+					},
+				},
+			}},
+		},
+	}
+
 	// Create a fake scheme
 	fakeScheme := runtime.NewScheme()
 	err := mcpserverv1.AddToScheme(fakeScheme)
@@ -504,6 +757,43 @@ func TestMCPServerReconciler_getDeploymentCondition(t *testing.T) {
 				Message: fmt.Sprintf("Deployment %s is not yet available", mcpServer.Name),
 			},
 		},
+		{
+			name: "Verify that if a pod cannot pull its image, the ImagePullFailed condition is returned",
+			fields: fields{
+				Client: fake.NewClientBuilder().WithRuntimeObjects([]runtime.Object{unreadyDeployment, imagePullBackOffPod}...).Build(),
+				Scheme: fakeScheme,
+			},
+			args: args{
+				ctx: testContext,
+				cli: fake.NewClientBuilder().WithRuntimeObjects([]runtime.Object{unreadyDeployment, imagePullBackOffPod}...).Build(),
+				cr:  mcpServer,
+			},
+			want: metav1.Condition{
+				Type:    DeploymentAvailable,
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonImagePullFailed,
+				Message: fmt.Sprintf("Failed to pull image %s for container mcp-server in pod %s-abcde: ImagePullBackOff, Back-off pulling image", mcpServerImage, mcpServerName),
+			},
+		},
+		{
+			name: "Verify that if no SCC admits the pods, the SecurityContextConstraintsDenied condition is returned",
+			fields: fields{
+				Client: fake.NewClientBuilder().WithRuntimeObjects([]runtime.Object{sccDeniedDeployment}...).Build(),
+				Scheme: fakeScheme,
+			},
+			args: args{
+				ctx: testContext,
+				cli: fake.NewClientBuilder().WithRuntimeObjects([]runtime.Object{sccDeniedDeployment}...).Build(),
+				cr:  mcpServer,
+			},
+			want: metav1.Condition{
+				Type:   DeploymentAvailable,
+				Status: metav1.ConditionFalse,
+				Reason: ReasonSCCDenied,
+				Message: fmt.Sprintf("No security context constraint admits the pods of Deployment %s, grant the service "+
+					"account of the pods the SCC they need: %s", mcpServerName, sccDeniedDeployment.Status.Conditions[1].Message),
+			},
+		},
 		{
 			name: "Verify that if deployment's status is missing, function returns DeploymentNotReady",
 			fields: fields{
@@ -554,6 +844,67 @@ func TestMCPServerReconciler_getDeploymentCondition(t *testing.T) {
 	}
 }
 
+func TestMCPServerReconciler_getDeploymentReplicas(t *testing.T) {
+	mcpServer := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mcpServerName,
+			Namespace: testNamespace,
+		},
+	}
+
+	rollingDeployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mcpServerName,
+			Namespace: testNamespace,
+		},
+		Status: appsv1.DeploymentStatus{
+			Replicas:      2,
+			ReadyReplicas: 1,
+		},
+	}
+
+	tests := []struct {
+		name      string
+		cli       client.Client
+		wantTotal int32
+		wantReady int32
+		wantErr   bool
+	}{
+		{
+			name: "missing deployment",
+			cli:  fake.NewClientBuilder().Build(),
+		},
+		{
+			name:      "deployment rolling out",
+			cli:       fake.NewClientBuilder().WithObjects(rollingDeployment).Build(),
+			wantTotal: 2,
+			wantReady: 1,
+		},
+		{
+			name: "get error",
+			cli: &mockErrorClient{
+				Client:   fake.NewClientBuilder().Build(),
+				errOnGet: true,
+				getError: fmt.Errorf("mock get error"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := tt.cli
+			r := &MCPServerReconciler{Client: cli}
+			total, ready, err := r.getDeploymentReplicas(context.Background(), cli, mcpServer)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getDeploymentReplicas() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if total != tt.wantTotal || ready != tt.wantReady {
+				t.Errorf("getDeploymentReplicas() = %d, %d, want %d, %d", total, ready, tt.wantTotal, tt.wantReady)
+			}
+		})
+	}
+}
+
 func TestMCPServerReconciler_getServiceCondition(t *testing.T) {
 
 	// Create an existing service
@@ -890,8 +1241,9 @@ func TestMCPServerReconciler_getOverallCondition(t *testing.T) {
 	}
 
 	type fields struct {
-		Client client.Client
-		Scheme *runtime.Scheme
+		Client   client.Client
+		Scheme   *runtime.Scheme
+		Platform *cluster.Platform
 	}
 	type args struct {
 		cr *mcpserverv1.MCPServer
@@ -919,6 +1271,7 @@ func TestMCPServerReconciler_getOverallCondition(t *testing.T) {
 							{Type: DeploymentAvailable, Status: metav1.ConditionTrue},
 							{Type: ServiceAvailable, Status: metav1.ConditionTrue},
 							{Type: RouteAvailable, Status: metav1.ConditionTrue},
+							{Type: EndpointReachable, Status: metav1.ConditionTrue},
 						},
 					},
 					Spec: mcpserverv1.MCPServerSpec{
@@ -930,7 +1283,71 @@ func TestMCPServerReconciler_getOverallCondition(t *testing.T) {
 				Type:    OverallAvailable,
 				Status:  metav1.ConditionTrue,
 				Reason:  "AllComponentsReady",
-				Message: "All managed components (Deployment, Service, Route) are ready",
+				Message: "All managed components (Deployment, Service, Route) are ready and the endpoint is reachable",
+			},
+		},
+		{
+			name: "Verify that without the Route API, the AllComponentsReady condition is returned without a RouteAvailable condition.",
+			fields: fields{
+				Client:   fakeClient,
+				Scheme:   fakeScheme,
+				Platform: &cluster.Platform{Name: cluster.Kubernetes},
+			},
+			args: args{
+				cr: &mcpserverv1.MCPServer{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      mcpServerName,
+						Namespace: testNamespace,
+					},
+					Status: mcpserverv1.MCPServerStatus{
+						Conditions: []metav1.Condition{
+							{Type: DeploymentAvailable, Status: metav1.ConditionTrue},
+							{Type: ServiceAvailable, Status: metav1.ConditionTrue},
+							{Type: EndpointReachable, Status: metav1.ConditionTrue},
+						},
+					},
+					Spec: mcpserverv1.MCPServerSpec{
+						Image: mcpServerImage,
+					},
+				},
+			},
+			want: metav1.Condition{
+				Type:    OverallAvailable,
+				Status:  metav1.ConditionTrue,
+				Reason:  "AllComponentsReady",
+				Message: "All managed components (Deployment, Service) are ready and the endpoint is reachable",
+			},
+		},
+		{
+			name: "Verify that if the endpoint isn't reachable, the function returns the EndpointUnreachable condition",
+			fields: fields{
+				Client: fakeClient,
+				Scheme: fakeScheme,
+			},
+			args: args{
+				cr: &mcpserverv1.MCPServer{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      mcpServerName,
+						Namespace: testNamespace,
+					},
+					Status: mcpserverv1.MCPServerStatus{
+						Conditions: []metav1.Condition{
+							{Type: DeploymentAvailable, Status: metav1.ConditionTrue},
+							{Type: ServiceAvailable, Status: metav1.ConditionTrue},
+							{Type: RouteAvailable, Status: metav1.ConditionTrue},
+							{Type: EndpointReachable, Status: metav1.ConditionFalse},
+						},
+					},
+					Spec: mcpserverv1.MCPServerSpec{
+						Image: mcpServerImage,
+					},
+				},
+			},
+			want: metav1.Condition{
+				Type:    OverallAvailable,
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonEndpointUnreachable,
+				Message: "Endpoint is not yet reachable",
 			},
 		},
 		{
@@ -1121,8 +1538,9 @@ func TestMCPServerReconciler_getOverallCondition(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := &MCPServerReconciler{
-				Client: tt.fields.Client,
-				Scheme: tt.fields.Scheme,
+				Client:   tt.fields.Client,
+				Scheme:   tt.fields.Scheme,
+				Platform: tt.fields.Platform,
 			}
 			if got := r.getOverallCondition(tt.args.cr); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("getOverallCondition() = %v, want %v", got, tt.want)
@@ -1130,3 +1548,310 @@ func TestMCPServerReconciler_getOverallCondition(t *testing.T) {
 		})
 	}
 }
+
+func TestMCPServerReconciler_reconcileDeploymentRestart(t *testing.T) {
+	// Create a deployment without a restartedAt annotation on its pod template
+	existingDeployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mcpServerName,
+			Namespace: testNamespace,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "mcp-server"}},
+				},
+			},
+		},
+	}
+
+	// Create a fake scheme
+	fakeScheme := runtime.NewScheme()
+	err := mcpserverv1.AddToScheme(fakeScheme)
+	if err != nil {
+		t.Errorf("failed to add mcpserverv1 scheme: %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		annotations     map[string]string
+		wantRestartedAt string
+	}{
+		{
+			name:            "Verify that the pod template is left untouched without the restartedAt annotation",
+			annotations:     nil,
+			wantRestartedAt: "",
+		},
+		{
+			name:            "Verify that the restartedAt annotation is copied onto the pod template",
+			annotations:     map[string]string{mcpserverv1.RestartedAtAnnotation: "2025-01-01T00:00:00Z"},
+			wantRestartedAt: "2025-01-01T00:00:00Z",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().WithRuntimeObjects(existingDeployment.DeepCopy()).Build()
+			r := &MCPServerReconciler{
+				Client: cli,
+				Scheme: fakeScheme,
+			}
+			cr := &mcpserverv1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        mcpServerName,
+					Namespace:   testNamespace,
+					Annotations: tt.annotations,
+				},
+				Spec: mcpserverv1.MCPServerSpec{
+					Image: mcpServerImage,
+				},
+			}
+
+			if err := r.reconcileMCPServerDeployment(context.Background(), cli, cr); err != nil {
+				t.Fatalf("reconcileMCPServerDeployment() error = %v", err)
+			}
+
+			foundDeployment := &appsv1.Deployment{}
+			err := cli.Get(context.Background(), types.NamespacedName{Name: mcpServerName, Namespace: testNamespace}, foundDeployment)
+			if err != nil {
+				t.Fatalf("failed to get deployment for verification: %v", err)
+			}
+			if got := foundDeployment.Spec.Template.Annotations[mcpserverv1.RestartedAtAnnotation]; got != tt.wantRestartedAt {
+				t.Errorf("restartedAt mismatch: got %v, want %v", got, tt.wantRestartedAt)
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_reconcileDeploymentSpec(t *testing.T) {
+	// Create a deployment with a single replica and no affinity
+	existingDeployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mcpServerName,
+			Namespace: testNamespace,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To[int32](1),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "mcp-server"}},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name                        string
+		replicas                    *int32
+		minReadySeconds             *int32
+		progressDeadlineSeconds     *int32
+		revisionHistoryLimit        *int32
+		hostAliases                 []corev1.HostAlias
+		lifecycle                   *corev1.Lifecycle
+		config                      map[string]apiextensionsv1.JSON
+		env                         []corev1.EnvVar
+		envFrom                     []corev1.EnvFromSource
+		ignoreDifferences           *mcpserverv1.IgnoreDifferences
+		autoscaling                 *mcpserverv1.Autoscaling
+		handMadeAutoscaler          bool
+		wantReplicas                int32
+		wantAffinity                bool
+		wantMinReadySeconds         int32
+		wantProgressDeadlineSeconds *int32
+		wantArgs                    []string
+	}{
+		{
+			name:         "Verify that the replicas of the Deployment are kept when unset",
+			wantReplicas: 1,
+		},
+		{
+			name:         "Verify that the Deployment is scaled and spread across nodes",
+			replicas:     ptr.To[int32](3),
+			wantReplicas: 3,
+			wantAffinity: true,
+		},
+		{
+			name:                        "Verify that the rollout settings are applied",
+			minReadySeconds:             ptr.To[int32](10),
+			progressDeadlineSeconds:     ptr.To[int32](120),
+			wantReplicas:                1,
+			wantMinReadySeconds:         10,
+			wantProgressDeadlineSeconds: ptr.To[int32](120),
+		},
+		{
+			name:              "Verify that ignored replicas are left to whatever scales the Deployment",
+			replicas:          ptr.To[int32](3),
+			ignoreDifferences: &mcpserverv1.IgnoreDifferences{Replicas: true},
+			wantReplicas:      1,
+			wantAffinity:      true,
+		},
+		{
+			name:         "Verify that the replicas are left to the autoscaler of the MCPServer",
+			replicas:     ptr.To[int32](3),
+			autoscaling:  &mcpserverv1.Autoscaling{MaxReplicas: 5},
+			wantReplicas: 1,
+			wantAffinity: true,
+		},
+		{
+			name:               "Verify that the replicas are left to an autoscaler created by hand",
+			replicas:           ptr.To[int32](3),
+			handMadeAutoscaler: true,
+			wantReplicas:       1,
+			wantAffinity:       true,
+		},
+		{
+			name:         "Verify that an autoscaled Deployment can still be scaled to zero",
+			replicas:     ptr.To[int32](0),
+			autoscaling:  &mcpserverv1.Autoscaling{MaxReplicas: 5},
+			wantReplicas: 0,
+			wantAffinity: true,
+		},
+		{
+			name:                 "Verify that the revision history limit is applied",
+			revisionHistoryLimit: ptr.To[int32](2),
+			wantReplicas:         1,
+		},
+		{
+			name:         "Verify that the host aliases are applied",
+			hostAliases:  []corev1.HostAlias{{IP: "10.0.0.10", Hostnames: []string{"erp.corp.example.com"}}},
+			wantReplicas: 1,
+		},
+		{
+			name: "Verify that the lifecycle hooks are applied to the MCP server container",
+			lifecycle: &corev1.Lifecycle{PreStop: &corev1.LifecycleHandler{
+				Exec: &corev1.ExecAction{Command: []string{"/bin/deregister"}},
+			}},
+			wantReplicas: 1,
+		},
+		{
+			name:         "Verify that the config is rendered into the args of the MCP server container",
+			config:       map[string]apiextensionsv1.JSON{"readOnly": {Raw: []byte("true")}},
+			wantReplicas: 1,
+			wantArgs:     []string{"--port", "8000", "--log-level", "9", "--read-only"},
+		},
+		{
+			name: "Verify that the env and envFrom are applied to the MCP server container",
+			env:  []corev1.EnvVar{{Name: "LOG_FORMAT", Value: "json"}},
+			envFrom: []corev1.EnvFromSource{{
+				SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "api-keys"}},
+			}},
+			wantReplicas: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objs := []runtime.Object{existingDeployment.DeepCopy()}
+			if tt.handMadeAutoscaler {
+				objs = append(objs, &autoscalingv2.HorizontalPodAutoscaler{
+					ObjectMeta: metav1.ObjectMeta{Name: "custom", Namespace: testNamespace},
+					Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+						ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+							APIVersion: "apps/v1", Kind: "Deployment", Name: mcpServerName,
+						},
+						MaxReplicas: 5,
+					},
+				})
+			}
+			cli := fake.NewClientBuilder().WithRuntimeObjects(objs...).Build()
+			r := &MCPServerReconciler{Client: cli}
+			cr := &mcpserverv1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+				Spec: mcpserverv1.MCPServerSpec{
+					Image:                   mcpServerImage,
+					Replicas:                tt.replicas,
+					Autoscaling:             tt.autoscaling,
+					MinReadySeconds:         tt.minReadySeconds,
+					ProgressDeadlineSeconds: tt.progressDeadlineSeconds,
+					RevisionHistoryLimit:    tt.revisionHistoryLimit,
+					HostAliases:             tt.hostAliases,
+					Lifecycle:               tt.lifecycle,
+					Config:                  tt.config,
+					Env:                     tt.env,
+					EnvFrom:                 tt.envFrom,
+					IgnoreDifferences:       tt.ignoreDifferences,
+				},
+			}
+
+			if err := r.reconcileDeploymentSpec(context.Background(), cli, cr); err != nil {
+				t.Fatalf("reconcileDeploymentSpec() error = %v", err)
+			}
+
+			foundDeployment := &appsv1.Deployment{}
+			err := cli.Get(context.Background(), types.NamespacedName{Name: mcpServerName, Namespace: testNamespace}, foundDeployment)
+			if err != nil {
+				t.Fatalf("failed to get deployment for verification: %v", err)
+			}
+			if got := *foundDeployment.Spec.Replicas; got != tt.wantReplicas {
+				t.Errorf("replicas = %d, want %d", got, tt.wantReplicas)
+			}
+			if got := foundDeployment.Spec.Template.Spec.Affinity != nil; got != tt.wantAffinity {
+				t.Errorf("affinity set = %v, want %v", got, tt.wantAffinity)
+			}
+			if got := foundDeployment.Spec.MinReadySeconds; got != tt.wantMinReadySeconds {
+				t.Errorf("minReadySeconds = %d, want %d", got, tt.wantMinReadySeconds)
+			}
+			if got := foundDeployment.Spec.ProgressDeadlineSeconds; !reflect.DeepEqual(got, tt.wantProgressDeadlineSeconds) {
+				t.Errorf("progressDeadlineSeconds = %v, want %v", got, tt.wantProgressDeadlineSeconds)
+			}
+			if got := foundDeployment.Spec.RevisionHistoryLimit; !reflect.DeepEqual(got, tt.revisionHistoryLimit) {
+				t.Errorf("revisionHistoryLimit = %v, want %v", got, tt.revisionHistoryLimit)
+			}
+			if got := foundDeployment.Spec.Template.Spec.HostAliases; !reflect.DeepEqual(got, tt.hostAliases) {
+				t.Errorf("hostAliases = %v, want %v", got, tt.hostAliases)
+			}
+			if got := foundDeployment.Spec.Template.Spec.Containers[0].Lifecycle; !reflect.DeepEqual(got, tt.lifecycle) {
+				t.Errorf("lifecycle = %v, want %v", got, tt.lifecycle)
+			}
+			wantArgs := tt.wantArgs
+			if wantArgs == nil {
+				wantArgs = DefaultMCPDeploymentArgs
+			}
+			if got := foundDeployment.Spec.Template.Spec.Containers[0].Args; !reflect.DeepEqual(got, wantArgs) {
+				t.Errorf("args = %v, want %v", got, wantArgs)
+			}
+			if got := foundDeployment.Spec.Template.Spec.Containers[0].Env; !reflect.DeepEqual(got, tt.env) {
+				t.Errorf("env = %v, want %v", got, tt.env)
+			}
+			if got := foundDeployment.Spec.Template.Spec.Containers[0].EnvFrom; !reflect.DeepEqual(got, tt.envFrom) {
+				t.Errorf("envFrom = %v, want %v", got, tt.envFrom)
+			}
+			if got := foundDeployment.Spec.Template.Spec.Containers[0].Image; got != mcpServerImage {
+				t.Errorf("image = %s, want %s", got, mcpServerImage)
+			}
+		})
+	}
+}
+
+func Test_automountServiceAccountToken(t *testing.T) {
+	tests := []struct {
+		name string
+		spec mcpserverv1.MCPServerSpec
+		want bool
+	}{
+		{
+			name: "Verify that the Kubernetes MCP server gets the token",
+			spec: mcpserverv1.MCPServerSpec{Image: mcpServerImage},
+			want: true,
+		},
+		{
+			name: "Verify that other servers do not get the token",
+			spec: mcpserverv1.MCPServerSpec{Image: mcpServerImage, Command: CustomMCPDeploymentCommand},
+		},
+		{
+			name: "Verify that the proxy gets the token to review its callers",
+			spec: mcpserverv1.MCPServerSpec{Type: mcpserverv1.MCPServerProxy, URL: "https://mcp.example.com/mcp"},
+			want: true,
+		},
+		{
+			name: "Verify that the setting of the MCPServer wins",
+			spec: mcpserverv1.MCPServerSpec{Image: mcpServerImage, AutomountServiceAccountToken: ptr.To(false)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &mcpserverv1.MCPServer{Spec: tt.spec}
+			if got := *automountServiceAccountToken(cr); got != tt.want {
+				t.Errorf("automountServiceAccountToken() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
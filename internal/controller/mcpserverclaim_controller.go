@@ -0,0 +1,403 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// +kubebuilder:rbac:groups=mcpserver.opendatahub.io,resources=mcpserverclaims,verbs=get;list;watch;update;patch;delete
+// +kubebuilder:rbac:groups=mcpserver.opendatahub.io,resources=mcpserverclaims/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=mcpserver.opendatahub.io,resources=mcpserverclaims/finalizers,verbs=update
+// +kubebuilder:rbac:groups="rbac.authorization.k8s.io",resources=roles;rolebindings,verbs=create;get;list;watch;update;patch;delete
+
+const (
+	// ReasonTemplate is set on the Available condition of MCPServers labeled as templates, which are not deployed.
+	ReasonTemplate = "Template"
+
+	// ReasonTemplateNotFound is set on the Available condition of an MCPServerClaim whose template does not exist or
+	// is not labeled as a template.
+	ReasonTemplateNotFound = "TemplateNotFound"
+
+	// ReasonTemplateNotIsolated is set on the Available condition of an MCPServerClaim whose template cannot be run
+	// with a token of its own for each user.
+	ReasonTemplateNotIsolated = "TemplateNotIsolated"
+
+	// ReasonInstanceConflict is set on the Available condition of an MCPServerClaim when an MCPServer the claim
+	// does not control has the name of its instance.
+	ReasonInstanceConflict = "InstanceConflict"
+
+	// ReasonAccessConflict is set on the Available condition of an MCPServerClaim when a Role or RoleBinding the claim
+	// does not control has the name of the ones that grant its user access to the instance.
+	ReasonAccessConflict = "AccessConflict"
+
+	// ReasonInstancePending is set on the Available condition of an MCPServerClaim whose instance has not reported
+	// its availability yet.
+	ReasonInstancePending = "InstancePending"
+
+	// claimUserSuffix is the suffix of the name of the Role and RoleBinding that grant the user of an
+	// MCPServerClaim access to its instance.
+	claimUserSuffix = "user"
+)
+
+// isTemplate reports whether cr is a template for the instances of MCPServerClaims.
+func isTemplate(cr *mcpserverv1.MCPServer) bool {
+	return cr.Labels[mcpserverv1.TemplateLabel] == "true"
+}
+
+// templateCondition returns the Available condition of a template.
+func templateCondition() metav1.Condition {
+	return metav1.Condition{
+		Type:    OverallAvailable,
+		Status:  metav1.ConditionFalse,
+		Reason:  ReasonTemplate,
+		Message: "The MCPServer is a template for the instances of MCPServerClaims and is not deployed",
+	}
+}
+
+// MCPServerClaimReconciler reconciles a MCPServerClaim object: it creates an instance of the template for the user
+// of the claim, which authenticates its clients with a token of its own, grants only that user access to the
+// instance and its token, and deletes the claim once its lifetime has elapsed.
+type MCPServerClaimReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// Shard selects the claims this replica reconciles. All claims are reconciled when nil.
+	Shard *Shard
+}
+
+// Reconcile creates or updates the instance of the MCPServerClaim and the access of its user, and reports the
+// availability of the instance in its status.
+func (r *MCPServerClaimReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	if !r.Shard.Owns(req.NamespacedName) {
+		return ctrl.Result{}, nil
+	}
+	claim := &mcpserverv1.MCPServerClaim{}
+	if err := r.Get(ctx, req.NamespacedName, claim); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	// The instance, Role and RoleBinding are removed with the claim by the garbage collector.
+	if !claim.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	if expiration := claimExpirationTime(claim, nil, nil); expiration != nil && !time.Now().Before(expiration.Time) {
+		return ctrl.Result{}, client.IgnoreNotFound(r.Delete(ctx, claim))
+	}
+
+	original := claim.DeepCopy()
+	claim.Status.ExpirationTime = claimExpirationTime(claim, nil, nil)
+	condition, err := r.reconcileInstance(ctx, claim)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	var requeueAfter time.Duration
+	if expiration := claim.Status.ExpirationTime; expiration != nil {
+		// The instance of the claim expired according to the TTLs of the template.
+		if requeueAfter = time.Until(expiration.Time); requeueAfter <= 0 {
+			return ctrl.Result{}, client.IgnoreNotFound(r.Delete(ctx, claim))
+		}
+	}
+	meta.SetStatusCondition(&claim.Status.Conditions, condition)
+	if !equality.Semantic.DeepEqual(original.Status, claim.Status) {
+		if err := r.Status().Patch(ctx, claim, client.MergeFrom(original)); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// reconcileInstance creates or updates the instance of claim and the Role and RoleBinding of its user, fills in
+// the status of claim that describes the instance and returns its Available condition.
+func (r *MCPServerClaimReconciler) reconcileInstance(ctx context.Context, claim *mcpserverv1.MCPServerClaim) (metav1.Condition, error) {
+	template := &mcpserverv1.MCPServer{}
+	err := r.Get(ctx, client.ObjectKey{Name: claim.Spec.TemplateName, Namespace: claim.Namespace}, template)
+	if err != nil && !k8serr.IsNotFound(err) {
+		return metav1.Condition{}, err
+	}
+	if err != nil || !isTemplate(template) {
+		return metav1.Condition{
+			Type:   OverallAvailable,
+			Status: metav1.ConditionFalse,
+			Reason: ReasonTemplateNotFound,
+			Message: fmt.Sprintf("MCPServer %s does not exist or is not labeled %s=true", claim.Spec.TemplateName,
+				mcpserverv1.TemplateLabel),
+		}, nil
+	}
+	if message := templateNotIsolated(template); message != "" {
+		return metav1.Condition{
+			Type:    OverallAvailable,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonTemplateNotIsolated,
+			Message: message,
+		}, nil
+	}
+
+	desired := claimInstance(claim, template)
+	if err := ctrl.SetControllerReference(claim, desired, r.Scheme); err != nil {
+		return metav1.Condition{}, err
+	}
+	instance := desired.DeepCopy()
+	if err := r.Create(ctx, instance); err != nil {
+		if !k8serr.IsAlreadyExists(err) {
+			return metav1.Condition{}, err
+		}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(desired), instance); err != nil {
+			return metav1.Condition{}, err
+		}
+		if !metav1.IsControlledBy(instance, claim) {
+			return metav1.Condition{
+				Type:    OverallAvailable,
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonInstanceConflict,
+				Message: fmt.Sprintf("MCPServer %s is not controlled by the claim", instance.Name),
+			}, nil
+		}
+		labelsMatch := true
+		for key, value := range desired.Labels {
+			if instance.Labels[key] != value {
+				labelsMatch = false
+			}
+		}
+		if !labelsMatch || !equality.Semantic.DeepEqual(instance.Spec, desired.Spec) {
+			original := instance.DeepCopy()
+			if instance.Labels == nil {
+				instance.Labels = map[string]string{}
+			}
+			for key, value := range desired.Labels {
+				instance.Labels[key] = value
+			}
+			instance.Spec = desired.Spec
+			if err := r.Patch(ctx, instance, client.MergeFrom(original)); err != nil {
+				return metav1.Condition{}, err
+			}
+		}
+	}
+	if conflict, err := r.reconcileUserAccess(ctx, claim, instance); err != nil || conflict != nil {
+		return ptr.Deref(conflict, metav1.Condition{}), err
+	}
+
+	claim.Status.MCPServer = instance.Name
+	claim.Status.URL = instance.Status.URL
+	claim.Status.TokenSecretName = authTokenSecretName(instance)
+	claim.Status.ExpirationTime = claimExpirationTime(claim, template, instance)
+	if available := meta.FindStatusCondition(instance.Status.Conditions, OverallAvailable); available != nil {
+		return metav1.Condition{
+			Type:    OverallAvailable,
+			Status:  available.Status,
+			Reason:  available.Reason,
+			Message: available.Message,
+		}, nil
+	}
+	return metav1.Condition{
+		Type:    OverallAvailable,
+		Status:  metav1.ConditionFalse,
+		Reason:  ReasonInstancePending,
+		Message: fmt.Sprintf("MCPServer %s has not reported its availability yet", instance.Name),
+	}, nil
+}
+
+// claimExpirationTime returns when claim is to be deleted: once its lifetime elapsed or, with the TTLs of template,
+// once instance expired, whichever comes first. It is nil when neither is set. The lifetime alone is considered
+// when template and instance are nil.
+func claimExpirationTime(claim *mcpserverv1.MCPServerClaim, template, instance *mcpserverv1.MCPServer) *metav1.Time {
+	var expiration *metav1.Time
+	if template != nil && instance != nil {
+		expiring := instance.DeepCopy()
+		expiring.Spec.TTLSecondsAfterCreation = template.Spec.TTLSecondsAfterCreation
+		expiring.Spec.TTLSecondsAfterLastActivity = template.Spec.TTLSecondsAfterLastActivity
+		expiration = expirationTime(expiring)
+	}
+	if claim.Spec.Lifetime != nil {
+		lifetime := claim.CreationTimestamp.Add(claim.Spec.Lifetime.Duration)
+		if expiration == nil || lifetime.Before(expiration.Time) {
+			expiration = &metav1.Time{Time: lifetime}
+		}
+	}
+	return expiration
+}
+
+// templateNotIsolated returns why the instances of template cannot authenticate their clients with a token of
+// their own, or an empty string if they can. Only Managed MCP servers support token authentication.
+func templateNotIsolated(template *mcpserverv1.MCPServer) string {
+	switch {
+	case isExternal(template) || isProxy(template):
+		return fmt.Sprintf("%s MCPServers cannot authenticate their clients with a token, only Managed templates "+
+			"are supported", template.Spec.Type)
+	case usesTokenPassthrough(template):
+		return "Templates with kubernetesAccess.mode TokenPassthrough cannot authenticate their clients with a token"
+	case usesHTTP2(template):
+		return fmt.Sprintf("Templates with protocol %s cannot authenticate their clients with a token",
+			template.Spec.Protocol)
+	}
+	return ""
+}
+
+// claimInstance returns the instance of template for claim. It has the spec and labels of the template, the
+// credentials of the user if the claim selects any, and a token generated for it alone, whatever the template
+// configures in spec.auth. The TTLs of the template are left out, see claimExpirationTime.
+func claimInstance(claim *mcpserverv1.MCPServerClaim, template *mcpserverv1.MCPServer) *mcpserverv1.MCPServer {
+	labels := map[string]string{}
+	for key, value := range template.Labels {
+		labels[key] = value
+	}
+	delete(labels, mcpserverv1.TemplateLabel)
+	labels[mcpserverv1.ClaimLabel] = claim.Name
+
+	spec := template.Spec.DeepCopy()
+	if claim.Spec.CredentialsSecretRef != nil {
+		spec.CredentialsSecretRef = claim.Spec.CredentialsSecretRef.DeepCopy()
+	}
+	spec.Auth = &mcpserverv1.Auth{Type: mcpserverv1.AuthToken}
+	// The claim expires with the instance instead, which it would otherwise create again.
+	spec.TTLSecondsAfterCreation = nil
+	spec.TTLSecondsAfterLastActivity = nil
+	return &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      claim.Name,
+			Namespace: claim.Namespace,
+			Labels:    labels,
+		},
+		Spec: *spec,
+	}
+}
+
+// claimUserRole returns the Role that lets the user of claim read instance and the token it authenticates its
+// clients with.
+func claimUserRole(claim *mcpserverv1.MCPServerClaim, instance *mcpserverv1.MCPServer) *rbacv1.Role {
+	return &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      shortenedName(claim.Name, claimUserSuffix),
+			Namespace: claim.Namespace,
+			Labels:    map[string]string{mcpserverv1.ClaimLabel: claim.Name},
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups:     []string{mcpserverv1.GroupVersion.Group},
+				Resources:     []string{"mcpservers"},
+				ResourceNames: []string{instance.Name},
+				Verbs:         []string{"get", "watch"},
+			},
+			{
+				APIGroups:     []string{""},
+				Resources:     []string{"secrets"},
+				ResourceNames: []string{authTokenSecretName(instance)},
+				Verbs:         []string{"get"},
+			},
+		},
+	}
+}
+
+// reconcileUserAccess creates or updates the Role of the user of claim and the RoleBinding that grants it to the
+// user. When a Role or RoleBinding the claim does not control has their name, the user is not bound and the
+// Available condition of the conflict is returned instead: binding the user to a Role someone else created could
+// grant it more than access to the instance.
+func (r *MCPServerClaimReconciler) reconcileUserAccess(ctx context.Context, claim *mcpserverv1.MCPServerClaim,
+	instance *mcpserverv1.MCPServer) (*metav1.Condition, error) {
+	role := claimUserRole(claim, instance)
+	if err := ctrl.SetControllerReference(claim, role, r.Scheme); err != nil {
+		return nil, err
+	}
+	if err := r.Create(ctx, role.DeepCopy()); err != nil {
+		if !k8serr.IsAlreadyExists(err) {
+			return nil, err
+		}
+		existing := &rbacv1.Role{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(role), existing); err != nil {
+			return nil, err
+		}
+		if !metav1.IsControlledBy(existing, claim) {
+			return accessConflict("Role", existing.Name), nil
+		}
+		if !equality.Semantic.DeepEqual(existing.Rules, role.Rules) {
+			original := existing.DeepCopy()
+			existing.Rules = role.Rules
+			if err := r.Patch(ctx, existing, client.MergeFrom(original)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	binding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      role.Name,
+			Namespace: role.Namespace,
+			Labels:    role.Labels,
+		},
+		Subjects: []rbacv1.Subject{{
+			APIGroup: rbacv1.GroupName,
+			Kind:     rbacv1.UserKind,
+			Name:     claim.Spec.User,
+		}},
+		RoleRef: rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: role.Name},
+	}
+	if err := ctrl.SetControllerReference(claim, binding, r.Scheme); err != nil {
+		return nil, err
+	}
+	// spec.user is immutable, so an existing RoleBinding of the claim already binds the user.
+	if err := r.Create(ctx, binding.DeepCopy()); err != nil {
+		if !k8serr.IsAlreadyExists(err) {
+			return nil, err
+		}
+		existing := &rbacv1.RoleBinding{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(binding), existing); err != nil {
+			return nil, err
+		}
+		if !metav1.IsControlledBy(existing, claim) {
+			return accessConflict("RoleBinding", existing.Name), nil
+		}
+	}
+	return nil, nil
+}
+
+// accessConflict returns the Available condition of a claim whose Role or RoleBinding named name is not controlled by
+// the claim.
+func accessConflict(kind, name string) *metav1.Condition {
+	return &metav1.Condition{
+		Type:    OverallAvailable,
+		Status:  metav1.ConditionFalse,
+		Reason:  ReasonAccessConflict,
+		Message: fmt.Sprintf("%s %s is not controlled by the claim, the user is not granted access", kind, name),
+	}
+}
+
+// mapTemplateToClaims maps a template to the MCPServerClaims of its namespace that create instances of it.
+func (r *MCPServerClaimReconciler) mapTemplateToClaims(ctx context.Context, obj client.Object) []reconcile.Request {
+	claims := &mcpserverv1.MCPServerClaimList{}
+	if err := r.List(ctx, claims, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, claim := range claims.Items {
+		if claim.Spec.TemplateName == obj.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&claim)})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *MCPServerClaimReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&mcpserverv1.MCPServerClaim{}).
+		Owns(&mcpserverv1.MCPServer{}).
+		Owns(&rbacv1.Role{}).
+		Owns(&rbacv1.RoleBinding{}).
+		Watches(&mcpserverv1.MCPServer{}, handler.EnqueueRequestsFromMapFunc(r.mapTemplateToClaims)).
+		Named("mcpserverclaim").
+		Complete(r)
+}
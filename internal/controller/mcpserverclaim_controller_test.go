@@ -0,0 +1,301 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// newTemplate returns a Managed MCPServer named notebook-tools labeled as a template, with shared credentials and
+// a shared token.
+func newTemplate() *mcpserverv1.MCPServer {
+	return &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "notebook-tools",
+			Namespace: testNamespace,
+			Labels:    map[string]string{mcpserverv1.TemplateLabel: "true", "team": "a"},
+		},
+		Spec: mcpserverv1.MCPServerSpec{
+			Image: "quay.io/example/tools:1",
+			CredentialsSecretRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "shared-credentials"},
+				Key:                  "token",
+			},
+			Auth: &mcpserverv1.Auth{
+				Type: mcpserverv1.AuthToken,
+				TokenSecretRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "shared-token"},
+					Key:                  "token",
+				},
+			},
+		},
+	}
+}
+
+// newClaim returns an MCPServerClaim named alice-tools of newTemplate for the user alice.
+func newClaim() *mcpserverv1.MCPServerClaim {
+	return &mcpserverv1.MCPServerClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "alice-tools",
+			Namespace:         testNamespace,
+			UID:               "claim-uid",
+			CreationTimestamp: metav1.Now(),
+		},
+		Spec: mcpserverv1.MCPServerClaimSpec{
+			TemplateName: "notebook-tools",
+			User:         "alice",
+			CredentialsSecretRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "alice-credentials"},
+				Key:                  "token",
+			},
+		},
+	}
+}
+
+func TestMCPServerClaimReconciler_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	fakeScheme := newAutoscalingScheme(t)
+	claim := newClaim()
+	cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(newTemplate(), claim).
+		WithStatusSubresource(&mcpserverv1.MCPServerClaim{}, &mcpserverv1.MCPServer{}).Build()
+	r := &MCPServerClaimReconciler{Client: cli, Scheme: fakeScheme}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: claim.Name, Namespace: testNamespace}}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	instance := &mcpserverv1.MCPServer{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: "alice-tools", Namespace: testNamespace}, instance); err != nil {
+		t.Fatalf("failed to get the instance: %v", err)
+	}
+	if isTemplate(instance) || instance.Labels["team"] != "a" || instance.Labels[mcpserverv1.ClaimLabel] != claim.Name {
+		t.Errorf("labels = %v, want the labels of the template without the template label, and the claim label",
+			instance.Labels)
+	}
+	if instance.Spec.CredentialsSecretRef.Name != "alice-credentials" {
+		t.Errorf("credentialsSecretRef = %+v, want the credentials of the claim", instance.Spec.CredentialsSecretRef)
+	}
+	if !usesTokenAuth(instance) || instance.Spec.Auth.TokenSecretRef != nil {
+		t.Errorf("auth = %+v, want a token generated for the instance", instance.Spec.Auth)
+	}
+
+	binding := &rbacv1.RoleBinding{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: "alice-tools-user", Namespace: testNamespace}, binding); err != nil {
+		t.Fatalf("failed to get the RoleBinding: %v", err)
+	}
+	if len(binding.Subjects) != 1 || binding.Subjects[0].Kind != rbacv1.UserKind || binding.Subjects[0].Name != "alice" {
+		t.Errorf("subjects = %+v, want the user alice", binding.Subjects)
+	}
+	role := &rbacv1.Role{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: "alice-tools-user", Namespace: testNamespace}, role); err != nil {
+		t.Fatalf("failed to get the Role: %v", err)
+	}
+	if got := role.Rules[1].ResourceNames; len(got) != 1 || got[0] != "alice-tools-auth-token" {
+		t.Errorf("Secrets of the Role = %v, want only the token of the instance", got)
+	}
+
+	if err := cli.Get(ctx, req.NamespacedName, claim); err != nil {
+		t.Fatalf("failed to get the claim: %v", err)
+	}
+	available := meta.FindStatusCondition(claim.Status.Conditions, OverallAvailable)
+	if available == nil || available.Reason != ReasonInstancePending {
+		t.Errorf("Available condition = %+v, want reason %s", available, ReasonInstancePending)
+	}
+	if claim.Status.MCPServer != "alice-tools" || claim.Status.TokenSecretName != "alice-tools-auth-token" {
+		t.Errorf("status = %+v, want the instance and its token", claim.Status)
+	}
+
+	// The availability of the instance is reported on the claim.
+	instance.Status.URL = "http://alice-tools.test-namespace.svc:8000/sse"
+	meta.SetStatusCondition(&instance.Status.Conditions,
+		metav1.Condition{Type: OverallAvailable, Status: metav1.ConditionTrue, Reason: ReasonAsExpected})
+	if err := cli.Status().Update(ctx, instance); err != nil {
+		t.Fatalf("failed to update the instance status: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := cli.Get(ctx, req.NamespacedName, claim); err != nil {
+		t.Fatalf("failed to get the claim: %v", err)
+	}
+	if !meta.IsStatusConditionTrue(claim.Status.Conditions, OverallAvailable) || claim.Status.URL != instance.Status.URL {
+		t.Errorf("status = %+v, want the instance available at %s", claim.Status, instance.Status.URL)
+	}
+}
+
+func TestMCPServerClaimReconciler_Reconcile_conditions(t *testing.T) {
+	proxy := newTemplate()
+	proxy.Spec.Type = mcpserverv1.MCPServerProxy
+	notTemplate := newTemplate()
+	notTemplate.Labels = nil
+	conflict := &mcpserverv1.MCPServer{ObjectMeta: metav1.ObjectMeta{Name: "alice-tools", Namespace: testNamespace}}
+	// A Role and RoleBinding with the names of the ones of the claim, created by someone else.
+	foreignRole := &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: "alice-tools-user", Namespace: testNamespace},
+		Rules:      []rbacv1.PolicyRule{{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}}},
+	}
+	foreignBinding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "alice-tools-user", Namespace: testNamespace},
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: "alice-tools-user"},
+	}
+
+	tests := []struct {
+		name       string
+		objects    []client.Object
+		wantReason string
+	}{
+		{
+			name:       "Verify that a claim of a missing template is not available",
+			wantReason: ReasonTemplateNotFound,
+		},
+		{
+			name:       "Verify that a claim of an MCPServer without the template label is not available",
+			objects:    []client.Object{notTemplate},
+			wantReason: ReasonTemplateNotFound,
+		},
+		{
+			name:       "Verify that a claim of a template without token authentication is not available",
+			objects:    []client.Object{proxy},
+			wantReason: ReasonTemplateNotIsolated,
+		},
+		{
+			name:       "Verify that an MCPServer with the name of the instance is left alone",
+			objects:    []client.Object{newTemplate(), conflict},
+			wantReason: ReasonInstanceConflict,
+		},
+		{
+			name:       "Verify that the user is not bound to a Role the claim does not control",
+			objects:    []client.Object{newTemplate(), foreignRole},
+			wantReason: ReasonAccessConflict,
+		},
+		{
+			name:       "Verify that a RoleBinding the claim does not control is left alone",
+			objects:    []client.Object{newTemplate(), foreignBinding},
+			wantReason: ReasonAccessConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			fakeScheme := newAutoscalingScheme(t)
+			claim := newClaim()
+			cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(append(tt.objects, claim)...).
+				WithStatusSubresource(&mcpserverv1.MCPServerClaim{}).Build()
+			r := &MCPServerClaimReconciler{Client: cli, Scheme: fakeScheme}
+
+			if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(claim)}); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if err := cli.Get(ctx, client.ObjectKeyFromObject(claim), claim); err != nil {
+				t.Fatalf("failed to get the claim: %v", err)
+			}
+			available := meta.FindStatusCondition(claim.Status.Conditions, OverallAvailable)
+			if available == nil || available.Status != metav1.ConditionFalse || available.Reason != tt.wantReason {
+				t.Errorf("Available condition = %+v, want False with reason %s", available, tt.wantReason)
+			}
+			binding := &rbacv1.RoleBinding{}
+			err := cli.Get(ctx, client.ObjectKey{Name: "alice-tools-user", Namespace: testNamespace}, binding)
+			if err == nil && len(binding.Subjects) > 0 {
+				t.Errorf("subjects = %+v, want the user not bound", binding.Subjects)
+			}
+		})
+	}
+}
+
+func TestMCPServerClaimReconciler_Reconcile_lifetime(t *testing.T) {
+	ctx := context.Background()
+	fakeScheme := newAutoscalingScheme(t)
+	expired := newClaim()
+	expired.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+	expired.Spec.Lifetime = &metav1.Duration{Duration: time.Hour}
+	cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(newTemplate(), expired).
+		WithStatusSubresource(&mcpserverv1.MCPServerClaim{}).Build()
+	r := &MCPServerClaimReconciler{Client: cli, Scheme: fakeScheme}
+
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(expired)}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	err := cli.Get(ctx, client.ObjectKeyFromObject(expired), &mcpserverv1.MCPServerClaim{})
+	if !k8serr.IsNotFound(err) {
+		t.Errorf("Get() error = %v, want the expired claim deleted", err)
+	}
+
+	active := newClaim()
+	active.Name = "bob-tools"
+	active.Spec.Lifetime = &metav1.Duration{Duration: time.Hour}
+	if err := cli.Create(ctx, active); err != nil {
+		t.Fatalf("failed to create the claim: %v", err)
+	}
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(active)})
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if result.RequeueAfter <= 0 || result.RequeueAfter > time.Hour {
+		t.Errorf("RequeueAfter = %v, want the time left until the claim expires", result.RequeueAfter)
+	}
+}
+
+func TestMCPServerReconciler_Reconcile_template(t *testing.T) {
+	fakeScheme := newAutoscalingScheme(t)
+	template := newTemplate()
+	cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithStatusSubresource(&mcpserverv1.MCPServer{}).
+		WithObjects(template).Build()
+	r := &MCPServerReconciler{Client: cli, Scheme: fakeScheme}
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(template)}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := cli.Get(context.Background(), client.ObjectKeyFromObject(template), template); err != nil {
+		t.Fatalf("failed to get the MCPServer: %v", err)
+	}
+	if len(template.Status.Conditions) != 2 || template.Status.Conditions[0].Reason != ReasonTemplate ||
+		!meta.IsStatusConditionFalse(template.Status.Conditions, Ready) {
+		t.Errorf("status.conditions = %+v, want only Available with reason %s and Ready", template.Status.Conditions, ReasonTemplate)
+	}
+	if reason, degraded := degradedReason(template.Status.Conditions); degraded {
+		t.Errorf("degradedReason() = %s, want a template not to be degraded", reason)
+	}
+}
+
+func TestMCPServerClaimReconciler_Reconcile_templateTTL(t *testing.T) {
+	ctx := context.Background()
+	fakeScheme := newAutoscalingScheme(t)
+	template := newTemplate()
+	template.Spec.MetricsExporter = &mcpserverv1.MetricsExporter{}
+	template.Spec.TTLSecondsAfterLastActivity = ptr.To(int32(600))
+	claim := newClaim()
+	instance := claimInstance(claim, template)
+	if instance.Spec.TTLSecondsAfterLastActivity != nil {
+		t.Errorf("ttlSecondsAfterLastActivity of the instance = %d, want the TTL left to the claim",
+			*instance.Spec.TTLSecondsAfterLastActivity)
+	}
+	instance.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+	instance.Status.Usage = &mcpserverv1.UsageStatus{LastActivityTime: &metav1.Time{Time: time.Now().Add(-time.Hour)}}
+	if err := ctrl.SetControllerReference(claim, instance, fakeScheme); err != nil {
+		t.Fatalf("failed to set the controller reference: %v", err)
+	}
+	cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(template, claim, instance).
+		WithStatusSubresource(&mcpserverv1.MCPServerClaim{}, &mcpserverv1.MCPServer{}).Build()
+	r := &MCPServerClaimReconciler{Client: cli, Scheme: fakeScheme}
+
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(claim)}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	err := cli.Get(ctx, client.ObjectKeyFromObject(claim), &mcpserverv1.MCPServerClaim{})
+	if !k8serr.IsNotFound(err) {
+		t.Errorf("Get() error = %v, want the claim of the idle instance deleted", err)
+	}
+}
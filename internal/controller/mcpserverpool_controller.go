@@ -0,0 +1,378 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/internal/poolrouter"
+)
+
+// +kubebuilder:rbac:groups=mcpserver.opendatahub.io,resources=mcpserverpools,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=mcpserver.opendatahub.io,resources=mcpserverpools/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=mcpserver.opendatahub.io,resources=mcpserverpools/finalizers,verbs=update
+
+const (
+	// poolRouterLabelKey selects the pods of the router of an MCPServerPool.
+	poolRouterLabelKey = "mcpserver.opendatahub.io/pool-router"
+
+	// poolRouterSuffix is the suffix of the name of the router Deployment and Service of an MCPServerPool.
+	poolRouterSuffix = "router"
+
+	// poolRouterPort is the port of the router and its Service, the same as that of the MCPServers it routes to.
+	poolRouterPort = mcpServerPort
+
+	// ReasonRouterNotReady is set on the Available condition of an MCPServerPool whose router has no available
+	// pod.
+	ReasonRouterNotReady = "RouterNotReady"
+
+	// ReasonShardsNotReady is set on the Available condition of an MCPServerPool none of whose MCPServers is
+	// available.
+	ReasonShardsNotReady = "ShardsNotReady"
+)
+
+// MCPServerPoolReconciler reconciles a MCPServerPool object: it keeps spec.shards identical MCPServers with the
+// spec of the template, and runs the router that spreads the sessions of the clients across them.
+type MCPServerPoolReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// OperatorImage runs the router.
+	OperatorImage string
+
+	// Shard selects the pools this replica reconciles. All pools are reconciled when nil.
+	Shard *Shard
+}
+
+// Reconcile creates, updates and removes the MCPServers and the router of the MCPServerPool and reports their
+// readiness in its status.
+func (r *MCPServerPoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	if !r.Shard.Owns(req.NamespacedName) {
+		return ctrl.Result{}, nil
+	}
+	pool := &mcpserverv1.MCPServerPool{}
+	if err := r.Get(ctx, req.NamespacedName, pool); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	// The MCPServers and the router are removed with the pool by the garbage collector.
+	if !pool.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	shards, err := r.reconcileShards(ctx, pool)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.reconcileRouter(ctx, pool, shards); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	router := &appsv1.Deployment{}
+	err = r.Get(ctx, client.ObjectKey{Name: poolRouterName(pool), Namespace: pool.Namespace}, router)
+	if err != nil && !k8serr.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+
+	original := pool.DeepCopy()
+	pool.Status.Shards = int32(len(shards))
+	pool.Status.ReadyShards = 0
+	for _, shard := range shards {
+		if meta.IsStatusConditionTrue(shard.Status.Conditions, OverallAvailable) {
+			pool.Status.ReadyShards++
+		}
+	}
+	pool.Status.URL = poolURL(pool)
+	meta.SetStatusCondition(&pool.Status.Conditions, poolCondition(pool, router))
+	if equality.Semantic.DeepEqual(original.Status, pool.Status) {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{}, r.Status().Patch(ctx, pool, client.MergeFrom(original))
+}
+
+// poolShardName returns the name of the MCPServer with index i of pool.
+func poolShardName(pool *mcpserverv1.MCPServerPool, i int) string {
+	return pool.Name + "-" + strconv.Itoa(i)
+}
+
+// poolRouterName returns the name of the router Deployment and Service of pool.
+func poolRouterName(pool *mcpserverv1.MCPServerPool) string {
+	return shortenedName(pool.Name, poolRouterSuffix)
+}
+
+// poolURL returns the in-cluster URL of the router of pool, under the path its MCPServers serve MCP at.
+func poolURL(pool *mcpserverv1.MCPServerPool) string {
+	path := pool.Spec.Template.Spec.BasePath
+	if path == "" {
+		path = mcpServerSSEPath
+	}
+	return fmt.Sprintf("http://%s.%s.svc:%d%s", poolRouterName(pool), pool.Namespace, poolRouterPort, path)
+}
+
+// poolShard returns the MCPServer with index i of pool.
+func poolShard(pool *mcpserverv1.MCPServerPool, i int) *mcpserverv1.MCPServer {
+	labels := map[string]string{}
+	for key, value := range pool.Spec.Template.Labels {
+		labels[key] = value
+	}
+	labels[mcpserverv1.PoolLabel] = pool.Name
+	return &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      poolShardName(pool, i),
+			Namespace: pool.Namespace,
+			Labels:    labels,
+		},
+		Spec: *pool.Spec.Template.Spec.DeepCopy(),
+	}
+}
+
+// reconcileShards creates the MCPServers of pool, gives them the spec and labels of the template and removes
+// those beyond spec.shards. It returns the MCPServers of the pool, in the order of their index. An MCPServer
+// with the name of a shard that the pool does not control is left alone and not routed to.
+func (r *MCPServerPoolReconciler) reconcileShards(ctx context.Context, pool *mcpserverv1.MCPServerPool) ([]*mcpserverv1.MCPServer, error) {
+	var shards []*mcpserverv1.MCPServer
+	wanted := map[string]bool{}
+	for i := 0; i < int(pool.Spec.Shards); i++ {
+		desired := poolShard(pool, i)
+		wanted[desired.Name] = true
+		if err := ctrl.SetControllerReference(pool, desired, r.Scheme); err != nil {
+			return nil, err
+		}
+		if err := r.Create(ctx, desired); err == nil {
+			shards = append(shards, desired)
+			continue
+		} else if !k8serr.IsAlreadyExists(err) {
+			return nil, err
+		}
+
+		existing := &mcpserverv1.MCPServer{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(desired), existing); err != nil {
+			return nil, err
+		}
+		if !metav1.IsControlledBy(existing, pool) {
+			logf.FromContext(ctx).Info("MCPServer is not controlled by the pool, skipping it", "name", existing.Name)
+			continue
+		}
+		shards = append(shards, existing)
+		labelsMatch := true
+		for key, value := range desired.Labels {
+			if existing.Labels[key] != value {
+				labelsMatch = false
+			}
+		}
+		if labelsMatch && equality.Semantic.DeepEqual(existing.Spec, desired.Spec) {
+			continue
+		}
+		original := existing.DeepCopy()
+		if existing.Labels == nil {
+			existing.Labels = map[string]string{}
+		}
+		for key, value := range desired.Labels {
+			existing.Labels[key] = value
+		}
+		existing.Spec = desired.Spec
+		if err := r.Patch(ctx, existing, client.MergeFrom(original)); err != nil {
+			return nil, err
+		}
+	}
+
+	list := &mcpserverv1.MCPServerList{}
+	if err := r.List(ctx, list, client.InNamespace(pool.Namespace),
+		client.MatchingLabels{mcpserverv1.PoolLabel: pool.Name}); err != nil {
+		return nil, err
+	}
+	for i := range list.Items {
+		shard := &list.Items[i]
+		if wanted[shard.Name] || !metav1.IsControlledBy(shard, pool) {
+			continue
+		}
+		if err := r.Delete(ctx, shard); err != nil && !k8serr.IsNotFound(err) {
+			return nil, err
+		}
+	}
+	return shards, nil
+}
+
+// routedShards returns the shards the router of a pool spreads the sessions across: those that are available, or
+// all of them while none is, as the router needs at least one backend.
+func routedShards(shards []*mcpserverv1.MCPServer) []*mcpserverv1.MCPServer {
+	var available []*mcpserverv1.MCPServer
+	for _, shard := range shards {
+		if meta.IsStatusConditionTrue(shard.Status.Conditions, OverallAvailable) {
+			available = append(available, shard)
+		}
+	}
+	if len(available) == 0 {
+		return shards
+	}
+	return available
+}
+
+// poolRouter returns the Deployment of the router of pool, which routes to the routedShards of shards.
+func (r *MCPServerPoolReconciler) poolRouter(pool *mcpserverv1.MCPServerPool, shards []*mcpserverv1.MCPServer) *appsv1.Deployment {
+	labels := map[string]string{poolRouterLabelKey: poolRouterName(pool)}
+	args := []string{"--port", strconv.Itoa(poolRouterPort)}
+	for _, shard := range routedShards(shards) {
+		args = append(args, "--backend", fmt.Sprintf("http://%s.%s.svc:%d", resourceName(shard), shard.Namespace, mcpServerPort))
+	}
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      poolRouterName(pool),
+			Namespace: pool.Namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To(ptr.Deref(pool.Spec.RouterReplicas, 1)),
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					AutomountServiceAccountToken: ptr.To(false),
+					Containers: []corev1.Container{{
+						Name:    "router",
+						Image:   r.OperatorImage,
+						Command: []string{"/manager", poolrouter.Command},
+						Args:    args,
+						Ports: []corev1.ContainerPort{{
+							ContainerPort: poolRouterPort,
+							Name:          "http",
+						}},
+						ReadinessProbe: &corev1.Probe{
+							ProbeHandler: corev1.ProbeHandler{
+								TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromString("http")},
+							},
+						},
+						SecurityContext: &corev1.SecurityContext{
+							AllowPrivilegeEscalation: ptr.To(false),
+							Capabilities: &corev1.Capabilities{
+								Drop: []corev1.Capability{"ALL"},
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// reconcileRouter creates or updates the Deployment and Service of the router of pool. The router is rolled out
+// again whenever the shards or their availability change.
+func (r *MCPServerPoolReconciler) reconcileRouter(ctx context.Context, pool *mcpserverv1.MCPServerPool, shards []*mcpserverv1.MCPServer) error {
+	if r.OperatorImage == "" {
+		return fmt.Errorf("the operator image is not configured, unable to deploy the router of %s", pool.Name)
+	}
+
+	desired := r.poolRouter(pool, shards)
+	if err := ctrl.SetControllerReference(pool, desired, r.Scheme); err != nil {
+		return err
+	}
+	if err := r.Create(ctx, desired); err != nil && !k8serr.IsAlreadyExists(err) {
+		return err
+	}
+	existing := &appsv1.Deployment{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(desired), existing); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if metav1.IsControlledBy(existing, pool) && len(existing.Spec.Template.Spec.Containers) > 0 {
+		original := existing.DeepCopy()
+		existing.Spec.Replicas = desired.Spec.Replicas
+		container := &existing.Spec.Template.Spec.Containers[0]
+		container.Image = desired.Spec.Template.Spec.Containers[0].Image
+		container.Args = desired.Spec.Template.Spec.Containers[0].Args
+		if !equality.Semantic.DeepEqual(original.Spec, existing.Spec) {
+			logChildDiff(ctx, original, existing)
+			if err := r.Patch(ctx, existing, client.MergeFrom(original)); err != nil {
+				return err
+			}
+		}
+	}
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      poolRouterName(pool),
+			Namespace: pool.Namespace,
+			Labels:    desired.Labels,
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: desired.Spec.Selector.MatchLabels,
+			Ports: []corev1.ServicePort{{
+				Name:       "http",
+				Port:       poolRouterPort,
+				TargetPort: intstr.FromString("http"),
+				Protocol:   corev1.ProtocolTCP,
+			}},
+		},
+	}
+	if err := ctrl.SetControllerReference(pool, service, r.Scheme); err != nil {
+		return err
+	}
+	if err := r.Create(ctx, service); err != nil && !k8serr.IsAlreadyExists(err) {
+		return err
+	}
+	existingService := &corev1.Service{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(service), existingService); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(existingService, pool) {
+		return nil
+	}
+	originalService := existingService.DeepCopy()
+	existingService.Spec.Selector = service.Spec.Selector
+	existingService.Spec.Ports = service.Spec.Ports
+	if equality.Semantic.DeepEqual(originalService.Spec, existingService.Spec) {
+		return nil
+	}
+	logChildDiff(ctx, originalService, existingService)
+	return r.Patch(ctx, existingService, client.MergeFrom(originalService))
+}
+
+// poolCondition returns the Available condition of pool with the router Deployment. The pool is available when
+// the router and at least one of its MCPServers are.
+func poolCondition(pool *mcpserverv1.MCPServerPool, router *appsv1.Deployment) metav1.Condition {
+	switch {
+	case router.Status.AvailableReplicas == 0:
+		return metav1.Condition{
+			Type:    OverallAvailable,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonRouterNotReady,
+			Message: fmt.Sprintf("Router %s has no available pod", poolRouterName(pool)),
+		}
+	case pool.Status.ReadyShards == 0:
+		return metav1.Condition{
+			Type:    OverallAvailable,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonShardsNotReady,
+			Message: fmt.Sprintf("None of the %d MCPServers of the pool is available", pool.Status.Shards),
+		}
+	}
+	return metav1.Condition{
+		Type:    OverallAvailable,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonAsExpected,
+		Message: fmt.Sprintf("%d of %d MCPServers of the pool are available", pool.Status.ReadyShards, pool.Status.Shards),
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *MCPServerPoolReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&mcpserverv1.MCPServerPool{}).
+		Owns(&mcpserverv1.MCPServer{}).
+		Owns(&appsv1.Deployment{}).
+		Owns(&corev1.Service{}).
+		Named("mcpserverpool").
+		Complete(r)
+}
@@ -0,0 +1,176 @@
+package controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// newPool returns an MCPServerPool named tools with shards MCPServers.
+func newPool(shards int32) *mcpserverv1.MCPServerPool {
+	return &mcpserverv1.MCPServerPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "tools", Namespace: testNamespace, UID: "pool-uid"},
+		Spec: mcpserverv1.MCPServerPoolSpec{
+			Shards: shards,
+			Template: mcpserverv1.MCPServerTemplate{
+				Labels: map[string]string{"team": "a"},
+				Spec:   mcpserverv1.MCPServerSpec{Image: "quay.io/example/tools:1"},
+			},
+		},
+	}
+}
+
+func TestMCPServerPoolReconciler_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	fakeScheme := newAutoscalingScheme(t)
+	pool := newPool(3)
+	cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(pool).
+		WithStatusSubresource(&mcpserverv1.MCPServerPool{}, &mcpserverv1.MCPServer{}, &appsv1.Deployment{}).Build()
+	r := &MCPServerPoolReconciler{Client: cli, Scheme: fakeScheme, OperatorImage: "quay.io/example/operator:1"}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: pool.Name, Namespace: testNamespace}}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	for _, name := range []string{"tools-0", "tools-1", "tools-2"} {
+		shard := &mcpserverv1.MCPServer{}
+		if err := cli.Get(ctx, client.ObjectKey{Name: name, Namespace: testNamespace}, shard); err != nil {
+			t.Fatalf("failed to get the MCPServer %s: %v", name, err)
+		}
+		if shard.Labels["team"] != "a" || shard.Labels[mcpserverv1.PoolLabel] != pool.Name {
+			t.Errorf("labels of %s = %v, want the template labels and the pool label", name, shard.Labels)
+		}
+		if shard.Spec.Image != "quay.io/example/tools:1" {
+			t.Errorf("image of %s = %q, want the image of the template", name, shard.Spec.Image)
+		}
+	}
+
+	router := &appsv1.Deployment{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: "tools-router", Namespace: testNamespace}, router); err != nil {
+		t.Fatalf("failed to get the router Deployment: %v", err)
+	}
+	// No shard is available yet, so the router routes to all of them.
+	wantArgs := []string{"--port", "8000",
+		"--backend", "http://tools-0.test-namespace.svc:8000",
+		"--backend", "http://tools-1.test-namespace.svc:8000",
+		"--backend", "http://tools-2.test-namespace.svc:8000",
+	}
+	if got := router.Spec.Template.Spec.Containers[0].Args; !reflect.DeepEqual(got, wantArgs) {
+		t.Errorf("router args = %v, want %v", got, wantArgs)
+	}
+	service := &corev1.Service{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: "tools-router", Namespace: testNamespace}, service); err != nil {
+		t.Fatalf("failed to get the router Service: %v", err)
+	}
+	if err := cli.Get(ctx, req.NamespacedName, pool); err != nil {
+		t.Fatalf("failed to get the MCPServerPool: %v", err)
+	}
+	available := meta.FindStatusCondition(pool.Status.Conditions, OverallAvailable)
+	if available == nil || available.Reason != ReasonRouterNotReady {
+		t.Errorf("Available condition = %+v, want False with reason %s", available, ReasonRouterNotReady)
+	}
+	if want := "http://tools-router.test-namespace.svc:8000/sse"; pool.Status.URL != want {
+		t.Errorf("status.url = %q, want %q", pool.Status.URL, want)
+	}
+
+	// The router and one of the shards become available, and the pool is scaled down to two shards.
+	router.Status.AvailableReplicas = 1
+	if err := cli.Status().Update(ctx, router); err != nil {
+		t.Fatalf("failed to update the router status: %v", err)
+	}
+	shard := &mcpserverv1.MCPServer{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: "tools-0", Namespace: testNamespace}, shard); err != nil {
+		t.Fatalf("failed to get the MCPServer: %v", err)
+	}
+	meta.SetStatusCondition(&shard.Status.Conditions,
+		metav1.Condition{Type: OverallAvailable, Status: metav1.ConditionTrue, Reason: ReasonAsExpected})
+	if err := cli.Status().Update(ctx, shard); err != nil {
+		t.Fatalf("failed to update the MCPServer status: %v", err)
+	}
+	// A router Service changed by hand is restored.
+	service.Spec.Ports[0].Port = 9000
+	if err := cli.Update(ctx, service); err != nil {
+		t.Fatalf("failed to update the router Service: %v", err)
+	}
+	pool.Spec.Shards = 2
+	if err := cli.Update(ctx, pool); err != nil {
+		t.Fatalf("failed to update the MCPServerPool: %v", err)
+	}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := cli.Get(ctx, client.ObjectKey{Name: "tools-2", Namespace: testNamespace}, &mcpserverv1.MCPServer{}); err == nil {
+		t.Errorf("MCPServer tools-2 still exists, want it removed with the shard")
+	}
+	if err := cli.Get(ctx, client.ObjectKey{Name: "tools-router", Namespace: testNamespace}, router); err != nil {
+		t.Fatalf("failed to get the router Deployment: %v", err)
+	}
+	wantArgs = []string{"--port", "8000", "--backend", "http://tools-0.test-namespace.svc:8000"}
+	if got := router.Spec.Template.Spec.Containers[0].Args; !reflect.DeepEqual(got, wantArgs) {
+		t.Errorf("router args = %v, want only the available shard %v", got, wantArgs)
+	}
+	if err := cli.Get(ctx, client.ObjectKey{Name: "tools-router", Namespace: testNamespace}, service); err != nil {
+		t.Fatalf("failed to get the router Service: %v", err)
+	}
+	if got := service.Spec.Ports[0].Port; got != 8000 {
+		t.Errorf("router Service port = %d, want 8000", got)
+	}
+	if err := cli.Get(ctx, req.NamespacedName, pool); err != nil {
+		t.Fatalf("failed to get the MCPServerPool: %v", err)
+	}
+	if pool.Status.Shards != 2 || pool.Status.ReadyShards != 1 {
+		t.Errorf("status = %+v, want 2 shards and 1 ready", pool.Status)
+	}
+	if !meta.IsStatusConditionTrue(pool.Status.Conditions, OverallAvailable) {
+		t.Errorf("conditions = %+v, want the pool available", pool.Status.Conditions)
+	}
+}
+
+func TestMCPServerPoolReconciler_reconcileShards(t *testing.T) {
+	ctx := context.Background()
+	fakeScheme := newAutoscalingScheme(t)
+	pool := newPool(2)
+	handMade := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{Name: "tools-1", Namespace: testNamespace},
+		Spec:       mcpserverv1.MCPServerSpec{Image: "quay.io/example/other:1"},
+	}
+	drifted := poolShard(pool, 0)
+	drifted.Spec.Image = "quay.io/example/tools:0"
+	if err := ctrl.SetControllerReference(pool, drifted, fakeScheme); err != nil {
+		t.Fatalf("failed to set the controller reference: %v", err)
+	}
+	cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(pool, handMade, drifted).Build()
+	r := &MCPServerPoolReconciler{Client: cli, Scheme: fakeScheme, OperatorImage: "quay.io/example/operator:1"}
+
+	shards, err := r.reconcileShards(ctx, pool)
+	if err != nil {
+		t.Fatalf("reconcileShards() error = %v", err)
+	}
+	if len(shards) != 1 || shards[0].Name != "tools-0" {
+		t.Fatalf("reconcileShards() = %v, want only the shard the pool controls", shards)
+	}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(drifted), drifted); err != nil {
+		t.Fatalf("failed to get the MCPServer: %v", err)
+	}
+	if drifted.Spec.Image != "quay.io/example/tools:1" {
+		t.Errorf("image of tools-0 = %q, want the image of the template", drifted.Spec.Image)
+	}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(handMade), handMade); err != nil {
+		t.Fatalf("failed to get the MCPServer: %v", err)
+	}
+	if handMade.Spec.Image != "quay.io/example/other:1" {
+		t.Errorf("image of tools-1 = %q, want the MCPServer the pool does not control left alone", handMade.Spec.Image)
+	}
+}
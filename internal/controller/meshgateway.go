@@ -0,0 +1,172 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster/gvk"
+)
+
+// +kubebuilder:rbac:groups="networking.istio.io",resources=virtualservices,verbs=create;get;list;watch;update;patch;delete
+
+const (
+	// VirtualServiceAvailable reports whether the VirtualService that publishes an MCP server with a meshGateway
+	// on the mesh gateway exists. It replaces RouteAvailable for these servers.
+	VirtualServiceAvailable = "VirtualServiceAvailable"
+
+	// ReasonMeshNotInstalled is set on the VirtualServiceAvailable condition when the cluster does not serve
+	// VirtualServices.
+	ReasonMeshNotInstalled = "MeshNotInstalled"
+)
+
+// usesMeshGateway reports whether the MCP server is published under a path of a shared mesh gateway host
+// rather than its own Route.
+func usesMeshGateway(cr *mcpserverv1.MCPServer) bool {
+	return cr.Spec.MeshGateway != nil
+}
+
+// virtualServiceAPIAvailable reports whether a service mesh serving VirtualServices is installed.
+func (r *MCPServerReconciler) virtualServiceAPIAvailable() bool {
+	return r.Platform != nil && r.Platform.HasAPI(gvk.VirtualService)
+}
+
+// meshGatewayPath returns the path prefix cr is published under on the mesh gateway, without a trailing slash.
+func meshGatewayPath(cr *mcpserverv1.MCPServer) string {
+	path := strings.TrimRight(cr.Spec.MeshGateway.Path, "/")
+	if path == "" {
+		path = fmt.Sprintf("/mcp/%s/%s", cr.Namespace, cr.Name)
+	}
+	return path
+}
+
+// meshGatewayURL returns the URL of the MCP endpoint of cr on the mesh gateway, which is assumed to terminate
+// TLS like most shared API hosts.
+func meshGatewayURL(cr *mcpserverv1.MCPServer) string {
+	return fmt.Sprintf("https://%s%s%s", cr.Spec.MeshGateway.Host, meshGatewayPath(cr), mcpServerPath(cr))
+}
+
+// newVirtualService returns the VirtualService that routes the path of cr on the mesh gateway host to the
+// Service of cr, removing the path prefix on the way.
+func newVirtualService(cr *mcpserverv1.MCPServer) *unstructured.Unstructured {
+	path := meshGatewayPath(cr)
+	virtualService := &unstructured.Unstructured{Object: map[string]any{
+		"spec": map[string]any{
+			"hosts":    []any{cr.Spec.MeshGateway.Host},
+			"gateways": []any{cr.Spec.MeshGateway.Gateway},
+			"http": []any{map[string]any{
+				"match": []any{
+					map[string]any{"uri": map[string]any{"prefix": path + "/"}},
+					map[string]any{"uri": map[string]any{"exact": path}},
+				},
+				"rewrite": map[string]any{"uri": "/"},
+				"route": []any{map[string]any{
+					"destination": map[string]any{
+						"host": fmt.Sprintf("%s.%s.svc.cluster.local", resourceName(cr), cr.Namespace),
+						"port": map[string]any{"number": int64(8000)},
+					},
+				}},
+			}},
+		},
+	}}
+	virtualService.SetGroupVersionKind(gvk.VirtualService)
+	virtualService.SetName(resourceName(cr))
+	virtualService.SetNamespace(cr.Namespace)
+	virtualService.SetLabels(map[string]string{mcpServerAppLabelKey: resourceName(cr)})
+	return virtualService
+}
+
+// reconcileVirtualService creates the VirtualService of cr and keeps its routing rule up to date. Nothing is
+// created when no service mesh is installed, which the VirtualServiceAvailable condition reports.
+func (r *MCPServerReconciler) reconcileVirtualService(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) error {
+	if !r.virtualServiceAPIAvailable() {
+		return nil
+	}
+
+	desired := newVirtualService(cr)
+	if err := r.createChild(ctx, cli, cr, desired.DeepCopy()); err != nil {
+		return err
+	}
+
+	virtualService := &unstructured.Unstructured{}
+	virtualService.SetGroupVersionKind(gvk.VirtualService)
+	err := cli.Get(ctx, client.ObjectKey{Name: resourceName(cr), Namespace: cr.Namespace}, virtualService)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	spec, _, _ := unstructured.NestedMap(desired.Object, "spec")
+	current, _, _ := unstructured.NestedMap(virtualService.Object, "spec")
+	if equality.Semantic.DeepEqual(current, spec) {
+		return nil
+	}
+	original := virtualService.DeepCopy()
+	if err := unstructured.SetNestedMap(virtualService.Object, spec, "spec"); err != nil {
+		return err
+	}
+	logChildDiff(ctx, original, virtualService)
+	return cli.Patch(ctx, virtualService, client.MergeFrom(original))
+}
+
+// deleteVirtualService removes the VirtualService of an MCP server that no longer uses a mesh gateway. It only
+// exists when the VirtualServiceAvailable condition was reported.
+func (r *MCPServerReconciler) deleteVirtualService(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) error {
+	if meta.FindStatusCondition(cr.Status.Conditions, VirtualServiceAvailable) == nil || !r.virtualServiceAPIAvailable() {
+		return nil
+	}
+	virtualService := &unstructured.Unstructured{}
+	virtualService.SetGroupVersionKind(gvk.VirtualService)
+	virtualService.SetName(resourceName(cr))
+	virtualService.SetNamespace(cr.Namespace)
+	return r.deleteChild(ctx, cli, cr, virtualService)
+}
+
+// getVirtualServiceCondition returns the VirtualServiceAvailable condition of cr.
+func (r *MCPServerReconciler) getVirtualServiceCondition(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) metav1.Condition {
+	if !r.virtualServiceAPIAvailable() {
+		return metav1.Condition{
+			Type:    VirtualServiceAvailable,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonMeshNotInstalled,
+			Message: "The cluster does not serve VirtualServices, install a service mesh to use meshGateway",
+		}
+	}
+
+	virtualService := &unstructured.Unstructured{}
+	virtualService.SetGroupVersionKind(gvk.VirtualService)
+	err := cli.Get(ctx, client.ObjectKey{Name: resourceName(cr), Namespace: cr.Namespace}, virtualService)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return metav1.Condition{
+				Type:    VirtualServiceAvailable,
+				Status:  metav1.ConditionFalse,
+				Reason:  fmt.Sprintf("%s%s", "VirtualService", ReasonNotFoundSuffix),
+				Message: fmt.Sprintf("VirtualService %s not found", resourceName(cr)),
+			}
+		}
+		return metav1.Condition{
+			Type:    VirtualServiceAvailable,
+			Status:  metav1.ConditionUnknown,
+			Reason:  fmt.Sprintf("%s%s", "VirtualService", ReasonGetFailedSuffix),
+			Message: fmt.Sprintf("Failed to get VirtualService %s: %v", resourceName(cr), err),
+		}
+	}
+	return metav1.Condition{
+		Type:   VirtualServiceAvailable,
+		Status: metav1.ConditionTrue,
+		Reason: fmt.Sprintf("%s%s", "VirtualService", ReasonReadySuffix),
+		Message: fmt.Sprintf("VirtualService %s publishes the server at %s%s on gateway %s", resourceName(cr),
+			cr.Spec.MeshGateway.Host, meshGatewayPath(cr), cr.Spec.MeshGateway.Gateway),
+	}
+}
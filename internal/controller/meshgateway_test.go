@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster/gvk"
+)
+
+func TestMCPServerReconciler_reconcileVirtualService(t *testing.T) {
+	discovery := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{{
+		GroupVersion: gvk.VirtualService.GroupVersion().String(),
+		APIResources: []metav1.APIResource{{Name: "virtualservices", Kind: gvk.VirtualService.Kind}},
+	}}}}
+	scheme := newGatewayScheme(t)
+	cli := fake.NewClientBuilder().WithScheme(scheme).Build()
+	r := &MCPServerReconciler{
+		Client:   cli,
+		Scheme:   scheme,
+		Platform: &cluster.Platform{Name: cluster.Kubernetes, APIs: gvk.NewAvailability(discovery)},
+	}
+
+	cr := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+		Spec: mcpserverv1.MCPServerSpec{
+			Image: mcpServerImage,
+			MeshGateway: &mcpserverv1.MeshGateway{
+				Gateway: "istio-system/api-gateway",
+				Host:    "api.example.com",
+			},
+		},
+	}
+	if err := r.reconcileExposure(context.Background(), cli, cr); err != nil {
+		t.Fatalf("reconcileExposure() error = %v", err)
+	}
+	if got, want := meshGatewayURL(cr), "https://api.example.com/mcp/"+testNamespace+"/"+mcpServerName+"/sse"; got != want {
+		t.Errorf("meshGatewayURL() = %s, want %s", got, want)
+	}
+
+	// Moving the server to another path updates the existing VirtualService.
+	cr.Spec.MeshGateway.Path = "/mcp/payments/"
+	if err := r.reconcileExposure(context.Background(), cli, cr); err != nil {
+		t.Fatalf("reconcileExposure() error = %v", err)
+	}
+
+	virtualService := &unstructured.Unstructured{}
+	virtualService.SetGroupVersionKind(gvk.VirtualService)
+	if err := cli.Get(context.Background(), client.ObjectKey{Name: mcpServerName, Namespace: testNamespace}, virtualService); err != nil {
+		t.Fatalf("failed to get the VirtualService: %v", err)
+	}
+	gateways, _, _ := unstructured.NestedStringSlice(virtualService.Object, "spec", "gateways")
+	hosts, _, _ := unstructured.NestedStringSlice(virtualService.Object, "spec", "hosts")
+	if len(gateways) != 1 || gateways[0] != "istio-system/api-gateway" || len(hosts) != 1 || hosts[0] != "api.example.com" {
+		t.Errorf("VirtualService gateways = %v and hosts = %v, want the mesh gateway host", gateways, hosts)
+	}
+	rules, _, _ := unstructured.NestedSlice(virtualService.Object, "spec", "http")
+	if len(rules) != 1 {
+		t.Fatalf("VirtualService http rules = %v, want 1", rules)
+	}
+	prefix, _, _ := unstructured.NestedString(rules[0].(map[string]any)["match"].([]any)[0].(map[string]any), "uri", "prefix")
+	if prefix != "/mcp/payments/" {
+		t.Errorf("VirtualService prefix = %s, want /mcp/payments/", prefix)
+	}
+	if condition := r.getVirtualServiceCondition(context.Background(), cli, cr); condition.Status != metav1.ConditionTrue {
+		t.Errorf("VirtualServiceAvailable = %v, want True", condition)
+	}
+
+	// Leaving the mesh gateway removes the VirtualService.
+	cr.Spec.MeshGateway = nil
+	cr.Status.Conditions = []metav1.Condition{{Type: VirtualServiceAvailable, Status: metav1.ConditionTrue}}
+	if err := r.reconcileExposure(context.Background(), cli, cr); err != nil {
+		t.Fatalf("reconcileExposure() error = %v", err)
+	}
+	if err := cli.Get(context.Background(), client.ObjectKeyFromObject(virtualService), virtualService); err == nil {
+		t.Errorf("the VirtualService still exists")
+	}
+}
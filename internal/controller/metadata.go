@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"context"
+	"maps"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// podTemplateMetadata returns the labels and annotations of spec.podTemplateMetadata, or nil for each that is unset.
+func podTemplateMetadata(cr *mcpserverv1.MCPServer) (labels, annotations map[string]string) {
+	if cr.Spec.PodTemplateMetadata == nil {
+		return nil, nil
+	}
+	return cr.Spec.PodTemplateMetadata.Labels, cr.Spec.PodTemplateMetadata.Annotations
+}
+
+// withCustomMetadata adds spec.labels and spec.annotations of cr to obj, except for the annotations in managed,
+// which the operator sets on obj itself. It reports whether obj changed. The label map of obj is replaced rather
+// than modified, as it may be shared with a selector.
+func withCustomMetadata(cr *mcpserverv1.MCPServer, obj metav1.Object, managed ...string) bool {
+	custom := maps.Clone(cr.Spec.Annotations)
+	for _, key := range managed {
+		delete(custom, key)
+	}
+	labels, labelsChanged := withEntries(obj.GetLabels(), cr.Spec.Labels)
+	annotations, annotationsChanged := withEntries(obj.GetAnnotations(), custom)
+	if labelsChanged {
+		obj.SetLabels(labels)
+	}
+	if annotationsChanged {
+		obj.SetAnnotations(annotations)
+	}
+	return labelsChanged || annotationsChanged
+}
+
+// withEntries returns a copy of m with the entries of entries set, and whether it differs from m.
+func withEntries(m, entries map[string]string) (map[string]string, bool) {
+	changed := false
+	for key, value := range entries {
+		if current, ok := m[key]; !ok || current != value {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return m, false
+	}
+	m = maps.Clone(m)
+	if m == nil {
+		m = map[string]string{}
+	}
+	maps.Copy(m, entries)
+	return m, true
+}
+
+// reconcileCustomMetadata patches spec.labels and spec.annotations of cr onto the existing child obj, which only
+// needs its name and namespace set, except for the annotations in managed and those that are ignored. Children
+// that are not controlled by cr are left alone.
+func reconcileCustomMetadata(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer, obj client.Object,
+	managed ...string) error {
+	if len(cr.Spec.Labels) == 0 && len(cr.Spec.Annotations) == 0 {
+		return nil
+	}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+		// A child that was only just created is not in the cache yet, and already has the metadata.
+		if k8serr.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	original, ok := obj.DeepCopyObject().(client.Object)
+	if !ok || !metav1.IsControlledBy(obj, cr) || !withCustomMetadata(cr, obj, managed...) {
+		return nil
+	}
+	keepIgnoredAnnotations(cr, original, obj)
+	if equality.Semantic.DeepEqual(original.GetLabels(), obj.GetLabels()) &&
+		equality.Semantic.DeepEqual(original.GetAnnotations(), obj.GetAnnotations()) {
+		return nil
+	}
+	logChildDiff(ctx, original, obj)
+	return cli.Patch(ctx, obj, client.MergeFrom(original))
+}
+
+// applyPodTemplateMetadata adds spec.labels and the labels and annotations of spec.podTemplateMetadata to the pod
+// template of an existing Deployment, so that changing them rolls out the Deployment. The labels and annotations
+// the operator sets on the pods are applied afterwards and take precedence.
+func applyPodTemplateMetadata(deployment *appsv1.Deployment, cr *mcpserverv1.MCPServer) {
+	podLabels, podAnnotations := podTemplateMetadata(cr)
+	template := &deployment.Spec.Template
+	if labels, changed := withEntries(template.Labels, cr.Spec.Labels); changed {
+		template.Labels = labels
+	}
+	if labels, changed := withEntries(template.Labels, podLabels); changed {
+		template.Labels = labels
+	}
+	if annotations, changed := withEntries(template.Annotations, podAnnotations); changed {
+		template.Annotations = annotations
+	}
+}
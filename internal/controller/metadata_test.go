@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster"
+)
+
+func Test_withCustomMetadata(t *testing.T) {
+	selector := map[string]string{mcpServerAppLabelKey: mcpServerName}
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Labels: selector, Annotations: map[string]string{"managed": "operator"}},
+		Spec:       corev1.ServiceSpec{Selector: selector},
+	}
+	cr := &mcpserverv1.MCPServer{Spec: mcpserverv1.MCPServerSpec{
+		Labels:      map[string]string{"cost-center": "1234"},
+		Annotations: map[string]string{"managed": "user", "example.com/contact": "payments"},
+	}}
+
+	if !withCustomMetadata(cr, service, "managed") {
+		t.Fatal("withCustomMetadata() = false, want the metadata added")
+	}
+	wantLabels := map[string]string{mcpServerAppLabelKey: mcpServerName, "cost-center": "1234"}
+	if !reflect.DeepEqual(service.Labels, wantLabels) {
+		t.Errorf("labels = %v, want %v", service.Labels, wantLabels)
+	}
+	if !reflect.DeepEqual(service.Spec.Selector, selector) || len(selector) != 1 {
+		t.Errorf("selector = %v, want it unchanged", service.Spec.Selector)
+	}
+	wantAnnotations := map[string]string{"managed": "operator", "example.com/contact": "payments"}
+	if !reflect.DeepEqual(service.Annotations, wantAnnotations) {
+		t.Errorf("annotations = %v, want %v", service.Annotations, wantAnnotations)
+	}
+	if withCustomMetadata(cr, service, "managed") {
+		t.Error("withCustomMetadata() = true, want no change for metadata already set")
+	}
+}
+
+func TestMCPServerReconciler_Reconcile_customMetadata(t *testing.T) {
+	fakeScheme := newAutoscalingScheme(t)
+	cr := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+		Spec: mcpserverv1.MCPServerSpec{
+			Image:       mcpServerImage,
+			Labels:      map[string]string{"cost-center": "1234"},
+			Annotations: map[string]string{"example.com/contact": "payments"},
+			PodTemplateMetadata: &mcpserverv1.PodTemplateMetadata{
+				Labels:      map[string]string{"cost-center": "5678"},
+				Annotations: map[string]string{"sidecar.istio.io/inject": "true"},
+			},
+		},
+	}
+	cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithStatusSubresource(&mcpserverv1.MCPServer{}).
+		WithObjects(cr).Build()
+	r := &MCPServerReconciler{Client: cli, Scheme: fakeScheme, Platform: &cluster.Platform{Name: cluster.Kubernetes}}
+	ctx := context.Background()
+	reconcile := func() {
+		t.Helper()
+		if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cr)}); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		if err := cli.Get(ctx, client.ObjectKeyFromObject(cr), cr); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+	}
+	key := client.ObjectKey{Name: mcpServerName, Namespace: testNamespace}
+
+	reconcile()
+	deployment := &appsv1.Deployment{}
+	if err := cli.Get(ctx, key, deployment); err != nil {
+		t.Fatalf("Get() Deployment error = %v", err)
+	}
+	if deployment.Labels["cost-center"] != "1234" || deployment.Annotations["example.com/contact"] != "payments" {
+		t.Errorf("Deployment metadata = %v %v, want the labels and annotations of the MCPServer",
+			deployment.Labels, deployment.Annotations)
+	}
+	if len(deployment.Spec.Selector.MatchLabels) != 1 {
+		t.Errorf("Deployment selector = %v, want only the label of the MCP server", deployment.Spec.Selector.MatchLabels)
+	}
+	template := deployment.Spec.Template
+	if template.Labels["cost-center"] != "5678" || template.Labels[mcpServerAppLabelKey] != mcpServerName {
+		t.Errorf("pod labels = %v, want the labels of podTemplateMetadata and of the MCP server", template.Labels)
+	}
+	if template.Annotations["sidecar.istio.io/inject"] != "true" {
+		t.Errorf("pod annotations = %v, want the annotations of podTemplateMetadata", template.Annotations)
+	}
+	service := &corev1.Service{}
+	if err := cli.Get(ctx, key, service); err != nil {
+		t.Fatalf("Get() Service error = %v", err)
+	}
+	if service.Labels["cost-center"] != "1234" || len(service.Spec.Selector) != 1 {
+		t.Errorf("Service labels = %v, selector = %v, want the labels of the MCPServer and only the label of the "+
+			"MCP server in the selector", service.Labels, service.Spec.Selector)
+	}
+
+	// Changed metadata is applied to the existing resources.
+	cr.Spec.Labels["cost-center"] = "9999"
+	cr.Spec.PodTemplateMetadata.Annotations["vault.hashicorp.com/agent-inject"] = "true"
+	if err := cli.Update(ctx, cr); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	reconcile()
+	if err := cli.Get(ctx, key, deployment); err != nil {
+		t.Fatalf("Get() Deployment error = %v", err)
+	}
+	if deployment.Labels["cost-center"] != "9999" {
+		t.Errorf("Deployment labels = %v, want the changed label", deployment.Labels)
+	}
+	if deployment.Spec.Template.Annotations["vault.hashicorp.com/agent-inject"] != "true" {
+		t.Errorf("pod annotations = %v, want the added annotation", deployment.Spec.Template.Annotations)
+	}
+	if err := cli.Get(ctx, key, service); err != nil {
+		t.Fatalf("Get() Service error = %v", err)
+	}
+	if service.Labels["cost-center"] != "9999" {
+		t.Errorf("Service labels = %v, want the changed label", service.Labels)
+	}
+}
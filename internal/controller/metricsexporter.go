@@ -0,0 +1,244 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/internal/metricsexporter"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster/gvk"
+)
+
+// +kubebuilder:rbac:groups="monitoring.coreos.com",resources=servicemonitors,verbs=create;get;list;watch;update;patch;delete
+
+const (
+	// MetricsExporterAvailable reports whether the metrics exporter of every MCP server pod is ready. It is only
+	// set for MCP servers with a metrics exporter.
+	MetricsExporterAvailable = "MetricsExporterAvailable"
+
+	metricsExporterContainerName = "metrics-exporter"
+	metricsExporterPort          = 8090
+	metricsExporterMetricsPort   = 9090
+	metricsPortName              = "metrics"
+
+	// metricsServiceLabelKey marks the Service of the metrics exporter, which the ServiceMonitor selects.
+	metricsServiceLabelKey = "mcpserver.opendatahub.io/metrics"
+)
+
+// metricsServiceSuffix is the suffix of the name of the metrics Service of an MCP server.
+const metricsServiceSuffix = "metrics"
+
+func metricsServiceName(cr *mcpserverv1.MCPServer) string {
+	return childName(cr, metricsServiceSuffix)
+}
+
+// metricsExporterContainer returns the metrics exporter of cr, which runs the metrics-exporter subcommand of the
+// operator image in front of the MCP server and its guardrails filter, or nil when cr has no metrics exporter.
+func (r *MCPServerReconciler) metricsExporterContainer(cr *mcpserverv1.MCPServer) *corev1.Container {
+	if cr.Spec.MetricsExporter == nil {
+		return nil
+	}
+
+	return &corev1.Container{
+		Name:    metricsExporterContainerName,
+		Image:   r.OperatorImage,
+		Command: []string{"/manager", metricsexporter.Command},
+		Args: []string{
+			"--upstream", fmt.Sprintf("http://localhost:%d", upstreamPort(cr, metricsExporterContainerName)),
+			"--port", strconv.Itoa(metricsExporterPort),
+			"--metrics-port", strconv.Itoa(metricsExporterMetricsPort),
+		},
+		Ports: []corev1.ContainerPort{
+			{ContainerPort: metricsExporterPort, Name: "http"},
+			{ContainerPort: metricsExporterMetricsPort, Name: metricsPortName},
+		},
+		ReadinessProbe: &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Path: metricsexporter.HealthPath,
+					Port: intstr.FromInt32(metricsExporterMetricsPort),
+				},
+			},
+			PeriodSeconds: 10,
+		},
+		SecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: ptr.To(false),
+			Capabilities: &corev1.Capabilities{
+				Drop: []corev1.Capability{"ALL"},
+			},
+		},
+	}
+}
+
+// withMetricsExporter returns containers with the metrics exporter set to sidecar, or removed when sidecar is
+// nil. While the exporter runs, it owns the http port and the port of the container behind it is renamed, the
+// one of the capability filter or the guardrails filter if there is one and otherwise the one of the MCP server.
+func withMetricsExporter(containers []corev1.Container, sidecar *corev1.Container) []corev1.Container {
+	return withSidecar(containers, metricsExporterContainerName, sidecar, capabilityFilterContainerName,
+		guardrailsContainerName, "mcp-server")
+}
+
+// serviceMonitorAPIAvailable reports whether the Prometheus Operator is installed.
+func (r *MCPServerReconciler) serviceMonitorAPIAvailable() bool {
+	return r.Platform != nil && r.Platform.HasAPI(gvk.ServiceMonitor)
+}
+
+// reconcileMetricsExporter creates the Service of the metrics exporter of cr and the ServiceMonitor that has
+// Prometheus scrape it, or removes them when the exporter is disabled.
+func (r *MCPServerReconciler) reconcileMetricsExporter(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) error {
+	if cr.Spec.MetricsExporter == nil {
+		return r.deleteMetricsExporter(ctx, cli, cr)
+	}
+
+	service := &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      metricsServiceName(cr),
+			Namespace: cr.Namespace,
+			Labels: map[string]string{
+				mcpServerAppLabelKey:   resourceName(cr),
+				metricsServiceLabelKey: "true",
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{
+				mcpServerAppLabelKey: resourceName(cr),
+			},
+			Ports: []corev1.ServicePort{{
+				Name:       metricsPortName,
+				Port:       metricsExporterMetricsPort,
+				TargetPort: intstr.FromString(metricsPortName),
+				Protocol:   corev1.ProtocolTCP,
+			}},
+		},
+	}
+	if err := r.createChild(ctx, cli, cr, service); err != nil {
+		return err
+	}
+
+	if !r.serviceMonitorAPIAvailable() {
+		return nil
+	}
+	return r.reconcileServiceMonitor(ctx, cli, cr)
+}
+
+// reconcileServiceMonitor creates the ServiceMonitor of cr and keeps its scrape endpoint up to date.
+func (r *MCPServerReconciler) reconcileServiceMonitor(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) error {
+	desired := newServiceMonitor(cr)
+	if err := r.createChild(ctx, cli, cr, desired.DeepCopy()); err != nil {
+		return err
+	}
+
+	serviceMonitor := &unstructured.Unstructured{}
+	serviceMonitor.SetGroupVersionKind(gvk.ServiceMonitor)
+	err := cli.Get(ctx, client.ObjectKey{Name: resourceName(cr), Namespace: cr.Namespace}, serviceMonitor)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	endpoints, _, _ := unstructured.NestedSlice(desired.Object, "spec", "endpoints")
+	current, _, _ := unstructured.NestedSlice(serviceMonitor.Object, "spec", "endpoints")
+	if equality.Semantic.DeepEqual(current, endpoints) {
+		return nil
+	}
+	original := serviceMonitor.DeepCopy()
+	if err := unstructured.SetNestedSlice(serviceMonitor.Object, endpoints, "spec", "endpoints"); err != nil {
+		return err
+	}
+	logChildDiff(ctx, original, serviceMonitor)
+	return cli.Patch(ctx, serviceMonitor, client.MergeFrom(original))
+}
+
+// newServiceMonitor returns the ServiceMonitor that has Prometheus scrape the metrics exporter of cr.
+func newServiceMonitor(cr *mcpserverv1.MCPServer) *unstructured.Unstructured {
+	endpoint := map[string]any{
+		"port": metricsPortName,
+		"path": metricsexporter.MetricsPath,
+	}
+	if interval := cr.Spec.MetricsExporter.Interval; interval != nil {
+		endpoint["interval"] = prometheusDuration(interval.Duration)
+	}
+
+	serviceMonitor := &unstructured.Unstructured{Object: map[string]any{
+		"spec": map[string]any{
+			"selector": map[string]any{
+				"matchLabels": map[string]any{
+					mcpServerAppLabelKey:   resourceName(cr),
+					metricsServiceLabelKey: "true",
+				},
+			},
+			"endpoints": []any{endpoint},
+		},
+	}}
+	serviceMonitor.SetGroupVersionKind(gvk.ServiceMonitor)
+	serviceMonitor.SetName(resourceName(cr))
+	serviceMonitor.SetNamespace(cr.Namespace)
+	serviceMonitor.SetLabels(map[string]string{mcpServerAppLabelKey: resourceName(cr)})
+	return serviceMonitor
+}
+
+// prometheusDuration formats d in the duration format of Prometheus, which has no fractional units.
+func prometheusDuration(d time.Duration) string {
+	if d < time.Millisecond {
+		return "0"
+	}
+	var b strings.Builder
+	remaining := d
+	for _, unit := range []struct {
+		suffix string
+		size   time.Duration
+	}{{"h", time.Hour}, {"m", time.Minute}, {"s", time.Second}, {"ms", time.Millisecond}} {
+		if count := remaining / unit.size; count > 0 {
+			fmt.Fprintf(&b, "%d%s", count, unit.suffix)
+			remaining -= count * unit.size
+		}
+	}
+	return b.String()
+}
+
+// deleteMetricsExporter removes the Service and ServiceMonitor of a metrics exporter that was disabled. They
+// only exist when the MetricsExporterAvailable condition was reported.
+func (r *MCPServerReconciler) deleteMetricsExporter(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) error {
+	if meta.FindStatusCondition(cr.Status.Conditions, MetricsExporterAvailable) == nil {
+		return nil
+	}
+
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: metricsServiceName(cr), Namespace: cr.Namespace}}
+	if err := r.deleteChild(ctx, cli, cr, service); err != nil {
+		return err
+	}
+	if !r.serviceMonitorAPIAvailable() {
+		return nil
+	}
+	serviceMonitor := &unstructured.Unstructured{}
+	serviceMonitor.SetGroupVersionKind(gvk.ServiceMonitor)
+	serviceMonitor.SetName(resourceName(cr))
+	serviceMonitor.SetNamespace(cr.Namespace)
+	return r.deleteChild(ctx, cli, cr, serviceMonitor)
+}
+
+// getMetricsExporterCondition returns the MetricsExporterAvailable condition of cr from the readiness of the
+// metrics exporter in each MCP server pod.
+func (r *MCPServerReconciler) getMetricsExporterCondition(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) metav1.Condition {
+	return r.getSidecarCondition(ctx, cli, cr, MetricsExporterAvailable, metricsExporterContainerName, "MetricsExporter",
+		"metrics exporter", "check the logs of its metrics-exporter container")
+}
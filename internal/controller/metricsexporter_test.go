@@ -0,0 +1,147 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster/gvk"
+)
+
+func Test_withSidecars(t *testing.T) {
+	r := &MCPServerReconciler{OperatorImage: "quay.io/example/operator:latest"}
+	server := corev1.Container{
+		Name:  "mcp-server",
+		Ports: []corev1.ContainerPort{{ContainerPort: 8000, Name: "http"}},
+	}
+	newCR := func(guardrails, metricsExporter bool) *mcpserverv1.MCPServer {
+		cr := newGuardrailsMCPServer()
+		if !guardrails {
+			cr.Spec.Guardrails = nil
+		}
+		if metricsExporter {
+			cr.Spec.MetricsExporter = &mcpserverv1.MetricsExporter{}
+		}
+		return cr
+	}
+	both := r.withSidecars(newCR(true, true), []corev1.Container{server})
+
+	tests := []struct {
+		name       string
+		cr         *mcpserverv1.MCPServer
+		containers []corev1.Container
+		wantPorts  map[string]string
+	}{
+		{
+			name:       "metrics exporter in front of the MCP server",
+			cr:         newCR(false, true),
+			containers: []corev1.Container{server},
+			wantPorts:  map[string]string{"mcp-server": mcpServerPortName, metricsExporterContainerName: "http"},
+		},
+		{
+			name:       "metrics exporter in front of the guardrails filter",
+			cr:         newCR(true, true),
+			containers: []corev1.Container{server},
+			wantPorts: map[string]string{"mcp-server": mcpServerPortName, guardrailsContainerName: guardrailsPortName,
+				metricsExporterContainerName: "http"},
+		},
+		{
+			name:       "metrics exporter removed from the guardrails filter",
+			cr:         newCR(true, false),
+			containers: both,
+			wantPorts:  map[string]string{"mcp-server": mcpServerPortName, guardrailsContainerName: "http"},
+		},
+		{
+			name:       "all sidecars removed",
+			cr:         newCR(false, false),
+			containers: both,
+			wantPorts:  map[string]string{"mcp-server": "http"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := r.withSidecars(tt.cr, tt.containers)
+			if len(got) != len(tt.wantPorts) {
+				t.Fatalf("withSidecars() returned %d containers, want %d", len(got), len(tt.wantPorts))
+			}
+			for _, container := range got {
+				if container.Ports[0].Name != tt.wantPorts[container.Name] {
+					t.Errorf("port of %s = %s, want %s", container.Name, container.Ports[0].Name, tt.wantPorts[container.Name])
+				}
+			}
+		})
+	}
+
+	// The exporter forwards to the guardrails filter when there is one.
+	if exporter := r.metricsExporterContainer(newCR(true, true)); exporter.Args[1] != "http://localhost:8080" {
+		t.Errorf("upstream of the metrics exporter = %s, want the guardrails filter", exporter.Args[1])
+	}
+}
+
+func TestMCPServerReconciler_reconcileMetricsExporter(t *testing.T) {
+	discovery := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{{
+		GroupVersion: gvk.ServiceMonitor.GroupVersion().String(),
+		APIResources: []metav1.APIResource{{Name: "servicemonitors", Kind: gvk.ServiceMonitor.Kind}},
+	}}}}
+	scheme := newGatewayScheme(t)
+	cli := fake.NewClientBuilder().WithScheme(scheme).Build()
+	r := &MCPServerReconciler{
+		Client:   cli,
+		Scheme:   scheme,
+		Platform: &cluster.Platform{Name: cluster.Kubernetes, APIs: gvk.NewAvailability(discovery)},
+	}
+
+	cr := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+		Spec: mcpserverv1.MCPServerSpec{
+			Image:           mcpServerImage,
+			MetricsExporter: &mcpserverv1.MetricsExporter{},
+		},
+	}
+	if err := r.reconcileMetricsExporter(context.Background(), cli, cr); err != nil {
+		t.Fatalf("reconcileMetricsExporter() error = %v", err)
+	}
+
+	// Setting a scrape interval updates the existing ServiceMonitor.
+	cr.Spec.MetricsExporter.Interval = &metav1.Duration{Duration: 90 * time.Second}
+	if err := r.reconcileMetricsExporter(context.Background(), cli, cr); err != nil {
+		t.Fatalf("reconcileMetricsExporter() error = %v", err)
+	}
+
+	service := &corev1.Service{}
+	if err := cli.Get(context.Background(), client.ObjectKey{Name: mcpServerName + "-metrics", Namespace: testNamespace}, service); err != nil {
+		t.Fatalf("failed to get the metrics Service: %v", err)
+	}
+	serviceMonitor := &unstructured.Unstructured{}
+	serviceMonitor.SetGroupVersionKind(gvk.ServiceMonitor)
+	if err := cli.Get(context.Background(), client.ObjectKey{Name: mcpServerName, Namespace: testNamespace}, serviceMonitor); err != nil {
+		t.Fatalf("failed to get the ServiceMonitor: %v", err)
+	}
+	endpoints, _, _ := unstructured.NestedSlice(serviceMonitor.Object, "spec", "endpoints")
+	if len(endpoints) != 1 || endpoints[0].(map[string]any)["interval"] != "1m30s" {
+		t.Errorf("ServiceMonitor endpoints = %v, want the metrics port scraped every 1m30s", endpoints)
+	}
+
+	// Disabling the exporter removes both.
+	cr.Spec.MetricsExporter = nil
+	cr.Status.Conditions = []metav1.Condition{{Type: MetricsExporterAvailable, Status: metav1.ConditionTrue}}
+	if err := r.reconcileMetricsExporter(context.Background(), cli, cr); err != nil {
+		t.Fatalf("reconcileMetricsExporter() error = %v", err)
+	}
+	if err := cli.Get(context.Background(), client.ObjectKeyFromObject(service), service); err == nil {
+		t.Errorf("the metrics Service still exists")
+	}
+	if err := cli.Get(context.Background(), client.ObjectKeyFromObject(serviceMonitor), serviceMonitor); err == nil {
+		t.Errorf("the ServiceMonitor still exists")
+	}
+}
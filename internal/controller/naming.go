@@ -0,0 +1,156 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// childNameHashLength is the length of the hash of the MCPServer name in a shortened child name.
+const childNameHashLength = 8
+
+// childName returns the name of the child of cr with suffix, such as session-store, or the name of cr itself for
+// an empty suffix. Every child is named through it, so that the names stay valid DNS labels, which Services and
+// the job-name label of Jobs require: a name longer than 63 characters is shortened by truncating the name of cr
+// and appending a hash of it before the suffix. The hash keeps the children of MCPServers that share a long
+// prefix apart, and the same name is derived on every reconcile.
+func childName(cr *mcpserverv1.MCPServer, suffix string) string {
+	return shortenedName(cr.Name, suffix)
+}
+
+// shortenedName returns name with suffix, shortened like childName if it is too long for a DNS label.
+func shortenedName(name, suffix string) string {
+	if suffix != "" {
+		suffix = "-" + suffix
+	}
+	if len(name+suffix) <= validation.DNS1123LabelMaxLength {
+		return name + suffix
+	}
+
+	sum := sha256.Sum256([]byte(name))
+	hash := hex.EncodeToString(sum[:])[:childNameHashLength]
+	prefix := name[:validation.DNS1123LabelMaxLength-len(suffix)-len(hash)-1]
+	return strings.TrimRight(prefix, "-.") + "-" + hash + suffix
+}
+
+// legacyChildName returns the name the child of cr with suffix had before long names were shortened by childName.
+// The two only differ for names longer than 63 characters.
+func legacyChildName(cr *mcpserverv1.MCPServer, suffix string) string {
+	if suffix == "" {
+		return cr.Name
+	}
+	return cr.Name + "-" + suffix
+}
+
+// deleteLegacyChild removes the object of the kind of obj that cr controls under the legacy name of the child with
+// suffix, once the child was renamed and its replacement has taken over. It does nothing for children whose name
+// did not change.
+func (r *MCPServerReconciler) deleteLegacyChild(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer, obj client.Object,
+	suffix string) error {
+	legacy := legacyChildName(cr, suffix)
+	if legacy == childName(cr, suffix) {
+		return nil
+	}
+	obj.SetName(legacy)
+	obj.SetNamespace(cr.Namespace)
+	return r.deleteChild(ctx, cli, cr, obj)
+}
+
+// mcpServerKey returns the key of the MCPServer in namespace whose resourceName is name, for children and pods
+// that are mapped to their MCPServer by name or label. A name shorter than 63 characters is the name of the
+// MCPServer itself; a shortened one is looked up among the MCPServers of the namespace.
+func (r *MCPServerReconciler) mcpServerKey(ctx context.Context, namespace, name string) client.ObjectKey {
+	key := client.ObjectKey{Name: name, Namespace: namespace}
+	if len(name) < validation.DNS1123LabelMaxLength {
+		return key
+	}
+	list := &mcpserverv1.MCPServerList{}
+	if err := r.Client.List(ctx, list, client.InNamespace(namespace)); err != nil {
+		return key
+	}
+	for i := range list.Items {
+		if resourceName(&list.Items[i]) == name {
+			return client.ObjectKeyFromObject(&list.Items[i])
+		}
+	}
+	return key
+}
+
+// components returns the resources the operator manages for cr with their names, as reported in
+// status.components.
+func (r *MCPServerReconciler) components(cr *mcpserverv1.MCPServer) []mcpserverv1.Component {
+	var components []mcpserverv1.Component
+	add := func(kind, name string) {
+		components = append(components, mcpserverv1.Component{Kind: kind, Name: name})
+	}
+
+	if !isExternal(cr) {
+		add("Deployment", resourceName(cr))
+		add("Service", resourceName(cr))
+		if usesOwnServiceAccount(cr) {
+			add("ServiceAccount", resourceName(cr))
+		}
+		if r.usesRoute(cr) {
+			add("Route", resourceName(cr))
+		}
+		if usesGateway(cr) {
+			add("HTTPRoute", resourceName(cr))
+		}
+		if usesMeshGateway(cr) {
+			add("VirtualService", resourceName(cr))
+		}
+		for _, exposure := range cr.Spec.Exposures {
+			// The Route of the shared host is not a component of the MCP server.
+			if exposure.Type == mcpserverv1.ExposureSharedHost {
+				continue
+			}
+			if exposure.Type != mcpserverv1.ExposureRoute || r.routeAPIAvailable() {
+				add(string(exposure.Type), exposureName(cr, exposure))
+			}
+		}
+		if cr.Spec.AllowedClientNamespaces != nil {
+			add("NetworkPolicy", resourceName(cr))
+		}
+		if cr.Spec.Autoscaling != nil {
+			add("HorizontalPodAutoscaler", resourceName(cr))
+		}
+		if provisionsSessionStore(cr) {
+			add("Deployment", sessionStoreName(cr))
+			add("Service", sessionStoreName(cr))
+		}
+		if usesTokenAuth(cr) && cr.Spec.Auth.TokenSecretRef == nil {
+			add("Secret", authTokenSecretName(cr))
+		}
+		if cr.Spec.MetricsExporter != nil {
+			add("Service", metricsServiceName(cr))
+			if r.serviceMonitorAPIAvailable() {
+				add("ServiceMonitor", resourceName(cr))
+			}
+		}
+		if usesRESTBridge(cr) {
+			add("Service", restBridgeName(cr))
+			if r.usesRoute(cr) {
+				add("Route", restBridgeName(cr))
+			}
+		}
+		if cr.Spec.ConformanceCheck != nil {
+			add("Job", conformanceCheckJobName(cr))
+		}
+	}
+	if cr.Spec.TestConnection {
+		add("Job", connectionTestJobName(cr))
+	}
+	return components
+}
+
+// resourceName returns the name of the children of cr that are named after it, such as its Deployment, Service and
+// Route, which is also the value of the labels that select its pods. It is the name of cr unless that is too long.
+func resourceName(cr *mcpserverv1.MCPServer) string {
+	return childName(cr, "")
+}
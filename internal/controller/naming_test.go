@@ -0,0 +1,326 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// longMCPServerName is an MCPServer name whose children with a suffix need shortened names.
+var longMCPServerName = "kubernetes-tools-for-the-platform-engineering-team-eu-west"
+
+func Test_childName(t *testing.T) {
+	tests := []struct {
+		name       string
+		crName     string
+		suffix     string
+		want       string
+		wantPrefix string
+	}{
+		{
+			name:   "short name without suffix",
+			crName: mcpServerName,
+			want:   mcpServerName,
+		},
+		{
+			name:   "short name with suffix",
+			crName: mcpServerName,
+			suffix: sessionStoreSuffix,
+			want:   mcpServerName + "-session-store",
+		},
+		{
+			name:   "name of exactly 63 characters",
+			crName: strings.Repeat("a", 49),
+			suffix: sessionStoreSuffix,
+			want:   strings.Repeat("a", 49) + "-session-store",
+		},
+		{
+			name:       "long name with suffix",
+			crName:     longMCPServerName,
+			suffix:     sessionStoreSuffix,
+			wantPrefix: "kubernetes-tools-for-the-platform-engine-",
+		},
+		{
+			name:       "long name without suffix",
+			crName:     strings.Repeat("a", 70),
+			wantPrefix: strings.Repeat("a", 54) + "-",
+		},
+		{
+			name:       "dashes before the hash are trimmed",
+			crName:     strings.Repeat("a", 39) + "-" + strings.Repeat("b", 30),
+			suffix:     sessionStoreSuffix,
+			wantPrefix: strings.Repeat("a", 39) + "-",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &mcpserverv1.MCPServer{ObjectMeta: metav1.ObjectMeta{Name: tt.crName}}
+			got := childName(cr, tt.suffix)
+			if tt.want != "" && got != tt.want {
+				t.Errorf("childName() = %q, want %q", got, tt.want)
+			}
+			if tt.wantPrefix != "" && !strings.HasPrefix(got, tt.wantPrefix) {
+				t.Errorf("childName() = %q, want the prefix %q", got, tt.wantPrefix)
+			}
+			if strings.Contains(got, "--") {
+				t.Errorf("childName() = %q, want no empty part before the hash", got)
+			}
+			if !strings.HasSuffix(got, tt.suffix) {
+				t.Errorf("childName() = %q, want the suffix %q", got, tt.suffix)
+			}
+			if errs := validation.IsDNS1123Label(got); len(errs) > 0 {
+				t.Errorf("childName() = %q is not a DNS label: %v", got, errs)
+			}
+			if again := childName(cr, tt.suffix); again != got {
+				t.Errorf("childName() = %q, then %q, want a stable name", got, again)
+			}
+		})
+	}
+
+	a := &mcpserverv1.MCPServer{ObjectMeta: metav1.ObjectMeta{Name: longMCPServerName + "-a"}}
+	b := &mcpserverv1.MCPServer{ObjectMeta: metav1.ObjectMeta{Name: longMCPServerName + "-b"}}
+	if childName(a, sessionStoreSuffix) == childName(b, sessionStoreSuffix) {
+		t.Errorf("childName() = %q for both %s and %s, want different names", childName(a, sessionStoreSuffix),
+			a.Name, b.Name)
+	}
+}
+
+func TestMCPServerReconciler_reconcileAuthToken_legacyName(t *testing.T) {
+	fakeScheme := newAutoscalingScheme(t)
+	cr := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{Name: longMCPServerName, Namespace: testNamespace, UID: types.UID("uid")},
+		Spec: mcpserverv1.MCPServerSpec{
+			Image: mcpServerImage,
+			Auth:  &mcpserverv1.Auth{Type: mcpserverv1.AuthToken},
+		},
+	}
+	legacyName := legacyChildName(cr, authTokenSuffix)
+	newLegacySecret := func() *corev1.Secret {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: legacyName, Namespace: testNamespace},
+			Data:       map[string][]byte{authTokenKey: []byte("issued-token")},
+		}
+		if err := ctrl.SetControllerReference(cr, secret, fakeScheme); err != nil {
+			t.Fatalf("failed to set the controller reference: %v", err)
+		}
+		return secret
+	}
+	newDeployment := func(secretName string, complete bool) *appsv1.Deployment {
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: cr.Name, Namespace: testNamespace, Generation: 2},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.To[int32](1),
+				Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Volumes: []corev1.Volume{{
+					Name:         authTokenVolumeName,
+					VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: secretName}},
+				}}}},
+			},
+			Status: appsv1.DeploymentStatus{ObservedGeneration: 1},
+		}
+		if complete {
+			deployment.Status = appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 1, UpdatedReplicas: 1,
+				AvailableReplicas: 1}
+		}
+		return deployment
+	}
+
+	tests := []struct {
+		name       string
+		deployment *appsv1.Deployment
+		wantLegacy bool
+	}{
+		{
+			name:       "Verify that the legacy Secret is kept while the pods mount it",
+			deployment: newDeployment(legacyName, true),
+			wantLegacy: true,
+		},
+		{
+			name:       "Verify that the legacy Secret is kept until the rollout to the new Secret completes",
+			deployment: newDeployment(authTokenSecretName(cr), false),
+			wantLegacy: true,
+		},
+		{
+			name:       "Verify that the legacy Secret is removed once no pod mounts it",
+			deployment: newDeployment(authTokenSecretName(cr), true),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(newLegacySecret(), tt.deployment).Build()
+			r := &MCPServerReconciler{Client: cli, Scheme: fakeScheme}
+
+			if err := r.reconcileAuthToken(context.Background(), cli, cr); err != nil {
+				t.Fatalf("reconcileAuthToken() error = %v", err)
+			}
+
+			secret := &corev1.Secret{}
+			key := client.ObjectKey{Name: authTokenSecretName(cr), Namespace: testNamespace}
+			if err := cli.Get(context.Background(), key, secret); err != nil {
+				t.Fatalf("failed to get the token Secret: %v", err)
+			}
+			if got := secret.StringData[authTokenKey] + string(secret.Data[authTokenKey]); got != "issued-token" {
+				t.Errorf("token = %q, want the token of the legacy Secret", got)
+			}
+			err := cli.Get(context.Background(), client.ObjectKey{Name: legacyName, Namespace: testNamespace},
+				&corev1.Secret{})
+			if gotLegacy := !k8serr.IsNotFound(err); gotLegacy != tt.wantLegacy {
+				t.Errorf("legacy Secret exists = %v, want %v", gotLegacy, tt.wantLegacy)
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_reconcileSessionStore_legacyName(t *testing.T) {
+	fakeScheme := newAutoscalingScheme(t)
+	cr := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{Name: longMCPServerName, Namespace: testNamespace, UID: types.UID("uid")},
+		Spec: mcpserverv1.MCPServerSpec{
+			Image:        mcpServerImage,
+			SessionStore: &mcpserverv1.SessionStore{},
+		},
+	}
+	legacy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: legacyChildName(cr, sessionStoreSuffix), Namespace: testNamespace},
+	}
+	if err := ctrl.SetControllerReference(cr, legacy, fakeScheme); err != nil {
+		t.Fatalf("failed to set the controller reference: %v", err)
+	}
+	cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(legacy).Build()
+	r := &MCPServerReconciler{Client: cli, Scheme: fakeScheme}
+
+	if err := r.reconcileSessionStore(context.Background(), cli, cr); err != nil {
+		t.Fatalf("reconcileSessionStore() error = %v", err)
+	}
+	if err := cli.Get(context.Background(), client.ObjectKeyFromObject(legacy), &appsv1.Deployment{}); err != nil {
+		t.Fatalf("legacy session store error = %v, want it kept until the renamed one is available", err)
+	}
+
+	deployment := &appsv1.Deployment{}
+	key := client.ObjectKey{Name: sessionStoreName(cr), Namespace: testNamespace}
+	if err := cli.Get(context.Background(), key, deployment); err != nil {
+		t.Fatalf("failed to get the session store: %v", err)
+	}
+	deployment.Status = appsv1.DeploymentStatus{ObservedGeneration: deployment.Generation, Replicas: 1,
+		UpdatedReplicas: 1, AvailableReplicas: 1}
+	if err := cli.Status().Update(context.Background(), deployment); err != nil {
+		t.Fatalf("failed to update the session store status: %v", err)
+	}
+
+	if err := r.reconcileSessionStore(context.Background(), cli, cr); err != nil {
+		t.Fatalf("reconcileSessionStore() error = %v", err)
+	}
+	err := cli.Get(context.Background(), client.ObjectKeyFromObject(legacy), &appsv1.Deployment{})
+	if !k8serr.IsNotFound(err) {
+		t.Errorf("legacy session store error = %v, want it removed", err)
+	}
+}
+
+func TestMCPServerReconciler_components(t *testing.T) {
+	long := &mcpserverv1.MCPServer{ObjectMeta: metav1.ObjectMeta{Name: strings.Repeat("a", 70)}}
+	tests := []struct {
+		name   string
+		crName string
+		spec   mcpserverv1.MCPServerSpec
+		want   []mcpserverv1.Component
+	}{
+		{
+			name:   "Verify that a managed server has a Deployment, a Service, a ServiceAccount and a Route",
+			crName: mcpServerName,
+			spec:   mcpserverv1.MCPServerSpec{Image: mcpServerImage},
+			want: []mcpserverv1.Component{
+				{Kind: "Deployment", Name: mcpServerName},
+				{Kind: "Service", Name: mcpServerName},
+				{Kind: "ServiceAccount", Name: mcpServerName},
+				{Kind: "Route", Name: mcpServerName},
+			},
+		},
+		{
+			name:   "Verify that the children of a long name are listed with their shortened names",
+			crName: long.Name,
+			spec: mcpserverv1.MCPServerSpec{
+				Image:        mcpServerImage,
+				SessionStore: &mcpserverv1.SessionStore{},
+				Autoscaling:  &mcpserverv1.Autoscaling{MaxReplicas: 3},
+			},
+			want: []mcpserverv1.Component{
+				{Kind: "Deployment", Name: resourceName(long)},
+				{Kind: "Service", Name: resourceName(long)},
+				{Kind: "ServiceAccount", Name: resourceName(long)},
+				{Kind: "Route", Name: resourceName(long)},
+				{Kind: "HorizontalPodAutoscaler", Name: resourceName(long)},
+				{Kind: "Deployment", Name: sessionStoreName(long)},
+				{Kind: "Service", Name: sessionStoreName(long)},
+			},
+		},
+		{
+			name:   "Verify that an external server only lists its connection test",
+			crName: mcpServerName,
+			spec: mcpserverv1.MCPServerSpec{Type: mcpserverv1.MCPServerExternal, URL: "https://mcp.example.com/sse",
+				TestConnection: true},
+			want: []mcpserverv1.Component{{Kind: "Job", Name: mcpServerName + "-connection-test"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &mcpserverv1.MCPServer{ObjectMeta: metav1.ObjectMeta{Name: tt.crName, Namespace: testNamespace}, Spec: tt.spec}
+			r := &MCPServerReconciler{}
+
+			got := r.components(cr)
+			if len(got) != len(tt.want) {
+				t.Fatalf("components() = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("components()[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_mcpServerKey(t *testing.T) {
+	fakeScheme := newAutoscalingScheme(t)
+	long := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{Name: strings.Repeat("a", 70), Namespace: testNamespace},
+	}
+	cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(long).Build()
+	r := &MCPServerReconciler{Client: cli, Scheme: fakeScheme}
+
+	tests := []struct {
+		name       string
+		objectName string
+		want       string
+	}{
+		{
+			name:       "Verify that a short name is the name of the MCPServer",
+			objectName: mcpServerName,
+			want:       mcpServerName,
+		},
+		{
+			name:       "Verify that a shortened name is mapped to the MCPServer it was derived from",
+			objectName: resourceName(long),
+			want:       long.Name,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := r.mcpServerKey(context.Background(), testNamespace, tt.objectName)
+			if want := (client.ObjectKey{Name: tt.want, Namespace: testNamespace}); got != want {
+				t.Errorf("mcpServerKey() = %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"context"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// +kubebuilder:rbac:groups="networking.k8s.io",resources=networkpolicies,verbs=create;get;list;watch;update;patch;delete
+
+const (
+	// namespaceNameLabelKey is set by Kubernetes on every namespace to its name.
+	namespaceNameLabelKey = "kubernetes.io/metadata.name"
+
+	// ingressPolicyGroupLabelKey marks the namespaces of the OpenShift routers.
+	ingressPolicyGroupLabelKey = "policy-group.network.openshift.io/ingress"
+
+	// monitoringPolicyGroupLabelKey marks the namespaces of the OpenShift monitoring stacks, with the value
+	// monitoringPolicyGroup.
+	monitoringPolicyGroupLabelKey = "network.openshift.io/policy-group"
+	monitoringPolicyGroup         = "monitoring"
+)
+
+// networkPolicy returns the NetworkPolicy that only admits traffic to the MCP server pods of cr on their http
+// port, and on the port of the REST bridge when it runs, so that clients cannot call the MCP server past the
+// sidecars in front of it. With spec.allowedClientNamespaces, it only admits it from the selected namespaces and
+// from what the MCP server needs to work: its own namespace, where the connection test and conformance check run,
+// the operator, the router of its Route or the namespace of its Gateway or mesh gateway. The monitoring stack is
+// admitted to the metrics port when it has a metrics exporter.
+func (r *MCPServerReconciler) networkPolicy(cr *mcpserverv1.MCPServer) *networkingv1.NetworkPolicy {
+	labels := map[string]string{mcpServerAppLabelKey: resourceName(cr)}
+	namespaces := func(labels map[string]string) networkingv1.NetworkPolicyPeer {
+		return networkingv1.NetworkPolicyPeer{NamespaceSelector: &metav1.LabelSelector{MatchLabels: labels}}
+	}
+
+	peers := []networkingv1.NetworkPolicyPeer{
+		{NamespaceSelector: cr.Spec.AllowedClientNamespaces.DeepCopy()},
+		{PodSelector: &metav1.LabelSelector{}},
+	}
+	if r.OperatorNamespace != "" && r.OperatorNamespace != cr.Namespace {
+		peers = append(peers, namespaces(map[string]string{namespaceNameLabelKey: r.OperatorNamespace}))
+	}
+	switch {
+	case usesMeshGateway(cr):
+		// The Istio Gateway resource is usually in the namespace of the gateway pods.
+		if namespace, _, _ := strings.Cut(cr.Spec.MeshGateway.Gateway, "/"); namespace != cr.Namespace {
+			peers = append(peers, namespaces(map[string]string{namespaceNameLabelKey: namespace}))
+		}
+	case usesGateway(cr):
+		if namespace := gatewayKey(cr).Namespace; namespace != cr.Namespace {
+			peers = append(peers, namespaces(map[string]string{namespaceNameLabelKey: namespace}))
+		}
+	case r.routeAPIAvailable():
+		peers = append(peers, namespaces(map[string]string{ingressPolicyGroupLabelKey: ""}))
+	}
+
+	ports := []networkingv1.NetworkPolicyPort{{
+		Protocol: ptr.To(corev1.ProtocolTCP),
+		Port:     ptr.To(intstr.FromString("http")),
+	}}
+	if usesRESTBridge(cr) {
+		ports = append(ports, networkingv1.NetworkPolicyPort{
+			Protocol: ptr.To(corev1.ProtocolTCP),
+			Port:     ptr.To(intstr.FromString(restBridgePortName)),
+		})
+	}
+	if cr.Spec.AllowedClientNamespaces == nil {
+		// All sources are admitted.
+		peers = nil
+	}
+	rules := []networkingv1.NetworkPolicyIngressRule{{From: peers, Ports: ports}}
+	if cr.Spec.MetricsExporter != nil {
+		rules = append(rules, networkingv1.NetworkPolicyIngressRule{
+			From: []networkingv1.NetworkPolicyPeer{
+				namespaces(map[string]string{monitoringPolicyGroupLabelKey: monitoringPolicyGroup}),
+			},
+			Ports: []networkingv1.NetworkPolicyPort{{
+				Protocol: ptr.To(corev1.ProtocolTCP),
+				Port:     ptr.To(intstr.FromInt32(metricsExporterMetricsPort)),
+			}},
+		})
+	}
+
+	return &networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: networkingv1.SchemeGroupVersion.String(),
+			Kind:       "NetworkPolicy",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resourceName(cr),
+			Namespace: cr.Namespace,
+			Labels:    labels,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: labels},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress:     rules,
+		},
+	}
+}
+
+// reconcileNetworkPolicy creates or updates the NetworkPolicy of cr when spec.allowedClientNamespaces is set or
+// sidecars run in front of the MCP server, and removes it otherwise.
+func (r *MCPServerReconciler) reconcileNetworkPolicy(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) error {
+	if cr.Spec.AllowedClientNamespaces == nil && httpPort(cr) == mcpServerPort {
+		return r.deleteNetworkPolicy(ctx, cli, cr)
+	}
+
+	desired := r.networkPolicy(cr)
+	if err := r.createChild(ctx, cli, cr, desired); err != nil {
+		return err
+	}
+
+	existing := &networkingv1.NetworkPolicy{}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(desired), existing); err != nil {
+		if k8serr.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if equality.Semantic.DeepEqual(existing.Spec, desired.Spec) {
+		return nil
+	}
+	original := existing.DeepCopy()
+	existing.Spec = desired.Spec
+	logChildDiff(ctx, original, existing)
+	return cli.Patch(ctx, existing, client.MergeFrom(original))
+}
+
+// deleteNetworkPolicy removes the NetworkPolicy of an MCP server that no longer restricts its clients or ports.
+func (r *MCPServerReconciler) deleteNetworkPolicy(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) error {
+	policy := &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: resourceName(cr), Namespace: cr.Namespace}}
+	return r.deleteChild(ctx, cli, cr, policy)
+}
@@ -0,0 +1,153 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+func TestMCPServerReconciler_reconcileNetworkPolicy(t *testing.T) {
+	fakeScheme := runtime.NewScheme()
+	err := clientgoscheme.AddToScheme(fakeScheme)
+	if err != nil {
+		t.Errorf("failed to add client-go scheme: %v", err)
+	}
+	err = mcpserverv1.AddToScheme(fakeScheme)
+	if err != nil {
+		t.Errorf("failed to add mcpserverv1 scheme: %v", err)
+	}
+
+	newMCPServer := func(selector *metav1.LabelSelector, gatewayRef *mcpserverv1.GatewayRef, auth *mcpserverv1.Auth) *mcpserverv1.MCPServer {
+		return &mcpserverv1.MCPServer{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      mcpServerName,
+				Namespace: testNamespace,
+				UID:       types.UID("uid"),
+			},
+			Spec: mcpserverv1.MCPServerSpec{
+				Image:                   mcpServerImage,
+				AllowedClientNamespaces: selector,
+				GatewayRef:              gatewayRef,
+				Auth:                    auth,
+			},
+		}
+	}
+	teamA := &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}}
+	teamB := &metav1.LabelSelector{MatchLabels: map[string]string{"team": "b"}}
+
+	tests := []struct {
+		name          string
+		previous      *metav1.LabelSelector
+		selector      *metav1.LabelSelector
+		gatewayRef    *mcpserverv1.GatewayRef
+		auth          *mcpserverv1.Auth
+		wantPolicy    bool
+		wantClients   string
+		wantExposedBy map[string]string
+	}{
+		{
+			name: "no restriction",
+		},
+		{
+			name:          "clients of a team through the Route",
+			selector:      teamA,
+			wantPolicy:    true,
+			wantClients:   "a",
+			wantExposedBy: map[string]string{ingressPolicyGroupLabelKey: ""},
+		},
+		{
+			name:          "clients of a team through a Gateway",
+			selector:      teamA,
+			gatewayRef:    &mcpserverv1.GatewayRef{Name: "shared", Namespace: "gateways"},
+			wantPolicy:    true,
+			wantClients:   "a",
+			wantExposedBy: map[string]string{namespaceNameLabelKey: "gateways"},
+		},
+		{
+			name:          "changed clients",
+			previous:      teamA,
+			selector:      teamB,
+			wantPolicy:    true,
+			wantClients:   "b",
+			wantExposedBy: map[string]string{ingressPolicyGroupLabelKey: ""},
+		},
+		{
+			name:     "restriction removed",
+			previous: teamA,
+		},
+		{
+			name:       "all clients on the port of the token authentication",
+			auth:       &mcpserverv1.Auth{Type: mcpserverv1.AuthToken},
+			wantPolicy: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().WithScheme(fakeScheme).Build()
+			r := &MCPServerReconciler{Client: cli, Scheme: fakeScheme, OperatorNamespace: "mcp-server-operator"}
+
+			if tt.previous != nil {
+				if err := r.reconcileNetworkPolicy(context.Background(), cli, newMCPServer(tt.previous, nil, nil)); err != nil {
+					t.Fatalf("reconcileNetworkPolicy() error = %v", err)
+				}
+			}
+			if err := r.reconcileNetworkPolicy(context.Background(), cli, newMCPServer(tt.selector, tt.gatewayRef, tt.auth)); err != nil {
+				t.Fatalf("reconcileNetworkPolicy() error = %v", err)
+			}
+
+			policy := &networkingv1.NetworkPolicy{}
+			err := cli.Get(context.Background(), client.ObjectKey{Name: mcpServerName, Namespace: testNamespace}, policy)
+			if !tt.wantPolicy {
+				if !k8serr.IsNotFound(err) {
+					t.Errorf("expected no NetworkPolicy, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to get the NetworkPolicy: %v", err)
+			}
+			if got := policy.Spec.PodSelector.MatchLabels[mcpServerAppLabelKey]; got != mcpServerName {
+				t.Errorf("pod selector = %v, want the MCP server pods", policy.Spec.PodSelector)
+			}
+			if len(policy.Spec.Ingress) != 1 {
+				t.Fatalf("ingress rules = %v, want 1", policy.Spec.Ingress)
+			}
+			if ports := policy.Spec.Ingress[0].Ports; len(ports) != 1 || ports[0].Port.String() != "http" {
+				t.Errorf("ports = %v, want the http port", ports)
+			}
+			peers := policy.Spec.Ingress[0].From
+			if tt.selector == nil {
+				if peers != nil {
+					t.Errorf("peers = %v, want all sources", peers)
+				}
+				return
+			}
+			if len(peers) != 4 {
+				t.Fatalf("peers = %v, want clients, own namespace, operator and exposure", peers)
+			}
+			if got := peers[0].NamespaceSelector.MatchLabels["team"]; got != tt.wantClients {
+				t.Errorf("client namespaces of team %q, want %q", got, tt.wantClients)
+			}
+			if peers[1].PodSelector == nil || peers[1].NamespaceSelector != nil {
+				t.Errorf("second peer = %v, want the pods of the namespace of the MCP server", peers[1])
+			}
+			if got := peers[2].NamespaceSelector.MatchLabels[namespaceNameLabelKey]; got != "mcp-server-operator" {
+				t.Errorf("third peer = %v, want the namespace of the operator", peers[2])
+			}
+			if got := peers[3].NamespaceSelector.MatchLabels; len(got) != 1 || got[ingressPolicyGroupLabelKey] != tt.wantExposedBy[ingressPolicyGroupLabelKey] ||
+				got[namespaceNameLabelKey] != tt.wantExposedBy[namespaceNameLabelKey] {
+				t.Errorf("fourth peer = %v, want %v", got, tt.wantExposedBy)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+const (
+	// OwnerTeamLabel holds the spec.owner.team of the MCPServer on all of its children and on its pods, so the
+	// resources of a team can be selected for cost attribution.
+	OwnerTeamLabel = "mcpserver.opendatahub.io/owner-team"
+
+	// OwnerContactAnnotation holds the spec.owner.contact of the MCPServer on all of its children. Contacts such
+	// as email addresses are not valid label values.
+	OwnerContactAnnotation = "mcpserver.opendatahub.io/owner-contact"
+
+	// ReasonInvalidOwner is set on the Available condition of an MCPServer whose spec.owner does not have the
+	// format the operator requires.
+	ReasonInvalidOwner = "InvalidOwner"
+)
+
+// OwnerFormat is the format the operator requires of spec.owner. A nil pattern accepts any value.
+type OwnerFormat struct {
+	// Team must match spec.owner.team, e.g. ^team-[a-z]+$.
+	Team *regexp.Regexp
+	// Contact must match spec.owner.contact when it is set, e.g. ^[^@]+@example\.com$.
+	Contact *regexp.Regexp
+}
+
+// getOwnerCondition returns the Available condition of an MCPServer whose spec.owner does not match the
+// OwnerFormat of the reconciler, or nil when it does.
+func (r *MCPServerReconciler) getOwnerCondition(cr *mcpserverv1.MCPServer) *metav1.Condition {
+	owner := cr.Spec.Owner
+	if owner == nil {
+		return nil
+	}
+	var problems []string
+	if pattern := r.OwnerFormat.Team; pattern != nil && !pattern.MatchString(owner.Team) {
+		problems = append(problems, fmt.Sprintf("team %q does not match %s", owner.Team, pattern))
+	}
+	if pattern := r.OwnerFormat.Contact; pattern != nil && owner.Contact != "" && !pattern.MatchString(owner.Contact) {
+		problems = append(problems, fmt.Sprintf("contact %q does not match %s", owner.Contact, pattern))
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return &metav1.Condition{
+		Type:    OverallAvailable,
+		Status:  metav1.ConditionFalse,
+		Reason:  ReasonInvalidOwner,
+		Message: fmt.Sprintf("Invalid spec.owner: %s", strings.Join(problems, ", ")),
+	}
+}
+
+// withOwnerMetadata sets the owner team label and contact annotation of cr on obj, or removes them when cr has
+// none. It reports whether obj changed. The label map of obj is replaced rather than modified, as it may be
+// shared with a selector.
+func withOwnerMetadata(cr *mcpserverv1.MCPServer, obj metav1.Object) bool {
+	var team, contact string
+	if cr.Spec.Owner != nil {
+		team, contact = cr.Spec.Owner.Team, cr.Spec.Owner.Contact
+	}
+	labels, labelsChanged := withEntry(obj.GetLabels(), OwnerTeamLabel, team)
+	annotations, annotationsChanged := withEntry(obj.GetAnnotations(), OwnerContactAnnotation, contact)
+	if labelsChanged {
+		obj.SetLabels(labels)
+	}
+	if annotationsChanged {
+		obj.SetAnnotations(annotations)
+	}
+	return labelsChanged || annotationsChanged
+}
+
+// withEntry returns a copy of m with key set to value, or removed when value is empty, and whether it differs
+// from m.
+func withEntry(m map[string]string, key, value string) (map[string]string, bool) {
+	current, ok := m[key]
+	if current == value && (ok || value == "") {
+		return m, false
+	}
+	m = maps.Clone(m)
+	if value == "" {
+		delete(m, key)
+		return m, true
+	}
+	if m == nil {
+		m = map[string]string{}
+	}
+	m[key] = value
+	return m, true
+}
+
+// reconcileOwnerMetadata patches the owner team label and contact annotation of cr onto the existing child obj,
+// unless the annotation is ignored.
+func reconcileOwnerMetadata(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer, obj client.Object) error {
+	original, ok := obj.DeepCopyObject().(client.Object)
+	if !ok || !withOwnerMetadata(cr, obj) {
+		return nil
+	}
+	keepIgnoredAnnotations(cr, original, obj)
+	if equality.Semantic.DeepEqual(original.GetLabels(), obj.GetLabels()) &&
+		equality.Semantic.DeepEqual(original.GetAnnotations(), obj.GetAnnotations()) {
+		return nil
+	}
+	logChildDiff(ctx, original, obj)
+	return cli.Patch(ctx, obj, client.MergeFrom(original))
+}
+
+// applyOwnerLabel sets the owner team label of cr on the pod template of an existing Deployment, or removes it,
+// so that the pods of the MCP server can be attributed to the team. Changing it rolls out the Deployment.
+func applyOwnerLabel(deployment *appsv1.Deployment, cr *mcpserverv1.MCPServer) {
+	var team string
+	if cr.Spec.Owner != nil {
+		team = cr.Spec.Owner.Team
+	}
+	if labels, changed := withEntry(deployment.Spec.Template.Labels, OwnerTeamLabel, team); changed {
+		deployment.Spec.Template.Labels = labels
+	}
+}
@@ -0,0 +1,197 @@
+package controller
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+func TestMCPServerReconciler_getOwnerCondition(t *testing.T) {
+	format := OwnerFormat{
+		Team:    regexp.MustCompile(`^team-[a-z]+$`),
+		Contact: regexp.MustCompile(`^[^@]+@example\.com$`),
+	}
+
+	tests := []struct {
+		name    string
+		owner   *mcpserverv1.Owner
+		format  OwnerFormat
+		wantErr bool
+	}{
+		{
+			name:   "no owner",
+			format: format,
+		},
+		{
+			name:  "any owner without a format",
+			owner: &mcpserverv1.Owner{Team: "payments", Contact: "#payments"},
+		},
+		{
+			name:   "owner matching the format",
+			owner:  &mcpserverv1.Owner{Team: "team-payments", Contact: "payments@example.com"},
+			format: format,
+		},
+		{
+			name:   "owner without a contact",
+			owner:  &mcpserverv1.Owner{Team: "team-payments"},
+			format: format,
+		},
+		{
+			name:    "team not matching the format",
+			owner:   &mcpserverv1.Owner{Team: "payments"},
+			format:  format,
+			wantErr: true,
+		},
+		{
+			name:    "contact not matching the format",
+			owner:   &mcpserverv1.Owner{Team: "team-payments", Contact: "#payments"},
+			format:  format,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &MCPServerReconciler{OwnerFormat: tt.format}
+			cr := &mcpserverv1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+				Spec:       mcpserverv1.MCPServerSpec{Owner: tt.owner},
+			}
+			condition := r.getOwnerCondition(cr)
+			if (condition != nil) != tt.wantErr {
+				t.Fatalf("getOwnerCondition() = %v, want a condition %v", condition, tt.wantErr)
+			}
+			if condition != nil && (condition.Reason != ReasonInvalidOwner || condition.Status != metav1.ConditionFalse) {
+				t.Errorf("getOwnerCondition() = %v, want reason %s", condition, ReasonInvalidOwner)
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_createChild_ownerMetadata(t *testing.T) {
+	fakeScheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(fakeScheme); err != nil {
+		t.Errorf("failed to add client-go scheme: %v", err)
+	}
+	if err := mcpserverv1.AddToScheme(fakeScheme); err != nil {
+		t.Errorf("failed to add mcpserverv1 scheme: %v", err)
+	}
+
+	newDeployment := func(labels, annotations map[string]string) *appsv1.Deployment {
+		selector := map[string]string{mcpServerAppLabelKey: mcpServerName}
+		labels[mcpServerAppLabelKey] = mcpServerName
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        mcpServerName,
+				Namespace:   testNamespace,
+				Labels:      labels,
+				Annotations: annotations,
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: mcpserverv1.GroupVersion.String(),
+					Kind:       "MCPServer",
+					Name:       mcpServerName,
+					UID:        "uid",
+					Controller: ptr.To(true),
+				}},
+			},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: selector},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: selector},
+					Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "mcp-server"}}},
+				},
+			},
+		}
+	}
+	payments := &mcpserverv1.Owner{Team: "payments", Contact: "payments@example.com"}
+
+	tests := []struct {
+		name        string
+		owner       *mcpserverv1.Owner
+		ignore      *mcpserverv1.IgnoreDifferences
+		existing    *appsv1.Deployment
+		wantTeam    string
+		wantContact string
+	}{
+		{
+			name:        "Verify that a new child gets the owner label and annotation",
+			owner:       payments,
+			wantTeam:    "payments",
+			wantContact: "payments@example.com",
+		},
+		{
+			name:        "Verify that an existing child gets the owner label and annotation",
+			owner:       payments,
+			existing:    newDeployment(map[string]string{}, nil),
+			wantTeam:    "payments",
+			wantContact: "payments@example.com",
+		},
+		{
+			name:  "Verify that a changed owner is updated on an existing child",
+			owner: &mcpserverv1.Owner{Team: "billing"},
+			existing: newDeployment(map[string]string{OwnerTeamLabel: "payments"},
+				map[string]string{OwnerContactAnnotation: "payments@example.com"}),
+			wantTeam: "billing",
+		},
+		{
+			name: "Verify that a removed owner is removed from an existing child",
+			existing: newDeployment(map[string]string{OwnerTeamLabel: "payments"},
+				map[string]string{OwnerContactAnnotation: "payments@example.com"}),
+		},
+		{
+			name:   "Verify that an ignored annotation keeps the value set outside the operator",
+			owner:  payments,
+			ignore: &mcpserverv1.IgnoreDifferences{Annotations: []string{"mcpserver.opendatahub.io/*"}},
+			existing: newDeployment(map[string]string{},
+				map[string]string{OwnerContactAnnotation: "oncall@example.com"}),
+			wantTeam:    "payments",
+			wantContact: "oncall@example.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithScheme(fakeScheme)
+			if tt.existing != nil {
+				builder = builder.WithObjects(tt.existing)
+			}
+			cli := builder.Build()
+			r := &MCPServerReconciler{Client: cli, Scheme: fakeScheme}
+			cr := &mcpserverv1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace, UID: "uid"},
+				Spec:       mcpserverv1.MCPServerSpec{Owner: tt.owner, IgnoreDifferences: tt.ignore},
+			}
+
+			desired := newDeployment(map[string]string{}, nil)
+			if err := r.createChild(context.Background(), cli, cr, desired); err != nil {
+				t.Fatalf("createChild() error = %v", err)
+			}
+			if _, ok := desired.Spec.Selector.MatchLabels[OwnerTeamLabel]; ok {
+				t.Errorf("createChild() added the owner label to the selector")
+			}
+
+			got := &appsv1.Deployment{}
+			if err := cli.Get(context.Background(), client.ObjectKeyFromObject(desired), got); err != nil {
+				t.Fatalf("failed to get the Deployment: %v", err)
+			}
+			if team := got.Labels[OwnerTeamLabel]; team != tt.wantTeam {
+				t.Errorf("owner team label = %q, want %q", team, tt.wantTeam)
+			}
+			if contact := got.Annotations[OwnerContactAnnotation]; contact != tt.wantContact {
+				t.Errorf("owner contact annotation = %q, want %q", contact, tt.wantContact)
+			}
+			if got.Labels[mcpServerAppLabelKey] != mcpServerName {
+				t.Errorf("labels = %v, want the app label kept", got.Labels)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	routev1 "github.com/openshift/api/route/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+const (
+	// veleroExcludeFromBackupLabel tells Velero to skip an object when backing up a namespace.
+	// It is set on children that are transient or are regenerated by the operator.
+	veleroExcludeFromBackupLabel = "velero.io/exclude-from-backup"
+
+	// ReasonAdopted is the reason of the event emitted when an MCPServer adopts an existing object.
+	ReasonAdopted = "Adopted"
+)
+
+// createChild sets the MCPServer as controller of obj and creates it. If the object already exists, its
+// owner references are repaired instead, so children restored from a backup are adopted by the new MCPServer,
+// and the owner team label and contact annotation of the MCPServer and the backup exclusion of obj are updated.
+func (r *MCPServerReconciler) createChild(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer, obj client.Object) error {
+	err := ctrl.SetControllerReference(cr, obj, r.Scheme)
+	if err != nil {
+		return err
+	}
+
+	withOwnerMetadata(cr, obj)
+	err = cli.Create(ctx, obj)
+	if err == nil {
+		return nil
+	}
+	if !k8serr.IsAlreadyExists(err) {
+		return err
+	}
+
+	existing, ok := reflect.New(reflect.TypeOf(obj).Elem()).Interface().(client.Object)
+	if !ok {
+		return nil
+	}
+	if u, ok := existing.(*unstructured.Unstructured); ok {
+		// Children without a Go type, such as ServiceMonitors, need their kind to be read.
+		u.SetGroupVersionKind(obj.GetObjectKind().GroupVersionKind())
+	}
+	err = cli.Get(ctx, client.ObjectKeyFromObject(obj), existing)
+	if err != nil {
+		return err
+	}
+	if metav1.GetControllerOf(existing) == nil && existing.GetAnnotations()[mcpserverv1.AdoptAnnotation] == "true" {
+		return r.adoptChild(ctx, cli, cr, existing, obj)
+	}
+	if err := r.reconcileOwnerReference(ctx, cli, cr, existing); err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(existing, cr) {
+		return nil
+	}
+	if err := reconcileBackupExclusion(ctx, cli, obj, existing); err != nil {
+		return err
+	}
+	return reconcileOwnerMetadata(ctx, cli, cr, existing)
+}
+
+// reconcileBackupExclusion excludes the existing child from backups when obj, its desired state, is excluded, so
+// that children created by earlier versions of the operator are excluded as well.
+func reconcileBackupExclusion(ctx context.Context, cli client.Client, obj, existing client.Object) error {
+	if obj.GetLabels()[veleroExcludeFromBackupLabel] != "true" ||
+		existing.GetLabels()[veleroExcludeFromBackupLabel] == "true" {
+		return nil
+	}
+	original, ok := existing.DeepCopyObject().(client.Object)
+	if !ok {
+		return fmt.Errorf("unable to copy %T %s to exclude it from backups", existing, existing.GetName())
+	}
+	labels, _ := withEntry(existing.GetLabels(), veleroExcludeFromBackupLabel, "true")
+	existing.SetLabels(labels)
+	logChildDiff(ctx, original, existing)
+	return cli.Patch(ctx, existing, client.MergeFrom(original))
+}
+
+// adoptChild takes over a manually created object that was marked for adoption: it sets the MCPServer as its
+// controller, adds the MCP server labels and replaces the parts of its spec the operator manages with the
+// desired ones. Immutable fields, such as the selector of a Deployment or the cluster IP of a Service, are kept.
+func (r *MCPServerReconciler) adoptChild(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer, existing, desired client.Object) error {
+	original, ok := existing.DeepCopyObject().(client.Object)
+	if !ok {
+		return fmt.Errorf("unable to copy %T %s to adopt it", existing, existing.GetName())
+	}
+
+	switch e := existing.(type) {
+	case *appsv1.Deployment:
+		d := desired.(*appsv1.Deployment)
+		selector := e.Spec.Selector
+		replicas := e.Spec.Replicas
+		e.Spec = *d.Spec.DeepCopy()
+		// The selector cannot be changed, so the pods keep the labels it selects on as well.
+		e.Spec.Selector = selector
+		if selector != nil {
+			for key, value := range selector.MatchLabels {
+				if e.Spec.Template.Labels == nil {
+					e.Spec.Template.Labels = map[string]string{}
+				}
+				e.Spec.Template.Labels[key] = value
+			}
+		}
+		if e.Spec.Replicas == nil || ignoresReplicas(cr) || cr.Spec.Autoscaling != nil {
+			e.Spec.Replicas = replicas
+		}
+	case *corev1.Service:
+		d := desired.(*corev1.Service)
+		e.Spec.Selector = d.Spec.Selector
+		e.Spec.Ports = d.Spec.Ports
+	case *routev1.Route:
+		d := desired.(*routev1.Route)
+		e.Spec.To = d.Spec.To
+		e.Spec.Port = d.Spec.Port
+	default:
+		return r.reconcileOwnerReference(ctx, cli, cr, existing)
+	}
+
+	labels := existing.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for key, value := range desired.GetLabels() {
+		labels[key] = value
+	}
+	existing.SetLabels(labels)
+
+	err := ctrl.SetControllerReference(cr, existing, r.Scheme)
+	if err != nil {
+		return err
+	}
+	logChildDiff(ctx, original, existing)
+	err = cli.Update(ctx, existing)
+	if err != nil {
+		return err
+	}
+
+	if r.Recorder != nil {
+		r.Recorder.Eventf(cr, corev1.EventTypeNormal, ReasonAdopted, "Adopted %s %s",
+			reflect.TypeOf(existing).Elem().Name(), existing.GetName())
+	}
+	return nil
+}
+
+// reconcileOwnerReference replaces owner references to a previous incarnation of the MCPServer with one to cr.
+// After a namespace restore the MCPServer is recreated with a new UID, leaving restored children pointing at an
+// owner that no longer exists; without this they would be garbage collected or never be reconciled again.
+func (r *MCPServerReconciler) reconcileOwnerReference(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer, obj client.Object) error {
+	stale := false
+	refs := make([]metav1.OwnerReference, 0, len(obj.GetOwnerReferences()))
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.Kind == "MCPServer" && ref.APIVersion == mcpserverv1.GroupVersion.String() &&
+			ref.Name == cr.Name && ref.UID != cr.UID {
+			stale = true
+			continue
+		}
+		refs = append(refs, ref)
+	}
+	if !stale {
+		return nil
+	}
+
+	original, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return fmt.Errorf("unable to copy %T %s to repair its owner references", obj, obj.GetName())
+	}
+	obj.SetOwnerReferences(refs)
+	err := ctrl.SetControllerReference(cr, obj, r.Scheme)
+	if err != nil {
+		return err
+	}
+	logChildDiff(ctx, original, obj)
+	return cli.Update(ctx, obj)
+}
+
+// deleteChild deletes the object with the name and namespace of obj if it exists and is controlled by the MCPServer.
+func (r *MCPServerReconciler) deleteChild(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer, obj client.Object,
+	opts ...client.DeleteOption) error {
+	err := cli.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+	if k8serr.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(obj, cr) {
+		return nil
+	}
+	err = cli.Delete(ctx, obj, opts...)
+	if err != nil && !k8serr.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
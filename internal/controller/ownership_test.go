@@ -0,0 +1,235 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMCPServerReconciler_createChild(t *testing.T) {
+	fakeScheme := runtime.NewScheme()
+	err := clientgoscheme.AddToScheme(fakeScheme)
+	if err != nil {
+		t.Errorf("failed to add client-go scheme: %v", err)
+	}
+	err = mcpserverv1.AddToScheme(fakeScheme)
+	if err != nil {
+		t.Errorf("failed to add mcpserverv1 scheme: %v", err)
+	}
+
+	mcpServer := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mcpServerName,
+			Namespace: testNamespace,
+			UID:       types.UID("new-uid"),
+		},
+	}
+
+	newDeployment := func(ownerUID types.UID) *appsv1.Deployment {
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      mcpServerName,
+				Namespace: testNamespace,
+			},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "mcp-server"}},
+					},
+				},
+			},
+		}
+		if ownerUID != "" {
+			deployment.OwnerReferences = []metav1.OwnerReference{{
+				APIVersion: mcpserverv1.GroupVersion.String(),
+				Kind:       "MCPServer",
+				Name:       mcpServerName,
+				UID:        ownerUID,
+				Controller: ptr.To(true),
+			}}
+		}
+		return deployment
+	}
+
+	tests := []struct {
+		name     string
+		existing *appsv1.Deployment
+		wantUID  types.UID
+	}{
+		{
+			name:    "Verify that a new child is created with the MCPServer as controller",
+			wantUID: "new-uid",
+		},
+		{
+			name:     "Verify that a restored child owned by a previous MCPServer is re-adopted",
+			existing: newDeployment("old-uid"),
+			wantUID:  "new-uid",
+		},
+		{
+			name:     "Verify that a child already owned by the MCPServer is left as is",
+			existing: newDeployment("new-uid"),
+			wantUID:  "new-uid",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithScheme(fakeScheme)
+			if tt.existing != nil {
+				builder = builder.WithRuntimeObjects(tt.existing)
+			}
+			cli := builder.Build()
+
+			r := &MCPServerReconciler{
+				Client: cli,
+				Scheme: fakeScheme,
+			}
+			if err := r.createChild(context.Background(), cli, mcpServer, newDeployment("")); err != nil {
+				t.Fatalf("createChild() error = %v", err)
+			}
+
+			found := &appsv1.Deployment{}
+			err := cli.Get(context.Background(), types.NamespacedName{Name: mcpServerName, Namespace: testNamespace}, found)
+			if err != nil {
+				t.Fatalf("failed to get deployment for verification: %v", err)
+			}
+			if len(found.OwnerReferences) != 1 {
+				t.Fatalf("got %d owner references, want 1", len(found.OwnerReferences))
+			}
+			if found.OwnerReferences[0].UID != tt.wantUID {
+				t.Errorf("owner reference UID = %v, want %v", found.OwnerReferences[0].UID, tt.wantUID)
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_adoptChild(t *testing.T) {
+	fakeScheme := runtime.NewScheme()
+	err := clientgoscheme.AddToScheme(fakeScheme)
+	if err != nil {
+		t.Errorf("failed to add client-go scheme: %v", err)
+	}
+	err = mcpserverv1.AddToScheme(fakeScheme)
+	if err != nil {
+		t.Errorf("failed to add mcpserverv1 scheme: %v", err)
+	}
+
+	mcpServer := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mcpServerName,
+			Namespace: testNamespace,
+			UID:       types.UID("uid"),
+		},
+		Spec: mcpserverv1.MCPServerSpec{
+			Image: mcpServerImage,
+		},
+	}
+
+	manualDeployment := func(annotations map[string]string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        mcpServerName,
+				Namespace:   testNamespace,
+				Labels:      map[string]string{"app": "my-mcp"},
+				Annotations: annotations,
+			},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.To[int32](2),
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "my-mcp"}},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "my-mcp"}},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "server", Image: "old-image"}},
+					},
+				},
+			},
+		}
+	}
+	adopt := map[string]string{mcpserverv1.AdoptAnnotation: "true"}
+
+	tests := []struct {
+		name         string
+		existing     *appsv1.Deployment
+		wantAdopted  bool
+		wantImage    string
+		wantReplicas int32
+	}{
+		{
+			name:         "Verify that a deployment marked for adoption is adopted and brought into shape",
+			existing:     manualDeployment(adopt),
+			wantAdopted:  true,
+			wantImage:    mcpServerImage,
+			wantReplicas: 2,
+		},
+		{
+			name:         "Verify that a deployment without the adopt annotation is left alone",
+			existing:     manualDeployment(nil),
+			wantImage:    "old-image",
+			wantReplicas: 2,
+		},
+		{
+			name: "Verify that a deployment controlled by another owner is not adopted",
+			existing: func() *appsv1.Deployment {
+				deployment := manualDeployment(adopt)
+				deployment.OwnerReferences = []metav1.OwnerReference{{
+					APIVersion: "apps/v1",
+					Kind:       "Other",
+					Name:       "other",
+					UID:        "other-uid",
+					Controller: ptr.To(true),
+				}}
+				return deployment
+			}(),
+			wantImage:    "old-image",
+			wantReplicas: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(tt.existing).Build()
+			r := &MCPServerReconciler{
+				Client: cli,
+				Scheme: fakeScheme,
+			}
+			if err := r.reconcileMCPServerDeployment(context.Background(), cli, mcpServer); err != nil {
+				t.Fatalf("reconcileMCPServerDeployment() error = %v", err)
+			}
+
+			found := &appsv1.Deployment{}
+			err := cli.Get(context.Background(), types.NamespacedName{Name: mcpServerName, Namespace: testNamespace}, found)
+			if err != nil {
+				t.Fatalf("failed to get deployment for verification: %v", err)
+			}
+			if adopted := metav1.IsControlledBy(found, mcpServer); adopted != tt.wantAdopted {
+				t.Errorf("controlled by the MCPServer = %v, want %v", adopted, tt.wantAdopted)
+			}
+			if image := found.Spec.Template.Spec.Containers[0].Image; image != tt.wantImage {
+				t.Errorf("image = %s, want %s", image, tt.wantImage)
+			}
+			if *found.Spec.Replicas != tt.wantReplicas {
+				t.Errorf("replicas = %d, want %d", *found.Spec.Replicas, tt.wantReplicas)
+			}
+			if found.Spec.Selector.MatchLabels["app"] != "my-mcp" {
+				t.Errorf("selector = %v, want it unchanged", found.Spec.Selector.MatchLabels)
+			}
+			if tt.wantAdopted {
+				for _, key := range []string{"app", mcpServerAppLabelKey} {
+					if found.Spec.Template.Labels[key] == "" {
+						t.Errorf("pod template labels %v are missing %s", found.Spec.Template.Labels, key)
+					}
+				}
+				if found.Labels[mcpServerAppLabelKey] != mcpServerName {
+					t.Errorf("labels %v are missing the MCP server label", found.Labels)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// listMCPServerPods returns the pods of the MCP server Deployment.
+func (r *MCPServerReconciler) listMCPServerPods(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) ([]corev1.Pod, error) {
+	pods := &corev1.PodList{}
+	err := cli.List(ctx, pods, client.InNamespace(cr.Namespace), client.MatchingLabels{mcpServerAppLabelKey: resourceName(cr)})
+	if err != nil {
+		return nil, err
+	}
+	return pods.Items, nil
+}
+
+// getPodSummary aggregates the readiness, restart counts and most recent container termination of the
+// MCP server pods, so users do not have to inspect the pods to find out why the Deployment is not available.
+func (r *MCPServerReconciler) getPodSummary(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) (*mcpserverv1.PodSummary, error) {
+	pods, err := r.listMCPServerPods(ctx, cli, cr)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &mcpserverv1.PodSummary{}
+	var lastTermination *corev1.ContainerStateTerminated
+	for _, pod := range pods {
+		summary.Total++
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type == corev1.PodReady && cond.Status == corev1.ConditionTrue {
+				summary.Ready++
+			}
+		}
+
+		for _, status := range pod.Status.ContainerStatuses {
+			summary.Restarts += status.RestartCount
+			for _, terminated := range []*corev1.ContainerStateTerminated{status.State.Terminated, status.LastTerminationState.Terminated} {
+				if terminated != nil && (lastTermination == nil || lastTermination.FinishedAt.Before(&terminated.FinishedAt)) {
+					lastTermination = terminated
+				}
+			}
+		}
+	}
+
+	if lastTermination != nil {
+		summary.LastTerminationReason = lastTermination.Reason
+		summary.LastTerminationMessage = lastTermination.Message
+		summary.LastTerminationExitCode = ptr.To(lastTermination.ExitCode)
+	}
+	return summary, nil
+}
@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMCPServerReconciler_getPodSummary(t *testing.T) {
+	mcpServer := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mcpServerName,
+			Namespace: testNamespace,
+		},
+	}
+
+	now := time.Now().Truncate(time.Second)
+
+	readyPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mcpServerName + "-ready",
+			Namespace: testNamespace,
+			Labels:    map[string]string{mcpServerAppLabelKey: mcpServerName},
+		},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         "mcp-server",
+				RestartCount: 1,
+				LastTerminationState: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{
+						Reason:     "Error",
+						ExitCode:   1,
+						FinishedAt: metav1.NewTime(now.Add(-time.Hour)),
+					},
+				},
+			}},
+		},
+	}
+
+	crashingPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mcpServerName + "-crashing",
+			Namespace: testNamespace,
+			Labels:    map[string]string{mcpServerAppLabelKey: mcpServerName},
+		},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}},
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         "mcp-server",
+				RestartCount: 4,
+				LastTerminationState: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{
+						Reason:     "OOMKilled",
+						Message:    "container exceeded its memory limit",
+						ExitCode:   137,
+						FinishedAt: metav1.NewTime(now),
+					},
+				},
+			}},
+		},
+	}
+
+	otherPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "other",
+			Namespace: testNamespace,
+			Labels:    map[string]string{mcpServerAppLabelKey: "other"},
+		},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		objects []runtime.Object
+		want    *mcpserverv1.PodSummary
+	}{
+		{
+			name: "Verify that an empty summary is returned when there are no pods",
+			want: &mcpserverv1.PodSummary{},
+		},
+		{
+			name:    "Verify that ready pods and restarts are counted",
+			objects: []runtime.Object{readyPod, otherPod},
+			want: &mcpserverv1.PodSummary{
+				Ready:                   1,
+				Total:                   1,
+				Restarts:                1,
+				LastTerminationReason:   "Error",
+				LastTerminationExitCode: ptr.To(int32(1)),
+			},
+		},
+		{
+			name:    "Verify that the most recent termination is reported",
+			objects: []runtime.Object{readyPod, crashingPod, otherPod},
+			want: &mcpserverv1.PodSummary{
+				Ready:                   1,
+				Total:                   2,
+				Restarts:                5,
+				LastTerminationReason:   "OOMKilled",
+				LastTerminationMessage:  "container exceeded its memory limit",
+				LastTerminationExitCode: ptr.To(int32(137)),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().WithRuntimeObjects(tt.objects...).Build()
+			r := &MCPServerReconciler{
+				Client: cli,
+			}
+			got, err := r.getPodSummary(context.Background(), cli, mcpServer)
+			if err != nil {
+				t.Fatalf("getPodSummary() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getPodSummary() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// +kubebuilder:rbac:groups="",resources=resourcequotas,verbs=list
+
+const (
+	// PrereqFailed reports that the namespace of an MCP server does not meet a prerequisite of its pods, which
+	// would keep them from being created or started. The workload is not created while it is True.
+	PrereqFailed = "PrereqFailed"
+
+	// ReasonPullSecretMissing is set on the PrereqFailed condition when an image pull secret of the service
+	// account of the pods does not exist.
+	ReasonPullSecretMissing = "PullSecretMissing"
+
+	// ReasonSCCUnavailable is set on the PrereqFailed condition when the service account of the pods may not use
+	// the SCC spec.securityContext requires.
+	ReasonSCCUnavailable = "SCCUnavailable"
+
+	// ReasonQuotaExceeded is set on the PrereqFailed condition when a ResourceQuota of the namespace has no room
+	// left for the pods.
+	ReasonQuotaExceeded = "QuotaExceeded"
+)
+
+// prereqFailure is a prerequisite of the MCP server pods the namespace does not meet.
+type prereqFailure struct {
+	reason, message string
+}
+
+// getPrereqCondition returns the PrereqFailed condition of cr. The prerequisites are only checked until the
+// Deployment is created, later problems with its pods are reported by the DeploymentAvailable condition.
+func (r *MCPServerReconciler) getPrereqCondition(ctx context.Context, cr *mcpserverv1.MCPServer) (metav1.Condition, error) {
+	deployment := &appsv1.Deployment{}
+	err := r.Get(ctx, client.ObjectKey{Name: resourceName(cr), Namespace: cr.Namespace}, deployment)
+	if err == nil {
+		return metav1.Condition{
+			Type:    PrereqFailed,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonAsExpected,
+			Message: fmt.Sprintf("Deployment %s is created", resourceName(cr)),
+		}, nil
+	}
+	if !k8serr.IsNotFound(err) {
+		return metav1.Condition{}, err
+	}
+
+	var failures []prereqFailure
+	for _, check := range []func(context.Context, *mcpserverv1.MCPServer) ([]prereqFailure, error){
+		r.checkPullSecrets,
+		r.checkSCC,
+		r.checkQuota,
+	} {
+		found, err := check(ctx, cr)
+		if err != nil {
+			return metav1.Condition{}, err
+		}
+		failures = append(failures, found...)
+	}
+	if len(failures) == 0 {
+		return metav1.Condition{
+			Type:    PrereqFailed,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonAsExpected,
+			Message: fmt.Sprintf("Namespace %s meets the prerequisites of the MCP server pods", cr.Namespace),
+		}, nil
+	}
+	messages := make([]string, len(failures))
+	for i, failure := range failures {
+		messages[i] = failure.message
+	}
+	return metav1.Condition{
+		Type:    PrereqFailed,
+		Status:  metav1.ConditionTrue,
+		Reason:  failures[0].reason,
+		Message: strings.Join(messages, "; "),
+	}, nil
+}
+
+// checkPullSecrets reports the image pull secrets of the service account of the pods that do not exist. The
+// kubelet would otherwise fail to pull the image from a private registry without telling why.
+func (r *MCPServerReconciler) checkPullSecrets(ctx context.Context, cr *mcpserverv1.MCPServer) ([]prereqFailure, error) {
+	serviceAccount, err := r.getPodServiceAccount(ctx, cr)
+	if err != nil || serviceAccount == nil {
+		return nil, err
+	}
+
+	var failures []prereqFailure
+	for _, ref := range serviceAccount.ImagePullSecrets {
+		err := r.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: cr.Namespace}, &corev1.Secret{})
+		if k8serr.IsNotFound(err) {
+			failures = append(failures, prereqFailure{
+				reason: ReasonPullSecretMissing,
+				message: fmt.Sprintf("Image pull secret %s of service account %s does not exist, the registry of "+
+					"image %s may refuse to serve it", ref.Name, serviceAccount.Name, cr.Spec.Image),
+			})
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	return failures, nil
+}
+
+// checkSCC reports when the service account of the pods may not use the SCC that the security contexts require
+// on OpenShift, which would keep the ReplicaSet from creating the pods. The check is skipped when the operator
+// may not review the access of the service account, as in namespace-scoped mode.
+func (r *MCPServerReconciler) checkSCC(ctx context.Context, cr *mcpserverv1.MCPServer) ([]prereqFailure, error) {
+	scc := r.requiredSCC(cr)
+	if scc == "" {
+		return nil, nil
+	}
+	serviceAccount := podServiceAccountName(cr)
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   fmt.Sprintf("system:serviceaccount:%s:%s", cr.Namespace, serviceAccount),
+			Groups: []string{"system:serviceaccounts", "system:serviceaccounts:" + cr.Namespace},
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: cr.Namespace,
+				Verb:      "use",
+				Group:     "security.openshift.io",
+				Resource:  "securitycontextconstraints",
+				Name:      scc,
+			},
+		},
+	}
+	if err := r.Create(ctx, review); err != nil {
+		if k8serr.IsForbidden(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if review.Status.Allowed {
+		return nil, nil
+	}
+	return []prereqFailure{{
+		reason: ReasonSCCUnavailable,
+		message: fmt.Sprintf("Service account %s may not use the SCC %s that spec.securityContext requires, grant "+
+			"it with oc adm policy add-scc-to-user %s -z %s -n %s", serviceAccount, scc, scc, serviceAccount,
+			cr.Namespace),
+	}}, nil
+}
+
+// quotaResources maps the resources a ResourceQuota may limit to the part of the requirements of a container
+// they count.
+var quotaResources = map[corev1.ResourceName]func(corev1.ResourceRequirements) resource.Quantity{
+	corev1.ResourceCPU:            func(r corev1.ResourceRequirements) resource.Quantity { return r.Requests[corev1.ResourceCPU] },
+	corev1.ResourceMemory:         func(r corev1.ResourceRequirements) resource.Quantity { return r.Requests[corev1.ResourceMemory] },
+	corev1.ResourceRequestsCPU:    func(r corev1.ResourceRequirements) resource.Quantity { return r.Requests[corev1.ResourceCPU] },
+	corev1.ResourceRequestsMemory: func(r corev1.ResourceRequirements) resource.Quantity { return r.Requests[corev1.ResourceMemory] },
+	corev1.ResourceLimitsCPU:      func(r corev1.ResourceRequirements) resource.Quantity { return r.Limits[corev1.ResourceCPU] },
+	corev1.ResourceLimitsMemory:   func(r corev1.ResourceRequirements) resource.Quantity { return r.Limits[corev1.ResourceMemory] },
+}
+
+// checkQuota reports the ResourceQuotas of the namespace that have no room left for the pods of the MCP server.
+// Quotas with scopes are skipped, as they may not apply to the pods.
+func (r *MCPServerReconciler) checkQuota(ctx context.Context, cr *mcpserverv1.MCPServer) ([]prereqFailure, error) {
+	quotas := &corev1.ResourceQuotaList{}
+	if err := r.List(ctx, quotas, client.InNamespace(cr.Namespace)); err != nil {
+		return nil, err
+	}
+	if len(quotas.Items) == 0 {
+		return nil, nil
+	}
+
+	replicas := int64(ptr.Deref(cr.Spec.Replicas, 1))
+	containers := r.withSidecars(cr, []corev1.Container{{Name: "mcp-server", Resources: r.resources(cr)}})
+	need := corev1.ResourceList{corev1.ResourcePods: *resource.NewQuantity(replicas, resource.DecimalSI)}
+	for name, requirement := range quotaResources {
+		perPod := resource.Quantity{}
+		for _, container := range containers {
+			perPod.Add(requirement(container.Resources))
+		}
+		if perPod.IsZero() {
+			continue
+		}
+		total := resource.Quantity{}
+		for range replicas {
+			total.Add(perPod)
+		}
+		need[name] = total
+	}
+
+	var failures []prereqFailure
+	for _, quota := range quotas.Items {
+		if len(quota.Spec.Scopes) > 0 || quota.Spec.ScopeSelector != nil {
+			continue
+		}
+		// The resources are checked in order, so that the message does not change between reconciles.
+		names := slices.Sorted(maps.Keys(quota.Status.Hard))
+		for _, name := range names {
+			hard := quota.Status.Hard[name]
+			wanted, ok := need[name]
+			if !ok {
+				continue
+			}
+			left := hard.DeepCopy()
+			left.Sub(quota.Status.Used[name])
+			if wanted.Cmp(left) <= 0 {
+				continue
+			}
+			failures = append(failures, prereqFailure{
+				reason: ReasonQuotaExceeded,
+				message: fmt.Sprintf("ResourceQuota %s has %s of %s left, the pods of the MCP server need %s",
+					quota.Name, left.String(), name, wanted.String()),
+			})
+		}
+	}
+	return failures, nil
+}
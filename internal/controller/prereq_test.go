@@ -0,0 +1,219 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster"
+)
+
+func TestMCPServerReconciler_getPrereqCondition(t *testing.T) {
+	serviceAccount := &corev1.ServiceAccount{
+		ObjectMeta:       metav1.ObjectMeta{Name: defaultServiceAccountName, Namespace: testNamespace},
+		ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry-pull"}},
+	}
+	pullSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "registry-pull", Namespace: testNamespace}}
+	quota := func(hard, used corev1.ResourceList) *corev1.ResourceQuota {
+		return &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: "compute", Namespace: testNamespace},
+			Status:     corev1.ResourceQuotaStatus{Hard: hard, Used: used},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		objects       []client.Object
+		spec          mcpserverv1.MCPServerSpec
+		sccAllowed    bool
+		wantStatus    metav1.ConditionStatus
+		wantReason    string
+		wantSubstring string
+	}{
+		{
+			name:       "Verify that a namespace without pull secrets or quotas meets the prerequisites",
+			wantStatus: metav1.ConditionFalse,
+			wantReason: ReasonAsExpected,
+		},
+		{
+			name:       "Verify that existing pull secrets meet the prerequisites",
+			objects:    []client.Object{serviceAccount, pullSecret},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: ReasonAsExpected,
+		},
+		{
+			name:          "Verify that a missing pull secret is reported",
+			objects:       []client.Object{serviceAccount},
+			wantStatus:    metav1.ConditionTrue,
+			wantReason:    ReasonPullSecretMissing,
+			wantSubstring: "Image pull secret registry-pull of service account default does not exist",
+		},
+		{
+			name: "Verify that a quota without room for the pods is reported",
+			objects: []client.Object{quota(
+				corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("2")},
+				corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("1950m")},
+			)},
+			spec:          mcpserverv1.MCPServerSpec{ResourcesPreset: mcpserverv1.ResourcesPresetSmall},
+			wantStatus:    metav1.ConditionTrue,
+			wantReason:    ReasonQuotaExceeded,
+			wantSubstring: "ResourceQuota compute has 50m of requests.cpu left",
+		},
+		{
+			name: "Verify that the pods of all replicas are counted against a quota",
+			objects: []client.Object{quota(
+				corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")},
+				corev1.ResourceList{corev1.ResourcePods: resource.MustParse("8")},
+			)},
+			spec:          mcpserverv1.MCPServerSpec{Replicas: ptr.To(int32(3))},
+			wantStatus:    metav1.ConditionTrue,
+			wantReason:    ReasonQuotaExceeded,
+			wantSubstring: "the pods of the MCP server need 3",
+		},
+		{
+			name: "Verify that a quota with room for the pods meets the prerequisites",
+			objects: []client.Object{quota(
+				corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")},
+				corev1.ResourceList{corev1.ResourcePods: resource.MustParse("2")},
+			)},
+			spec:       mcpserverv1.MCPServerSpec{Replicas: ptr.To(int32(3))},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: ReasonAsExpected,
+		},
+		{
+			name:          "Verify that an SCC the service account may not use is reported",
+			spec:          mcpserverv1.MCPServerSpec{SecurityContext: &mcpserverv1.SecurityContext{RunAsUser: ptr.To(int64(0))}},
+			wantStatus:    metav1.ConditionTrue,
+			wantReason:    ReasonSCCUnavailable,
+			wantSubstring: "oc adm policy add-scc-to-user anyuid -z test-mcpserver -n test-namespace",
+		},
+		{
+			name:       "Verify that an SCC the service account may use meets the prerequisites",
+			spec:       mcpserverv1.MCPServerSpec{SecurityContext: &mcpserverv1.SecurityContext{RunAsUser: ptr.To(int64(0))}},
+			sccAllowed: true,
+			wantStatus: metav1.ConditionFalse,
+			wantReason: ReasonAsExpected,
+		},
+		{
+			name: "Verify that the prerequisites are not checked once the Deployment is created",
+			objects: []client.Object{serviceAccount, &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+			}},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: ReasonAsExpected,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeScheme := newAutoscalingScheme(t)
+			cr := &mcpserverv1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+				Spec:       tt.spec,
+			}
+			cr.Spec.Image = "registry.example.com/tools:1"
+			reviewAccess := interceptor.Funcs{
+				Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					if review, ok := obj.(*authorizationv1.SubjectAccessReview); ok {
+						review.Status.Allowed = tt.sccAllowed
+						return nil
+					}
+					return c.Create(ctx, obj, opts...)
+				},
+			}
+			cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(tt.objects...).
+				WithInterceptorFuncs(reviewAccess).Build()
+			r := &MCPServerReconciler{
+				Client:   cli,
+				Scheme:   fakeScheme,
+				Platform: &cluster.Platform{Name: cluster.OpenShift},
+			}
+
+			got, err := r.getPrereqCondition(context.Background(), cr)
+			if err != nil {
+				t.Fatalf("getPrereqCondition() error = %v", err)
+			}
+			if got.Status != tt.wantStatus || got.Reason != tt.wantReason {
+				t.Errorf("getPrereqCondition() = %s/%s, want %s/%s: %s", got.Status, got.Reason, tt.wantStatus,
+					tt.wantReason, got.Message)
+			}
+			if !strings.Contains(got.Message, tt.wantSubstring) {
+				t.Errorf("getPrereqCondition() message = %q, want it to contain %q", got.Message, tt.wantSubstring)
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_Reconcile_prereqFailed(t *testing.T) {
+	fakeScheme := newAutoscalingScheme(t)
+	cr := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+		Spec:       mcpserverv1.MCPServerSpec{Image: "registry.example.com/tools:1"},
+	}
+	serviceAccount := &corev1.ServiceAccount{
+		ObjectMeta:       metav1.ObjectMeta{Name: defaultServiceAccountName, Namespace: testNamespace},
+		ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry-pull"}},
+	}
+	cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithStatusSubresource(&mcpserverv1.MCPServer{}).
+		WithObjects(cr, serviceAccount).Build()
+	recorder := record.NewFakeRecorder(10)
+	r := &MCPServerReconciler{
+		Client:   cli,
+		Scheme:   fakeScheme,
+		Platform: &cluster.Platform{Name: cluster.Kubernetes},
+		Recorder: recorder,
+	}
+
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cr)})
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if result.RequeueAfter != DefaultRequeueInterval {
+		t.Errorf("Reconcile() requeues after %v, want %v", result.RequeueAfter, DefaultRequeueInterval)
+	}
+	deployment := &appsv1.Deployment{}
+	if err := cli.Get(context.Background(), client.ObjectKey{Name: mcpServerName, Namespace: testNamespace},
+		deployment); err == nil {
+		t.Error("Deployment created, want no workload until the prerequisites are met")
+	}
+	if err := cli.Get(context.Background(), client.ObjectKeyFromObject(cr), cr); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	available := meta.FindStatusCondition(cr.Status.Conditions, OverallAvailable)
+	if available == nil || available.Reason != PrereqFailed {
+		t.Errorf("Available condition = %+v, want reason %s", available, PrereqFailed)
+	}
+	if !meta.IsStatusConditionTrue(cr.Status.Conditions, PrereqFailed) {
+		t.Errorf("PrereqFailed condition is not True: %+v", cr.Status.Conditions)
+	}
+	if len(recorder.Events) != 1 {
+		t.Errorf("%d events emitted, want 1", len(recorder.Events))
+	}
+
+	// The workload is created once the pull secret exists.
+	if err := cli.Create(context.Background(), &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "registry-pull", Namespace: testNamespace},
+	}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cr)}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := cli.Get(context.Background(), client.ObjectKey{Name: mcpServerName, Namespace: testNamespace},
+		deployment); err != nil {
+		t.Errorf("Get() Deployment error = %v, want it created", err)
+	}
+}
@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"fmt"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/yaml"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// ResourcePresets maps the presets an MCPServer can select in spec.resourcesPreset to the resource requests
+// and limits of the MCP server container.
+type ResourcePresets map[mcpserverv1.ResourcesPreset]corev1.ResourceRequirements
+
+// DefaultResourcePresets returns the presets used when the operator configuration does not define them.
+func DefaultResourcePresets() ResourcePresets {
+	return ResourcePresets{
+		mcpserverv1.ResourcesPresetSmall:  resourceRequirements("100m", "256Mi", "500m", "512Mi"),
+		mcpserverv1.ResourcesPresetMedium: resourceRequirements("250m", "512Mi", "1", "1Gi"),
+		mcpserverv1.ResourcesPresetLarge:  resourceRequirements("500m", "1Gi", "2", "2Gi"),
+	}
+}
+
+// LoadResourcePresets reads presets from a YAML file that maps preset names to resource requirements.
+// Presets missing from the file keep their defaults.
+func LoadResourcePresets(path string) (ResourcePresets, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides := ResourcePresets{}
+	if err := yaml.UnmarshalStrict(data, &overrides); err != nil {
+		return nil, fmt.Errorf("parsing resource presets %s: %w", path, err)
+	}
+
+	presets := DefaultResourcePresets()
+	for name, requirements := range overrides {
+		if _, ok := presets[name]; !ok {
+			return nil, fmt.Errorf("unknown resource preset %q in %s", name, path)
+		}
+		presets[name] = requirements
+	}
+	return presets, nil
+}
+
+// resources returns the resource requirements of the MCP server container.
+func (r *MCPServerReconciler) resources(cr *mcpserverv1.MCPServer) corev1.ResourceRequirements {
+	if cr.Spec.ResourcesPreset == "" {
+		return corev1.ResourceRequirements{}
+	}
+	presets := r.ResourcePresets
+	if presets == nil {
+		presets = DefaultResourcePresets()
+	}
+	requirements := presets[cr.Spec.ResourcesPreset]
+	return *requirements.DeepCopy()
+}
+
+func resourceRequirements(cpuRequest, memoryRequest, cpuLimit, memoryLimit string) corev1.ResourceRequirements {
+	return corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpuRequest),
+			corev1.ResourceMemory: resource.MustParse(memoryRequest),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpuLimit),
+			corev1.ResourceMemory: resource.MustParse(memoryLimit),
+		},
+	}
+}
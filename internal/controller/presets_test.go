@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestLoadResourcePresets(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		wantSmall corev1.ResourceRequirements
+		wantErr   bool
+	}{
+		{
+			name:      "empty file keeps the defaults",
+			content:   "",
+			wantSmall: DefaultResourcePresets()[mcpserverv1.ResourcesPresetSmall],
+		},
+		{
+			name: "preset is overridden",
+			content: `small:
+  requests:
+    cpu: 50m
+    memory: 128Mi
+  limits:
+    cpu: 200m
+    memory: 256Mi
+`,
+			wantSmall: resourceRequirements("50m", "128Mi", "200m", "256Mi"),
+		},
+		{
+			name:    "unknown preset",
+			content: "huge: {}\n",
+			wantErr: true,
+		},
+		{
+			name:    "unknown field",
+			content: "small:\n  request: {}\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "resource-presets.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("failed to write presets: %v", err)
+			}
+
+			got, err := LoadResourcePresets(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadResourcePresets() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got[mcpserverv1.ResourcesPresetSmall], tt.wantSmall) {
+				t.Errorf("small = %v, want %v", got[mcpserverv1.ResourcesPresetSmall], tt.wantSmall)
+			}
+			if !reflect.DeepEqual(got[mcpserverv1.ResourcesPresetLarge], DefaultResourcePresets()[mcpserverv1.ResourcesPresetLarge]) {
+				t.Errorf("large = %v, want the default", got[mcpserverv1.ResourcesPresetLarge])
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_resources(t *testing.T) {
+	custom := ResourcePresets{
+		mcpserverv1.ResourcesPresetMedium: resourceRequirements("1", "1Gi", "1", "1Gi"),
+	}
+
+	tests := []struct {
+		name    string
+		presets ResourcePresets
+		preset  mcpserverv1.ResourcesPreset
+		want    corev1.ResourceRequirements
+	}{
+		{
+			name: "no preset",
+			want: corev1.ResourceRequirements{},
+		},
+		{
+			name:   "default preset",
+			preset: mcpserverv1.ResourcesPresetLarge,
+			want:   DefaultResourcePresets()[mcpserverv1.ResourcesPresetLarge],
+		},
+		{
+			name:    "configured preset",
+			presets: custom,
+			preset:  mcpserverv1.ResourcesPresetMedium,
+			want:    custom[mcpserverv1.ResourcesPresetMedium],
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &MCPServerReconciler{ResourcePresets: tt.presets}
+			cr := &mcpserverv1.MCPServer{Spec: mcpserverv1.MCPServerSpec{ResourcesPreset: tt.preset}}
+			if got := r.resources(cr); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resources() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
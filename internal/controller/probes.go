@@ -0,0 +1,106 @@
+package controller
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// defaultHealthPath is the health endpoint of the Kubernetes MCP server run by the default command.
+const defaultHealthPath = "/healthz"
+
+// serverProbeHandler returns the handler of the default probes of the MCP server container: a request for the
+// health path on the server port, or a TCP connection to it for servers without a known health path.
+func serverProbeHandler(cr *mcpserverv1.MCPServer) corev1.ProbeHandler {
+	path := ""
+	if cr.Spec.Probes != nil {
+		path = cr.Spec.Probes.Path
+	}
+	if path == "" && cr.Spec.Command == nil && !usesHTTP2(cr) {
+		path = defaultHealthPath
+	}
+	if path == "" {
+		return corev1.ProbeHandler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(mcpServerPort)}}
+	}
+	return corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: path, Port: intstr.FromInt32(mcpServerPort)}}
+}
+
+// serverProbes returns the startup, liveness and readiness probes of the MCP server container: those of
+// spec.probes, or the defaults.
+func serverProbes(cr *mcpserverv1.MCPServer) (startup, liveness, readiness *corev1.Probe) {
+	handler := serverProbeHandler(cr)
+	startup = &corev1.Probe{ProbeHandler: handler, PeriodSeconds: 5, FailureThreshold: 60}
+	liveness = &corev1.Probe{ProbeHandler: *handler.DeepCopy(), PeriodSeconds: 20, TimeoutSeconds: 5, FailureThreshold: 3}
+	readiness = &corev1.Probe{ProbeHandler: *handler.DeepCopy(), PeriodSeconds: 10, TimeoutSeconds: 5, FailureThreshold: 3}
+	if probes := cr.Spec.Probes; probes != nil {
+		if probes.Startup != nil {
+			startup = probes.Startup.DeepCopy()
+		}
+		if probes.Liveness != nil {
+			liveness = probes.Liveness.DeepCopy()
+		}
+		if probes.Readiness != nil {
+			readiness = probes.Readiness.DeepCopy()
+		}
+	}
+	return startup, liveness, readiness
+}
+
+// withServerProbes sets the probes of serverProbes on the MCP server container. The proxy of a Proxy MCP server
+// is left alone.
+func withServerProbes(container *corev1.Container, cr *mcpserverv1.MCPServer) {
+	if isProxy(cr) {
+		return
+	}
+	container.StartupProbe, container.LivenessProbe, container.ReadinessProbe = serverProbes(cr)
+}
+
+// applyProbes sets the probes of the MCP server container of an existing Deployment once spec.probes is set, so
+// that Deployments created without probes only get them on request. Probes whose fields the API server defaulted
+// are kept when they are otherwise the same, so that they do not cause a rollout. Probes removed from spec.probes
+// are reset to the defaults.
+func applyProbes(deployment *appsv1.Deployment, cr *mcpserverv1.MCPServer) {
+	if cr.Spec.Probes == nil {
+		return
+	}
+	for i := range deployment.Spec.Template.Spec.Containers {
+		if container := &deployment.Spec.Template.Spec.Containers[i]; container.Name == "mcp-server" {
+			startup, liveness, readiness := serverProbes(cr)
+			container.StartupProbe = withProbe(container.StartupProbe, startup)
+			container.LivenessProbe = withProbe(container.LivenessProbe, liveness)
+			container.ReadinessProbe = withProbe(container.ReadinessProbe, readiness)
+		}
+	}
+}
+
+// withProbe returns probe, or current when it equals probe with the defaults of the API server.
+func withProbe(current, probe *corev1.Probe) *corev1.Probe {
+	if current != nil && probe != nil && equality.Semantic.DeepEqual(current, withProbeDefaults(probe)) {
+		return current
+	}
+	return probe
+}
+
+// withProbeDefaults returns a copy of probe with its unset fields defaulted as the API server does.
+func withProbeDefaults(probe *corev1.Probe) *corev1.Probe {
+	probe = probe.DeepCopy()
+	if probe.TimeoutSeconds == 0 {
+		probe.TimeoutSeconds = 1
+	}
+	if probe.PeriodSeconds == 0 {
+		probe.PeriodSeconds = 10
+	}
+	if probe.SuccessThreshold == 0 {
+		probe.SuccessThreshold = 1
+	}
+	if probe.FailureThreshold == 0 {
+		probe.FailureThreshold = 3
+	}
+	if probe.HTTPGet != nil && probe.HTTPGet.Scheme == "" {
+		probe.HTTPGet.Scheme = corev1.URISchemeHTTP
+	}
+	return probe
+}
@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+func Test_serverProbes(t *testing.T) {
+	execProbe := &corev1.Probe{ProbeHandler: corev1.ProbeHandler{Exec: &corev1.ExecAction{Command: []string{"true"}}}}
+
+	tests := []struct {
+		name          string
+		spec          mcpserverv1.MCPServerSpec
+		wantPath      string
+		wantLiveness  *corev1.Probe
+		wantReadiness *corev1.Probe
+	}{
+		{
+			name:     "Verify that the default command is probed at its health endpoint",
+			wantPath: defaultHealthPath,
+		},
+		{
+			name: "Verify that a custom command is probed with a TCP connection",
+			spec: mcpserverv1.MCPServerSpec{Command: CustomMCPDeploymentCommand},
+		},
+		{
+			name: "Verify that a GRPC server is probed with a TCP connection",
+			spec: mcpserverv1.MCPServerSpec{Protocol: mcpserverv1.ProtocolGRPC},
+		},
+		{
+			name:     "Verify that a custom command is probed at the path of spec.probes",
+			spec:     mcpserverv1.MCPServerSpec{Command: CustomMCPDeploymentCommand, Probes: &mcpserverv1.Probes{Path: "/health"}},
+			wantPath: "/health",
+		},
+		{
+			name:          "Verify that the probes of spec.probes replace the defaults",
+			spec:          mcpserverv1.MCPServerSpec{Probes: &mcpserverv1.Probes{Liveness: execProbe, Readiness: execProbe}},
+			wantPath:      defaultHealthPath,
+			wantLiveness:  execProbe,
+			wantReadiness: execProbe,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &mcpserverv1.MCPServer{Spec: tt.spec}
+
+			startup, liveness, readiness := serverProbes(cr)
+			if startup.HTTPGet == nil && tt.wantPath != "" || startup.HTTPGet != nil && startup.HTTPGet.Path != tt.wantPath {
+				t.Errorf("startup probe = %+v, want a request for %q", startup.ProbeHandler, tt.wantPath)
+			}
+			if tt.wantPath == "" && (startup.TCPSocket == nil || startup.TCPSocket.Port != intstr.FromInt32(8000)) {
+				t.Errorf("startup probe = %+v, want a TCP connection to the server port", startup.ProbeHandler)
+			}
+			if tt.wantLiveness != nil && !equality.Semantic.DeepEqual(liveness, tt.wantLiveness) {
+				t.Errorf("liveness probe = %+v, want %+v", liveness, tt.wantLiveness)
+			}
+			if tt.wantReadiness != nil && !equality.Semantic.DeepEqual(readiness, tt.wantReadiness) {
+				t.Errorf("readiness probe = %+v, want %+v", readiness, tt.wantReadiness)
+			}
+		})
+	}
+}
+
+func Test_applyProbes(t *testing.T) {
+	newDeployment := func(readiness *corev1.Probe) *appsv1.Deployment {
+		return &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "mcp-server", ReadinessProbe: readiness}},
+		}}}}
+	}
+	readiness := &corev1.Probe{
+		ProbeHandler:  corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: "/ready", Port: intstr.FromInt32(8000)}},
+		PeriodSeconds: 5,
+	}
+
+	tests := []struct {
+		name          string
+		probes        *mcpserverv1.Probes
+		existing      *corev1.Probe
+		wantReadiness *corev1.Probe
+	}{
+		{
+			name: "Verify that a Deployment without probes is left alone without spec.probes",
+		},
+		{
+			name:          "Verify that the probe of spec.probes is applied",
+			probes:        &mcpserverv1.Probes{Readiness: readiness},
+			wantReadiness: readiness,
+		},
+		{
+			name:          "Verify that a probe defaulted by the API server is kept",
+			probes:        &mcpserverv1.Probes{Readiness: readiness},
+			existing:      withProbeDefaults(readiness),
+			wantReadiness: withProbeDefaults(readiness),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deployment := newDeployment(tt.existing)
+			cr := &mcpserverv1.MCPServer{Spec: mcpserverv1.MCPServerSpec{Probes: tt.probes}}
+
+			applyProbes(deployment, cr)
+			container := deployment.Spec.Template.Spec.Containers[0]
+			if !equality.Semantic.DeepEqual(container.ReadinessProbe, tt.wantReadiness) {
+				t.Errorf("readiness probe = %+v, want %+v", container.ReadinessProbe, tt.wantReadiness)
+			}
+			if tt.probes == nil && (container.StartupProbe != nil || container.LivenessProbe != nil) {
+				t.Errorf("probes = %+v, want none", container)
+			}
+			if tt.probes != nil && (container.StartupProbe == nil || container.LivenessProbe == nil) {
+				t.Errorf("probes = %+v, want the defaults", container)
+			}
+		})
+	}
+}
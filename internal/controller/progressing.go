@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+const (
+	// Progressing is True while the Deployment of the MCP server rolls out a change.
+	Progressing = "Progressing"
+	// Ready is True while the MCP server is Available and not rolling out a change, for tools such as kubectl wait
+	// that look for a Ready condition.
+	Ready = "Ready"
+
+	ReasonRolloutInProgress = "RolloutInProgress"
+	ReasonRolloutComplete   = "RolloutComplete"
+)
+
+// getProgressingCondition returns the Progressing condition of cr from the rollout of its Deployment.
+func (r *MCPServerReconciler) getProgressingCondition(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) metav1.Condition {
+	dep := &appsv1.Deployment{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: resourceName(cr), Namespace: cr.Namespace}, dep); err != nil {
+		if k8serr.IsNotFound(err) {
+			return metav1.Condition{
+				Type:    Progressing,
+				Status:  metav1.ConditionTrue,
+				Reason:  fmt.Sprintf("%s%s", "Deployment", ReasonNotFoundSuffix),
+				Message: fmt.Sprintf("Deployment %s is not created yet", resourceName(cr)),
+			}
+		}
+		return metav1.Condition{
+			Type:    Progressing,
+			Status:  metav1.ConditionUnknown,
+			Reason:  fmt.Sprintf("%s%s", "Deployment", ReasonGetFailedSuffix),
+			Message: fmt.Sprintf("Failed to retrieve Deployment %s, %v", cr.Name, err),
+		}
+	}
+
+	if rolloutInProgress(dep) {
+		return metav1.Condition{
+			Type:    Progressing,
+			Status:  metav1.ConditionTrue,
+			Reason:  ReasonRolloutInProgress,
+			Message: fmt.Sprintf("Deployment %s is rolling out generation %d of %s", resourceName(cr), cr.Generation, cr.Name),
+		}
+	}
+	return metav1.Condition{
+		Type:    Progressing,
+		Status:  metav1.ConditionFalse,
+		Reason:  ReasonRolloutComplete,
+		Message: fmt.Sprintf("Deployment %s has rolled out generation %d of %s", resourceName(cr), cr.Generation, cr.Name),
+	}
+}
+
+// rolloutInProgress reports whether the Deployment has not yet replaced all pods with ones of its current pod
+// template. Unlike rolloutComplete, a pod that is merely unavailable does not count as a rollout.
+func rolloutInProgress(dep *appsv1.Deployment) bool {
+	replicas := ptr.Deref(dep.Spec.Replicas, 1)
+	return dep.Status.ObservedGeneration < dep.Generation || dep.Status.UpdatedReplicas < replicas ||
+		dep.Status.Replicas > dep.Status.UpdatedReplicas
+}
+
+// verifiedGeneration reports whether the Available condition of cr was already True for its current generation.
+// Until then, the Available condition of a previous generation is not trusted while a rollout is in progress.
+func verifiedGeneration(cr *mcpserverv1.MCPServer) bool {
+	available := meta.FindStatusCondition(cr.Status.Conditions, OverallAvailable)
+	return available != nil && available.Status == metav1.ConditionTrue && available.ObservedGeneration == cr.Generation
+}
+
+// getReadyCondition returns the Ready condition of cr from its Available and Progressing conditions. It follows
+// Available, except that it is False rather than Unknown during a rollout of the spec, and that it is only True
+// once the rollout of the Deployment is done.
+func getReadyCondition(cr *mcpserverv1.MCPServer) metav1.Condition {
+	available := meta.FindStatusCondition(cr.Status.Conditions, OverallAvailable)
+	if available == nil {
+		return metav1.Condition{
+			Type:    Ready,
+			Status:  metav1.ConditionUnknown,
+			Reason:  fmt.Sprintf("%s%s", OverallAvailable, ReasonNotFoundSuffix),
+			Message: fmt.Sprintf("The availability of %s is not known yet", cr.Name),
+		}
+	}
+	if available.Status != metav1.ConditionTrue {
+		status := available.Status
+		if available.Reason == ReasonRolloutInProgress {
+			status = metav1.ConditionFalse
+		}
+		return metav1.Condition{Type: Ready, Status: status, Reason: available.Reason, Message: available.Message}
+	}
+	if progressing := meta.FindStatusCondition(cr.Status.Conditions, Progressing); progressing != nil &&
+		progressing.Status == metav1.ConditionTrue {
+		return metav1.Condition{
+			Type:    Ready,
+			Status:  metav1.ConditionFalse,
+			Reason:  progressing.Reason,
+			Message: progressing.Message,
+		}
+	}
+	return metav1.Condition{Type: Ready, Status: metav1.ConditionTrue, Reason: available.Reason, Message: available.Message}
+}
+
+// observeGeneration records the generation of cr on all of its conditions, which are evaluated for it on every
+// reconcile, so that clients can tell conditions about a previous spec apart.
+func observeGeneration(cr *mcpserverv1.MCPServer) {
+	for i := range cr.Status.Conditions {
+		cr.Status.Conditions[i].ObservedGeneration = cr.Generation
+	}
+}
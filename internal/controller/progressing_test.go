@@ -0,0 +1,182 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster"
+)
+
+func TestMCPServerReconciler_getProgressingCondition(t *testing.T) {
+	newDeployment := func(generation, observedGeneration int64, replicas, updated int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace, Generation: generation},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](2)},
+			Status: appsv1.DeploymentStatus{
+				ObservedGeneration: observedGeneration,
+				Replicas:           replicas,
+				UpdatedReplicas:    updated,
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		objects    []client.Object
+		wantStatus metav1.ConditionStatus
+		wantReason string
+	}{
+		{
+			name:       "Verify that a missing Deployment is progressing",
+			wantStatus: metav1.ConditionTrue,
+			wantReason: "DeploymentNotFound",
+		},
+		{
+			name:       "Verify that a Deployment whose spec was not observed yet is progressing",
+			objects:    []client.Object{newDeployment(3, 2, 2, 2)},
+			wantStatus: metav1.ConditionTrue,
+			wantReason: ReasonRolloutInProgress,
+		},
+		{
+			name:       "Verify that a Deployment with pods of the previous template is progressing",
+			objects:    []client.Object{newDeployment(3, 3, 3, 2)},
+			wantStatus: metav1.ConditionTrue,
+			wantReason: ReasonRolloutInProgress,
+		},
+		{
+			name:       "Verify that a Deployment with only updated pods has rolled out",
+			objects:    []client.Object{newDeployment(3, 3, 2, 2)},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: ReasonRolloutComplete,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().WithObjects(tt.objects...).Build()
+			r := &MCPServerReconciler{Client: cli}
+			cr := &mcpserverv1.MCPServer{ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace}}
+			got := r.getProgressingCondition(context.Background(), cli, cr)
+			if got.Status != tt.wantStatus || got.Reason != tt.wantReason {
+				t.Errorf("getProgressingCondition() = %s/%s, want %s/%s", got.Status, got.Reason, tt.wantStatus, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_getOverallCondition_generation(t *testing.T) {
+	newMCPServer := func(available metav1.ConditionStatus, availableGeneration int64) *mcpserverv1.MCPServer {
+		return &mcpserverv1.MCPServer{
+			ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace, Generation: 2},
+			Status: mcpserverv1.MCPServerStatus{
+				Conditions: []metav1.Condition{
+					{Type: DeploymentAvailable, Status: metav1.ConditionTrue},
+					{Type: ServiceAvailable, Status: metav1.ConditionTrue},
+					{Type: EndpointReachable, Status: metav1.ConditionTrue},
+					{Type: Progressing, Status: metav1.ConditionTrue, Reason: ReasonRolloutInProgress},
+					{Type: OverallAvailable, Status: available, ObservedGeneration: availableGeneration},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		cr         *mcpserverv1.MCPServer
+		wantStatus metav1.ConditionStatus
+	}{
+		{
+			name:       "Verify that Available of a previous generation is not trusted during a rollout",
+			cr:         newMCPServer(metav1.ConditionTrue, 1),
+			wantStatus: metav1.ConditionUnknown,
+		},
+		{
+			name:       "Verify that Available stays Unknown until the rollout of the new generation is done",
+			cr:         newMCPServer(metav1.ConditionUnknown, 2),
+			wantStatus: metav1.ConditionUnknown,
+		},
+		{
+			name:       "Verify that Available of the current generation survives pods being replaced",
+			cr:         newMCPServer(metav1.ConditionTrue, 2),
+			wantStatus: metav1.ConditionTrue,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &MCPServerReconciler{Platform: &cluster.Platform{Name: cluster.Kubernetes}}
+			if got := r.getOverallCondition(tt.cr); got.Status != tt.wantStatus {
+				t.Errorf("getOverallCondition() = %s (%s), want %s", got.Status, got.Reason, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_getReadyCondition(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions []metav1.Condition
+		wantStatus metav1.ConditionStatus
+		wantReason string
+	}{
+		{
+			name:       "Verify that Ready is Unknown before Available is known",
+			wantStatus: metav1.ConditionUnknown,
+			wantReason: "AvailableNotFound",
+		},
+		{
+			name: "Verify that Ready follows an Available MCP server",
+			conditions: []metav1.Condition{
+				{Type: OverallAvailable, Status: metav1.ConditionTrue, Reason: "EndpointReachable"},
+				{Type: Progressing, Status: metav1.ConditionFalse, Reason: ReasonRolloutComplete},
+			},
+			wantStatus: metav1.ConditionTrue,
+			wantReason: "EndpointReachable",
+		},
+		{
+			name: "Verify that Ready follows an unavailable MCP server",
+			conditions: []metav1.Condition{
+				{Type: OverallAvailable, Status: metav1.ConditionFalse, Reason: ImageNotFound},
+				{Type: Progressing, Status: metav1.ConditionTrue, Reason: ReasonRolloutInProgress},
+			},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: ImageNotFound,
+		},
+		{
+			name: "Verify that Ready is False while a change of the spec rolls out",
+			conditions: []metav1.Condition{
+				{Type: OverallAvailable, Status: metav1.ConditionUnknown, Reason: ReasonRolloutInProgress},
+				{Type: Progressing, Status: metav1.ConditionTrue, Reason: ReasonRolloutInProgress},
+			},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: ReasonRolloutInProgress,
+		},
+		{
+			name: "Verify that Ready is False while pods of an Available MCP server are replaced",
+			conditions: []metav1.Condition{
+				{Type: OverallAvailable, Status: metav1.ConditionTrue, Reason: "EndpointReachable"},
+				{Type: Progressing, Status: metav1.ConditionTrue, Reason: ReasonRolloutInProgress},
+			},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: ReasonRolloutInProgress,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &mcpserverv1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+				Status:     mcpserverv1.MCPServerStatus{Conditions: tt.conditions},
+			}
+			got := getReadyCondition(cr)
+			if got.Type != Ready || got.Status != tt.wantStatus || got.Reason != tt.wantReason {
+				t.Errorf("getReadyCondition() = %s %s/%s, want %s %s/%s", got.Type, got.Status, got.Reason, Ready,
+					tt.wantStatus, tt.wantReason)
+			}
+		})
+	}
+}
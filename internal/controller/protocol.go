@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"context"
+	"net"
+	"net/url"
+
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// h2cAppProtocol is the appProtocol of a Service port that serves cleartext HTTP/2. Gateway API implementations,
+// service meshes and the OpenShift router connect to such ports with HTTP/2 instead of HTTP/1.1.
+const h2cAppProtocol = "kubernetes.io/h2c"
+
+// usesHTTP2 reports whether the MCP server speaks cleartext HTTP/2, either plain or as gRPC.
+func usesHTTP2(cr *mcpserverv1.MCPServer) bool {
+	return cr.Spec.Protocol == mcpserverv1.ProtocolHTTP2 || cr.Spec.Protocol == mcpserverv1.ProtocolGRPC
+}
+
+// usesGRPC reports whether the MCP server speaks gRPC, and so cannot be probed or discovered with MCP requests.
+func usesGRPC(cr *mcpserverv1.MCPServer) bool {
+	return cr.Spec.Protocol == mcpserverv1.ProtocolGRPC
+}
+
+// serviceAppProtocol returns the appProtocol of the http port of the Service of cr, nil for HTTP/1.1.
+func serviceAppProtocol(cr *mcpserverv1.MCPServer) *string {
+	if !usesHTTP2(cr) {
+		return nil
+	}
+	return ptr.To(h2cAppProtocol)
+}
+
+// routeTLS returns the TLS configuration of the Route of a new MCP server. HTTP/2 clients negotiate the protocol
+// with the router over TLS, so HTTP2 and GRPC servers get an edge terminated Route, which redirects plain HTTP.
+func routeTLS(cr *mcpserverv1.MCPServer) *routev1.TLSConfig {
+	if !usesHTTP2(cr) {
+		return nil
+	}
+	return &routev1.TLSConfig{
+		Termination:                   routev1.TLSTerminationEdge,
+		InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyRedirect,
+	}
+}
+
+// reconcileServiceAppProtocol sets the appProtocol of the http port of the existing Service of cr to the one its
+// protocol needs.
+func (r *MCPServerReconciler) reconcileServiceAppProtocol(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) error {
+	service := &corev1.Service{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: resourceName(cr), Namespace: cr.Namespace}, service); err != nil {
+		// A Service that was only just created is not in the cache yet, and already has the appProtocol.
+		if k8serr.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	original := service.DeepCopy()
+	appProtocol := serviceAppProtocol(cr)
+	changed := false
+	for i := range service.Spec.Ports {
+		port := &service.Spec.Ports[i]
+		if port.Name == "http" && ptr.Deref(port.AppProtocol, "") != ptr.Deref(appProtocol, "") {
+			port.AppProtocol = appProtocol
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	logChildDiff(ctx, original, service)
+	return cli.Patch(ctx, service, client.MergeFrom(original))
+}
+
+// probeTCP returns an error if no TCP connection can be opened to the host of rawURL. It is used for gRPC
+// servers, which do not answer the HTTP/1.1 request of probeEndpoint.
+func probeTCP(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	address := u.Host
+	if u.Port() == "" {
+		port := "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+		address = net.JoinHostPort(u.Hostname(), port)
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, endpointProbeTimeout)
+	defer cancel()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(probeCtx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+func newProtocolScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	fakeScheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(fakeScheme); err != nil {
+		t.Fatalf("failed to add client-go scheme: %v", err)
+	}
+	if err := routev1.AddToScheme(fakeScheme); err != nil {
+		t.Fatalf("failed to add routev1 scheme: %v", err)
+	}
+	if err := mcpserverv1.AddToScheme(fakeScheme); err != nil {
+		t.Fatalf("failed to add mcpserverv1 scheme: %v", err)
+	}
+	return fakeScheme
+}
+
+func TestMCPServerReconciler_reconcileMCPServerService_protocol(t *testing.T) {
+	tests := []struct {
+		name     string
+		protocol mcpserverv1.Protocol
+		existing *string
+		want     *string
+	}{
+		{name: "new HTTP service", protocol: mcpserverv1.ProtocolHTTP},
+		{name: "new HTTP2 service", protocol: mcpserverv1.ProtocolHTTP2, want: ptr.To(h2cAppProtocol)},
+		{name: "new GRPC service", protocol: mcpserverv1.ProtocolGRPC, want: ptr.To(h2cAppProtocol)},
+		{name: "existing service switched to GRPC", protocol: mcpserverv1.ProtocolGRPC, existing: ptr.To(""),
+			want: ptr.To(h2cAppProtocol)},
+		{name: "existing service switched back to HTTP", protocol: mcpserverv1.ProtocolHTTP,
+			existing: ptr.To(h2cAppProtocol)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeScheme := newProtocolScheme(t)
+			cr := &mcpserverv1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace, UID: "uid"},
+				Spec:       mcpserverv1.MCPServerSpec{Image: mcpServerImage, Protocol: tt.protocol},
+			}
+			builder := fake.NewClientBuilder().WithScheme(fakeScheme)
+			if tt.existing != nil {
+				existing := &corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+					Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "http", Port: 8000}}},
+				}
+				if *tt.existing != "" {
+					existing.Spec.Ports[0].AppProtocol = tt.existing
+				}
+				builder = builder.WithObjects(existing)
+			}
+			cli := builder.Build()
+			r := &MCPServerReconciler{Client: cli, Scheme: fakeScheme}
+
+			if err := r.reconcileMCPServerService(context.Background(), cli, cr); err != nil {
+				t.Fatalf("reconcileMCPServerService() error = %v", err)
+			}
+			service := &corev1.Service{}
+			if err := cli.Get(context.Background(), client.ObjectKeyFromObject(cr), service); err != nil {
+				t.Fatalf("failed to get the Service: %v", err)
+			}
+			if got := service.Spec.Ports[0].AppProtocol; ptr.Deref(got, "") != ptr.Deref(tt.want, "") {
+				t.Errorf("appProtocol = %v, want %v", ptr.Deref(got, "<nil>"), ptr.Deref(tt.want, "<nil>"))
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_reconcileMCPServerRoute_protocol(t *testing.T) {
+	passthrough := &routev1.TLSConfig{Termination: routev1.TLSTerminationPassthrough}
+	tests := []struct {
+		name     string
+		protocol mcpserverv1.Protocol
+		existing *routev1.TLSConfig
+		exists   bool
+		want     routev1.TLSTerminationType
+	}{
+		{name: "new HTTP route", protocol: mcpserverv1.ProtocolHTTP},
+		{name: "new HTTP2 route", protocol: mcpserverv1.ProtocolHTTP2, want: routev1.TLSTerminationEdge},
+		{name: "existing route switched to GRPC", protocol: mcpserverv1.ProtocolGRPC, exists: true,
+			want: routev1.TLSTerminationEdge},
+		{name: "TLS set by hand is kept", protocol: mcpserverv1.ProtocolGRPC, exists: true, existing: passthrough,
+			want: routev1.TLSTerminationPassthrough},
+		{name: "TLS is kept when switched back to HTTP", protocol: mcpserverv1.ProtocolHTTP, exists: true,
+			existing: passthrough, want: routev1.TLSTerminationPassthrough},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeScheme := newProtocolScheme(t)
+			cr := &mcpserverv1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace, UID: "uid"},
+				Spec:       mcpserverv1.MCPServerSpec{Image: mcpServerImage, Protocol: tt.protocol},
+			}
+			builder := fake.NewClientBuilder().WithScheme(fakeScheme)
+			if tt.exists {
+				builder = builder.WithObjects(&routev1.Route{
+					ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+					Spec:       routev1.RouteSpec{TLS: tt.existing},
+				})
+			}
+			cli := builder.Build()
+			r := &MCPServerReconciler{Client: cli, Scheme: fakeScheme}
+
+			if err := r.reconcileMCPServerRoute(context.Background(), cli, cr); err != nil {
+				t.Fatalf("reconcileMCPServerRoute() error = %v", err)
+			}
+			route := &routev1.Route{}
+			if err := cli.Get(context.Background(), client.ObjectKeyFromObject(cr), route); err != nil {
+				t.Fatalf("failed to get the Route: %v", err)
+			}
+			var got routev1.TLSTerminationType
+			if route.Spec.TLS != nil {
+				got = route.Spec.TLS.Termination
+			}
+			if got != tt.want {
+				t.Errorf("TLS termination = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_probeTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	address := listener.Addr().String()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+
+	if err := probeTCP(context.Background(), "http://"+address+mcpServerSSEPath); err != nil {
+		t.Errorf("probeTCP() error = %v, want the listener reachable", err)
+	}
+	_ = listener.Close()
+	if err := probeTCP(context.Background(), "http://"+address+mcpServerSSEPath); err == nil {
+		t.Error("probeTCP() error = nil, want the closed listener unreachable")
+	}
+}
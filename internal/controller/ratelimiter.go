@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/internal/ratelimiter"
+)
+
+const (
+	// RateLimiterAvailable reports whether the rate limiter of every MCP server pod is ready. It is only set for
+	// MCP servers with a local rate limit.
+	RateLimiterAvailable = "RateLimiterAvailable"
+
+	rateLimiterContainerName = "rate-limiter"
+	rateLimiterPort          = 8070
+	rateLimiterHealthPort    = 8071
+)
+
+// rateLimiterContainer returns the rate limiter of cr, which runs the rate-limiter subcommand of the operator
+// image in front of all other containers, or nil when cr has no local rate limit. It is the first to receive the
+// traffic, as the proxies behind it do not pass on the address of the client.
+func (r *MCPServerReconciler) rateLimiterContainer(cr *mcpserverv1.MCPServer) *corev1.Container {
+	if cr.Spec.RateLimit == nil || cr.Spec.RateLimit.Local == nil {
+		return nil
+	}
+	spec := cr.Spec.RateLimit.Local
+
+	// The default of the CRD is repeated for MCPServers created before it had it.
+	key := spec.Key
+	if key == "" {
+		key = mcpserverv1.RateLimitClientIP
+	}
+	burst := spec.RequestsPerSecond
+	if spec.Burst != nil {
+		burst = *spec.Burst
+	}
+
+	return &corev1.Container{
+		Name:    rateLimiterContainerName,
+		Image:   r.OperatorImage,
+		Command: []string{"/manager", ratelimiter.Command},
+		Args: []string{
+			"--upstream", fmt.Sprintf("http://localhost:%d", upstreamPort(cr, rateLimiterContainerName)),
+			"--port", strconv.Itoa(rateLimiterPort),
+			"--health-port", strconv.Itoa(rateLimiterHealthPort),
+			"--requests-per-second", strconv.Itoa(int(spec.RequestsPerSecond)),
+			"--burst", strconv.Itoa(int(burst)),
+			"--key", string(key),
+		},
+		Ports: []corev1.ContainerPort{{
+			ContainerPort: rateLimiterPort,
+			Name:          "http",
+		}},
+		ReadinessProbe: &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Path: ratelimiter.HealthPath,
+					Port: intstr.FromInt32(rateLimiterHealthPort),
+				},
+			},
+			PeriodSeconds: 10,
+		},
+		SecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: ptr.To(false),
+			Capabilities: &corev1.Capabilities{
+				Drop: []corev1.Capability{"ALL"},
+			},
+		},
+	}
+}
+
+// withRateLimiter returns containers with the rate limiter set to sidecar, or removed when sidecar is nil. While
+// the rate limiter runs, it owns the http port and the port of the container behind it is renamed.
+func withRateLimiter(containers []corev1.Container, sidecar *corev1.Container) []corev1.Container {
+	return withSidecar(containers, rateLimiterContainerName, sidecar,
+		tokenAuthContainerName, metricsExporterContainerName, capabilityFilterContainerName, guardrailsContainerName,
+		"mcp-server")
+}
+
+// getRateLimiterCondition returns the RateLimiterAvailable condition of cr from the readiness of the rate limiter
+// in each MCP server pod.
+func (r *MCPServerReconciler) getRateLimiterCondition(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) metav1.Condition {
+	return r.getSidecarCondition(ctx, cli, cr, RateLimiterAvailable, rateLimiterContainerName, "RateLimiter", "rate limiter",
+		"check the logs of its rate-limiter container")
+}
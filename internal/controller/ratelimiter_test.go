@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+func Test_withRateLimiter(t *testing.T) {
+	r := &MCPServerReconciler{OperatorImage: "quay.io/example/operator:latest"}
+	server := corev1.Container{
+		Name:  "mcp-server",
+		Ports: []corev1.ContainerPort{{ContainerPort: 8000, Name: "http"}},
+	}
+	newCR := func(guardrails, metricsExporter, rateLimit bool) *mcpserverv1.MCPServer {
+		cr := newGuardrailsMCPServer()
+		if !guardrails {
+			cr.Spec.Guardrails = nil
+		}
+		if metricsExporter {
+			cr.Spec.MetricsExporter = &mcpserverv1.MetricsExporter{}
+		}
+		if rateLimit {
+			cr.Spec.RateLimit = &mcpserverv1.RateLimit{Local: &mcpserverv1.LocalRateLimit{RequestsPerSecond: 5}}
+		}
+		return cr
+	}
+	all := r.withSidecars(newCR(true, true, true), []corev1.Container{server})
+
+	tests := []struct {
+		name         string
+		cr           *mcpserverv1.MCPServer
+		containers   []corev1.Container
+		wantPorts    map[string]string
+		wantUpstream string
+	}{
+		{
+			name:         "rate limiter in front of the MCP server",
+			cr:           newCR(false, false, true),
+			containers:   []corev1.Container{server},
+			wantPorts:    map[string]string{"mcp-server": mcpServerPortName, rateLimiterContainerName: "http"},
+			wantUpstream: "http://localhost:8000",
+		},
+		{
+			name:       "rate limiter in front of the guardrails filter",
+			cr:         newCR(true, false, true),
+			containers: []corev1.Container{server},
+			wantPorts: map[string]string{"mcp-server": mcpServerPortName, guardrailsContainerName: guardrailsPortName,
+				rateLimiterContainerName: "http"},
+			wantUpstream: "http://localhost:8080",
+		},
+		{
+			name:       "rate limiter in front of all sidecars",
+			cr:         newCR(true, true, true),
+			containers: all,
+			wantPorts: map[string]string{"mcp-server": mcpServerPortName, guardrailsContainerName: guardrailsPortName,
+				metricsExporterContainerName: metricsExporterPortName, rateLimiterContainerName: "http"},
+			wantUpstream: "http://localhost:8090",
+		},
+		{
+			name:       "rate limiter removed from the metrics exporter",
+			cr:         newCR(true, true, false),
+			containers: all,
+			wantPorts: map[string]string{"mcp-server": mcpServerPortName, guardrailsContainerName: guardrailsPortName,
+				metricsExporterContainerName: "http"},
+		},
+		{
+			name:       "metrics exporter removed behind the rate limiter",
+			cr:         newCR(true, false, true),
+			containers: all,
+			wantPorts: map[string]string{"mcp-server": mcpServerPortName, guardrailsContainerName: guardrailsPortName,
+				rateLimiterContainerName: "http"},
+			wantUpstream: "http://localhost:8080",
+		},
+		{
+			name:       "all sidecars removed",
+			cr:         newCR(false, false, false),
+			containers: all,
+			wantPorts:  map[string]string{"mcp-server": "http"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := r.withSidecars(tt.cr, tt.containers)
+			if len(got) != len(tt.wantPorts) {
+				t.Fatalf("withSidecars() returned %d containers, want %d", len(got), len(tt.wantPorts))
+			}
+			for _, container := range got {
+				if container.Ports[0].Name != tt.wantPorts[container.Name] {
+					t.Errorf("port of %s = %s, want %s", container.Name, container.Ports[0].Name, tt.wantPorts[container.Name])
+				}
+				if container.Name == rateLimiterContainerName && container.Args[1] != tt.wantUpstream {
+					t.Errorf("upstream of the rate limiter = %s, want %s", container.Args[1], tt.wantUpstream)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/internal/restbridge"
+)
+
+const (
+	// RESTBridgeAvailable reports whether the REST bridge of every MCP server pod is ready. It is only set for MCP
+	// servers with spec.restBridge enabled.
+	RESTBridgeAvailable = "RESTBridgeAvailable"
+
+	restBridgeContainerName = "rest-bridge"
+	restBridgePort          = 8040
+	restBridgePortName      = "rest"
+)
+
+// usesRESTBridge reports whether cr offers its tools as REST API.
+func usesRESTBridge(cr *mcpserverv1.MCPServer) bool {
+	return cr.Spec.RESTBridge != nil && cr.Spec.RESTBridge.Enabled && !isExternal(cr)
+}
+
+// restBridgeName returns the name of the Service and Route of the REST bridge of cr.
+func restBridgeName(cr *mcpserverv1.MCPServer) string {
+	return childName(cr, restBridgePortName)
+}
+
+// restBridgeContainer returns the REST bridge of cr, which runs the rest-bridge subcommand of the operator image
+// next to the MCP server, or nil when cr has no REST bridge. It calls the tools through the http port of the pod,
+// so that the sidecars in front of the MCP server apply to REST clients too.
+func (r *MCPServerReconciler) restBridgeContainer(cr *mcpserverv1.MCPServer) *corev1.Container {
+	if !usesRESTBridge(cr) {
+		return nil
+	}
+
+	return &corev1.Container{
+		Name:    restBridgeContainerName,
+		Image:   r.OperatorImage,
+		Command: []string{"/manager", restbridge.Command},
+		Args: []string{
+			"--upstream", fmt.Sprintf("http://localhost:%d%s", httpPort(cr), mcpServerPath(cr)),
+			"--port", strconv.Itoa(restBridgePort),
+		},
+		Ports: []corev1.ContainerPort{{
+			ContainerPort: restBridgePort,
+			Name:          restBridgePortName,
+		}},
+		ReadinessProbe: &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Path: restbridge.HealthPath,
+					Port: intstr.FromString(restBridgePortName),
+				},
+			},
+			PeriodSeconds: 10,
+		},
+		SecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: ptr.To(false),
+			Capabilities: &corev1.Capabilities{
+				Drop: []corev1.Capability{"ALL"},
+			},
+		},
+	}
+}
+
+// withRESTBridge returns containers with the REST bridge set to sidecar, or removed when sidecar is nil. The
+// bridge has a port of its own and runs in front of no other container.
+func withRESTBridge(containers []corev1.Container, sidecar *corev1.Container) []corev1.Container {
+	return withSidecar(containers, restBridgeContainerName, sidecar)
+}
+
+// reconcileRESTBridge creates the Service of the REST bridge of cr, and its Route where the MCP server has one,
+// or removes them when the bridge is disabled.
+func (r *MCPServerReconciler) reconcileRESTBridge(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) error {
+	if !usesRESTBridge(cr) {
+		return r.deleteRESTBridge(ctx, cli, cr)
+	}
+
+	labels := map[string]string{mcpServerAppLabelKey: resourceName(cr)}
+	service := &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      restBridgeName(cr),
+			Namespace: cr.Namespace,
+			Labels:    labels,
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: labels,
+			Ports: []corev1.ServicePort{{
+				Name:       restBridgePortName,
+				Port:       restBridgePort,
+				TargetPort: intstr.FromString(restBridgePortName),
+				Protocol:   corev1.ProtocolTCP,
+			}},
+		},
+	}
+	if err := r.createChild(ctx, cli, cr, service); err != nil {
+		return err
+	}
+
+	if !r.usesRoute(cr) {
+		return nil
+	}
+	return r.createChild(ctx, cli, cr, restBridgeRoute(cr))
+}
+
+// restBridgeRoute returns the Route of the REST bridge of cr. It is edge terminated, and takes the annotations
+// and router shard labels of the Route of the MCP server, so that spec.expose.allowedSourceRanges restricts its
+// clients too.
+func restBridgeRoute(cr *mcpserverv1.MCPServer) *routev1.Route {
+	route := &routev1.Route{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "route.openshift.io/v1",
+			Kind:       "Route",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      restBridgeName(cr),
+			Namespace: cr.Namespace,
+			Labels:    map[string]string{mcpServerAppLabelKey: resourceName(cr)},
+		},
+		Spec: routev1.RouteSpec{
+			To: routev1.RouteTargetReference{
+				Kind: "Service",
+				Name: restBridgeName(cr),
+			},
+			Port: &routev1.RoutePort{
+				TargetPort: intstr.FromString(restBridgePortName),
+			},
+			TLS: &routev1.TLSConfig{
+				Termination:                   routev1.TLSTerminationEdge,
+				InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyRedirect,
+			},
+		},
+	}
+	for key, value := range routeAnnotations(cr) {
+		if value != "" {
+			metav1.SetMetaDataAnnotation(&route.ObjectMeta, key, value)
+		}
+	}
+	applyIngressControllerSelector(cr, route)
+	return route
+}
+
+// deleteRESTBridge removes the Service and Route of a REST bridge that was disabled. They only exist when the
+// RESTBridgeAvailable condition was reported.
+func (r *MCPServerReconciler) deleteRESTBridge(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) error {
+	if meta.FindStatusCondition(cr.Status.Conditions, RESTBridgeAvailable) == nil {
+		return nil
+	}
+
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: restBridgeName(cr), Namespace: cr.Namespace}}
+	if err := r.deleteChild(ctx, cli, cr, service); err != nil {
+		return err
+	}
+	if !r.routeAPIAvailable() {
+		return nil
+	}
+	route := &routev1.Route{ObjectMeta: metav1.ObjectMeta{Name: restBridgeName(cr), Namespace: cr.Namespace}}
+	return r.deleteChild(ctx, cli, cr, route)
+}
+
+// getRESTBridgeURL returns the base URL of the REST bridge of cr: the one of its Route once a router admitted
+// it, the cluster-internal one of its Service otherwise, and "" when cr has no REST bridge.
+func (r *MCPServerReconciler) getRESTBridgeURL(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) (string, error) {
+	if !usesRESTBridge(cr) {
+		return "", nil
+	}
+	serviceURL := fmt.Sprintf("http://%s.%s.svc:%d", restBridgeName(cr), cr.Namespace, restBridgePort)
+	if !r.usesRoute(cr) {
+		return serviceURL, nil
+	}
+
+	route := &routev1.Route{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: restBridgeName(cr), Namespace: cr.Namespace}, route); err != nil {
+		if k8serr.IsNotFound(err) {
+			return serviceURL, nil
+		}
+		return "", err
+	}
+	if !routeAdmitted(route) {
+		return serviceURL, nil
+	}
+	for _, ingress := range route.Status.Ingress {
+		if ingress.Host != "" {
+			return "https://" + ingress.Host, nil
+		}
+	}
+	return serviceURL, nil
+}
+
+// getRESTBridgeCondition returns the RESTBridgeAvailable condition of cr from the readiness of the REST bridge
+// in each MCP server pod.
+func (r *MCPServerReconciler) getRESTBridgeCondition(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) metav1.Condition {
+	return r.getSidecarCondition(ctx, cli, cr, RESTBridgeAvailable, restBridgeContainerName, "RESTBridge",
+		"REST bridge", "check the logs of its rest-bridge container")
+}
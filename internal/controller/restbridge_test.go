@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+func newRESTBridgeMCPServer() *mcpserverv1.MCPServer {
+	return &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+		Spec: mcpserverv1.MCPServerSpec{
+			Image:      mcpServerImage,
+			RESTBridge: &mcpserverv1.RESTBridge{Enabled: true},
+		},
+	}
+}
+
+func Test_withRESTBridge(t *testing.T) {
+	r := &MCPServerReconciler{OperatorImage: "quay.io/example/operator:latest"}
+	server := corev1.Container{
+		Name:  "mcp-server",
+		Ports: []corev1.ContainerPort{{ContainerPort: 8000, Name: "http"}},
+	}
+
+	tests := []struct {
+		name         string
+		configure    func(cr *mcpserverv1.MCPServer)
+		wantUpstream string
+		wantHTTP     string
+	}{
+		{
+			name:         "Verify that the bridge calls the MCP server directly when it has no other sidecars",
+			configure:    func(*mcpserverv1.MCPServer) {},
+			wantUpstream: "http://localhost:8000/sse",
+			wantHTTP:     "mcp-server",
+		},
+		{
+			name: "Verify that the bridge calls the MCP server through the outermost sidecar",
+			configure: func(cr *mcpserverv1.MCPServer) {
+				cr.Spec.BasePath = "/tools/sse"
+				cr.Spec.Auth = &mcpserverv1.Auth{Type: mcpserverv1.AuthToken}
+				cr.Spec.MetricsExporter = &mcpserverv1.MetricsExporter{}
+			},
+			wantUpstream: "http://localhost:8060/tools/sse",
+			wantHTTP:     tokenAuthContainerName,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := newRESTBridgeMCPServer()
+			tt.configure(cr)
+			containers := r.withSidecars(cr, []corev1.Container{server})
+
+			var bridge *corev1.Container
+			for i, container := range containers {
+				if container.Name == restBridgeContainerName {
+					bridge = &containers[i]
+				}
+				for _, port := range container.Ports {
+					if port.Name == "http" && container.Name != tt.wantHTTP {
+						t.Errorf("container %s owns the http port, want %s", container.Name, tt.wantHTTP)
+					}
+				}
+			}
+			if bridge == nil {
+				t.Fatalf("containers = %v, want the REST bridge", containers)
+			}
+			if bridge.Args[1] != tt.wantUpstream || bridge.Ports[0].Name != restBridgePortName {
+				t.Errorf("REST bridge args = %v, ports = %v, want upstream %s and the %s port", bridge.Args,
+					bridge.Ports, tt.wantUpstream, restBridgePortName)
+			}
+
+			// Disabling the bridge removes it and leaves the other containers alone.
+			cr.Spec.RESTBridge = nil
+			if got := r.withSidecars(cr, containers); len(got) != len(containers)-1 {
+				t.Errorf("%d containers after disabling the bridge, want %d", len(got), len(containers)-1)
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_reconcileRESTBridge(t *testing.T) {
+	scheme := newAutoscalingScheme(t)
+	if err := routev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add routev1 scheme: %v", err)
+	}
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&routev1.Route{}).Build()
+	r := &MCPServerReconciler{Client: cli, Scheme: scheme}
+	ctx := context.Background()
+
+	cr := newRESTBridgeMCPServer()
+	cr.Spec.Expose = &mcpserverv1.Expose{AllowedSourceRanges: []string{"10.0.0.0/8"}}
+	if err := r.reconcileRESTBridge(ctx, cli, cr); err != nil {
+		t.Fatalf("reconcileRESTBridge() error = %v", err)
+	}
+
+	service := &corev1.Service{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: mcpServerName + "-rest", Namespace: testNamespace}, service); err != nil {
+		t.Fatalf("failed to get the Service of the REST bridge: %v", err)
+	}
+	if service.Spec.Ports[0].Port != restBridgePort || service.Spec.Selector[mcpServerAppLabelKey] != mcpServerName {
+		t.Errorf("Service spec = %+v, want the %d port of the MCP server pods", service.Spec, restBridgePort)
+	}
+	route := &routev1.Route{}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(service), route); err != nil {
+		t.Fatalf("failed to get the Route of the REST bridge: %v", err)
+	}
+	if route.Spec.To.Name != service.Name || route.Spec.TLS == nil ||
+		route.Annotations[routeIPAllowlistAnnotation] != "10.0.0.0/8" {
+		t.Errorf("Route = %+v, want an edge terminated Route to the Service with the allowlist of the MCP server", route)
+	}
+
+	// The Service URL is reported until a router admits the Route.
+	url, err := r.getRESTBridgeURL(ctx, cli, cr)
+	if err != nil || url != "http://"+mcpServerName+"-rest."+testNamespace+".svc:8040" {
+		t.Errorf("getRESTBridgeURL() = %s, %v, want the URL of the Service", url, err)
+	}
+	route.Status.Ingress = []routev1.RouteIngress{{
+		Host:       "rest.apps.example.com",
+		Conditions: []routev1.RouteIngressCondition{{Type: routev1.RouteAdmitted, Status: corev1.ConditionTrue}},
+	}}
+	if err := cli.Status().Update(ctx, route); err != nil {
+		t.Fatalf("failed to admit the Route: %v", err)
+	}
+	if url, err := r.getRESTBridgeURL(ctx, cli, cr); err != nil || url != "https://rest.apps.example.com" {
+		t.Errorf("getRESTBridgeURL() = %s, %v, want the URL of the Route", url, err)
+	}
+
+	// Disabling the bridge removes its Service and Route.
+	cr.Spec.RESTBridge.Enabled = false
+	cr.Status.Conditions = []metav1.Condition{{Type: RESTBridgeAvailable, Status: metav1.ConditionTrue}}
+	if err := r.reconcileRESTBridge(ctx, cli, cr); err != nil {
+		t.Fatalf("reconcileRESTBridge() error = %v", err)
+	}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(service), service); err == nil {
+		t.Errorf("the Service of the REST bridge still exists")
+	}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(route), route); err == nil {
+		t.Errorf("the Route of the REST bridge still exists")
+	}
+	if url, _ := r.getRESTBridgeURL(ctx, cli, cr); url != "" {
+		t.Errorf("getRESTBridgeURL() = %s, want none for a disabled bridge", url)
+	}
+}
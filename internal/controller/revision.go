@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// maxRevisions is the number of revisions kept in the status of an MCPServer.
+const maxRevisions = 10
+
+// configHash returns a short hash of config, or an empty string when it has no options.
+func configHash(config map[string]apiextensionsv1.JSON) string {
+	if len(config) == 0 {
+		return ""
+	}
+	// The keys of a map are marshaled in sorted order, so equal configs hash the same.
+	data, err := json.Marshal(config)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// recordRevision adds the spec of cr to its revisions once its Deployment runs it and has completed the rollout,
+// unless it is the spec of the latest revision. Only the last maxRevisions revisions are kept.
+func (r *MCPServerReconciler) recordRevision(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer, now metav1.Time) error {
+	if isExternal(cr) || isProxy(cr) {
+		cr.Status.Revisions = nil
+		return nil
+	}
+	if !meta.IsStatusConditionTrue(cr.Status.Conditions, DeploymentAvailable) {
+		return nil
+	}
+	deployment := &appsv1.Deployment{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: resourceName(cr), Namespace: cr.Namespace}, deployment); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if rolloutInProgress(deployment) || !runsSpec(deployment, cr) {
+		return nil
+	}
+
+	revision := mcpserverv1.Revision{
+		Revision:           1,
+		Image:              cr.Spec.Image,
+		Command:            slices.Clone(cr.Spec.Command),
+		Args:               slices.Clone(cr.Spec.Args),
+		ConfigHash:         configHash(cr.Spec.Config),
+		ObservedGeneration: cr.Generation,
+		RolloutTime:        now,
+	}
+	if len(cr.Spec.Config) > 0 {
+		revision.Config = make(map[string]apiextensionsv1.JSON, len(cr.Spec.Config))
+		for key, value := range cr.Spec.Config {
+			revision.Config[key] = *value.DeepCopy()
+		}
+	}
+	if n := len(cr.Status.Revisions); n > 0 {
+		latest := cr.Status.Revisions[n-1]
+		if latest.Image == revision.Image && slices.Equal(latest.Command, revision.Command) &&
+			slices.Equal(latest.Args, revision.Args) && latest.ConfigHash == revision.ConfigHash {
+			return nil
+		}
+		revision.Revision = latest.Revision + 1
+	}
+	cr.Status.Revisions = append(cr.Status.Revisions, revision)
+	if n := len(cr.Status.Revisions); n > maxRevisions {
+		cr.Status.Revisions = slices.Clone(cr.Status.Revisions[n-maxRevisions:])
+	}
+	return nil
+}
+
+// runsSpec reports whether the MCP server container of the Deployment runs the image, command and args of cr,
+// which a Deployment read from a stale cache may not yet do.
+func runsSpec(deployment *appsv1.Deployment, cr *mcpserverv1.MCPServer) bool {
+	args, err := mcpServerArgs(cr)
+	if err != nil {
+		return false
+	}
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if container.Name == "mcp-server" {
+			return container.Image == pinnedImage(cr) && slices.Equal(container.Command, mcpServerCommand(cr)) &&
+				slices.Equal(container.Args, args)
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+func TestMCPServerReconciler_recordRevision(t *testing.T) {
+	newDeployment := func(image string, args []string, updatedReplicas int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.To[int32](1),
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{
+						Name:    "mcp-server",
+						Image:   image,
+						Command: DefaultMCPDeploymentCommand,
+						Args:    args,
+					}}},
+				},
+			},
+			Status: appsv1.DeploymentStatus{Replicas: 1, UpdatedReplicas: updatedReplicas},
+		}
+	}
+	readOnly := map[string]apiextensionsv1.JSON{"readOnly": {Raw: []byte("true")}}
+	readOnlyArgs := append(append([]string{}, DefaultMCPDeploymentArgs...), "--read-only")
+
+	tests := []struct {
+		name         string
+		revisions    []mcpserverv1.Revision
+		config       map[string]apiextensionsv1.JSON
+		deployment   *appsv1.Deployment
+		unavailable  bool
+		wantRevision int64
+		wantCount    int
+	}{
+		{
+			name:         "first rollout",
+			deployment:   newDeployment(mcpServerImage, DefaultMCPDeploymentArgs, 1),
+			wantRevision: 1,
+			wantCount:    1,
+		},
+		{
+			name:         "unchanged spec",
+			revisions:    []mcpserverv1.Revision{{Revision: 3, Image: mcpServerImage}},
+			deployment:   newDeployment(mcpServerImage, DefaultMCPDeploymentArgs, 1),
+			wantRevision: 3,
+			wantCount:    1,
+		},
+		{
+			name:         "new config rolled out",
+			revisions:    []mcpserverv1.Revision{{Revision: 3, Image: mcpServerImage}},
+			config:       readOnly,
+			deployment:   newDeployment(mcpServerImage, readOnlyArgs, 1),
+			wantRevision: 4,
+			wantCount:    2,
+		},
+		{
+			name:         "rollout in progress",
+			revisions:    []mcpserverv1.Revision{{Revision: 3, Image: "quay.io/mcp/previous:1.0"}},
+			deployment:   newDeployment(mcpServerImage, DefaultMCPDeploymentArgs, 0),
+			wantRevision: 3,
+			wantCount:    1,
+		},
+		{
+			name:         "deployment still runs the previous image",
+			revisions:    []mcpserverv1.Revision{{Revision: 3, Image: "quay.io/mcp/previous:1.0"}},
+			deployment:   newDeployment("quay.io/mcp/previous:1.0", DefaultMCPDeploymentArgs, 1),
+			wantRevision: 3,
+			wantCount:    1,
+		},
+		{
+			name:         "deployment unavailable",
+			deployment:   newDeployment(mcpServerImage, DefaultMCPDeploymentArgs, 1),
+			unavailable:  true,
+			wantRevision: 0,
+			wantCount:    0,
+		},
+		{
+			name: "oldest revision dropped",
+			revisions: func() []mcpserverv1.Revision {
+				revisions := []mcpserverv1.Revision{}
+				for i := 1; i <= maxRevisions; i++ {
+					revisions = append(revisions, mcpserverv1.Revision{Revision: int64(i), Image: fmt.Sprintf("quay.io/mcp/server:%d", i)})
+				}
+				return revisions
+			}(),
+			deployment:   newDeployment(mcpServerImage, DefaultMCPDeploymentArgs, 1),
+			wantRevision: maxRevisions + 1,
+			wantCount:    maxRevisions,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().WithObjects(tt.deployment).Build()
+			r := &MCPServerReconciler{Client: cli}
+			available := metav1.ConditionTrue
+			if tt.unavailable {
+				available = metav1.ConditionFalse
+			}
+			cr := &mcpserverv1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace, Generation: 7},
+				Spec:       mcpserverv1.MCPServerSpec{Image: mcpServerImage, Config: tt.config},
+				Status: mcpserverv1.MCPServerStatus{
+					Conditions: []metav1.Condition{{Type: DeploymentAvailable, Status: available}},
+					Revisions:  tt.revisions,
+				},
+			}
+
+			now := metav1.NewTime(time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC))
+			if err := r.recordRevision(context.Background(), cli, cr, now); err != nil {
+				t.Fatalf("recordRevision() error = %v", err)
+			}
+			if got := len(cr.Status.Revisions); got != tt.wantCount {
+				t.Fatalf("revisions = %v, want %d", cr.Status.Revisions, tt.wantCount)
+			}
+			if tt.wantCount == 0 {
+				return
+			}
+			latest := cr.Status.Revisions[len(cr.Status.Revisions)-1]
+			if latest.Revision != tt.wantRevision {
+				t.Errorf("latest revision = %d, want %d", latest.Revision, tt.wantRevision)
+			}
+			if latest.Revision > 3 || tt.revisions == nil {
+				if latest.Image != mcpServerImage || latest.ObservedGeneration != 7 || !latest.RolloutTime.Equal(&now) {
+					t.Errorf("latest revision = %+v, want the rolled out spec", latest)
+				}
+				if got, want := latest.ConfigHash, configHash(tt.config); got != want {
+					t.Errorf("config hash = %q, want %q", got, want)
+				}
+			}
+		})
+	}
+
+	if configHash(nil) != "" || configHash(readOnly) == "" ||
+		configHash(readOnly) == configHash(map[string]apiextensionsv1.JSON{"readOnly": {Raw: []byte("false")}}) {
+		t.Errorf("configHash() does not tell configs apart")
+	}
+}
@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=list;watch
+
+const (
+	// ReasonCertificateSecretInvalid is set on the RouteAvailable condition when the Secret of
+	// spec.route.tls.certificateSecretRef does not exist or does not hold a certificate and matching key.
+	ReasonCertificateSecretInvalid = "CertificateSecretInvalid"
+
+	// routeCertificateAnnotation records on the Route the name of the Secret its certificate was synced from, so
+	// that the certificate is removed again once spec.route.tls.certificateSecretRef is unset.
+	routeCertificateAnnotation = "mcpserver.opendatahub.io/certificate-secret"
+)
+
+// certificateSecretName returns the name of the Secret of spec.route.tls.certificateSecretRef, or "".
+func certificateSecretName(cr *mcpserverv1.MCPServer) string {
+	if cr.Spec.Route == nil || cr.Spec.Route.TLS == nil {
+		return ""
+	}
+	return cr.Spec.Route.TLS.CertificateSecretRef.Name
+}
+
+// getRouteCertificate returns the edge terminated TLS configuration of the Route of cr with the certificate of
+// spec.route.tls.certificateSecretRef, or nil when it is unset. A Secret that does not exist or does not hold a
+// certificate and matching key is described by the returned message, with a nil configuration.
+func (r *MCPServerReconciler) getRouteCertificate(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) (*routev1.TLSConfig, string, error) {
+	name := certificateSecretName(cr)
+	if name == "" {
+		return nil, "", nil
+	}
+	secret := &corev1.Secret{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: name, Namespace: cr.Namespace}, secret); err != nil {
+		if k8serr.IsNotFound(err) {
+			return nil, fmt.Sprintf("Certificate Secret %s does not exist", name), nil
+		}
+		return nil, "", err
+	}
+	certificate, key := secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey]
+	if _, err := tls.X509KeyPair(certificate, key); err != nil {
+		return nil, fmt.Sprintf("Certificate Secret %s does not hold a certificate and matching key in %s and %s: %v",
+			name, corev1.TLSCertKey, corev1.TLSPrivateKeyKey, err), nil
+	}
+	return &routev1.TLSConfig{
+		Termination:                   routev1.TLSTerminationEdge,
+		InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyRedirect,
+		Certificate:                   string(certificate),
+		Key:                           string(key),
+		CACertificate:                 string(secret.Data[corev1.ServiceAccountRootCAKey]),
+	}, "", nil
+}
+
+// applyRouteCertificate sets the TLS configuration of route to certificate, the result of getRouteCertificate
+// for cr. The certificate of a Secret that became invalid is kept, so that a failed renewal does not take the
+// Route down, and a synced certificate is removed once spec.route.tls.certificateSecretRef is unset, leaving the
+// TLS termination in place.
+func applyRouteCertificate(cr *mcpserverv1.MCPServer, route *routev1.Route, certificate *routev1.TLSConfig) {
+	if certificate != nil {
+		route.Spec.TLS = certificate
+		metav1.SetMetaDataAnnotation(&route.ObjectMeta, routeCertificateAnnotation, certificateSecretName(cr))
+		return
+	}
+	if certificateSecretName(cr) != "" {
+		return
+	}
+	if _, ok := route.Annotations[routeCertificateAnnotation]; !ok {
+		return
+	}
+	delete(route.Annotations, routeCertificateAnnotation)
+	if route.Spec.TLS != nil {
+		route.Spec.TLS.Certificate, route.Spec.TLS.Key, route.Spec.TLS.CACertificate = "", "", ""
+	}
+}
+
+// mapSecretToMCPServers maps a Secret to the MCPServers of its namespace whose Route serves its certificate, so
+// that a renewed certificate is synced to their Routes.
+func (r *MCPServerReconciler) mapSecretToMCPServers(ctx context.Context, obj client.Object) []reconcile.Request {
+	list := &mcpserverv1.MCPServerList{}
+	if err := r.Client.List(ctx, list, client.InNamespace(obj.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list the MCPServers of a Secret", "namespace", obj.GetNamespace())
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, cr := range list.Items {
+		if certificateSecretName(&cr) == obj.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&cr)})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,214 @@
+package controller
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// newCertificateSecret returns a kubernetes.io/tls Secret with a self-signed certificate for host.
+func newCertificateSecret(t *testing.T, name, host string) *corev1.Secret {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate a key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: host},
+		DNSNames:     []string{host},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create a certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal the key: %v", err)
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Type:       corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			corev1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		},
+	}
+}
+
+func TestMCPServerReconciler_getRouteCertificate(t *testing.T) {
+	valid := newCertificateSecret(t, "mcp-tls", "mcp.example.com")
+	other := newCertificateSecret(t, "other", "other.example.com")
+	mismatched := valid.DeepCopy()
+	mismatched.Name = "mismatched"
+	mismatched.Data[corev1.TLSPrivateKeyKey] = other.Data[corev1.TLSPrivateKeyKey]
+
+	tests := []struct {
+		name            string
+		secretName      string
+		objects         []client.Object
+		wantCertificate bool
+		wantProblem     string
+	}{
+		{
+			name: "Verify that a Route without a certificate Secret gets no certificate",
+		},
+		{
+			name:            "Verify that the certificate of the Secret is returned",
+			secretName:      "mcp-tls",
+			objects:         []client.Object{valid},
+			wantCertificate: true,
+		},
+		{
+			name:        "Verify that a missing Secret is reported",
+			secretName:  "mcp-tls",
+			wantProblem: "Certificate Secret mcp-tls does not exist",
+		},
+		{
+			name:       "Verify that a key that does not match the certificate is reported",
+			secretName: "mismatched",
+			objects:    []client.Object{mismatched},
+			wantProblem: "Certificate Secret mismatched does not hold a certificate and matching key in tls.crt and " +
+				"tls.key: tls: private key does not match public key",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeScheme := newAutoscalingScheme(t)
+			cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(tt.objects...).Build()
+			r := &MCPServerReconciler{Client: cli, Scheme: fakeScheme}
+			cr := &mcpserverv1.MCPServer{ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace}}
+			if tt.secretName != "" {
+				cr.Spec.Route = &mcpserverv1.Route{TLS: &mcpserverv1.RouteTLS{
+					CertificateSecretRef: corev1.LocalObjectReference{Name: tt.secretName},
+				}}
+			}
+
+			certificate, problem, err := r.getRouteCertificate(context.Background(), cli, cr)
+			if err != nil {
+				t.Fatalf("getRouteCertificate() error = %v", err)
+			}
+			if problem != tt.wantProblem {
+				t.Errorf("getRouteCertificate() problem = %q, want %q", problem, tt.wantProblem)
+			}
+			if (certificate != nil) != tt.wantCertificate {
+				t.Fatalf("getRouteCertificate() = %+v, want a certificate %v", certificate, tt.wantCertificate)
+			}
+			if certificate != nil && (certificate.Termination != routev1.TLSTerminationEdge ||
+				certificate.Certificate != string(valid.Data[corev1.TLSCertKey])) {
+				t.Errorf("getRouteCertificate() = %+v, want the edge terminated certificate of the Secret", certificate)
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_reconcileMCPServerRoute_certificate(t *testing.T) {
+	fakeScheme := newAutoscalingScheme(t)
+	if err := routev1.AddToScheme(fakeScheme); err != nil {
+		t.Fatalf("failed to add routev1 scheme: %v", err)
+	}
+	secret := newCertificateSecret(t, "mcp-tls", "mcp.example.com")
+	cr := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace, UID: "uid"},
+		Spec: mcpserverv1.MCPServerSpec{
+			Image: mcpServerImage,
+			Route: &mcpserverv1.Route{TLS: &mcpserverv1.RouteTLS{
+				CertificateSecretRef: corev1.LocalObjectReference{Name: secret.Name},
+			}},
+		},
+	}
+	cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(secret).Build()
+	r := &MCPServerReconciler{Client: cli, Scheme: fakeScheme}
+	ctx := context.Background()
+	getRoute := func() *routev1.Route {
+		t.Helper()
+		if err := r.reconcileMCPServerRoute(ctx, cli, cr); err != nil {
+			t.Fatalf("reconcileMCPServerRoute() error = %v", err)
+		}
+		route := &routev1.Route{}
+		if err := cli.Get(ctx, client.ObjectKeyFromObject(cr), route); err != nil {
+			t.Fatalf("failed to get the Route: %v", err)
+		}
+		return route
+	}
+
+	route := getRoute()
+	if route.Spec.TLS == nil || route.Spec.TLS.Certificate != string(secret.Data[corev1.TLSCertKey]) {
+		t.Fatalf("Route TLS = %+v, want the certificate of the Secret", route.Spec.TLS)
+	}
+
+	// A renewed certificate is synced to the Route.
+	renewed := newCertificateSecret(t, secret.Name, "mcp.example.com")
+	secret.Data = renewed.Data
+	if err := cli.Update(ctx, secret); err != nil {
+		t.Fatalf("failed to renew the certificate: %v", err)
+	}
+	route = getRoute()
+	if route.Spec.TLS.Certificate != string(renewed.Data[corev1.TLSCertKey]) ||
+		route.Spec.TLS.Key != string(renewed.Data[corev1.TLSPrivateKeyKey]) {
+		t.Errorf("Route TLS = %+v, want the renewed certificate", route.Spec.TLS)
+	}
+
+	// A Secret that became invalid leaves the last certificate in place.
+	secret.Data[corev1.TLSPrivateKeyKey] = nil
+	if err := cli.Update(ctx, secret); err != nil {
+		t.Fatalf("failed to break the Secret: %v", err)
+	}
+	route = getRoute()
+	if route.Spec.TLS.Certificate != string(renewed.Data[corev1.TLSCertKey]) {
+		t.Errorf("Route TLS = %+v, want the last valid certificate kept", route.Spec.TLS)
+	}
+
+	// The certificate is removed with the reference, the TLS termination is kept.
+	cr.Spec.Route = nil
+	route = getRoute()
+	if route.Spec.TLS == nil || route.Spec.TLS.Termination != routev1.TLSTerminationEdge ||
+		route.Spec.TLS.Certificate != "" || route.Spec.TLS.Key != "" {
+		t.Errorf("Route TLS = %+v, want edge termination without a certificate", route.Spec.TLS)
+	}
+	if _, ok := route.Annotations[routeCertificateAnnotation]; ok {
+		t.Errorf("Route annotations = %v, want %s removed", route.Annotations, routeCertificateAnnotation)
+	}
+}
+
+func TestMCPServerReconciler_mapSecretToMCPServers(t *testing.T) {
+	fakeScheme := newAutoscalingScheme(t)
+	withCertificate := func(name, secretName string) *mcpserverv1.MCPServer {
+		cr := &mcpserverv1.MCPServer{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace}}
+		if secretName != "" {
+			cr.Spec.Route = &mcpserverv1.Route{TLS: &mcpserverv1.RouteTLS{
+				CertificateSecretRef: corev1.LocalObjectReference{Name: secretName},
+			}}
+		}
+		return cr
+	}
+	cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(
+		withCertificate("uses-secret", "mcp-tls"),
+		withCertificate("uses-other", "other-tls"),
+		withCertificate("no-certificate", ""),
+	).Build()
+	r := &MCPServerReconciler{Client: cli, Scheme: fakeScheme}
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "mcp-tls", Namespace: testNamespace}}
+	requests := r.mapSecretToMCPServers(context.Background(), secret)
+	if len(requests) != 1 || requests[0].Name != "uses-secret" {
+		t.Errorf("mapSecretToMCPServers() = %v, want only uses-secret", requests)
+	}
+}
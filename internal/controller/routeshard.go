@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"slices"
+	"strings"
+
+	routev1 "github.com/openshift/api/route/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// routeShardLabelsAnnotation records on the Route the keys of the labels set from
+// spec.route.ingressControllerSelector, so that they are removed again once they leave the selector.
+const routeShardLabelsAnnotation = "mcpserver.opendatahub.io/ingress-controller-labels"
+
+// ingressControllerSelector returns spec.route.ingressControllerSelector of cr, or nil.
+func ingressControllerSelector(cr *mcpserverv1.MCPServer) map[string]string {
+	if cr.Spec.Route == nil {
+		return nil
+	}
+	return cr.Spec.Route.IngressControllerSelector
+}
+
+// applyIngressControllerSelector sets the labels of spec.route.ingressControllerSelector of cr on route, and
+// removes those it set before that are not in the selector anymore. Other labels, e.g. set by hand, are kept.
+func applyIngressControllerSelector(cr *mcpserverv1.MCPServer, route *routev1.Route) {
+	selector := ingressControllerSelector(cr)
+	if previous, ok := route.Annotations[routeShardLabelsAnnotation]; ok {
+		for _, key := range strings.Split(previous, ",") {
+			if _, ok := selector[key]; !ok {
+				delete(route.Labels, key)
+			}
+		}
+	}
+	if len(selector) == 0 {
+		delete(route.Annotations, routeShardLabelsAnnotation)
+		return
+	}
+	keys := make([]string, 0, len(selector))
+	for key, value := range selector {
+		metav1.SetMetaDataLabel(&route.ObjectMeta, key, value)
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	metav1.SetMetaDataAnnotation(&route.ObjectMeta, routeShardLabelsAnnotation, strings.Join(keys, ","))
+}
@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	routev1 "github.com/openshift/api/route/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+func Test_applyIngressControllerSelector(t *testing.T) {
+	tests := []struct {
+		name            string
+		selector        map[string]string
+		labels          map[string]string
+		annotations     map[string]string
+		wantLabels      map[string]string
+		wantAnnotations map[string]string
+	}{
+		{
+			name:            "Verify that the labels of the selector are set and recorded",
+			selector:        map[string]string{"router": "internal", "tier": "tools"},
+			labels:          map[string]string{mcpServerAppLabelKey: mcpServerName},
+			wantLabels:      map[string]string{mcpServerAppLabelKey: mcpServerName, "router": "internal", "tier": "tools"},
+			wantAnnotations: map[string]string{routeShardLabelsAnnotation: "router,tier"},
+		},
+		{
+			name:            "Verify that a label removed from the selector is removed from the Route",
+			selector:        map[string]string{"router": "public"},
+			labels:          map[string]string{mcpServerAppLabelKey: mcpServerName, "router": "internal", "tier": "tools"},
+			annotations:     map[string]string{routeShardLabelsAnnotation: "router,tier"},
+			wantLabels:      map[string]string{mcpServerAppLabelKey: mcpServerName, "router": "public"},
+			wantAnnotations: map[string]string{routeShardLabelsAnnotation: "router"},
+		},
+		{
+			name:            "Verify that unsetting the selector removes its labels and keeps labels set by hand",
+			labels:          map[string]string{mcpServerAppLabelKey: mcpServerName, "router": "internal", "team": "a"},
+			annotations:     map[string]string{routeShardLabelsAnnotation: "router", "other": "kept"},
+			wantLabels:      map[string]string{mcpServerAppLabelKey: mcpServerName, "team": "a"},
+			wantAnnotations: map[string]string{"other": "kept"},
+		},
+		{
+			name:       "Verify that a Route without a selector is left alone",
+			labels:     map[string]string{mcpServerAppLabelKey: mcpServerName, "router": "internal"},
+			wantLabels: map[string]string{mcpServerAppLabelKey: mcpServerName, "router": "internal"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &mcpserverv1.MCPServer{}
+			if tt.selector != nil {
+				cr.Spec.Route = &mcpserverv1.Route{IngressControllerSelector: tt.selector}
+			}
+			route := &routev1.Route{ObjectMeta: metav1.ObjectMeta{Labels: tt.labels, Annotations: tt.annotations}}
+			applyIngressControllerSelector(cr, route)
+			if !reflect.DeepEqual(route.Labels, tt.wantLabels) {
+				t.Errorf("labels = %v, want %v", route.Labels, tt.wantLabels)
+			}
+			if len(route.Annotations) != len(tt.wantAnnotations) ||
+				(len(tt.wantAnnotations) > 0 && !reflect.DeepEqual(route.Annotations, tt.wantAnnotations)) {
+				t.Errorf("annotations = %v, want %v", route.Annotations, tt.wantAnnotations)
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_reconcileMCPServerRoute_ingressControllerSelector(t *testing.T) {
+	fakeScheme := newAutoscalingScheme(t)
+	if err := routev1.AddToScheme(fakeScheme); err != nil {
+		t.Fatalf("failed to add routev1 scheme: %v", err)
+	}
+	cr := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace, UID: "uid"},
+		Spec: mcpserverv1.MCPServerSpec{
+			Image: mcpServerImage,
+			Route: &mcpserverv1.Route{IngressControllerSelector: map[string]string{"router": "internal"}},
+		},
+	}
+	cli := fake.NewClientBuilder().WithScheme(fakeScheme).Build()
+	r := &MCPServerReconciler{Client: cli, Scheme: fakeScheme}
+	ctx := context.Background()
+	getRoute := func() *routev1.Route {
+		t.Helper()
+		if err := r.reconcileMCPServerRoute(ctx, cli, cr); err != nil {
+			t.Fatalf("reconcileMCPServerRoute() error = %v", err)
+		}
+		route := &routev1.Route{}
+		if err := cli.Get(ctx, client.ObjectKeyFromObject(cr), route); err != nil {
+			t.Fatalf("failed to get the Route: %v", err)
+		}
+		return route
+	}
+
+	if route := getRoute(); route.Labels["router"] != "internal" {
+		t.Errorf("Route labels = %v, want router=internal", route.Labels)
+	}
+
+	// Moving the MCP server to another shard relabels the existing Route.
+	cr.Spec.Route.IngressControllerSelector = map[string]string{"router": "public"}
+	if route := getRoute(); route.Labels["router"] != "public" {
+		t.Errorf("Route labels = %v, want router=public", route.Labels)
+	}
+
+	cr.Spec.Route = nil
+	route := getRoute()
+	if _, ok := route.Labels["router"]; ok {
+		t.Errorf("Route labels = %v, want router removed", route.Labels)
+	}
+	if route.Labels[mcpServerAppLabelKey] != mcpServerName {
+		t.Errorf("Route labels = %v, want the label of the MCP server kept", route.Labels)
+	}
+}
@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/utils/ptr"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+const (
+	// secretsMountDir holds the files of Secrets exposed in File mode that have no path of their own.
+	secretsMountDir = "/var/run/secrets/mcpserver.opendatahub.io"
+
+	credentialsVolumeName           = "credentials"
+	guardrailsCredentialsVolumeName = "guardrails-credentials"
+	sessionStoreURLVolumeName       = "session-store-url"
+)
+
+// exposedSecret is the key of a Secret handed to a container, either in an environment variable or as a file
+// mounted from a Secret volume.
+type exposedSecret struct {
+	// Env is set in Env mode.
+	Env *corev1.EnvVar
+	// Volume and Mount are set in File mode, the file is at Path.
+	Volume *corev1.Volume
+	Mount  *corev1.VolumeMount
+	Path   string
+}
+
+// exposeSecret hands the key selected by ref to a container as exposure asks, in the environment variable
+// envName or as a file from a Secret volume named volumeName. defaultMode applies when exposure sets no mode.
+// The zero exposedSecret is returned when ref is nil.
+func exposeSecret(ref *corev1.SecretKeySelector, exposure *mcpserverv1.SecretExposure, defaultMode mcpserverv1.SecretExposureMode,
+	envName, volumeName string) exposedSecret {
+	if ref == nil {
+		return exposedSecret{}
+	}
+	if exposure == nil {
+		exposure = &mcpserverv1.SecretExposure{}
+	}
+	mode := exposure.Mode
+	if mode == "" {
+		mode = defaultMode
+	}
+
+	if mode != mcpserverv1.SecretExposureFile {
+		return exposedSecret{Env: &corev1.EnvVar{
+			Name:      envName,
+			ValueFrom: &corev1.EnvVarSource{SecretKeyRef: ref.DeepCopy()},
+		}}
+	}
+
+	filePath := exposure.Path
+	if filePath == "" {
+		filePath = path.Join(secretsMountDir, volumeName)
+	}
+	var fileMode *int32
+	if exposure.FileMode != nil {
+		fileMode = ptr.To(*exposure.FileMode)
+	}
+	return exposedSecret{
+		Volume: &corev1.Volume{
+			Name: volumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: ref.Name,
+					Items:      []corev1.KeyToPath{{Key: ref.Key, Path: ref.Key, Mode: fileMode}},
+					Optional:   ref.Optional,
+				},
+			},
+		},
+		// The file is mounted on its own, so that a path in an existing directory does not hide the directory.
+		Mount: &corev1.VolumeMount{
+			Name:      volumeName,
+			MountPath: filePath,
+			SubPath:   ref.Key,
+			ReadOnly:  true,
+		},
+		Path: filePath,
+	}
+}
+
+// withSecretVolume returns volumes with the Secret volume named name set to volume, or removed when volume is
+// nil. A volume already in volumes is kept when it projects the same keys of the same Secret, so that the default
+// mode set by the API server does not cause a rollout.
+func withSecretVolume(volumes []corev1.Volume, name string, volume *corev1.Volume) []corev1.Volume {
+	result := make([]corev1.Volume, 0, len(volumes)+1)
+	var existing *corev1.Volume
+	for i := range volumes {
+		if volumes[i].Name == name {
+			existing = &volumes[i]
+			continue
+		}
+		result = append(result, volumes[i])
+	}
+
+	if volume == nil {
+		return result
+	}
+	if existing != nil && existing.Secret != nil && existing.Secret.SecretName == volume.Secret.SecretName &&
+		equality.Semantic.DeepEqual(existing.Secret.Items, volume.Secret.Items) &&
+		equality.Semantic.DeepEqual(existing.Secret.Optional, volume.Secret.Optional) {
+		return append(result, *existing)
+	}
+	return append(result, *volume)
+}
@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+func Test_exposeSecret(t *testing.T) {
+	ref := &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "upstream"}, Key: "token"}
+
+	tests := []struct {
+		name        string
+		ref         *corev1.SecretKeySelector
+		exposure    *mcpserverv1.SecretExposure
+		defaultMode mcpserverv1.SecretExposureMode
+		wantEnv     bool
+		wantPath    string
+		wantMode    *int32
+	}{
+		{
+			name:        "no secret",
+			defaultMode: mcpserverv1.SecretExposureFile,
+		},
+		{
+			name:        "default mode Env",
+			ref:         ref,
+			defaultMode: mcpserverv1.SecretExposureEnv,
+			wantEnv:     true,
+		},
+		{
+			name:        "default mode File",
+			ref:         ref,
+			defaultMode: mcpserverv1.SecretExposureFile,
+			wantPath:    "/var/run/secrets/mcpserver.opendatahub.io/credentials",
+		},
+		{
+			name:        "File with path and mode",
+			ref:         ref,
+			exposure:    &mcpserverv1.SecretExposure{Mode: mcpserverv1.SecretExposureFile, Path: "/etc/upstream/token", FileMode: ptr.To[int32](0o400)},
+			defaultMode: mcpserverv1.SecretExposureEnv,
+			wantPath:    "/etc/upstream/token",
+			wantMode:    ptr.To[int32](0o400),
+		},
+		{
+			name:        "Env instead of the default",
+			ref:         ref,
+			exposure:    &mcpserverv1.SecretExposure{Mode: mcpserverv1.SecretExposureEnv},
+			defaultMode: mcpserverv1.SecretExposureFile,
+			wantEnv:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := exposeSecret(tt.ref, tt.exposure, tt.defaultMode, "TOKEN", credentialsVolumeName)
+			if (got.Env != nil) != tt.wantEnv {
+				t.Errorf("env = %v, want env %v", got.Env, tt.wantEnv)
+			}
+			if got.Path != tt.wantPath {
+				t.Errorf("path = %q, want %q", got.Path, tt.wantPath)
+			}
+			if tt.wantPath == "" {
+				if got.Volume != nil || got.Mount != nil {
+					t.Errorf("volume = %v with mount %v, want none", got.Volume, got.Mount)
+				}
+				return
+			}
+			if got.Mount.MountPath != tt.wantPath || got.Mount.SubPath != ref.Key || !got.Mount.ReadOnly {
+				t.Errorf("mount = %+v, want the key mounted read-only at %s", got.Mount, tt.wantPath)
+			}
+			items := got.Volume.Secret.Items
+			if got.Volume.Secret.SecretName != ref.Name || len(items) != 1 || items[0].Key != ref.Key ||
+				ptr.Deref(items[0].Mode, -1) != ptr.Deref(tt.wantMode, -1) {
+				t.Errorf("volume = %+v, want key %s of Secret %s", got.Volume.Secret, ref.Key, ref.Name)
+			}
+		})
+	}
+}
+
+func Test_withSecretVolume(t *testing.T) {
+	ref := &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "detector"}, Key: "token"}
+	desired := exposeSecret(ref, nil, mcpserverv1.SecretExposureFile, "TOKEN", guardrailsCredentialsVolumeName).Volume
+	defaulted := desired.DeepCopy()
+	defaulted.Secret.DefaultMode = ptr.To[int32](0o644)
+	cache := corev1.Volume{Name: cacheVolumeName, VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}
+
+	got := withSecretVolume([]corev1.Volume{cache}, guardrailsCredentialsVolumeName, desired)
+	if len(got) != 2 || got[1].Secret == nil {
+		t.Errorf("withSecretVolume() = %v, want the cache and the Secret volume", got)
+	}
+	// The default mode set by the API server is kept.
+	got = withSecretVolume([]corev1.Volume{cache, *defaulted}, guardrailsCredentialsVolumeName, desired)
+	if len(got) != 2 || got[1].Secret.DefaultMode == nil {
+		t.Errorf("withSecretVolume() = %v, want the existing Secret volume kept", got)
+	}
+	got = withSecretVolume([]corev1.Volume{cache, *defaulted}, guardrailsCredentialsVolumeName, nil)
+	if len(got) != 1 || got[0].Name != cacheVolumeName {
+		t.Errorf("withSecretVolume() = %v, want only the cache", got)
+	}
+}
@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+const (
+	// ReasonSCCDenied is set on the DeploymentAvailable condition when the pods of an MCP server are not admitted
+	// by any security context constraint the service account may use. It is also used as the reason of the
+	// emitted Warning event.
+	ReasonSCCDenied = "SecurityContextConstraintsDenied"
+
+	// requiredSCCAnnotation names the SCC that OpenShift must admit a pod with, instead of picking one by priority.
+	requiredSCCAnnotation = "openshift.io/required-scc"
+
+	// nonRootSCC admits pods that run as any non-root UID and with any fsGroup.
+	nonRootSCC = "nonroot-v2"
+
+	// anyUIDSCC admits pods that run as any UID, including root.
+	anyUIDSCC = "anyuid"
+
+	// sccAdmissionFailure is part of the error OpenShift reports when no SCC admits a pod.
+	sccAdmissionFailure = "unable to validate against any security context constraint"
+)
+
+// podSecurityContext returns the security context of the MCP server pods: spec.podSecurityContext with the user
+// and groups of spec.securityContext, which take precedence, or nil if neither is set.
+func podSecurityContext(cr *mcpserverv1.MCPServer) *corev1.PodSecurityContext {
+	securityContext := cr.Spec.SecurityContext
+	if securityContext == nil {
+		return cr.Spec.PodSecurityContext.DeepCopy()
+	}
+	podSecurityContext := &corev1.PodSecurityContext{}
+	if cr.Spec.PodSecurityContext != nil {
+		podSecurityContext = cr.Spec.PodSecurityContext.DeepCopy()
+	}
+	podSecurityContext.RunAsUser = cmp.Or(securityContext.RunAsUser, podSecurityContext.RunAsUser)
+	podSecurityContext.RunAsGroup = cmp.Or(securityContext.RunAsGroup, podSecurityContext.RunAsGroup)
+	podSecurityContext.FSGroup = cmp.Or(securityContext.FSGroup, podSecurityContext.FSGroup)
+	return podSecurityContext
+}
+
+// serverContainerSecurityContext returns the security context of the MCP server container:
+// spec.containerSecurityContext merged into the hardened security context of the proxy of Proxy MCP servers, or
+// nil for other MCP servers without it.
+func (r *MCPServerReconciler) serverContainerSecurityContext(cr *mcpserverv1.MCPServer) *corev1.SecurityContext {
+	var securityContext *corev1.SecurityContext
+	if isProxy(cr) {
+		securityContext = r.upstreamProxyContainer(cr).SecurityContext
+	}
+	if cr.Spec.ContainerSecurityContext == nil {
+		return securityContext
+	}
+	if securityContext == nil {
+		securityContext = &corev1.SecurityContext{}
+	}
+	withSecurityFields(securityContext, cr.Spec.ContainerSecurityContext.DeepCopy())
+	return securityContext
+}
+
+// withSecurityFields sets the fields that are set in override on securityContext.
+func withSecurityFields(securityContext, override *corev1.SecurityContext) {
+	securityContext.Capabilities = cmp.Or(override.Capabilities, securityContext.Capabilities)
+	securityContext.Privileged = cmp.Or(override.Privileged, securityContext.Privileged)
+	securityContext.SELinuxOptions = cmp.Or(override.SELinuxOptions, securityContext.SELinuxOptions)
+	securityContext.WindowsOptions = cmp.Or(override.WindowsOptions, securityContext.WindowsOptions)
+	securityContext.RunAsUser = cmp.Or(override.RunAsUser, securityContext.RunAsUser)
+	securityContext.RunAsGroup = cmp.Or(override.RunAsGroup, securityContext.RunAsGroup)
+	securityContext.RunAsNonRoot = cmp.Or(override.RunAsNonRoot, securityContext.RunAsNonRoot)
+	securityContext.ReadOnlyRootFilesystem = cmp.Or(override.ReadOnlyRootFilesystem, securityContext.ReadOnlyRootFilesystem)
+	securityContext.AllowPrivilegeEscalation = cmp.Or(override.AllowPrivilegeEscalation, securityContext.AllowPrivilegeEscalation)
+	securityContext.ProcMount = cmp.Or(override.ProcMount, securityContext.ProcMount)
+	securityContext.SeccompProfile = cmp.Or(override.SeccompProfile, securityContext.SeccompProfile)
+	securityContext.AppArmorProfile = cmp.Or(override.AppArmorProfile, securityContext.AppArmorProfile)
+}
+
+// requiredSCC returns the SCC the MCP server pods request on OpenShift. The default restricted-v2 SCC assigns
+// the UID and groups from the range of the namespace and rejects others, so pods with a UID or groups of their
+// own need nonroot-v2, or anyuid to run as root. It is empty on other platforms and for pods that leave the UID
+// and groups to the SCC.
+func (r *MCPServerReconciler) requiredSCC(cr *mcpserverv1.MCPServer) string {
+	if r.Platform == nil || !r.Platform.IsOpenShift() {
+		return ""
+	}
+	pod := podSecurityContext(cr)
+	if pod == nil {
+		pod = &corev1.PodSecurityContext{}
+	}
+	runAsUser := pod.RunAsUser
+	runAsGroup := pod.RunAsGroup
+	if container := cr.Spec.ContainerSecurityContext; container != nil {
+		runAsUser = cmp.Or(container.RunAsUser, runAsUser)
+		runAsGroup = cmp.Or(container.RunAsGroup, runAsGroup)
+	}
+	switch {
+	case runAsUser != nil && *runAsUser == 0:
+		return anyUIDSCC
+	case runAsUser != nil || runAsGroup != nil || pod.FSGroup != nil || len(pod.SupplementalGroups) > 0:
+		return nonRootSCC
+	}
+	return ""
+}
+
+// applySecurityContext sets the security contexts of spec.securityContext, spec.podSecurityContext and
+// spec.containerSecurityContext and the SCC they require on the pod template of an existing Deployment. Removing
+// the fields leaves both in place.
+func (r *MCPServerReconciler) applySecurityContext(deployment *appsv1.Deployment, cr *mcpserverv1.MCPServer) {
+	if cr.Spec.SecurityContext == nil && cr.Spec.PodSecurityContext == nil && cr.Spec.ContainerSecurityContext == nil {
+		return
+	}
+	if cr.Spec.SecurityContext != nil || cr.Spec.PodSecurityContext != nil {
+		deployment.Spec.Template.Spec.SecurityContext = podSecurityContext(cr)
+	}
+	if cr.Spec.ContainerSecurityContext != nil {
+		for i := range deployment.Spec.Template.Spec.Containers {
+			if container := &deployment.Spec.Template.Spec.Containers[i]; container.Name == "mcp-server" {
+				container.SecurityContext = r.serverContainerSecurityContext(cr)
+			}
+		}
+	}
+	if scc := r.requiredSCC(cr); scc != "" {
+		if deployment.Spec.Template.Annotations == nil {
+			deployment.Spec.Template.Annotations = map[string]string{}
+		}
+		deployment.Spec.Template.Annotations[requiredSCCAnnotation] = scc
+	}
+}
+
+// getSCCFailure returns a message describing why OpenShift did not admit the pods of the Deployment, or an empty
+// string when no pod was rejected by SCC admission. The Deployment controller reports the error of the
+// ReplicaSet in the ReplicaFailure condition.
+func getSCCFailure(deployment *appsv1.Deployment) string {
+	for _, cond := range deployment.Status.Conditions {
+		if cond.Type != appsv1.DeploymentReplicaFailure || cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		if strings.Contains(cond.Message, sccAdmissionFailure) {
+			return fmt.Sprintf("No security context constraint admits the pods of Deployment %s, grant the service "+
+				"account of the pods the SCC they need: %s", deployment.Name, cond.Message)
+		}
+	}
+	return ""
+}
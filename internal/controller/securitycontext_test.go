@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster"
+)
+
+func TestMCPServerReconciler_reconcileDeploymentSpec_securityContext(t *testing.T) {
+	fakeScheme := newAutoscalingScheme(t)
+	existingSecurityContext := &corev1.PodSecurityContext{RunAsUser: ptr.To[int64](1000)}
+
+	tests := []struct {
+		name                     string
+		platform                 string
+		securityContext          *mcpserverv1.SecurityContext
+		podSecurityContext       *corev1.PodSecurityContext
+		containerSecurityContext *corev1.SecurityContext
+		want                     *corev1.PodSecurityContext
+		wantContainer            *corev1.SecurityContext
+		wantSCC                  string
+		existingRunsAsUser       bool
+	}{
+		{
+			name:            "Verify that the UID and groups are set on Kubernetes without an SCC",
+			platform:        cluster.Kubernetes,
+			securityContext: &mcpserverv1.SecurityContext{RunAsUser: ptr.To[int64](1001), FSGroup: ptr.To[int64](2000)},
+			want:            &corev1.PodSecurityContext{RunAsUser: ptr.To[int64](1001), FSGroup: ptr.To[int64](2000)},
+		},
+		{
+			name:     "Verify that a non-root UID requests nonroot-v2 on OpenShift",
+			platform: cluster.OpenShift,
+			securityContext: &mcpserverv1.SecurityContext{RunAsUser: ptr.To[int64](1001),
+				RunAsGroup: ptr.To[int64](1001)},
+			want:    &corev1.PodSecurityContext{RunAsUser: ptr.To[int64](1001), RunAsGroup: ptr.To[int64](1001)},
+			wantSCC: nonRootSCC,
+		},
+		{
+			name:            "Verify that an fsGroup alone requests nonroot-v2 on OpenShift",
+			platform:        cluster.OpenShift,
+			securityContext: &mcpserverv1.SecurityContext{FSGroup: ptr.To[int64](2000)},
+			want:            &corev1.PodSecurityContext{FSGroup: ptr.To[int64](2000)},
+			wantSCC:         nonRootSCC,
+		},
+		{
+			name:            "Verify that root requests anyuid on OpenShift",
+			platform:        cluster.OpenShift,
+			securityContext: &mcpserverv1.SecurityContext{RunAsUser: ptr.To[int64](0)},
+			want:            &corev1.PodSecurityContext{RunAsUser: ptr.To[int64](0)},
+			wantSCC:         anyUIDSCC,
+		},
+		{
+			name:     "Verify that securityContext takes precedence over podSecurityContext",
+			platform: cluster.Kubernetes,
+			podSecurityContext: &corev1.PodSecurityContext{RunAsUser: ptr.To[int64](1000), RunAsNonRoot: ptr.To(true),
+				SupplementalGroups: []int64{3000}},
+			securityContext: &mcpserverv1.SecurityContext{RunAsUser: ptr.To[int64](1001)},
+			want: &corev1.PodSecurityContext{RunAsUser: ptr.To[int64](1001), RunAsNonRoot: ptr.To(true),
+				SupplementalGroups: []int64{3000}},
+		},
+		{
+			name:     "Verify that supplemental groups of podSecurityContext request nonroot-v2 on OpenShift",
+			platform: cluster.OpenShift,
+			podSecurityContext: &corev1.PodSecurityContext{SupplementalGroups: []int64{3000},
+				SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}},
+			want: &corev1.PodSecurityContext{SupplementalGroups: []int64{3000},
+				SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}},
+			wantSCC: nonRootSCC,
+		},
+		{
+			name:     "Verify that the containerSecurityContext is set on the MCP server container",
+			platform: cluster.OpenShift,
+			containerSecurityContext: &corev1.SecurityContext{ReadOnlyRootFilesystem: ptr.To(true),
+				Capabilities: &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}}},
+			wantContainer: &corev1.SecurityContext{ReadOnlyRootFilesystem: ptr.To(true),
+				Capabilities: &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}}},
+		},
+		{
+			name:                     "Verify that a root UID of containerSecurityContext requests anyuid on OpenShift",
+			platform:                 cluster.OpenShift,
+			containerSecurityContext: &corev1.SecurityContext{RunAsUser: ptr.To[int64](0)},
+			wantContainer:            &corev1.SecurityContext{RunAsUser: ptr.To[int64](0)},
+			wantSCC:                  anyUIDSCC,
+		},
+		{
+			name:               "Verify that removing securityContext leaves the existing one in place",
+			platform:           cluster.OpenShift,
+			existingRunsAsUser: true,
+			want:               existingSecurityContext,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &mcpserverv1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace, UID: types.UID("uid")},
+				Spec: mcpserverv1.MCPServerSpec{Image: mcpServerImage, SecurityContext: tt.securityContext,
+					PodSecurityContext: tt.podSecurityContext, ContainerSecurityContext: tt.containerSecurityContext},
+			}
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+				Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "mcp-server", Image: mcpServerImage}},
+				}}},
+			}
+			if tt.existingRunsAsUser {
+				deployment.Spec.Template.Spec.SecurityContext = existingSecurityContext.DeepCopy()
+			}
+			cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(deployment).Build()
+			r := &MCPServerReconciler{Client: cli, Scheme: fakeScheme, Platform: &cluster.Platform{Name: tt.platform}}
+
+			if err := r.reconcileDeploymentSpec(context.Background(), cli, cr); err != nil {
+				t.Fatalf("reconcileDeploymentSpec() error = %v", err)
+			}
+
+			got := &appsv1.Deployment{}
+			if err := cli.Get(context.Background(), client.ObjectKeyFromObject(deployment), got); err != nil {
+				t.Fatalf("failed to get the Deployment: %v", err)
+			}
+			if !equality.Semantic.DeepEqual(got.Spec.Template.Spec.SecurityContext, tt.want) {
+				t.Errorf("securityContext = %+v, want %+v", got.Spec.Template.Spec.SecurityContext, tt.want)
+			}
+			if container := got.Spec.Template.Spec.Containers[0]; !equality.Semantic.DeepEqual(container.SecurityContext, tt.wantContainer) {
+				t.Errorf("container securityContext = %+v, want %+v", container.SecurityContext, tt.wantContainer)
+			}
+			if scc := got.Spec.Template.Annotations[requiredSCCAnnotation]; scc != tt.wantSCC {
+				t.Errorf("required SCC = %q, want %q", scc, tt.wantSCC)
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_serverContainerSecurityContext(t *testing.T) {
+	r := &MCPServerReconciler{OperatorImage: "operator-image"}
+	cr := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+		Spec: mcpserverv1.MCPServerSpec{
+			Type:                     mcpserverv1.MCPServerProxy,
+			URL:                      "https://mcp.example.com/sse",
+			ContainerSecurityContext: &corev1.SecurityContext{ReadOnlyRootFilesystem: ptr.To(true)},
+		},
+	}
+
+	// The fields that are set replace those of the proxy, the others are kept.
+	want := &corev1.SecurityContext{
+		AllowPrivilegeEscalation: ptr.To(false),
+		Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+		ReadOnlyRootFilesystem:   ptr.To(true),
+	}
+	if got := r.serverContainerSecurityContext(cr); !equality.Semantic.DeepEqual(got, want) {
+		t.Errorf("serverContainerSecurityContext() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"context"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;create;patch;delete
+
+// defaultServiceAccountName is the service account every namespace has, which Proxy MCP server pods run with
+// unless spec.serviceAccountName is set.
+const defaultServiceAccountName = "default"
+
+// usesOwnServiceAccount reports whether the operator creates a service account for the pods of cr. Proxy MCP
+// servers keep the default service account, which the proxy-auth ClusterRole is bound to.
+func usesOwnServiceAccount(cr *mcpserverv1.MCPServer) bool {
+	return cr.Spec.ServiceAccountName == "" && !isExternal(cr) && !isProxy(cr)
+}
+
+// podServiceAccountName returns the service account the MCP server pods of cr run with.
+func podServiceAccountName(cr *mcpserverv1.MCPServer) string {
+	switch {
+	case cr.Spec.ServiceAccountName != "":
+		return cr.Spec.ServiceAccountName
+	case usesOwnServiceAccount(cr):
+		return resourceName(cr)
+	}
+	return defaultServiceAccountName
+}
+
+// getPodServiceAccount returns the service account the MCP server pods of cr run with, or nil when it does not
+// exist. Until the operator has created the service account of its own, the default service account it takes the
+// image pull secrets from is returned in its place.
+func (r *MCPServerReconciler) getPodServiceAccount(ctx context.Context, cr *mcpserverv1.MCPServer) (*corev1.ServiceAccount, error) {
+	names := []string{podServiceAccountName(cr)}
+	if usesOwnServiceAccount(cr) {
+		names = append(names, defaultServiceAccountName)
+	}
+	for _, name := range names {
+		serviceAccount := &corev1.ServiceAccount{}
+		err := r.Get(ctx, client.ObjectKey{Name: name, Namespace: cr.Namespace}, serviceAccount)
+		if k8serr.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return serviceAccount, nil
+	}
+	return nil, nil
+}
+
+// reconcileServiceAccount creates the service account of the MCP server pods of cr, or removes it when they run
+// with another one. It gets the image pull secrets of the default service account, so that images from private
+// registries the namespace was set up for can still be pulled. Pull secrets added to the default service account
+// later are added as well, and others, such as those OpenShift adds for its internal registry, are kept.
+func (r *MCPServerReconciler) reconcileServiceAccount(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) error {
+	serviceAccount := &corev1.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ServiceAccount",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resourceName(cr),
+			Namespace: cr.Namespace,
+			Labels:    map[string]string{mcpServerAppLabelKey: resourceName(cr)},
+		},
+	}
+	if !usesOwnServiceAccount(cr) {
+		return r.deleteChild(ctx, cli, cr, serviceAccount)
+	}
+
+	defaultServiceAccount := &corev1.ServiceAccount{}
+	err := cli.Get(ctx, client.ObjectKey{Name: defaultServiceAccountName, Namespace: cr.Namespace}, defaultServiceAccount)
+	if err != nil && !k8serr.IsNotFound(err) {
+		return err
+	}
+	serviceAccount.ImagePullSecrets = defaultServiceAccount.ImagePullSecrets
+	if err := r.createChild(ctx, cli, cr, serviceAccount.DeepCopy()); err != nil {
+		return err
+	}
+	if len(serviceAccount.ImagePullSecrets) == 0 {
+		return nil
+	}
+
+	existing := &corev1.ServiceAccount{}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(serviceAccount), existing); err != nil {
+		// A service account that was only created as a dry run does not exist.
+		if k8serr.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !metav1.IsControlledBy(existing, cr) {
+		return nil
+	}
+	original := existing.DeepCopy()
+	for _, ref := range serviceAccount.ImagePullSecrets {
+		if !slices.Contains(existing.ImagePullSecrets, ref) {
+			existing.ImagePullSecrets = append(existing.ImagePullSecrets, ref)
+		}
+	}
+	if len(existing.ImagePullSecrets) == len(original.ImagePullSecrets) {
+		return nil
+	}
+	logChildDiff(ctx, original, existing)
+	return cli.Patch(ctx, existing, client.MergeFrom(original))
+}
@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+func TestMCPServerReconciler_reconcileServiceAccount(t *testing.T) {
+	fakeScheme := newAutoscalingScheme(t)
+	defaultServiceAccount := &corev1.ServiceAccount{
+		ObjectMeta:       metav1.ObjectMeta{Name: defaultServiceAccountName, Namespace: testNamespace},
+		ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry-pull"}},
+	}
+
+	tests := []struct {
+		name            string
+		spec            mcpserverv1.MCPServerSpec
+		existingSecrets []corev1.LocalObjectReference
+		wantName        string
+		wantSecrets     []corev1.LocalObjectReference
+	}{
+		{
+			name:        "Verify that a Managed server gets a service account with the pull secrets of the default one",
+			spec:        mcpserverv1.MCPServerSpec{Image: mcpServerImage},
+			wantName:    mcpServerName,
+			wantSecrets: []corev1.LocalObjectReference{{Name: "registry-pull"}},
+		},
+		{
+			name:            "Verify that the pull secrets of the default service account are added to existing ones",
+			spec:            mcpserverv1.MCPServerSpec{Image: mcpServerImage},
+			existingSecrets: []corev1.LocalObjectReference{{Name: "test-mcpserver-dockercfg-abcde"}},
+			wantName:        mcpServerName,
+			wantSecrets: []corev1.LocalObjectReference{
+				{Name: "test-mcpserver-dockercfg-abcde"},
+				{Name: "registry-pull"},
+			},
+		},
+		{
+			name:            "Verify that the service account is removed when the pods run with another one",
+			spec:            mcpserverv1.MCPServerSpec{Image: mcpServerImage, ServiceAccountName: "tools"},
+			existingSecrets: []corev1.LocalObjectReference{},
+			wantName:        "tools",
+		},
+		{
+			name:     "Verify that a Proxy server runs with the default service account",
+			spec:     mcpserverv1.MCPServerSpec{Type: mcpserverv1.MCPServerProxy, URL: "https://mcp.example.com/sse"},
+			wantName: defaultServiceAccountName,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &mcpserverv1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace, UID: "uid"},
+				Spec:       tt.spec,
+			}
+			objects := []client.Object{defaultServiceAccount.DeepCopy()}
+			if tt.existingSecrets != nil {
+				existing := &corev1.ServiceAccount{
+					ObjectMeta:       metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+					ImagePullSecrets: tt.existingSecrets,
+				}
+				if err := ctrl.SetControllerReference(cr, existing, fakeScheme); err != nil {
+					t.Fatalf("SetControllerReference() error = %v", err)
+				}
+				objects = append(objects, existing)
+			}
+			cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(objects...).Build()
+			r := &MCPServerReconciler{Client: cli, Scheme: fakeScheme}
+
+			if err := r.reconcileServiceAccount(context.Background(), cli, cr); err != nil {
+				t.Fatalf("reconcileServiceAccount() error = %v", err)
+			}
+			if got := podServiceAccountName(cr); got != tt.wantName {
+				t.Errorf("podServiceAccountName() = %s, want %s", got, tt.wantName)
+			}
+
+			serviceAccount := &corev1.ServiceAccount{}
+			err := cli.Get(context.Background(), client.ObjectKey{Name: mcpServerName, Namespace: testNamespace}, serviceAccount)
+			if tt.wantName != mcpServerName {
+				if !k8serr.IsNotFound(err) {
+					t.Errorf("Get() service account error = %v, want it not found", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Get() service account error = %v", err)
+			}
+			if !metav1.IsControlledBy(serviceAccount, cr) {
+				t.Errorf("service account owners = %v, want the MCPServer", serviceAccount.OwnerReferences)
+			}
+			if !reflect.DeepEqual(serviceAccount.ImagePullSecrets, tt.wantSecrets) {
+				t.Errorf("image pull secrets = %v, want %v", serviceAccount.ImagePullSecrets, tt.wantSecrets)
+			}
+		})
+	}
+}
@@ -0,0 +1,326 @@
+package controller
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+const (
+	// DefaultSessionStoreImage is the Redis image of session stores provisioned by the operator, the Redis 7 build
+	// for CentOS Stream 9. Installations pin it to a digest with the SESSION_STORE_IMAGE environment variable.
+	DefaultSessionStoreImage = "quay.io/sclorg/redis-7-c9s:c9s"
+
+	// sessionStoreLabelKey selects the pods of a provisioned session store. They deliberately do not carry
+	// the MCP server label, which would put them behind the MCP server Service.
+	sessionStoreLabelKey = "opendatahub.io/mcp-server-session-store"
+
+	sessionStorePort = 6379
+
+	// sessionStorePasswordKey and sessionStoreURLKey hold the password of a provisioned session store and the URL
+	// with that password in its Secret.
+	sessionStorePasswordKey = "password"
+	sessionStoreURLKey      = "url"
+
+	// sessionStorePasswordEnv makes the Redis image require the password.
+	sessionStorePasswordEnv = "REDIS_PASSWORD"
+
+	sessionStoreTypeEnv = "MCP_SESSION_STORE_TYPE"
+	sessionStoreURLEnv  = "MCP_SESSION_STORE_URL"
+
+	// sessionStoreURLFileEnv holds the path of the file with the URL of the store when it is exposed as a file.
+	sessionStoreURLFileEnv = "MCP_SESSION_STORE_URL_FILE"
+)
+
+// sessionStoreSuffix is the suffix of the name of the session store Deployment and Service of an MCP server.
+const sessionStoreSuffix = "session-store"
+
+func sessionStoreName(cr *mcpserverv1.MCPServer) string {
+	return childName(cr, sessionStoreSuffix)
+}
+
+// provisionsSessionStore reports whether the operator runs the session store of cr.
+func provisionsSessionStore(cr *mcpserverv1.MCPServer) bool {
+	return cr.Spec.SessionStore != nil && cr.Spec.SessionStore.URLSecretRef == nil
+}
+
+// sessionStoreURL returns the URL of an existing session store, as spec.sessionStore.urlExposure asks.
+func sessionStoreURL(cr *mcpserverv1.MCPServer) exposedSecret {
+	store := cr.Spec.SessionStore
+	if store == nil {
+		return exposedSecret{}
+	}
+	return exposeSecret(store.URLSecretRef, store.URLExposure, mcpserverv1.SecretExposureEnv, sessionStoreURLEnv,
+		sessionStoreURLVolumeName)
+}
+
+// sessionStoreEnv returns the environment variables that point the MCP server at its session store.
+func sessionStoreEnv(cr *mcpserverv1.MCPServer) []corev1.EnvVar {
+	store := cr.Spec.SessionStore
+	if store == nil {
+		return nil
+	}
+
+	storeType := store.Type
+	if storeType == "" {
+		storeType = mcpserverv1.SessionStoreRedis
+	}
+	url := corev1.EnvVar{
+		Name: sessionStoreURLEnv,
+		ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: sessionStoreName(cr)},
+			Key:                  sessionStoreURLKey,
+		}},
+	}
+	if secret := sessionStoreURL(cr); secret.Env != nil {
+		url = *secret.Env
+	} else if secret.Mount != nil {
+		url = corev1.EnvVar{Name: sessionStoreURLFileEnv, Value: secret.Path}
+	}
+	return []corev1.EnvVar{
+		{Name: sessionStoreTypeEnv, Value: strings.ToLower(string(storeType))},
+		url,
+	}
+}
+
+// reconcileSessionStore creates the Deployment and Service of a session store provisioned by the operator, the
+// Secret with its password and the NetworkPolicy that only admits the MCP server pods to it, and removes them once
+// the MCPServer no longer asks for one. A session store under the legacy name of a shortened name keeps serving
+// until the renamed one is available, since the pods of both carry the labels the Services select.
+func (r *MCPServerReconciler) reconcileSessionStore(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) error {
+	if !provisionsSessionStore(cr) {
+		return r.deleteSessionStore(ctx, cli, cr)
+	}
+
+	image := r.SessionStoreImage
+	if image == "" {
+		image = DefaultSessionStoreImage
+	}
+
+	selector := map[string]string{
+		sessionStoreLabelKey: resourceName(cr),
+	}
+	// The MCP server label on the objects themselves makes the controller watch them.
+	labels := map[string]string{
+		mcpServerAppLabelKey: resourceName(cr),
+		sessionStoreLabelKey: resourceName(cr),
+	}
+
+	if err := r.reconcileSessionStoreSecret(ctx, cli, cr, labels); err != nil {
+		return err
+	}
+	password := []corev1.EnvVar{{
+		Name: sessionStorePasswordEnv,
+		ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: sessionStoreName(cr)},
+			Key:                  sessionStorePasswordKey,
+		}},
+	}}
+
+	deployment := &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sessionStoreName(cr),
+			Namespace: cr.Namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To[int32](1),
+			Selector: &metav1.LabelSelector{
+				MatchLabels: selector,
+			},
+			Strategy: appsv1.DeploymentStrategy{
+				Type: appsv1.RecreateDeploymentStrategyType,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: selector,
+				},
+				Spec: corev1.PodSpec{
+					SecurityContext: &corev1.PodSecurityContext{
+						RunAsNonRoot: ptr.To(true),
+						SeccompProfile: &corev1.SeccompProfile{
+							Type: corev1.SeccompProfileTypeRuntimeDefault,
+						},
+					},
+					Containers: []corev1.Container{{
+						Name:  "redis",
+						Image: image,
+						Env:   password,
+						Ports: []corev1.ContainerPort{{
+							ContainerPort: sessionStorePort,
+							Name:          "redis",
+						}},
+						ReadinessProbe: &corev1.Probe{
+							ProbeHandler: corev1.ProbeHandler{
+								TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromString("redis")},
+							},
+						},
+						SecurityContext: &corev1.SecurityContext{
+							AllowPrivilegeEscalation: ptr.To(false),
+							Capabilities: &corev1.Capabilities{
+								Drop: []corev1.Capability{"ALL"},
+							},
+						},
+						VolumeMounts: []corev1.VolumeMount{{
+							Name:      "data",
+							MountPath: "/var/lib/redis/data",
+						}},
+					}},
+					// Sessions are short-lived, so they are not worth persisting across restarts.
+					Volumes: []corev1.Volume{{
+						Name: "data",
+						VolumeSource: corev1.VolumeSource{
+							EmptyDir: &corev1.EmptyDirVolumeSource{},
+						},
+					}},
+				},
+			},
+		},
+	}
+	err := r.createChild(ctx, cli, cr, deployment)
+	if err != nil {
+		return err
+	}
+
+	service := &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sessionStoreName(cr),
+			Namespace: cr.Namespace,
+			Labels:    labels,
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: selector,
+			Ports: []corev1.ServicePort{{
+				Name:       "redis",
+				Port:       sessionStorePort,
+				TargetPort: intstr.FromString("redis"),
+				Protocol:   corev1.ProtocolTCP,
+			}},
+		},
+	}
+	if err := r.createChild(ctx, cli, cr, service); err != nil {
+		return err
+	}
+
+	if err := r.createChild(ctx, cli, cr, sessionStoreNetworkPolicy(cr, labels, selector)); err != nil {
+		return err
+	}
+
+	existing := &appsv1.Deployment{}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(deployment), existing); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	// Session stores provisioned before they had a password are given one.
+	if containers := existing.Spec.Template.Spec.Containers; metav1.IsControlledBy(existing, cr) && len(containers) > 0 &&
+		!equality.Semantic.DeepEqual(containers[0].Env, password) {
+		original := existing.DeepCopy()
+		existing.Spec.Template.Spec.Containers[0].Env = password
+		logChildDiff(ctx, original, existing)
+		return cli.Patch(ctx, existing, client.MergeFrom(original))
+	}
+	if !rolloutComplete(existing) {
+		return nil
+	}
+	return r.deleteLegacySessionStore(ctx, cli, cr)
+}
+
+// reconcileSessionStoreSecret creates the Secret with a generated password of the session store of cr and the
+// URL the MCP server connects to it with. The password of an existing Secret is kept.
+func (r *MCPServerReconciler) reconcileSessionStoreSecret(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer,
+	labels map[string]string) error {
+	password := make([]byte, 32)
+	if _, err := rand.Read(password); err != nil {
+		return fmt.Errorf("failed to generate the session store password of %s: %w", cr.Name, err)
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(password)
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sessionStoreName(cr),
+			Namespace: cr.Namespace,
+			Labels:    labels,
+		},
+		StringData: map[string]string{
+			sessionStorePasswordKey: encoded,
+			sessionStoreURLKey: fmt.Sprintf("redis://:%s@%s.%s.svc:%d", encoded, sessionStoreName(cr), cr.Namespace,
+				sessionStorePort),
+		},
+	}
+	return r.createChild(ctx, cli, cr, secret)
+}
+
+// sessionStoreNetworkPolicy returns the NetworkPolicy that only admits the MCP server pods of cr to the pods of its
+// session store, which are selected by selector.
+func sessionStoreNetworkPolicy(cr *mcpserverv1.MCPServer, labels, selector map[string]string) *networkingv1.NetworkPolicy {
+	return &networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: networkingv1.SchemeGroupVersion.String(),
+			Kind:       "NetworkPolicy",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sessionStoreName(cr),
+			Namespace: cr.Namespace,
+			Labels:    labels,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: selector},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{
+				From: []networkingv1.NetworkPolicyPeer{{
+					PodSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{mcpServerAppLabelKey: resourceName(cr)},
+					},
+				}},
+				Ports: []networkingv1.NetworkPolicyPort{{
+					Protocol: ptr.To(corev1.ProtocolTCP),
+					Port:     ptr.To(intstr.FromString("redis")),
+				}},
+			}},
+		},
+	}
+}
+
+func (r *MCPServerReconciler) deleteSessionStore(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) error {
+	for _, obj := range []client.Object{&appsv1.Deployment{}, &corev1.Service{}, &corev1.Secret{}, &networkingv1.NetworkPolicy{}} {
+		obj.SetName(sessionStoreName(cr))
+		obj.SetNamespace(cr.Namespace)
+		if err := r.deleteChild(ctx, cli, cr, obj); err != nil {
+			return err
+		}
+	}
+	return r.deleteLegacySessionStore(ctx, cli, cr)
+}
+
+// deleteLegacySessionStore removes the Deployment and Service of the session store under their legacy name.
+func (r *MCPServerReconciler) deleteLegacySessionStore(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) error {
+	for _, obj := range []client.Object{&appsv1.Deployment{}, &corev1.Service{}} {
+		if err := r.deleteLegacyChild(ctx, cli, cr, obj, sessionStoreSuffix); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package controller
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_sessionStoreEnv(t *testing.T) {
+	secretRef := &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "redis"},
+		Key:                  "url",
+	}
+
+	tests := []struct {
+		name  string
+		store *mcpserverv1.SessionStore
+		want  []corev1.EnvVar
+	}{
+		{
+			name: "no session store",
+		},
+		{
+			name:  "provisioned store",
+			store: &mcpserverv1.SessionStore{},
+			want: []corev1.EnvVar{
+				{Name: sessionStoreTypeEnv, Value: "redis"},
+				{Name: sessionStoreURLEnv, ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "test-mcpserver-session-store"},
+					Key:                  "url",
+				}}},
+			},
+		},
+		{
+			name:  "existing store",
+			store: &mcpserverv1.SessionStore{Type: mcpserverv1.SessionStoreRedis, URLSecretRef: secretRef},
+			want: []corev1.EnvVar{
+				{Name: sessionStoreTypeEnv, Value: "redis"},
+				{Name: sessionStoreURLEnv, ValueFrom: &corev1.EnvVarSource{SecretKeyRef: secretRef}},
+			},
+		},
+		{
+			name: "existing store with the URL in a file",
+			store: &mcpserverv1.SessionStore{URLSecretRef: secretRef, URLExposure: &mcpserverv1.SecretExposure{
+				Mode: mcpserverv1.SecretExposureFile, Path: "/etc/session-store/url"}},
+			want: []corev1.EnvVar{
+				{Name: sessionStoreTypeEnv, Value: "redis"},
+				{Name: sessionStoreURLFileEnv, Value: "/etc/session-store/url"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &mcpserverv1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+				Spec:       mcpserverv1.MCPServerSpec{SessionStore: tt.store},
+			}
+			if got := sessionStoreEnv(cr); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sessionStoreEnv() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_reconcileSessionStore(t *testing.T) {
+	fakeScheme := runtime.NewScheme()
+	err := clientgoscheme.AddToScheme(fakeScheme)
+	if err != nil {
+		t.Errorf("failed to add client-go scheme: %v", err)
+	}
+	err = mcpserverv1.AddToScheme(fakeScheme)
+	if err != nil {
+		t.Errorf("failed to add mcpserverv1 scheme: %v", err)
+	}
+
+	newMCPServer := func(store *mcpserverv1.SessionStore) *mcpserverv1.MCPServer {
+		return &mcpserverv1.MCPServer{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      mcpServerName,
+				Namespace: testNamespace,
+				UID:       types.UID("uid"),
+			},
+			Spec: mcpserverv1.MCPServerSpec{
+				Image:        mcpServerImage,
+				SessionStore: store,
+			},
+		}
+	}
+	existingStore := &mcpserverv1.SessionStore{
+		URLSecretRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "redis"},
+			Key:                  "url",
+		},
+	}
+
+	tests := []struct {
+		name         string
+		previous     *mcpserverv1.SessionStore
+		unprotected  bool
+		store        *mcpserverv1.SessionStore
+		wantObjects  bool
+		wantSelector map[string]string
+	}{
+		{
+			name: "no session store",
+		},
+		{
+			name:         "provisioned store",
+			store:        &mcpserverv1.SessionStore{},
+			wantObjects:  true,
+			wantSelector: map[string]string{sessionStoreLabelKey: mcpServerName},
+		},
+		{
+			name:         "provisioned store without a password",
+			previous:     &mcpserverv1.SessionStore{},
+			unprotected:  true,
+			store:        &mcpserverv1.SessionStore{},
+			wantObjects:  true,
+			wantSelector: map[string]string{sessionStoreLabelKey: mcpServerName},
+		},
+		{
+			name:  "existing store",
+			store: existingStore,
+		},
+		{
+			name:     "provisioned store is removed",
+			previous: &mcpserverv1.SessionStore{},
+			store:    existingStore,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().WithScheme(fakeScheme).Build()
+			r := &MCPServerReconciler{Client: cli, Scheme: fakeScheme}
+
+			if tt.previous != nil {
+				if err := r.reconcileSessionStore(context.Background(), cli, newMCPServer(tt.previous)); err != nil {
+					t.Fatalf("reconcileSessionStore() error = %v", err)
+				}
+			}
+			key := client.ObjectKey{Name: mcpServerName + "-session-store", Namespace: testNamespace}
+			if tt.unprotected {
+				deployment := &appsv1.Deployment{}
+				if err := cli.Get(context.Background(), key, deployment); err != nil {
+					t.Fatalf("failed to get the session store: %v", err)
+				}
+				deployment.Spec.Template.Spec.Containers[0].Env = nil
+				if err := cli.Update(context.Background(), deployment); err != nil {
+					t.Fatalf("failed to update the session store: %v", err)
+				}
+			}
+			if err := r.reconcileSessionStore(context.Background(), cli, newMCPServer(tt.store)); err != nil {
+				t.Fatalf("reconcileSessionStore() error = %v", err)
+			}
+
+			deployment := &appsv1.Deployment{}
+			deploymentErr := cli.Get(context.Background(), key, deployment)
+			service := &corev1.Service{}
+			serviceErr := cli.Get(context.Background(), key, service)
+			secret := &corev1.Secret{}
+			secretErr := cli.Get(context.Background(), key, secret)
+			policy := &networkingv1.NetworkPolicy{}
+			policyErr := cli.Get(context.Background(), key, policy)
+			if !tt.wantObjects {
+				for _, err := range []error{deploymentErr, serviceErr, secretErr, policyErr} {
+					if !k8serr.IsNotFound(err) {
+						t.Errorf("expected no session store, got error %v", err)
+					}
+				}
+				return
+			}
+			if deploymentErr != nil || serviceErr != nil || secretErr != nil || policyErr != nil {
+				t.Fatalf("failed to get the session store: %v, %v, %v, %v", deploymentErr, serviceErr, secretErr, policyErr)
+			}
+			password := secret.StringData[sessionStorePasswordKey]
+			if password == "" || !strings.HasPrefix(secret.StringData[sessionStoreURLKey], "redis://:"+password+"@") {
+				t.Errorf("Secret data = %v, want a password and the URL with it", secret.StringData)
+			}
+			if env := deployment.Spec.Template.Spec.Containers[0].Env; len(env) != 1 || env[0].Name != sessionStorePasswordEnv ||
+				env[0].ValueFrom.SecretKeyRef.Key != sessionStorePasswordKey {
+				t.Errorf("Redis env = %v, want the password of the Secret", env)
+			}
+			if !reflect.DeepEqual(policy.Spec.PodSelector.MatchLabels, tt.wantSelector) ||
+				policy.Spec.Ingress[0].From[0].PodSelector.MatchLabels[mcpServerAppLabelKey] != mcpServerName {
+				t.Errorf("NetworkPolicy = %+v, want only the MCP server pods admitted to the store", policy.Spec)
+			}
+			if !reflect.DeepEqual(deployment.Spec.Template.Labels, tt.wantSelector) {
+				t.Errorf("pod labels = %v, want %v", deployment.Spec.Template.Labels, tt.wantSelector)
+			}
+			if !reflect.DeepEqual(service.Spec.Selector, tt.wantSelector) {
+				t.Errorf("service selector = %v, want %v", service.Spec.Selector, tt.wantSelector)
+			}
+			if deployment.Spec.Template.Spec.Containers[0].Image != DefaultSessionStoreImage {
+				t.Errorf("image = %s, want %s", deployment.Spec.Template.Spec.Containers[0].Image, DefaultSessionStoreImage)
+			}
+		})
+	}
+}
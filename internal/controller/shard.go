@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// Shard is the part of the MCPServers, MCPServerPools and MCPServerClaims a replica of the operator reconciles
+// when the work is spread across several active replicas. Each object belongs to the shard of the hash of its
+// namespace and name.
+type Shard struct {
+	// ID is the shard of this replica, from 0 to Count-1.
+	ID int
+
+	// Count is the number of shards.
+	Count int
+}
+
+// ShardOf returns the shard of the object with key among count shards.
+func ShardOf(key types.NamespacedName, count int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key.Namespace + "/" + key.Name))
+	return int(h.Sum32() % uint32(count))
+}
+
+// Owns reports whether the object with key belongs to the shard. Every object belongs to a nil Shard, which is
+// the operator running unsharded.
+func (s *Shard) Owns(key types.NamespacedName) bool {
+	return s == nil || s.Count <= 1 || ShardOf(key, s.Count) == s.ID
+}
+
+// ShardFromHostname returns the ordinal a StatefulSet gives the pod named hostname, e.g. 2 for
+// mcp-server-operator-2, which is the shard of a replica run by a StatefulSet.
+func ShardFromHostname(hostname string) (int, error) {
+	i := strings.LastIndex(hostname, "-")
+	id, err := strconv.Atoi(hostname[i+1:])
+	if i < 0 || err != nil || id < 0 {
+		return 0, fmt.Errorf("hostname %q does not end with the ordinal of a StatefulSet pod", hostname)
+	}
+	return id, nil
+}
+
+// sharded reports whether the operator runs with several shards.
+func (s *Shard) sharded() bool {
+	return s != nil && s.Count > 1
+}
+
+// status returns the shard reported in the status of the objects of the shard, nil when the operator runs
+// unsharded.
+func (s *Shard) status() *int32 {
+	if !s.sharded() {
+		return nil
+	}
+	return ptr.To(int32(s.ID))
+}
+
+// reconcileShardLabel sets the shard label of cr to the shard of this replica, so that the MCPServers of a shard
+// can be selected. The label is removed once the operator no longer runs sharded.
+func (r *MCPServerReconciler) reconcileShardLabel(ctx context.Context, cr *mcpserverv1.MCPServer) error {
+	want := ""
+	if r.Shard.sharded() {
+		want = strconv.Itoa(r.Shard.ID)
+	}
+	if cr.Labels[mcpserverv1.ShardLabel] == want || r.DryRun {
+		return nil
+	}
+
+	original := cr.DeepCopy()
+	if want == "" {
+		delete(cr.Labels, mcpserverv1.ShardLabel)
+	} else {
+		if cr.Labels == nil {
+			cr.Labels = map[string]string{}
+		}
+		cr.Labels[mcpserverv1.ShardLabel] = want
+	}
+	return client.IgnoreNotFound(r.Patch(ctx, cr, client.MergeFrom(original)))
+}
@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster"
+)
+
+func TestShardOf(t *testing.T) {
+	counts := make([]int, 4)
+	for i := 0; i < 400; i++ {
+		key := types.NamespacedName{Namespace: testNamespace, Name: fmt.Sprintf("server-%d", i)}
+		shard := ShardOf(key, len(counts))
+		if shard != ShardOf(key, len(counts)) {
+			t.Fatalf("ShardOf(%v) is not stable", key)
+		}
+		counts[shard]++
+	}
+	for shard, count := range counts {
+		if count < 50 {
+			t.Errorf("shard %d got %d of 400 objects, want them spread across the shards", shard, count)
+		}
+	}
+}
+
+func TestShardFromHostname(t *testing.T) {
+	tests := []struct {
+		name     string
+		hostname string
+		want     int
+		wantErr  bool
+	}{
+		{
+			name:     "Verify that the ordinal of a StatefulSet pod is the shard",
+			hostname: "mcp-server-operator-controller-manager-2",
+			want:     2,
+		},
+		{
+			name:     "Verify that a Deployment pod has no shard",
+			hostname: "mcp-server-operator-controller-manager-7d9f8b6c5-x2v4q",
+			wantErr:  true,
+		},
+		{
+			name:     "Verify that a hostname without a dash has no shard",
+			hostname: "3",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ShardFromHostname(tt.hostname)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ShardFromHostname() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ShardFromHostname() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_Reconcile_shard(t *testing.T) {
+	key := types.NamespacedName{Namespace: testNamespace, Name: mcpServerName}
+	owner := ShardOf(key, 3)
+
+	tests := []struct {
+		name      string
+		shard     *Shard
+		labels    map[string]string
+		wantOwned bool
+		wantLabel string
+		wantShard *int32
+	}{
+		{
+			name:      "Verify that an unsharded operator reconciles every MCPServer",
+			wantOwned: true,
+		},
+		{
+			name:      "Verify that the shard of the MCPServer labels it and reports itself in the status",
+			shard:     &Shard{ID: owner, Count: 3},
+			wantOwned: true,
+			wantLabel: fmt.Sprint(owner),
+			wantShard: ptr.To(int32(owner)),
+		},
+		{
+			name:  "Verify that another shard leaves the MCPServer alone",
+			shard: &Shard{ID: (owner + 1) % 3, Count: 3},
+		},
+		{
+			name:      "Verify that the shard label is removed once the operator runs unsharded",
+			labels:    map[string]string{mcpserverv1.ShardLabel: "1"},
+			wantOwned: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeScheme := newAutoscalingScheme(t)
+			cr := &mcpserverv1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace, Labels: tt.labels},
+				Spec:       mcpserverv1.MCPServerSpec{Image: "quay.io/example/tools:1"},
+			}
+			cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithStatusSubresource(&mcpserverv1.MCPServer{}).
+				WithObjects(cr).Build()
+			r := &MCPServerReconciler{
+				Client:   cli,
+				Scheme:   fakeScheme,
+				Platform: &cluster.Platform{Name: cluster.Kubernetes},
+				Shard:    tt.shard,
+			}
+
+			if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key}); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+
+			deployment := &appsv1.Deployment{}
+			err := cli.Get(context.Background(), client.ObjectKey{Name: resourceName(cr), Namespace: key.Namespace},
+				deployment)
+			if owned := err == nil; owned != tt.wantOwned {
+				t.Errorf("Deployment created = %v, want %v", owned, tt.wantOwned)
+			}
+			if err := cli.Get(context.Background(), key, cr); err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if got := cr.Labels[mcpserverv1.ShardLabel]; got != tt.wantLabel {
+				t.Errorf("shard label = %q, want %q", got, tt.wantLabel)
+			}
+			if (cr.Status.Shard == nil) != (tt.wantShard == nil) ||
+				(cr.Status.Shard != nil && *cr.Status.Shard != *tt.wantShard) {
+				t.Errorf("status.shard = %v, want %v", cr.Status.Shard, tt.wantShard)
+			}
+		})
+	}
+}
@@ -0,0 +1,433 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	routev1 "github.com/openshift/api/route/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/internal/sharedhost"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster/gvk"
+)
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=create;get;update;patch
+
+const (
+	// SharedHostName is the name of the router Deployment, Service and Route or HTTPRoute of the shared host in
+	// the namespace of the operator.
+	SharedHostName = "mcp-shared-host"
+	// sharedHostRoutesName is the name of the ConfigMap that holds the routes of the shared host router.
+	sharedHostRoutesName = "mcp-shared-host-routes"
+	// sharedHostRoutesKey is the key of the routes in the ConfigMap, a file of the router.
+	sharedHostRoutesKey = "routes.json"
+	// sharedHostRoutesPath is where the ConfigMap is mounted in the router.
+	sharedHostRoutesPath = "/etc/shared-host"
+	// sharedHostLabelKey selects the pods of the shared host router.
+	sharedHostLabelKey = "mcpserver.opendatahub.io/shared-host"
+	// sharedHostReplicas is the number of pods of the shared host router, which all MCP servers on the shared host
+	// depend on.
+	sharedHostReplicas = 2
+
+	// ReasonSharedHostNotConfigured is set on the condition of a SharedHost exposure when the operator is not
+	// configured with a shared host.
+	ReasonSharedHostNotConfigured = "SharedHostNotConfigured"
+	// ReasonSharedHostRouterNotReady is set on the condition of a SharedHost exposure while the shared host router
+	// has no available pod.
+	ReasonSharedHostRouterNotReady = "SharedHostRouterNotReady"
+	// ReasonSharedHostReady is set on the condition of a SharedHost exposure once the shared host router is
+	// available.
+	ReasonSharedHostReady = "SharedHostReady"
+)
+
+// sharedHostRequest is the request of the shared host, which every change of an MCPServer enqueues.
+var sharedHostRequest = reconcile.Request{NamespacedName: types.NamespacedName{Name: SharedHostName}}
+
+// usesSharedHost reports whether cr has a SharedHost exposure.
+func usesSharedHost(cr *mcpserverv1.MCPServer) bool {
+	return slices.ContainsFunc(cr.Spec.Exposures, func(exposure mcpserverv1.Exposure) bool {
+		return exposure.Type == mcpserverv1.ExposureSharedHost
+	})
+}
+
+// sharedHostURL returns the URL of the MCP endpoint of cr on host, which is assumed to terminate TLS like the
+// edge terminated Route of the shared host.
+func sharedHostURL(cr *mcpserverv1.MCPServer, host string) string {
+	return fmt.Sprintf("https://%s%s%s", host, sharedhost.Path(cr.Namespace, cr.Name), mcpServerPath(cr))
+}
+
+// SharedHostReconciler publishes all MCP servers with a SharedHost exposure under their path of one host, through
+// a router Deployment in the namespace of the operator behind a single Route, or an HTTPRoute attached to Gateway.
+// The routes of the router are kept in a ConfigMap it reads again when it changes.
+type SharedHostReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// Host is the shared host, e.g. mcp.apps.example.com.
+	Host string
+	// Namespace is the namespace of the operator, which the router runs in.
+	Namespace string
+	// Gateway is the Gateway the HTTPRoute of the shared host attaches to. A Route is created when nil.
+	Gateway *types.NamespacedName
+	// OperatorImage runs the router.
+	OperatorImage string
+	// Platform tells whether the cluster serves Routes.
+	Platform *cluster.Platform
+}
+
+// Reconcile writes the routes of the MCPServers with a SharedHost exposure to the ConfigMap of the router, and
+// creates or updates the router and its Service and Route or HTTPRoute.
+func (r *SharedHostReconciler) Reconcile(ctx context.Context, _ ctrl.Request) (ctrl.Result, error) {
+	if r.OperatorImage == "" {
+		return ctrl.Result{}, fmt.Errorf("the operator image is not configured, unable to deploy the shared host router")
+	}
+	servers := &mcpserverv1.MCPServerList{}
+	if err := r.List(ctx, servers); err != nil {
+		return ctrl.Result{}, err
+	}
+	routes, timeout := sharedHostRoutes(servers.Items)
+	if err := r.reconcileRoutes(ctx, routes); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.reconcileRouter(ctx); err != nil {
+		return ctrl.Result{}, err
+	}
+	if r.Gateway != nil {
+		return ctrl.Result{}, r.reconcileHTTPRoute(ctx)
+	}
+	if r.Platform != nil && !r.Platform.HasAPI(gvk.Route) {
+		return ctrl.Result{}, fmt.Errorf("the cluster does not serve Routes, configure a Gateway for the shared host")
+	}
+	return ctrl.Result{}, r.reconcileRoute(ctx, timeout)
+}
+
+// sharedHostRoutes returns the routes of the servers with a SharedHost exposure, from their path to their
+// Service, and the longest spec.sse.idleTimeout among them, which the Route of the shared host keeps idle event
+// streams open for. Templates and MCPServers being deleted are not routed.
+func sharedHostRoutes(servers []mcpserverv1.MCPServer) (map[string]string, time.Duration) {
+	routes := map[string]string{}
+	var timeout time.Duration
+	for i := range servers {
+		cr := &servers[i]
+		if !usesSharedHost(cr) || isExternal(cr) || isTemplate(cr) || !cr.DeletionTimestamp.IsZero() {
+			continue
+		}
+		routes[sharedhost.Path(cr.Namespace, cr.Name)] = fmt.Sprintf("http://%s.%s.svc:%d", resourceName(cr),
+			cr.Namespace, 8000)
+		if cr.Spec.SSE != nil && cr.Spec.SSE.IdleTimeout != nil {
+			timeout = max(timeout, cr.Spec.SSE.IdleTimeout.Duration)
+		}
+	}
+	return routes, timeout
+}
+
+// reconcileRoutes writes routes to the ConfigMap of the router.
+func (r *SharedHostReconciler) reconcileRoutes(ctx context.Context, routes map[string]string) error {
+	// The keys of a map are marshaled in sorted order, so the same routes give the same data.
+	data, err := json.MarshalIndent(routes, "", "  ")
+	if err != nil {
+		return err
+	}
+	configMap := &corev1.ConfigMap{}
+	err = r.Get(ctx, client.ObjectKey{Name: sharedHostRoutesName, Namespace: r.Namespace}, configMap)
+	if k8serr.IsNotFound(err) {
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      sharedHostRoutesName,
+				Namespace: r.Namespace,
+				Labels:    r.labels(),
+			},
+			Data: map[string]string{sharedHostRoutesKey: string(data)},
+		}
+		return r.Create(ctx, configMap)
+	}
+	if err != nil {
+		return err
+	}
+	if configMap.Data[sharedHostRoutesKey] == string(data) {
+		return nil
+	}
+	original := configMap.DeepCopy()
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	configMap.Data[sharedHostRoutesKey] = string(data)
+	return r.Patch(ctx, configMap, client.MergeFrom(original))
+}
+
+func (r *SharedHostReconciler) labels() map[string]string {
+	return map[string]string{sharedHostLabelKey: SharedHostName}
+}
+
+// router returns the Deployment of the shared host router, which reads its routes from the mounted ConfigMap.
+func (r *SharedHostReconciler) router() *appsv1.Deployment {
+	labels := r.labels()
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      SharedHostName,
+			Namespace: r.Namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To(int32(sharedHostReplicas)),
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					AutomountServiceAccountToken: ptr.To(false),
+					Containers: []corev1.Container{{
+						Name:    "router",
+						Image:   r.OperatorImage,
+						Command: []string{"/manager", sharedhost.Command},
+						Args: []string{
+							"--routes", sharedHostRoutesPath + "/" + sharedHostRoutesKey,
+							"--port", "8000",
+						},
+						Ports: []corev1.ContainerPort{{
+							ContainerPort: 8000,
+							Name:          "http",
+						}},
+						ReadinessProbe: &corev1.Probe{
+							ProbeHandler: corev1.ProbeHandler{
+								TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromString("http")},
+							},
+						},
+						SecurityContext: &corev1.SecurityContext{
+							AllowPrivilegeEscalation: ptr.To(false),
+							Capabilities: &corev1.Capabilities{
+								Drop: []corev1.Capability{"ALL"},
+							},
+						},
+						VolumeMounts: []corev1.VolumeMount{{
+							Name:      "routes",
+							MountPath: sharedHostRoutesPath,
+							ReadOnly:  true,
+						}},
+					}},
+					Volumes: []corev1.Volume{{
+						Name: "routes",
+						VolumeSource: corev1.VolumeSource{
+							ConfigMap: &corev1.ConfigMapVolumeSource{
+								LocalObjectReference: corev1.LocalObjectReference{Name: sharedHostRoutesName},
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// reconcileRouter creates the Deployment and Service of the shared host router, and keeps the image of the
+// router in line with the operator. The routes are not part of the Deployment, so the router is not rolled out
+// again when they change.
+func (r *SharedHostReconciler) reconcileRouter(ctx context.Context) error {
+	desired := r.router()
+	if err := r.Create(ctx, desired); err != nil && !k8serr.IsAlreadyExists(err) {
+		return err
+	}
+	existing := &appsv1.Deployment{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(desired), existing); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if len(existing.Spec.Template.Spec.Containers) > 0 {
+		original := existing.DeepCopy()
+		container := &existing.Spec.Template.Spec.Containers[0]
+		container.Image = desired.Spec.Template.Spec.Containers[0].Image
+		container.Args = desired.Spec.Template.Spec.Containers[0].Args
+		if !equality.Semantic.DeepEqual(original.Spec, existing.Spec) {
+			logChildDiff(ctx, original, existing)
+			if err := r.Patch(ctx, existing, client.MergeFrom(original)); err != nil {
+				return err
+			}
+		}
+	}
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      SharedHostName,
+			Namespace: r.Namespace,
+			Labels:    desired.Labels,
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: desired.Spec.Selector.MatchLabels,
+			Ports: []corev1.ServicePort{{
+				Name:       "http",
+				Port:       8000,
+				TargetPort: intstr.FromString("http"),
+			}},
+		},
+	}
+	if err := r.Create(ctx, service); err != nil && !k8serr.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// reconcileRoute creates the Route of the shared host, and keeps its host, backend and timeout in line. The Route
+// is edge terminated and redirects plain HTTP, a TLS configuration set by hand, e.g. with a certificate for the
+// host, is kept.
+func (r *SharedHostReconciler) reconcileRoute(ctx context.Context, timeout time.Duration) error {
+	desired := &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      SharedHostName,
+			Namespace: r.Namespace,
+			Labels:    r.labels(),
+		},
+		Spec: routev1.RouteSpec{
+			Host: r.Host,
+			To: routev1.RouteTargetReference{
+				Kind: "Service",
+				Name: SharedHostName,
+			},
+			Port: &routev1.RoutePort{TargetPort: intstr.FromString("http")},
+			TLS: &routev1.TLSConfig{
+				Termination:                   routev1.TLSTerminationEdge,
+				InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyRedirect,
+			},
+		},
+	}
+	if timeout > 0 {
+		metav1.SetMetaDataAnnotation(&desired.ObjectMeta, routeTimeoutAnnotation,
+			fmt.Sprintf("%ds", max(int64(timeout.Seconds()), 1)))
+	}
+	if err := r.Create(ctx, desired.DeepCopy()); err != nil && !k8serr.IsAlreadyExists(err) {
+		return err
+	}
+
+	route := &routev1.Route{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(desired), route); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	original := route.DeepCopy()
+	route.Spec.Host = desired.Spec.Host
+	route.Spec.To = desired.Spec.To
+	route.Spec.Port = desired.Spec.Port
+	if route.Spec.TLS == nil {
+		route.Spec.TLS = desired.Spec.TLS
+	}
+	if value, ok := desired.Annotations[routeTimeoutAnnotation]; ok {
+		metav1.SetMetaDataAnnotation(&route.ObjectMeta, routeTimeoutAnnotation, value)
+	} else {
+		delete(route.Annotations, routeTimeoutAnnotation)
+	}
+	if equality.Semantic.DeepEqual(original.Spec, route.Spec) &&
+		equality.Semantic.DeepEqual(original.Annotations, route.Annotations) {
+		return nil
+	}
+	logChildDiff(ctx, original, route)
+	return r.Patch(ctx, route, client.MergeFrom(original))
+}
+
+// reconcileHTTPRoute creates the HTTPRoute of the shared host, which attaches to Gateway and routes every path of
+// the host to the router, and keeps its spec in line.
+func (r *SharedHostReconciler) reconcileHTTPRoute(ctx context.Context) error {
+	desired := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      SharedHostName,
+			Namespace: r.Namespace,
+			Labels:    r.labels(),
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: []gatewayv1.ParentReference{{
+					Group:     ptr.To(gatewayv1.Group(gatewayv1.GroupName)),
+					Kind:      ptr.To(gatewayv1.Kind("Gateway")),
+					Namespace: ptr.To(gatewayv1.Namespace(r.Gateway.Namespace)),
+					Name:      gatewayv1.ObjectName(r.Gateway.Name),
+				}},
+			},
+			Hostnames: []gatewayv1.Hostname{gatewayv1.Hostname(r.Host)},
+			Rules: []gatewayv1.HTTPRouteRule{{
+				BackendRefs: []gatewayv1.HTTPBackendRef{{
+					BackendRef: gatewayv1.BackendRef{
+						BackendObjectReference: gatewayv1.BackendObjectReference{
+							Name: gatewayv1.ObjectName(SharedHostName),
+							Port: ptr.To(gatewayv1.PortNumber(8000)),
+						},
+					},
+				}},
+			}},
+		},
+	}
+	if err := r.Create(ctx, desired.DeepCopy()); err != nil && !k8serr.IsAlreadyExists(err) {
+		return err
+	}
+
+	httpRoute := &gatewayv1.HTTPRoute{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(desired), httpRoute); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	original := httpRoute.DeepCopy()
+	httpRoute.Spec.ParentRefs = desired.Spec.ParentRefs
+	httpRoute.Spec.Hostnames = desired.Spec.Hostnames
+	if len(httpRoute.Spec.Rules) != 1 || !equality.Semantic.DeepEqual(httpRoute.Spec.Rules[0].BackendRefs,
+		desired.Spec.Rules[0].BackendRefs) {
+		httpRoute.Spec.Rules = desired.Spec.Rules
+	}
+	if equality.Semantic.DeepEqual(original.Spec, httpRoute.Spec) {
+		return nil
+	}
+	logChildDiff(ctx, original, httpRoute)
+	return r.Patch(ctx, httpRoute, client.MergeFrom(original))
+}
+
+// SetupWithManager sets up the controller with the Manager. Every change of an MCPServer updates the routes.
+func (r *SharedHostReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Watches(&mcpserverv1.MCPServer{}, handler.EnqueueRequestsFromMapFunc(
+			func(context.Context, client.Object) []reconcile.Request {
+				return []reconcile.Request{sharedHostRequest}
+			})).
+		Named("sharedhost").
+		Complete(r)
+}
+
+// getSharedHostCondition returns the condition of a SharedHost exposure of cr: it is available once the operator
+// has a shared host and its router has an available pod.
+func (r *MCPServerReconciler) getSharedHostCondition(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer,
+	condition metav1.Condition) metav1.Condition {
+	if r.SharedHost == "" || r.OperatorNamespace == "" {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonSharedHostNotConfigured
+		condition.Message = "The operator is not configured with a shared host, see its --shared-host flag"
+		return condition
+	}
+	router := &appsv1.Deployment{}
+	err := cli.Get(ctx, client.ObjectKey{Name: SharedHostName, Namespace: r.OperatorNamespace}, router)
+	if err != nil && !k8serr.IsNotFound(err) {
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = "Deployment" + ReasonGetFailedSuffix
+		condition.Message = fmt.Sprintf("Failed to get the shared host router %s: %v", SharedHostName, err)
+		return condition
+	}
+	if err != nil || router.Status.AvailableReplicas == 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonSharedHostRouterNotReady
+		condition.Message = fmt.Sprintf("Shared host router %s in namespace %s has no available pod", SharedHostName,
+			r.OperatorNamespace)
+		return condition
+	}
+	condition.Status = metav1.ConditionTrue
+	condition.Reason = ReasonSharedHostReady
+	condition.Message = fmt.Sprintf("Published on the shared host at %s", sharedHostURL(cr, r.SharedHost))
+	return condition
+}
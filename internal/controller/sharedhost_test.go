@@ -0,0 +1,294 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	routev1 "github.com/openshift/api/route/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster"
+)
+
+const operatorNamespace = "mcp-operator"
+
+// newSharedHostMCPServer returns an MCPServer named name in namespace with a SharedHost exposure.
+func newSharedHostMCPServer(namespace, name string) *mcpserverv1.MCPServer {
+	return &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: mcpserverv1.MCPServerSpec{
+			Image:     mcpServerImage,
+			Exposures: []mcpserverv1.Exposure{{Name: "shared", Type: mcpserverv1.ExposureSharedHost}},
+		},
+	}
+}
+
+func Test_sharedHostRoutes(t *testing.T) {
+	tools := newSharedHostMCPServer("team-a", "tools")
+	search := newSharedHostMCPServer("team-b", "search")
+	search.Spec.SSE = &mcpserverv1.SSE{IdleTimeout: &metav1.Duration{Duration: 10 * time.Minute}}
+	private := newSharedHostMCPServer("team-a", "private")
+	private.Spec.Exposures = nil
+	deleting := newSharedHostMCPServer("team-a", "deleting")
+	deleting.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	deleting.Finalizers = []string{"test"}
+
+	routes, timeout := sharedHostRoutes([]mcpserverv1.MCPServer{*tools, *search, *private, *deleting})
+	want := map[string]string{
+		"/mcp/team-a/tools":  "http://tools.team-a.svc:8000",
+		"/mcp/team-b/search": "http://search.team-b.svc:8000",
+	}
+	if !reflect.DeepEqual(routes, want) {
+		t.Errorf("sharedHostRoutes() routes = %v, want %v", routes, want)
+	}
+	if timeout != 10*time.Minute {
+		t.Errorf("sharedHostRoutes() timeout = %v, want 10m", timeout)
+	}
+}
+
+func TestSharedHostReconciler_Reconcile(t *testing.T) {
+	fakeScheme := newAutoscalingScheme(t)
+	if err := routev1.AddToScheme(fakeScheme); err != nil {
+		t.Fatalf("failed to add route scheme: %v", err)
+	}
+	if err := gatewayv1.Install(fakeScheme); err != nil {
+		t.Fatalf("failed to add gateway scheme: %v", err)
+	}
+	tools := newSharedHostMCPServer("team-a", "tools")
+	tools.Spec.SSE = &mcpserverv1.SSE{IdleTimeout: &metav1.Duration{Duration: 5 * time.Minute}}
+
+	tests := []struct {
+		name    string
+		gateway *types.NamespacedName
+	}{
+		{name: "Verify that the shared host is published through a Route"},
+		{
+			name:    "Verify that the shared host is published through an HTTPRoute when a Gateway is configured",
+			gateway: &types.NamespacedName{Namespace: "gateways", Name: "public"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().WithScheme(fakeScheme).
+				WithObjects(tools.DeepCopy(), newSharedHostMCPServer("team-b", "search")).Build()
+			r := &SharedHostReconciler{
+				Client:        cli,
+				Scheme:        fakeScheme,
+				Host:          "mcp.apps.example.com",
+				Namespace:     operatorNamespace,
+				Gateway:       tt.gateway,
+				OperatorImage: "quay.io/mcp/operator:1.2.0",
+			}
+			ctx := context.Background()
+			reconcile := func() {
+				t.Helper()
+				if _, err := r.Reconcile(ctx, sharedHostRequest); err != nil {
+					t.Fatalf("Reconcile() error = %v", err)
+				}
+			}
+			routes := func() map[string]string {
+				t.Helper()
+				configMap := &corev1.ConfigMap{}
+				key := client.ObjectKey{Name: sharedHostRoutesName, Namespace: operatorNamespace}
+				if err := cli.Get(ctx, key, configMap); err != nil {
+					t.Fatalf("Get() ConfigMap error = %v", err)
+				}
+				routes := map[string]string{}
+				if err := json.Unmarshal([]byte(configMap.Data[sharedHostRoutesKey]), &routes); err != nil {
+					t.Fatalf("Unmarshal() routes error = %v", err)
+				}
+				return routes
+			}
+
+			reconcile()
+			want := map[string]string{
+				"/mcp/team-a/tools":  "http://tools.team-a.svc:8000",
+				"/mcp/team-b/search": "http://search.team-b.svc:8000",
+			}
+			if got := routes(); !reflect.DeepEqual(got, want) {
+				t.Errorf("routes = %v, want %v", got, want)
+			}
+			key := client.ObjectKey{Name: SharedHostName, Namespace: operatorNamespace}
+			router := &appsv1.Deployment{}
+			if err := cli.Get(ctx, key, router); err != nil {
+				t.Fatalf("Get() Deployment error = %v", err)
+			}
+			container := router.Spec.Template.Spec.Containers[0]
+			if container.Image != r.OperatorImage || container.Command[1] != "shared-host-router" {
+				t.Errorf("router container = %s %v, want the operator image running shared-host-router",
+					container.Image, container.Command)
+			}
+			if err := cli.Get(ctx, key, &corev1.Service{}); err != nil {
+				t.Errorf("Get() Service error = %v", err)
+			}
+			if tt.gateway == nil {
+				route := &routev1.Route{}
+				if err := cli.Get(ctx, key, route); err != nil {
+					t.Fatalf("Get() Route error = %v", err)
+				}
+				if route.Spec.Host != r.Host || route.Spec.To.Name != SharedHostName {
+					t.Errorf("Route spec = %+v, want host %s to Service %s", route.Spec, r.Host, SharedHostName)
+				}
+				if got := route.Annotations[routeTimeoutAnnotation]; got != "300s" {
+					t.Errorf("Route timeout = %q, want 300s", got)
+				}
+			} else {
+				httpRoute := &gatewayv1.HTTPRoute{}
+				if err := cli.Get(ctx, key, httpRoute); err != nil {
+					t.Fatalf("Get() HTTPRoute error = %v", err)
+				}
+				if parent := httpRoute.Spec.ParentRefs[0]; string(parent.Name) != tt.gateway.Name ||
+					string(*parent.Namespace) != tt.gateway.Namespace {
+					t.Errorf("HTTPRoute parent = %+v, want Gateway %s", parent, tt.gateway)
+				}
+				if err := cli.Get(ctx, key, &routev1.Route{}); err == nil {
+					t.Errorf("Route created next to the HTTPRoute")
+				}
+			}
+
+			// Removing the exposure of an MCPServer removes its route and keeps the router as it is.
+			cr := &mcpserverv1.MCPServer{}
+			if err := cli.Get(ctx, client.ObjectKey{Name: "tools", Namespace: "team-a"}, cr); err != nil {
+				t.Fatalf("Get() MCPServer error = %v", err)
+			}
+			cr.Spec.Exposures = nil
+			if err := cli.Update(ctx, cr); err != nil {
+				t.Fatalf("Update() MCPServer error = %v", err)
+			}
+			reconcile()
+			if got := routes(); !reflect.DeepEqual(got, map[string]string{"/mcp/team-b/search": "http://search.team-b.svc:8000"}) {
+				t.Errorf("routes = %v, want only team-b/search", got)
+			}
+			updated := &appsv1.Deployment{}
+			if err := cli.Get(ctx, key, updated); err != nil {
+				t.Fatalf("Get() Deployment error = %v", err)
+			}
+			if updated.Generation != router.Generation || updated.ResourceVersion != router.ResourceVersion {
+				t.Errorf("router Deployment changed with the routes")
+			}
+		})
+	}
+}
+
+func TestSharedHostReconciler_Reconcile_noRouteAPI(t *testing.T) {
+	fakeScheme := newAutoscalingScheme(t)
+	cli := fake.NewClientBuilder().WithScheme(fakeScheme).Build()
+	r := &SharedHostReconciler{
+		Client:        cli,
+		Scheme:        fakeScheme,
+		Host:          "mcp.example.com",
+		Namespace:     operatorNamespace,
+		OperatorImage: "quay.io/mcp/operator:1.2.0",
+		Platform:      &cluster.Platform{Name: cluster.Kubernetes},
+	}
+	if _, err := r.Reconcile(context.Background(), sharedHostRequest); err == nil {
+		t.Errorf("Reconcile() error = nil, want an error without the Route API and a Gateway")
+	}
+}
+
+func TestMCPServerReconciler_getSharedHostCondition(t *testing.T) {
+	fakeScheme := newAutoscalingScheme(t)
+	router := func(available int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: SharedHostName, Namespace: operatorNamespace},
+			Status:     appsv1.DeploymentStatus{AvailableReplicas: available},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		sharedHost  string
+		objects     []client.Object
+		wantStatus  metav1.ConditionStatus
+		wantReason  string
+		wantMessage string
+	}{
+		{
+			name:       "Verify that the exposure is not available without a shared host",
+			wantStatus: metav1.ConditionFalse,
+			wantReason: ReasonSharedHostNotConfigured,
+		},
+		{
+			name:       "Verify that the exposure is not available without the router",
+			sharedHost: "mcp.apps.example.com",
+			wantStatus: metav1.ConditionFalse,
+			wantReason: ReasonSharedHostRouterNotReady,
+		},
+		{
+			name:       "Verify that the exposure is not available while the router has no available pod",
+			sharedHost: "mcp.apps.example.com",
+			objects:    []client.Object{router(0)},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: ReasonSharedHostRouterNotReady,
+		},
+		{
+			name:        "Verify that the exposure is available with its URL once the router is",
+			sharedHost:  "mcp.apps.example.com",
+			objects:     []client.Object{router(2)},
+			wantStatus:  metav1.ConditionTrue,
+			wantReason:  ReasonSharedHostReady,
+			wantMessage: "Published on the shared host at https://mcp.apps.example.com/mcp/" + testNamespace + "/" + mcpServerName + "/sse",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(tt.objects...).Build()
+			r := &MCPServerReconciler{Client: cli, Scheme: fakeScheme, SharedHost: tt.sharedHost, OperatorNamespace: operatorNamespace}
+			cr := newSharedHostMCPServer(testNamespace, mcpServerName)
+			got := r.getExposureCondition(context.Background(), cli, cr, cr.Spec.Exposures[0])
+			if got.Type != "ExposureAvailable-shared" || got.Status != tt.wantStatus || got.Reason != tt.wantReason {
+				t.Errorf("getExposureCondition() = %+v, want status %s and reason %s", got, tt.wantStatus, tt.wantReason)
+			}
+			if tt.wantMessage != "" && got.Message != tt.wantMessage {
+				t.Errorf("getExposureCondition() message = %q, want %q", got.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_Reconcile_sharedHost(t *testing.T) {
+	fakeScheme := newAutoscalingScheme(t)
+	cr := newSharedHostMCPServer(testNamespace, mcpServerName)
+	cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithStatusSubresource(&mcpserverv1.MCPServer{}).
+		WithObjects(cr).Build()
+	r := &MCPServerReconciler{
+		Client:            cli,
+		Scheme:            fakeScheme,
+		Platform:          &cluster.Platform{Name: cluster.Kubernetes},
+		SharedHost:        "mcp.apps.example.com",
+		OperatorNamespace: operatorNamespace,
+	}
+	ctx := context.Background()
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cr)}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(cr), cr); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	want := "https://mcp.apps.example.com/mcp/" + testNamespace + "/" + mcpServerName + "/sse"
+	found := false
+	for _, endpoint := range cr.Status.Endpoints {
+		if endpoint.Type == mcpserverv1.EndpointSharedHost {
+			found = endpoint.URL == want
+		}
+	}
+	if !found {
+		t.Errorf("status.endpoints = %+v, want a SharedHost endpoint %s", cr.Status.Endpoints, want)
+	}
+	for _, component := range cr.Status.Components {
+		if component.Kind == string(mcpserverv1.ExposureSharedHost) {
+			t.Errorf("status.components = %+v, want no component for the SharedHost exposure", cr.Status.Components)
+		}
+	}
+}
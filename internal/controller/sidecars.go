@@ -0,0 +1,221 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+const (
+	// mcpServerPortName, guardrailsPortName, capabilityFilterPortName, metricsExporterPortName and
+	// tokenAuthPortName name the http port of their container while a sidecar in front of it takes over the http
+	// port the Service and Route target.
+	mcpServerPortName        = "mcp"
+	guardrailsPortName       = "guardrails"
+	capabilityFilterPortName = "capabilities"
+	metricsExporterPortName  = "exporter"
+	tokenAuthPortName        = "token-auth"
+)
+
+// innerPortNames maps the containers that sidecars run in front of to the name of their http port while they do.
+var innerPortNames = map[string]string{
+	"mcp-server":                  mcpServerPortName,
+	guardrailsContainerName:       guardrailsPortName,
+	capabilityFilterContainerName: capabilityFilterPortName,
+	metricsExporterContainerName:  metricsExporterPortName,
+	tokenAuthContainerName:        tokenAuthPortName,
+}
+
+// withSidecars returns containers with the sidecars of cr that run in front of the MCP server, in the order
+// the traffic passes them: the rate limiter, the token authentication, the metrics exporter, the capability
+// filter, then the guardrails filter. They are applied from the innermost outwards. The REST bridge, which calls
+// the MCP server through all of them, comes last.
+func (r *MCPServerReconciler) withSidecars(cr *mcpserverv1.MCPServer, containers []corev1.Container) []corev1.Container {
+	containers = withGuardrails(containers, r.guardrailsContainer(cr))
+	containers = withCapabilityFilter(containers, r.capabilityFilterContainer(cr))
+	containers = withMetricsExporter(containers, r.metricsExporterContainer(cr))
+	containers = withTokenAuth(containers, r.tokenAuthContainer(cr))
+	containers = withRateLimiter(containers, r.rateLimiterContainer(cr))
+	return withRESTBridge(containers, r.restBridgeContainer(cr))
+}
+
+// sidecarHop is a container on the path of the traffic to the MCP server, with the port it listens on.
+type sidecarHop struct {
+	name string
+	port int
+}
+
+// sidecarChain returns the containers the traffic to the MCP server pods of cr passes, in the order withSidecars
+// documents: the sidecars that cr runs in front of the MCP server, then the MCP server itself.
+func sidecarChain(cr *mcpserverv1.MCPServer) []sidecarHop {
+	var chain []sidecarHop
+	if cr.Spec.RateLimit != nil && cr.Spec.RateLimit.Local != nil {
+		chain = append(chain, sidecarHop{rateLimiterContainerName, rateLimiterPort})
+	}
+	if usesTokenAuth(cr) {
+		chain = append(chain, sidecarHop{tokenAuthContainerName, tokenAuthPort})
+	}
+	if cr.Spec.MetricsExporter != nil {
+		chain = append(chain, sidecarHop{metricsExporterContainerName, metricsExporterPort})
+	}
+	if len(disabledCapabilities(cr)) > 0 {
+		chain = append(chain, sidecarHop{capabilityFilterContainerName, capabilityFilterPort})
+	}
+	if cr.Spec.Guardrails != nil {
+		chain = append(chain, sidecarHop{guardrailsContainerName, guardrailsPort})
+	}
+	return append(chain, sidecarHop{"mcp-server", mcpServerPort})
+}
+
+// httpPort returns the port of the container that owns the http port of the MCP server pods of cr: the
+// outermost of the sidecars in front of the MCP server, or the MCP server itself.
+func httpPort(cr *mcpserverv1.MCPServer) int {
+	return sidecarChain(cr)[0].port
+}
+
+// upstreamPort returns the port the sidecar named sidecar forwards to: that of the next container in the
+// sidecarChain of cr.
+func upstreamPort(cr *mcpserverv1.MCPServer, sidecar string) int {
+	chain := sidecarChain(cr)
+	for i := range chain[:len(chain)-1] {
+		if chain[i].name == sidecar {
+			return chain[i+1].port
+		}
+	}
+	return mcpServerPort
+}
+
+// withSidecar returns containers with the container named name set to sidecar, or removed when sidecar is nil.
+// behind lists the containers the sidecar may run in front of, closest first. While the sidecar runs, it owns the
+// http port and the port of the container behind it is renamed; once it is removed, the closest container behind
+// it owns the http port again. A sidecar already in containers is kept when it runs the same image, arguments,
+// environment and mounts, so that fields defaulted by the API server do not cause a rollout.
+func withSidecar(containers []corev1.Container, name string, sidecar *corev1.Container, behind ...string) []corev1.Container {
+	result := make([]corev1.Container, 0, len(containers)+1)
+	var existing *corev1.Container
+	running := map[string]bool{}
+	for i := range containers {
+		container := *containers[i].DeepCopy()
+		if container.Name == name {
+			existing = &container
+			continue
+		}
+		running[container.Name] = true
+		result = append(result, container)
+	}
+
+	var closest string
+	for _, inner := range behind {
+		if running[inner] {
+			closest = inner
+			break
+		}
+	}
+	for i := range result {
+		container := &result[i]
+		if !slices.Contains(behind, container.Name) {
+			continue
+		}
+		for j := range container.Ports {
+			switch {
+			case sidecar != nil && container.Ports[j].Name == "http":
+				container.Ports[j].Name = innerPortNames[container.Name]
+			case sidecar == nil && container.Name == closest && container.Ports[j].Name == innerPortNames[container.Name]:
+				container.Ports[j].Name = "http"
+			}
+		}
+	}
+
+	if sidecar == nil {
+		return result
+	}
+	if existing != nil && existing.Image == sidecar.Image && equality.Semantic.DeepEqual(existing.Args, sidecar.Args) &&
+		equality.Semantic.DeepEqual(existing.Env, sidecar.Env) &&
+		equality.Semantic.DeepEqual(existing.VolumeMounts, sidecar.VolumeMounts) {
+		return append(result, *existing)
+	}
+	return append(result, *sidecar)
+}
+
+// reconcileDeploymentSidecars adds, updates or removes the sidecars of an existing Deployment and their Secret
+// volumes, so that changes to spec.guardrails, spec.capabilities, spec.metricsExporter, spec.auth,
+// spec.rateLimit and spec.restBridge are enforced without recreating it.
+func (r *MCPServerReconciler) reconcileDeploymentSidecars(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) error {
+	deployment := &appsv1.Deployment{}
+	err := cli.Get(ctx, client.ObjectKey{Name: resourceName(cr), Namespace: cr.Namespace}, deployment)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	original := deployment.DeepCopy()
+	deployment.Spec.Template.Spec.Containers = r.withSidecars(cr, deployment.Spec.Template.Spec.Containers)
+	deployment.Spec.Template.Spec.Volumes = withSecretVolume(deployment.Spec.Template.Spec.Volumes,
+		guardrailsCredentialsVolumeName, guardrailsCredentials(cr).Volume)
+	deployment.Spec.Template.Spec.Volumes = withSecretVolume(deployment.Spec.Template.Spec.Volumes,
+		authTokenVolumeName, authToken(cr).Volume)
+	if equality.Semantic.DeepEqual(original.Spec.Template, deployment.Spec.Template) {
+		return nil
+	}
+	logChildDiff(ctx, original, deployment)
+	return cli.Patch(ctx, deployment, client.MergeFrom(original))
+}
+
+// getSidecarCondition returns a condition of conditionType from the readiness of the container named
+// containerName in each MCP server pod. The sidecar is named by description in messages, and hint is appended
+// to the message of a sidecar that is not ready.
+func (r *MCPServerReconciler) getSidecarCondition(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer,
+	conditionType, containerName, component, description, hint string) metav1.Condition {
+	pods, err := r.listMCPServerPods(ctx, cli, cr)
+	if err != nil {
+		return metav1.Condition{
+			Type:    conditionType,
+			Status:  metav1.ConditionUnknown,
+			Reason:  fmt.Sprintf("%s%s", "Pods", ReasonGetFailedSuffix),
+			Message: fmt.Sprintf("Failed to list the pods of %s, %v", cr.Name, err),
+		}
+	}
+
+	ready := 0
+	for _, pod := range pods {
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name != containerName {
+				continue
+			}
+			if !status.Ready {
+				return metav1.Condition{
+					Type:    conditionType,
+					Status:  metav1.ConditionFalse,
+					Reason:  fmt.Sprintf("%s%s", component, ReasonNotReadySuffix),
+					Message: fmt.Sprintf("The %s in pod %s is not ready, %s", description, pod.Name, hint),
+				}
+			}
+			ready++
+		}
+	}
+	if ready == 0 {
+		return metav1.Condition{
+			Type:    conditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  fmt.Sprintf("%s%s", component, ReasonNotReadySuffix),
+			Message: fmt.Sprintf("No pod of %s runs the %s yet", cr.Name, description),
+		}
+	}
+	return metav1.Condition{
+		Type:    conditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  fmt.Sprintf("%s%s", component, ReasonReadySuffix),
+		Message: fmt.Sprintf("The %s is ready in %d pods", description, ready),
+	}
+}
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+func Test_upstreamPort(t *testing.T) {
+	r := &MCPServerReconciler{OperatorImage: "quay.io/example/operator:latest"}
+	server := corev1.Container{
+		Name:  "mcp-server",
+		Ports: []corev1.ContainerPort{{ContainerPort: 8000, Name: "http"}},
+	}
+	// The sidecars in the order the traffic passes them, each with its port and how an MCPServer enables it.
+	sidecars := []struct {
+		name   string
+		port   int
+		enable func(cr *mcpserverv1.MCPServer)
+	}{
+		{rateLimiterContainerName, rateLimiterPort, func(cr *mcpserverv1.MCPServer) {
+			cr.Spec.RateLimit = &mcpserverv1.RateLimit{Local: &mcpserverv1.LocalRateLimit{RequestsPerSecond: 5}}
+		}},
+		{tokenAuthContainerName, tokenAuthPort, func(cr *mcpserverv1.MCPServer) {
+			cr.Spec.Auth = &mcpserverv1.Auth{Type: mcpserverv1.AuthToken}
+		}},
+		{metricsExporterContainerName, metricsExporterPort, func(cr *mcpserverv1.MCPServer) {
+			cr.Spec.MetricsExporter = &mcpserverv1.MetricsExporter{}
+		}},
+		{capabilityFilterContainerName, capabilityFilterPort, func(cr *mcpserverv1.MCPServer) {
+			cr.Spec.Capabilities = &mcpserverv1.Capabilities{Prompts: ptr.To(false)}
+		}},
+		{guardrailsContainerName, guardrailsPort, func(cr *mcpserverv1.MCPServer) {
+			cr.Spec.Guardrails = newGuardrailsMCPServer().Spec.Guardrails
+		}},
+	}
+
+	// Every combination of the sidecars must forward each of them to the next one and the last to the MCP server,
+	// so that no sidecar is bypassed.
+	for combination := range 1 << len(sidecars) {
+		cr := newGuardrailsMCPServer()
+		cr.Spec.Guardrails = nil
+		var enabled []string
+		wantUpstream := map[string]string{}
+		upstream := mcpServerPort
+		for i := len(sidecars) - 1; i >= 0; i-- {
+			if combination&(1<<i) == 0 {
+				continue
+			}
+			sidecars[i].enable(cr)
+			enabled = append(enabled, sidecars[i].name)
+			wantUpstream[sidecars[i].name] = fmt.Sprintf("http://localhost:%d", upstream)
+			upstream = sidecars[i].port
+		}
+		slices.Reverse(enabled)
+
+		t.Run(fmt.Sprintf("Verify that the sidecars %s forward along the chain", strings.Join(enabled, ", ")), func(t *testing.T) {
+			if got := httpPort(cr); got != upstream {
+				t.Errorf("httpPort() = %d, want %d", got, upstream)
+			}
+			containers := r.withSidecars(cr, []corev1.Container{server})
+			if len(containers) != len(enabled)+1 {
+				t.Fatalf("withSidecars() returned %d containers, want %d", len(containers), len(enabled)+1)
+			}
+			for _, container := range containers {
+				if container.Name == server.Name {
+					continue
+				}
+				if got := container.Args[1]; container.Args[0] != "--upstream" || got != wantUpstream[container.Name] {
+					t.Errorf("upstream of %s = %s, want %s", container.Name, got, wantUpstream[container.Name])
+				}
+			}
+		})
+	}
+}
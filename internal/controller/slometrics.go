@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+const (
+	// triggerCreate labels the time to ready of a new MCPServer, measured from its creation.
+	triggerCreate = "create"
+	// triggerUpdate labels the time to ready of a change of the spec of an MCPServer, measured from when the operator
+	// started to roll it out.
+	triggerUpdate = "update"
+)
+
+var timeToReady = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name: "mcpserver_operator_time_to_ready_seconds",
+	Help: "Time from the creation of an MCPServer, or from the rollout of a change of its spec, until it is " +
+		"Available.",
+	Buckets: []float64{5, 10, 20, 30, 45, 60, 90, 120, 180, 300, 600, 1200},
+}, []string{"type", "trigger"})
+
+func init() {
+	metrics.Registry.MustRegister(timeToReady)
+}
+
+// observeTimeToReady observes in the time to ready histogram how long cr took to become available, when its
+// Available condition turned True from originalStatus to its status: since its creation when it was never
+// available before, or since its Available condition turned Unknown for the rollout of a changed spec. Recoveries
+// from other outages are not provisioning latency and are not observed.
+func observeTimeToReady(cr *mcpserverv1.MCPServer, originalStatus *mcpserverv1.MCPServerStatus) {
+	current := meta.FindStatusCondition(cr.Status.Conditions, OverallAvailable)
+	if current == nil || current.Status != metav1.ConditionTrue {
+		return
+	}
+	previous := meta.FindStatusCondition(originalStatus.Conditions, OverallAvailable)
+	if previous != nil && previous.Status == metav1.ConditionTrue {
+		return
+	}
+
+	var trigger string
+	var start metav1.Time
+	switch {
+	case originalStatus.LastReadyTime == nil:
+		trigger, start = triggerCreate, cr.CreationTimestamp
+	case previous != nil && previous.Reason == ReasonRolloutInProgress:
+		trigger, start = triggerUpdate, previous.LastTransitionTime
+	default:
+		return
+	}
+	if start.IsZero() {
+		return
+	}
+	serverType := cr.Spec.Type
+	if serverType == "" {
+		serverType = mcpserverv1.MCPServerManaged
+	}
+	seconds := max(current.LastTransitionTime.Sub(start.Time).Seconds(), 0)
+	timeToReady.WithLabelValues(string(serverType), trigger).Observe(seconds)
+}
@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/expfmt"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+func Test_observeTimeToReady(t *testing.T) {
+	created := metav1.NewTime(time.Date(2025, 1, 2, 15, 0, 0, 0, time.UTC))
+	at := func(after time.Duration) metav1.Time { return metav1.NewTime(created.Add(after)) }
+	condition := func(status metav1.ConditionStatus, reason string, since metav1.Time) []metav1.Condition {
+		return []metav1.Condition{{Type: OverallAvailable, Status: status, Reason: reason, LastTransitionTime: since}}
+	}
+
+	tests := []struct {
+		name        string
+		original    mcpserverv1.MCPServerStatus
+		status      mcpserverv1.MCPServerStatus
+		serverType  mcpserverv1.MCPServerType
+		wantTrigger string
+		wantSeconds float64
+	}{
+		{
+			name:        "Verify that a new MCPServer is measured from its creation",
+			original:    mcpserverv1.MCPServerStatus{Conditions: condition(metav1.ConditionFalse, ReasonEndpointUnreachable, at(0))},
+			status:      mcpserverv1.MCPServerStatus{Conditions: condition(metav1.ConditionTrue, ReasonAsExpected, at(42*time.Second))},
+			wantTrigger: triggerCreate,
+			wantSeconds: 42,
+		},
+		{
+			name: "Verify that a change of the spec is measured from the start of its rollout",
+			original: mcpserverv1.MCPServerStatus{
+				Conditions:    condition(metav1.ConditionUnknown, ReasonRolloutInProgress, at(time.Hour)),
+				LastReadyTime: &created,
+			},
+			status:      mcpserverv1.MCPServerStatus{Conditions: condition(metav1.ConditionTrue, ReasonAsExpected, at(time.Hour+90*time.Second))},
+			serverType:  mcpserverv1.MCPServerProxy,
+			wantTrigger: triggerUpdate,
+			wantSeconds: 90,
+		},
+		{
+			name: "Verify that the recovery from an outage is not observed",
+			original: mcpserverv1.MCPServerStatus{
+				Conditions:    condition(metav1.ConditionFalse, ReasonEndpointUnreachable, at(time.Hour)),
+				LastReadyTime: &created,
+			},
+			status: mcpserverv1.MCPServerStatus{Conditions: condition(metav1.ConditionTrue, ReasonAsExpected, at(2*time.Hour))},
+		},
+		{
+			name:     "Verify that an MCPServer that stays available is not observed",
+			original: mcpserverv1.MCPServerStatus{Conditions: condition(metav1.ConditionTrue, ReasonAsExpected, at(0))},
+			status:   mcpserverv1.MCPServerStatus{Conditions: condition(metav1.ConditionTrue, ReasonAsExpected, at(0))},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeToReady.Reset()
+			cr := &mcpserverv1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace, CreationTimestamp: created},
+				Spec:       mcpserverv1.MCPServerSpec{Type: tt.serverType},
+				Status:     tt.status,
+			}
+
+			observeTimeToReady(cr, &tt.original)
+
+			if tt.wantTrigger == "" {
+				if n := testutil.CollectAndCount(timeToReady); n != 0 {
+					t.Errorf("observeTimeToReady() observed %d series, want none", n)
+				}
+				return
+			}
+			serverType := tt.serverType
+			if serverType == "" {
+				serverType = mcpserverv1.MCPServerManaged
+			}
+			want := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+				Name:    "mcpserver_operator_time_to_ready_seconds",
+				Help:    "Time from the creation of an MCPServer, or from the rollout of a change of its spec, until it is Available.",
+				Buckets: []float64{5, 10, 20, 30, 45, 60, 90, 120, 180, 300, 600, 1200},
+			}, []string{"type", "trigger"})
+			want.WithLabelValues(string(serverType), tt.wantTrigger).Observe(tt.wantSeconds)
+			registry := prometheus.NewPedanticRegistry()
+			registry.MustRegister(want)
+			families, err := registry.Gather()
+			if err != nil {
+				t.Fatalf("Gather() error = %v", err)
+			}
+			expected := &bytes.Buffer{}
+			for _, family := range families {
+				if _, err := expfmt.MetricFamilyToText(expected, family); err != nil {
+					t.Fatalf("MetricFamilyToText() error = %v", err)
+				}
+			}
+			if err := testutil.CollectAndCompare(timeToReady, expected); err != nil {
+				t.Errorf("observeTimeToReady() observed unexpected time to ready: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"strconv"
+	"time"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// routeTimeoutAnnotation sets how long the OpenShift router keeps an idle connection to the backend of a Route
+// open. Event streams without traffic for longer are closed by the router.
+const routeTimeoutAnnotation = "haproxy.router.openshift.io/timeout"
+
+// sseKeepAliveInterval returns spec.sse.keepAliveInterval of cr, or zero when it is unset or not positive.
+func sseKeepAliveInterval(cr *mcpserverv1.MCPServer) time.Duration {
+	if cr.Spec.SSE == nil || cr.Spec.SSE.KeepAliveInterval == nil || cr.Spec.SSE.KeepAliveInterval.Duration <= 0 {
+		return 0
+	}
+	return cr.Spec.SSE.KeepAliveInterval.Duration
+}
+
+// withSSEKeepAlive returns args with spec.sse.keepAliveFlag set to the keep-alive interval in seconds, or args
+// unchanged when either is unset.
+func withSSEKeepAlive(args []string, cr *mcpserverv1.MCPServer) []string {
+	interval := sseKeepAliveInterval(cr)
+	if interval == 0 || cr.Spec.SSE.KeepAliveFlag == "" {
+		return args
+	}
+	seconds := max(int64(interval.Seconds()), 1)
+	return withFlag(args, configParameter{Type: configInteger, Flag: cr.Spec.SSE.KeepAliveFlag},
+		strconv.FormatInt(seconds, 10))
+}
+
+// routeTimeout returns the value of the timeout annotation of the Route of cr, in seconds, or an empty string
+// when spec.sse.idleTimeout is unset.
+func routeTimeout(cr *mcpserverv1.MCPServer) string {
+	if cr.Spec.SSE == nil || cr.Spec.SSE.IdleTimeout == nil || cr.Spec.SSE.IdleTimeout.Duration <= 0 {
+		return ""
+	}
+	return strconv.FormatInt(max(int64(cr.Spec.SSE.IdleTimeout.Seconds()), 1), 10) + "s"
+}
@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+const (
+	// ReconcileStalled is set when the reconciliation of an MCPServer failed StallThreshold times in a row. The
+	// MCPServer is then only retried after a cool-down, or when it changes.
+	ReconcileStalled = "ReconcileStalled"
+
+	// ReasonReconcileFailing is set on the ReconcileStalled condition and used as the reason of the emitted
+	// Warning event.
+	ReasonReconcileFailing = "ReconcileFailing"
+
+	// DefaultStallThreshold is the number of consecutive failures after which an MCPServer is stalled.
+	DefaultStallThreshold = 5
+)
+
+var (
+	// stallCooldown is the cool-down after the first stalled failure, doubled after each further failure.
+	stallCooldown = 30 * time.Second
+	// maxStallCooldown bounds the cool-down of a stalled MCPServer.
+	maxStallCooldown = 30 * time.Minute
+)
+
+// failureTracker counts the consecutive failed reconciliations of each MCPServer. Its zero value is ready to use.
+type failureTracker struct {
+	mu       sync.Mutex
+	failures map[types.NamespacedName]int
+}
+
+// fail records a failed reconciliation of key and returns the number of consecutive failures.
+func (t *failureTracker) fail(key types.NamespacedName) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.failures == nil {
+		t.failures = map[types.NamespacedName]int{}
+	}
+	t.failures[key]++
+	return t.failures[key]
+}
+
+// succeed records a successful reconciliation of key.
+func (t *failureTracker) succeed(key types.NamespacedName) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.failures, key)
+}
+
+// stallThreshold returns the number of consecutive failures after which an MCPServer is stalled.
+func (r *MCPServerReconciler) stallThreshold() int {
+	if r.StallThreshold > 0 {
+		return r.StallThreshold
+	}
+	return DefaultStallThreshold
+}
+
+// stallCooldownAfter returns how long to wait before retrying an MCPServer that failed failures times in a row.
+func (r *MCPServerReconciler) stallCooldownAfter(failures int) time.Duration {
+	cooldown := stallCooldown
+	for i := r.stallThreshold(); i < failures && cooldown < maxStallCooldown; i++ {
+		cooldown *= 2
+	}
+	return min(cooldown, maxStallCooldown)
+}
+
+// handleFailure records a failed reconciliation of the MCPServer of req. Below the stall threshold err is returned,
+// so that the MCPServer is retried with the rate limited backoff of the controller. From the threshold on, the
+// ReconcileStalled condition is set and the MCPServer is requeued after an exponential cool-down instead, so that
+// it does not hold up healthy MCPServers in the queue. The message of the condition does not count the failures, so
+// that further failures with the same error do not write the status again.
+func (r *MCPServerReconciler) handleFailure(ctx context.Context, req ctrl.Request, err error) (ctrl.Result, error) {
+	failures := r.failures.fail(req.NamespacedName)
+	if failures < r.stallThreshold() {
+		return ctrl.Result{}, err
+	}
+	logger := logf.FromContext(ctx)
+	cooldown := r.stallCooldownAfter(failures)
+
+	mcpServer := &mcpserverv1.MCPServer{}
+	if getErr := r.Get(ctx, req.NamespacedName, mcpServer); getErr != nil {
+		return ctrl.Result{}, err
+	}
+	originalStatus := mcpServer.Status.DeepCopy()
+	condition := metav1.Condition{
+		Type:    ReconcileStalled,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonReconcileFailing,
+		Message: fmt.Sprintf("Reconciliation keeps failing, retrying with a cool-down: %v", err),
+	}
+	if failures == r.stallThreshold() && r.Recorder != nil {
+		r.Recorder.Event(mcpServer, corev1.EventTypeWarning, ReasonReconcileFailing, condition.Message)
+	}
+	meta.SetStatusCondition(&mcpServer.Status.Conditions, condition)
+	if patchErr := r.patchStatus(ctx, mcpServer, originalStatus); patchErr != nil {
+		logger.Error(patchErr, "unable to update MCPServer status")
+	}
+	logger.Info("MCPServer reconciliation stalled", "failures", failures, "cooldown", cooldown, "error", err.Error())
+	return ctrl.Result{RequeueAfter: cooldown}, nil
+}
@@ -0,0 +1,173 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+func TestMCPServerReconciler_stallCooldownAfter(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold int
+		failures  int
+		want      time.Duration
+	}{
+		{name: "first stalled failure", failures: DefaultStallThreshold, want: 30 * time.Second},
+		{name: "doubled after each failure", failures: DefaultStallThreshold + 2, want: 2 * time.Minute},
+		{name: "custom threshold", threshold: 2, failures: 3, want: time.Minute},
+		{name: "capped", failures: 100, want: 30 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &MCPServerReconciler{StallThreshold: tt.threshold}
+			if got := r.stallCooldownAfter(tt.failures); got != tt.want {
+				t.Errorf("stallCooldownAfter(%d) = %v, want %v", tt.failures, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_Reconcile_stalled(t *testing.T) {
+	fakeScheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(fakeScheme); err != nil {
+		t.Fatalf("failed to add client-go scheme: %v", err)
+	}
+	if err := mcpserverv1.AddToScheme(fakeScheme); err != nil {
+		t.Fatalf("failed to add mcpserverv1 scheme: %v", err)
+	}
+
+	// The defaults cannot be read while failing is set. Once they can, the External MCPServer is not allowed by
+	// them, which is a successful reconciliation.
+	failing := true
+	failDefaults := interceptor.Funcs{
+		Get: func(ctx context.Context, cli client.WithWatch, key client.ObjectKey, obj client.Object,
+			opts ...client.GetOption) error {
+			if _, ok := obj.(*mcpserverv1.MCPServerDefaults); ok && failing {
+				return errors.New("the defaults are unavailable")
+			}
+			return cli.Get(ctx, key, obj, opts...)
+		},
+	}
+	cr := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+		Spec:       mcpserverv1.MCPServerSpec{Type: mcpserverv1.MCPServerExternal, URL: "https://mcp.example.com/sse"},
+	}
+	cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithStatusSubresource(&mcpserverv1.MCPServer{}).
+		WithObjects(cr, newDefaults("", mcpserverv1.MCPServerManaged)).WithInterceptorFuncs(failDefaults).Build()
+	recorder := record.NewFakeRecorder(10)
+	r := &MCPServerReconciler{Client: cli, Scheme: fakeScheme, Recorder: recorder, StallThreshold: 2}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: mcpServerName, Namespace: testNamespace}}
+	var resourceVersion string
+	stalled := func() *metav1.Condition {
+		t.Helper()
+		got := &mcpserverv1.MCPServer{}
+		if err := cli.Get(context.Background(), client.ObjectKeyFromObject(cr), got); err != nil {
+			t.Fatalf("failed to get the MCPServer: %v", err)
+		}
+		resourceVersion = got.ResourceVersion
+		return meta.FindStatusCondition(got.Status.Conditions, ReconcileStalled)
+	}
+
+	if _, err := r.Reconcile(context.Background(), req); err == nil {
+		t.Fatal("Reconcile() error = nil, want the error below the stall threshold")
+	}
+	if condition := stalled(); condition != nil {
+		t.Errorf("ReconcileStalled = %+v, want none below the stall threshold", condition)
+	}
+
+	// Every stalled failure is requeued after a longer cool-down, and only the first one writes the status, which
+	// would otherwise trigger the next reconciliation right away.
+	var stalledVersion string
+	for i, want := range []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute} {
+		result, err := r.Reconcile(context.Background(), req)
+		if err != nil || result.RequeueAfter != want {
+			t.Errorf("Reconcile() = %+v, %v, want a requeue after %v", result, err, want)
+		}
+		condition := stalled()
+		if i == 0 {
+			stalledVersion = resourceVersion
+		} else if resourceVersion != stalledVersion {
+			t.Errorf("the status was written again after %d stalled failures", i+1)
+		}
+		if condition == nil || condition.Status != metav1.ConditionTrue || condition.Reason != ReasonReconcileFailing ||
+			!strings.Contains(condition.Message, "the defaults are unavailable") {
+			t.Errorf("ReconcileStalled = %+v, want True with the error", condition)
+		}
+		if len(recorder.Events) != 1 {
+			t.Errorf("%d events emitted after %d stalled failures, want 1", len(recorder.Events), i+1)
+		}
+	}
+
+	failing = false
+	if _, err := r.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if condition := stalled(); condition != nil {
+		t.Errorf("ReconcileStalled = %+v, want it removed after a successful reconciliation", condition)
+	}
+	failing = true
+	if _, err := r.Reconcile(context.Background(), req); err == nil {
+		t.Error("Reconcile() error = nil, want the failures counted again from zero")
+	}
+}
+
+func Test_mcpServerChangedPredicate(t *testing.T) {
+	old := &mcpserverv1.MCPServer{ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace,
+		Generation: 1}}
+	tests := []struct {
+		name   string
+		update func(cr *mcpserverv1.MCPServer)
+		want   bool
+	}{
+		{
+			name: "status only",
+			update: func(cr *mcpserverv1.MCPServer) {
+				cr.Status.Conditions = []metav1.Condition{{Type: ReconcileStalled, Status: metav1.ConditionTrue}}
+			},
+		},
+		{
+			name:   "spec",
+			update: func(cr *mcpserverv1.MCPServer) { cr.Generation = 2 },
+			want:   true,
+		},
+		{
+			name:   "labels",
+			update: func(cr *mcpserverv1.MCPServer) { cr.Labels = map[string]string{mcpserverv1.TemplateLabel: "true"} },
+			want:   true,
+		},
+		{
+			name: "annotations",
+			update: func(cr *mcpserverv1.MCPServer) {
+				cr.Annotations = map[string]string{mcpserverv1.RefreshToolsAnnotation: "2026-10-16T10:00:00Z"}
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated := old.DeepCopy()
+			tt.update(updated)
+			if got := mcpServerChangedPredicate.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: updated}); got != tt.want {
+				t.Errorf("Update() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package controller
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/internal/tokenauth"
+)
+
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;create;update;patch;delete
+
+const (
+	// TokenAuthAvailable reports whether the token authentication of every MCP server pod is ready. It is only
+	// set for MCP servers with token authentication.
+	TokenAuthAvailable = "TokenAuthAvailable"
+
+	tokenAuthContainerName = "token-auth"
+	tokenAuthPort          = 8060
+	tokenAuthHealthPort    = 8061
+	authTokenVolumeName    = "auth-token"
+
+	// authTokenKey is the key of the token in the Secret the operator generates.
+	authTokenKey = "token"
+)
+
+func usesTokenAuth(cr *mcpserverv1.MCPServer) bool {
+	return cr.Spec.Auth != nil && cr.Spec.Auth.Type == mcpserverv1.AuthToken
+}
+
+// authTokenSuffix is the suffix of the name of the generated token Secret of an MCP server.
+const authTokenSuffix = "auth-token"
+
+func authTokenSecretName(cr *mcpserverv1.MCPServer) string {
+	return childName(cr, authTokenSuffix)
+}
+
+// authTokenSecretRef returns the key of the Secret that holds the token clients of cr must present: the one
+// selected by spec.auth.tokenSecretRef, otherwise the one the operator generates. It is nil when cr has no token
+// authentication.
+func authTokenSecretRef(cr *mcpserverv1.MCPServer) *corev1.SecretKeySelector {
+	if !usesTokenAuth(cr) {
+		return nil
+	}
+	if ref := cr.Spec.Auth.TokenSecretRef; ref != nil {
+		return ref
+	}
+	return &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: authTokenSecretName(cr)},
+		Key:                  authTokenKey,
+	}
+}
+
+// authToken returns the token clients of cr must present as a file, which the sidecar reads again when the
+// Secret changes.
+func authToken(cr *mcpserverv1.MCPServer) exposedSecret {
+	return exposeSecret(authTokenSecretRef(cr), nil, mcpserverv1.SecretExposureFile, "", authTokenVolumeName)
+}
+
+// tokenAuthContainer returns the token authentication of cr, which runs the token-auth subcommand of the operator
+// image in front of the metrics exporter, the guardrails filter and the MCP server, or nil when cr has no token
+// authentication.
+func (r *MCPServerReconciler) tokenAuthContainer(cr *mcpserverv1.MCPServer) *corev1.Container {
+	if !usesTokenAuth(cr) {
+		return nil
+	}
+
+	token := authToken(cr)
+
+	return &corev1.Container{
+		Name:    tokenAuthContainerName,
+		Image:   r.OperatorImage,
+		Command: []string{"/manager", tokenauth.Command},
+		Args: []string{
+			"--upstream", fmt.Sprintf("http://localhost:%d", upstreamPort(cr, tokenAuthContainerName)),
+			"--port", strconv.Itoa(tokenAuthPort),
+			"--health-port", strconv.Itoa(tokenAuthHealthPort),
+			"--token-file", token.Path,
+		},
+		Ports: []corev1.ContainerPort{{
+			ContainerPort: tokenAuthPort,
+			Name:          "http",
+		}},
+		VolumeMounts: []corev1.VolumeMount{*token.Mount},
+		ReadinessProbe: &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Path: tokenauth.HealthPath,
+					Port: intstr.FromInt32(tokenAuthHealthPort),
+				},
+			},
+			PeriodSeconds: 10,
+		},
+		SecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: ptr.To(false),
+			Capabilities: &corev1.Capabilities{
+				Drop: []corev1.Capability{"ALL"},
+			},
+		},
+	}
+}
+
+// withTokenAuth returns containers with the token authentication set to sidecar, or removed when sidecar is nil.
+// While it runs, it owns the http port and the port of the container behind it is renamed.
+func withTokenAuth(containers []corev1.Container, sidecar *corev1.Container) []corev1.Container {
+	return withSidecar(containers, tokenAuthContainerName, sidecar,
+		metricsExporterContainerName, capabilityFilterContainerName, guardrailsContainerName, "mcp-server")
+}
+
+// reconcileAuthToken generates the token of cr into a Secret when cr has token authentication without a Secret of
+// its own, and deletes the generated Secret otherwise. The Secret is created once and never updated, so a new
+// token is only generated when it is deleted. A Secret generated under the legacy name of a shortened name is
+// adopted: its token is copied, so that clients keep working, and it is removed once no pod mounts it anymore.
+func (r *MCPServerReconciler) reconcileAuthToken(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) error {
+	if !usesTokenAuth(cr) || cr.Spec.Auth.TokenSecretRef != nil {
+		err := r.deleteChild(ctx, cli, cr, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: authTokenSecretName(cr), Namespace: cr.Namespace},
+		})
+		if err != nil {
+			return err
+		}
+		return r.deleteLegacyChild(ctx, cli, cr, &corev1.Secret{}, authTokenSuffix)
+	}
+
+	legacy, err := r.getLegacyAuthToken(ctx, cli, cr)
+	if err != nil {
+		return err
+	}
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return fmt.Errorf("failed to generate the token of %s: %w", cr.Name, err)
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(token)
+	if legacy != nil {
+		encoded = string(legacy.Data[authTokenKey])
+	}
+	// The token is a credential that the operator generates again when the Secret is missing, so it has no place
+	// in a backup.
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      authTokenSecretName(cr),
+			Namespace: cr.Namespace,
+			Labels: map[string]string{
+				mcpServerAppLabelKey:         resourceName(cr),
+				veleroExcludeFromBackupLabel: "true",
+			},
+		},
+		StringData: map[string]string{authTokenKey: encoded},
+	}
+	if err := r.createChild(ctx, cli, cr, secret); err != nil {
+		return err
+	}
+
+	if legacy == nil {
+		return nil
+	}
+	inUse, err := r.deploymentMountsSecret(ctx, cli, cr, legacy.Name)
+	if err != nil || inUse {
+		return err
+	}
+	return r.deleteLegacyChild(ctx, cli, cr, &corev1.Secret{}, authTokenSuffix)
+}
+
+// getLegacyAuthToken returns the token Secret cr generated under the legacy name of authTokenSecretName, or nil if
+// the name did not change or there is no such Secret.
+func (r *MCPServerReconciler) getLegacyAuthToken(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) (*corev1.Secret, error) {
+	name := legacyChildName(cr, authTokenSuffix)
+	if name == authTokenSecretName(cr) {
+		return nil, nil
+	}
+	secret := &corev1.Secret{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: name, Namespace: cr.Namespace}, secret); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(secret, cr) {
+		return nil, nil
+	}
+	return secret, nil
+}
+
+// deploymentMountsSecret reports whether pods of the MCP server Deployment may still mount the Secret name: while its
+// pod template refers to it, or its rollout to a template without it is not complete.
+func (r *MCPServerReconciler) deploymentMountsSecret(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer, name string) (bool, error) {
+	deployment := &appsv1.Deployment{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: resourceName(cr), Namespace: cr.Namespace}, deployment); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	for _, volume := range deployment.Spec.Template.Spec.Volumes {
+		if volume.Secret != nil && volume.Secret.SecretName == name {
+			return true, nil
+		}
+	}
+	return !rolloutComplete(deployment), nil
+}
+
+// getTokenAuthCondition returns the TokenAuthAvailable condition of cr from the readiness of the token
+// authentication in each MCP server pod.
+func (r *MCPServerReconciler) getTokenAuthCondition(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) metav1.Condition {
+	return r.getSidecarCondition(ctx, cli, cr, TokenAuthAvailable, tokenAuthContainerName, "TokenAuth", "token authentication",
+		fmt.Sprintf("check that the Secret %s holds the token", authTokenSecretRef(cr).Name))
+}
@@ -0,0 +1,225 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+func Test_withTokenAuth(t *testing.T) {
+	r := &MCPServerReconciler{OperatorImage: "quay.io/example/operator:latest"}
+	server := corev1.Container{
+		Name:  "mcp-server",
+		Ports: []corev1.ContainerPort{{ContainerPort: 8000, Name: "http"}},
+	}
+	newCR := func(metricsExporter, tokenAuth, rateLimit bool) *mcpserverv1.MCPServer {
+		cr := newGuardrailsMCPServer()
+		cr.Spec.Guardrails = nil
+		if metricsExporter {
+			cr.Spec.MetricsExporter = &mcpserverv1.MetricsExporter{}
+		}
+		if tokenAuth {
+			cr.Spec.Auth = &mcpserverv1.Auth{Type: mcpserverv1.AuthToken}
+		}
+		if rateLimit {
+			cr.Spec.RateLimit = &mcpserverv1.RateLimit{Local: &mcpserverv1.LocalRateLimit{RequestsPerSecond: 5}}
+		}
+		return cr
+	}
+	all := r.withSidecars(newCR(true, true, true), []corev1.Container{server})
+
+	tests := []struct {
+		name         string
+		cr           *mcpserverv1.MCPServer
+		containers   []corev1.Container
+		wantPorts    map[string]string
+		wantUpstream string
+	}{
+		{
+			name:         "token authentication in front of the MCP server",
+			cr:           newCR(false, true, false),
+			containers:   []corev1.Container{server},
+			wantPorts:    map[string]string{"mcp-server": mcpServerPortName, tokenAuthContainerName: "http"},
+			wantUpstream: "http://localhost:8000",
+		},
+		{
+			name:       "token authentication between the rate limiter and the metrics exporter",
+			cr:         newCR(true, true, true),
+			containers: []corev1.Container{server},
+			wantPorts: map[string]string{"mcp-server": mcpServerPortName, metricsExporterContainerName: metricsExporterPortName,
+				tokenAuthContainerName: tokenAuthPortName, rateLimiterContainerName: "http"},
+			wantUpstream: "http://localhost:8090",
+		},
+		{
+			name:       "token authentication removed behind the rate limiter",
+			cr:         newCR(true, false, true),
+			containers: all,
+			wantPorts: map[string]string{"mcp-server": mcpServerPortName, metricsExporterContainerName: metricsExporterPortName,
+				rateLimiterContainerName: "http"},
+		},
+		{
+			name:       "rate limiter removed from the token authentication",
+			cr:         newCR(true, true, false),
+			containers: all,
+			wantPorts: map[string]string{"mcp-server": mcpServerPortName, metricsExporterContainerName: metricsExporterPortName,
+				tokenAuthContainerName: "http"},
+			wantUpstream: "http://localhost:8090",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := r.withSidecars(tt.cr, tt.containers)
+			if len(got) != len(tt.wantPorts) {
+				t.Fatalf("withSidecars() returned %d containers, want %d", len(got), len(tt.wantPorts))
+			}
+			for _, container := range got {
+				if container.Ports[0].Name != tt.wantPorts[container.Name] {
+					t.Errorf("port of %s = %s, want %s", container.Name, container.Ports[0].Name, tt.wantPorts[container.Name])
+				}
+				if container.Name != tokenAuthContainerName {
+					continue
+				}
+				if container.Args[1] != tt.wantUpstream {
+					t.Errorf("upstream of the token authentication = %s, want %s", container.Args[1], tt.wantUpstream)
+				}
+				if len(container.VolumeMounts) != 1 || container.VolumeMounts[0].Name != authTokenVolumeName {
+					t.Errorf("volume mounts = %v, want the token", container.VolumeMounts)
+				}
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_reconcileAuthToken(t *testing.T) {
+	fakeScheme := runtime.NewScheme()
+	err := clientgoscheme.AddToScheme(fakeScheme)
+	if err != nil {
+		t.Errorf("failed to add client-go scheme: %v", err)
+	}
+	err = mcpserverv1.AddToScheme(fakeScheme)
+	if err != nil {
+		t.Errorf("failed to add mcpserverv1 scheme: %v", err)
+	}
+
+	newMCPServer := func(auth *mcpserverv1.Auth) *mcpserverv1.MCPServer {
+		return &mcpserverv1.MCPServer{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      mcpServerName,
+				Namespace: testNamespace,
+				UID:       types.UID("uid"),
+			},
+			Spec: mcpserverv1.MCPServerSpec{
+				Image: mcpServerImage,
+				Auth:  auth,
+			},
+		}
+	}
+	ownSecret := &mcpserverv1.Auth{
+		Type: mcpserverv1.AuthToken,
+		TokenSecretRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "team-token"},
+			Key:                  "token",
+		},
+	}
+
+	tests := []struct {
+		name       string
+		previous   *mcpserverv1.Auth
+		auth       *mcpserverv1.Auth
+		unlabeled  bool
+		wantSecret bool
+	}{
+		{
+			name: "no authentication",
+		},
+		{
+			name:       "generated token",
+			auth:       &mcpserverv1.Auth{Type: mcpserverv1.AuthToken},
+			wantSecret: true,
+		},
+		{
+			name:       "generated token is kept",
+			previous:   &mcpserverv1.Auth{Type: mcpserverv1.AuthToken},
+			auth:       &mcpserverv1.Auth{Type: mcpserverv1.AuthToken},
+			wantSecret: true,
+		},
+		{
+			name:       "generated token of an earlier version is excluded from backups",
+			previous:   &mcpserverv1.Auth{Type: mcpserverv1.AuthToken},
+			auth:       &mcpserverv1.Auth{Type: mcpserverv1.AuthToken},
+			unlabeled:  true,
+			wantSecret: true,
+		},
+		{
+			name: "token of the team",
+			auth: ownSecret,
+		},
+		{
+			name:     "generated token is removed",
+			previous: &mcpserverv1.Auth{Type: mcpserverv1.AuthToken},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().WithScheme(fakeScheme).Build()
+			r := &MCPServerReconciler{Client: cli, Scheme: fakeScheme}
+			key := client.ObjectKey{Name: mcpServerName + "-auth-token", Namespace: testNamespace}
+
+			var previousToken string
+			if tt.previous != nil {
+				if err := r.reconcileAuthToken(context.Background(), cli, newMCPServer(tt.previous)); err != nil {
+					t.Fatalf("reconcileAuthToken() error = %v", err)
+				}
+				previous := &corev1.Secret{}
+				if err := cli.Get(context.Background(), key, previous); err != nil {
+					t.Fatalf("failed to get the generated token: %v", err)
+				}
+				previousToken = previous.StringData[authTokenKey]
+				if tt.unlabeled {
+					delete(previous.Labels, veleroExcludeFromBackupLabel)
+					if err := cli.Update(context.Background(), previous); err != nil {
+						t.Fatalf("failed to remove the backup label: %v", err)
+					}
+				}
+			}
+			if err := r.reconcileAuthToken(context.Background(), cli, newMCPServer(tt.auth)); err != nil {
+				t.Fatalf("reconcileAuthToken() error = %v", err)
+			}
+
+			secret := &corev1.Secret{}
+			err := cli.Get(context.Background(), key, secret)
+			if !tt.wantSecret {
+				if !k8serr.IsNotFound(err) {
+					t.Errorf("expected no generated token, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to get the generated token: %v", err)
+			}
+			token := secret.StringData[authTokenKey]
+			if len(token) < 32 {
+				t.Errorf("token = %q, want a random token", token)
+			}
+			if previousToken != "" && token != previousToken {
+				t.Errorf("token = %q, want the previous token %q", token, previousToken)
+			}
+			if secret.Labels[veleroExcludeFromBackupLabel] != "true" {
+				t.Errorf("labels = %v, want the generated token excluded from backups", secret.Labels)
+			}
+			if len(secret.OwnerReferences) != 1 || secret.OwnerReferences[0].UID != "uid" {
+				t.Errorf("owner references = %v, want the MCPServer", secret.OwnerReferences)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/mcp"
+)
+
+const (
+	ReasonToolsRefreshFailed = "ToolsRefreshFailed"
+
+	// toolsRefreshTimeout bounds the whole MCP session the tools are listed in.
+	toolsRefreshTimeout = 10 * time.Second
+
+	// toolDescriptionLimit caps the description of each tool copied into the MCPServer status.
+	toolDescriptionLimit = 256
+)
+
+// toolsRefreshDue reports whether the tools of cr should be listed again: after a rollout of a new Deployment
+// revision or a change of cr, when the refresh-tools annotation changed, and once the refresh interval passed
+// since the last listing.
+func toolsRefreshDue(cr *mcpserverv1.MCPServer, revision string, now time.Time) bool {
+	refresh := cr.Status.ToolsRefresh
+	if refresh == nil || refresh.Revision != revision || refresh.ObservedGeneration != cr.Generation ||
+		refresh.Trigger != cr.Annotations[mcpserverv1.RefreshToolsAnnotation] {
+		return true
+	}
+	return toolsRefreshAfter(cr, now) == 0
+}
+
+// toolsRefreshAfter returns how long until the refresh interval of cr passes, zero if it already did and a
+// negative duration if cr has no refresh interval.
+func toolsRefreshAfter(cr *mcpserverv1.MCPServer, now time.Time) time.Duration {
+	interval := cr.Spec.ToolsRefreshInterval
+	if interval == nil || interval.Duration <= 0 {
+		return -1
+	}
+	refresh := cr.Status.ToolsRefresh
+	if refresh == nil || refresh.LastRefreshTime == nil {
+		return 0
+	}
+	return max(refresh.LastRefreshTime.Add(interval.Duration).Sub(now), 0)
+}
+
+// reconcileTools lists the tools of the MCP server once its endpoint is reachable and records them in the
+// status of cr. The tools of a Managed MCP server are only listed once the rollout of its Deployment is
+// complete, so that they are not taken from pods that are about to go away. A failed listing keeps the tools
+// found before and is retried on the next reconcile.
+func (r *MCPServerReconciler) reconcileTools(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) error {
+	// gRPC servers do not answer the MCP requests the tools are listed with, and servers with token passthrough would
+	// call the Kubernetes API with the token of the operator.
+	if usesGRPC(cr) || usesTokenPassthrough(cr) || !meta.IsStatusConditionTrue(cr.Status.Conditions, EndpointReachable) {
+		return nil
+	}
+
+	var revision string
+	if !isExternal(cr) {
+		deployment := &appsv1.Deployment{}
+		err := cli.Get(ctx, client.ObjectKey{Name: resourceName(cr), Namespace: cr.Namespace}, deployment)
+		if err != nil {
+			if k8serr.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		if !rolloutComplete(deployment) {
+			return nil
+		}
+		revision = deployment.Annotations[deploymentRevisionAnnotation]
+	}
+
+	now := metav1.Now()
+	if !toolsRefreshDue(cr, revision, now.Time) {
+		return nil
+	}
+
+	tools, err := r.listTools(ctx, cli, cr)
+	if err != nil {
+		message := fmt.Sprintf("Failed to list the tools of %s: %v", cr.Name, err)
+		if cr.Status.ToolsRefresh == nil || cr.Status.ToolsRefresh.Message != message {
+			if r.Recorder != nil {
+				r.Recorder.Event(cr, corev1.EventTypeWarning, ReasonToolsRefreshFailed, message)
+			}
+		}
+		if cr.Status.ToolsRefresh == nil {
+			cr.Status.ToolsRefresh = &mcpserverv1.ToolsRefreshStatus{}
+		}
+		cr.Status.ToolsRefresh.Message = message
+		return nil
+	}
+
+	cr.Status.Tools = tools
+	cr.Status.ToolsRefresh = &mcpserverv1.ToolsRefreshStatus{
+		Revision:           revision,
+		ObservedGeneration: cr.Generation,
+		Trigger:            cr.Annotations[mcpserverv1.RefreshToolsAnnotation],
+		LastRefreshTime:    &now,
+	}
+	return nil
+}
+
+// listTools connects to the MCP server of cr, the same way the connection test does, and lists its tools. No
+// tools are returned when the server does not offer the tools capability.
+func (r *MCPServerReconciler) listTools(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) ([]mcpserverv1.Tool, error) {
+	token, err := r.getCredentialsToken(ctx, cli, cr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the credentials: %w", err)
+	}
+	if isProxy(cr) {
+		// The proxy only admits Kubernetes identities and does not pass them on, the operator authenticates with its
+		// own.
+		data, err := os.ReadFile(serviceAccountTokenPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the service account token: %w", err)
+		}
+		token = strings.TrimSpace(string(data))
+	}
+
+	httpClient := r.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	if token != "" {
+		httpClient = mcp.WithBearerToken(httpClient, token)
+	}
+
+	listCtx, cancel := context.WithTimeout(ctx, toolsRefreshTimeout)
+	defer cancel()
+
+	session, err := mcp.Connect(listCtx, httpClient, connectionTestURL(cr))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = session.Close()
+	}()
+	result, err := session.Initialize(listCtx)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := result.Capabilities["tools"]; !ok {
+		// The server offers no tools, e.g. because spec.capabilities disables them.
+		return nil, nil
+	}
+	found, err := session.ListTools(listCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	tools := make([]mcpserverv1.Tool, 0, len(found))
+	for _, tool := range found {
+		description := tool.Description
+		if len(description) > toolDescriptionLimit {
+			description = description[:toolDescriptionLimit]
+		}
+		tools = append(tools, mcpserverv1.Tool{Name: tool.Name, Description: description})
+	}
+	return tools, nil
+}
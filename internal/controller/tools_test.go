@@ -0,0 +1,136 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/mcp"
+	mcptesting "github.com/opendatahub-io/mcp-server-operator/pkg/testing"
+)
+
+func TestMCPServerReconciler_reconcileTools(t *testing.T) {
+	tool := func(name, description string) mcptesting.Tool {
+		return mcptesting.Tool{Tool: mcp.Tool{Name: name, Description: description}}
+	}
+	server := mcptesting.NewServer(tool("echo", "Echoes its input"))
+	t.Cleanup(server.Close)
+
+	cli := fake.NewClientBuilder().Build()
+	recorder := record.NewFakeRecorder(10)
+	r := &MCPServerReconciler{Client: cli, HTTPClient: server.Client(), Recorder: recorder}
+	cr := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace, Generation: 1},
+		Spec: mcpserverv1.MCPServerSpec{
+			Type: mcpserverv1.MCPServerExternal,
+			URL:  server.SSEURL(),
+		},
+		Status: mcpserverv1.MCPServerStatus{
+			Conditions: []metav1.Condition{{Type: EndpointReachable, Status: metav1.ConditionTrue}},
+		},
+	}
+	reconcileTools := func(want ...string) {
+		t.Helper()
+		if err := r.reconcileTools(context.Background(), cli, cr); err != nil {
+			t.Fatalf("reconcileTools() error = %v", err)
+		}
+		var got []string
+		for _, tool := range cr.Status.Tools {
+			got = append(got, tool.Name)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("status.tools = %v, want %v", got, want)
+		}
+	}
+
+	reconcileTools("echo")
+	if cr.Status.Tools[0].Description != "Echoes its input" || cr.Status.ToolsRefresh.LastRefreshTime == nil {
+		t.Errorf("status.tools = %+v with refresh %+v, want the description and the refresh time", cr.Status.Tools,
+			cr.Status.ToolsRefresh)
+	}
+
+	// A reconfigured server is only listed again once a refresh is requested.
+	server.SetTools(tool("echo", ""), tool("search", ""))
+	reconcileTools("echo")
+	cr.Annotations = map[string]string{mcpserverv1.RefreshToolsAnnotation: "2026-10-16T10:00:00Z"}
+	reconcileTools("echo", "search")
+	if cr.Status.ToolsRefresh.Trigger != "2026-10-16T10:00:00Z" {
+		t.Errorf("status.toolsRefresh.trigger = %q, want the value of the annotation", cr.Status.ToolsRefresh.Trigger)
+	}
+
+	// The refresh interval lists the tools again once it passed.
+	server.SetTools(tool("search", ""))
+	cr.Spec.ToolsRefreshInterval = &metav1.Duration{Duration: time.Hour}
+	reconcileTools("echo", "search")
+	cr.Status.ToolsRefresh.LastRefreshTime = &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}
+	reconcileTools("search")
+
+	// The tools of a server with token passthrough are not listed, it would receive the token of the operator.
+	server.SetTools(tool("echo", ""))
+	cr.Spec.KubernetesAccess = &mcpserverv1.KubernetesAccess{Mode: mcpserverv1.KubernetesAccessTokenPassthrough}
+	cr.Annotations[mcpserverv1.RefreshToolsAnnotation] = "2026-10-16T10:30:00Z"
+	requests := len(server.Requests())
+	reconcileTools("search")
+	if got := len(server.Requests()); got != requests {
+		t.Errorf("the server received %d requests, want none", got-requests)
+	}
+	cr.Spec.KubernetesAccess = nil
+
+	// A failed listing keeps the tools found before and is reported with an event.
+	server.Close()
+	cr.Annotations[mcpserverv1.RefreshToolsAnnotation] = "2026-10-16T11:00:00Z"
+	reconcileTools("search")
+	if cr.Status.ToolsRefresh.Message == "" || len(recorder.Events) != 1 {
+		t.Errorf("status.toolsRefresh = %+v with %d events, want the failure reported", cr.Status.ToolsRefresh,
+			len(recorder.Events))
+	}
+}
+
+func Test_toolsRefreshAfter(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name        string
+		interval    *metav1.Duration
+		lastRefresh *metav1.Time
+		want        time.Duration
+	}{
+		{
+			name: "no interval",
+			want: -1,
+		},
+		{
+			name:     "never listed",
+			interval: &metav1.Duration{Duration: time.Hour},
+			want:     0,
+		},
+		{
+			name:        "interval not yet passed",
+			interval:    &metav1.Duration{Duration: time.Hour},
+			lastRefresh: &metav1.Time{Time: now.Add(-15 * time.Minute)},
+			want:        45 * time.Minute,
+		},
+		{
+			name:        "interval passed",
+			interval:    &metav1.Duration{Duration: time.Hour},
+			lastRefresh: &metav1.Time{Time: now.Add(-2 * time.Hour)},
+			want:        0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &mcpserverv1.MCPServer{
+				Spec:   mcpserverv1.MCPServerSpec{ToolsRefreshInterval: tt.interval},
+				Status: mcpserverv1.MCPServerStatus{ToolsRefresh: &mcpserverv1.ToolsRefreshStatus{LastRefreshTime: tt.lastRefresh}},
+			}
+			if got := toolsRefreshAfter(cr, now); got != tt.want {
+				t.Errorf("toolsRefreshAfter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// ReasonExpired is the reason of the event emitted when an MCPServer is deleted because its TTL elapsed.
+const ReasonExpired = "Expired"
+
+// expirationTime returns when cr is to be deleted according to its TTLs, the earlier of them, or nil if it sets
+// none. An MCPServer that was never found active expires the TTL after its creation.
+func expirationTime(cr *mcpserverv1.MCPServer) *metav1.Time {
+	var expiration *metav1.Time
+	earliest := func(t time.Time) {
+		if expiration == nil || t.Before(expiration.Time) {
+			expiration = &metav1.Time{Time: t}
+		}
+	}
+	if ttl := cr.Spec.TTLSecondsAfterCreation; ttl != nil {
+		earliest(cr.CreationTimestamp.Add(time.Duration(*ttl) * time.Second))
+	}
+	if ttl := cr.Spec.TTLSecondsAfterLastActivity; ttl != nil {
+		lastActivity := cr.CreationTimestamp.Time
+		if cr.Status.Usage != nil && cr.Status.Usage.LastActivityTime != nil {
+			lastActivity = cr.Status.Usage.LastActivityTime.Time
+		}
+		earliest(lastActivity.Add(time.Duration(*ttl) * time.Second))
+	}
+	return expiration
+}
+
+// expiresAfter returns how long until cr is to be deleted, zero if it already is and a negative duration if it
+// sets no TTL.
+func expiresAfter(cr *mcpserverv1.MCPServer, now time.Time) time.Duration {
+	expiration := expirationTime(cr)
+	if expiration == nil {
+		return -1
+	}
+	return max(expiration.Sub(now), 0)
+}
+
+// deleteExpired deletes cr once its TTL elapsed, and reports whether it did. The children of cr are removed by
+// the garbage collector.
+func (r *MCPServerReconciler) deleteExpired(ctx context.Context, cr *mcpserverv1.MCPServer) (bool, error) {
+	if expiresAfter(cr, time.Now()) != 0 || !cr.DeletionTimestamp.IsZero() {
+		return false, nil
+	}
+	if r.DryRun {
+		// Only the status is written in dry-run mode, the expiration time in it reports the deletion.
+		return false, nil
+	}
+	if err := r.Delete(ctx, cr); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	if r.Recorder != nil {
+		r.Recorder.Eventf(cr, corev1.EventTypeNormal, ReasonExpired, "Deleted the MCPServer, its TTL elapsed at %s",
+			expirationTime(cr).UTC().Format(time.RFC3339))
+	}
+	return true, nil
+}
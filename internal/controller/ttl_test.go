@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+func Test_expirationTime(t *testing.T) {
+	created := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	active := created.Add(time.Hour)
+
+	tests := []struct {
+		name         string
+		afterCreate  *int32
+		afterActive  *int32
+		lastActivity *time.Time
+		want         *time.Time
+	}{
+		{
+			name: "Verify that an MCPServer without a TTL does not expire",
+		},
+		{
+			name:        "Verify that the TTL after creation counts from the creation",
+			afterCreate: ptr.To(int32(600)),
+			want:        ptr.To(created.Add(10 * time.Minute)),
+		},
+		{
+			name:        "Verify that an MCPServer that was never active expires after its creation",
+			afterActive: ptr.To(int32(600)),
+			want:        ptr.To(created.Add(10 * time.Minute)),
+		},
+		{
+			name:         "Verify that the TTL after activity counts from the last activity",
+			afterActive:  ptr.To(int32(600)),
+			lastActivity: &active,
+			want:         ptr.To(active.Add(10 * time.Minute)),
+		},
+		{
+			name:         "Verify that the earlier of both TTLs applies",
+			afterCreate:  ptr.To(int32(7200)),
+			afterActive:  ptr.To(int32(3600)),
+			lastActivity: &active,
+			want:         ptr.To(created.Add(2 * time.Hour)),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &mcpserverv1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)},
+				Spec: mcpserverv1.MCPServerSpec{
+					TTLSecondsAfterCreation:     tt.afterCreate,
+					TTLSecondsAfterLastActivity: tt.afterActive,
+				},
+			}
+			if tt.lastActivity != nil {
+				cr.Status.Usage = &mcpserverv1.UsageStatus{LastActivityTime: &metav1.Time{Time: *tt.lastActivity}}
+			}
+			got := expirationTime(cr)
+			if (got == nil) != (tt.want == nil) || (got != nil && !got.Time.Equal(*tt.want)) {
+				t.Errorf("expirationTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_Reconcile_expired(t *testing.T) {
+	fakeScheme := newAutoscalingScheme(t)
+	cr := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              mcpServerName,
+			Namespace:         testNamespace,
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Hour)),
+		},
+		Spec: mcpserverv1.MCPServerSpec{Image: "quay.io/example/tools:1", TTLSecondsAfterCreation: ptr.To(int32(60))},
+	}
+	cli := fake.NewClientBuilder().WithScheme(fakeScheme).WithStatusSubresource(&mcpserverv1.MCPServer{}).
+		WithObjects(cr).Build()
+	recorder := record.NewFakeRecorder(10)
+	r := &MCPServerReconciler{Client: cli, Scheme: fakeScheme, Recorder: recorder}
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cr)}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := cli.Get(context.Background(), client.ObjectKeyFromObject(cr), cr); !k8serr.IsNotFound(err) {
+		t.Errorf("Get() error = %v, want the expired MCPServer deleted", err)
+	}
+	if len(recorder.Events) != 1 {
+		t.Errorf("%d events emitted, want 1", len(recorder.Events))
+	}
+}
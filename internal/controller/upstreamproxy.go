@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/internal/proxy"
+)
+
+// isProxy reports whether the MCP server is hosted elsewhere and reached through a proxy deployed by the operator.
+func isProxy(cr *mcpserverv1.MCPServer) bool {
+	return cr.Spec.Type == mcpserverv1.MCPServerProxy
+}
+
+// upstreamProxyCredentials returns the token of a Proxy MCP server as spec.credentialsExposure asks, read from
+// a file by default.
+func upstreamProxyCredentials(cr *mcpserverv1.MCPServer) exposedSecret {
+	return exposeSecret(cr.Spec.CredentialsSecretRef, cr.Spec.CredentialsExposure, mcpserverv1.SecretExposureFile,
+		proxy.TokenEnv, credentialsVolumeName)
+}
+
+// upstreamProxyContainer returns the container of a Proxy MCP server, which runs the proxy subcommand of the
+// operator image in place of an MCP server image.
+func (r *MCPServerReconciler) upstreamProxyContainer(cr *mcpserverv1.MCPServer) corev1.Container {
+	args := []string{
+		"--upstream", cr.Spec.URL,
+		"--sse-path", mcpServerPath(cr),
+		"--port", strconv.Itoa(mcpServerPort),
+		"--mcp-server", cr.Name,
+		"--namespace", cr.Namespace,
+	}
+	if interval := sseKeepAliveInterval(cr); interval > 0 {
+		args = append(args, "--sse-keep-alive", interval.String())
+	}
+	env := r.proxyEnv()
+	var volumeMounts []corev1.VolumeMount
+	credentials := upstreamProxyCredentials(cr)
+	if credentials.Env != nil {
+		env = append(env, *credentials.Env)
+	}
+	if credentials.Mount != nil {
+		args = append(args, "--token-file", credentials.Path)
+		volumeMounts = append(volumeMounts, *credentials.Mount)
+	}
+
+	return corev1.Container{
+		Name:    "mcp-server",
+		Image:   r.OperatorImage,
+		Command: []string{"/manager", proxy.Command},
+		Args:    args,
+		Ports: []corev1.ContainerPort{{
+			ContainerPort: mcpServerPort,
+			Name:          "http",
+		}},
+		Env:          env,
+		Resources:    r.resources(cr),
+		VolumeMounts: volumeMounts,
+		SecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: ptr.To(false),
+			Capabilities: &corev1.Capabilities{
+				Drop: []corev1.Capability{"ALL"},
+			},
+		},
+	}
+}
@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/opendatahub-io/mcp-server-operator/internal/proxy"
+)
+
+func TestMCPServerReconciler_upstreamProxyDeployment(t *testing.T) {
+	fakeScheme := runtime.NewScheme()
+	err := clientgoscheme.AddToScheme(fakeScheme)
+	if err != nil {
+		t.Errorf("failed to add client-go scheme: %v", err)
+	}
+	err = mcpserverv1.AddToScheme(fakeScheme)
+	if err != nil {
+		t.Errorf("failed to add mcpserverv1 scheme: %v", err)
+	}
+
+	secretRef := &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "upstream"},
+		Key:                  "token",
+	}
+	cr := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+		Spec: mcpserverv1.MCPServerSpec{
+			Type:                 mcpserverv1.MCPServerProxy,
+			URL:                  "https://mcp.example.com/sse",
+			CredentialsSecretRef: secretRef,
+		},
+	}
+
+	tests := []struct {
+		name          string
+		operatorImage string
+		exposure      *mcpserverv1.SecretExposure
+		sse           *mcpserverv1.SSE
+		basePath      string
+		wantErr       bool
+		wantArgs      []string
+		wantEnv       []corev1.EnvVar
+		wantVolumes   int
+	}{
+		{
+			name:    "operator image is not configured",
+			wantErr: true,
+		},
+		{
+			name:          "proxy with credentials in a file",
+			operatorImage: "quay.io/opendatahub/mcp-server-operator:latest",
+			wantArgs: []string{
+				"--upstream", "https://mcp.example.com/sse",
+				"--sse-path", mcpServerSSEPath,
+				"--port", "8000",
+				"--mcp-server", mcpServerName,
+				"--namespace", testNamespace,
+				"--token-file", "/var/run/secrets/mcpserver.opendatahub.io/credentials",
+			},
+			wantVolumes: 1,
+		},
+		{
+			name:          "proxy with credentials in the environment",
+			operatorImage: "quay.io/opendatahub/mcp-server-operator:latest",
+			exposure:      &mcpserverv1.SecretExposure{Mode: mcpserverv1.SecretExposureEnv},
+			wantArgs: []string{
+				"--upstream", "https://mcp.example.com/sse",
+				"--sse-path", mcpServerSSEPath,
+				"--port", "8000",
+				"--mcp-server", mcpServerName,
+				"--namespace", testNamespace,
+			},
+			wantEnv: []corev1.EnvVar{
+				{Name: proxy.TokenEnv, ValueFrom: &corev1.EnvVarSource{SecretKeyRef: secretRef}},
+			},
+		},
+		{
+			name:          "proxy with SSE keep-alives",
+			operatorImage: "quay.io/opendatahub/mcp-server-operator:latest",
+			exposure:      &mcpserverv1.SecretExposure{Mode: mcpserverv1.SecretExposureEnv},
+			sse:           &mcpserverv1.SSE{KeepAliveInterval: &metav1.Duration{Duration: 15 * time.Second}},
+			wantArgs: []string{
+				"--upstream", "https://mcp.example.com/sse",
+				"--sse-path", mcpServerSSEPath,
+				"--port", "8000",
+				"--mcp-server", mcpServerName,
+				"--namespace", testNamespace,
+				"--sse-keep-alive", "15s",
+			},
+			wantEnv: []corev1.EnvVar{
+				{Name: proxy.TokenEnv, ValueFrom: &corev1.EnvVarSource{SecretKeyRef: secretRef}},
+			},
+		},
+		{
+			name:          "proxy under a base path",
+			operatorImage: "quay.io/opendatahub/mcp-server-operator:latest",
+			exposure:      &mcpserverv1.SecretExposure{Mode: mcpserverv1.SecretExposureEnv},
+			basePath:      "/api/sse",
+			wantArgs: []string{
+				"--upstream", "https://mcp.example.com/sse",
+				"--sse-path", "/api/sse",
+				"--port", "8000",
+				"--mcp-server", mcpServerName,
+				"--namespace", testNamespace,
+			},
+			wantEnv: []corev1.EnvVar{
+				{Name: proxy.TokenEnv, ValueFrom: &corev1.EnvVarSource{SecretKeyRef: secretRef}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().WithScheme(fakeScheme).Build()
+			r := &MCPServerReconciler{Client: cli, Scheme: fakeScheme, OperatorImage: tt.operatorImage}
+
+			proxyCR := cr.DeepCopy()
+			proxyCR.Spec.CredentialsExposure = tt.exposure
+			proxyCR.Spec.SSE = tt.sse
+			proxyCR.Spec.BasePath = tt.basePath
+			err := r.reconcileMCPServerDeployment(context.Background(), cli, proxyCR)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("reconcileMCPServerDeployment() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			deployment := &appsv1.Deployment{}
+			err = cli.Get(context.Background(), client.ObjectKey{Name: mcpServerName, Namespace: testNamespace}, deployment)
+			if err != nil {
+				t.Fatalf("failed to get the deployment: %v", err)
+			}
+			container := deployment.Spec.Template.Spec.Containers[0]
+			if container.Image != tt.operatorImage {
+				t.Errorf("image = %s, want %s", container.Image, tt.operatorImage)
+			}
+			if want := []string{"/manager", proxy.Command}; !reflect.DeepEqual(container.Command, want) {
+				t.Errorf("command = %v, want %v", container.Command, want)
+			}
+			if !reflect.DeepEqual(container.Args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", container.Args, tt.wantArgs)
+			}
+			if !reflect.DeepEqual(container.Env, tt.wantEnv) {
+				t.Errorf("env = %v, want %v", container.Env, tt.wantEnv)
+			}
+			if volumes := deployment.Spec.Template.Spec.Volumes; len(volumes) != tt.wantVolumes ||
+				len(container.VolumeMounts) != tt.wantVolumes {
+				t.Errorf("volumes = %v with mounts %v, want %d", volumes, container.VolumeMounts, tt.wantVolumes)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/common/expfmt"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/internal/metricsexporter"
+)
+
+const (
+	// defaultUsageInterval is how often the usage of an MCP server is scraped when its metrics exporter sets
+	// no interval.
+	defaultUsageInterval = 5 * time.Minute
+
+	// usageScrapeTimeout bounds the scrape of the metrics of each pod.
+	usageScrapeTimeout = 5 * time.Second
+
+	requestsMetric = "mcp_requests_total"
+	sessionsMetric = "mcp_active_sessions"
+)
+
+// usage is the traffic of the MCP server pods found in a scrape.
+type usage struct {
+	requests float64
+	sessions float64
+}
+
+// usageScrapeAfter returns how long until the usage of cr is due to be scraped, zero if it already is and a
+// negative duration if cr has no metrics exporter to scrape.
+func usageScrapeAfter(cr *mcpserverv1.MCPServer, now time.Time) time.Duration {
+	if cr.Spec.MetricsExporter == nil {
+		return -1
+	}
+	interval := defaultUsageInterval
+	if i := cr.Spec.MetricsExporter.UsageInterval; i != nil && i.Duration > 0 {
+		interval = i.Duration
+	}
+	if cr.Status.Usage == nil || cr.Status.Usage.LastScrapeTime == nil {
+		return 0
+	}
+	return max(cr.Status.Usage.LastScrapeTime.Add(interval).Sub(now), 0)
+}
+
+// reconcileUsage scrapes the metrics exporters of the MCP server pods once the usage interval passed and records
+// the sum of their traffic in the status of cr. A failed scrape keeps the usage found before and is retried on
+// the next reconcile.
+func (r *MCPServerReconciler) reconcileUsage(ctx context.Context, cli client.Client, cr *mcpserverv1.MCPServer) error {
+	if cr.Spec.MetricsExporter == nil {
+		cr.Status.Usage = nil
+		return nil
+	}
+	now := metav1.Now()
+	if usageScrapeAfter(cr, now.Time) > 0 {
+		return nil
+	}
+
+	pods, err := r.listMCPServerPods(ctx, cli, cr)
+	if err != nil {
+		return err
+	}
+	var total usage
+	for _, pod := range pods {
+		if !metricsExporterReady(&pod) {
+			continue
+		}
+		podUsage, err := r.scrapeUsage(ctx, metricsURL(&pod))
+		if err != nil {
+			if cr.Status.Usage == nil {
+				cr.Status.Usage = &mcpserverv1.UsageStatus{}
+			}
+			cr.Status.Usage.Message = fmt.Sprintf("Failed to scrape the metrics of pod %s: %v", pod.Name, err)
+			return nil
+		}
+		total.requests += podUsage.requests
+		total.sessions += podUsage.sessions
+	}
+	recordUsage(cr, total, now)
+	return nil
+}
+
+// recordUsage sets the usage of cr to total. The MCP server was active when it answered requests since the
+// previous scrape or holds sessions open. Requests answered by pods that went away since are not noticed.
+func recordUsage(cr *mcpserverv1.MCPServer, total usage, now metav1.Time) {
+	previous := cr.Status.Usage
+	if previous == nil {
+		previous = &mcpserverv1.UsageStatus{}
+	}
+	status := &mcpserverv1.UsageStatus{
+		TotalRequests:    int64(total.requests),
+		ActiveSessions:   int32(total.sessions),
+		LastActivityTime: previous.LastActivityTime,
+		LastScrapeTime:   &now,
+	}
+	if status.TotalRequests > previous.TotalRequests || status.ActiveSessions > 0 {
+		status.LastActivityTime = &now
+	}
+	cr.Status.Usage = status
+}
+
+// metricsExporterReady reports whether the metrics exporter of pod serves its metrics.
+func metricsExporterReady(pod *corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" {
+		return false
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == metricsExporterContainerName {
+			return status.Ready
+		}
+	}
+	return false
+}
+
+// metricsURL returns the URL of the metrics of the metrics exporter of pod.
+func metricsURL(pod *corev1.Pod) string {
+	host := net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(metricsExporterMetricsPort))
+	return fmt.Sprintf("http://%s%s", host, metricsexporter.MetricsPath)
+}
+
+// scrapeUsage reads the MCP requests answered and the sessions held open from the Prometheus metrics at url.
+func (r *MCPServerReconciler) scrapeUsage(ctx context.Context, url string) (usage, error) {
+	ctx, cancel := context.WithTimeout(ctx, usageScrapeTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return usage{}, err
+	}
+	httpClient := r.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return usage{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return usage{}, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(resp.Body)
+	if err != nil {
+		return usage{}, fmt.Errorf("failed to parse the metrics: %w", err)
+	}
+	var result usage
+	if family := families[requestsMetric]; family != nil {
+		for _, metric := range family.GetMetric() {
+			result.requests += metric.GetCounter().GetValue()
+		}
+	}
+	if family := families[sessionsMetric]; family != nil {
+		for _, metric := range family.GetMetric() {
+			result.sessions += metric.GetGauge().GetValue()
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+const testMetrics = `# TYPE mcp_requests_total counter
+mcp_requests_total{method="initialize",outcome="success"} 3
+mcp_requests_total{method="tools/call",outcome="error"} 2
+# TYPE mcp_active_sessions gauge
+mcp_active_sessions %d
+`
+
+func Test_usageScrapeAfter(t *testing.T) {
+	now := time.Now()
+	scrapedAt := func(ago time.Duration) *mcpserverv1.UsageStatus {
+		return &mcpserverv1.UsageStatus{LastScrapeTime: &metav1.Time{Time: now.Add(-ago)}}
+	}
+
+	tests := []struct {
+		name     string
+		exporter *mcpserverv1.MetricsExporter
+		usage    *mcpserverv1.UsageStatus
+		want     time.Duration
+	}{
+		{
+			name: "Verify that servers without a metrics exporter are not scraped",
+			want: -1,
+		},
+		{
+			name:     "Verify that a server that was never scraped is due",
+			exporter: &mcpserverv1.MetricsExporter{},
+		},
+		{
+			name:     "Verify that the default interval applies",
+			exporter: &mcpserverv1.MetricsExporter{},
+			usage:    scrapedAt(time.Minute),
+			want:     defaultUsageInterval - time.Minute,
+		},
+		{
+			name:     "Verify that a scrape is due once the interval passed",
+			exporter: &mcpserverv1.MetricsExporter{UsageInterval: &metav1.Duration{Duration: time.Minute}},
+			usage:    scrapedAt(2 * time.Minute),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &mcpserverv1.MCPServer{
+				Spec:   mcpserverv1.MCPServerSpec{MetricsExporter: tt.exporter},
+				Status: mcpserverv1.MCPServerStatus{Usage: tt.usage},
+			}
+			if got := usageScrapeAfter(cr, now); got != tt.want {
+				t.Errorf("usageScrapeAfter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_reconcileUsage(t *testing.T) {
+	sessions := 0
+	failing := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = fmt.Fprintf(w, testMetrics, sessions)
+	}))
+	defer server.Close()
+	// Every pod is served by the test server.
+	httpClient := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+		},
+	}}
+
+	newPod := func(name string, ready bool) client.Object {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace, Labels: map[string]string{mcpServerAppLabelKey: mcpServerName}},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				PodIP: "10.0.0.1",
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: "mcp-server", Ready: true},
+					{Name: metricsExporterContainerName, Ready: ready},
+				},
+			},
+		}
+	}
+	cli := fake.NewClientBuilder().WithObjects(newPod("pod-1", true), newPod("pod-2", true), newPod("pod-3", false)).Build()
+	r := &MCPServerReconciler{Client: cli, HTTPClient: httpClient}
+	cr := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpServerName, Namespace: testNamespace},
+		Spec:       mcpserverv1.MCPServerSpec{Image: mcpServerImage, MetricsExporter: &mcpserverv1.MetricsExporter{}},
+	}
+	scrape := func() {
+		t.Helper()
+		if cr.Status.Usage != nil {
+			// Make the next scrape due.
+			cr.Status.Usage.LastScrapeTime = &metav1.Time{Time: time.Now().Add(-defaultUsageInterval)}
+		}
+		if err := r.reconcileUsage(context.Background(), cli, cr); err != nil {
+			t.Fatalf("reconcileUsage() error = %v", err)
+		}
+	}
+
+	// The ready pods are summed up.
+	scrape()
+	usage := cr.Status.Usage
+	if usage == nil || usage.TotalRequests != 10 || usage.ActiveSessions != 0 || usage.LastActivityTime == nil {
+		t.Fatalf("status.usage = %+v, want 10 requests, no sessions and an activity", usage)
+	}
+	firstActivity := usage.LastActivityTime
+
+	// No new requests and no sessions is no activity.
+	scrape()
+	if got := cr.Status.Usage.LastActivityTime; got != firstActivity {
+		t.Errorf("lastActivityTime = %v, want %v", got, firstActivity)
+	}
+
+	// Open sessions are activity.
+	sessions = 1
+	scrape()
+	if got := cr.Status.Usage; got.ActiveSessions != 2 || got.LastActivityTime == firstActivity {
+		t.Errorf("status.usage = %+v, want 2 sessions and a new activity", got)
+	}
+
+	// A failed scrape keeps the previous usage.
+	failing = true
+	scrape()
+	if got := cr.Status.Usage; got.Message == "" || got.TotalRequests != 10 {
+		t.Errorf("status.usage = %+v, want a message and the previous usage", got)
+	}
+
+	// The usage is dropped with the metrics exporter.
+	cr.Spec.MetricsExporter = nil
+	scrape()
+	if cr.Status.Usage != nil {
+		t.Errorf("status.usage = %+v, want nil", cr.Status.Usage)
+	}
+}
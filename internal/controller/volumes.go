@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+const (
+	// ReasonUnknownVolume is set on the Available condition of an MCPServer whose spec.volumeMounts mount volumes
+	// that are not in spec.volumes.
+	ReasonUnknownVolume = "UnknownVolume"
+)
+
+// withVolumes returns volumes with the volumes of vars set, replacing those with the same name. A volume that only
+// differs in the default mode the API server sets is kept, so that it does not cause a patch on every reconcile.
+func withVolumes(volumes []corev1.Volume, vars []corev1.Volume) []corev1.Volume {
+	for _, v := range vars {
+		i := slices.IndexFunc(volumes, func(e corev1.Volume) bool { return e.Name == v.Name })
+		if i < 0 {
+			volumes = append(volumes, v)
+			continue
+		}
+		if !equality.Semantic.DeepEqual(volumes[i], withDefaultMode(v)) {
+			volumes[i] = v
+		}
+	}
+	return volumes
+}
+
+// withDefaultMode returns a copy of volume with the default mode of its files set as the API server does.
+func withDefaultMode(volume corev1.Volume) corev1.Volume {
+	volume = *volume.DeepCopy()
+	switch {
+	case volume.Secret != nil && volume.Secret.DefaultMode == nil:
+		volume.Secret.DefaultMode = ptr.To(corev1.SecretVolumeSourceDefaultMode)
+	case volume.ConfigMap != nil && volume.ConfigMap.DefaultMode == nil:
+		volume.ConfigMap.DefaultMode = ptr.To(corev1.ConfigMapVolumeSourceDefaultMode)
+	case volume.Projected != nil && volume.Projected.DefaultMode == nil:
+		volume.Projected.DefaultMode = ptr.To(corev1.ProjectedVolumeSourceDefaultMode)
+	case volume.DownwardAPI != nil && volume.DownwardAPI.DefaultMode == nil:
+		volume.DownwardAPI.DefaultMode = ptr.To(corev1.DownwardAPIVolumeSourceDefaultMode)
+	}
+	return volume
+}
+
+// withVolumeMounts returns mounts with the mounts of vars set, replacing those at the same path.
+func withVolumeMounts(mounts []corev1.VolumeMount, vars []corev1.VolumeMount) []corev1.VolumeMount {
+	for _, v := range vars {
+		i := slices.IndexFunc(mounts, func(e corev1.VolumeMount) bool { return e.MountPath == v.MountPath })
+		if i < 0 {
+			mounts = append(mounts, v)
+			continue
+		}
+		mounts[i] = v
+	}
+	return mounts
+}
+
+// applyVolumes sets spec.volumes on the pod template of an existing Deployment and spec.volumeMounts on its MCP
+// server container. Like the other settings, volumes and mounts removed from the MCPServer are left in place, as
+// the operator cannot tell them from those added by hand.
+func applyVolumes(deployment *appsv1.Deployment, cr *mcpserverv1.MCPServer) {
+	if len(cr.Spec.Volumes) == 0 && len(cr.Spec.VolumeMounts) == 0 {
+		return
+	}
+	podSpec := &deployment.Spec.Template.Spec
+	podSpec.Volumes = withVolumes(podSpec.Volumes, cr.Spec.Volumes)
+	for i := range podSpec.Containers {
+		if container := &podSpec.Containers[i]; container.Name == "mcp-server" {
+			container.VolumeMounts = withVolumeMounts(container.VolumeMounts, cr.Spec.VolumeMounts)
+		}
+	}
+}
+
+// getVolumeMountsCondition returns the Available condition of an MCPServer whose spec.volumeMounts mount volumes
+// that are not in spec.volumes, or nil when all of them are. The CRD cannot check this itself, as the cost of a
+// validation rule comparing the names of both lists exceeds the budget of the API server.
+func getVolumeMountsCondition(cr *mcpserverv1.MCPServer) *metav1.Condition {
+	var unknown []string
+	for _, mount := range cr.Spec.VolumeMounts {
+		known := slices.ContainsFunc(cr.Spec.Volumes, func(v corev1.Volume) bool { return v.Name == mount.Name })
+		if !known && !slices.Contains(unknown, mount.Name) {
+			unknown = append(unknown, mount.Name)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	return &metav1.Condition{
+		Type:    OverallAvailable,
+		Status:  metav1.ConditionFalse,
+		Reason:  ReasonUnknownVolume,
+		Message: fmt.Sprintf("spec.volumeMounts mount %s, which are not in spec.volumes", strings.Join(unknown, ", ")),
+	}
+}
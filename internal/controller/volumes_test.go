@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+func Test_applyVolumes(t *testing.T) {
+	configMapVolume := func(name string, defaultMode *int32) corev1.Volume {
+		return corev1.Volume{
+			Name: "tools",
+			VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: name},
+				DefaultMode:          defaultMode,
+			}},
+		}
+	}
+	cache := corev1.Volume{Name: cacheVolumeName, VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}
+	kubeconfig := corev1.Volume{
+		Name:         "kubeconfig",
+		VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "kubeconfig"}},
+	}
+	newDeployment := func() *appsv1.Deployment {
+		return &appsv1.Deployment{
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{
+								Name:         "mcp-server",
+								VolumeMounts: []corev1.VolumeMount{{Name: cacheVolumeName, MountPath: "/cache"}},
+							},
+							{Name: metricsExporterContainerName},
+						},
+						Volumes: []corev1.Volume{
+							cache,
+							configMapVolume("tools", ptr.To(corev1.ConfigMapVolumeSourceDefaultMode)),
+						},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		spec        mcpserverv1.MCPServerSpec
+		wantVolumes []corev1.Volume
+		wantMounts  []corev1.VolumeMount
+	}{
+		{
+			name: "Verify that the Deployment is left alone without volumes",
+			wantVolumes: []corev1.Volume{
+				cache,
+				configMapVolume("tools", ptr.To(corev1.ConfigMapVolumeSourceDefaultMode)),
+			},
+			wantMounts: []corev1.VolumeMount{{Name: cacheVolumeName, MountPath: "/cache"}},
+		},
+		{
+			name: "Verify that volumes are added and mounted into the MCP server container",
+			spec: mcpserverv1.MCPServerSpec{
+				Volumes: []corev1.Volume{configMapVolume("tools", nil), kubeconfig},
+				VolumeMounts: []corev1.VolumeMount{
+					{Name: "tools", MountPath: "/etc/tools", ReadOnly: true},
+					{Name: "kubeconfig", MountPath: "/etc/kubeconfig", ReadOnly: true},
+				},
+			},
+			wantVolumes: []corev1.Volume{
+				cache,
+				configMapVolume("tools", ptr.To(corev1.ConfigMapVolumeSourceDefaultMode)),
+				kubeconfig,
+			},
+			wantMounts: []corev1.VolumeMount{
+				{Name: cacheVolumeName, MountPath: "/cache"},
+				{Name: "tools", MountPath: "/etc/tools", ReadOnly: true},
+				{Name: "kubeconfig", MountPath: "/etc/kubeconfig", ReadOnly: true},
+			},
+		},
+		{
+			name: "Verify that a changed volume replaces the one of the Deployment",
+			spec: mcpserverv1.MCPServerSpec{
+				Volumes:      []corev1.Volume{configMapVolume("tools-v2", nil)},
+				VolumeMounts: []corev1.VolumeMount{{Name: "tools", MountPath: "/etc/tools"}},
+			},
+			wantVolumes: []corev1.Volume{cache, configMapVolume("tools-v2", nil)},
+			wantMounts: []corev1.VolumeMount{
+				{Name: cacheVolumeName, MountPath: "/cache"},
+				{Name: "tools", MountPath: "/etc/tools"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deployment := newDeployment()
+			applyVolumes(deployment, &mcpserverv1.MCPServer{Spec: tt.spec})
+
+			podSpec := deployment.Spec.Template.Spec
+			if !reflect.DeepEqual(podSpec.Volumes, tt.wantVolumes) {
+				t.Errorf("volumes = %v, want %v", podSpec.Volumes, tt.wantVolumes)
+			}
+			if got := podSpec.Containers[0].VolumeMounts; !reflect.DeepEqual(got, tt.wantMounts) {
+				t.Errorf("volume mounts = %v, want %v", got, tt.wantMounts)
+			}
+			if got := podSpec.Containers[1].VolumeMounts; got != nil {
+				t.Errorf("volume mounts of the metrics exporter = %v, want none", got)
+			}
+		})
+	}
+}
+
+func Test_getVolumeMountsCondition(t *testing.T) {
+	volumes := []corev1.Volume{{Name: "kubeconfig"}}
+
+	tests := []struct {
+		name    string
+		mounts  []corev1.VolumeMount
+		wantErr bool
+	}{
+		{
+			name: "Verify that an MCP server without volume mounts is accepted",
+		},
+		{
+			name:   "Verify that a mount of a volume of spec.volumes is accepted",
+			mounts: []corev1.VolumeMount{{Name: "kubeconfig", MountPath: "/etc/kubeconfig"}},
+		},
+		{
+			name:    "Verify that a mount of an unknown volume is rejected",
+			mounts:  []corev1.VolumeMount{{Name: "kubeconfig", MountPath: "/etc/kubeconfig"}, {Name: "tools", MountPath: "/tools"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &mcpserverv1.MCPServer{Spec: mcpserverv1.MCPServerSpec{Volumes: volumes, VolumeMounts: tt.mounts}}
+
+			condition := getVolumeMountsCondition(cr)
+			if (condition != nil) != tt.wantErr {
+				t.Errorf("getVolumeMountsCondition() = %v, want a condition %v", condition, tt.wantErr)
+			}
+			if condition != nil && condition.Reason != ReasonUnknownVolume {
+				t.Errorf("getVolumeMountsCondition() reason = %s, want %s", condition.Reason, ReasonUnknownVolume)
+			}
+		})
+	}
+}
@@ -0,0 +1,356 @@
+// Package discovery serves the discovery API of the operator: a list of the ready MCPServers the caller is
+// allowed to get, with their URL and tools, as JSON or as a stream of server-sent events. It is the integration
+// point for dashboards, the console plugin and agent frameworks that need to find MCP servers in the cluster.
+package discovery
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/internal/accessreview"
+	"github.com/opendatahub-io/mcp-server-operator/internal/controller"
+)
+
+const (
+	// Path is where the list of MCPServers is served.
+	Path = "/api/v1/mcpservers"
+	// AllParameter is the query parameter that lists the MCPServers that are not ready as well, e.g. ?all=true.
+	AllParameter = "all"
+
+	// DescriptionAnnotation holds the human readable description of an MCPServer without spec.description.
+	DescriptionAnnotation = mcpserverv1.DescriptionAnnotation
+)
+
+var (
+	// streamInterval is how often the list is refreshed for clients of the event stream.
+	streamInterval = 5 * time.Second
+	// streamReviewInterval is how often the token of an event stream is authenticated again and the access decisions
+	// of its user are reviewed again. In between, the list is refreshed from the decisions already made.
+	streamReviewInterval = time.Minute
+)
+
+// +kubebuilder:rbac:groups="authentication.k8s.io",resources=tokenreviews,verbs=create
+// +kubebuilder:rbac:groups="authorization.k8s.io",resources=subjectaccessreviews,verbs=create
+
+// MCPServer is an entry of the discovery API.
+type MCPServer struct {
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace"`
+	DisplayName string `json:"displayName,omitempty"`
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url"`
+	// Ready tells whether the MCPServer is available, which only MCPServers listed with ?all=true may not be.
+	Ready bool `json:"ready"`
+	// Message is the message of the Available condition of an MCPServer that is not ready.
+	Message string `json:"message,omitempty"`
+	// Endpoints are all the ways to reach the MCP server, see the status of the MCPServer.
+	Endpoints []mcpserverv1.Endpoint `json:"endpoints,omitempty"`
+	// Tools is the number of tools the operator last listed, or found by the last connection test.
+	Tools *int32 `json:"tools,omitempty"`
+	// ToolNames are the names of the tools the operator last listed.
+	ToolNames []string `json:"toolNames,omitempty"`
+}
+
+// List is the response of the discovery API.
+type List struct {
+	Items []MCPServer `json:"items"`
+}
+
+// Routes are further endpoints served next to the discovery API, such as the REST API.
+type Routes interface {
+	Register(mux *http.ServeMux)
+}
+
+// Server serves the discovery API over HTTPS. It implements manager.Runnable.
+type Server struct {
+	// Reader lists the MCPServers, usually from the manager's cache.
+	Reader client.Reader
+	// Reviewer authenticates and authorizes the callers.
+	Reviewer *accessreview.Reviewer
+
+	// BindAddress is the address the server listens on.
+	BindAddress string
+	// CertDir is the directory holding tls.crt and tls.key. A self-signed certificate is generated when empty.
+	CertDir string
+	// TLSOpts are applied to the TLS configuration of the server.
+	TLSOpts []func(*tls.Config)
+
+	// Extensions are served on the same port.
+	Extensions []Routes
+}
+
+// Start serves the discovery API until ctx is done.
+func (s *Server) Start(ctx context.Context) error {
+	logger := logf.FromContext(ctx).WithName("discovery")
+
+	tlsConfig, err := s.tlsConfig(ctx)
+	if err != nil {
+		return err
+	}
+	listener, err := tls.Listen("tcp", s.BindAddress, tlsConfig)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	logger.Info("Serving the discovery API", "address", s.BindAddress, "path", Path)
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable; every replica serves the discovery API.
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+func (s *Server) tlsConfig(ctx context.Context) (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if s.CertDir != "" {
+		watcher, err := certwatcher.New(filepath.Join(s.CertDir, "tls.crt"), filepath.Join(s.CertDir, "tls.key"))
+		if err != nil {
+			return nil, err
+		}
+		go func() {
+			_ = watcher.Start(ctx)
+		}()
+		config.GetCertificate = watcher.GetCertificate
+	} else {
+		certPEM, keyPEM, err := certutil.GenerateSelfSignedCertKey("mcp-server-operator-discovery", nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+
+	for _, opt := range s.TLSOpts {
+		opt(config)
+	}
+	return config, nil
+}
+
+// Handler returns the handler of the discovery API.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+Path, s.serveList)
+	for _, routes := range s.Extensions {
+		routes.Register(mux)
+	}
+	return mux
+}
+
+func (s *Server) serveList(w http.ResponseWriter, r *http.Request) {
+	user := s.Reviewer.AuthenticateRequest(w, r)
+	if user == nil {
+		return
+	}
+
+	all := r.URL.Query().Get(AllParameter) == "true"
+	if strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		s.stream(w, r, user, all)
+		return
+	}
+
+	list, err := s.list(r.Context(), user, all, accessDecisions{})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("unable to list MCPServers: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(list)
+}
+
+// stream sends the list as an mcpservers event, and again whenever it changes. The access decisions of user are
+// remembered for the stream and dropped every streamReviewInterval, when the token is authenticated again; the stream
+// ends with an error event once the token is no longer valid.
+func (s *Server) stream(w http.ResponseWriter, r *http.Request, user *authenticationv1.UserInfo, all bool) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	sendError := func(err error) {
+		_, _ = fmt.Fprintf(w, "event: error\ndata: %s\n\n", strings.ReplaceAll(err.Error(), "\n", " "))
+		flusher.Flush()
+	}
+
+	ticker := time.NewTicker(streamInterval)
+	defer ticker.Stop()
+	review := time.NewTicker(streamReviewInterval)
+	defer review.Stop()
+
+	decisions := accessDecisions{}
+	var previous []byte
+	for {
+		list, err := s.list(r.Context(), user, all, decisions)
+		if err != nil {
+			sendError(err)
+			return
+		}
+		data, err := json.Marshal(list)
+		if err != nil {
+			return
+		}
+		if !bytes.Equal(data, previous) {
+			if _, err := fmt.Fprintf(w, "event: mcpservers\ndata: %s\n\n", data); err != nil {
+				return
+			}
+			flusher.Flush()
+			previous = data
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		case <-review.C:
+			token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			user, err = s.Reviewer.Authenticate(r.Context(), token)
+			if err != nil {
+				sendError(fmt.Errorf("unable to authenticate the token: %w", err))
+				return
+			}
+			if user == nil {
+				sendError(errors.New("the token is no longer valid"))
+				return
+			}
+			decisions = accessDecisions{}
+		}
+	}
+}
+
+// accessDecisions remembers whether a user is allowed to list or get MCPServers, keyed by the verb, namespace and
+// name, so that the same SubjectAccessReview is not created again while the decisions are kept.
+type accessDecisions map[string]bool
+
+// allowed reports whether user may perform verb on the MCPServers of namespace, or on the one named name, reviewing
+// it only when decisions does not hold it yet.
+func (s *Server) allowed(ctx context.Context, user *authenticationv1.UserInfo, decisions accessDecisions,
+	namespace, verb, name string) (bool, error) {
+	key := verb + "/" + namespace + "/" + name
+	if allowed, known := decisions[key]; known {
+		return allowed, nil
+	}
+	allowed, err := s.Reviewer.Allowed(ctx, user, resourceAttributes(namespace, verb, name))
+	if err != nil {
+		return false, err
+	}
+	decisions[key] = allowed
+	return allowed, nil
+}
+
+// list returns the ready MCPServers user is allowed to get, or all of them when all is set. Users allowed to list
+// the MCPServers of a namespace see all of them, others only the MCPServers they may get individually. The access
+// decisions are taken from, and added to, decisions.
+func (s *Server) list(ctx context.Context, user *authenticationv1.UserInfo, all bool,
+	decisions accessDecisions) (*List, error) {
+	servers := &mcpserverv1.MCPServerList{}
+	if err := s.Reader.List(ctx, servers); err != nil {
+		return nil, err
+	}
+
+	list := &List{Items: []MCPServer{}}
+	for i := range servers.Items {
+		cr := &servers.Items[i]
+		ready := cr.Status.URL != "" && meta.IsStatusConditionTrue(cr.Status.Conditions, controller.OverallAvailable)
+		if !ready && !all {
+			continue
+		}
+
+		allowed, err := s.allowed(ctx, user, decisions, cr.Namespace, "list", "")
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			allowed, err = s.allowed(ctx, user, decisions, cr.Namespace, "get", cr.Name)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if !allowed {
+			continue
+		}
+
+		entry := MCPServer{
+			Name:        cr.Name,
+			Namespace:   cr.Namespace,
+			DisplayName: controller.DisplayName(cr),
+			Description: controller.Description(cr),
+			URL:         cr.Status.URL,
+			Ready:       ready,
+			Endpoints:   cr.Status.Endpoints,
+		}
+		if available := meta.FindStatusCondition(cr.Status.Conditions, controller.OverallAvailable); !ready &&
+			available != nil {
+			entry.Message = available.Message
+		}
+		if cr.Status.ToolsRefresh != nil && cr.Status.ToolsRefresh.LastRefreshTime != nil {
+			entry.Tools = ptr.To(int32(len(cr.Status.Tools)))
+			for _, tool := range cr.Status.Tools {
+				entry.ToolNames = append(entry.ToolNames, tool.Name)
+			}
+		} else if cr.Status.ConnectionTest != nil {
+			entry.Tools = cr.Status.ConnectionTest.Tools
+		}
+		list.Items = append(list.Items, entry)
+	}
+
+	sort.Slice(list.Items, func(i, j int) bool {
+		if list.Items[i].Namespace != list.Items[j].Namespace {
+			return list.Items[i].Namespace < list.Items[j].Namespace
+		}
+		return list.Items[i].Name < list.Items[j].Name
+	})
+	return list, nil
+}
+
+func resourceAttributes(namespace, verb, name string) authorizationv1.ResourceAttributes {
+	return authorizationv1.ResourceAttributes{
+		Namespace: namespace,
+		Verb:      verb,
+		Group:     mcpserverv1.GroupVersion.Group,
+		Resource:  "mcpservers",
+		Name:      name,
+	}
+}
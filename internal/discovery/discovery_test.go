@@ -0,0 +1,238 @@
+package discovery
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/internal/accessreview"
+	"github.com/opendatahub-io/mcp-server-operator/internal/controller"
+)
+
+func newMCPServer(namespace, name string, ready bool) *mcpserverv1.MCPServer {
+	status := metav1.ConditionFalse
+	if ready {
+		status = metav1.ConditionTrue
+	}
+	return &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Annotations: map[string]string{DescriptionAnnotation: "The " + name + " server"},
+		},
+		Status: mcpserverv1.MCPServerStatus{
+			URL: "http://" + name + "." + namespace + ".svc:8000/sse",
+			Endpoints: []mcpserverv1.Endpoint{{
+				Type:      mcpserverv1.EndpointService,
+				URL:       "http://" + name + "." + namespace + ".svc:8000/sse",
+				Scheme:    "http",
+				Host:      name + "." + namespace + ".svc:8000",
+				Path:      "/sse",
+				Transport: mcpserverv1.TransportSSE,
+			}},
+			Conditions: []metav1.Condition{{Type: controller.OverallAvailable, Status: status}},
+			ConnectionTest: &mcpserverv1.ConnectionTestStatus{
+				Result: mcpserverv1.ConnectionTestSucceeded,
+				Tools:  ptr.To[int32](3),
+			},
+		},
+	}
+}
+
+func TestServer_Handler(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := mcpserverv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add mcpserverv1 scheme: %v", err)
+	}
+	objects := []client.Object{
+		newMCPServer("team-a", "one", true),
+		newMCPServer("team-a", "two", false),
+		newMCPServer("team-b", "visible", true),
+		newMCPServer("team-b", "hidden", true),
+	}
+	reader := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+
+	// alice may list the MCPServers of team-a and get team-b/visible.
+	clientset := fake.NewClientset()
+	clientset.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		if review.Spec.Token == "alice" {
+			review.Status.Authenticated = true
+			review.Status.User = authenticationv1.UserInfo{Username: "alice"}
+		}
+		return true, review, nil
+	})
+	clientset.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		attributes := review.Spec.ResourceAttributes
+		review.Status.Allowed = attributes.Namespace == "team-a" && attributes.Verb == "list" ||
+			attributes.Namespace == "team-b" && attributes.Verb == "get" && attributes.Name == "visible"
+		return true, review, nil
+	})
+
+	server := &Server{Reader: reader, Reviewer: &accessreview.Reviewer{Clientset: clientset}}
+
+	tests := []struct {
+		name       string
+		token      string
+		query      string
+		wantStatus int
+		wantNames  []string
+	}{
+		{
+			name:       "missing token",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "invalid token",
+			token:      "mallory",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "ready MCPServers the caller may get",
+			token:      "alice",
+			wantStatus: http.StatusOK,
+			wantNames:  []string{"team-a/one", "team-b/visible"},
+		},
+		{
+			name:       "all MCPServers the caller may get",
+			token:      "alice",
+			query:      "?" + AllParameter + "=true",
+			wantStatus: http.StatusOK,
+			wantNames:  []string{"team-a/one", "team-a/two", "team-b/visible"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, Path+tt.query, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			server.Handler().ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			list := &List{}
+			if err := json.Unmarshal(rec.Body.Bytes(), list); err != nil {
+				t.Fatalf("failed to decode the response: %v", err)
+			}
+			var names []string
+			for _, item := range list.Items {
+				names = append(names, item.Namespace+"/"+item.Name)
+				if item.Tools == nil || *item.Tools != 3 || item.Description == "" || item.URL == "" ||
+					len(item.Endpoints) != 1 {
+					t.Errorf("incomplete entry %+v", item)
+				}
+				if item.Ready != (item.Name != "two") {
+					t.Errorf("entry %s ready = %v", item.Name, item.Ready)
+				}
+			}
+			if !reflect.DeepEqual(names, tt.wantNames) {
+				t.Errorf("MCPServers = %v, want %v", names, tt.wantNames)
+			}
+		})
+	}
+}
+
+func TestServer_stream(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := mcpserverv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add mcpserverv1 scheme: %v", err)
+	}
+	reader := fakeclient.NewClientBuilder().WithScheme(scheme).
+		WithObjects(newMCPServer("team-a", "one", true), newMCPServer("team-b", "two", true)).Build()
+
+	var revoked atomic.Bool
+	var reviews atomic.Int32
+	clientset := fake.NewClientset()
+	clientset.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		if review.Spec.Token == "alice" && !revoked.Load() {
+			review.Status.Authenticated = true
+			review.Status.User = authenticationv1.UserInfo{Username: "alice"}
+		}
+		return true, review, nil
+	})
+	clientset.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		reviews.Add(1)
+		review.Status.Allowed = review.Spec.ResourceAttributes.Namespace == "team-a"
+		return true, review, nil
+	})
+
+	defer func(interval, reviewInterval time.Duration) {
+		streamInterval, streamReviewInterval = interval, reviewInterval
+	}(streamInterval, streamReviewInterval)
+	streamInterval, streamReviewInterval = 10*time.Millisecond, 200*time.Millisecond
+
+	server := httptest.NewServer((&Server{Reader: reader, Reviewer: &accessreview.Reviewer{Clientset: clientset}}).Handler())
+	defer server.Close()
+	req, err := http.NewRequest(http.MethodGet, server.URL+Path, nil)
+	if err != nil {
+		t.Fatalf("failed to create the request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer alice")
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to open the stream: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	events := make(chan string)
+	go func() {
+		defer close(events)
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if event, ok := strings.CutPrefix(scanner.Text(), "event: "); ok {
+				events <- event
+			}
+		}
+	}()
+
+	if event := <-events; event != "mcpservers" {
+		t.Fatalf("first event = %q, want mcpservers", event)
+	}
+	// The list is refreshed many times before the review interval without reviewing the access of alice again:
+	// one list review for each namespace and one get review for team-b/two.
+	time.Sleep(100 * time.Millisecond)
+	if got := reviews.Load(); got != 3 {
+		t.Errorf("SubjectAccessReviews = %d before the review interval, want 3", got)
+	}
+
+	revoked.Store(true)
+	select {
+	case event := <-events:
+		if event != "error" {
+			t.Errorf("event after revoking the token = %q, want error", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the stream did not end after revoking the token")
+	}
+	if event, ok := <-events; ok {
+		t.Errorf("unexpected event %q after the error", event)
+	}
+}
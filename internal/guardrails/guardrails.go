@@ -0,0 +1,437 @@
+// Package guardrails implements the guardrails subcommand of the manager binary. It runs as a sidecar in front
+// of MCP servers that set spec.guardrails: the arguments of tool calls and the content of tool results are
+// checked by a guardrails detection service, such as the TrustyAI guardrails orchestrator, and flagged text is
+// blocked, redacted or only logged according to the policy of the MCPServer. When the detection service cannot
+// be reached, tool calls are rejected and tool results are withheld.
+package guardrails
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/internal/jsonrpc"
+)
+
+const (
+	// Command is the name of the subcommand.
+	Command = "guardrails"
+
+	// TokenEnv is the environment variable holding the bearer token sent to the detection service.
+	TokenEnv = "GUARDRAILS_TOKEN"
+
+	// HealthPath is where the filter reports on its health port whether the detection service is healthy.
+	HealthPath = "/healthz"
+
+	// maxMessageSize caps the size of the JSON-RPC messages that are inspected.
+	maxMessageSize = 10 << 20
+
+	// redacted replaces the flagged text.
+	redacted = "[REDACTED]"
+
+	// blockedErrorCode is the JSON-RPC error code of rejected tool calls.
+	blockedErrorCode = -32001
+)
+
+// Detection is a span of text flagged by a detector. Start and End count characters.
+type Detection struct {
+	Start         int     `json:"start"`
+	End           int     `json:"end"`
+	DetectorID    string  `json:"detector_id,omitempty"`
+	DetectionType string  `json:"detection_type,omitempty"`
+	Detection     string  `json:"detection,omitempty"`
+	Score         float64 `json:"score,omitempty"`
+}
+
+// Detector checks text for content that violates a policy.
+type Detector interface {
+	Detect(ctx context.Context, text string) ([]Detection, error)
+}
+
+// Run starts the filter with the given arguments and returns the process exit code.
+func Run(args []string) int {
+	fs := flag.NewFlagSet(Command, flag.ContinueOnError)
+	upstream := fs.String("upstream", "http://localhost:8000", "The URL of the MCP server the filter runs in front of.")
+	port := fs.Int("port", 8080, "The port the filter listens on.")
+	healthPort := fs.Int("health-port", 8081, "The port the health of the filter is served on.")
+	detectionURL := fs.String("url", "", "The URL of the guardrails detection service.")
+	var detectors []string
+	fs.Func("detector", "The name of a detector to run, can be repeated.", func(name string) error {
+		detectors = append(detectors, name)
+		return nil
+	})
+	input := fs.String("input", string(mcpserverv1.GuardrailsBlock), "The action for flagged tool calls: Block, Redact or Audit.")
+	output := fs.String("output", string(mcpserverv1.GuardrailsRedact), "The action for flagged tool results: Block, Redact or Audit.")
+	tokenFile := fs.String("token-file", "",
+		"A file holding the bearer token sent to the detection service, used instead of the "+TokenEnv+" environment variable.")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *detectionURL == "" || len(detectors) == 0 {
+		_, _ = fmt.Fprintln(os.Stderr, "--url and at least one --detector are required")
+		return 2
+	}
+	for _, action := range []string{*input, *output} {
+		if !validAction(mcpserverv1.GuardrailsAction(action)) {
+			_, _ = fmt.Fprintf(os.Stderr, "invalid action %q, must be Block, Redact or Audit\n", action)
+			return 2
+		}
+	}
+	upstreamURL, err := url.Parse(*upstream)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "invalid --upstream: %v\n", err)
+		return 2
+	}
+	orchestratorURL, err := url.Parse(*detectionURL)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "invalid --url: %v\n", err)
+		return 2
+	}
+
+	token := os.Getenv(TokenEnv)
+	if *tokenFile != "" {
+		data, err := os.ReadFile(*tokenFile)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "unable to read the token: %v\n", err)
+			return 2
+		}
+		token = strings.TrimSpace(string(data))
+	}
+
+	orchestrator := &Orchestrator{
+		URL:        orchestratorURL,
+		Detectors:  detectors,
+		Token:      token,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+	filter := &Filter{
+		Detector: orchestrator,
+		Input:    mcpserverv1.GuardrailsAction(*input),
+		Output:   mcpserverv1.GuardrailsAction(*output),
+	}
+
+	health := http.NewServeMux()
+	health.HandleFunc("GET "+HealthPath, func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+		if err := orchestrator.Healthy(ctx); err != nil {
+			http.Error(w, fmt.Sprintf("the detection service is not healthy: %v", err), http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = fmt.Fprintln(w, "ok")
+	})
+	healthServer := &http.Server{
+		Addr:              fmt.Sprintf(":%d", *healthPort),
+		Handler:           health,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		if err := healthServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			_, _ = fmt.Fprintf(os.Stderr, "health server failed: %v\n", err)
+			os.Exit(1)
+		}
+	}()
+
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", *port),
+		Handler:           New(upstreamURL, filter),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	_, _ = fmt.Fprintf(os.Stdout, "filtering port %d to %s with %s\n", *port, upstreamURL.Redacted(), orchestratorURL.Redacted())
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		_, _ = fmt.Fprintf(os.Stderr, "guardrails filter failed: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func validAction(action mcpserverv1.GuardrailsAction) bool {
+	switch action {
+	case mcpserverv1.GuardrailsBlock, mcpserverv1.GuardrailsRedact, mcpserverv1.GuardrailsAudit:
+		return true
+	}
+	return false
+}
+
+// Filter applies the guardrails policy of an MCP server to its tool traffic.
+type Filter struct {
+	Detector Detector
+	// Input is the action for flagged tool call arguments.
+	Input mcpserverv1.GuardrailsAction
+	// Output is the action for flagged tool results.
+	Output mcpserverv1.GuardrailsAction
+}
+
+// New returns a handler that forwards requests to upstream after filtering the tool calls they carry, and
+// filters the tool results of the responses, whether they are plain JSON or server-sent events.
+func New(upstream *url.URL, filter *Filter) http.Handler {
+	reverseProxy := &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.SetURL(upstream)
+			// Responses are inspected, so they must not be compressed.
+			r.Out.Header.Del("Accept-Encoding")
+		},
+		ModifyResponse: filter.filterResponse,
+		// SSE responses must reach the client as they are written.
+		FlushInterval: -1,
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && jsonrpc.IsJSON(r.Header.Get("Content-Type")) {
+			if !filter.filterRequest(w, r) {
+				return
+			}
+		}
+		reverseProxy.ServeHTTP(w, r)
+	})
+}
+
+// filterRequest applies the input policy to the tool calls in the body of r. When a tool call is rejected an
+// error response is written to w and false is returned.
+func (f *Filter) filterRequest(w http.ResponseWriter, r *http.Request) bool {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxMessageSize))
+	if err != nil {
+		code := http.StatusBadRequest
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			code = http.StatusRequestEntityTooLarge
+		}
+		http.Error(w, fmt.Sprintf("unable to read the request: %v", err), code)
+		return false
+	}
+
+	messages, batch, err := jsonrpc.DecodeMessages(body)
+	if err != nil {
+		// Not JSON-RPC, let the MCP server answer it.
+		jsonrpc.RestoreBody(r, body)
+		return true
+	}
+
+	changed := false
+	for _, msg := range messages {
+		if msg["method"] != "tools/call" {
+			continue
+		}
+		params, _ := msg["params"].(map[string]any)
+		if params == nil {
+			continue
+		}
+
+		arguments, detections, err := f.inspect(r.Context(), params["arguments"], f.Input == mcpserverv1.GuardrailsRedact)
+		if err != nil {
+			writeRPCError(w, http.StatusServiceUnavailable, msg["id"],
+				fmt.Sprintf("the tool call could not be checked by guardrails: %v", err))
+			return false
+		}
+		if len(detections) == 0 {
+			continue
+		}
+
+		log.Printf("guardrails flagged the arguments of tool %v (%s): %s", params["name"], f.Input, summarize(detections))
+		switch f.Input {
+		case mcpserverv1.GuardrailsBlock:
+			writeRPCError(w, http.StatusForbidden, msg["id"],
+				fmt.Sprintf("the tool call was blocked by guardrails: %s", summarize(detections)))
+			return false
+		case mcpserverv1.GuardrailsRedact:
+			params["arguments"] = arguments
+			changed = true
+		}
+	}
+
+	if changed {
+		if body, err = jsonrpc.EncodeMessages(messages, batch); err != nil {
+			http.Error(w, fmt.Sprintf("unable to encode the request: %v", err), http.StatusInternalServerError)
+			return false
+		}
+	}
+	jsonrpc.RestoreBody(r, body)
+	return true
+}
+
+// filterResponse applies the output policy to the tool results in resp.
+func (f *Filter) filterResponse(resp *http.Response) error {
+	contentType := resp.Header.Get("Content-Type")
+	switch {
+	case strings.HasPrefix(contentType, "text/event-stream"):
+		ctx := resp.Request.Context()
+		resp.Body = jsonrpc.FilterEventStream(resp.Body, func(data []byte) []byte { return f.filterResults(ctx, data) })
+	case jsonrpc.IsJSON(contentType):
+		data, err := io.ReadAll(io.LimitReader(resp.Body, maxMessageSize+1))
+		_ = resp.Body.Close()
+		if err != nil {
+			return err
+		}
+		if len(data) > maxMessageSize {
+			return fmt.Errorf("the response exceeds %d bytes", maxMessageSize)
+		}
+		data = f.filterResults(resp.Request.Context(), data)
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+		resp.ContentLength = int64(len(data))
+		resp.Header.Set("Content-Length", strconv.Itoa(len(data)))
+	}
+	return nil
+}
+
+// filterResults applies the output policy to the tool results among the JSON-RPC messages in data and returns
+// the messages to send to the client. Tool results that cannot be checked are withheld.
+func (f *Filter) filterResults(ctx context.Context, data []byte) []byte {
+	messages, batch, err := jsonrpc.DecodeMessages(data)
+	if err != nil {
+		return data
+	}
+
+	changed := false
+	for _, msg := range messages {
+		result, _ := msg["result"].(map[string]any)
+		if _, ok := result["content"].([]any); !ok {
+			// Only the results of tool calls have a content list.
+			continue
+		}
+
+		filtered, detections, err := f.inspect(ctx, result, f.Output == mcpserverv1.GuardrailsRedact)
+		if err != nil {
+			log.Printf("guardrails could not check a tool result: %v", err)
+			msg["result"] = errorResult(fmt.Sprintf("The tool result could not be checked by guardrails: %v", err))
+			changed = true
+			continue
+		}
+		if len(detections) == 0 {
+			continue
+		}
+
+		log.Printf("guardrails flagged a tool result (%s): %s", f.Output, summarize(detections))
+		switch f.Output {
+		case mcpserverv1.GuardrailsBlock:
+			msg["result"] = errorResult(fmt.Sprintf("The tool result was blocked by guardrails: %s", summarize(detections)))
+			changed = true
+		case mcpserverv1.GuardrailsRedact:
+			msg["result"] = filtered
+			changed = true
+		}
+	}
+
+	if !changed {
+		return data
+	}
+	encoded, err := jsonrpc.EncodeMessages(messages, batch)
+	if err != nil {
+		return data
+	}
+	return encoded
+}
+
+// inspect runs the detector on every string in value. When redact is set, the returned value has the flagged
+// text replaced, otherwise it is value itself.
+func (f *Filter) inspect(ctx context.Context, value any, redact bool) (any, []Detection, error) {
+	switch v := value.(type) {
+	case string:
+		detections, err := f.Detector.Detect(ctx, v)
+		if err != nil || len(detections) == 0 || !redact {
+			return v, detections, err
+		}
+		return redactText(v, detections), detections, nil
+	case map[string]any:
+		var all []Detection
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			filtered, detections, err := f.inspect(ctx, item, redact)
+			if err != nil {
+				return nil, nil, err
+			}
+			out[key] = filtered
+			all = append(all, detections...)
+		}
+		return out, all, nil
+	case []any:
+		var all []Detection
+		out := make([]any, len(v))
+		for i, item := range v {
+			filtered, detections, err := f.inspect(ctx, item, redact)
+			if err != nil {
+				return nil, nil, err
+			}
+			out[i] = filtered
+			all = append(all, detections...)
+		}
+		return out, all, nil
+	default:
+		return v, nil, nil
+	}
+}
+
+// redactText replaces the flagged spans of text. A detection without a span flags the whole text.
+func redactText(text string, detections []Detection) string {
+	runes := []rune(text)
+	sorted := append([]Detection(nil), detections...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	var b strings.Builder
+	pos := 0
+	for _, d := range sorted {
+		start, end := d.Start, d.End
+		if end <= start {
+			return redacted
+		}
+		end = min(end, len(runes))
+		if start < pos {
+			// Overlaps the previous span, which is extended.
+			pos = max(pos, end)
+			continue
+		}
+		start = min(start, len(runes))
+		b.WriteString(string(runes[pos:start]))
+		b.WriteString(redacted)
+		pos = end
+	}
+	b.WriteString(string(runes[pos:]))
+	return b.String()
+}
+
+// summarize describes detections without the flagged text, which must not end up in the logs.
+func summarize(detections []Detection) string {
+	seen := map[string]bool{}
+	var kinds []string
+	for _, d := range detections {
+		kind := d.DetectorID
+		if d.DetectionType != "" {
+			kind = fmt.Sprintf("%s/%s", kind, d.DetectionType)
+		}
+		if !seen[kind] {
+			seen[kind] = true
+			kinds = append(kinds, kind)
+		}
+	}
+	return strings.Join(kinds, ", ")
+}
+
+func errorResult(text string) map[string]any {
+	return map[string]any{
+		"content": []any{map[string]any{"type": "text", "text": text}},
+		"isError": true,
+	}
+}
+
+func writeRPCError(w http.ResponseWriter, code int, id any, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"error":   map[string]any{"code": blockedErrorCode, "message": message},
+	})
+}
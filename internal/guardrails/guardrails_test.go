@@ -0,0 +1,189 @@
+package guardrails
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// secretDetector flags every occurrence of "secret".
+type secretDetector struct {
+	err error
+}
+
+func (d secretDetector) Detect(_ context.Context, text string) ([]Detection, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	var detections []Detection
+	runes := []rune(text)
+	word := []rune("secret")
+	for i := 0; i+len(word) <= len(runes); i++ {
+		if string(runes[i:i+len(word)]) == string(word) {
+			detections = append(detections, Detection{Start: i, End: i + len(word), DetectorID: "pii", DetectionType: "secret"})
+		}
+	}
+	return detections, nil
+}
+
+const (
+	toolCall   = `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo","arguments":{"text":"my secret"}}}`
+	toolResult = `{"jsonrpc":"2.0","id":1,"result":{"content":[{"type":"text","text":"the secret is 42"}]}}`
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name         string
+		filter       Filter
+		request      string
+		response     string
+		contentType  string
+		wantStatus   int
+		wantUpstream string
+		wantBody     string
+	}{
+		{
+			name:        "blocked tool call",
+			filter:      Filter{Detector: secretDetector{}, Input: mcpserverv1.GuardrailsBlock},
+			request:     toolCall,
+			contentType: "application/json",
+			wantStatus:  http.StatusForbidden,
+			wantBody:    "blocked by guardrails: pii/secret",
+		},
+		{
+			name:         "redacted tool call",
+			filter:       Filter{Detector: secretDetector{}, Input: mcpserverv1.GuardrailsRedact, Output: mcpserverv1.GuardrailsAudit},
+			request:      toolCall,
+			response:     `{"jsonrpc":"2.0","id":1,"result":{}}`,
+			contentType:  "application/json",
+			wantStatus:   http.StatusOK,
+			wantUpstream: `"text":"my [REDACTED]"`,
+		},
+		{
+			name:        "tool call that cannot be checked",
+			filter:      Filter{Detector: secretDetector{err: errors.New("unavailable")}, Input: mcpserverv1.GuardrailsAudit},
+			request:     toolCall,
+			contentType: "application/json",
+			wantStatus:  http.StatusServiceUnavailable,
+			wantBody:    "could not be checked",
+		},
+		{
+			name:         "other requests are forwarded unchanged",
+			filter:       Filter{Detector: secretDetector{}, Input: mcpserverv1.GuardrailsBlock},
+			request:      `{"jsonrpc":"2.0","id":2,"method":"prompts/get","params":{"name":"secret"}}`,
+			response:     `{"jsonrpc":"2.0","id":2,"result":{"messages":[]}}`,
+			contentType:  "application/json",
+			wantStatus:   http.StatusOK,
+			wantUpstream: `"name":"secret"`,
+		},
+		{
+			name:        "redacted tool result",
+			filter:      Filter{Detector: secretDetector{}, Input: mcpserverv1.GuardrailsBlock, Output: mcpserverv1.GuardrailsRedact},
+			request:     `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"answer"}}`,
+			response:    toolResult,
+			contentType: "application/json",
+			wantStatus:  http.StatusOK,
+			wantBody:    `"text":"the [REDACTED] is 42"`,
+		},
+		{
+			name:        "blocked tool result in an event stream",
+			filter:      Filter{Detector: secretDetector{}, Input: mcpserverv1.GuardrailsBlock, Output: mcpserverv1.GuardrailsBlock},
+			request:     `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"answer"}}`,
+			response:    "event: message\ndata: " + toolResult + "\n\n",
+			contentType: "text/event-stream",
+			wantStatus:  http.StatusOK,
+			wantBody:    `"isError":true`,
+		},
+		{
+			name:        "tool result that cannot be checked is withheld",
+			filter:      Filter{Detector: secretDetector{err: errors.New("unavailable")}, Output: mcpserverv1.GuardrailsAudit},
+			request:     `{"jsonrpc":"2.0","id":1,"method":"initialize"}`,
+			response:    toolResult,
+			contentType: "application/json",
+			wantStatus:  http.StatusOK,
+			wantBody:    "could not be checked by guardrails",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUpstream string
+			upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				gotUpstream = string(body)
+				w.Header().Set("Content-Type", tt.contentType)
+				_, _ = io.WriteString(w, tt.response)
+			}))
+			defer upstream.Close()
+			upstreamURL, err := url.Parse(upstream.URL)
+			if err != nil {
+				t.Fatalf("failed to parse the upstream URL: %v", err)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(tt.request))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			New(upstreamURL, &tt.filter).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if !strings.Contains(gotUpstream, tt.wantUpstream) {
+				t.Errorf("upstream received %s, want it to contain %s", gotUpstream, tt.wantUpstream)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body = %s, want it to contain %s", rec.Body.String(), tt.wantBody)
+			}
+			if strings.Contains(rec.Body.String(), "the secret is") && tt.filter.Output != mcpserverv1.GuardrailsAudit {
+				t.Errorf("body = %s, the flagged text was not filtered", rec.Body.String())
+			}
+		})
+	}
+}
+
+func Test_redactText(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		detections []Detection
+		want       string
+	}{
+		{
+			name:       "spans",
+			text:       "call 555-1234 or mail a@b.c",
+			detections: []Detection{{Start: 22, End: 27}, {Start: 5, End: 13}},
+			want:       "call [REDACTED] or mail [REDACTED]",
+		},
+		{
+			name:       "overlapping spans",
+			text:       "a secret word",
+			detections: []Detection{{Start: 2, End: 8}, {Start: 4, End: 13}},
+			want:       "a [REDACTED]",
+		},
+		{
+			name:       "characters beyond ASCII",
+			text:       "héllo secret",
+			detections: []Detection{{Start: 6, End: 12}},
+			want:       "héllo [REDACTED]",
+		},
+		{
+			name:       "whole text",
+			text:       "offensive",
+			detections: []Detection{{}},
+			want:       "[REDACTED]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactText(tt.text, tt.detections); got != tt.want {
+				t.Errorf("redactText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
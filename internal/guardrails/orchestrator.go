@@ -0,0 +1,92 @@
+package guardrails
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+const (
+	// detectionPath is the endpoint of the orchestrator that runs detectors on a text.
+	detectionPath = "/api/v2/text/detection/content"
+	// orchestratorHealthPath is the health endpoint of the orchestrator.
+	orchestratorHealthPath = "/health"
+)
+
+// Orchestrator is a Detector backed by the content detection API of the TrustyAI guardrails orchestrator.
+type Orchestrator struct {
+	// URL is the base URL of the orchestrator.
+	URL *url.URL
+	// Detectors are the names of the detectors run on every text.
+	Detectors []string
+	// Token is sent as bearer token when set.
+	Token string
+
+	HTTPClient *http.Client
+}
+
+// Detect implements Detector.
+func (o *Orchestrator) Detect(ctx context.Context, text string) ([]Detection, error) {
+	detectors := map[string]map[string]any{}
+	for _, name := range o.Detectors {
+		detectors[name] = map[string]any{}
+	}
+	body, err := json.Marshal(map[string]any{"detectors": detectors, "content": text})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := o.do(ctx, http.MethodPost, detectionPath, body)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	var result struct {
+		Detections []Detection `json:"detections"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("invalid detection response: %w", err)
+	}
+	return result.Detections, nil
+}
+
+// Healthy returns an error when the orchestrator does not report itself healthy.
+func (o *Orchestrator) Healthy(ctx context.Context) error {
+	resp, err := o.do(ctx, http.MethodGet, orchestratorHealthPath, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// do sends a request to the orchestrator and returns its response when it succeeded.
+func (o *Orchestrator) do(ctx context.Context, method, path string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, o.URL.JoinPath(path).String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if o.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+o.Token)
+	}
+
+	resp, err := o.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("%s %s returned %s: %s", method, path, resp.Status, bytes.TrimSpace(message))
+	}
+	return resp, nil
+}
@@ -0,0 +1,140 @@
+// Package jsonrpc reads and rewrites the JSON-RPC messages of MCP in the HTTP bodies that pass the sidecars of the
+// operator, such as the capability filter, guardrails and metrics exporter: plain JSON bodies holding a message or a
+// batch, and event streams carrying one message in the data of each event.
+package jsonrpc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// DecodeMessages decodes a JSON-RPC message or batch and reports whether data is a batch. Numbers are kept as they
+// are, so that request ids survive a round trip and the ids of requests and responses compare equal.
+func DecodeMessages(data []byte) ([]map[string]any, bool, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var messages []map[string]any
+		err := decoder.Decode(&messages)
+		return messages, true, err
+	}
+	var message map[string]any
+	if err := decoder.Decode(&message); err != nil {
+		return nil, false, err
+	}
+	return []map[string]any{message}, false, nil
+}
+
+// EncodeMessages encodes messages as a batch, or the first of them as a single message when batch is not set.
+func EncodeMessages(messages []map[string]any, batch bool) ([]byte, error) {
+	if batch {
+		return json.Marshal(messages)
+	}
+	return json.Marshal(messages[0])
+}
+
+// RestoreBody makes body the body of r again after it was read, with its length.
+func RestoreBody(r *http.Request, body []byte) {
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.ContentLength = int64(len(body))
+	r.Header.Set("Content-Length", strconv.Itoa(len(body)))
+}
+
+// IsJSON reports whether contentType is application/json, whatever its parameters.
+func IsJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "application/json"
+}
+
+// FilterEventStream returns a body that yields the events of body with the JSON-RPC messages in the data of message
+// events passed through filter. filter returns the data to send instead, which may be data itself, or nil to drop
+// the event. Other events are passed on unchanged.
+func FilterEventStream(body io.ReadCloser, filter func(data []byte) []byte) io.ReadCloser {
+	reader, writer := io.Pipe()
+	go func() {
+		defer func() {
+			_ = body.Close()
+		}()
+		lines := bufio.NewReader(body)
+		var event []string
+		for {
+			line, err := lines.ReadString('\n')
+			if line != "" {
+				event = append(event, line)
+				if strings.TrimRight(line, "\r\n") == "" {
+					if _, werr := io.WriteString(writer, filterEvent(event, filter)); werr != nil {
+						_ = writer.CloseWithError(werr)
+						return
+					}
+					event = nil
+				}
+			}
+			if err != nil {
+				_, _ = io.WriteString(writer, strings.Join(event, ""))
+				_ = writer.CloseWithError(err)
+				return
+			}
+		}
+	}()
+	return &eventStream{PipeReader: reader, body: body}
+}
+
+// eventStream closes the upstream body along with the pipe, so that the goroutine reading it stops.
+type eventStream struct {
+	*io.PipeReader
+	body io.Closer
+}
+
+func (s *eventStream) Close() error {
+	_ = s.body.Close()
+	return s.PipeReader.Close()
+}
+
+// filterEvent returns the lines of a server-sent event with the JSON-RPC message in its data filtered, or an empty
+// string when filter dropped it.
+func filterEvent(lines []string, filter func(data []byte) []byte) string {
+	var data []string
+	for _, line := range lines {
+		trimmed := strings.TrimRight(line, "\r\n")
+		if name, ok := strings.CutPrefix(trimmed, "event:"); ok && strings.TrimSpace(name) != "message" {
+			return strings.Join(lines, "")
+		}
+		if value, ok := strings.CutPrefix(trimmed, "data:"); ok {
+			data = append(data, strings.TrimPrefix(value, " "))
+		}
+	}
+	if len(data) == 0 {
+		return strings.Join(lines, "")
+	}
+
+	original := []byte(strings.Join(data, "\n"))
+	filtered := filter(original)
+	if filtered == nil {
+		return ""
+	}
+	if bytes.Equal(filtered, original) {
+		return strings.Join(lines, "")
+	}
+
+	var event strings.Builder
+	written := false
+	for _, line := range lines {
+		if strings.HasPrefix(line, "data:") {
+			if !written {
+				event.WriteString("data: ")
+				event.Write(filtered)
+				event.WriteString("\n")
+				written = true
+			}
+			continue
+		}
+		event.WriteString(line)
+	}
+	return event.String()
+}
@@ -0,0 +1,87 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestDecodeMessages(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		wantBatch bool
+		wantLen   int
+		wantErr   bool
+	}{
+		{name: "message", data: `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`, wantLen: 1},
+		{name: "batch", data: ` [{"jsonrpc":"2.0","id":1},{"jsonrpc":"2.0","id":2}]`, wantBatch: true, wantLen: 2},
+		{name: "not JSON", data: `initialize`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messages, batch, err := DecodeMessages([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeMessages() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if batch != tt.wantBatch || len(messages) != tt.wantLen {
+				t.Fatalf("DecodeMessages() = %d messages, batch %v, want %d, %v", len(messages), batch, tt.wantLen,
+					tt.wantBatch)
+			}
+			encoded, err := EncodeMessages(messages, batch)
+			if err != nil {
+				t.Fatalf("EncodeMessages() error = %v", err)
+			}
+			if again, _, _ := DecodeMessages(encoded); len(again) != tt.wantLen || again[0]["id"] != messages[0]["id"] {
+				t.Errorf("EncodeMessages() = %s, does not round trip", encoded)
+			}
+		})
+	}
+}
+
+func TestFilterEventStream(t *testing.T) {
+	stream := "event: message\ndata: keep\n\n" +
+		"event: message\ndata: drop\n\n" +
+		"event: message\ndata: rewrite\n\n" +
+		"event: endpoint\ndata: drop\n\n"
+	filter := func(data []byte) []byte {
+		switch string(data) {
+		case "drop":
+			return nil
+		case "rewrite":
+			return []byte("rewritten")
+		}
+		return data
+	}
+
+	body := FilterEventStream(io.NopCloser(bytes.NewReader([]byte(stream))), filter)
+	defer func() {
+		_ = body.Close()
+	}()
+	got, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatalf("reading the stream: %v", err)
+	}
+	want := "event: message\ndata: keep\n\n" +
+		"event: message\ndata: rewritten\n\n" +
+		"event: endpoint\ndata: drop\n\n"
+	if string(got) != want {
+		t.Errorf("FilterEventStream() = %q, want %q", got, want)
+	}
+}
+
+func TestIsJSON(t *testing.T) {
+	for contentType, want := range map[string]bool{
+		"application/json":                true,
+		"application/json; charset=utf-8": true,
+		"text/event-stream":               false,
+		"":                                false,
+	} {
+		if got := IsJSON(contentType); got != want {
+			t.Errorf("IsJSON(%q) = %v, want %v", contentType, got, want)
+		}
+	}
+}
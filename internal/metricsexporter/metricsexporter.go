@@ -0,0 +1,462 @@
+// Package metricsexporter implements the metrics-exporter subcommand of the manager binary. It runs as a
+// sidecar in front of MCP servers that set spec.metricsExporter, typically servers whose images export no
+// metrics of their own, and translates the MCP traffic passing through it into Prometheus metrics: request
+// counts and latencies per method, tool calls per tool and the number of active sessions.
+package metricsexporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/opendatahub-io/mcp-server-operator/internal/jsonrpc"
+)
+
+const (
+	// Command is the name of the subcommand.
+	Command = "metrics-exporter"
+
+	// MetricsPath is where the metrics are served on the metrics port.
+	MetricsPath = "/metrics"
+
+	// HealthPath is where the exporter reports its health on the metrics port.
+	HealthPath = "/healthz"
+
+	// maxMessageSize caps the size of the JSON-RPC messages that are inspected. Larger messages are forwarded
+	// without being counted.
+	maxMessageSize = 10 << 20
+
+	// maxToolLabels caps the number of distinct tool names used as label values, as tool names are chosen by
+	// clients. Further tools are counted as "other".
+	maxToolLabels = 100
+
+	// maxPending caps the requests awaiting a response on an event stream. Requests that are never answered
+	// are dropped once they are older than pendingTimeout.
+	maxPending     = 10000
+	pendingTimeout = 10 * time.Minute
+
+	outcomeSuccess   = "success"
+	outcomeError     = "error"
+	outcomeToolError = "tool_error"
+	otherLabel       = "other"
+)
+
+// methods are the MCP methods used as label values, any other method is counted as "other".
+var methods = map[string]bool{
+	"initialize":               true,
+	"ping":                     true,
+	"tools/list":               true,
+	"tools/call":               true,
+	"resources/list":           true,
+	"resources/read":           true,
+	"resources/templates/list": true,
+	"resources/subscribe":      true,
+	"resources/unsubscribe":    true,
+	"prompts/list":             true,
+	"prompts/get":              true,
+	"completion/complete":      true,
+	"logging/setLevel":         true,
+}
+
+// Run starts the exporter with the given arguments and returns the process exit code.
+func Run(args []string) int {
+	fs := flag.NewFlagSet(Command, flag.ContinueOnError)
+	upstream := fs.String("upstream", "http://localhost:8000", "The URL of the MCP server the exporter runs in front of.")
+	port := fs.Int("port", 8090, "The port the exporter listens on.")
+	metricsPort := fs.Int("metrics-port", 9090, "The port the metrics are served on.")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	upstreamURL, err := url.Parse(*upstream)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "invalid --upstream: %v\n", err)
+		return 2
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	metrics := NewMetrics(registry)
+
+	mux := http.NewServeMux()
+	mux.Handle("GET "+MetricsPath, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	mux.HandleFunc("GET "+HealthPath, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintln(w, "ok")
+	})
+	metricsServer := &http.Server{
+		Addr:              fmt.Sprintf(":%d", *metricsPort),
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			_, _ = fmt.Fprintf(os.Stderr, "metrics server failed: %v\n", err)
+			os.Exit(1)
+		}
+	}()
+
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", *port),
+		Handler:           New(upstreamURL, metrics),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	_, _ = fmt.Fprintf(os.Stdout, "exporting metrics of %s on port %d\n", upstreamURL.Redacted(), *metricsPort)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		_, _ = fmt.Fprintf(os.Stderr, "metrics exporter failed: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// Metrics records the MCP traffic of a server.
+type Metrics struct {
+	requests  *prometheus.CounterVec
+	durations *prometheus.HistogramVec
+	toolCalls *prometheus.CounterVec
+	sessions  prometheus.Gauge
+
+	mu sync.Mutex
+	// pending holds the requests whose response is sent on an event stream, by session and request id.
+	pending map[string]request
+	tools   map[string]bool
+}
+
+// request is a JSON-RPC request awaiting its response.
+type request struct {
+	method string
+	tool   string
+	start  time.Time
+}
+
+// NewMetrics returns Metrics registered with registerer.
+func NewMetrics(registerer prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "mcp_requests_total",
+			Help: "Number of MCP requests answered by the server, by method and outcome.",
+		}, []string{"method", "outcome"}),
+		durations: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "mcp_request_duration_seconds",
+			Help:    "Time the server took to answer MCP requests, by method.",
+			Buckets: prometheus.ExponentialBuckets(0.005, 2.5, 10),
+		}, []string{"method"}),
+		toolCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "mcp_tool_calls_total",
+			Help: "Number of tool calls answered by the server, by tool and outcome.",
+		}, []string{"tool", "outcome"}),
+		sessions: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "mcp_active_sessions",
+			Help: "Number of clients holding an event stream of the server open.",
+		}),
+		pending: map[string]request{},
+		tools:   map[string]bool{},
+	}
+	registerer.MustRegister(m.requests, m.durations, m.toolCalls, m.sessions)
+	return m
+}
+
+// requestsKey is the context key of the requests carried by a POST request.
+type requestsKey struct{}
+
+// New returns a handler that forwards requests to upstream and records the MCP traffic in metrics. Responses
+// are matched to their requests whether they are sent in the body of the POST request, as plain JSON or as
+// server-sent events, or on a separate event stream of the session.
+func New(upstream *url.URL, metrics *Metrics) http.Handler {
+	reverseProxy := &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.SetURL(upstream)
+			// Responses are inspected, so they must not be compressed.
+			r.Out.Header.Del("Accept-Encoding")
+		},
+		ModifyResponse: metrics.observeResponse,
+		// SSE responses must reach the client as they are written.
+		FlushInterval: -1,
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && jsonrpc.IsJSON(r.Header.Get("Content-Type")) {
+			r = metrics.observeRequest(r)
+		}
+		reverseProxy.ServeHTTP(w, r)
+	})
+}
+
+// observeRequest records the JSON-RPC requests in the body of r and returns r with them in its context.
+func (m *Metrics) observeRequest(r *http.Request) *http.Request {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxMessageSize+1))
+	if err != nil || len(body) > maxMessageSize {
+		// The request is forwarded as it is, its responses are not counted.
+		r.Body = readCloser{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+		return r
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	messages, _, err := jsonrpc.DecodeMessages(body)
+	if err != nil {
+		return r
+	}
+	now := time.Now()
+	requests := map[string]request{}
+	for _, msg := range messages {
+		method, _ := msg["method"].(string)
+		id, ok := idKey(msg["id"])
+		if method == "" || !ok {
+			// Notifications and responses to requests of the server are not answered.
+			continue
+		}
+		req := request{method: method, start: now}
+		if method == "tools/call" {
+			params, _ := msg["params"].(map[string]any)
+			req.tool, _ = params["name"].(string)
+		}
+		requests[id] = req
+	}
+	if len(requests) == 0 {
+		return r
+	}
+
+	// With the SSE transport, the responses are sent on the event stream of the session.
+	if session := sessionID(r); session != "" {
+		m.mu.Lock()
+		if len(m.pending)+len(requests) > maxPending {
+			m.prune(now)
+		}
+		for id, req := range requests {
+			if len(m.pending) < maxPending {
+				m.pending[session+"/"+id] = req
+			}
+		}
+		m.mu.Unlock()
+	}
+	return r.WithContext(context.WithValue(r.Context(), requestsKey{}, requests))
+}
+
+// observeResponse records the JSON-RPC responses in resp.
+func (m *Metrics) observeResponse(resp *http.Response) error {
+	r := resp.Request
+	requests, _ := r.Context().Value(requestsKey{}).(map[string]request)
+	session := sessionID(r)
+	contentType := resp.Header.Get("Content-Type")
+
+	switch {
+	case resp.StatusCode >= http.StatusBadRequest:
+		// The requests were rejected before reaching the MCP server.
+		for id, req := range requests {
+			m.observe(session, id, req, outcomeError)
+		}
+	case strings.HasPrefix(contentType, "text/event-stream"):
+		stream := r.Method == http.MethodGet
+		if stream {
+			m.sessions.Inc()
+		}
+		resp.Body = &eventTap{
+			ReadCloser: resp.Body,
+			onEvent: func(name, data string) {
+				if name == "endpoint" {
+					// The SSE transport announces the session in the URL the client posts its messages to.
+					if endpoint, err := url.Parse(strings.TrimSpace(data)); err == nil {
+						session = endpointSessionID(endpoint)
+					}
+					return
+				}
+				m.observeMessages(session, requests, []byte(data))
+			},
+			onClose: func() {
+				if stream {
+					m.sessions.Dec()
+				}
+			},
+		}
+	case jsonrpc.IsJSON(contentType):
+		data, err := io.ReadAll(io.LimitReader(resp.Body, maxMessageSize+1))
+		_ = resp.Body.Close()
+		if err != nil {
+			return err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+		if len(data) <= maxMessageSize {
+			m.observeMessages(session, requests, data)
+		}
+	}
+	return nil
+}
+
+// observeMessages records the JSON-RPC responses among the messages in data. Responses are matched to the
+// requests of the POST request they answer first, and otherwise to the pending requests of session.
+func (m *Metrics) observeMessages(session string, requests map[string]request, data []byte) {
+	messages, _, err := jsonrpc.DecodeMessages(data)
+	if err != nil {
+		return
+	}
+	for _, msg := range messages {
+		id, ok := idKey(msg["id"])
+		if !ok {
+			continue
+		}
+		_, isResult := msg["result"]
+		_, isError := msg["error"]
+		if !isResult && !isError {
+			continue
+		}
+
+		req, ok := requests[id]
+		if !ok {
+			m.mu.Lock()
+			req, ok = m.pending[session+"/"+id]
+			m.mu.Unlock()
+		}
+		if !ok {
+			continue
+		}
+
+		outcome := outcomeSuccess
+		if isError {
+			outcome = outcomeError
+		} else if result, _ := msg["result"].(map[string]any); req.method == "tools/call" && result["isError"] == true {
+			outcome = outcomeToolError
+		}
+		m.observe(session, id, req, outcome)
+	}
+}
+
+// observe records the response to req and forgets the request.
+func (m *Metrics) observe(session, id string, req request, outcome string) {
+	method := req.method
+	if !methods[method] {
+		method = otherLabel
+	}
+	requestOutcome := outcome
+	if outcome == outcomeToolError {
+		requestOutcome = outcomeSuccess
+	}
+	m.requests.WithLabelValues(method, requestOutcome).Inc()
+	m.durations.WithLabelValues(method).Observe(time.Since(req.start).Seconds())
+
+	m.mu.Lock()
+	delete(m.pending, session+"/"+id)
+	tool := req.tool
+	if method == "tools/call" && !m.tools[tool] {
+		if len(m.tools) < maxToolLabels {
+			m.tools[tool] = true
+		} else {
+			tool = otherLabel
+		}
+	}
+	m.mu.Unlock()
+
+	if method == "tools/call" {
+		m.toolCalls.WithLabelValues(tool, outcome).Inc()
+	}
+}
+
+// prune drops the pending requests older than pendingTimeout. m.mu must be held.
+func (m *Metrics) prune(now time.Time) {
+	for key, req := range m.pending {
+		if now.Sub(req.start) > pendingTimeout {
+			delete(m.pending, key)
+		}
+	}
+}
+
+// eventTap passes an event stream through unchanged and reports each of its events.
+type eventTap struct {
+	io.ReadCloser
+	onEvent func(name, data string)
+	onClose func()
+
+	partial   []byte
+	name      string
+	data      []string
+	closeOnce sync.Once
+}
+
+func (t *eventTap) Read(p []byte) (int, error) {
+	n, err := t.ReadCloser.Read(p)
+	t.feed(p[:n])
+	return n, err
+}
+
+func (t *eventTap) Close() error {
+	t.closeOnce.Do(t.onClose)
+	return t.ReadCloser.Close()
+}
+
+func (t *eventTap) feed(chunk []byte) {
+	t.partial = append(t.partial, chunk...)
+	for {
+		i := bytes.IndexByte(t.partial, '\n')
+		if i < 0 {
+			break
+		}
+		line := strings.TrimRight(string(t.partial[:i]), "\r")
+		t.partial = t.partial[i+1:]
+
+		switch {
+		case line == "":
+			if len(t.data) > 0 {
+				name := t.name
+				if name == "" {
+					name = "message"
+				}
+				t.onEvent(name, strings.Join(t.data, "\n"))
+			}
+			t.name, t.data = "", nil
+		case strings.HasPrefix(line, "event:"):
+			t.name = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			t.data = append(t.data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if len(t.partial) > maxMessageSize {
+		// An oversized event is not inspected.
+		t.partial, t.data = nil, nil
+	}
+}
+
+// sessionID returns the MCP session of r, from the header of the streamable HTTP transport or from the
+// query of the message endpoint of the SSE transport.
+func sessionID(r *http.Request) string {
+	if session := r.Header.Get("Mcp-Session-Id"); session != "" {
+		return session
+	}
+	return endpointSessionID(r.URL)
+}
+
+func endpointSessionID(endpoint *url.URL) string {
+	query := endpoint.Query()
+	if session := query.Get("sessionId"); session != "" {
+		return session
+	}
+	return query.Get("session_id")
+}
+
+// idKey returns a key for a JSON-RPC request id, which is either a string or a number.
+func idKey(id any) (string, bool) {
+	switch v := id.(type) {
+	case string:
+		return "s:" + v, true
+	case json.Number:
+		return "n:" + v.String(), true
+	}
+	return "", false
+}
+
+// readCloser reads from a reader and closes the original body.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
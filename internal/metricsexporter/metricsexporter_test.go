@@ -0,0 +1,159 @@
+package metricsexporter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func newExporter(t *testing.T, upstream http.Handler) (*Metrics, *httptest.Server) {
+	t.Helper()
+	upstreamServer := httptest.NewServer(upstream)
+	t.Cleanup(upstreamServer.Close)
+	upstreamURL, err := url.Parse(upstreamServer.URL)
+	if err != nil {
+		t.Fatalf("failed to parse the upstream URL: %v", err)
+	}
+
+	metrics := NewMetrics(prometheus.NewRegistry())
+	exporter := httptest.NewServer(New(upstreamURL, metrics))
+	t.Cleanup(exporter.Close)
+	return metrics, exporter
+}
+
+func TestNew_StreamableHTTP(t *testing.T) {
+	tests := []struct {
+		name        string
+		request     string
+		response    string
+		contentType string
+		wantMethod  string
+		wantOutcome string
+		wantTool    string
+	}{
+		{
+			name:        "tool call answered with JSON",
+			request:     `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo"}}`,
+			response:    `{"jsonrpc":"2.0","id":1,"result":{"content":[{"type":"text","text":"hi"}]}}`,
+			contentType: "application/json",
+			wantMethod:  "tools/call",
+			wantOutcome: outcomeSuccess,
+			wantTool:    "echo",
+		},
+		{
+			name:        "failed tool call answered with an event stream",
+			request:     `{"jsonrpc":"2.0","id":"a","method":"tools/call","params":{"name":"search"}}`,
+			response:    "event: message\ndata: {\"jsonrpc\":\"2.0\",\"id\":\"a\",\"result\":{\"content\":[],\"isError\":true}}\n\n",
+			contentType: "text/event-stream",
+			wantMethod:  "tools/call",
+			wantOutcome: outcomeToolError,
+			wantTool:    "search",
+		},
+		{
+			name:        "unknown method",
+			request:     `{"jsonrpc":"2.0","id":2,"method":"vendor/extension"}`,
+			response:    `{"jsonrpc":"2.0","id":2,"error":{"code":-32601,"message":"method not found"}}`,
+			contentType: "application/json",
+			wantMethod:  otherLabel,
+			wantOutcome: outcomeError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics, exporter := newExporter(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				_, _ = io.WriteString(w, tt.response)
+			}))
+
+			resp, err := http.Post(exporter.URL+"/mcp", "application/json", strings.NewReader(tt.request))
+			if err != nil {
+				t.Fatalf("POST failed: %v", err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			if string(body) != tt.response {
+				t.Errorf("body = %s, want the response of the server unchanged", body)
+			}
+
+			requestOutcome := tt.wantOutcome
+			if requestOutcome == outcomeToolError {
+				requestOutcome = outcomeSuccess
+			}
+			if got := testutil.ToFloat64(metrics.requests.WithLabelValues(tt.wantMethod, requestOutcome)); got != 1 {
+				t.Errorf("mcp_requests_total{method=%q,outcome=%q} = %v, want 1", tt.wantMethod, requestOutcome, got)
+			}
+			if got := testutil.CollectAndCount(metrics.durations); got != 1 {
+				t.Errorf("mcp_request_duration_seconds has %d series, want 1", got)
+			}
+			if tt.wantTool == "" {
+				return
+			}
+			if got := testutil.ToFloat64(metrics.toolCalls.WithLabelValues(tt.wantTool, tt.wantOutcome)); got != 1 {
+				t.Errorf("mcp_tool_calls_total{tool=%q,outcome=%q} = %v, want 1", tt.wantTool, tt.wantOutcome, got)
+			}
+		})
+	}
+}
+
+func TestNew_SSE(t *testing.T) {
+	responses := make(chan string, 1)
+	upstream := http.NewServeMux()
+	upstream.HandleFunc("GET /sse", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "event: endpoint\ndata: /message?sessionId=abc\n\n")
+		w.(http.Flusher).Flush()
+		select {
+		case response := <-responses:
+			_, _ = fmt.Fprintf(w, "event: message\ndata: %s\n\n", response)
+			w.(http.Flusher).Flush()
+		case <-r.Context().Done():
+		}
+	})
+	upstream.HandleFunc("POST /message", func(w http.ResponseWriter, r *http.Request) {
+		responses <- `{"jsonrpc":"2.0","id":1,"result":{"tools":[]}}`
+		w.WriteHeader(http.StatusAccepted)
+	})
+	metrics, exporter := newExporter(t, upstream)
+
+	stream, err := http.Get(exporter.URL + "/sse")
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	events := bufio.NewReader(stream.Body)
+	if line, _ := events.ReadString('\n'); line != "event: endpoint\n" {
+		t.Fatalf("first line = %q, want the endpoint event", line)
+	}
+	if got := testutil.ToFloat64(metrics.sessions); got != 1 {
+		t.Errorf("mcp_active_sessions = %v while the stream is open, want 1", got)
+	}
+
+	resp, err := http.Post(exporter.URL+"/message?sessionId=abc", "application/json",
+		strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+	if err != nil {
+		t.Fatalf("POST failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	_, _ = io.ReadAll(events)
+	_ = stream.Body.Close()
+
+	if got := testutil.ToFloat64(metrics.requests.WithLabelValues("tools/list", outcomeSuccess)); got != 1 {
+		t.Errorf("mcp_requests_total{method=\"tools/list\",outcome=\"success\"} = %v, want 1", got)
+	}
+	// The exporter closes the upstream stream after the client went away.
+	deadline := time.Now().Add(5 * time.Second)
+	for testutil.ToFloat64(metrics.sessions) != 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := testutil.ToFloat64(metrics.sessions); got != 0 {
+		t.Errorf("mcp_active_sessions = %v after the stream closed, want 0", got)
+	}
+}
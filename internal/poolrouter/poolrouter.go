@@ -0,0 +1,280 @@
+// Package poolrouter implements the pool-router subcommand of the manager binary. It is run by the router
+// Deployments the operator creates for MCPServerPools: it spreads the MCP sessions of the clients of a pool across
+// its identical shards by consistent hashing, and keeps every request of a session on the shard that holds it.
+//
+// The session IDs the shards issue are only known once a session is set up, so the router assigns each new
+// session a random routing key, places the key on a hash ring of the shards and prefixes the session ID the shard
+// issues with it, in the Mcp-Session-Id header of the streamable HTTP transport and in the message endpoint the
+// SSE transport announces. Later requests are routed by the key and reach the shard with the original session ID.
+// Resizing the pool moves about 1/N of the keys to another shard, whose clients then set up a new session.
+package poolrouter
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Command is the name of the subcommand.
+	Command = "pool-router"
+
+	// SessionHeader carries the session ID of the streamable HTTP transport.
+	SessionHeader = "Mcp-Session-Id"
+
+	// keySeparator separates the routing key from the session ID of the shard.
+	keySeparator = "."
+
+	// virtualNodes is the number of points each shard has on the hash ring, which evens out the share of the
+	// keys each shard gets.
+	virtualNodes = 128
+)
+
+// sessionQueryParameters are the query parameters the SSE transport passes the session ID in, sessionId in the
+// TypeScript SDK and session_id in the Python SDK.
+var sessionQueryParameters = []string{"sessionId", "session_id"}
+
+// Run starts the router with the given arguments and returns the process exit code.
+func Run(args []string) int {
+	fs := flag.NewFlagSet(Command, flag.ContinueOnError)
+	var backends []*url.URL
+	fs.Func("backend", "The base URL of a shard, e.g. http://tools-0.team-a.svc:8000. Repeat for each shard.",
+		func(value string) error {
+			backend, err := url.Parse(value)
+			if err != nil {
+				return err
+			}
+			backends = append(backends, backend)
+			return nil
+		})
+	port := fs.Int("port", 8000, "The port the router listens on.")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if len(backends) == 0 {
+		_, _ = fmt.Fprintln(os.Stderr, "at least one --backend is required")
+		return 2
+	}
+
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", *port),
+		Handler:           New(backends),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	_, _ = fmt.Fprintf(os.Stdout, "routing port %d to %d shards\n", *port, len(backends))
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		_, _ = fmt.Fprintf(os.Stderr, "router failed: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// Ring is a consistent hash ring of the shards of a pool.
+type Ring struct {
+	points   []uint32
+	backends map[uint32]*url.URL
+}
+
+// NewRing returns a Ring of backends.
+func NewRing(backends []*url.URL) *Ring {
+	ring := &Ring{backends: map[uint32]*url.URL{}}
+	for _, backend := range backends {
+		for i := 0; i < virtualNodes; i++ {
+			point := hash(backend.String() + "#" + strconv.Itoa(i))
+			if _, ok := ring.backends[point]; ok {
+				continue
+			}
+			ring.backends[point] = backend
+			ring.points = append(ring.points, point)
+		}
+	}
+	sort.Slice(ring.points, func(i, j int) bool { return ring.points[i] < ring.points[j] })
+	return ring
+}
+
+// Get returns the backend that key is placed on: the first one clockwise of the hash of key.
+func (r *Ring) Get(key string) *url.URL {
+	point := hash(key)
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i] >= point })
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.backends[r.points[i]]
+}
+
+// hash spreads similar strings, such as the points of one shard, evenly across the ring.
+func hash(s string) uint32 {
+	sum := sha256.Sum256([]byte(s))
+	return binary.BigEndian.Uint32(sum[:4])
+}
+
+type routeKey struct{}
+
+// route is where a request is sent and the routing key of its session.
+type route struct {
+	backend *url.URL
+	key     string
+}
+
+// New returns a handler that routes the requests of each session to the shard among backends its routing key
+// is placed on. Requests with a session ID the router did not issue are answered with 404 Not Found, which
+// makes MCP clients set up a new session.
+func New(backends []*url.URL) http.Handler {
+	ring := NewRing(backends)
+	reverseProxy := &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			route := r.In.Context().Value(routeKey{}).(route)
+			r.Out.URL.Scheme = route.backend.Scheme
+			r.Out.URL.Host = route.backend.Host
+			r.Out.Host = route.backend.Host
+		},
+		ModifyResponse: func(resp *http.Response) error {
+			route := resp.Request.Context().Value(routeKey{}).(route)
+			if session := resp.Header.Get(SessionHeader); session != "" {
+				resp.Header.Set(SessionHeader, route.key+keySeparator+session)
+			}
+			if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+				resp.Body = newEndpointRewriter(resp.Body, route.key)
+			}
+			return nil
+		},
+		// SSE responses must reach the client as they are written.
+		FlushInterval: -1,
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = r.Clone(r.Context())
+		key, ok := routingKey(r)
+		if !ok {
+			http.Error(w, "unknown session", http.StatusNotFound)
+			return
+		}
+		if key == "" {
+			var err error
+			if key, err = newKey(); err != nil {
+				http.Error(w, fmt.Sprintf("unable to start a session: %v", err), http.StatusInternalServerError)
+				return
+			}
+		}
+		ctx := context.WithValue(r.Context(), routeKey{}, route{backend: ring.Get(key), key: key})
+		reverseProxy.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// routingKey returns the routing key of the session of r, and removes it from the session ID r carries. The key
+// is empty for a request without a session, and false is returned for a session ID without a key.
+func routingKey(r *http.Request) (string, bool) {
+	if session := r.Header.Get(SessionHeader); session != "" {
+		key, id, ok := strings.Cut(session, keySeparator)
+		if !ok {
+			return "", false
+		}
+		r.Header.Set(SessionHeader, id)
+		return key, true
+	}
+
+	query := r.URL.Query()
+	for _, parameter := range sessionQueryParameters {
+		session := query.Get(parameter)
+		if session == "" {
+			continue
+		}
+		key, id, ok := strings.Cut(session, keySeparator)
+		if !ok {
+			return "", false
+		}
+		query.Set(parameter, id)
+		r.URL.RawQuery = query.Encode()
+		return key, true
+	}
+	return "", true
+}
+
+// newKey returns a random routing key.
+func newKey() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// endpointRewriter prefixes the session ID in the message endpoint an SSE stream announces with the routing key,
+// so that the messages of the client reach the router, and through it the same shard, again. An absolute
+// endpoint is made relative, as the client would otherwise bypass the router.
+type endpointRewriter struct {
+	src      *bufio.Reader
+	closer   io.Closer
+	key      string
+	endpoint bool
+	buf      []byte
+	err      error
+}
+
+func newEndpointRewriter(body io.ReadCloser, key string) io.ReadCloser {
+	return &endpointRewriter{src: bufio.NewReader(body), closer: body, key: key}
+}
+
+// Read returns the stream line by line, as each line may have to be rewritten.
+func (e *endpointRewriter) Read(p []byte) (int, error) {
+	for len(e.buf) == 0 {
+		if e.err != nil {
+			return 0, e.err
+		}
+		line, err := e.src.ReadString('\n')
+		e.buf = []byte(e.rewrite(line))
+		e.err = err
+	}
+	n := copy(p, e.buf)
+	e.buf = e.buf[n:]
+	return n, nil
+}
+
+func (e *endpointRewriter) Close() error {
+	return e.closer.Close()
+}
+
+// rewrite returns line, with the session ID prefixed if it is the data of an endpoint event.
+func (e *endpointRewriter) rewrite(line string) string {
+	content := strings.TrimRight(line, "\r\n")
+	if field, value, ok := strings.Cut(content, ":"); ok && field == "event" {
+		e.endpoint = strings.TrimSpace(value) == "endpoint"
+		return line
+	}
+	if content == "" {
+		e.endpoint = false
+		return line
+	}
+	data, ok := strings.CutPrefix(content, "data:")
+	if !e.endpoint || !ok {
+		return line
+	}
+	endpoint, err := url.Parse(strings.TrimSpace(data))
+	if err != nil {
+		return line
+	}
+	query := endpoint.Query()
+	for _, parameter := range sessionQueryParameters {
+		if session := query.Get(parameter); session != "" {
+			query.Set(parameter, e.key+keySeparator+session)
+		}
+	}
+	endpoint.RawQuery = query.Encode()
+	endpoint.Scheme, endpoint.Host, endpoint.User = "", "", nil
+	return "data: " + endpoint.String() + line[len(content):]
+}
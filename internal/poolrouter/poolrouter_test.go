@@ -0,0 +1,167 @@
+package poolrouter
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// newShard returns a shard named name that issues the session ID name-session. It answers GET /sse with an
+// endpoint event and other requests with a streamable HTTP session, and records the session ID of each request.
+func newShard(t *testing.T, name string, sessions *[]string) *httptest.Server {
+	t.Helper()
+	shard := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session := r.Header.Get(SessionHeader)
+		if session == "" {
+			session = r.URL.Query().Get("sessionId")
+		}
+		*sessions = append(*sessions, name+":"+session)
+
+		if r.Method == http.MethodGet && r.URL.Path == "/sse" {
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = fmt.Fprintf(w, "event: endpoint\r\ndata: http://%s/messages?sessionId=%s-session\r\n\r\n", r.Host, name)
+			_, _ = fmt.Fprint(w, "event: message\ndata: {\"sessionId\":\"unchanged\"}\n\n")
+			return
+		}
+		w.Header().Set(SessionHeader, name+"-session")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(shard.Close)
+	return shard
+}
+
+func TestNew(t *testing.T) {
+	var sessions []string
+	var backends []*url.URL
+	for _, name := range []string{"a", "b", "c"} {
+		backend, err := url.Parse(newShard(t, name, &sessions).URL)
+		if err != nil {
+			t.Fatalf("failed to parse the shard URL: %v", err)
+		}
+		backends = append(backends, backend)
+	}
+	router := httptest.NewServer(New(backends))
+	defer router.Close()
+
+	t.Run("SSE", func(t *testing.T) {
+		sessions = nil
+		resp, err := http.Get(router.URL + "/sse")
+		if err != nil {
+			t.Fatalf("failed to open the stream: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+
+		_, data, _ := strings.Cut(string(body), "data: ")
+		endpoint, _, _ := strings.Cut(data, "\r\n")
+		if !strings.HasPrefix(endpoint, "/messages?sessionId=") {
+			t.Fatalf("endpoint = %q, want a relative endpoint with a session ID", endpoint)
+		}
+		if !strings.Contains(string(body), `data: {"sessionId":"unchanged"}`) {
+			t.Errorf("stream = %q, want the data of other events unchanged", body)
+		}
+		key, shardSession, _ := strings.Cut(strings.TrimPrefix(endpoint, "/messages?sessionId="), keySeparator)
+		if key == "" || !strings.HasSuffix(shardSession, "-session") {
+			t.Fatalf("endpoint = %q, want the session ID of the shard prefixed with a routing key", endpoint)
+		}
+
+		for i := 0; i < 3; i++ {
+			resp, err := http.Post(router.URL+endpoint, "application/json", strings.NewReader("{}"))
+			if err != nil {
+				t.Fatalf("failed to post a message: %v", err)
+			}
+			_ = resp.Body.Close()
+		}
+		shard, _, _ := strings.Cut(shardSession, "-")
+		for _, got := range sessions[1:] {
+			if want := shard + ":" + shardSession; got != want {
+				t.Errorf("message reached %q, want %q", got, want)
+			}
+		}
+	})
+
+	t.Run("streamable HTTP", func(t *testing.T) {
+		sessions = nil
+		resp, err := http.Post(router.URL+"/mcp", "application/json", strings.NewReader("{}"))
+		if err != nil {
+			t.Fatalf("failed to initialize: %v", err)
+		}
+		_ = resp.Body.Close()
+		session := resp.Header.Get(SessionHeader)
+		key, shardSession, _ := strings.Cut(session, keySeparator)
+		if key == "" || !strings.HasSuffix(shardSession, "-session") {
+			t.Fatalf("%s = %q, want the session ID of the shard prefixed with a routing key", SessionHeader, session)
+		}
+
+		for i := 0; i < 3; i++ {
+			req, _ := http.NewRequest(http.MethodPost, router.URL+"/mcp", strings.NewReader("{}"))
+			req.Header.Set(SessionHeader, session)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("failed to send a request: %v", err)
+			}
+			_ = resp.Body.Close()
+			if got := resp.Header.Get(SessionHeader); got != session {
+				t.Errorf("%s = %q, want %q", SessionHeader, got, session)
+			}
+		}
+		shard, _, _ := strings.Cut(shardSession, "-")
+		for _, got := range sessions[1:] {
+			if want := shard + ":" + shardSession; got != want {
+				t.Errorf("request reached %q, want %q", got, want)
+			}
+		}
+	})
+
+	t.Run("unknown session", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, router.URL+"/mcp", strings.NewReader("{}"))
+		req.Header.Set(SessionHeader, "issued-elsewhere")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("failed to send a request: %v", err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusNotFound)
+		}
+	})
+}
+
+func TestRing(t *testing.T) {
+	backends := func(n int) []*url.URL {
+		var urls []*url.URL
+		for i := 0; i < n; i++ {
+			urls = append(urls, &url.URL{Scheme: "http", Host: fmt.Sprintf("tools-%d.team-a.svc:8000", i)})
+		}
+		return urls
+	}
+	three, four := NewRing(backends(3)), NewRing(backends(4))
+
+	const keys = 3000
+	shares := map[string]int{}
+	moved := 0
+	for i := 0; i < keys; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		before, after := three.Get(key), four.Get(key)
+		shares[before.Host]++
+		if before.Host != after.Host {
+			moved++
+			if after.Host != "tools-3.team-a.svc:8000" {
+				t.Errorf("key %s moved from %s to %s, want it to move only to the new shard", key, before.Host,
+					after.Host)
+			}
+		}
+	}
+	for host, share := range shares {
+		if share < keys/6 {
+			t.Errorf("shard %s got %d of %d keys, want an even share", host, share, keys)
+		}
+	}
+	if moved > keys/2 {
+		t.Errorf("%d of %d keys moved when a shard was added, want about a quarter", moved, keys)
+	}
+}
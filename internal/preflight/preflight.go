@@ -0,0 +1,311 @@
+// Package preflight implements the check subcommand of the manager binary, which verifies the prerequisites of
+// the operator in a cluster and prints a report, so that a broken install shows up before any MCPServer is
+// reconciled. The manager runs the same checks when it starts and logs the ones that do not pass.
+package preflight
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/kubernetes"
+	authorizationv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster/gvk"
+)
+
+// Command is the name of the subcommand.
+const Command = "check"
+
+// Status is the outcome of a check.
+type Status string
+
+const (
+	// Pass means the prerequisite is met.
+	Pass Status = "Pass"
+	// Warn means the operator works, but some features are not available.
+	Warn Status = "Warn"
+	// Fail means the operator cannot reconcile MCPServers.
+	Fail Status = "Fail"
+	// Skip means the check does not apply to the cluster.
+	Skip Status = "Skip"
+)
+
+// Check names.
+const (
+	CheckCRDs          = "CRDs"
+	CheckRouteAPI      = "RouteAPI"
+	CheckIngressDomain = "IngressDomain"
+	CheckGatewayAPI    = "GatewayAPI"
+	CheckRBAC          = "RBAC"
+)
+
+// Result is the outcome of a check.
+type Result struct {
+	Name    string `json:"name"`
+	Status  Status `json:"status"`
+	Message string `json:"message"`
+}
+
+// Report holds the results of all checks.
+type Report struct {
+	// Platform is OpenShift or Kubernetes.
+	Platform string   `json:"platform"`
+	Results  []Result `json:"results"`
+}
+
+// Failed reports whether a check failed.
+func (r *Report) Failed() bool {
+	for _, result := range r.Results {
+		if result.Status == Fail {
+			return true
+		}
+	}
+	return false
+}
+
+// permission is a permission the operator needs on a resource.
+type permission struct {
+	group, resource string
+	verbs           []string
+	// clusterScoped permissions are only checked when the operator watches all namespaces, as namespace-scoped
+	// installs do not grant them.
+	clusterScoped bool
+	// kind is the kind that must be served for the permission to be needed, if any.
+	kind *schema.GroupVersionKind
+}
+
+// permissions are the permissions the controller needs to reconcile MCPServers. Permissions of optional features,
+// such as ServiceMonitors, are not checked.
+var permissions = []permission{
+	{group: mcpserverv1.GroupVersion.Group, resource: "mcpservers", verbs: []string{"get", "list", "watch", "update"}},
+	{group: mcpserverv1.GroupVersion.Group, resource: "mcpservers/status", verbs: []string{"update"}},
+	{group: mcpserverv1.GroupVersion.Group, resource: "mcpserverdefaults", verbs: []string{"get", "list", "watch"}},
+	{group: "apps", resource: "deployments", verbs: []string{"create", "get", "list", "watch", "patch", "delete"}},
+	{group: "", resource: "services", verbs: []string{"create", "get", "list", "watch", "patch", "delete"}},
+	{group: "", resource: "pods", verbs: []string{"get", "list", "watch"}},
+	{group: "", resource: "serviceaccounts", verbs: []string{"get", "create", "patch", "delete"}},
+	{group: "", resource: "resourcequotas", verbs: []string{"list"}},
+	{group: "", resource: "events", verbs: []string{"create"}},
+	{group: "batch", resource: "jobs", verbs: []string{"create", "get", "list", "watch", "delete"}},
+	{group: "networking.k8s.io", resource: "ingresses", verbs: []string{"create", "get", "list", "watch", "patch", "delete"}},
+	{group: "", resource: "secrets", verbs: []string{"list", "watch"}, kind: &gvk.Route},
+	{group: "route.openshift.io", resource: "routes", verbs: []string{"create", "get", "list", "watch", "patch", "delete"},
+		kind: &gvk.Route},
+	{group: "authentication.k8s.io", resource: "tokenreviews", verbs: []string{"create"}, clusterScoped: true},
+	{group: "authorization.k8s.io", resource: "subjectaccessreviews", verbs: []string{"create"}, clusterScoped: true},
+}
+
+// Checker checks the prerequisites of the operator.
+type Checker struct {
+	// Platform is the detected platform of the cluster.
+	Platform *cluster.Platform
+	// AccessReviews checks the permissions of the operator.
+	AccessReviews authorizationv1client.SelfSubjectAccessReviewInterface
+	// Namespace is the namespace the operator is restricted to, all namespaces when empty.
+	Namespace string
+}
+
+// Run runs all checks.
+func (c *Checker) Run(ctx context.Context) *Report {
+	return &Report{
+		Platform: c.Platform.Name,
+		Results: []Result{
+			c.checkCRDs(),
+			c.checkRouteAPI(),
+			c.checkIngressDomain(),
+			c.checkGatewayAPI(),
+			c.checkRBAC(ctx),
+		},
+	}
+}
+
+func (c *Checker) checkCRDs() Result {
+	var missing []string
+	for _, kind := range []string{"MCPServer", "MCPServerDefaults"} {
+		if !c.Platform.HasAPI(mcpserverv1.GroupVersion.WithKind(kind)) {
+			missing = append(missing, kind)
+		}
+	}
+	if len(missing) > 0 {
+		return Result{CheckCRDs, Fail, fmt.Sprintf("The %s CRDs of %s are not installed, apply config/crd first",
+			strings.Join(missing, " and "), mcpserverv1.GroupVersion)}
+	}
+	return Result{CheckCRDs, Pass, fmt.Sprintf("The CRDs of %s are installed", mcpserverv1.GroupVersion)}
+}
+
+func (c *Checker) checkRouteAPI() Result {
+	if c.Platform.HasAPI(gvk.Route) {
+		return Result{CheckRouteAPI, Pass, "Routes are created for MCP servers"}
+	}
+	return Result{CheckRouteAPI, Warn, "The Route API is not served: MCP servers are only reachable inside the " +
+		"cluster, unless they set gatewayRef or meshGateway"}
+}
+
+func (c *Checker) checkIngressDomain() Result {
+	switch {
+	case !c.Platform.IsOpenShift():
+		return Result{CheckIngressDomain, Skip, "The cluster is not OpenShift"}
+	case c.Platform.IngressDomain == "":
+		return Result{CheckIngressDomain, Warn, "The default ingress domain is unknown: Routes only get a host once " +
+			"a router admits them"}
+	}
+	return Result{CheckIngressDomain, Pass, fmt.Sprintf("Routes get hosts under %s", c.Platform.IngressDomain)}
+}
+
+func (c *Checker) checkGatewayAPI() Result {
+	if c.Platform.HasAPI(gvk.HTTPRoute) {
+		return Result{CheckGatewayAPI, Pass, "MCP servers can be attached to a Gateway with gatewayRef"}
+	}
+	return Result{CheckGatewayAPI, Skip, "The Gateway API is not installed, gatewayRef cannot be used"}
+}
+
+func (c *Checker) checkRBAC(ctx context.Context) Result {
+	var missing []string
+	for _, p := range permissions {
+		if p.clusterScoped && c.Namespace != "" {
+			continue
+		}
+		if p.kind != nil && !c.Platform.HasAPI(*p.kind) {
+			continue
+		}
+		for _, verb := range p.verbs {
+			review, err := c.AccessReviews.Create(ctx, &authorizationv1.SelfSubjectAccessReview{
+				Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+					ResourceAttributes: &authorizationv1.ResourceAttributes{
+						Namespace: c.Namespace,
+						Verb:      verb,
+						Group:     p.group,
+						Resource:  p.resource,
+					},
+				},
+			}, metav1.CreateOptions{})
+			if err != nil {
+				return Result{CheckRBAC, Fail, fmt.Sprintf("Unable to review the permissions of the operator: %v", err)}
+			}
+			if !review.Status.Allowed {
+				missing = append(missing, fmt.Sprintf("%s %s", verb, qualifiedResource(p)))
+			}
+		}
+	}
+
+	scope := "all namespaces"
+	if c.Namespace != "" {
+		scope = "namespace " + c.Namespace
+	}
+	if len(missing) > 0 {
+		return Result{CheckRBAC, Fail, fmt.Sprintf("The operator may not %s in %s", strings.Join(missing, ", "), scope)}
+	}
+	return Result{CheckRBAC, Pass, fmt.Sprintf("The operator has the permissions it needs in %s", scope)}
+}
+
+// qualifiedResource returns the resource of p with its group, e.g. deployments.apps.
+func qualifiedResource(p permission) string {
+	if p.group == "" {
+		return p.resource
+	}
+	resource, subresource, found := strings.Cut(p.resource, "/")
+	if found {
+		return fmt.Sprintf("%s.%s/%s", resource, p.group, subresource)
+	}
+	return fmt.Sprintf("%s.%s", resource, p.group)
+}
+
+// Write prints report to out, as a table or, with output json, as JSON.
+func Write(out io.Writer, report *Report, output string) error {
+	if output == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintf(w, "Platform: %s\n\n", report.Platform)
+	_, _ = fmt.Fprintln(w, "CHECK\tSTATUS\tMESSAGE")
+	for _, result := range report.Results {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", result.Name, result.Status, result.Message)
+	}
+	return w.Flush()
+}
+
+// Run checks the cluster of the current kubeconfig, or of the service account of the pod, prints the report and
+// returns the process exit code: 1 when a check failed, 2 when the cluster could not be checked.
+func Run(args []string) int {
+	fs := flag.NewFlagSet(Command, flag.ContinueOnError)
+	namespace := fs.String("watch-namespace", "",
+		"The namespace the operator is restricted to. The permissions in all namespaces are checked if unset.")
+	output := fs.String("output", "text", "The format of the report, text or json.")
+	timeout := fs.Duration("timeout", time.Minute, "The maximum time the checks may take.")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *output != "text" && *output != "json" {
+		_, _ = fmt.Fprintf(os.Stderr, "unknown output %q, want text or json\n", *output)
+		return 2
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	checker, err := newChecker(ctx, *namespace)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "unable to connect to the cluster: %v\n", err)
+		return 2
+	}
+	report := checker.Run(ctx)
+	if err := Write(os.Stdout, report, *output); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "unable to write the report: %v\n", err)
+		return 2
+	}
+	if report.Failed() {
+		return 1
+	}
+	return 0
+}
+
+// newChecker returns a Checker for the cluster of the current kubeconfig.
+func newChecker(ctx context.Context, namespace string) (*Checker, error) {
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	scheme := runtime.NewScheme()
+	if err := configv1.Install(scheme); err != nil {
+		return nil, err
+	}
+	reader, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, err
+	}
+	platform, err := cluster.DetectPlatform(ctx, dc, reader)
+	if err != nil {
+		return nil, err
+	}
+	return &Checker{
+		Platform:      platform,
+		AccessReviews: clientset.AuthorizationV1().SelfSubjectAccessReviews(),
+		Namespace:     namespace,
+	}, nil
+}
@@ -0,0 +1,179 @@
+package preflight
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster/gvk"
+)
+
+// newPlatform returns a platform named name that serves kinds.
+func newPlatform(name, ingressDomain string, kinds ...schema.GroupVersionKind) *cluster.Platform {
+	discovery := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{}}
+	lists := map[string]*metav1.APIResourceList{}
+	for _, kind := range kinds {
+		groupVersion := kind.GroupVersion().String()
+		list := lists[groupVersion]
+		if list == nil {
+			list = &metav1.APIResourceList{GroupVersion: groupVersion}
+			lists[groupVersion] = list
+			discovery.Resources = append(discovery.Resources, list)
+		}
+		list.APIResources = append(list.APIResources,
+			metav1.APIResource{Name: strings.ToLower(kind.Kind) + "s", Kind: kind.Kind})
+	}
+	return &cluster.Platform{Name: name, APIs: gvk.NewAvailability(discovery), IngressDomain: ingressDomain}
+}
+
+// newAccessReviews returns a fake clientset whose SelfSubjectAccessReviews deny the verbs of denied, keyed by
+// verb and resource, and record the reviews into reviews.
+func newAccessReviews(denied map[string]bool, reviews *[]authorizationv1.ResourceAttributes) *fake.Clientset {
+	clientset := fake.NewClientset()
+	clientset.PrependReactor("create", "selfsubjectaccessreviews",
+		func(action clienttesting.Action) (bool, runtime.Object, error) {
+			review := action.(clienttesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+			attributes := review.Spec.ResourceAttributes
+			*reviews = append(*reviews, *attributes)
+			review.Status.Allowed = !denied[attributes.Verb+" "+attributes.Resource]
+			return true, review, nil
+		})
+	return clientset
+}
+
+func TestChecker_Run(t *testing.T) {
+	mcpServer := mcpserverv1.GroupVersion.WithKind("MCPServer")
+	mcpServerDefaults := mcpserverv1.GroupVersion.WithKind("MCPServerDefaults")
+
+	tests := []struct {
+		name      string
+		platform  *cluster.Platform
+		namespace string
+		denied    map[string]bool
+		want      map[string]Status
+		wantFail  string
+	}{
+		{
+			name: "OpenShift with all prerequisites",
+			platform: newPlatform(cluster.OpenShift, "apps.example.com",
+				mcpServer, mcpServerDefaults, gvk.Route, gvk.HTTPRoute),
+			want: map[string]Status{
+				CheckCRDs: Pass, CheckRouteAPI: Pass, CheckIngressDomain: Pass, CheckGatewayAPI: Pass, CheckRBAC: Pass,
+			},
+		},
+		{
+			name:     "Kubernetes without Routes",
+			platform: newPlatform(cluster.Kubernetes, "", mcpServer, mcpServerDefaults),
+			want: map[string]Status{
+				CheckCRDs: Pass, CheckRouteAPI: Warn, CheckIngressDomain: Skip, CheckGatewayAPI: Skip, CheckRBAC: Pass,
+			},
+		},
+		{
+			name:     "OpenShift without an ingress domain",
+			platform: newPlatform(cluster.OpenShift, "", mcpServer, mcpServerDefaults, gvk.Route),
+			want: map[string]Status{
+				CheckCRDs: Pass, CheckRouteAPI: Pass, CheckIngressDomain: Warn, CheckGatewayAPI: Skip, CheckRBAC: Pass,
+			},
+		},
+		{
+			name:     "missing CRD",
+			platform: newPlatform(cluster.Kubernetes, "", mcpServer),
+			want: map[string]Status{
+				CheckCRDs: Fail, CheckRouteAPI: Warn, CheckIngressDomain: Skip, CheckGatewayAPI: Skip, CheckRBAC: Pass,
+			},
+			wantFail: "The MCPServerDefaults CRDs",
+		},
+		{
+			name:      "missing permissions",
+			platform:  newPlatform(cluster.Kubernetes, "", mcpServer, mcpServerDefaults),
+			namespace: "team-a",
+			denied:    map[string]bool{"create deployments": true, "update mcpservers/status": true},
+			want: map[string]Status{
+				CheckCRDs: Pass, CheckRouteAPI: Warn, CheckIngressDomain: Skip, CheckGatewayAPI: Skip, CheckRBAC: Fail,
+			},
+			wantFail: "The operator may not update mcpservers.mcpserver.opendatahub.io/status, " +
+				"create deployments.apps in namespace team-a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reviews []authorizationv1.ResourceAttributes
+			checker := &Checker{
+				Platform:      tt.platform,
+				AccessReviews: newAccessReviews(tt.denied, &reviews).AuthorizationV1().SelfSubjectAccessReviews(),
+				Namespace:     tt.namespace,
+			}
+			report := checker.Run(context.Background())
+
+			got := map[string]Status{}
+			var failures []string
+			for _, result := range report.Results {
+				got[result.Name] = result.Status
+				if result.Status == Fail {
+					failures = append(failures, result.Message)
+				}
+			}
+			for name, status := range tt.want {
+				if got[name] != status {
+					t.Errorf("check %s = %s, want %s", name, got[name], status)
+				}
+			}
+			if report.Failed() != (tt.wantFail != "") {
+				t.Errorf("Failed() = %v, want %v", report.Failed(), tt.wantFail != "")
+			}
+			if tt.wantFail != "" && !strings.Contains(strings.Join(failures, "\n"), tt.wantFail) {
+				t.Errorf("failures = %q, want %q", failures, tt.wantFail)
+			}
+
+			for _, review := range reviews {
+				if review.Namespace != tt.namespace {
+					t.Errorf("review %+v, want it in namespace %q", review, tt.namespace)
+				}
+				if review.Resource == "routes" && !tt.platform.HasAPI(gvk.Route) {
+					t.Errorf("review %+v, want Routes only reviewed when served", review)
+				}
+				if review.Resource == "tokenreviews" && tt.namespace != "" {
+					t.Errorf("review %+v, want cluster-scoped permissions only reviewed cluster-wide", review)
+				}
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	report := &Report{Platform: cluster.Kubernetes, Results: []Result{
+		{Name: CheckCRDs, Status: Pass, Message: "installed"},
+		{Name: CheckRouteAPI, Status: Warn, Message: "not served"},
+	}}
+
+	var text bytes.Buffer
+	if err := Write(&text, report, "text"); err != nil {
+		t.Fatalf("Write(text) error = %v", err)
+	}
+	want := "Platform: Kubernetes\n\nCHECK      STATUS   MESSAGE\nCRDs       Pass     installed\n" +
+		"RouteAPI   Warn     not served\n"
+	if text.String() != want {
+		t.Errorf("Write(text) = %q, want %q", text.String(), want)
+	}
+
+	var out bytes.Buffer
+	if err := Write(&out, report, "json"); err != nil {
+		t.Fatalf("Write(json) error = %v", err)
+	}
+	got := &Report{}
+	if err := json.Unmarshal(out.Bytes(), got); err != nil || len(got.Results) != 2 || got.Results[1].Status != Warn {
+		t.Errorf("Write(json) = %s (%v), want the report", out.String(), err)
+	}
+}
@@ -0,0 +1,109 @@
+package proxy
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// keepAliveComment is an SSE comment, which clients ignore, but which keeps proxies from closing an idle stream.
+const keepAliveComment = ": keep-alive\n\n"
+
+// WithKeepAlive returns a handler that writes a keep-alive comment to the event streams next responds with
+// whenever nothing was written to them for interval. Event streams are also marked with the X-Accel-Buffering
+// header, which keeps proxies that honor it, such as NGINX, from buffering them.
+func WithKeepAlive(next http.Handler, interval time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		kw := &keepAliveWriter{ResponseWriter: w, lastWrite: time.Now()}
+		done := make(chan struct{})
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			kw.keepAlive(interval, done)
+		}()
+		// Nothing may be written to w once the handler returned.
+		defer wg.Wait()
+		defer close(done)
+		next.ServeHTTP(kw, r)
+	})
+}
+
+// keepAliveWriter serializes the writes of a handler and of the keep-alives to an event stream.
+type keepAliveWriter struct {
+	http.ResponseWriter
+
+	mu          sync.Mutex
+	wroteHeader bool
+	streaming   bool
+	lastWrite   time.Time
+}
+
+// keepAlive writes a keep-alive comment to the stream every interval it was idle, until done is closed.
+func (w *keepAliveWriter) keepAlive(interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case now := <-ticker.C:
+			w.mu.Lock()
+			if w.streaming && now.Sub(w.lastWrite) >= interval {
+				if _, err := io.WriteString(w.ResponseWriter, keepAliveComment); err == nil {
+					_ = http.NewResponseController(w.ResponseWriter).Flush()
+				}
+				w.lastWrite = now
+			}
+			w.mu.Unlock()
+		}
+	}
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (w *keepAliveWriter) WriteHeader(statusCode int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.writeHeader(statusCode)
+}
+
+// writeHeader writes the header of the response with w.mu held.
+func (w *keepAliveWriter) writeHeader(statusCode int) {
+	if w.wroteHeader {
+		return
+	}
+	if statusCode >= 100 && statusCode < 200 {
+		// Informational responses precede the final header.
+		w.ResponseWriter.WriteHeader(statusCode)
+		return
+	}
+	w.wroteHeader = true
+	if statusCode == http.StatusOK && strings.HasPrefix(w.Header().Get("Content-Type"), "text/event-stream") {
+		w.streaming = true
+		w.Header().Set("X-Accel-Buffering", "no")
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write implements http.ResponseWriter.
+func (w *keepAliveWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.writeHeader(http.StatusOK)
+	w.lastWrite = time.Now()
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher.
+func (w *keepAliveWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap returns the wrapped http.ResponseWriter, for http.ResponseController.
+func (w *keepAliveWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
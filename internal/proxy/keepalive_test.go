@@ -0,0 +1,56 @@
+package proxy
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithKeepAlive(t *testing.T) {
+	handler := WithKeepAlive(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sse" {
+			_, _ = io.WriteString(w, "{}")
+			time.Sleep(100 * time.Millisecond)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		_, _ = io.WriteString(w, "event: endpoint\ndata: /message\n\n")
+		w.(http.Flusher).Flush()
+		time.Sleep(100 * time.Millisecond)
+	}), 20*time.Millisecond)
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	get := func(path string) (*http.Response, string) {
+		t.Helper()
+		resp, err := server.Client().Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s error = %v", path, err)
+		}
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("failed to read the body of %s: %v", path, err)
+		}
+		return resp, string(body)
+	}
+
+	resp, body := get("/sse")
+	if !strings.HasPrefix(body, "event: endpoint\ndata: /message\n\n"+keepAliveComment) {
+		t.Errorf("event stream = %q, want the event followed by keep-alives", body)
+	}
+	if resp.Header.Get("X-Accel-Buffering") != "no" {
+		t.Errorf("X-Accel-Buffering = %q, want no", resp.Header.Get("X-Accel-Buffering"))
+	}
+
+	resp, body = get("/message")
+	if body != "{}" || resp.Header.Get("X-Accel-Buffering") != "" {
+		t.Errorf("response = %q with headers %v, want other responses left alone", body, resp.Header)
+	}
+}
@@ -0,0 +1,224 @@
+// Package proxy implements the proxy subcommand of the manager binary. It is run by the Deployments the
+// operator creates for MCPServers of type Proxy: it admits callers whose Kubernetes token allows them to get
+// the MCPServer and forwards their requests to the upstream MCP server with the upstream credentials, so that
+// users never handle them.
+package proxy
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/internal/accessreview"
+)
+
+const (
+	// Command is the name of the subcommand.
+	Command = "proxy"
+
+	// TokenEnv is the environment variable holding the bearer token sent to the upstream MCP server.
+	TokenEnv = "MCP_UPSTREAM_TOKEN"
+
+	// authorizationTTL is how long the outcome of a review is reused for the same caller token, so that the
+	// messages of an MCP session don't each cost a round trip to the API server.
+	authorizationTTL = time.Minute
+)
+
+// Authorizer decides whether the caller presenting token may use the MCP server.
+type Authorizer interface {
+	Authorize(ctx context.Context, token string) (bool, error)
+}
+
+// Run starts the proxy with the given arguments and returns the process exit code.
+func Run(args []string) int {
+	fs := flag.NewFlagSet(Command, flag.ContinueOnError)
+	upstream := fs.String("upstream", "", "The SSE URL of the upstream MCP server.")
+	ssePath := fs.String("sse-path", "/sse", "The path clients open the SSE stream at.")
+	port := fs.Int("port", 8000, "The port the proxy listens on.")
+	name := fs.String("mcp-server", "", "The name of the MCPServer callers must be allowed to get.")
+	namespace := fs.String("namespace", "", "The namespace of the MCPServer.")
+	tokenFile := fs.String("token-file", "",
+		"A file holding the bearer token sent upstream, used instead of the "+TokenEnv+" environment variable.")
+	keepAlive := fs.Duration("sse-keep-alive", 0,
+		"How often a keep-alive comment is sent on an idle event stream. No keep-alives are sent if 0.")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *upstream == "" || *name == "" || *namespace == "" {
+		_, _ = fmt.Fprintln(os.Stderr, "--upstream, --mcp-server and --namespace are required")
+		return 2
+	}
+	upstreamURL, err := url.Parse(*upstream)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "invalid --upstream: %v\n", err)
+		return 2
+	}
+
+	token := os.Getenv(TokenEnv)
+	if *tokenFile != "" {
+		data, err := os.ReadFile(*tokenFile)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "unable to read the token: %v\n", err)
+			return 2
+		}
+		token = strings.TrimSpace(string(data))
+	}
+
+	config, err := ctrl.GetConfig()
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "unable to load the cluster configuration: %v\n", err)
+		return 1
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "unable to create a cluster client: %v\n", err)
+		return 1
+	}
+
+	handler := New(upstreamURL, *ssePath, token, NewReviewAuthorizer(clientset, *name, *namespace))
+	if *keepAlive > 0 {
+		handler = WithKeepAlive(handler, *keepAlive)
+	}
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", *port),
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	_, _ = fmt.Fprintf(os.Stdout, "proxying port %d to %s\n", *port, upstreamURL.Redacted())
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		_, _ = fmt.Fprintf(os.Stderr, "proxy failed: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// New returns a handler that forwards authorized requests to upstream. Requests for ssePath are sent to the
+// upstream URL itself, all other paths, such as the message endpoint announced by the server, to the same
+// path on the upstream host. The Authorization header of the caller is replaced by token, or removed when
+// token is empty.
+func New(upstream *url.URL, ssePath, token string, authorizer Authorizer) http.Handler {
+	reverseProxy := &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.Out.URL.Scheme = upstream.Scheme
+			r.Out.URL.Host = upstream.Host
+			r.Out.Host = upstream.Host
+			if r.In.URL.Path == ssePath {
+				r.Out.URL.Path = upstream.Path
+				r.Out.URL.RawPath = upstream.RawPath
+				if upstream.RawQuery != "" {
+					r.Out.URL.RawQuery = upstream.RawQuery
+				}
+			}
+
+			r.Out.Header.Del("Authorization")
+			if token != "" {
+				r.Out.Header.Set("Authorization", "Bearer "+token)
+			}
+		},
+		// SSE responses must reach the client as they are written.
+		FlushInterval: -1,
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		callerToken, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || callerToken == "" {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "a bearer token is required", http.StatusUnauthorized)
+			return
+		}
+
+		allowed, err := authorizer.Authorize(r.Context(), callerToken)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to authorize the request: %v", err), http.StatusServiceUnavailable)
+			return
+		}
+		if !allowed {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+
+		reverseProxy.ServeHTTP(w, r)
+	})
+}
+
+// reviewAuthorizer admits callers whose token is accepted by a TokenReview and who are allowed to get the
+// MCPServer according to a SubjectAccessReview.
+type reviewAuthorizer struct {
+	reviewer  *accessreview.Reviewer
+	name      string
+	namespace string
+
+	mu      sync.Mutex
+	reviews map[[sha256.Size]byte]review
+}
+
+type review struct {
+	allowed bool
+	expires time.Time
+}
+
+// NewReviewAuthorizer returns an Authorizer that asks the API server whether the caller may get the MCPServer
+// name in namespace.
+func NewReviewAuthorizer(clientset kubernetes.Interface, name, namespace string) Authorizer {
+	return &reviewAuthorizer{
+		reviewer:  &accessreview.Reviewer{Clientset: clientset},
+		name:      name,
+		namespace: namespace,
+		reviews:   map[[sha256.Size]byte]review{},
+	}
+}
+
+func (a *reviewAuthorizer) Authorize(ctx context.Context, token string) (bool, error) {
+	key := sha256.Sum256([]byte(token))
+	now := time.Now()
+
+	a.mu.Lock()
+	cached, ok := a.reviews[key]
+	a.mu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.allowed, nil
+	}
+
+	allowed, err := a.review(ctx, token)
+	if err != nil {
+		return false, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for k, r := range a.reviews {
+		if now.After(r.expires) {
+			delete(a.reviews, k)
+		}
+	}
+	a.reviews[key] = review{allowed: allowed, expires: now.Add(authorizationTTL)}
+	return allowed, nil
+}
+
+func (a *reviewAuthorizer) review(ctx context.Context, token string) (bool, error) {
+	user, err := a.reviewer.Authenticate(ctx, token)
+	if err != nil || user == nil {
+		return false, err
+	}
+	return a.reviewer.Allowed(ctx, user, authorizationv1.ResourceAttributes{
+		Namespace: a.namespace,
+		Verb:      "get",
+		Group:     mcpserverv1.GroupVersion.Group,
+		Resource:  "mcpservers",
+		Name:      a.name,
+	})
+}
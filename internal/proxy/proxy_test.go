@@ -0,0 +1,179 @@
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+type staticAuthorizer map[string]bool
+
+func (a staticAuthorizer) Authorize(_ context.Context, token string) (bool, error) {
+	return a[token], nil
+}
+
+func TestNew(t *testing.T) {
+	var gotPath, gotQuery, gotAuthorization string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery, gotAuthorization = r.URL.Path, r.URL.RawQuery, r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+
+	upstreamURL, err := url.Parse(upstream.URL + "/v1/sse?tenant=a")
+	if err != nil {
+		t.Fatalf("failed to parse the upstream URL: %v", err)
+	}
+	authorizer := staticAuthorizer{"allowed": true}
+
+	tests := []struct {
+		name              string
+		path              string
+		callerToken       string
+		upstreamToken     string
+		wantStatus        int
+		wantPath          string
+		wantQuery         string
+		wantAuthorization string
+	}{
+		{
+			name:       "missing token",
+			path:       "/sse",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:        "forbidden caller",
+			path:        "/sse",
+			callerToken: "denied",
+			wantStatus:  http.StatusForbidden,
+		},
+		{
+			name:              "SSE stream",
+			path:              "/sse",
+			callerToken:       "allowed",
+			upstreamToken:     "upstream",
+			wantStatus:        http.StatusOK,
+			wantPath:          "/v1/sse",
+			wantQuery:         "tenant=a",
+			wantAuthorization: "Bearer upstream",
+		},
+		{
+			name:              "message endpoint",
+			path:              "/messages?sessionId=1",
+			callerToken:       "allowed",
+			upstreamToken:     "upstream",
+			wantStatus:        http.StatusOK,
+			wantPath:          "/messages",
+			wantQuery:         "sessionId=1",
+			wantAuthorization: "Bearer upstream",
+		},
+		{
+			name:        "caller token is not forwarded",
+			path:        "/sse",
+			callerToken: "allowed",
+			wantStatus:  http.StatusOK,
+			wantPath:    "/v1/sse",
+			wantQuery:   "tenant=a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotPath, gotQuery, gotAuthorization = "", "", ""
+			proxy := httptest.NewServer(New(upstreamURL, "/sse", tt.upstreamToken, authorizer))
+			defer proxy.Close()
+
+			req, err := http.NewRequest(http.MethodGet, proxy.URL+tt.path, nil)
+			if err != nil {
+				t.Fatalf("failed to create the request: %v", err)
+			}
+			if tt.callerToken != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.callerToken)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if gotPath != tt.wantPath || gotQuery != tt.wantQuery {
+				t.Errorf("upstream request = %s?%s, want %s?%s", gotPath, gotQuery, tt.wantPath, tt.wantQuery)
+			}
+			if gotAuthorization != tt.wantAuthorization {
+				t.Errorf("upstream Authorization = %q, want %q", gotAuthorization, tt.wantAuthorization)
+			}
+		})
+	}
+}
+
+func TestReviewAuthorizer(t *testing.T) {
+	tests := []struct {
+		name          string
+		authenticated bool
+		allowed       bool
+		want          bool
+	}{
+		{
+			name: "unauthenticated",
+		},
+		{
+			name:          "not allowed to get the MCPServer",
+			authenticated: true,
+		},
+		{
+			name:          "allowed to get the MCPServer",
+			authenticated: true,
+			allowed:       true,
+			want:          true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotAttributes *authorizationv1.ResourceAttributes
+			reviews := 0
+			clientset := fake.NewClientset()
+			clientset.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				reviews++
+				review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+				review.Status.Authenticated = tt.authenticated
+				review.Status.User = authenticationv1.UserInfo{Username: "alice"}
+				return true, review, nil
+			})
+			clientset.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+				gotAttributes = review.Spec.ResourceAttributes
+				review.Status.Allowed = tt.allowed
+				return true, review, nil
+			})
+
+			authorizer := NewReviewAuthorizer(clientset, "test-mcpserver", "test-namespace")
+			for range 2 {
+				got, err := authorizer.Authorize(context.Background(), "token")
+				if err != nil {
+					t.Fatalf("Authorize() error = %v", err)
+				}
+				if got != tt.want {
+					t.Errorf("Authorize() = %v, want %v", got, tt.want)
+				}
+			}
+
+			if reviews != 1 {
+				t.Errorf("TokenReviews = %d, want the second call to be cached", reviews)
+			}
+			if tt.authenticated && (gotAttributes == nil || gotAttributes.Name != "test-mcpserver" ||
+				gotAttributes.Namespace != "test-namespace" || gotAttributes.Resource != "mcpservers") {
+				t.Errorf("SubjectAccessReview attributes = %+v", gotAttributes)
+			}
+		})
+	}
+}
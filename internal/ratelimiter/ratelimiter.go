@@ -0,0 +1,223 @@
+// Package ratelimiter implements the rate-limiter subcommand of the manager binary. It runs as a sidecar in front
+// of MCP servers that set spec.rateLimit.local and rejects the requests of clients that exceed their rate with
+// 429 Too Many Requests, so that a misbehaving agent loop cannot overwhelm the API the MCP server wraps. Each
+// client has a token bucket of its own, keyed by its address or by its bearer token.
+package ratelimiter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+const (
+	// Command is the name of the subcommand.
+	Command = "rate-limiter"
+
+	// HealthPath is where the rate limiter reports its health on the health port.
+	HealthPath = "/healthz"
+
+	// maxClients caps the clients with a bucket of their own. Once reached, new clients share a single bucket
+	// until idle clients are forgotten.
+	maxClients = 100000
+)
+
+// Run starts the rate limiter with the given arguments and returns the process exit code.
+func Run(args []string) int {
+	fs := flag.NewFlagSet(Command, flag.ContinueOnError)
+	upstream := fs.String("upstream", "http://localhost:8000", "The URL of the MCP server the rate limiter runs in front of.")
+	port := fs.Int("port", 8070, "The port the rate limiter listens on.")
+	healthPort := fs.Int("health-port", 8071, "The port the health of the rate limiter is served on.")
+	requestsPerSecond := fs.Int("requests-per-second", 0, "The sustained rate of requests each client may send.")
+	burst := fs.Int("burst", 0, "The number of requests a client may send at once, defaults to --requests-per-second.")
+	key := fs.String("key", string(mcpserverv1.RateLimitClientIP), "What identifies a client: ClientIP or Identity.")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *requestsPerSecond <= 0 {
+		_, _ = fmt.Fprintln(os.Stderr, "--requests-per-second must be positive")
+		return 2
+	}
+	if *burst <= 0 {
+		*burst = *requestsPerSecond
+	}
+	if mcpserverv1.RateLimitKey(*key) != mcpserverv1.RateLimitClientIP && mcpserverv1.RateLimitKey(*key) != mcpserverv1.RateLimitIdentity {
+		_, _ = fmt.Fprintf(os.Stderr, "invalid --key %q, must be ClientIP or Identity\n", *key)
+		return 2
+	}
+	upstreamURL, err := url.Parse(*upstream)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "invalid --upstream: %v\n", err)
+		return 2
+	}
+
+	health := http.NewServeMux()
+	health.HandleFunc("GET "+HealthPath, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintln(w, "ok")
+	})
+	healthServer := &http.Server{
+		Addr:              fmt.Sprintf(":%d", *healthPort),
+		Handler:           health,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		if err := healthServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			_, _ = fmt.Fprintf(os.Stderr, "health server failed: %v\n", err)
+			os.Exit(1)
+		}
+	}()
+
+	limiter := NewLimiter(rate.Limit(*requestsPerSecond), *burst, mcpserverv1.RateLimitKey(*key))
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", *port),
+		Handler:           New(upstreamURL, limiter),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	_, _ = fmt.Fprintf(os.Stdout, "limiting port %d to %s at %d requests per second and %s, burst %d\n",
+		*port, upstreamURL.Redacted(), *requestsPerSecond, *key, *burst)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		_, _ = fmt.Fprintf(os.Stderr, "rate limiter failed: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// Limiter keeps a token bucket per client.
+type Limiter struct {
+	limit rate.Limit
+	burst int
+	key   mcpserverv1.RateLimitKey
+
+	mu      sync.Mutex
+	clients map[string]*client
+	// overflow is shared by the clients that arrive while maxClients are known.
+	overflow *rate.Limiter
+}
+
+type client struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// NewLimiter returns a Limiter that lets each client identified by key send limit requests per second, and up
+// to burst at once.
+func NewLimiter(limit rate.Limit, burst int, key mcpserverv1.RateLimitKey) *Limiter {
+	return &Limiter{
+		limit:    limit,
+		burst:    burst,
+		key:      key,
+		clients:  map[string]*client{},
+		overflow: rate.NewLimiter(limit, burst),
+	}
+}
+
+// Allow reports whether the client of r may send a request now, and otherwise how long it should wait.
+func (l *Limiter) Allow(r *http.Request, now time.Time) (bool, time.Duration) {
+	limiter := l.clientLimiter(l.clientKey(r), now)
+	reservation := limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
+
+func (l *Limiter) clientLimiter(key string, now time.Time) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if c, ok := l.clients[key]; ok {
+		c.lastSeen = now
+		return c.limiter
+	}
+	if len(l.clients) >= maxClients {
+		l.prune(now)
+		if len(l.clients) >= maxClients {
+			return l.overflow
+		}
+	}
+	c := &client{limiter: rate.NewLimiter(l.limit, l.burst), lastSeen: now}
+	l.clients[key] = c
+	return c.limiter
+}
+
+// prune forgets the clients whose bucket has refilled since their last request, as a new bucket would be the
+// same. l.mu must be held.
+func (l *Limiter) prune(now time.Time) {
+	refill := time.Duration(math.Ceil(float64(l.burst) / float64(l.limit) * float64(time.Second)))
+	for key, c := range l.clients {
+		if now.Sub(c.lastSeen) > refill {
+			delete(l.clients, key)
+		}
+	}
+}
+
+// clientKey returns what identifies the client of r: a hash of its bearer token with the Identity key, and
+// otherwise its address.
+func (l *Limiter) clientKey(r *http.Request) string {
+	if l.key == mcpserverv1.RateLimitIdentity {
+		if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && token != "" {
+			// Only a hash of the token is kept in memory.
+			sum := sha256.Sum256([]byte(token))
+			return "token:" + hex.EncodeToString(sum[:])
+		}
+	}
+	return "ip:" + clientIP(r)
+}
+
+// clientIP returns the address of the client of r. Behind a Route or Gateway, the address the router saw is the
+// last one it appended to X-Forwarded-For, entries before it are set by the client and cannot be trusted.
+func clientIP(r *http.Request) string {
+	if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+		last := forwarded[len(forwarded)-1]
+		if i := strings.LastIndexByte(last, ','); i >= 0 {
+			last = last[i+1:]
+		}
+		if ip := strings.TrimSpace(last); ip != "" {
+			return ip
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// New returns a handler that forwards the requests of clients within their rate to upstream and rejects the
+// others with 429 Too Many Requests.
+func New(upstream *url.URL, limiter *Limiter) http.Handler {
+	reverseProxy := &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.SetURL(upstream)
+			r.Out.Header["X-Forwarded-For"] = r.In.Header["X-Forwarded-For"]
+		},
+		// SSE responses must reach the client as they are written.
+		FlushInterval: -1,
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ok, delay := limiter.Allow(r, time.Now()); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+			http.Error(w, "rate limit exceeded, retry later", http.StatusTooManyRequests)
+			return
+		}
+		reverseProxy.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,112 @@
+package ratelimiter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+func newRequest(remoteAddr, forwardedFor, token string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	r.RemoteAddr = remoteAddr
+	if forwardedFor != "" {
+		r.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	return r
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name     string
+		key      mcpserverv1.RateLimitKey
+		first    *http.Request
+		second   *http.Request
+		wantSame bool
+	}{
+		{
+			name:     "same address",
+			key:      mcpserverv1.RateLimitClientIP,
+			first:    newRequest("10.0.0.1:1234", "", ""),
+			second:   newRequest("10.0.0.1:5678", "", ""),
+			wantSame: true,
+		},
+		{
+			name:   "different addresses",
+			key:    mcpserverv1.RateLimitClientIP,
+			first:  newRequest("10.0.0.1:1234", "", ""),
+			second: newRequest("10.0.0.2:1234", "", ""),
+		},
+		{
+			name:     "address appended by the router",
+			key:      mcpserverv1.RateLimitClientIP,
+			first:    newRequest("10.128.0.5:1234", "1.2.3.4, 192.0.2.7", ""),
+			second:   newRequest("10.128.0.6:1234", "5.6.7.8, 192.0.2.7", ""),
+			wantSame: true,
+		},
+		{
+			name:   "different tokens from the same address",
+			key:    mcpserverv1.RateLimitIdentity,
+			first:  newRequest("10.0.0.1:1234", "", "agent-a"),
+			second: newRequest("10.0.0.1:1234", "", "agent-b"),
+		},
+		{
+			name:     "same token from different addresses",
+			key:      mcpserverv1.RateLimitIdentity,
+			first:    newRequest("10.0.0.1:1234", "", "agent-a"),
+			second:   newRequest("10.0.0.2:1234", "", "agent-a"),
+			wantSame: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewLimiter(1, 1, tt.key)
+			if ok, _ := limiter.Allow(tt.first, now); !ok {
+				t.Fatalf("first request rejected")
+			}
+			ok, delay := limiter.Allow(tt.second, now)
+			if ok == tt.wantSame {
+				t.Errorf("second request allowed = %v, want %v", ok, !tt.wantSame)
+			}
+			if !ok && delay <= 0 {
+				t.Errorf("delay = %v, want a positive delay", delay)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer upstream.Close()
+	upstreamURL, err := url.Parse(upstream.URL)
+	if err != nil {
+		t.Fatalf("failed to parse the upstream URL: %v", err)
+	}
+	limiter := httptest.NewServer(New(upstreamURL, NewLimiter(rate.Every(time.Hour), 2, mcpserverv1.RateLimitClientIP)))
+	defer limiter.Close()
+
+	for i, want := range []int{http.StatusAccepted, http.StatusAccepted, http.StatusTooManyRequests} {
+		resp, err := http.Post(limiter.URL, "application/json", nil)
+		if err != nil {
+			t.Fatalf("request %d failed: %v", i, err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("request %d: status = %d, want %d", i, resp.StatusCode, want)
+		}
+		if want == http.StatusTooManyRequests && resp.Header.Get("Retry-After") == "" {
+			t.Errorf("request %d: no Retry-After header", i)
+		}
+	}
+}
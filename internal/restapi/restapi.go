@@ -0,0 +1,200 @@
+// Package restapi implements an optional REST API to create, list, get and delete MCPServers, served next to
+// the discovery API. It lets the ODH dashboard and other UIs manage MCP servers without access to the CRD: every
+// request is authorized with a SubjectAccessReview for the caller and then performed by the operator, so that
+// the API server validates the MCPServer exactly as for a kubectl user.
+package restapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/internal/accessreview"
+)
+
+const (
+	// CreatedByAnnotation records the user an MCPServer was created for through the REST API, as the
+	// operator is the one creating it.
+	CreatedByAnnotation = "mcpserver.opendatahub.io/created-by"
+
+	collectionPath = "/api/v1/namespaces/{namespace}/mcpservers"
+	itemPath       = collectionPath + "/{name}"
+
+	// maxBodySize caps the size of a submitted MCPServer.
+	maxBodySize = 1 << 20
+)
+
+// API serves the REST API. It implements discovery.Routes.
+type API struct {
+	// Client performs the requests on behalf of the callers.
+	Client client.Client
+	// Reviewer authenticates and authorizes the callers.
+	Reviewer *accessreview.Reviewer
+}
+
+// Register adds the REST API to mux.
+func (a *API) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET "+collectionPath, a.list)
+	mux.HandleFunc("POST "+collectionPath, a.create)
+	mux.HandleFunc("GET "+itemPath, a.get)
+	mux.HandleFunc("DELETE "+itemPath, a.delete)
+}
+
+func (a *API) list(w http.ResponseWriter, r *http.Request) {
+	namespace := r.PathValue("namespace")
+	if !a.authorize(w, r, namespace, "list", "") {
+		return
+	}
+
+	list := &mcpserverv1.MCPServerList{}
+	if err := a.Client.List(r.Context(), list, client.InNamespace(namespace)); err != nil {
+		writeError(w, err)
+		return
+	}
+	list.APIVersion = mcpserverv1.GroupVersion.String()
+	list.Kind = "MCPServerList"
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (a *API) get(w http.ResponseWriter, r *http.Request) {
+	namespace, name := r.PathValue("namespace"), r.PathValue("name")
+	if !a.authorize(w, r, namespace, "get", name) {
+		return
+	}
+
+	cr := &mcpserverv1.MCPServer{}
+	if err := a.Client.Get(r.Context(), client.ObjectKey{Namespace: namespace, Name: name}, cr); err != nil {
+		writeError(w, err)
+		return
+	}
+	setTypeMeta(cr)
+	writeJSON(w, http.StatusOK, cr)
+}
+
+// create creates the submitted MCPServer in the namespace of the path. With ?dryRun=All the MCPServer is only
+// validated.
+func (a *API) create(w http.ResponseWriter, r *http.Request) {
+	namespace := r.PathValue("namespace")
+	user := a.Reviewer.AuthenticateRequest(w, r)
+	if user == nil || !a.allowed(w, r, user, namespace, "create", "") {
+		return
+	}
+
+	cr := &mcpserverv1.MCPServer{}
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(cr); err != nil {
+		writeError(w, k8serr.NewBadRequest(fmt.Sprintf("invalid MCPServer: %v", err)))
+		return
+	}
+	if cr.Namespace != "" && cr.Namespace != namespace {
+		writeError(w, k8serr.NewBadRequest(fmt.Sprintf("the namespace of the MCPServer must be %s", namespace)))
+		return
+	}
+
+	// Only the fields a user may set are kept.
+	created := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:         cr.Name,
+			GenerateName: cr.GenerateName,
+			Namespace:    namespace,
+			Labels:       cr.Labels,
+			Annotations:  cr.Annotations,
+		},
+		Spec: cr.Spec,
+	}
+	if created.Annotations == nil {
+		created.Annotations = map[string]string{}
+	}
+	created.Annotations[CreatedByAnnotation] = user.Username
+
+	var opts []client.CreateOption
+	if dryRun := r.URL.Query().Get("dryRun"); dryRun == metav1.DryRunAll {
+		opts = append(opts, client.DryRunAll)
+	} else if dryRun != "" {
+		writeError(w, k8serr.NewBadRequest(fmt.Sprintf("unsupported dryRun %q, only %s is supported", dryRun, metav1.DryRunAll)))
+		return
+	}
+	if err := a.Client.Create(r.Context(), created, opts...); err != nil {
+		writeError(w, err)
+		return
+	}
+	setTypeMeta(created)
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (a *API) delete(w http.ResponseWriter, r *http.Request) {
+	namespace, name := r.PathValue("namespace"), r.PathValue("name")
+	if !a.authorize(w, r, namespace, "delete", name) {
+		return
+	}
+
+	cr := &mcpserverv1.MCPServer{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+	if err := a.Client.Delete(r.Context(), cr); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, &metav1.Status{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Status"},
+		Status:   metav1.StatusSuccess,
+		Details:  &metav1.StatusDetails{Name: name, Group: mcpserverv1.GroupVersion.Group, Kind: "mcpservers"},
+	})
+}
+
+// authorize authenticates the caller and checks they may perform verb on MCPServers. An error response is
+// written when they may not.
+func (a *API) authorize(w http.ResponseWriter, r *http.Request, namespace, verb, name string) bool {
+	user := a.Reviewer.AuthenticateRequest(w, r)
+	return user != nil && a.allowed(w, r, user, namespace, verb, name)
+}
+
+func (a *API) allowed(w http.ResponseWriter, r *http.Request, user *authenticationv1.UserInfo,
+	namespace, verb, name string) bool {
+	allowed, err := a.Reviewer.Allowed(r.Context(), user, authorizationv1.ResourceAttributes{
+		Namespace: namespace,
+		Verb:      verb,
+		Group:     mcpserverv1.GroupVersion.Group,
+		Resource:  "mcpservers",
+		Name:      name,
+	})
+	if err != nil {
+		writeError(w, k8serr.NewServiceUnavailable(fmt.Sprintf("unable to authorize the request: %v", err)))
+		return false
+	}
+	if !allowed {
+		writeError(w, k8serr.NewForbidden(mcpserverv1.GroupVersion.WithResource("mcpservers").GroupResource(), name,
+			fmt.Errorf("user %q cannot %s MCPServers in namespace %q", user.Username, verb, namespace)))
+		return false
+	}
+	return true
+}
+
+func setTypeMeta(cr *mcpserverv1.MCPServer) {
+	cr.APIVersion = mcpserverv1.GroupVersion.String()
+	cr.Kind = "MCPServer"
+}
+
+// writeError writes err as a Kubernetes Status, so that clients get the same field causes as from the API server.
+func writeError(w http.ResponseWriter, err error) {
+	var apiStatus k8serr.APIStatus
+	if !errors.As(err, &apiStatus) {
+		apiStatus = k8serr.NewInternalError(err)
+	}
+	status := apiStatus.Status()
+	status.APIVersion, status.Kind = "v1", "Status"
+	writeJSON(w, int(status.Code), &status)
+}
+
+func writeJSON(w http.ResponseWriter, code int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
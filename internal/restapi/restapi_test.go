@@ -0,0 +1,144 @@
+package restapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/internal/accessreview"
+)
+
+func TestAPI(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := mcpserverv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add mcpserverv1 scheme: %v", err)
+	}
+
+	// alice may do anything with the MCPServers of team-a, and nothing else.
+	clientset := fake.NewClientset()
+	clientset.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		review.Status.Authenticated = review.Spec.Token == "alice"
+		review.Status.User = authenticationv1.UserInfo{Username: review.Spec.Token}
+		return true, review, nil
+	})
+	clientset.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		review.Status.Allowed = review.Spec.ResourceAttributes.Namespace == "team-a"
+		return true, review, nil
+	})
+
+	existing := &mcpserverv1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "team-a"},
+		Spec:       mcpserverv1.MCPServerSpec{Image: "quay.io/example/mcp:latest"},
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		check      func(t *testing.T, cli client.Client, body []byte)
+	}{
+		{
+			name:       "list",
+			method:     http.MethodGet,
+			path:       "/api/v1/namespaces/team-a/mcpservers",
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, _ client.Client, body []byte) {
+				list := &mcpserverv1.MCPServerList{}
+				if err := json.Unmarshal(body, list); err != nil || len(list.Items) != 1 {
+					t.Errorf("list = %s, want the existing MCPServer", body)
+				}
+			},
+		},
+		{
+			name:       "list in a namespace the caller has no access to",
+			method:     http.MethodGet,
+			path:       "/api/v1/namespaces/team-b/mcpservers",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "get a missing MCPServer",
+			method:     http.MethodGet,
+			path:       "/api/v1/namespaces/team-a/mcpservers/missing",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "create",
+			method:     http.MethodPost,
+			path:       "/api/v1/namespaces/team-a/mcpservers",
+			body:       `{"metadata":{"name":"new"},"spec":{"image":"quay.io/example/mcp:latest"}}`,
+			wantStatus: http.StatusCreated,
+			check: func(t *testing.T, cli client.Client, _ []byte) {
+				cr := &mcpserverv1.MCPServer{}
+				if err := cli.Get(context.Background(), client.ObjectKey{Namespace: "team-a", Name: "new"}, cr); err != nil {
+					t.Fatalf("failed to get the created MCPServer: %v", err)
+				}
+				if cr.Annotations[CreatedByAnnotation] != "alice" {
+					t.Errorf("created-by = %q, want alice", cr.Annotations[CreatedByAnnotation])
+				}
+			},
+		},
+		{
+			name:       "create with an unknown field",
+			method:     http.MethodPost,
+			path:       "/api/v1/namespaces/team-a/mcpservers",
+			body:       `{"metadata":{"name":"new"},"spec":{"imag":"quay.io/example/mcp:latest"}}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "create in another namespace than the path",
+			method:     http.MethodPost,
+			path:       "/api/v1/namespaces/team-a/mcpservers",
+			body:       `{"metadata":{"name":"new","namespace":"team-b"},"spec":{"image":"quay.io/example/mcp:latest"}}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "delete",
+			method:     http.MethodDelete,
+			path:       "/api/v1/namespaces/team-a/mcpservers/existing",
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, cli client.Client, _ []byte) {
+				err := cli.Get(context.Background(), client.ObjectKeyFromObject(existing), &mcpserverv1.MCPServer{})
+				if err == nil {
+					t.Errorf("expected the MCPServer to be deleted")
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(existing.DeepCopy()).Build()
+			api := &API{Client: cli, Reviewer: &accessreview.Reviewer{Clientset: clientset}}
+			mux := http.NewServeMux()
+			api.Register(mux)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer alice")
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.check != nil {
+				tt.check(t, cli, rec.Body.Bytes())
+			}
+		})
+	}
+}
@@ -0,0 +1,330 @@
+// Package restbridge implements the rest-bridge subcommand of the manager binary. It runs as a sidecar of MCP
+// servers with spec.restBridge enabled and offers their tools to clients that do not speak MCP: every tool is
+// a POST endpoint taking its arguments as JSON body, documented in an OpenAPI description generated from the
+// tool list of the server. The bridge calls the tools as an MCP client of the server, through the sidecars in
+// front of it, and passes on the token of the caller, so that REST clients are authenticated, rate limited and
+// filtered like MCP clients.
+package restbridge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/opendatahub-io/mcp-server-operator/pkg/mcp"
+)
+
+const (
+	// Command is the name of the subcommand.
+	Command = "rest-bridge"
+
+	// HealthPath is where the bridge reports that it is running.
+	HealthPath = "/healthz"
+
+	// OpenAPIPath is where the OpenAPI description of the tools is served.
+	OpenAPIPath = "/openapi.json"
+
+	// ToolsPath is the prefix of the endpoints of the tools, which are followed by the name of the tool.
+	ToolsPath = "/tools/"
+
+	// invalidParams is the JSON-RPC error code for requests with invalid arguments.
+	invalidParams = -32602
+
+	// maxBodySize caps the size of the arguments of a tool call.
+	maxBodySize = 10 << 20
+)
+
+// Run starts the bridge with the given arguments and returns the process exit code.
+func Run(args []string) int {
+	fs := flag.NewFlagSet(Command, flag.ContinueOnError)
+	upstream := fs.String("upstream", "http://localhost:8000/sse", "The SSE URL of the MCP server whose tools are bridged.")
+	port := fs.Int("port", 8040, "The port the REST API is served on.")
+	timeout := fs.Duration("timeout", 5*time.Minute, "How long a tool call may take.")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if _, err := url.Parse(*upstream); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "invalid --upstream: %v\n", err)
+		return 2
+	}
+
+	bridge := &Bridge{Upstream: *upstream, Timeout: *timeout}
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", *port),
+		Handler:           bridge.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	_, _ = fmt.Fprintf(os.Stdout, "serving the tools of %s as REST API on port %d\n", *upstream, *port)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		_, _ = fmt.Fprintf(os.Stderr, "REST bridge failed: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// Bridge serves the tools of an MCP server as REST API.
+type Bridge struct {
+	// Upstream is the SSE URL of the MCP server.
+	Upstream string
+	// HTTPClient connects to the MCP server, http.DefaultClient when nil.
+	HTTPClient *http.Client
+	// Timeout bounds each request to the MCP server, unbounded when zero.
+	Timeout time.Duration
+}
+
+// Handler returns the handler of the REST API: GET /openapi.json describes the tools, POST /tools/{name} calls
+// the tool with the JSON object of its body as arguments and answers with the result of the tool, with status 422
+// when the tool reports an error.
+func (b *Bridge) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+HealthPath, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("GET "+OpenAPIPath, b.serveOpenAPI)
+	mux.HandleFunc("POST "+ToolsPath+"{name}", b.callTool)
+	return mux
+}
+
+// connect opens an initialized session to the MCP server with the bearer token of r, if any.
+func (b *Bridge) connect(ctx context.Context, r *http.Request) (*mcp.Session, *mcp.InitializeResult, error) {
+	httpClient := b.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && token != "" {
+		httpClient = mcp.WithBearerToken(httpClient, token)
+	}
+	session, err := mcp.Connect(ctx, httpClient, b.Upstream)
+	if err != nil {
+		return nil, nil, err
+	}
+	result, err := session.Initialize(ctx)
+	if err != nil {
+		_ = session.Close()
+		return nil, nil, err
+	}
+	return session, result, nil
+}
+
+// listTools returns the tools of the MCP server, none when it does not offer the tools capability.
+func listTools(ctx context.Context, session *mcp.Session, result *mcp.InitializeResult) ([]mcp.Tool, error) {
+	if _, ok := result.Capabilities["tools"]; !ok {
+		return nil, nil
+	}
+	return session.ListTools(ctx)
+}
+
+func (b *Bridge) context(r *http.Request) (context.Context, context.CancelFunc) {
+	if b.Timeout <= 0 {
+		return context.WithCancel(r.Context())
+	}
+	return context.WithTimeout(r.Context(), b.Timeout)
+}
+
+func (b *Bridge) serveOpenAPI(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := b.context(r)
+	defer cancel()
+
+	session, result, err := b.connect(ctx, r)
+	if err != nil {
+		writeUpstreamError(w, err)
+		return
+	}
+	defer func() {
+		_ = session.Close()
+	}()
+	tools, err := listTools(ctx, session, result)
+	if err != nil {
+		writeUpstreamError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, Document(result, tools))
+}
+
+func (b *Bridge) callTool(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("the arguments exceed %d bytes", maxBodySize))
+		return
+	}
+	var arguments map[string]any
+	if len(strings.TrimSpace(string(body))) > 0 {
+		if err := json.Unmarshal(body, &arguments); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("the arguments must be a JSON object: %v", err))
+			return
+		}
+	}
+
+	ctx, cancel := b.context(r)
+	defer cancel()
+
+	session, result, err := b.connect(ctx, r)
+	if err != nil {
+		writeUpstreamError(w, err)
+		return
+	}
+	defer func() {
+		_ = session.Close()
+	}()
+	tools, err := listTools(ctx, session, result)
+	if err != nil {
+		writeUpstreamError(w, err)
+		return
+	}
+	found := false
+	for _, tool := range tools {
+		found = found || tool.Name == name
+	}
+	if !found {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("the MCP server has no tool %q", name))
+		return
+	}
+
+	params := map[string]any{"name": name}
+	if len(arguments) > 0 {
+		params["arguments"] = arguments
+	}
+	var raw json.RawMessage
+	if err := session.Call(ctx, "tools/call", params, &raw); err != nil {
+		writeUpstreamError(w, err)
+		return
+	}
+	toolResult := struct {
+		IsError bool `json:"isError"`
+	}{}
+	_ = json.Unmarshal(raw, &toolResult)
+	code := http.StatusOK
+	if toolResult.IsError {
+		code = http.StatusUnprocessableEntity
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_, _ = w.Write(raw)
+}
+
+// Document returns the OpenAPI description of the tools of the MCP server that answered initialize with result.
+// The input schema of each tool is the schema of the request body of its endpoint, which OpenAPI 3.1 takes as
+// is.
+func Document(result *mcp.InitializeResult, tools []mcp.Tool) map[string]any {
+	title := result.ServerInfo.Name
+	if title == "" {
+		title = "MCP server"
+	}
+	version := result.ServerInfo.Version
+	if version == "" {
+		version = "unknown"
+	}
+	info := map[string]any{"title": title, "version": version}
+	if result.Instructions != "" {
+		info["description"] = result.Instructions
+	}
+
+	response := func(description, schema string) map[string]any {
+		return map[string]any{
+			"description": description,
+			"content": map[string]any{
+				"application/json": map[string]any{
+					"schema": map[string]any{"$ref": "#/components/schemas/" + schema},
+				},
+			},
+		}
+	}
+	paths := map[string]any{}
+	for _, tool := range tools {
+		schema := any(map[string]any{"type": "object"})
+		if len(tool.InputSchema) > 0 {
+			schema = tool.InputSchema
+		}
+		operation := map[string]any{
+			"operationId": tool.Name,
+			"requestBody": map[string]any{
+				"content": map[string]any{
+					"application/json": map[string]any{"schema": schema},
+				},
+			},
+			"responses": map[string]any{
+				"200":     response("The result of the tool.", "CallToolResult"),
+				"422":     response("The tool failed, the result describes the error.", "CallToolResult"),
+				"default": response("The tool could not be called.", "Error"),
+			},
+		}
+		if tool.Description != "" {
+			summary, _, _ := strings.Cut(tool.Description, "\n")
+			operation["summary"] = summary
+			operation["description"] = tool.Description
+		}
+		paths[ToolsPath+url.PathEscape(tool.Name)] = map[string]any{"post": operation}
+	}
+
+	return map[string]any{
+		"openapi": "3.1.0",
+		"info":    info,
+		"paths":   paths,
+		"components": map[string]any{
+			"schemas": map[string]any{
+				"CallToolResult": map[string]any{
+					"type":     "object",
+					"required": []string{"content"},
+					"properties": map[string]any{
+						"content": map[string]any{
+							"type": "array",
+							"items": map[string]any{
+								"type":     "object",
+								"required": []string{"type"},
+								"properties": map[string]any{
+									"type": map[string]any{"type": "string"},
+									"text": map[string]any{"type": "string"},
+								},
+							},
+						},
+						"structuredContent": map[string]any{"type": "object"},
+						"isError":           map[string]any{"type": "boolean"},
+					},
+				},
+				"Error": map[string]any{
+					"type":       "object",
+					"required":   []string{"message"},
+					"properties": map[string]any{"message": map[string]any{"type": "string"}},
+				},
+			},
+		},
+	}
+}
+
+// writeUpstreamError answers with the error of the MCP server: the status it rejected the token of the caller
+// with, 400 for invalid arguments, 504 when it did not answer in time and 502 otherwise.
+func writeUpstreamError(w http.ResponseWriter, err error) {
+	code := http.StatusBadGateway
+	var statusErr *mcp.StatusError
+	var rpcErr *mcp.RPCError
+	switch {
+	case errors.As(err, &statusErr) &&
+		(statusErr.StatusCode == http.StatusUnauthorized || statusErr.StatusCode == http.StatusForbidden ||
+			statusErr.StatusCode == http.StatusTooManyRequests):
+		code = statusErr.StatusCode
+	case errors.As(err, &rpcErr) && rpcErr.Code == invalidParams:
+		code = http.StatusBadRequest
+	case errors.Is(err, context.DeadlineExceeded):
+		code = http.StatusGatewayTimeout
+	}
+	writeError(w, code, err.Error())
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	writeJSON(w, code, map[string]string{"message": message})
+}
+
+func writeJSON(w http.ResponseWriter, code int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
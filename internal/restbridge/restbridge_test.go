@@ -0,0 +1,143 @@
+package restbridge
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/opendatahub-io/mcp-server-operator/pkg/mcp"
+	mcptesting "github.com/opendatahub-io/mcp-server-operator/pkg/testing"
+)
+
+func TestBridge(t *testing.T) {
+	fake := mcptesting.NewUnstartedServer(
+		mcptesting.Tool{Tool: mcp.Tool{
+			Name:        "echo",
+			Description: "Echoes the text.\nUseful for tests.",
+			InputSchema: json.RawMessage(`{"type":"object","properties":{"text":{"type":"string"}},"required":["text"]}`),
+		}},
+		mcptesting.Tool{
+			Tool: mcp.Tool{Name: "fail"},
+			Handler: func(map[string]any) mcp.CallToolResult {
+				return mcp.CallToolResult{Content: []mcp.Content{{Type: "text", Text: "boom"}}, IsError: true}
+			},
+		},
+	)
+	// The server only admits callers with the token, like one behind the token authentication sidecar.
+	handler := fake.Handler()
+	fake.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+	fake.Start()
+	defer fake.Close()
+	bridge := &Bridge{Upstream: fake.SSEURL()}
+
+	serve := func(method, path, token, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		bridge.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("Verify that every tool is documented with its input schema", func(t *testing.T) {
+		rec := serve(http.MethodGet, OpenAPIPath, "secret", "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+		}
+		document := struct {
+			OpenAPI string `json:"openapi"`
+			Info    struct {
+				Title string `json:"title"`
+			} `json:"info"`
+			Paths map[string]struct {
+				Post struct {
+					Summary     string `json:"summary"`
+					RequestBody struct {
+						Content map[string]struct {
+							Schema map[string]any `json:"schema"`
+						} `json:"content"`
+					} `json:"requestBody"`
+				} `json:"post"`
+			} `json:"paths"`
+		}{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &document); err != nil {
+			t.Fatalf("failed to decode the document: %v", err)
+		}
+		if document.OpenAPI != "3.1.0" || document.Info.Title != "fake" || len(document.Paths) != 2 {
+			t.Errorf("document = %+v, want an OpenAPI 3.1 description of the 2 tools of fake", document)
+		}
+		echo := document.Paths[ToolsPath+"echo"].Post
+		if echo.Summary != "Echoes the text." || echo.RequestBody.Content["application/json"].Schema["required"] == nil {
+			t.Errorf("echo = %+v, want the first line of its description and its input schema", echo)
+		}
+		if got := document.Paths[ToolsPath+"fail"].Post.RequestBody.Content["application/json"].Schema["type"]; got != "object" {
+			t.Errorf("fail schema type = %v, want object for a tool without input schema", got)
+		}
+	})
+
+	tests := []struct {
+		name     string
+		path     string
+		token    string
+		body     string
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "Verify that a tool is called with the body as arguments",
+			path:     ToolsPath + "echo",
+			token:    "secret",
+			body:     `{"text":"hello"}`,
+			wantCode: http.StatusOK,
+			wantBody: `"text":"hello"`,
+		},
+		{
+			name:     "Verify that an error of the tool is reported with its result",
+			path:     ToolsPath + "fail",
+			token:    "secret",
+			wantCode: http.StatusUnprocessableEntity,
+			wantBody: `"isError":true`,
+		},
+		{
+			name:     "Verify that an unknown tool is not found",
+			path:     ToolsPath + "missing",
+			token:    "secret",
+			body:     `{}`,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "Verify that arguments that are not an object are rejected",
+			path:     ToolsPath + "echo",
+			token:    "secret",
+			body:     `["hello"]`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "Verify that a caller the MCP server does not admit is unauthorized",
+			path:     ToolsPath + "echo",
+			body:     `{"text":"hello"}`,
+			wantCode: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(http.MethodPost, tt.path, tt.token, tt.body)
+			if rec.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body = %s, want it to contain %s", rec.Body, tt.wantBody)
+			}
+		})
+	}
+}
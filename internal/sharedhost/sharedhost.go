@@ -0,0 +1,289 @@
+// Package sharedhost implements the shared-host-router subcommand of the manager binary. It is run by the router
+// Deployment the operator creates in its own namespace when it is configured with a shared host: it publishes
+// every MCP server with a SharedHost exposure under the path /mcp/<namespace>/<name> of that host, behind a single
+// Route or HTTPRoute, so that the servers need no host name, DNS entry or certificate of their own.
+//
+// The routes are read from a file, a key of a ConfigMap the operator maintains, which is read again whenever it
+// changes, so that publishing a server does not restart the router and break the sessions of the other servers.
+// The path prefix is removed from the requests passed to a server, and added to the message endpoint the SSE
+// transport announces, so that clients post their messages under the prefix too.
+package sharedhost
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// Command is the name of the subcommand.
+	Command = "shared-host-router"
+
+	// PathPrefix is the prefix of the paths of all MCP servers on the shared host, followed by their namespace
+	// and name.
+	PathPrefix = "/mcp/"
+
+	// ForwardedPrefixHeader tells an MCP server the prefix its paths have on the shared host.
+	ForwardedPrefixHeader = "X-Forwarded-Prefix"
+
+	// reloadInterval is how often the routes file is checked for changes.
+	reloadInterval = 5 * time.Second
+)
+
+// Path returns the path of the MCP server name in namespace on the shared host, without a trailing slash.
+func Path(namespace, name string) string {
+	return PathPrefix + namespace + "/" + name
+}
+
+// Run starts the router with the given arguments and returns the process exit code.
+func Run(args []string) int {
+	fs := flag.NewFlagSet(Command, flag.ContinueOnError)
+	routesFile := fs.String("routes", "", "The JSON file that maps the path of each MCP server, "+
+		"/mcp/<namespace>/<name>, to the base URL of its Service.")
+	port := fs.Int("port", 8000, "The port the router listens on.")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *routesFile == "" {
+		_, _ = fmt.Fprintln(os.Stderr, "--routes is required")
+		return 2
+	}
+	data, err := os.ReadFile(*routesFile)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "unable to read the routes: %v\n", err)
+		return 1
+	}
+	routes, err := ParseRoutes(data)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "invalid routes: %v\n", err)
+		return 1
+	}
+
+	router := New(routes)
+	go router.Watch(context.Background(), *routesFile, data, reloadInterval)
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", *port),
+		Handler:           router,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	_, _ = fmt.Fprintf(os.Stdout, "routing port %d to %d MCP servers\n", *port, len(routes))
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		_, _ = fmt.Fprintf(os.Stderr, "router failed: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// ParseRoutes parses the content of a routes file, a JSON object that maps the path of each MCP server to the
+// base URL of its Service, e.g. {"/mcp/team-a/tools": "http://tools.team-a.svc:8000"}.
+func ParseRoutes(data []byte) (map[string]*url.URL, error) {
+	raw := map[string]string{}
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, err
+		}
+	}
+	routes := map[string]*url.URL{}
+	for path, backend := range raw {
+		namespace, name, rest, ok := splitPath(path)
+		if !ok || rest != "" {
+			return nil, fmt.Errorf("path %q is not of the form %s<namespace>/<name>", path, PathPrefix)
+		}
+		target, err := url.Parse(backend)
+		if err != nil || target.Scheme == "" || target.Host == "" {
+			return nil, fmt.Errorf("backend %q of %s is not an absolute URL", backend, path)
+		}
+		routes[Path(namespace, name)] = target
+	}
+	return routes, nil
+}
+
+// splitPath splits a path on the shared host into the namespace and name of its MCP server and the path of the
+// request to the server, which is empty or starts with a slash.
+func splitPath(path string) (namespace, name, rest string, ok bool) {
+	trimmed, found := strings.CutPrefix(path, PathPrefix)
+	if !found {
+		return "", "", "", false
+	}
+	namespace, trimmed, found = strings.Cut(trimmed, "/")
+	if !found || namespace == "" {
+		return "", "", "", false
+	}
+	name, rest, found = strings.Cut(trimmed, "/")
+	if name == "" {
+		return "", "", "", false
+	}
+	if found {
+		rest = "/" + rest
+	}
+	return namespace, name, rest, true
+}
+
+// Router routes the requests for the path of each MCP server on the shared host to its Service. It implements
+// http.Handler.
+type Router struct {
+	routes atomic.Pointer[map[string]*url.URL]
+	proxy  *httputil.ReverseProxy
+}
+
+// backendKey and prefixKey carry the backend and the path prefix of a request to the reverse proxy.
+type (
+	backendKey struct{}
+	prefixKey  struct{}
+)
+
+// New returns a Router with routes, keyed by the path of each MCP server.
+func New(routes map[string]*url.URL) *Router {
+	router := &Router{}
+	router.SetRoutes(routes)
+	router.proxy = &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			backend := r.In.Context().Value(backendKey{}).(*url.URL)
+			prefix := r.In.Context().Value(prefixKey{}).(string)
+			r.Out.URL.Path = strings.TrimPrefix(r.In.URL.Path, prefix)
+			r.Out.URL.RawPath = ""
+			r.SetURL(backend)
+			r.SetXForwarded()
+			r.Out.Header.Set(ForwardedPrefixHeader, prefix)
+		},
+		ModifyResponse: func(resp *http.Response) error {
+			prefix := resp.Request.Context().Value(prefixKey{}).(string)
+			if location := resp.Header.Get("Location"); strings.HasPrefix(location, "/") &&
+				!strings.HasPrefix(location, "//") {
+				resp.Header.Set("Location", prefix+location)
+			}
+			if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+				// The rewritten stream is longer than the one the server sent.
+				resp.Header.Del("Content-Length")
+				resp.ContentLength = -1
+				resp.Body = newEndpointRewriter(resp.Body, prefix)
+			}
+			return nil
+		},
+	}
+	return router
+}
+
+// SetRoutes replaces the routes of the router. Requests in flight keep their backend.
+func (r *Router) SetRoutes(routes map[string]*url.URL) {
+	r.routes.Store(&routes)
+}
+
+// ServeHTTP passes the request to the MCP server its path starts with, without the path prefix. Requests for
+// unknown MCP servers are answered with 404 Not Found.
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	namespace, name, _, ok := splitPath(req.URL.Path)
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+	prefix := Path(namespace, name)
+	backend, ok := (*r.routes.Load())[prefix]
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+	ctx := context.WithValue(req.Context(), backendKey{}, backend)
+	ctx = context.WithValue(ctx, prefixKey{}, prefix)
+	r.proxy.ServeHTTP(w, req.WithContext(ctx))
+}
+
+// Watch reads the routes file at path every interval until ctx is done, and replaces the routes of the router
+// when its content differs from current. A file that cannot be read or parsed leaves the routes unchanged, as the
+// kubelet replaces the file of a ConfigMap atomically and the next read sees it whole.
+func (r *Router) Watch(ctx context.Context, path string, current []byte, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		data, err := os.ReadFile(path)
+		if err != nil || bytes.Equal(data, current) {
+			continue
+		}
+		routes, err := ParseRoutes(data)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "ignoring invalid routes: %v\n", err)
+			continue
+		}
+		r.SetRoutes(routes)
+		current = data
+		_, _ = fmt.Fprintf(os.Stdout, "routing to %d MCP servers\n", len(routes))
+	}
+}
+
+// endpointRewriter adds the path prefix of an MCP server to the message endpoint announced in the endpoint events
+// of its SSE stream.
+type endpointRewriter struct {
+	src      *bufio.Reader
+	closer   io.Closer
+	prefix   string
+	endpoint bool
+	buf      []byte
+	err      error
+}
+
+func newEndpointRewriter(body io.ReadCloser, prefix string) io.ReadCloser {
+	return &endpointRewriter{src: bufio.NewReader(body), closer: body, prefix: prefix}
+}
+
+// Read returns the stream line by line, as each line may have to be rewritten.
+func (e *endpointRewriter) Read(p []byte) (int, error) {
+	for len(e.buf) == 0 {
+		if e.err != nil {
+			return 0, e.err
+		}
+		line, err := e.src.ReadString('\n')
+		e.buf = []byte(e.rewrite(line))
+		e.err = err
+	}
+	n := copy(p, e.buf)
+	e.buf = e.buf[n:]
+	return n, nil
+}
+
+func (e *endpointRewriter) Close() error {
+	return e.closer.Close()
+}
+
+// rewrite returns line, with the path prefix added if it is the data of an endpoint event. An absolute URL is
+// turned into a path, as it names the Service rather than the shared host. A relative path already resolves
+// under the prefix and is left alone.
+func (e *endpointRewriter) rewrite(line string) string {
+	content := strings.TrimRight(line, "\r\n")
+	if field, value, ok := strings.Cut(content, ":"); ok && field == "event" {
+		e.endpoint = strings.TrimSpace(value) == "endpoint"
+		return line
+	}
+	if content == "" {
+		e.endpoint = false
+		return line
+	}
+	data, ok := strings.CutPrefix(content, "data:")
+	if !e.endpoint || !ok {
+		return line
+	}
+	endpoint, err := url.Parse(strings.TrimSpace(data))
+	if err != nil || (endpoint.Host == "" && !strings.HasPrefix(endpoint.Path, "/")) {
+		return line
+	}
+	endpoint.Scheme, endpoint.Host, endpoint.User = "", "", nil
+	endpoint.Path = e.prefix + endpoint.Path
+	endpoint.RawPath = ""
+	return "data: " + endpoint.String() + line[len(content):]
+}
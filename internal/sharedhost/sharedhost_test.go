@@ -0,0 +1,204 @@
+package sharedhost
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newServer returns an MCP server named name that answers GET /sse with an endpoint event announcing endpoint,
+// and other requests with its name and the path and prefix they were received with.
+func newServer(t *testing.T, name, endpoint string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && r.URL.Path == "/sse" {
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = fmt.Fprintf(w, "event: endpoint\r\ndata: %s\r\n\r\n", endpoint)
+			_, _ = fmt.Fprint(w, "event: message\ndata: /unchanged\n\n")
+			return
+		}
+		_, _ = fmt.Fprintf(w, "%s %s %s", name, r.URL.RequestURI(), r.Header.Get(ForwardedPrefixHeader))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func mustParse(t *testing.T, raw string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("url.Parse() error = %v", err)
+	}
+	return u
+}
+
+func TestRouter(t *testing.T) {
+	tools := newServer(t, "tools", "/messages?sessionId=1")
+	search := newServer(t, "search", "http://search.team-b.svc:8000/messages?session_id=2")
+	relative := newServer(t, "relative", "messages?sessionId=3")
+	router := httptest.NewServer(New(map[string]*url.URL{
+		Path("team-a", "tools"):    mustParse(t, tools.URL),
+		Path("team-b", "search"):   mustParse(t, search.URL),
+		Path("team-c", "relative"): mustParse(t, relative.URL),
+	}))
+	t.Cleanup(router.Close)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "request under the prefix",
+			method:     http.MethodPost,
+			path:       "/mcp/team-a/tools/mcp?x=1",
+			wantStatus: http.StatusOK,
+			wantBody:   "tools /mcp?x=1 /mcp/team-a/tools",
+		},
+		{
+			name:       "request for the prefix itself",
+			method:     http.MethodPost,
+			path:       "/mcp/team-b/search",
+			wantStatus: http.StatusOK,
+			wantBody:   "search / /mcp/team-b/search",
+		},
+		{
+			name:       "endpoint event with a path",
+			method:     http.MethodGet,
+			path:       "/mcp/team-a/tools/sse",
+			wantStatus: http.StatusOK,
+			wantBody: "event: endpoint\r\ndata: /mcp/team-a/tools/messages?sessionId=1\r\n\r\n" +
+				"event: message\ndata: /unchanged\n\n",
+		},
+		{
+			name:       "endpoint event with the URL of the Service",
+			method:     http.MethodGet,
+			path:       "/mcp/team-b/search/sse",
+			wantStatus: http.StatusOK,
+			wantBody: "event: endpoint\r\ndata: /mcp/team-b/search/messages?session_id=2\r\n\r\n" +
+				"event: message\ndata: /unchanged\n\n",
+		},
+		{
+			name:       "endpoint event with a relative path",
+			method:     http.MethodGet,
+			path:       "/mcp/team-c/relative/sse",
+			wantStatus: http.StatusOK,
+			wantBody:   "event: endpoint\r\ndata: messages?sessionId=3\r\n\r\nevent: message\ndata: /unchanged\n\n",
+		},
+		{name: "unknown MCP server", method: http.MethodPost, path: "/mcp/team-a/other/mcp", wantStatus: http.StatusNotFound},
+		{name: "MCP server of another namespace", method: http.MethodPost, path: "/mcp/team-b/tools", wantStatus: http.StatusNotFound},
+		{name: "path outside the prefix", method: http.MethodGet, path: "/team-a/tools", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, router.URL+tt.path, nil)
+			if err != nil {
+				t.Fatalf("NewRequest() error = %v", err)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Do() error = %v", err)
+			}
+			defer func() { _ = resp.Body.Close() }()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("ReadAll() error = %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantBody != "" && string(body) != tt.wantBody {
+				t.Errorf("body = %q, want %q", body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestParseRoutes(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]string
+		wantErr bool
+	}{
+		{name: "empty file", data: "", want: map[string]string{}},
+		{
+			name: "routes",
+			data: `{"/mcp/team-a/tools": "http://tools.team-a.svc:8000"}`,
+			want: map[string]string{"/mcp/team-a/tools": "http://tools.team-a.svc:8000"},
+		},
+		{name: "path outside the prefix", data: `{"/tools": "http://tools.team-a.svc:8000"}`, wantErr: true},
+		{name: "path with a subpath", data: `{"/mcp/team-a/tools/sse": "http://tools.team-a.svc:8000"}`, wantErr: true},
+		{name: "relative backend", data: `{"/mcp/team-a/tools": "tools:8000"}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			routes, err := ParseRoutes([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRoutes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got := map[string]string{}
+			for path, backend := range routes {
+				got[path] = backend.String()
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("ParseRoutes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRouter_Watch(t *testing.T) {
+	server := newServer(t, "tools", "/messages")
+	path := filepath.Join(t.TempDir(), "routes.json")
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+	write("{}")
+	router := New(map[string]*url.URL{})
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go router.Watch(ctx, path, []byte("{}"), 10*time.Millisecond)
+
+	status := func() int {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/mcp/team-a/tools/mcp", nil))
+		return rec.Code
+	}
+	waitFor := func(want int) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for status() != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("status = %d, want %d", status(), want)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	write(fmt.Sprintf(`{"/mcp/team-a/tools": %q}`, server.URL))
+	waitFor(http.StatusOK)
+
+	// Invalid routes leave the last ones in place.
+	write(`{"/tools": "tools"}`)
+	time.Sleep(50 * time.Millisecond)
+	waitFor(http.StatusOK)
+
+	write("{}")
+	waitFor(http.StatusNotFound)
+}
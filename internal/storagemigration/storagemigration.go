@@ -0,0 +1,167 @@
+// Package storagemigration rewrites the stored objects of a custom resource in its current storage version and
+// then drops older versions from the stored versions of its CustomResourceDefinition. Until that has happened,
+// a version that is no longer served cannot be removed from the CRD without stranding objects in etcd.
+package storagemigration
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster/gvk"
+)
+
+// listPageSize bounds the number of objects fetched per list request.
+const listPageSize = 500
+
+// +kubebuilder:rbac:groups="apiextensions.k8s.io",resources=customresourcedefinitions/status,verbs=update;patch
+
+var (
+	pendingObjects = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mcpserver_operator_storage_migration_pending_objects",
+		Help: "Number of objects of a custom resource that still have to be rewritten in the storage version.",
+	}, []string{"resource"})
+	migratedObjects = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "mcpserver_operator_storage_migration_migrated_objects_total",
+		Help: "Number of objects of a custom resource rewritten in the storage version.",
+	}, []string{"resource"})
+	migrationComplete = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mcpserver_operator_storage_migration_complete",
+		Help: "Whether all objects of a custom resource are stored in the storage version (1) or not (0).",
+	}, []string{"resource"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(pendingObjects, migratedObjects, migrationComplete)
+}
+
+// Migrator migrates the objects of a single custom resource once the manager is started.
+type Migrator struct {
+	// Reader reads the CRD and the objects directly from the API server.
+	Reader client.Reader
+	// Client writes the objects and the CRD status.
+	Client client.Client
+
+	// CRDName is the name of the CustomResourceDefinition, e.g. mcpservers.mcpserver.opendatahub.io.
+	CRDName string
+}
+
+// Start runs the migration. It implements manager.Runnable. A failed migration is logged and retried on the
+// next start of the operator, it never stops the manager.
+func (m *Migrator) Start(ctx context.Context) error {
+	logger := logf.FromContext(ctx).WithName("storagemigration").WithValues("crd", m.CRDName)
+	if err := m.Migrate(ctx); err != nil {
+		logger.Error(err, "storage version migration failed")
+	}
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable; only the leader migrates objects.
+func (m *Migrator) NeedLeaderElection() bool {
+	return true
+}
+
+// Migrate rewrites every object that may still be stored in an old version and then records the storage
+// version as the only stored version of the CRD.
+func (m *Migrator) Migrate(ctx context.Context) error {
+	logger := logf.FromContext(ctx).WithName("storagemigration").WithValues("crd", m.CRDName)
+
+	crd := &unstructured.Unstructured{}
+	crd.SetGroupVersionKind(gvk.CustomResourceDefinition)
+	if err := m.Reader.Get(ctx, client.ObjectKey{Name: m.CRDName}, crd); err != nil {
+		return err
+	}
+
+	storageVersion, err := getStorageVersion(crd)
+	if err != nil {
+		return err
+	}
+	storedVersions, _, err := unstructured.NestedStringSlice(crd.Object, "status", "storedVersions")
+	if err != nil {
+		return err
+	}
+	if len(storedVersions) == 1 && storedVersions[0] == storageVersion {
+		migrationComplete.WithLabelValues(m.CRDName).Set(1)
+		return nil
+	}
+	migrationComplete.WithLabelValues(m.CRDName).Set(0)
+
+	group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+	kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+	listGVK := schema.GroupVersionKind{Group: group, Version: storageVersion, Kind: kind + "List"}
+	logger.Info("Migrating objects to the storage version", "storedVersions", storedVersions, "storageVersion", storageVersion)
+
+	migrated := 0
+	continueToken := ""
+	for {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(listGVK)
+		err := m.Reader.List(ctx, list, client.Limit(listPageSize), client.Continue(continueToken))
+		if err != nil {
+			return err
+		}
+		if continueToken == "" {
+			remaining := int64(0)
+			if list.GetRemainingItemCount() != nil {
+				remaining = *list.GetRemainingItemCount()
+			}
+			pendingObjects.WithLabelValues(m.CRDName).Set(float64(int64(len(list.Items)) + remaining))
+		}
+
+		for i := range list.Items {
+			// An unchanged update makes the API server encode the object in the storage version again.
+			err := m.Client.Update(ctx, &list.Items[i])
+			// A conflicting or deleted object has been written, or removed, by someone else in the meantime.
+			if err != nil && !k8serr.IsConflict(err) && !k8serr.IsNotFound(err) {
+				return fmt.Errorf("migrating %s/%s: %w", list.Items[i].GetNamespace(), list.Items[i].GetName(), err)
+			}
+			migrated++
+			migratedObjects.WithLabelValues(m.CRDName).Inc()
+			pendingObjects.WithLabelValues(m.CRDName).Dec()
+		}
+
+		continueToken = list.GetContinue()
+		if continueToken == "" {
+			break
+		}
+	}
+
+	patch := client.MergeFrom(crd.DeepCopy())
+	if err := unstructured.SetNestedStringSlice(crd.Object, []string{storageVersion}, "status", "storedVersions"); err != nil {
+		return err
+	}
+	if err := m.Client.Status().Patch(ctx, crd, patch); err != nil {
+		return err
+	}
+
+	pendingObjects.WithLabelValues(m.CRDName).Set(0)
+	migrationComplete.WithLabelValues(m.CRDName).Set(1)
+	logger.Info("Migrated objects to the storage version", "objects", migrated, "storageVersion", storageVersion)
+	return nil
+}
+
+// getStorageVersion returns the version of the CRD that objects are stored in.
+func getStorageVersion(crd *unstructured.Unstructured) (string, error) {
+	versions, _, err := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	if err != nil {
+		return "", err
+	}
+	for _, version := range versions {
+		v, ok := version.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if storage, _ := v["storage"].(bool); storage {
+			name, _ := v["name"].(string)
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("CustomResourceDefinition %s has no storage version", crd.GetName())
+}
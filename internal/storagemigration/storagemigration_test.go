@@ -0,0 +1,109 @@
+package storagemigration
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster/gvk"
+)
+
+const crdName = "mcpservers.mcpserver.opendatahub.io"
+
+func newCRD(storedVersions ...string) *unstructured.Unstructured {
+	crd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"group": "mcpserver.opendatahub.io",
+			"names": map[string]interface{}{"kind": "MCPServer"},
+			"versions": []interface{}{
+				map[string]interface{}{"name": "v1alpha1", "served": false, "storage": false},
+				map[string]interface{}{"name": "v1", "served": true, "storage": true},
+			},
+		},
+	}}
+	crd.SetGroupVersionKind(gvk.CustomResourceDefinition)
+	crd.SetName(crdName)
+	_ = unstructured.SetNestedStringSlice(crd.Object, storedVersions, "status", "storedVersions")
+	return crd
+}
+
+func TestMigrator_Migrate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := mcpserverv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add mcpserverv1 scheme: %v", err)
+	}
+
+	mcpServers := []client.Object{
+		&mcpserverv1.MCPServer{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "ns"}},
+		&mcpserverv1.MCPServer{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "ns"}},
+	}
+
+	tests := []struct {
+		name               string
+		storedVersions     []string
+		wantStoredVersions []string
+		wantUpdates        int
+	}{
+		{
+			name:               "nothing to migrate",
+			storedVersions:     []string{"v1"},
+			wantStoredVersions: []string{"v1"},
+		},
+		{
+			name:               "old version is migrated",
+			storedVersions:     []string{"v1alpha1", "v1"},
+			wantStoredVersions: []string{"v1"},
+			wantUpdates:        len(mcpServers),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crd := newCRD(tt.storedVersions...)
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(mcpServers...).
+				WithObjects(crd).WithStatusSubresource(crd).Build()
+			versions := map[string]string{}
+			for _, obj := range mcpServers {
+				current := &mcpserverv1.MCPServer{}
+				if err := cli.Get(context.Background(), client.ObjectKeyFromObject(obj), current); err != nil {
+					t.Fatalf("failed to get MCPServer: %v", err)
+				}
+				versions[current.Name] = current.ResourceVersion
+			}
+
+			m := &Migrator{Reader: cli, Client: cli, CRDName: crdName}
+			if err := m.Migrate(context.Background()); err != nil {
+				t.Fatalf("Migrate() error = %v", err)
+			}
+
+			got := newCRD()
+			if err := cli.Get(context.Background(), client.ObjectKey{Name: crdName}, got); err != nil {
+				t.Fatalf("failed to get CRD: %v", err)
+			}
+			storedVersions, _, _ := unstructured.NestedStringSlice(got.Object, "status", "storedVersions")
+			if !reflect.DeepEqual(storedVersions, tt.wantStoredVersions) {
+				t.Errorf("storedVersions = %v, want %v", storedVersions, tt.wantStoredVersions)
+			}
+
+			updates := 0
+			for name, resourceVersion := range versions {
+				current := &mcpserverv1.MCPServer{}
+				if err := cli.Get(context.Background(), client.ObjectKey{Name: name, Namespace: "ns"}, current); err != nil {
+					t.Fatalf("failed to get MCPServer: %v", err)
+				}
+				if current.ResourceVersion != resourceVersion {
+					updates++
+				}
+			}
+			if updates != tt.wantUpdates {
+				t.Errorf("updated %d MCPServers, want %d", updates, tt.wantUpdates)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+// Package tokenauth implements the token-auth subcommand of the manager binary. It runs as a sidecar in front of
+// MCP servers that set spec.auth.type to Token and only forwards the requests that present the static bearer
+// token of the MCPServer, for teams without an OIDC or OAuth provider. The token is read from a file mounted from
+// a Secret and picked up again when the Secret changes.
+package tokenauth
+
+import (
+	"crypto/subtle"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Command is the name of the subcommand.
+	Command = "token-auth"
+
+	// HealthPath is where the sidecar reports on its health port whether it has a token to check requests
+	// against.
+	HealthPath = "/healthz"
+
+	// reloadInterval is how long a token read from the token file is used before the file is read again.
+	reloadInterval = 10 * time.Second
+)
+
+// Run starts the sidecar with the given arguments and returns the process exit code.
+func Run(args []string) int {
+	fs := flag.NewFlagSet(Command, flag.ContinueOnError)
+	upstream := fs.String("upstream", "http://localhost:8000", "The URL of the MCP server the sidecar runs in front of.")
+	port := fs.Int("port", 8060, "The port the sidecar listens on.")
+	healthPort := fs.Int("health-port", 8061, "The port the health of the sidecar is served on.")
+	tokenFile := fs.String("token-file", "", "A file holding the bearer token clients must present.")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *tokenFile == "" {
+		_, _ = fmt.Fprintln(os.Stderr, "--token-file is required")
+		return 2
+	}
+	upstreamURL, err := url.Parse(*upstream)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "invalid --upstream: %v\n", err)
+		return 2
+	}
+
+	token := &FileToken{Path: *tokenFile}
+	health := http.NewServeMux()
+	health.HandleFunc("GET "+HealthPath, func(w http.ResponseWriter, r *http.Request) {
+		if _, err := token.Token(time.Now()); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = fmt.Fprintln(w, "ok")
+	})
+	healthServer := &http.Server{
+		Addr:              fmt.Sprintf(":%d", *healthPort),
+		Handler:           health,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		if err := healthServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			_, _ = fmt.Fprintf(os.Stderr, "health server failed: %v\n", err)
+			os.Exit(1)
+		}
+	}()
+
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", *port),
+		Handler:           New(upstreamURL, token),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	_, _ = fmt.Fprintf(os.Stdout, "authenticating port %d to %s\n", *port, upstreamURL.Redacted())
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		_, _ = fmt.Fprintf(os.Stderr, "token authentication failed: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// TokenSource returns the token clients must present at now.
+type TokenSource interface {
+	Token(now time.Time) (string, error)
+}
+
+// FileToken reads the token from the file at Path, at most once every reloadInterval.
+type FileToken struct {
+	Path string
+
+	mu     sync.Mutex
+	token  string
+	readAt time.Time
+}
+
+func (f *FileToken) Token(now time.Time) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.token != "" && now.Sub(f.readAt) < reloadInterval {
+		return f.token, nil
+	}
+	data, err := os.ReadFile(f.Path)
+	if err != nil {
+		return "", fmt.Errorf("unable to read the token: %w", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("the token file %s is empty", f.Path)
+	}
+	f.token, f.readAt = token, now
+	return token, nil
+}
+
+// New returns a handler that forwards the requests presenting the token of source to upstream. The token is
+// removed from the forwarded requests, so that the MCP server never sees it.
+func New(upstream *url.URL, source TokenSource) http.Handler {
+	reverseProxy := &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.SetURL(upstream)
+			r.Out.Header.Del("Authorization")
+		},
+		// SSE responses must reach the client as they are written.
+		FlushInterval: -1,
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		callerToken, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || callerToken == "" {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "a bearer token is required", http.StatusUnauthorized)
+			return
+		}
+
+		token, err := source.Token(time.Now())
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to authenticate the request: %v", err), http.StatusServiceUnavailable)
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(callerToken), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			http.Error(w, "invalid bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		reverseProxy.ServeHTTP(w, r)
+	})
+}
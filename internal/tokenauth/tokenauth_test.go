@@ -0,0 +1,104 @@
+package tokenauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("the token was forwarded to the MCP server")
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer upstream.Close()
+	upstreamURL, err := url.Parse(upstream.URL)
+	if err != nil {
+		t.Fatalf("failed to parse the upstream URL: %v", err)
+	}
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("s3cr3t\n"), 0o600); err != nil {
+		t.Fatalf("failed to write the token: %v", err)
+	}
+	server := httptest.NewServer(New(upstreamURL, &FileToken{Path: tokenFile}))
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+	}{
+		{
+			name:       "no token",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:          "wrong token",
+			authorization: "Bearer guess",
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:          "not a bearer token",
+			authorization: "Basic czNjcjN0",
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:          "token of the MCPServer",
+			authorization: "Bearer s3cr3t",
+			wantStatus:    http.StatusAccepted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, server.URL+"/mcp", nil)
+			if err != nil {
+				t.Fatalf("failed to create the request: %v", err)
+			}
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			_ = resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestFileToken(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	source := &FileToken{Path: tokenFile}
+	now := time.Now()
+
+	if _, err := source.Token(now); err == nil {
+		t.Errorf("Token() of a missing file succeeded")
+	}
+
+	if err := os.WriteFile(tokenFile, []byte("first"), 0o600); err != nil {
+		t.Fatalf("failed to write the token: %v", err)
+	}
+	if got, err := source.Token(now); err != nil || got != "first" {
+		t.Fatalf("Token() = %q, %v, want first", got, err)
+	}
+
+	// A rotated token is picked up once the reload interval passed.
+	if err := os.WriteFile(tokenFile, []byte("second"), 0o600); err != nil {
+		t.Fatalf("failed to write the token: %v", err)
+	}
+	if got, _ := source.Token(now.Add(time.Second)); got != "first" {
+		t.Errorf("Token() before the reload interval = %q, want first", got)
+	}
+	if got, _ := source.Token(now.Add(reloadInterval)); got != "second" {
+		t.Errorf("Token() after the reload interval = %q, want second", got)
+	}
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v1 holds the admission webhooks of the v1 MCPServer API. The validating webhook of MCPServers never rejects
+// an MCPServer, the CRD validation does that; it only returns warnings that kubectl and oc print at apply time. The
+// validating webhook of MCPServerClaims rejects claims for a user other than the one creating them.
+package v1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster/gvk"
+)
+
+// deprecatedField is a field of MCPServers that still works but is going to be removed.
+type deprecatedField struct {
+	// Path of the field, e.g. spec.testConnection
+	Path string
+	// IsSet reports whether the MCPServer sets the field.
+	IsSet func(cr *mcpserverv1.MCPServer) bool
+	// Replacement tells users what to use instead.
+	Replacement string
+}
+
+// deprecatedFields lists the deprecated fields of MCPServers. A field is listed here for at least one release
+// before it is removed from the API. No field is deprecated yet.
+var deprecatedFields []deprecatedField
+
+// SetupMCPServerWebhookWithManager registers the webhook for MCPServers in the manager.
+func SetupMCPServerWebhookWithManager(mgr ctrl.Manager, platform *cluster.Platform) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&mcpserverv1.MCPServer{}).
+		WithValidator(&MCPServerCustomValidator{Client: mgr.GetClient(), Platform: platform}).
+		Complete()
+}
+
+// The webhook is called with failurePolicy=ignore, as it only warns and must not block MCPServers while the
+// operator is unavailable.
+// +kubebuilder:webhook:path=/validate-mcpserver-opendatahub-io-v1-mcpserver,mutating=false,failurePolicy=ignore,sideEffects=None,groups=mcpserver.opendatahub.io,resources=mcpservers,verbs=create;update,versions=v1,name=vmcpserver-v1.kb.io,admissionReviewVersions=v1
+
+// MCPServerCustomValidator warns about deprecated fields and risky configurations of MCPServers when they are
+// created or updated.
+type MCPServerCustomValidator struct {
+	// Client reads the MCPServerDefaults of the namespace of an MCPServer.
+	Client client.Reader
+	// Platform tells whether MCP servers get a Route, it is nil when unknown.
+	Platform *cluster.Platform
+}
+
+var _ webhook.CustomValidator = &MCPServerCustomValidator{}
+
+// ValidateCreate returns the warnings for a new MCPServer.
+func (v *MCPServerCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	cr, ok := obj.(*mcpserverv1.MCPServer)
+	if !ok {
+		return nil, fmt.Errorf("expected an MCPServer object but got %T", obj)
+	}
+	return v.warnings(ctx, cr), nil
+}
+
+// ValidateUpdate returns the warnings for the new version of an MCPServer.
+func (v *MCPServerCustomValidator) ValidateUpdate(ctx context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	cr, ok := newObj.(*mcpserverv1.MCPServer)
+	if !ok {
+		return nil, fmt.Errorf("expected an MCPServer object for the newObj but got %T", newObj)
+	}
+	return v.warnings(ctx, cr), nil
+}
+
+// ValidateDelete returns no warnings, deleting an MCPServer is never risky.
+func (v *MCPServerCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// warnings returns the warnings for cr, each naming the field and what to do about it.
+func (v *MCPServerCustomValidator) warnings(ctx context.Context, cr *mcpserverv1.MCPServer) admission.Warnings {
+	var warnings admission.Warnings
+	for _, field := range deprecatedFields {
+		if field.IsSet(cr) {
+			warnings = append(warnings, fmt.Sprintf("%s is deprecated and will be removed in a future release, use %s instead",
+				field.Path, field.Replacement))
+		}
+	}
+
+	if len(cr.Name) > validation.DNS1123LabelMaxLength {
+		warnings = append(warnings, fmt.Sprintf("metadata.name is longer than %d characters, so the resources of the "+
+			"MCP server get shortened names with a hash; they are listed in status.components",
+			validation.DNS1123LabelMaxLength))
+	}
+
+	serverType := cr.Spec.Type
+	if serverType == "" {
+		serverType = mcpserverv1.MCPServerManaged
+	}
+	if serverType == mcpserverv1.MCPServerExternal {
+		return warnings
+	}
+
+	if strings.Contains(cr.Name, ".") {
+		warnings = append(warnings, "metadata.name contains a dot, which the name of a Service cannot; the Service "+
+			"of the MCP server cannot be created, use a name without dots")
+	}
+
+	if serverType == mcpserverv1.MCPServerManaged && usesLatestTag(cr.Spec.Image) && !cr.Spec.PinImageDigest {
+		warnings = append(warnings, fmt.Sprintf("spec.image %s does not pin a version, the pods may run different "+
+			"images after a restart; use a version tag or a digest, or set spec.pinImageDigest", cr.Spec.Image))
+	}
+
+	if cr.Spec.ResourcesPreset == "" && !v.defaultsSetResourcesPreset(ctx, cr.Namespace) {
+		warnings = append(warnings, "spec.resourcesPreset is not set, the MCP server runs without resource requests "+
+			"and limits; set it to small, medium or large")
+	}
+
+	passthrough := cr.Spec.KubernetesAccess != nil && cr.Spec.KubernetesAccess.Mode == mcpserverv1.KubernetesAccessTokenPassthrough
+	if passthrough && (cr.Spec.TestConnection || cr.Spec.ConformanceCheck != nil) {
+		warnings = append(warnings, "spec.testConnection and spec.conformanceCheck are not run with kubernetesAccess.mode "+
+			"TokenPassthrough, the server would receive the service account token of the operator")
+	}
+	if serverType == mcpserverv1.MCPServerManaged && cr.Spec.Auth == nil && !passthrough {
+		if exposure := v.exposure(cr); exposure != "" {
+			warnings = append(warnings, fmt.Sprintf("the MCP server is reachable without authentication through %s; "+
+				"set spec.auth or restrict the callers with spec.expose.allowedSourceRanges", exposure))
+		}
+	}
+	return warnings
+}
+
+// usesLatestTag reports whether image has the latest tag, or no tag and no digest, which means latest.
+func usesLatestTag(image string) bool {
+	if image == "" || strings.Contains(image, "@") {
+		return false
+	}
+	name := image[strings.LastIndex(image, "/")+1:]
+	i := strings.LastIndex(name, ":")
+	return i < 0 || name[i+1:] == "latest"
+}
+
+// defaultsSetResourcesPreset reports whether the MCPServerDefaults of namespace set a resources preset. The
+// warning is skipped when they cannot be read.
+func (v *MCPServerCustomValidator) defaultsSetResourcesPreset(ctx context.Context, namespace string) bool {
+	defaults := &mcpserverv1.MCPServerDefaults{}
+	err := v.Client.Get(ctx, client.ObjectKey{Name: mcpserverv1.MCPServerDefaultsName, Namespace: namespace}, defaults)
+	if err != nil {
+		if !k8serr.IsNotFound(err) {
+			logf.FromContext(ctx).Error(err, "Failed to get MCPServerDefaults", "namespace", namespace)
+			return true
+		}
+		return false
+	}
+	return defaults.Spec.ResourcesPreset != ""
+}
+
+// exposure returns how a Managed MCP server is published outside the cluster to any client, or an empty string
+// when it is not. spec.expose.allowedSourceRanges does not restrict the Ingresses and the shared host of
+// spec.exposures.
+func (v *MCPServerCustomValidator) exposure(cr *mcpserverv1.MCPServer) string {
+	for _, exposure := range cr.Spec.Exposures {
+		if exposure.Type == mcpserverv1.ExposureIngress || exposure.Type == mcpserverv1.ExposureSharedHost {
+			return fmt.Sprintf("its %s exposure %s", exposure.Type, exposure.Name)
+		}
+	}
+	switch {
+	case cr.Spec.GatewayRef != nil:
+		return fmt.Sprintf("Gateway %s", cr.Spec.GatewayRef.Name)
+	case cr.Spec.MeshGateway != nil:
+		return fmt.Sprintf("mesh gateway host %s", cr.Spec.MeshGateway.Host)
+	case cr.Spec.Expose != nil && len(cr.Spec.Expose.AllowedSourceRanges) > 0:
+		return ""
+	case v.Platform != nil && v.Platform.HasAPI(gvk.Route):
+		return "its Route"
+	}
+	return ""
+}
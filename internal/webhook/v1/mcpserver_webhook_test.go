@@ -0,0 +1,214 @@
+package v1
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster"
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster/gvk"
+)
+
+func TestMCPServerCustomValidator_ValidateCreate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := mcpserverv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add mcpserverv1 scheme: %v", err)
+	}
+	discovery := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{{
+		GroupVersion: gvk.Route.GroupVersion().String(),
+		APIResources: []metav1.APIResource{{Name: "routes", Kind: gvk.Route.Kind}},
+	}}}}
+	openShift := &cluster.Platform{Name: cluster.OpenShift, APIs: gvk.NewAvailability(discovery)}
+	defaults := &mcpserverv1.MCPServerDefaults{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpserverv1.MCPServerDefaultsName, Namespace: "team-a"},
+		Spec:       mcpserverv1.MCPServerDefaultsSpec{ResourcesPreset: mcpserverv1.ResourcesPresetSmall},
+	}
+
+	tests := []struct {
+		name         string
+		crName       string
+		namespace    string
+		spec         mcpserverv1.MCPServerSpec
+		platform     *cluster.Platform
+		deprecated   []deprecatedField
+		wantWarnings []string
+	}{
+		{
+			name: "pinned image with resources on Kubernetes",
+			spec: mcpserverv1.MCPServerSpec{Image: "quay.io/mcp/server:1.2.0", ResourcesPreset: mcpserverv1.ResourcesPresetSmall},
+		},
+		{
+			name:         "latest tag",
+			spec:         mcpserverv1.MCPServerSpec{Image: "quay.io/mcp/server:latest", ResourcesPreset: mcpserverv1.ResourcesPresetSmall},
+			wantWarnings: []string{"spec.image"},
+		},
+		{
+			name:         "no tag on a registry with a port",
+			spec:         mcpserverv1.MCPServerSpec{Image: "registry:5000/mcp/server", ResourcesPreset: mcpserverv1.ResourcesPresetSmall},
+			wantWarnings: []string{"spec.image"},
+		},
+		{
+			name: "digest",
+			spec: mcpserverv1.MCPServerSpec{Image: "quay.io/mcp/server@sha256:0123", ResourcesPreset: mcpserverv1.ResourcesPresetSmall},
+		},
+		{
+			name: "latest tag pinned by digest",
+			spec: mcpserverv1.MCPServerSpec{Image: "quay.io/mcp/server:latest", PinImageDigest: true,
+				ResourcesPreset: mcpserverv1.ResourcesPresetSmall},
+		},
+		{
+			name:         "no resources",
+			spec:         mcpserverv1.MCPServerSpec{Image: "quay.io/mcp/server:1.2.0"},
+			wantWarnings: []string{"set it to small, medium or large"},
+		},
+		{
+			name:      "resources from the namespace defaults",
+			namespace: "team-a",
+			spec:      mcpserverv1.MCPServerSpec{Image: "quay.io/mcp/server:1.2.0"},
+		},
+		{
+			name:         "unauthenticated Route",
+			spec:         mcpserverv1.MCPServerSpec{Image: "quay.io/mcp/server:1.2.0", ResourcesPreset: mcpserverv1.ResourcesPresetSmall},
+			platform:     openShift,
+			wantWarnings: []string{"without authentication through its Route"},
+		},
+		{
+			name: "Route restricted to source ranges",
+			spec: mcpserverv1.MCPServerSpec{
+				Image:           "quay.io/mcp/server:1.2.0",
+				ResourcesPreset: mcpserverv1.ResourcesPresetSmall,
+				Expose:          &mcpserverv1.Expose{AllowedSourceRanges: []string{"10.0.0.0/8"}},
+			},
+			platform: openShift,
+		},
+		{
+			name: "unauthenticated Ingress exposure next to a restricted Route",
+			spec: mcpserverv1.MCPServerSpec{
+				Image:           "quay.io/mcp/server:1.2.0",
+				ResourcesPreset: mcpserverv1.ResourcesPresetSmall,
+				Expose:          &mcpserverv1.Expose{AllowedSourceRanges: []string{"10.0.0.0/8"}},
+				Exposures:       []mcpserverv1.Exposure{{Name: "public", Type: mcpserverv1.ExposureIngress}},
+			},
+			platform:     openShift,
+			wantWarnings: []string{"without authentication through its Ingress exposure public"},
+		},
+		{
+			name: "unauthenticated SharedHost exposure next to a restricted Route",
+			spec: mcpserverv1.MCPServerSpec{
+				Image:           "quay.io/mcp/server:1.2.0",
+				ResourcesPreset: mcpserverv1.ResourcesPresetSmall,
+				Expose:          &mcpserverv1.Expose{AllowedSourceRanges: []string{"10.0.0.0/8"}},
+				Exposures:       []mcpserverv1.Exposure{{Name: "shared", Type: mcpserverv1.ExposureSharedHost}},
+			},
+			platform:     openShift,
+			wantWarnings: []string{"without authentication through its SharedHost exposure shared"},
+		},
+		{
+			name: "token authentication",
+			spec: mcpserverv1.MCPServerSpec{
+				Image:           "quay.io/mcp/server:1.2.0",
+				ResourcesPreset: mcpserverv1.ResourcesPresetSmall,
+				Auth:            &mcpserverv1.Auth{Type: mcpserverv1.AuthToken},
+			},
+			platform: openShift,
+		},
+		{
+			name: "token passthrough",
+			spec: mcpserverv1.MCPServerSpec{
+				Image:            "quay.io/mcp/server:1.2.0",
+				ResourcesPreset:  mcpserverv1.ResourcesPresetSmall,
+				KubernetesAccess: &mcpserverv1.KubernetesAccess{Mode: mcpserverv1.KubernetesAccessTokenPassthrough},
+			},
+			platform: openShift,
+		},
+		{
+			name: "conformance check with token passthrough",
+			spec: mcpserverv1.MCPServerSpec{
+				Image:            "quay.io/mcp/server:1.2.0",
+				ResourcesPreset:  mcpserverv1.ResourcesPresetSmall,
+				KubernetesAccess: &mcpserverv1.KubernetesAccess{Mode: mcpserverv1.KubernetesAccessTokenPassthrough},
+				ConformanceCheck: &mcpserverv1.ConformanceCheck{},
+			},
+			platform:     openShift,
+			wantWarnings: []string{"are not run with kubernetesAccess.mode TokenPassthrough"},
+		},
+		{
+			name: "unauthenticated Gateway",
+			spec: mcpserverv1.MCPServerSpec{
+				Image:           "quay.io/mcp/server:1.2.0",
+				ResourcesPreset: mcpserverv1.ResourcesPresetSmall,
+				GatewayRef:      &mcpserverv1.GatewayRef{Name: "shared"},
+			},
+			wantWarnings: []string{"without authentication through Gateway shared"},
+		},
+		{
+			name: "External server",
+			spec: mcpserverv1.MCPServerSpec{Type: mcpserverv1.MCPServerExternal, URL: "https://mcp.example.com/sse"},
+		},
+		{
+			name:         "name longer than a DNS label",
+			crName:       strings.Repeat("a", 64),
+			spec:         mcpserverv1.MCPServerSpec{Type: mcpserverv1.MCPServerExternal, URL: "https://mcp.example.com/sse"},
+			wantWarnings: []string{"status.components"},
+		},
+		{
+			name:         "name with a dot",
+			crName:       "mcp.tools",
+			spec:         mcpserverv1.MCPServerSpec{Image: "quay.io/mcp/server:1.2.0", ResourcesPreset: mcpserverv1.ResourcesPresetSmall},
+			wantWarnings: []string{"contains a dot"},
+		},
+		{
+			name: "deprecated field",
+			spec: mcpserverv1.MCPServerSpec{Image: "quay.io/mcp/server:1.2.0", ResourcesPreset: mcpserverv1.ResourcesPresetSmall, TestConnection: true},
+			deprecated: []deprecatedField{{
+				Path:        "spec.testConnection",
+				IsSet:       func(cr *mcpserverv1.MCPServer) bool { return cr.Spec.TestConnection },
+				Replacement: "spec.conformanceCheck",
+			}},
+			wantWarnings: []string{"spec.testConnection is deprecated"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deprecatedFields = tt.deprecated
+			defer func() { deprecatedFields = nil }()
+
+			crName := tt.crName
+			if crName == "" {
+				crName = "mcp"
+			}
+			namespace := tt.namespace
+			if namespace == "" {
+				namespace = "default"
+			}
+			v := &MCPServerCustomValidator{
+				Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(defaults).Build(),
+				Platform: tt.platform,
+			}
+			cr := &mcpserverv1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{Name: crName, Namespace: namespace},
+				Spec:       tt.spec,
+			}
+
+			warnings, err := v.ValidateCreate(context.Background(), cr)
+			if err != nil {
+				t.Fatalf("ValidateCreate() error = %v, want only warnings", err)
+			}
+			if len(warnings) != len(tt.wantWarnings) {
+				t.Fatalf("warnings = %q, want %d", warnings, len(tt.wantWarnings))
+			}
+			for i, want := range tt.wantWarnings {
+				if !strings.Contains(warnings[i], want) {
+					t.Errorf("warning %q does not mention %q", warnings[i], want)
+				}
+			}
+		})
+	}
+}
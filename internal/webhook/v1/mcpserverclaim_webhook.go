@@ -0,0 +1,90 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/internal/accessreview"
+)
+
+// SetupMCPServerClaimWebhookWithManager registers the webhook for MCPServerClaims in the manager.
+func SetupMCPServerClaimWebhookWithManager(mgr ctrl.Manager, reviewer *accessreview.Reviewer) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&mcpserverv1.MCPServerClaim{}).
+		WithValidator(&MCPServerClaimCustomValidator{Reviewer: reviewer}).
+		Complete()
+}
+
+// The webhook is called with failurePolicy=fail, as a claim admitted without it could grant its instance to any user.
+// +kubebuilder:webhook:path=/validate-mcpserver-opendatahub-io-v1-mcpserverclaim,mutating=false,failurePolicy=fail,sideEffects=None,groups=mcpserver.opendatahub.io,resources=mcpserverclaims,verbs=create,versions=v1,name=vmcpserverclaim-v1.kb.io,admissionReviewVersions=v1
+
+// MCPServerClaimCustomValidator rejects MCPServerClaims for a user other than the one creating them, unless that
+// user may impersonate the user of the claim, so that nobody is granted an instance they did not ask for and
+// nobody creates an instance under the name of someone else.
+type MCPServerClaimCustomValidator struct {
+	// Reviewer tells whether the creator of a claim may impersonate its user.
+	Reviewer *accessreview.Reviewer
+}
+
+var _ webhook.CustomValidator = &MCPServerClaimCustomValidator{}
+
+// ValidateCreate rejects a new MCPServerClaim whose spec.user is neither its creator nor a user its creator may
+// impersonate.
+func (v *MCPServerClaimCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	claim, ok := obj.(*mcpserverv1.MCPServerClaim)
+	if !ok {
+		return nil, fmt.Errorf("expected an MCPServerClaim object but got %T", obj)
+	}
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if claim.Spec.User == req.UserInfo.Username {
+		return nil, nil
+	}
+	allowed, err := v.Reviewer.Allowed(ctx, &req.UserInfo, authorizationv1.ResourceAttributes{
+		Verb:     "impersonate",
+		Resource: "users",
+		Name:     claim.Spec.User,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to review the access of %s: %w", req.UserInfo.Username, err)
+	}
+	if !allowed {
+		return nil, fmt.Errorf("spec.user %s is not the user creating the claim, %s, who may not impersonate it",
+			claim.Spec.User, req.UserInfo.Username)
+	}
+	return nil, nil
+}
+
+// ValidateUpdate admits every update, spec.user cannot be changed.
+func (v *MCPServerClaimCustomValidator) ValidateUpdate(_ context.Context, _, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// ValidateDelete admits every deletion.
+func (v *MCPServerClaimCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
@@ -0,0 +1,55 @@
+package v1
+
+import (
+	"context"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+	"github.com/opendatahub-io/mcp-server-operator/internal/accessreview"
+)
+
+func TestMCPServerClaimCustomValidator_ValidateCreate(t *testing.T) {
+	// admin may impersonate all users.
+	clientset := fake.NewClientset()
+	clientset.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		review.Status.Allowed = review.Spec.User == "admin" && review.Spec.ResourceAttributes.Verb == "impersonate" &&
+			review.Spec.ResourceAttributes.Resource == "users"
+		return true, review, nil
+	})
+	v := &MCPServerClaimCustomValidator{Reviewer: &accessreview.Reviewer{Clientset: clientset}}
+
+	tests := []struct {
+		name      string
+		requester string
+		user      string
+		wantErr   bool
+	}{
+		{name: "claim for the requester", requester: "alice", user: "alice"},
+		{name: "claim for another user", requester: "mallory", user: "alice", wantErr: true},
+		{name: "claim for a user the requester may impersonate", requester: "admin", user: "alice"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claim := &mcpserverv1.MCPServerClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "alice-tools", Namespace: "team-a"},
+				Spec:       mcpserverv1.MCPServerClaimSpec{TemplateName: "notebook-tools", User: tt.user},
+			}
+			ctx := admission.NewContextWithRequest(context.Background(), admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{UserInfo: authenticationv1.UserInfo{Username: tt.requester}},
+			})
+			if _, err := v.ValidateCreate(ctx, claim); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
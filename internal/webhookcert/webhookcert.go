@@ -0,0 +1,223 @@
+// Package webhookcert provisions the serving certificate of the operator's webhook server. On OpenShift
+// the certificate is issued by the service CA; elsewhere a self-signed cert-manager Issuer and Certificate
+// are created. In both cases the CA bundle is injected into the operator's webhook configurations and the
+// certificate is copied from its Secret into the directory the webhook server loads it from.
+package webhookcert
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// servingCertSecretAnnotation asks the OpenShift service CA to issue a certificate for a Service.
+	servingCertSecretAnnotation = "service.beta.openshift.io/serving-cert-secret-name"
+	// injectCABundleAnnotation asks the OpenShift service CA to inject its bundle into a webhook configuration.
+	injectCABundleAnnotation = "service.beta.openshift.io/inject-cabundle"
+	// certManagerInjectCAAnnotation asks the cert-manager CA injector to inject the CA of a Certificate.
+	certManagerInjectCAAnnotation = "cert-manager.io/inject-ca-from"
+
+	// resyncInterval is how often the certificate is copied from its Secret to pick up rotations.
+	resyncInterval = time.Minute
+)
+
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get
+// +kubebuilder:rbac:groups="admissionregistration.k8s.io",resources=validatingwebhookconfigurations;mutatingwebhookconfigurations,verbs=get;patch
+// +kubebuilder:rbac:groups="cert-manager.io",resources=issuers;certificates,verbs=create
+
+var (
+	issuerGVK      = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Issuer"}
+	certificateGVK = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}
+)
+
+// Provisioner obtains a serving certificate for the webhook Service and keeps CertDir up to date with it.
+type Provisioner struct {
+	Client client.Client
+
+	// Namespace is the namespace of the operator.
+	Namespace string
+	// ServiceName is the name of the Service in front of the webhook server.
+	ServiceName string
+	// SecretName is the name of the Secret the certificate is issued into.
+	SecretName string
+	// WebhookConfigurations are the names of the validating and mutating webhook configurations
+	// that need the CA bundle. Configurations that do not exist are skipped.
+	WebhookConfigurations []string
+	// CertDir is the directory tls.crt and tls.key are written to.
+	CertDir string
+	// OpenShift selects the OpenShift service CA instead of cert-manager.
+	OpenShift bool
+}
+
+// Provision requests the certificate, injects the CA bundle into the webhook configurations and waits until
+// the certificate has been written to CertDir, or the timeout expires.
+func (p *Provisioner) Provision(ctx context.Context, timeout time.Duration) error {
+	if p.OpenShift {
+		if err := p.annotateService(ctx); err != nil {
+			return fmt.Errorf("requesting a service CA certificate: %w", err)
+		}
+	} else {
+		if err := p.ensureCertificate(ctx); err != nil {
+			return fmt.Errorf("requesting a cert-manager certificate: %w", err)
+		}
+	}
+
+	if err := p.injectCABundle(ctx); err != nil {
+		return fmt.Errorf("injecting the CA bundle: %w", err)
+	}
+
+	return wait.PollUntilContextTimeout(ctx, 2*time.Second, timeout, true, func(ctx context.Context) (bool, error) {
+		err := p.syncCertificate(ctx)
+		if k8serr.IsNotFound(err) {
+			return false, nil
+		}
+		return err == nil, err
+	})
+}
+
+// Start keeps CertDir in sync with the Secret so that rotated certificates are picked up by the webhook
+// server's certificate watcher. It implements manager.Runnable.
+func (p *Provisioner) Start(ctx context.Context) error {
+	logger := logf.FromContext(ctx).WithName("webhookcert")
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := p.syncCertificate(ctx); err != nil {
+			logger.Error(err, "unable to sync the webhook serving certificate", "secret", p.SecretName)
+		}
+	}, resyncInterval)
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable; every replica serves webhooks.
+func (p *Provisioner) NeedLeaderElection() bool {
+	return false
+}
+
+func (p *Provisioner) annotateService(ctx context.Context) error {
+	service := &corev1.Service{}
+	err := p.Client.Get(ctx, client.ObjectKey{Name: p.ServiceName, Namespace: p.Namespace}, service)
+	if err != nil {
+		return err
+	}
+	if service.Annotations[servingCertSecretAnnotation] == p.SecretName {
+		return nil
+	}
+
+	patch := client.MergeFrom(service.DeepCopy())
+	if service.Annotations == nil {
+		service.Annotations = map[string]string{}
+	}
+	service.Annotations[servingCertSecretAnnotation] = p.SecretName
+	return p.Client.Patch(ctx, service, patch)
+}
+
+// ensureCertificate creates a self-signed Issuer and a Certificate for the webhook Service.
+func (p *Provisioner) ensureCertificate(ctx context.Context) error {
+	issuer := &unstructured.Unstructured{}
+	issuer.SetGroupVersionKind(issuerGVK)
+	issuer.SetName(p.SecretName + "-issuer")
+	issuer.SetNamespace(p.Namespace)
+	issuer.Object["spec"] = map[string]interface{}{
+		"selfSigned": map[string]interface{}{},
+	}
+
+	certificate := &unstructured.Unstructured{}
+	certificate.SetGroupVersionKind(certificateGVK)
+	certificate.SetName(p.SecretName)
+	certificate.SetNamespace(p.Namespace)
+	certificate.Object["spec"] = map[string]interface{}{
+		"secretName": p.SecretName,
+		"dnsNames": []interface{}{
+			fmt.Sprintf("%s.%s.svc", p.ServiceName, p.Namespace),
+			fmt.Sprintf("%s.%s.svc.cluster.local", p.ServiceName, p.Namespace),
+		},
+		"issuerRef": map[string]interface{}{
+			"kind": issuerGVK.Kind,
+			"name": issuer.GetName(),
+		},
+	}
+
+	for _, obj := range []*unstructured.Unstructured{issuer, certificate} {
+		err := p.Client.Create(ctx, obj)
+		if err != nil && !k8serr.IsAlreadyExists(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Provisioner) injectCABundle(ctx context.Context) error {
+	key, value := injectCABundleAnnotation, "true"
+	if !p.OpenShift {
+		key, value = certManagerInjectCAAnnotation, p.Namespace+"/"+p.SecretName
+	}
+
+	for _, name := range p.WebhookConfigurations {
+		for _, obj := range []client.Object{
+			&admissionregistrationv1.ValidatingWebhookConfiguration{},
+			&admissionregistrationv1.MutatingWebhookConfiguration{},
+		} {
+			err := p.Client.Get(ctx, client.ObjectKey{Name: name}, obj)
+			if k8serr.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if obj.GetAnnotations()[key] == value {
+				continue
+			}
+
+			patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+			annotations := obj.GetAnnotations()
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			annotations[key] = value
+			obj.SetAnnotations(annotations)
+			if err := p.Client.Patch(ctx, obj, patch); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// syncCertificate writes the certificate and key from the Secret to CertDir if they changed.
+func (p *Provisioner) syncCertificate(ctx context.Context) error {
+	secret := &corev1.Secret{}
+	err := p.Client.Get(ctx, client.ObjectKey{Name: p.SecretName, Namespace: p.Namespace}, secret)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(p.CertDir, 0o700); err != nil {
+		return err
+	}
+	for _, name := range []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey} {
+		data, ok := secret.Data[name]
+		if !ok {
+			return fmt.Errorf("secret %s/%s has no %s", p.Namespace, p.SecretName, name)
+		}
+		path := filepath.Join(p.CertDir, name)
+		if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, data) {
+			continue
+		}
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package webhookcert
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestProvisioner_Provision(t *testing.T) {
+	const namespace = "operator-namespace"
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "webhook-service", Namespace: namespace},
+	}
+	webhookConfiguration := &admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "validating-webhook-configuration"},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "webhook-server-cert", Namespace: namespace},
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       []byte("certificate"),
+			corev1.TLSPrivateKeyKey: []byte("key"),
+		},
+	}
+
+	cli := fake.NewClientBuilder().WithObjects(service, webhookConfiguration, secret).Build()
+	p := &Provisioner{
+		Client:                cli,
+		Namespace:             namespace,
+		ServiceName:           service.Name,
+		SecretName:            secret.Name,
+		WebhookConfigurations: []string{webhookConfiguration.Name, "missing-webhook-configuration"},
+		CertDir:               t.TempDir(),
+		OpenShift:             true,
+	}
+	if err := p.Provision(context.Background(), 10*time.Second); err != nil {
+		t.Fatalf("Provision() error = %v", err)
+	}
+
+	if err := cli.Get(context.Background(), client.ObjectKeyFromObject(service), service); err != nil {
+		t.Fatalf("failed to get service for verification: %v", err)
+	}
+	if got := service.Annotations[servingCertSecretAnnotation]; got != secret.Name {
+		t.Errorf("service annotation %s = %q, want %q", servingCertSecretAnnotation, got, secret.Name)
+	}
+
+	if err := cli.Get(context.Background(), client.ObjectKeyFromObject(webhookConfiguration), webhookConfiguration); err != nil {
+		t.Fatalf("failed to get webhook configuration for verification: %v", err)
+	}
+	if got := webhookConfiguration.Annotations[injectCABundleAnnotation]; got != "true" {
+		t.Errorf("webhook configuration annotation %s = %q, want %q", injectCABundleAnnotation, got, "true")
+	}
+
+	for name, want := range secret.Data {
+		got, err := os.ReadFile(filepath.Join(p.CertDir, name))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", name, err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// Package cluster provides information about the cluster the operator runs on, so that differences
+// between OpenShift and other Kubernetes distributions are handled in one place.
+package cluster
+
+import (
+	"context"
+	"os"
+
+	configv1 "github.com/openshift/api/config/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster/gvk"
+)
+
+const (
+	OpenShift  = "OpenShift"
+	Kubernetes = "Kubernetes"
+
+	// clusterConfigName is the name of the singleton OpenShift cluster configuration objects.
+	clusterConfigName = "cluster"
+)
+
+// +kubebuilder:rbac:groups="config.openshift.io",resources=ingresses;proxies,verbs=get
+
+// Platform describes the cluster the operator runs on and the optional APIs it serves.
+type Platform struct {
+	// Name is either OpenShift or Kubernetes.
+	Name string
+
+	// APIs reports which optional APIs are served by the cluster.
+	APIs *gvk.Availability
+
+	// IngressDomain is the default domain of the cluster's ingress controller, if known.
+	IngressDomain string
+
+	// Proxy holds the cluster-wide egress proxy settings. It is nil when no proxy is configured.
+	Proxy *Proxy
+}
+
+// Proxy holds egress proxy settings.
+type Proxy struct {
+	HTTPProxy  string
+	HTTPSProxy string
+	NoProxy    string
+}
+
+// IsOpenShift reports whether the platform is OpenShift.
+func (p *Platform) IsOpenShift() bool {
+	return p.Name == OpenShift
+}
+
+// HasAPI reports whether the cluster serves the given optional kind. Kinds that cannot be looked up are
+// treated as not served.
+func (p *Platform) HasAPI(kind schema.GroupVersionKind) bool {
+	available, err := p.APIs.IsAvailable(kind)
+	return err == nil && available
+}
+
+// DetectPlatform uses discovery and the OpenShift cluster configuration to describe the platform the operator
+// runs on. The reader must not depend on a started cache.
+func DetectPlatform(ctx context.Context, dc discovery.DiscoveryInterface, reader client.Reader) (*Platform, error) {
+	platform := &Platform{
+		Name: Kubernetes,
+		APIs: gvk.NewAvailability(dc),
+	}
+
+	openShift, err := platform.APIs.IsAvailable(gvk.ClusterVersion)
+	if err != nil {
+		return nil, err
+	}
+	if !openShift {
+		platform.Proxy = proxyFromEnvironment()
+		return platform, nil
+	}
+	platform.Name = OpenShift
+
+	ingress := &configv1.Ingress{}
+	err = reader.Get(ctx, client.ObjectKey{Name: clusterConfigName}, ingress)
+	if err != nil && !k8serr.IsNotFound(err) {
+		return nil, err
+	}
+	platform.IngressDomain = ingress.Spec.Domain
+
+	proxy := &configv1.Proxy{}
+	err = reader.Get(ctx, client.ObjectKey{Name: clusterConfigName}, proxy)
+	if err != nil && !k8serr.IsNotFound(err) {
+		return nil, err
+	}
+	if proxy.Status.HTTPProxy != "" || proxy.Status.HTTPSProxy != "" {
+		platform.Proxy = &Proxy{
+			HTTPProxy:  proxy.Status.HTTPProxy,
+			HTTPSProxy: proxy.Status.HTTPSProxy,
+			NoProxy:    proxy.Status.NoProxy,
+		}
+	}
+	return platform, nil
+}
+
+// IsOpenShift reports whether the cluster is an OpenShift cluster.
+func IsOpenShift(dc discovery.DiscoveryInterface) (bool, error) {
+	return gvk.IsServed(dc, gvk.ClusterVersion)
+}
+
+// proxyFromEnvironment returns the proxy settings the operator itself was started with. Outside of OpenShift
+// there is no cluster-wide proxy configuration, so these are passed on to the MCP servers instead.
+func proxyFromEnvironment() *Proxy {
+	proxy := &Proxy{
+		HTTPProxy:  os.Getenv("HTTP_PROXY"),
+		HTTPSProxy: os.Getenv("HTTPS_PROXY"),
+		NoProxy:    os.Getenv("NO_PROXY"),
+	}
+	if proxy.HTTPProxy == "" && proxy.HTTPSProxy == "" {
+		return nil
+	}
+	return proxy
+}
@@ -0,0 +1,109 @@
+package cluster
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/opendatahub-io/mcp-server-operator/pkg/cluster/gvk"
+)
+
+func TestDetectPlatform(t *testing.T) {
+	t.Setenv("HTTP_PROXY", "")
+	t.Setenv("HTTPS_PROXY", "")
+
+	fakeScheme := runtime.NewScheme()
+	if err := configv1.Install(fakeScheme); err != nil {
+		t.Fatalf("failed to add config scheme: %v", err)
+	}
+
+	openShiftResources := []*metav1.APIResourceList{
+		{
+			GroupVersion: "route.openshift.io/v1",
+			APIResources: []metav1.APIResource{{Name: "routes", Kind: "Route"}},
+		},
+		{
+			GroupVersion: "config.openshift.io/v1",
+			APIResources: []metav1.APIResource{{Name: "clusterversions", Kind: "ClusterVersion"}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		resources []*metav1.APIResourceList
+		objects   []client.Object
+		want      *Platform
+		wantRoute bool
+	}{
+		{
+			name:      "Verify that an OpenShift cluster is detected with its ingress domain and proxy",
+			resources: openShiftResources,
+			objects: []client.Object{
+				&configv1.Ingress{
+					ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+					Spec:       configv1.IngressSpec{Domain: "apps.example.com"},
+				},
+				&configv1.Proxy{
+					ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+					Status: configv1.ProxyStatus{
+						HTTPSProxy: "http://proxy.example.com:3128",
+						NoProxy:    ".cluster.local,.svc",
+					},
+				},
+			},
+			want: &Platform{
+				Name:          OpenShift,
+				IngressDomain: "apps.example.com",
+				Proxy: &Proxy{
+					HTTPSProxy: "http://proxy.example.com:3128",
+					NoProxy:    ".cluster.local,.svc",
+				},
+			},
+			wantRoute: true,
+		},
+		{
+			name:      "Verify that an OpenShift cluster without a proxy has no proxy settings",
+			resources: openShiftResources,
+			want: &Platform{
+				Name: OpenShift,
+			},
+			wantRoute: true,
+		},
+		{
+			name: "Verify that a cluster without the OpenShift APIs is detected as Kubernetes",
+			resources: []*metav1.APIResourceList{{
+				GroupVersion: "apps/v1",
+				APIResources: []metav1.APIResource{{Name: "deployments", Kind: "Deployment"}},
+			}},
+			want: &Platform{
+				Name: Kubernetes,
+			},
+			wantRoute: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dc := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: tt.resources}}
+			reader := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(tt.objects...).Build()
+			got, err := DetectPlatform(context.Background(), dc, reader)
+			if err != nil {
+				t.Fatalf("DetectPlatform() error = %v", err)
+			}
+			if got.HasAPI(gvk.Route) != tt.wantRoute {
+				t.Errorf("HasAPI(Route) = %v, want %v", got.HasAPI(gvk.Route), tt.wantRoute)
+			}
+			got.APIs = nil
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DetectPlatform() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
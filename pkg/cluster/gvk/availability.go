@@ -0,0 +1,99 @@
+package gvk
+
+import (
+	"context"
+	"sync"
+
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+)
+
+// +kubebuilder:rbac:groups="apiextensions.k8s.io",resources=customresourcedefinitions,verbs=get;list;watch
+
+// Availability reports whether the cluster serves optional kinds, such as those of integrations that are
+// only installed on some clusters. Discovery results are cached until Invalidate is called, which happens
+// automatically on CustomResourceDefinition changes once InvalidateOnCRDEvents has been set up.
+type Availability struct {
+	discovery discovery.DiscoveryInterface
+
+	mu    sync.RWMutex
+	known map[schema.GroupVersionKind]bool
+}
+
+// NewAvailability returns an Availability that uses dc to look up kinds.
+func NewAvailability(dc discovery.DiscoveryInterface) *Availability {
+	return &Availability{
+		discovery: dc,
+		known:     map[schema.GroupVersionKind]bool{},
+	}
+}
+
+// IsAvailable reports whether the cluster serves kind. A nil Availability serves nothing.
+func (a *Availability) IsAvailable(kind schema.GroupVersionKind) (bool, error) {
+	if a == nil {
+		return false, nil
+	}
+
+	a.mu.RLock()
+	available, ok := a.known[kind]
+	a.mu.RUnlock()
+	if ok {
+		return available, nil
+	}
+
+	available, err := IsServed(a.discovery, kind)
+	if err != nil {
+		return false, err
+	}
+
+	a.mu.Lock()
+	a.known[kind] = available
+	a.mu.Unlock()
+	return available, nil
+}
+
+// Invalidate drops all cached results.
+func (a *Availability) Invalidate() {
+	a.mu.Lock()
+	a.known = map[schema.GroupVersionKind]bool{}
+	a.mu.Unlock()
+}
+
+// InvalidateOnCRDEvents invalidates the cached results whenever a CustomResourceDefinition is added, changed
+// or deleted. It requires permission to list and watch CustomResourceDefinitions.
+func (a *Availability) InvalidateOnCRDEvents(ctx context.Context, informers cache.Informers) error {
+	// Only the metadata is watched, CRD schemas can be large and are of no interest here.
+	crd := &metav1.PartialObjectMetadata{}
+	crd.SetGroupVersionKind(CustomResourceDefinition)
+	informer, err := informers.GetInformer(ctx, crd, cache.BlockUntilSynced(false))
+	if err != nil {
+		return err
+	}
+	_, err = informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { a.Invalidate() },
+		UpdateFunc: func(interface{}, interface{}) { a.Invalidate() },
+		DeleteFunc: func(interface{}) { a.Invalidate() },
+	})
+	return err
+}
+
+// IsServed uses discovery to check whether the cluster serves kind.
+func IsServed(dc discovery.DiscoveryInterface, kind schema.GroupVersionKind) (bool, error) {
+	resources, err := dc.ServerResourcesForGroupVersion(kind.GroupVersion().String())
+	if k8serr.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Kind == kind.Kind {
+			return true, nil
+		}
+	}
+	return false, nil
+}
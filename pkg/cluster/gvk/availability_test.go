@@ -0,0 +1,39 @@
+package gvk
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestAvailability_IsAvailable(t *testing.T) {
+	fake := &clienttesting.Fake{}
+	a := NewAvailability(&fakediscovery.FakeDiscovery{Fake: fake})
+
+	available, err := a.IsAvailable(ServiceMonitor)
+	if err != nil {
+		t.Fatalf("IsAvailable() error = %v", err)
+	}
+	if available {
+		t.Errorf("IsAvailable(ServiceMonitor) = true before the CRD is installed, want false")
+	}
+
+	// Installing the CRD is not noticed until the cached result is invalidated.
+	fake.Resources = []*metav1.APIResourceList{{
+		GroupVersion: "monitoring.coreos.com/v1",
+		APIResources: []metav1.APIResource{{Name: "servicemonitors", Kind: "ServiceMonitor"}},
+	}}
+	if available, _ := a.IsAvailable(ServiceMonitor); available {
+		t.Errorf("IsAvailable(ServiceMonitor) = true from the cache, want false")
+	}
+
+	a.Invalidate()
+	if available, _ := a.IsAvailable(ServiceMonitor); !available {
+		t.Errorf("IsAvailable(ServiceMonitor) = false after invalidation, want true")
+	}
+	if available, _ := a.IsAvailable(ScaledObject); available {
+		t.Errorf("IsAvailable(ScaledObject) = true, want false")
+	}
+}
@@ -10,4 +10,52 @@ var (
 		Kind:    "MCPServer",
 		Version: "v1",
 	}
+
+	Route = schema.GroupVersionKind{
+		Group:   "route.openshift.io",
+		Kind:    "Route",
+		Version: "v1",
+	}
+
+	ConsolePlugin = schema.GroupVersionKind{
+		Group:   "console.openshift.io",
+		Kind:    "ConsolePlugin",
+		Version: "v1",
+	}
+
+	ClusterVersion = schema.GroupVersionKind{
+		Group:   "config.openshift.io",
+		Kind:    "ClusterVersion",
+		Version: "v1",
+	}
+
+	CustomResourceDefinition = schema.GroupVersionKind{
+		Group:   "apiextensions.k8s.io",
+		Kind:    "CustomResourceDefinition",
+		Version: "v1",
+	}
+
+	ServiceMonitor = schema.GroupVersionKind{
+		Group:   "monitoring.coreos.com",
+		Kind:    "ServiceMonitor",
+		Version: "v1",
+	}
+
+	HTTPRoute = schema.GroupVersionKind{
+		Group:   "gateway.networking.k8s.io",
+		Kind:    "HTTPRoute",
+		Version: "v1",
+	}
+
+	VirtualService = schema.GroupVersionKind{
+		Group:   "networking.istio.io",
+		Kind:    "VirtualService",
+		Version: "v1",
+	}
+
+	ScaledObject = schema.GroupVersionKind{
+		Group:   "keda.sh",
+		Kind:    "ScaledObject",
+		Version: "v1alpha1",
+	}
 )
@@ -0,0 +1,30 @@
+package mcp
+
+import "net/http"
+
+// WithBearerToken returns a copy of httpClient that authenticates every request with token. http.DefaultClient
+// is used as base when httpClient is nil.
+func WithBearerToken(httpClient *http.Client, token string) *http.Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	base := httpClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	authenticated := *httpClient
+	authenticated.Transport = &bearerTokenTransport{base: base, token: token}
+	return &authenticated
+}
+
+type bearerTokenTransport struct {
+	base  http.RoundTripper
+	token string
+}
+
+func (t *bearerTokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,350 @@
+// Package mcp implements the small subset of the Model Context Protocol client needed by the
+// operator to talk to the MCP servers it manages over the SSE transport.
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+const (
+	// ProtocolVersion is the MCP protocol revision announced by the client.
+	ProtocolVersion = "2024-11-05"
+
+	clientName = "mcp-server-operator"
+
+	// MethodNotFound is the JSON-RPC error code for requests with an unknown method.
+	MethodNotFound = -32601
+)
+
+// Implementation identifies an MCP client or server.
+type Implementation struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// InitializeResult is the server's answer to the initialize request.
+type InitializeResult struct {
+	ProtocolVersion string                     `json:"protocolVersion"`
+	Capabilities    map[string]json.RawMessage `json:"capabilities,omitempty"`
+	ServerInfo      Implementation             `json:"serverInfo"`
+	Instructions    string                     `json:"instructions,omitempty"`
+}
+
+// Tool is a tool offered by an MCP server.
+type Tool struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// InputSchema is the JSON Schema of the arguments of the tool.
+	InputSchema json.RawMessage `json:"inputSchema,omitempty"`
+}
+
+type listToolsResult struct {
+	Tools      []Tool `json:"tools"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// Content is an item of the content of a tool result.
+type Content struct {
+	Type string `json:"type"`
+	Text string `json:"text,omitempty"`
+}
+
+// CallToolResult is the result of a tool call. Errors of the tool itself are reported with IsError rather
+// than as JSON-RPC errors.
+type CallToolResult struct {
+	Content []Content `json:"content"`
+	IsError bool      `json:"isError,omitempty"`
+}
+
+// RPCError is a JSON-RPC error returned by the server.
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
+// StatusError is returned when the server answers an HTTP request of the client with an unexpected status, such
+// as 401 Unauthorized when the client does not present the token the server requires.
+type StatusError struct {
+	StatusCode int
+	// Operation is what the request was for, such as opening SSE stream.
+	Operation string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status %d %s", e.StatusCode, e.Operation)
+}
+
+type event struct {
+	name string
+	data string
+}
+
+type rpcRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      *int   `json:"id,omitempty"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	ID     *int            `json:"id"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *RPCError       `json:"error,omitempty"`
+}
+
+// Session is an open SSE connection to an MCP server.
+type Session struct {
+	httpClient *http.Client
+	endpoint   string
+	body       io.ReadCloser
+	events     chan event
+	done       chan struct{}
+	closeOnce  sync.Once
+
+	mu     sync.Mutex
+	nextID int
+	err    error
+}
+
+// Connect opens the SSE stream at sseURL and waits for the server to announce its message endpoint.
+func Connect(ctx context.Context, httpClient *http.Client, sseURL string) (*Session, error) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sseURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, &StatusError{StatusCode: resp.StatusCode, Operation: "opening SSE stream"}
+	}
+
+	s := &Session{
+		httpClient: httpClient,
+		body:       resp.Body,
+		events:     make(chan event, 16),
+		done:       make(chan struct{}),
+	}
+	go s.readEvents()
+
+	ev, err := s.next(ctx, "endpoint")
+	if err != nil {
+		_ = s.Close()
+		return nil, fmt.Errorf("waiting for endpoint event: %w", err)
+	}
+
+	base, err := url.Parse(sseURL)
+	if err != nil {
+		_ = s.Close()
+		return nil, err
+	}
+	endpoint, err := base.Parse(strings.TrimSpace(ev.data))
+	if err != nil {
+		_ = s.Close()
+		return nil, fmt.Errorf("invalid endpoint %q: %w", ev.data, err)
+	}
+	s.endpoint = endpoint.String()
+
+	return s, nil
+}
+
+// Initialize performs the MCP initialization handshake.
+func (s *Session) Initialize(ctx context.Context) (*InitializeResult, error) {
+	params := map[string]any{
+		"protocolVersion": ProtocolVersion,
+		"capabilities":    map[string]any{},
+		"clientInfo":      Implementation{Name: clientName, Version: "v1"},
+	}
+
+	result := &InitializeResult{}
+	if err := s.Call(ctx, "initialize", params, result); err != nil {
+		return nil, err
+	}
+	if err := s.Notify(ctx, "notifications/initialized", nil); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ListTools returns all tools the server offers, following pagination cursors.
+func (s *Session) ListTools(ctx context.Context) ([]Tool, error) {
+	var tools []Tool
+	cursor := ""
+	for {
+		var params any
+		if cursor != "" {
+			params = map[string]any{"cursor": cursor}
+		}
+
+		result := &listToolsResult{}
+		if err := s.Call(ctx, "tools/list", params, result); err != nil {
+			return nil, err
+		}
+		tools = append(tools, result.Tools...)
+		if result.NextCursor == "" || result.NextCursor == cursor {
+			return tools, nil
+		}
+		cursor = result.NextCursor
+	}
+}
+
+// CallTool calls the tool with the given name and arguments.
+func (s *Session) CallTool(ctx context.Context, name string, arguments map[string]any) (*CallToolResult, error) {
+	params := map[string]any{"name": name}
+	if len(arguments) > 0 {
+		params["arguments"] = arguments
+	}
+
+	result := &CallToolResult{}
+	if err := s.Call(ctx, "tools/call", params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Call sends a JSON-RPC request and decodes the matching response into result.
+func (s *Session) Call(ctx context.Context, method string, params any, result any) error {
+	s.mu.Lock()
+	s.nextID++
+	id := s.nextID
+	s.mu.Unlock()
+
+	if err := s.post(ctx, rpcRequest{JSONRPC: "2.0", ID: &id, Method: method, Params: params}); err != nil {
+		return err
+	}
+
+	for {
+		ev, err := s.next(ctx, "message")
+		if err != nil {
+			return fmt.Errorf("waiting for %s response: %w", method, err)
+		}
+
+		resp := rpcResponse{}
+		if err := json.Unmarshal([]byte(ev.data), &resp); err != nil {
+			return fmt.Errorf("decoding %s response: %w", method, err)
+		}
+		if resp.ID == nil || *resp.ID != id {
+			continue
+		}
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if result == nil {
+			return nil
+		}
+		return json.Unmarshal(resp.Result, result)
+	}
+}
+
+// Notify sends a JSON-RPC notification, which the server does not answer.
+func (s *Session) Notify(ctx context.Context, method string, params any) error {
+	return s.post(ctx, rpcRequest{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+// Close terminates the SSE stream.
+func (s *Session) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+	return s.body.Close()
+}
+
+func (s *Session) post(ctx context.Context, msg rpcRequest) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &StatusError{StatusCode: resp.StatusCode, Operation: "posting " + msg.Method}
+	}
+	return nil
+}
+
+// next returns the next event with the given name, skipping any others.
+func (s *Session) next(ctx context.Context, name string) (event, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return event{}, ctx.Err()
+		case ev, ok := <-s.events:
+			if !ok {
+				if s.err != nil {
+					return event{}, s.err
+				}
+				return event{}, errors.New("SSE stream closed")
+			}
+			if ev.name == name {
+				return ev, nil
+			}
+		}
+	}
+}
+
+// readEvents parses the SSE stream into events until the stream ends.
+func (s *Session) readEvents() {
+	defer close(s.events)
+
+	scanner := bufio.NewScanner(s.body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	current := event{name: "message"}
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if len(data) > 0 {
+				current.data = strings.Join(data, "\n")
+				select {
+				case s.events <- current:
+				case <-s.done:
+					return
+				}
+			}
+			current = event{name: "message"}
+			data = nil
+		case strings.HasPrefix(line, "event:"):
+			current.name = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	s.err = scanner.Err()
+}
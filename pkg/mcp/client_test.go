@@ -0,0 +1,204 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newSSEServer starts a minimal SSE MCP server that answers initialize requests.
+func newSSEServer(t *testing.T, initializeError bool) *httptest.Server {
+	t.Helper()
+	messages := make(chan string, 8)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sse", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher := w.(http.Flusher)
+		_, _ = fmt.Fprint(w, "event: endpoint\ndata: /message?sessionId=test\n\n")
+		flusher.Flush()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case msg := <-messages:
+				_, _ = fmt.Fprintf(w, "event: message\ndata: %s\n\n", msg)
+				flusher.Flush()
+			}
+		}
+	})
+	mux.HandleFunc("/message", func(w http.ResponseWriter, r *http.Request) {
+		req := rpcRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		if req.ID == nil {
+			return
+		}
+		if initializeError {
+			messages <- fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"error":{"code":-32603,"message":"boom"}}`, *req.ID)
+			return
+		}
+		if req.Method == "tools/list" {
+			// Serve the tools in two pages.
+			if params, ok := req.Params.(map[string]any); ok && params["cursor"] == "2" {
+				messages <- fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"result":{"tools":[{"name":"c"}]}}`, *req.ID)
+				return
+			}
+			messages <- fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"result":{"tools":[{"name":"a"},{"name":"b"}],`+
+				`"nextCursor":"2"}}`, *req.ID)
+			return
+		}
+		if req.Method == "tools/call" {
+			// The echo tool returns its text argument, any other tool fails.
+			params, _ := req.Params.(map[string]any)
+			arguments, _ := params["arguments"].(map[string]any)
+			if params["name"] != "echo" {
+				messages <- fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"result":{"content":[{"type":"text","text":"unknown tool"}],`+
+					`"isError":true}}`, *req.ID)
+				return
+			}
+			messages <- fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"result":{"content":[{"type":"text","text":"%s"}]}}`,
+				*req.ID, arguments["text"])
+			return
+		}
+		messages <- fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"result":{"protocolVersion":"%s",`+
+			`"capabilities":{"tools":{}},"serverInfo":{"name":"fake","version":"1.0.0"}}}`, *req.ID, ProtocolVersion)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSession_Initialize(t *testing.T) {
+	tests := []struct {
+		name            string
+		initializeError bool
+		wantErr         bool
+	}{
+		{
+			name:    "Verify that the handshake returns the server info",
+			wantErr: false,
+		},
+		{
+			name:            "Verify that a JSON-RPC error is returned to the caller",
+			initializeError: true,
+			wantErr:         true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newSSEServer(t, tt.initializeError)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			session, err := Connect(ctx, server.Client(), server.URL+"/sse")
+			if err != nil {
+				t.Fatalf("Connect() error = %v", err)
+			}
+			defer func() {
+				_ = session.Close()
+			}()
+
+			result, err := session.Initialize(ctx)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Initialize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if result.ServerInfo.Name != "fake" || result.ProtocolVersion != ProtocolVersion {
+				t.Errorf("Initialize() = %+v, want server fake with protocol %s", result, ProtocolVersion)
+			}
+		})
+	}
+}
+
+func TestSession_ListTools(t *testing.T) {
+	server := newSSEServer(t, false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	session, err := Connect(ctx, server.Client(), server.URL+"/sse")
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer func() {
+		_ = session.Close()
+	}()
+
+	tools, err := session.ListTools(ctx)
+	if err != nil {
+		t.Fatalf("ListTools() error = %v", err)
+	}
+	var names []string
+	for _, tool := range tools {
+		names = append(names, tool.Name)
+	}
+	if got := fmt.Sprint(names); got != "[a b c]" {
+		t.Errorf("ListTools() = %s, want the tools of both pages [a b c]", got)
+	}
+}
+
+func TestSession_CallTool(t *testing.T) {
+	tests := []struct {
+		name        string
+		tool        string
+		wantText    string
+		wantIsError bool
+	}{
+		{
+			name:     "Verify that the content of the tool result is returned",
+			tool:     "echo",
+			wantText: "hello",
+		},
+		{
+			name:        "Verify that an error of the tool is reported in the result",
+			tool:        "missing",
+			wantText:    "unknown tool",
+			wantIsError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newSSEServer(t, false)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			session, err := Connect(ctx, server.Client(), server.URL+"/sse")
+			if err != nil {
+				t.Fatalf("Connect() error = %v", err)
+			}
+			defer func() {
+				_ = session.Close()
+			}()
+
+			result, err := session.CallTool(ctx, tt.tool, map[string]any{"text": "hello"})
+			if err != nil {
+				t.Fatalf("CallTool() error = %v", err)
+			}
+			if len(result.Content) != 1 || result.Content[0].Text != tt.wantText || result.IsError != tt.wantIsError {
+				t.Errorf("CallTool() = %+v, want text %s with isError %v", result, tt.wantText, tt.wantIsError)
+			}
+		})
+	}
+}
+
+func TestConnect_NotSSE(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	if _, err := Connect(context.Background(), server.Client(), server.URL+"/sse"); err == nil {
+		t.Errorf("Connect() expected an error for a non-SSE endpoint")
+	}
+}
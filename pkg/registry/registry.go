@@ -0,0 +1,333 @@
+// Package registry implements the small subset of the OCI distribution API needed by the operator to check
+// that the manifest of an image exists before it is rolled out, and to resolve the digest it is pinned to.
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	// dockerHub is the registry of images without a registry host.
+	dockerHub = "docker.io"
+	// dockerHubAPI is the host serving the distribution API of Docker Hub.
+	dockerHubAPI = "registry-1.docker.io"
+)
+
+// manifestMediaTypes are accepted for manifests, so that registries answer for multi-arch images too.
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// ErrUnauthorized is returned when the registry refuses the credentials, or asks for credentials that are not
+// known. Registries such as Docker Hub answer so for repositories that do not exist as well.
+var ErrUnauthorized = errors.New("the registry refused access to the image")
+
+// ErrManifestNotFound is returned when the registry has no manifest for the image.
+var ErrManifestNotFound = errors.New("the registry has no manifest for the image")
+
+// errNoDigest is returned when the registry does not send the digest of a manifest.
+var errNoDigest = errors.New("the registry did not send the digest of the manifest")
+
+// Reference is a parsed image reference.
+type Reference struct {
+	// Registry is the host, with the port, of the registry, e.g. quay.io. It is docker.io for Docker Hub.
+	Registry string
+	// Repository is the path of the repository in the registry, e.g. library/nginx.
+	Repository string
+	// Reference is the tag or the digest of the image.
+	Reference string
+}
+
+// ParseReference parses image the way the container runtime does: an image without a registry host is on
+// Docker Hub, an official Docker Hub image is in the library namespace, and an image without tag or digest
+// has the latest tag.
+func ParseReference(image string) (Reference, error) {
+	name, digest, hasDigest := strings.Cut(image, "@")
+	ref := Reference{Reference: digest}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		if !hasDigest {
+			ref.Reference = name[i+1:]
+		}
+		name = name[:i]
+	}
+	if ref.Reference == "" {
+		if hasDigest {
+			return Reference{}, fmt.Errorf("image %q has an empty digest", image)
+		}
+		ref.Reference = "latest"
+	}
+
+	host, path, found := strings.Cut(name, "/")
+	if found && (strings.ContainsAny(host, ".:") || host == "localhost") {
+		ref.Registry, ref.Repository = host, path
+	} else {
+		ref.Registry, ref.Repository = dockerHub, name
+	}
+	if ref.Registry == dockerHub && !strings.Contains(ref.Repository, "/") {
+		ref.Repository = "library/" + ref.Repository
+	}
+	if ref.Repository == "" || strings.ToLower(ref.Repository) != ref.Repository {
+		return Reference{}, fmt.Errorf("image %q has an invalid repository", image)
+	}
+	return ref, nil
+}
+
+// String returns the reference in its normalized form.
+func (r Reference) String() string {
+	separator := ":"
+	if strings.Contains(r.Reference, ":") {
+		separator = "@"
+	}
+	return r.Registry + "/" + r.Repository + separator + r.Reference
+}
+
+// Credentials authenticate with a registry.
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// dockerConfig is the content of a .dockerconfigjson key, the auths of a .dockercfg key.
+type dockerConfig struct {
+	Auths map[string]dockerConfigEntry `json:"auths"`
+}
+
+type dockerConfigEntry struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Auth     string `json:"auth,omitempty"`
+}
+
+// CredentialsFor returns the credentials for ref in data, the content of the .dockerconfigjson key of a
+// kubernetes.io/dockerconfigjson Secret, or of the .dockercfg key of a kubernetes.io/dockercfg Secret. The
+// entry with the longest matching key wins, as in the kubelet.
+func CredentialsFor(data []byte, ref Reference) (Credentials, bool, error) {
+	config := dockerConfig{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return Credentials{}, false, err
+	}
+	if config.Auths == nil {
+		// The legacy .dockercfg format holds the auths at the top level.
+		if err := json.Unmarshal(data, &config.Auths); err != nil {
+			return Credentials{}, false, err
+		}
+	}
+
+	var best string
+	var found *dockerConfigEntry
+	for key, entry := range config.Auths {
+		if matchesKey(key, ref) && len(key) > len(best) {
+			best, found = key, &entry
+		}
+	}
+	if found == nil {
+		return Credentials{}, false, nil
+	}
+	credentials := Credentials{Username: found.Username, Password: found.Password}
+	if found.Auth != "" {
+		decoded, err := base64.StdEncoding.DecodeString(found.Auth)
+		if err != nil {
+			return Credentials{}, false, fmt.Errorf("invalid auth of %s: %w", best, err)
+		}
+		credentials.Username, credentials.Password, _ = strings.Cut(string(decoded), ":")
+	}
+	return credentials, true, nil
+}
+
+// matchesKey reports whether the key of a docker config entry, a registry host optionally followed by a
+// repository path, applies to ref.
+func matchesKey(key string, ref Reference) bool {
+	if u, err := url.Parse(key); err == nil && u.Host != "" {
+		key = u.Host + u.Path
+	}
+	key = strings.TrimSuffix(key, "/")
+	key = strings.TrimSuffix(strings.TrimSuffix(key, "/v1"), "/v2")
+	host, path, _ := strings.Cut(key, "/")
+	switch host {
+	case "index.docker.io", dockerHubAPI:
+		host = dockerHub
+	}
+	if host != ref.Registry {
+		return false
+	}
+	return path == "" || ref.Repository == path || strings.HasPrefix(ref.Repository, path+"/")
+}
+
+// Client checks images in registries.
+type Client struct {
+	// HTTPClient sends the requests. http.DefaultClient is used when nil.
+	HTTPClient *http.Client
+}
+
+// ManifestExists reports whether the manifest of ref exists in its registry, authenticating with credentials
+// when the registry asks for them. An error is returned when the registry cannot tell, such as when it refuses
+// access or cannot be reached.
+func (c *Client) ManifestExists(ctx context.Context, ref Reference, credentials *Credentials) (bool, error) {
+	_, err := c.ManifestDigest(ctx, ref, credentials)
+	switch {
+	case errors.Is(err, ErrManifestNotFound):
+		return false, nil
+	case errors.Is(err, errNoDigest):
+		// Registries that do not send the digest still answered that the manifest exists.
+		return true, nil
+	case err != nil:
+		return false, err
+	}
+	return true, nil
+}
+
+// ManifestDigest returns the digest of the manifest of ref, e.g. sha256:4a5b..., as sent by its registry in the
+// Docker-Content-Digest header. For multi-arch images it is the digest of the index, which pins the image on
+// all platforms. ErrManifestNotFound is returned when the registry has no manifest for ref.
+func (c *Client) ManifestDigest(ctx context.Context, ref Reference, credentials *Credentials) (string, error) {
+	host := ref.Registry
+	if host == dockerHub {
+		host = dockerHubAPI
+	}
+	manifestURL := fmt.Sprintf("https://%s/v2/%s/manifests/%s", host, ref.Repository, ref.Reference)
+
+	resp, err := c.headManifest(ctx, manifestURL, "")
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		authorization, err := c.authorize(ctx, resp.Header.Get("WWW-Authenticate"), ref, credentials)
+		if err != nil {
+			return "", err
+		}
+		if resp, err = c.headManifest(ctx, manifestURL, authorization); err != nil {
+			return "", err
+		}
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return "", ErrManifestNotFound
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return "", ErrUnauthorized
+	default:
+		return "", fmt.Errorf("registry %s answered %s", ref.Registry, resp.Status)
+	}
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if algorithm, hex, ok := strings.Cut(digest, ":"); !ok || algorithm == "" || hex == "" {
+		return "", fmt.Errorf("%w: registry %s", errNoDigest, ref.Registry)
+	}
+	return digest, nil
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient == nil {
+		return http.DefaultClient
+	}
+	return c.HTTPClient
+}
+
+// headManifest sends a HEAD request for the manifest at manifestURL with the given Authorization header.
+func (c *Client) headManifest(ctx context.Context, manifestURL, authorization string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, manifestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	_ = resp.Body.Close()
+	return resp, nil
+}
+
+// authorize answers the challenge of a registry: Basic challenges with the credentials, Bearer challenges with a
+// token of the realm, which is requested with the credentials if there are any, for anonymous access otherwise.
+func (c *Client) authorize(ctx context.Context, challenge string, ref Reference,
+	credentials *Credentials) (string, error) {
+	scheme, params := parseChallenge(challenge)
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if credentials == nil {
+			return "", ErrUnauthorized
+		}
+		return "Basic " + basicAuth(*credentials), nil
+	case "bearer":
+	default:
+		return "", fmt.Errorf("registry %s asks for unsupported authentication %q", ref.Registry, scheme)
+	}
+
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Scheme == "" {
+		return "", fmt.Errorf("registry %s sent an invalid token realm %q", ref.Registry, params["realm"])
+	}
+	query := realm.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	query.Set("scope", fmt.Sprintf("repository:%s:pull", ref.Repository))
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if credentials != nil {
+		req.Header.Set("Authorization", "Basic "+basicAuth(*credentials))
+	}
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return "", ErrUnauthorized
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token service of registry %s answered %s", ref.Registry, resp.Status)
+	}
+	token := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("invalid token from registry %s: %w", ref.Registry, err)
+	}
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+	return "Bearer " + token.Token, nil
+}
+
+// parseChallenge parses a WWW-Authenticate header with a single challenge into its scheme and parameters.
+func parseChallenge(header string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(header), " ")
+	params := map[string]string{}
+	for rest != "" {
+		var key, value string
+		key, rest, _ = strings.Cut(strings.TrimLeft(rest, " ,"), "=")
+		if strings.HasPrefix(rest, `"`) {
+			value, rest, _ = strings.Cut(rest[1:], `"`)
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		if key = strings.TrimSpace(key); key != "" {
+			params[strings.ToLower(key)] = value
+		}
+	}
+	return scheme, params
+}
+
+func basicAuth(credentials Credentials) string {
+	return base64.StdEncoding.EncodeToString([]byte(credentials.Username + ":" + credentials.Password))
+}
@@ -0,0 +1,236 @@
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		image   string
+		want    Reference
+		wantErr bool
+	}{
+		{image: "nginx", want: Reference{Registry: "docker.io", Repository: "library/nginx", Reference: "latest"}},
+		{image: "team/server:1.2", want: Reference{Registry: "docker.io", Repository: "team/server", Reference: "1.2"}},
+		{
+			image: "quay.io/team/server:1.2",
+			want:  Reference{Registry: "quay.io", Repository: "team/server", Reference: "1.2"},
+		},
+		{
+			image: "localhost:5000/server",
+			want:  Reference{Registry: "localhost:5000", Repository: "server", Reference: "latest"},
+		},
+		{
+			image: "quay.io/team/server:1.2@sha256:0123",
+			want:  Reference{Registry: "quay.io", Repository: "team/server", Reference: "sha256:0123"},
+		},
+		{image: "quay.io/Team/server", wantErr: true},
+		{image: "quay.io/team/server@", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			got, err := ParseReference(tt.image)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseReference() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseReference() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCredentialsFor(t *testing.T) {
+	auth := func(username, password string) string {
+		return base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+	}
+	config := fmt.Sprintf(`{"auths": {
+		"quay.io": {"auth": %q},
+		"quay.io/team": {"username": "team", "password": "team-secret"},
+		"https://index.docker.io/v1/": {"auth": %q}
+	}}`, auth("robot", "robot-secret"), auth("hub", "hub-secret"))
+	legacy := fmt.Sprintf(`{"registry.example.com": {"auth": %q}}`, auth("legacy", "legacy-secret"))
+
+	tests := []struct {
+		name   string
+		data   string
+		image  string
+		want   Credentials
+		wantOK bool
+	}{
+		{
+			name:   "registry entry",
+			data:   config,
+			image:  "quay.io/other/server:1",
+			want:   Credentials{Username: "robot", Password: "robot-secret"},
+			wantOK: true,
+		},
+		{
+			name:   "longest matching repository entry",
+			data:   config,
+			image:  "quay.io/team/server:1",
+			want:   Credentials{Username: "team", Password: "team-secret"},
+			wantOK: true,
+		},
+		{
+			name:   "Docker Hub entry",
+			data:   config,
+			image:  "nginx",
+			want:   Credentials{Username: "hub", Password: "hub-secret"},
+			wantOK: true,
+		},
+		{
+			name:  "no matching entry",
+			data:  config,
+			image: "ghcr.io/team/server:1",
+		},
+		{
+			name:   "legacy dockercfg",
+			data:   legacy,
+			image:  "registry.example.com/server:1",
+			want:   Credentials{Username: "legacy", Password: "legacy-secret"},
+			wantOK: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, err := ParseReference(tt.image)
+			if err != nil {
+				t.Fatalf("ParseReference() error = %v", err)
+			}
+			got, ok, err := CredentialsFor([]byte(tt.data), ref)
+			if err != nil {
+				t.Fatalf("CredentialsFor() error = %v", err)
+			}
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("CredentialsFor() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+// testDigest is the digest of the manifest of team/server:1 served by newRegistry.
+const testDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+// newRegistry returns a registry that serves the manifest of team/server:1 to clients with a token, which its
+// token service hands out to user:secret.
+func newRegistry(t *testing.T) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/token":
+			username, password, ok := r.BasicAuth()
+			if !ok || username != "user" || password != "secret" ||
+				r.URL.Query().Get("scope") != "repository:team/server:pull" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]string{"token": "pull-token"})
+		case strings.HasPrefix(r.URL.Path, "/v2/"):
+			if r.Header.Get("Authorization") != "Bearer pull-token" {
+				w.Header().Set("WWW-Authenticate",
+					fmt.Sprintf(`Bearer realm="%s/token",service="registry.test"`, server.URL))
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if r.Method != http.MethodHead || r.URL.Path != "/v2/team/server/manifests/1" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Docker-Content-Digest", testDigest)
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_ManifestExists(t *testing.T) {
+	server := newRegistry(t)
+	host := strings.TrimPrefix(server.URL, "https://")
+	credentials := &Credentials{Username: "user", Password: "secret"}
+
+	tests := []struct {
+		name        string
+		image       string
+		credentials *Credentials
+		want        bool
+		wantErr     error
+	}{
+		{name: "existing image", image: host + "/team/server:1", credentials: credentials, want: true},
+		{name: "missing tag", image: host + "/team/server:2", credentials: credentials},
+		{name: "wrong credentials", image: host + "/team/server:1", credentials: &Credentials{Username: "user"},
+			wantErr: ErrUnauthorized},
+		{name: "anonymous", image: host + "/team/server:1", wantErr: ErrUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, err := ParseReference(tt.image)
+			if err != nil {
+				t.Fatalf("ParseReference() error = %v", err)
+			}
+			c := &Client{HTTPClient: server.Client()}
+			got, err := c.ManifestExists(context.Background(), ref, tt.credentials)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ManifestExists() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ManifestExists() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_ManifestDigest(t *testing.T) {
+	server := newRegistry(t)
+	host := strings.TrimPrefix(server.URL, "https://")
+	credentials := &Credentials{Username: "user", Password: "secret"}
+
+	tests := []struct {
+		name        string
+		image       string
+		credentials *Credentials
+		want        string
+		wantErr     error
+	}{
+		{name: "existing image", image: host + "/team/server:1", credentials: credentials, want: testDigest},
+		{name: "missing tag", image: host + "/team/server:2", credentials: credentials, wantErr: ErrManifestNotFound},
+		{name: "anonymous", image: host + "/team/server:1", wantErr: ErrUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, err := ParseReference(tt.image)
+			if err != nil {
+				t.Fatalf("ParseReference() error = %v", err)
+			}
+			c := &Client{HTTPClient: server.Client()}
+			got, err := c.ManifestDigest(context.Background(), ref, tt.credentials)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ManifestDigest() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ManifestDigest() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseChallenge(t *testing.T) {
+	scheme, params := parseChallenge(
+		`Bearer realm="https://auth.example.com/token",service="registry.example.com",scope="a,b"`)
+	if scheme != "Bearer" || params["realm"] != "https://auth.example.com/token" ||
+		params["service"] != "registry.example.com" || params["scope"] != "a,b" {
+		t.Errorf("parseChallenge() = %s, %v", scheme, params)
+	}
+}
@@ -0,0 +1,86 @@
+package testing
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+
+	routev1 "github.com/openshift/api/route/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	mcpserverv1 "github.com/opendatahub-io/mcp-server-operator/api/v1"
+)
+
+// moduleRoot returns the root of the operator module, found from the location of this file. It is also found in
+// the module cache of downstream integrators, which holds the CRDs as well.
+func moduleRoot() string {
+	_, file, _, ok := runtime.Caller(0)
+	if !ok {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(file), "..", "..")
+}
+
+// CRDPaths returns the directories holding the CRDs of the operator, and the CRDs of the OpenShift and Gateway
+// APIs it uses, which envtest installs.
+func CRDPaths() []string {
+	root := moduleRoot()
+	return []string{
+		filepath.Join(root, "config", "crd", "bases"),
+		filepath.Join(root, "config", "crd", "external"),
+	}
+}
+
+// NewScheme returns a scheme with the MCPServer API and the APIs of the resources the operator creates.
+func NewScheme() *k8sruntime.Scheme {
+	scheme := k8sruntime.NewScheme()
+	utilruntime.Must(corev1.AddToScheme(scheme))
+	utilruntime.Must(appsv1.AddToScheme(scheme))
+	utilruntime.Must(batchv1.AddToScheme(scheme))
+	utilruntime.Must(networkingv1.AddToScheme(scheme))
+	utilruntime.Must(rbacv1.AddToScheme(scheme))
+	utilruntime.Must(routev1.AddToScheme(scheme))
+	utilruntime.Must(mcpserverv1.AddToScheme(scheme))
+	return scheme
+}
+
+// NewEnvironment returns an envtest environment that installs the CRDs of CRDPaths with scheme, or with
+// NewScheme when nil. The binaries of the API server and etcd are taken from KUBEBUILDER_ASSETS, or from the
+// bin/k8s directory of the operator that make setup-envtest fills, so that tests also run from an IDE.
+func NewEnvironment(scheme *k8sruntime.Scheme) *envtest.Environment {
+	if scheme == nil {
+		scheme = NewScheme()
+	}
+	return &envtest.Environment{
+		CRDInstallOptions: envtest.CRDInstallOptions{
+			Scheme:             scheme,
+			Paths:              CRDPaths(),
+			ErrorIfPathMissing: true,
+			CleanUpAfterUse:    false,
+		},
+		BinaryAssetsDirectory: binaryAssetsDirectory(),
+	}
+}
+
+// binaryAssetsDirectory returns the first directory of the envtest binaries in bin/k8s of the operator, or an
+// empty string when there is none.
+func binaryAssetsDirectory() string {
+	basePath := filepath.Join(moduleRoot(), "bin", "k8s")
+	entries, err := os.ReadDir(basePath)
+	if err != nil {
+		return ""
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			return filepath.Join(basePath, entry.Name())
+		}
+	}
+	return ""
+}
@@ -0,0 +1,408 @@
+// Package testing provides helpers to test integrations with the MCP server operator without a cluster image of
+// an MCP server: an in-process fake MCP server speaking the SSE and streamable HTTP transports, and envtest
+// environments with the CRDs of the operator installed. It is usually imported as mcptesting.
+package testing
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/opendatahub-io/mcp-server-operator/pkg/mcp"
+)
+
+const (
+	// SSEPath is where the fake server serves the event stream of the SSE transport.
+	SSEPath = "/sse"
+	// MessagePath is where clients of the SSE transport post their messages, as announced by the endpoint event.
+	MessagePath = "/message"
+	// StreamableHTTPPath is the endpoint of the streamable HTTP transport.
+	StreamableHTTPPath = "/mcp"
+
+	// SessionIDHeader carries the session of the streamable HTTP transport.
+	SessionIDHeader = "Mcp-Session-Id"
+
+	// invalidParams is the JSON-RPC error code for requests with invalid parameters.
+	invalidParams = -32602
+)
+
+// Transport is the MCP transport a request was received on.
+type Transport string
+
+const (
+	TransportSSE            Transport = "SSE"
+	TransportStreamableHTTP Transport = "StreamableHTTP"
+)
+
+// Tool is a tool offered by the fake server.
+type Tool struct {
+	mcp.Tool
+	// Handler answers the calls of the tool. Tools without a handler return their text argument, like an echo
+	// tool.
+	Handler func(arguments map[string]any) mcp.CallToolResult
+}
+
+// Request is a JSON-RPC request or notification received by the fake server.
+type Request struct {
+	Transport Transport
+	SessionID string
+	Method    string
+	// ID is nil for notifications.
+	ID     *int
+	Params json.RawMessage
+}
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *int            `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      *int          `json:"id"`
+	Result  any           `json:"result,omitempty"`
+	Error   *mcp.RPCError `json:"error,omitempty"`
+}
+
+// Server is a fake MCP server that answers initialize, ping, tools/list and tools/call, and answers other
+// methods with a method not found error. It serves the SSE transport at SSEPath and the streamable HTTP
+// transport at StreamableHTTPPath, and records the requests it receives.
+type Server struct {
+	*httptest.Server
+
+	// ServerInfo is returned by initialize. Defaults to fake 1.0.0.
+	ServerInfo mcp.Implementation
+	// Instructions are returned by initialize.
+	Instructions string
+	// PageSize is the number of tools returned by each tools/list call, all of them when zero.
+	PageSize int
+
+	mu          sync.Mutex
+	tools       []Tool
+	requests    []Request
+	nextSession int
+	// sseSessions holds the messages to send on the event stream of each SSE session.
+	sseSessions map[string]chan []byte
+	// httpSessions holds the streamable HTTP sessions that were initialized and not deleted.
+	httpSessions map[string]bool
+	done         chan struct{}
+	closeOnce    sync.Once
+}
+
+// NewServer starts a fake MCP server offering tools. The caller should call Close when finished.
+func NewServer(tools ...Tool) *Server {
+	s := NewUnstartedServer(tools...)
+	s.Start()
+	return s
+}
+
+// NewUnstartedServer returns a fake MCP server offering tools that is not started yet, so that its fields can
+// be set. The caller should call Start, and Close when finished.
+func NewUnstartedServer(tools ...Tool) *Server {
+	s := &Server{
+		ServerInfo:   mcp.Implementation{Name: "fake", Version: "1.0.0"},
+		tools:        slices.Clone(tools),
+		sseSessions:  map[string]chan []byte{},
+		httpSessions: map[string]bool{},
+		done:         make(chan struct{}),
+	}
+	s.Server = httptest.NewUnstartedServer(s.Handler())
+	return s
+}
+
+// Close ends the open event streams and shuts the server down.
+func (s *Server) Close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+	s.Server.Close()
+}
+
+// SSEURL returns the URL of the SSE transport, as set in spec.url of an External MCPServer.
+func (s *Server) SSEURL() string {
+	return s.URL + SSEPath
+}
+
+// StreamableHTTPURL returns the URL of the streamable HTTP transport.
+func (s *Server) StreamableHTTPURL() string {
+	return s.URL + StreamableHTTPPath
+}
+
+// SetTools replaces the tools of the server, e.g. to test how a reconfigured server is listed again.
+func (s *Server) SetTools(tools ...Tool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tools = slices.Clone(tools)
+}
+
+// Requests returns the requests received so far, in order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.requests)
+}
+
+// Methods returns the methods of the requests received so far, in order.
+func (s *Server) Methods() []string {
+	var methods []string
+	for _, request := range s.Requests() {
+		methods = append(methods, request.Method)
+	}
+	return methods
+}
+
+// Handler returns the handler of the server, to serve it with a server of its own, e.g. over TLS.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+SSEPath, s.serveSSE)
+	mux.HandleFunc("POST "+MessagePath, s.serveMessage)
+	mux.HandleFunc(StreamableHTTPPath, s.serveStreamableHTTP)
+	return mux
+}
+
+// serveSSE opens an SSE session and sends its endpoint, then the answers to the messages posted to it.
+func (s *Server) serveSSE(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	messages := make(chan []byte, 64)
+	s.mu.Lock()
+	s.nextSession++
+	sessionID := strconv.Itoa(s.nextSession)
+	s.sseSessions[sessionID] = messages
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.sseSessions, sessionID)
+		s.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	_, _ = fmt.Fprintf(w, "event: endpoint\ndata: %s?sessionId=%s\n\n", MessagePath, sessionID)
+	flusher.Flush()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.done:
+			return
+		case message := <-messages:
+			_, _ = fmt.Fprintf(w, "event: message\ndata: %s\n\n", message)
+			flusher.Flush()
+		}
+	}
+}
+
+// serveMessage accepts a message of an SSE session and sends the answer on its event stream.
+func (s *Server) serveMessage(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.URL.Query().Get("sessionId")
+	s.mu.Lock()
+	messages, ok := s.sseSessions[sessionID]
+	s.mu.Unlock()
+	if !ok {
+		http.Error(w, "unknown session", http.StatusNotFound)
+		return
+	}
+
+	req := rpcRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON-RPC message", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+	response := s.handle(TransportSSE, sessionID, req)
+	if response == nil {
+		return
+	}
+	data, err := json.Marshal(response)
+	if err != nil {
+		return
+	}
+	select {
+	case messages <- data:
+	case <-s.done:
+	}
+}
+
+// serveStreamableHTTP answers a message of the streamable HTTP transport in the body of the response, as JSON
+// or, for clients that only accept event streams, as a single event. The server opens no streams of its own, so
+// GET is not allowed; DELETE ends a session.
+func (s *Server) serveStreamableHTTP(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.Header.Get(SessionIDHeader)
+	switch r.Method {
+	case http.MethodPost:
+	case http.MethodDelete:
+		s.mu.Lock()
+		known := s.httpSessions[sessionID]
+		delete(s.httpSessions, sessionID)
+		s.mu.Unlock()
+		if !known {
+			http.Error(w, "unknown session", http.StatusNotFound)
+		}
+		return
+	default:
+		w.Header().Set("Allow", "POST, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	req := rpcRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON-RPC message", http.StatusBadRequest)
+		return
+	}
+	if req.Method == "initialize" {
+		s.mu.Lock()
+		s.nextSession++
+		sessionID = "http-" + strconv.Itoa(s.nextSession)
+		s.httpSessions[sessionID] = true
+		s.mu.Unlock()
+		w.Header().Set(SessionIDHeader, sessionID)
+	} else {
+		s.mu.Lock()
+		known := s.httpSessions[sessionID]
+		s.mu.Unlock()
+		switch {
+		case sessionID == "":
+			http.Error(w, "missing "+SessionIDHeader+" header", http.StatusBadRequest)
+			return
+		case !known:
+			http.Error(w, "unknown session", http.StatusNotFound)
+			return
+		}
+	}
+
+	response := s.handle(TransportStreamableHTTP, sessionID, req)
+	if response == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	data, err := json.Marshal(response)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if accept := r.Header.Get("Accept"); strings.Contains(accept, "text/event-stream") &&
+		!strings.Contains(accept, "application/json") {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
+
+// handle records req and returns its response, or nil for notifications.
+func (s *Server) handle(transport Transport, sessionID string, req rpcRequest) *rpcResponse {
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Transport: transport,
+		SessionID: sessionID,
+		Method:    req.Method,
+		ID:        req.ID,
+		Params:    req.Params,
+	})
+	tools := s.tools
+	s.mu.Unlock()
+
+	if req.ID == nil {
+		return nil
+	}
+	response := &rpcResponse{JSONRPC: "2.0", ID: req.ID}
+	switch req.Method {
+	case "initialize":
+		params := struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}{}
+		_ = json.Unmarshal(req.Params, &params)
+		version := params.ProtocolVersion
+		if version == "" {
+			version = mcp.ProtocolVersion
+		}
+		response.Result = mcp.InitializeResult{
+			ProtocolVersion: version,
+			Capabilities:    map[string]json.RawMessage{"tools": json.RawMessage(`{}`)},
+			ServerInfo:      s.ServerInfo,
+			Instructions:    s.Instructions,
+		}
+	case "ping":
+		response.Result = struct{}{}
+	case "tools/list":
+		result, err := s.listTools(tools, req.Params)
+		if err != nil {
+			response.Error = &mcp.RPCError{Code: invalidParams, Message: err.Error()}
+			break
+		}
+		response.Result = result
+	case "tools/call":
+		response.Result = callTool(tools, req.Params)
+	default:
+		response.Error = &mcp.RPCError{Code: mcp.MethodNotFound, Message: "method not found: " + req.Method}
+	}
+	return response
+}
+
+// listTools returns the page of tools at the cursor of params.
+func (s *Server) listTools(tools []Tool, params json.RawMessage) (any, error) {
+	cursor := struct {
+		Cursor string `json:"cursor"`
+	}{}
+	_ = json.Unmarshal(params, &cursor)
+	start := 0
+	if cursor.Cursor != "" {
+		var err error
+		if start, err = strconv.Atoi(cursor.Cursor); err != nil || start < 0 || start > len(tools) {
+			return nil, fmt.Errorf("invalid cursor %q", cursor.Cursor)
+		}
+	}
+	end := len(tools)
+	if s.PageSize > 0 && start+s.PageSize < end {
+		end = start + s.PageSize
+	}
+
+	result := struct {
+		Tools      []mcp.Tool `json:"tools"`
+		NextCursor string     `json:"nextCursor,omitempty"`
+	}{Tools: []mcp.Tool{}}
+	for _, tool := range tools[start:end] {
+		result.Tools = append(result.Tools, tool.Tool)
+	}
+	if end < len(tools) {
+		result.NextCursor = strconv.Itoa(end)
+	}
+	return result, nil
+}
+
+// callTool calls the tool named in params. Unknown tools are reported as a tool error, as the MCP
+// specification asks.
+func callTool(tools []Tool, params json.RawMessage) mcp.CallToolResult {
+	call := struct {
+		Name      string         `json:"name"`
+		Arguments map[string]any `json:"arguments"`
+	}{}
+	_ = json.Unmarshal(params, &call)
+	for _, tool := range tools {
+		if tool.Name != call.Name {
+			continue
+		}
+		if tool.Handler != nil {
+			return tool.Handler(call.Arguments)
+		}
+		text, _ := call.Arguments["text"].(string)
+		return mcp.CallToolResult{Content: []mcp.Content{{Type: "text", Text: text}}}
+	}
+	return mcp.CallToolResult{
+		Content: []mcp.Content{{Type: "text", Text: "unknown tool: " + call.Name}},
+		IsError: true,
+	}
+}
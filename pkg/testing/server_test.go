@@ -0,0 +1,179 @@
+package testing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opendatahub-io/mcp-server-operator/pkg/mcp"
+)
+
+func TestServer_SSE(t *testing.T) {
+	server := NewUnstartedServer(
+		Tool{Tool: mcp.Tool{Name: "echo", Description: "Echoes its text"}},
+		Tool{Tool: mcp.Tool{Name: "fail"}, Handler: func(map[string]any) mcp.CallToolResult {
+			return mcp.CallToolResult{Content: []mcp.Content{{Type: "text", Text: "failed"}}, IsError: true}
+		}},
+		Tool{Tool: mcp.Tool{Name: "search"}},
+	)
+	server.PageSize = 2
+	server.Start()
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	session, err := mcp.Connect(ctx, server.Client(), server.SSEURL())
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer func() {
+		_ = session.Close()
+	}()
+
+	result, err := session.Initialize(ctx)
+	if err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	if result.ServerInfo.Name != "fake" || result.ProtocolVersion != mcp.ProtocolVersion {
+		t.Errorf("Initialize() = %+v, want server fake with protocol %s", result, mcp.ProtocolVersion)
+	}
+
+	tools, err := session.ListTools(ctx)
+	if err != nil {
+		t.Fatalf("ListTools() error = %v", err)
+	}
+	var got []string
+	for _, tool := range tools {
+		got = append(got, tool.Name+": "+tool.Description)
+	}
+	if fmt.Sprint(got) != "[echo: Echoes its text fail:  search: ]" {
+		t.Errorf("ListTools() = %v, want the tools of all pages", tools)
+	}
+
+	for name, want := range map[string]mcp.CallToolResult{
+		"echo":    {Content: []mcp.Content{{Type: "text", Text: "hello"}}},
+		"fail":    {Content: []mcp.Content{{Type: "text", Text: "failed"}}, IsError: true},
+		"unknown": {Content: []mcp.Content{{Type: "text", Text: "unknown tool: unknown"}}, IsError: true},
+	} {
+		got, err := session.CallTool(ctx, name, map[string]any{"text": "hello"})
+		if err != nil {
+			t.Fatalf("CallTool(%s) error = %v", name, err)
+		}
+		if fmt.Sprint(*got) != fmt.Sprint(want) {
+			t.Errorf("CallTool(%s) = %+v, want %+v", name, *got, want)
+		}
+	}
+
+	err = session.Call(ctx, "resources/list", nil, nil)
+	var rpcErr *mcp.RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != mcp.MethodNotFound {
+		t.Errorf("Call(resources/list) error = %v, want method not found", err)
+	}
+
+	requests := server.Requests()
+	if len(requests) == 0 || requests[0].Transport != TransportSSE || requests[0].SessionID == "" {
+		t.Errorf("Requests() = %+v, want the requests of the SSE session", requests)
+	}
+	handshake := "initialize,notifications/initialized,tools/list,tools/list,"
+	if got := strings.Join(server.Methods(), ","); !strings.HasPrefix(got, handshake) {
+		t.Errorf("Methods() = %s, want the handshake followed by two pages of tools", got)
+	}
+}
+
+func TestServer_StreamableHTTP(t *testing.T) {
+	server := NewServer(Tool{Tool: mcp.Tool{Name: "echo"}})
+	t.Cleanup(server.Close)
+
+	post := func(sessionID, accept, body string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, server.StreamableHTTPURL(), strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("failed to create the request: %v", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", accept)
+		if sessionID != "" {
+			req.Header.Set(SessionIDHeader, sessionID)
+		}
+		resp, err := server.Client().Do(req)
+		if err != nil {
+			t.Fatalf("failed to post %s: %v", body, err)
+		}
+		t.Cleanup(func() {
+			_ = resp.Body.Close()
+		})
+		return resp
+	}
+	const both = "application/json, text/event-stream"
+
+	resp := post("", both, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`)
+	sessionID := resp.Header.Get(SessionIDHeader)
+	if resp.StatusCode != http.StatusOK || sessionID == "" {
+		t.Fatalf("initialize status = %d with session %q, want 200 and a session", resp.StatusCode, sessionID)
+	}
+	response := struct {
+		Result mcp.InitializeResult `json:"result"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil || response.Result.ProtocolVersion != "2025-03-26" {
+		t.Errorf("initialize result = %+v (%v), want the requested protocol version", response.Result, err)
+	}
+
+	resp = post(sessionID, both, `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Errorf("notification status = %d, want %d", resp.StatusCode, http.StatusAccepted)
+	}
+
+	resp = post(sessionID, "text/event-stream", `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)
+	body, _ := io.ReadAll(resp.Body)
+	if resp.Header.Get("Content-Type") != "text/event-stream" || !bytes.Contains(body, []byte(`"name":"echo"`)) {
+		t.Errorf("tools/list = %s, want the tools as an event", body)
+	}
+
+	if resp := post("", both, `{"jsonrpc":"2.0","id":3,"method":"ping"}`); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status without a session = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+	if resp := post("unknown", both, `{"jsonrpc":"2.0","id":3,"method":"ping"}`); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("status of an unknown session = %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+
+	req, _ := http.NewRequest(http.MethodDelete, server.StreamableHTTPURL(), nil)
+	req.Header.Set(SessionIDHeader, sessionID)
+	resp, err := server.Client().Do(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("DELETE = %v (%v), want the session ended", resp, err)
+	}
+	_ = resp.Body.Close()
+	if resp := post(sessionID, both, `{"jsonrpc":"2.0","id":4,"method":"ping"}`); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("status of an ended session = %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+
+	for _, request := range server.Requests() {
+		if request.Transport != TransportStreamableHTTP || request.SessionID != sessionID {
+			t.Errorf("request %+v, want it in the streamable HTTP session %s", request, sessionID)
+		}
+	}
+	if got := strings.Join(server.Methods(), ","); got != "initialize,notifications/initialized,tools/list" {
+		t.Errorf("Methods() = %s, want the requests of the session", got)
+	}
+}
+
+func TestNewEnvironment(t *testing.T) {
+	env := NewEnvironment(nil)
+	if env.CRDInstallOptions.Scheme == nil || len(env.CRDInstallOptions.Paths) != 2 {
+		t.Fatalf("NewEnvironment() = %+v, want the scheme and the CRD paths", env.CRDInstallOptions)
+	}
+	for _, path := range CRDPaths() {
+		entries, err := filepath.Glob(filepath.Join(path, "*.yaml"))
+		if err != nil || len(entries) == 0 {
+			t.Errorf("CRD path %s has no CRDs (%v)", path, err)
+		}
+	}
+}